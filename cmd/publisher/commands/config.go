@@ -0,0 +1,7 @@
+package commands
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+type ConfigCommands struct {
+	Show ShowConfigCommand `kong:"cmd" help:"Show the configuration as it will be used for deployment, with defaults applied and Python and R versions inspected."`
+}
@@ -0,0 +1,36 @@
+package commands
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/posit-dev/publisher/internal/cli_types"
+	"github.com/posit-dev/publisher/internal/initialize"
+	"github.com/posit-dev/publisher/internal/util"
+)
+
+type ShowConfigCommand struct {
+	Path       util.Path `help:"Path to project directory containing files to publish." arg:"" default:"."`
+	Python     util.Path `help:"Path to Python interpreter used to determine the Python version, if it isn't configured. Default is the Python 3 on your PATH."`
+	R          util.Path `help:"Path to R interpreter used to determine the R version, if it isn't configured. Default is the R on your PATH."`
+	ConfigName string    `name:"config" short:"c" help:"Configuration name (in .posit/publish/)"`
+}
+
+func (cmd *ShowConfigCommand) Run(args *cli_types.CommonArgs, ctx *cli_types.CLIContext) error {
+	absPath, err := cmd.Path.Abs()
+	if err != nil {
+		return err
+	}
+	cfg, err := initialize.ResolveConfig(absPath, cmd.ConfigName, cmd.Python, cmd.R, ctx.Logger)
+	if err != nil {
+		return err
+	}
+	for _, warning := range cfg.Lint(absPath) {
+		fmt.Fprintln(os.Stderr, "Warning:", warning.Message)
+	}
+	// Comments are from the file, not part of the configuration.
+	cfg.Comments = nil
+	return cfg.Write(os.Stdout)
+}
@@ -5,9 +5,13 @@ package commands
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
+	"github.com/posit-dev/publisher/internal/accounts"
 	"github.com/posit-dev/publisher/internal/cli_types"
+	"github.com/posit-dev/publisher/internal/clients/connect"
 	"github.com/posit-dev/publisher/internal/credentials"
+	"github.com/posit-dev/publisher/internal/events"
 	"github.com/posit-dev/publisher/internal/logging"
 )
 
@@ -16,21 +20,53 @@ type CredentialsCommand struct {
 	Delete DeleteCredentialCommand `kong:"cmd" help:"Delete a credential"`
 	Get    GetCredentialCommand    `kong:"cmd" help:"Get a credential"`
 	List   ListCredentialsCommand  `kong:"cmd" help:"List credentials"`
+	Reset  ResetCredentialsCommand `kong:"cmd" help:"Delete all credentials, such as when they are corrupted"`
 }
 
 type CreateCredentialCommand struct {
 	Name   string `kong:"arg,required,help='Credential nickname'"`
 	URL    string `kong:"arg,required,help='Server URL'"`
 	ApiKey string `kong:"arg,required,help='Server API Key'"`
+	Force  bool   `help:"Save the credential without verifying it with the server."`
+}
+
+// verify checks that the API key can authenticate to the server.
+func (cmd *CreateCredentialCommand) verify(log logging.Logger) error {
+	account := &accounts.Account{
+		ServerType: accounts.ServerTypeConnect,
+		AuthType:   accounts.AuthTypeAPIKey,
+		Name:       cmd.Name,
+		URL:        cmd.URL,
+		ApiKey:     cmd.ApiKey,
+	}
+	client, err := connect.NewConnectClient(account, 30*time.Second, events.NewNullEmitter(), log)
+	if err != nil {
+		return err
+	}
+	_, err = client.TestAuthentication(log)
+	if err != nil {
+		return fmt.Errorf("could not verify the credential (use --force to save it anyway): %w", err)
+	}
+	return nil
 }
 
 func (cmd *CreateCredentialCommand) Run(args *cli_types.CommonArgs, ctx *cli_types.CLIContext) error {
+	if !cmd.Force {
+		err := cmd.verify(ctx.Logger)
+		if err != nil {
+			return err
+		}
+	}
 	cs, err := credentials.NewCredentialsService(logging.NewDiscardLogger())
 	if err != nil {
 		return err
 	}
 
-	cred, err := cs.Set(cmd.Name, cmd.URL, cmd.ApiKey)
+	cred, err := cs.Set(credentials.CreateCredentialDetails{
+		Name:   cmd.Name,
+		URL:    cmd.URL,
+		ApiKey: cmd.ApiKey,
+	})
 	if err != nil {
 		return err
 	}
@@ -109,3 +145,31 @@ func (cmd *ListCredentialsCommand) Run(args *cli_types.CommonArgs, ctx *cli_type
 	fmt.Println(string(body))
 	return nil
 }
+
+type ResetCredentialsCommand struct {
+	Yes bool `short:"y" help:"Delete the credentials without asking for confirmation."`
+}
+
+func (cmd *ResetCredentialsCommand) Run(args *cli_types.CommonArgs, ctx *cli_types.CLIContext) error {
+	if !cmd.Yes {
+		ok, err := confirm("Delete all stored credentials?")
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+	}
+	cs, err := credentials.NewCredentialsService(logging.NewDiscardLogger())
+	if err != nil {
+		return err
+	}
+
+	err = cs.Reset()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("ok")
+	return nil
+}
@@ -0,0 +1,84 @@
+package commands
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/posit-dev/publisher/internal/cli_types"
+	"github.com/posit-dev/publisher/internal/clients/connect"
+	"github.com/posit-dev/publisher/internal/deployment"
+	"github.com/posit-dev/publisher/internal/events"
+	"github.com/posit-dev/publisher/internal/util"
+)
+
+type DeleteCmd struct {
+	TargetName string    `name:"deployment-name" arg:"" help:"Name of deployment to delete (in .posit/deployments/)"`
+	Path       util.Path `help:"Path to project directory." arg:"" default:"."`
+	Yes        bool      `short:"y" help:"Delete without asking for confirmation."`
+}
+
+// confirm asks the user a yes/no question on the terminal.
+func confirm(question string) (bool, error) {
+	fmt.Printf("%s [y/N] ", question)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false, err
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
+
+func (cmd *DeleteCmd) Run(args *cli_types.CommonArgs, ctx *cli_types.CLIContext) error {
+	absPath, err := cmd.Path.Abs()
+	if err != nil {
+		return err
+	}
+	ctx.Logger = events.NewCLILogger(args.Verbose, os.Stderr)
+
+	cmd.TargetName = strings.TrimSuffix(cmd.TargetName, ".toml")
+	err = util.ValidateFilename(cmd.TargetName)
+	if err != nil {
+		return fmt.Errorf("invalid deployment name '%s': %w", cmd.TargetName, err)
+	}
+	path := deployment.GetDeploymentPath(absPath, cmd.TargetName)
+	d, err := deployment.FromFile(path)
+	if err != nil {
+		return err
+	}
+	if !d.IsDeployed() {
+		return fmt.Errorf("deployment '%s' has not been deployed; delete %s to remove it", cmd.TargetName, path)
+	}
+	account, err := ctx.Accounts.GetAccountByServerURL(d.ServerURL)
+	if err != nil {
+		return err
+	}
+
+	if !cmd.Yes {
+		ok, err := confirm(fmt.Sprintf("Delete content %s from server %s, and deployment %s?", d.ID, d.ServerURL, cmd.TargetName))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+	}
+	client, err := connect.NewConnectClient(account, 30*time.Second, events.NewNullEmitter(), ctx.Logger)
+	if err != nil {
+		return err
+	}
+	err = client.DeleteContent(d.ID, ctx.Logger)
+	if err != nil {
+		return fmt.Errorf("could not delete the content from the server; the deployment record was kept: %w", err)
+	}
+	err = path.Remove()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Deleted content %s and deployment %s\n", d.ID, cmd.TargetName)
+	return nil
+}
@@ -3,8 +3,11 @@ package commands
 // Copyright (C) 2023 by Posit Software, PBC.
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
 
 	"github.com/posit-dev/publisher/internal/accounts"
 	"github.com/posit-dev/publisher/internal/cli_types"
@@ -12,6 +15,7 @@ import (
 	"github.com/posit-dev/publisher/internal/deployment"
 	"github.com/posit-dev/publisher/internal/events"
 	"github.com/posit-dev/publisher/internal/initialize"
+	"github.com/posit-dev/publisher/internal/logging"
 	"github.com/posit-dev/publisher/internal/publish"
 	"github.com/posit-dev/publisher/internal/state"
 	"github.com/posit-dev/publisher/internal/util"
@@ -22,6 +26,13 @@ type DeployCmd struct {
 	AccountName string            `name:"account" short:"a" help:"Nickname of the publishing account to use (run list-accounts to see them)."`
 	ConfigName  string            `name:"config" short:"c" help:"Configuration name (in .posit/publish/)"`
 	SaveName    string            `name:"name" short:"n" help:"Save deployment with this name (in .posit/deployments/)"`
+	Bundle      util.Path         `help:"Deploy an existing bundle (.tar.gz) instead of bundling the project directory." xor:"source"`
+	Manifest    util.Path         `help:"Deploy the files listed in an existing manifest.json, using that manifest." xor:"source"`
+	Strict      bool              `help:"Fail instead of deploying if there are any warnings, such as unused requirements or an insecure connection."`
+	PruneEnv    bool              `help:"Remove environment variables from the content on the server if they are not in the configuration."`
+	Note        string            `help:"Note describing this deployment, for your reference."`
+	Labels      map[string]string `name:"label" help:"Label describing this deployment, as key=value. Can be repeated."`
+	Open        bool              `help:"Open the deployed content in the default browser when deployment succeeds."`
 	Account     *accounts.Account `kong:"-"`
 	Config      *config.Config    `kong:"-"`
 	// NOTE: Currently hardcoded to insecure = false. No CLI param added for now.
@@ -55,20 +66,100 @@ func (cmd *DeployCmd) Run(args *cli_types.CommonArgs, ctx *cli_types.CLIContext)
 	}
 	err = initialize.InitIfNeeded(absPath, cmd.ConfigName, ctx.Logger)
 	if err != nil {
-		return err
+		return configNameError(err)
 	}
 	stateStore, err := state.New(absPath, cmd.AccountName, cmd.ConfigName, "", cmd.SaveName, ctx.Accounts, nil, false)
 	if err != nil {
 		return err
 	}
+	stateStore.Strict = cmd.Strict
+	stateStore.PruneEnvVars = cmd.PruneEnv
+	stateStore.Target.Annotate(cmd.Note, cmd.Labels)
 	fmt.Printf("Deploy to server %s using account %s and configuration %s, creating deployment %s\n",
 		stateStore.Account.URL,
 		stateStore.Account.Name,
 		stateStore.ConfigName,
 		stateStore.SaveName)
-	publisher, err := publish.NewFromState(stateStore, events.NewCliEmitter(os.Stderr, ctx.Logger), ctx.Logger)
+	interruptCtx, stop := interruptContext()
+	defer stop()
+	publisher, err := publish.NewFromState(interruptCtx, stateStore, events.NewCliEmitter(os.Stderr, ctx.Logger), ctx.Logger)
+	if err != nil {
+		return err
+	}
+	err = runPublish(publisher, cmd.Bundle, cmd.Manifest)
 	if err != nil {
 		return err
 	}
+	if cmd.Open {
+		openContent(stateStore, ctx.Logger)
+	}
+	return nil
+}
+
+// configNameError adds the option for choosing a
+// configuration to errors that ask the user to choose one.
+func configNameError(err error) error {
+	if errors.Is(err, config.ErrMultipleConfigs) {
+		return fmt.Errorf("%w with the -c option", err)
+	}
+	return err
+}
+
+// interruptContext returns a context that is cancelled when the
+// user interrupts the command, which stops any requests in progress.
+// Steps that don't make requests, like bundling, aren't stopped, so
+// after the first interrupt, another one exits immediately.
+func interruptContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	go func() {
+		select {
+		case <-interrupts:
+			signal.Stop(interrupts)
+			fmt.Fprintln(os.Stderr, "Interrupted; stopping the deployment. Interrupt again to exit immediately.")
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	stop := func() {
+		signal.Stop(interrupts)
+		cancel()
+	}
+	return ctx, stop
+}
+
+// runPublish deploys the bundle or manifest, if one was given,
+// or the project directory.
+func runPublish(publisher publish.Publisher, bundle util.Path, manifest util.Path) error {
+	if bundle.String() != "" {
+		bundlePath, err := bundle.Abs()
+		if err != nil {
+			return err
+		}
+		return publisher.PublishBundle(bundlePath)
+	}
+	if manifest.String() != "" {
+		manifestPath, err := manifest.Abs()
+		if err != nil {
+			return err
+		}
+		return publisher.PublishManifest(manifestPath)
+	}
 	return publisher.PublishDirectory()
 }
+
+// openContent opens the deployed content in the default browser.
+func openContent(stateStore *state.State, log logging.Logger) {
+	url := publish.ContentURL(stateStore)
+	if url == "" {
+		return
+	}
+	opened, err := publish.OpenInBrowser(url)
+	if err != nil {
+		log.Warn("Could not open a browser", "error", err.Error())
+	}
+	if !opened {
+		fmt.Printf("Open %s to see the deployed content\n", url)
+	}
+}
@@ -14,10 +14,12 @@ import (
 )
 
 type InitCommand struct {
-	Path       util.Path `help:"Path to project directory containing files to publish." arg:"" default:"."`
-	Python     util.Path `help:"Path to Python interpreter for this content, if it is Python-based. Default is the Python 3 on your PATH."`
-	R          util.Path `help:"Path to R interpreter for this content, if it is R-based. Default is the R on your PATH."`
-	ConfigName string    `name:"config" short:"c" help:"Configuration name to create (in .posit/publish/)"`
+	Path        util.Path `help:"Path to project directory containing files to publish." arg:"" default:"."`
+	Python      util.Path `help:"Path to Python interpreter for this content, if it is Python-based. Default is the Python 3 on your PATH."`
+	R           util.Path `help:"Path to R interpreter for this content, if it is R-based. Default is the R on your PATH."`
+	ConfigName  string    `name:"config" short:"c" help:"Configuration name to create (in .posit/publish/)"`
+	SearchDepth int       `help:"Levels of subdirectories to search for an entrypoint, up to 2, for projects with the app in a directory like src/."`
+	ScanImports bool      `help:"Detect Python apps that import their framework from a project module, not the entrypoint."`
 }
 
 const contentTypeDetectionFailed = "Could not determine content type and entrypoint.\n\n" +
@@ -52,7 +54,11 @@ func (cmd *InitCommand) Run(args *cli_types.CommonArgs, ctx *cli_types.CLIContex
 	if cmd.ConfigName == "" {
 		cmd.ConfigName = config.DefaultConfigName
 	}
-	cfg, err := initialize.Init(absPath, cmd.ConfigName, cmd.Python, cmd.R, ctx.Logger)
+	opts := initialize.DetectionOptions{
+		SearchDepth: cmd.SearchDepth,
+		ScanImports: cmd.ScanImports,
+	}
+	cfg, err := initialize.Init(absPath, cmd.ConfigName, cmd.Python, cmd.R, opts, ctx.Logger)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,8 @@
+package commands
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+type ManifestCommands struct {
+	Show          ShowManifestCommand  `kong:"cmd" help:"Show the manifest that will be sent to the server when deploying, without creating a bundle."`
+	SupportBundle SupportBundleCommand `kong:"cmd" help:"Create an archive of the manifest and the names and sizes of the files to be deployed, without their contents, to share with support."`
+}
@@ -0,0 +1,39 @@
+package commands
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/posit-dev/publisher/internal/cli_types"
+	"github.com/posit-dev/publisher/internal/config"
+	"github.com/posit-dev/publisher/internal/publish"
+	"github.com/posit-dev/publisher/internal/util"
+)
+
+type ShowManifestCommand struct {
+	Path       util.Path `help:"Path to project directory containing files to publish." arg:"" default:"."`
+	ConfigName string    `name:"config" short:"c" help:"Configuration name (in .posit/publish/)"`
+}
+
+func (cmd *ShowManifestCommand) Run(args *cli_types.CommonArgs, ctx *cli_types.CLIContext) error {
+	absPath, err := cmd.Path.Abs()
+	if err != nil {
+		return err
+	}
+	cfg, err := config.FromFile(config.GetConfigPath(absPath, cmd.ConfigName))
+	if err != nil {
+		return err
+	}
+	manifest, err := publish.CreateManifest(absPath, cfg, "", ctx.Logger)
+	if err != nil {
+		return err
+	}
+	err = manifest.WriteManifest(os.Stdout)
+	if err != nil {
+		return err
+	}
+	fmt.Println()
+	return nil
+}
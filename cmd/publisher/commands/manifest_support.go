@@ -0,0 +1,40 @@
+package commands
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"fmt"
+
+	"github.com/posit-dev/publisher/internal/cli_types"
+	"github.com/posit-dev/publisher/internal/config"
+	"github.com/posit-dev/publisher/internal/publish"
+	"github.com/posit-dev/publisher/internal/util"
+)
+
+type SupportBundleCommand struct {
+	Path       util.Path `help:"Path to project directory containing files to publish." arg:"" default:"."`
+	ConfigName string    `name:"config" short:"c" help:"Configuration name (in .posit/publish/)"`
+	Output     util.Path `short:"o" help:"Path of the archive to create." default:"support-bundle.tar.gz"`
+}
+
+func (cmd *SupportBundleCommand) Run(args *cli_types.CommonArgs, ctx *cli_types.CLIContext) error {
+	absPath, err := cmd.Path.Abs()
+	if err != nil {
+		return err
+	}
+	cfg, err := config.FromFile(config.GetConfigPath(absPath, cmd.ConfigName))
+	if err != nil {
+		return err
+	}
+	f, err := cmd.Output.Create()
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	err = publish.CreateSupportBundle(absPath, cfg, "", f, ctx.Logger)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Created support bundle", cmd.Output.String())
+	return nil
+}
@@ -21,6 +21,13 @@ type RedeployCmd struct {
 	TargetName string                 `name:"deployment-name" arg:"" help:"Name of deployment to update (in .posit/deployments/)"`
 	Path       util.Path              `help:"Path to project directory containing files to publish." arg:"" default:"."`
 	ConfigName string                 `name:"config" short:"c" help:"Configuration name (in .posit/publish/)"`
+	Bundle     util.Path              `help:"Deploy an existing bundle (.tar.gz) instead of bundling the project directory." xor:"source"`
+	Manifest   util.Path              `help:"Deploy the files listed in an existing manifest.json, using that manifest." xor:"source"`
+	Strict     bool                   `help:"Fail instead of deploying if there are any warnings, such as unused requirements or an insecure connection."`
+	PruneEnv   bool                   `help:"Remove environment variables from the content on the server if they are not in the configuration."`
+	Note       string                 `help:"Note describing this deployment, for your reference."`
+	Labels     map[string]string      `name:"label" help:"Label describing this deployment, as key=value. Can be repeated."`
+	Open       bool                   `help:"Open the deployed content in the default browser when deployment succeeds."`
 	Config     *config.Config         `kong:"-"`
 	Target     *deployment.Deployment `kong:"-"`
 	// NOTE: Currently hardcoded to insecure = false. No CLI param added for now.
@@ -33,28 +40,49 @@ func (cmd *RedeployCmd) Run(args *cli_types.CommonArgs, ctx *cli_types.CLIContex
 	}
 	ctx.Logger = events.NewCLILogger(args.Verbose, os.Stderr)
 
-	err = initialize.InitIfNeeded(absPath, cmd.ConfigName, ctx.Logger)
-	if err != nil {
-		return err
-	}
 	cmd.TargetName = strings.TrimSuffix(cmd.TargetName, ".toml")
 	err = util.ValidateFilename(cmd.TargetName)
 	if err != nil {
 		return fmt.Errorf("invalid deployment name '%s': %w", cmd.TargetName, err)
 	}
+	configName := cmd.ConfigName
+	if configName == "" {
+		// Otherwise, the deployment specifies the configuration.
+		target, err := deployment.FromFile(deployment.GetDeploymentPath(absPath, cmd.TargetName))
+		if err != nil {
+			return fmt.Errorf("can't read deployment '%s': %w", cmd.TargetName, err)
+		}
+		configName = target.ConfigName
+	}
+	err = initialize.InitIfNeeded(absPath, configName, ctx.Logger)
+	if err != nil {
+		return configNameError(err)
+	}
 	stateStore, err := state.New(absPath, "", cmd.ConfigName, cmd.TargetName, "", ctx.Accounts, nil, false)
 	if err != nil {
 		return err
 	}
+	stateStore.Strict = cmd.Strict
+	stateStore.PruneEnvVars = cmd.PruneEnv
+	stateStore.Target.Annotate(cmd.Note, cmd.Labels)
 	fmt.Printf("Redeploy %s to server %s using account %s and configuration %s\n",
 		stateStore.TargetName,
 		stateStore.Account.URL,
 		stateStore.Account.Name,
 		stateStore.ConfigName)
 
-	publisher, err := publish.NewFromState(stateStore, events.NewCliEmitter(os.Stderr, ctx.Logger), ctx.Logger)
+	interruptCtx, stop := interruptContext()
+	defer stop()
+	publisher, err := publish.NewFromState(interruptCtx, stateStore, events.NewCliEmitter(os.Stderr, ctx.Logger), ctx.Logger)
 	if err != nil {
 		return err
 	}
-	return publisher.PublishDirectory()
+	err = runPublish(publisher, cmd.Bundle, cmd.Manifest)
+	if err != nil {
+		return err
+	}
+	if cmd.Open {
+		openContent(stateStore, ctx.Logger)
+	}
+	return nil
 }
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/posit-dev/publisher/internal/cli_types"
 	"github.com/posit-dev/publisher/internal/inspect"
@@ -17,6 +18,7 @@ type CreateRequirementsCommand struct {
 	Python util.Path `help:"Path to Python interpreter for this content, if it is Python-based. Default is the Python 3 on your PATH."`
 	Output string    `short:"o" help:"Name of output file." default:"requirements.txt"`
 	Force  bool      `short:"f" help:"Overwrite the output file, if it exists."`
+	Unused bool      `help:"Also show the packages listed in the previous output file that are not imported by the project."`
 }
 
 var errRequirementsFileExists = errors.New("the requirements file already exists; use the -f option to overwrite it")
@@ -35,20 +37,30 @@ func (cmd *CreateRequirementsCommand) Run(args *cli_types.CommonArgs, ctx *cli_t
 		return errRequirementsFileExists
 	}
 	inspector := inspect.NewPythonInspector(absPath, cmd.Python, ctx.Logger)
-	reqs, incomplete, pythonExecutable, err := inspector.ScanRequirements(absPath)
+	packageFile := ""
+	if cmd.Unused {
+		packageFile = cmd.Output
+	}
+	scan, err := inspector.ScanRequirements(absPath, packageFile, time.Time{})
 	if err != nil {
 		return err
 	}
-	err = inspector.WriteRequirementsFile(reqPath, reqs)
+	err = inspector.WriteRequirementsFile(reqPath, scan.Requirements)
 	if err != nil {
 		return err
 	}
 	fmt.Fprintf(os.Stderr, "Wrote file %s:\n", cmd.Output)
-	fmt.Println("Using package information from", pythonExecutable)
-	if len(incomplete) > 0 {
+	fmt.Println("Using package information from", scan.Python)
+	if len(scan.Incomplete) > 0 {
 		fmt.Println("Warning: could not find some package versions in your local Python library.")
 		fmt.Println("Consider installing these packages and re-running.")
-		for _, pkg := range incomplete {
+		for _, pkg := range scan.Incomplete {
+			fmt.Println(pkg)
+		}
+	}
+	if len(scan.Unused) > 0 {
+		fmt.Println("Note: these packages were listed in the previous requirements.txt, but are not imported by the project:")
+		for _, pkg := range scan.Unused {
 			fmt.Println(pkg)
 		}
 	}
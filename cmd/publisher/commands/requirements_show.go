@@ -5,6 +5,7 @@ package commands
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/posit-dev/publisher/internal/cli_types"
 	"github.com/posit-dev/publisher/internal/inspect"
@@ -12,8 +13,10 @@ import (
 )
 
 type ShowRequirementsCommand struct {
-	Path   util.Path `help:"Path to project directory containing files to publish." arg:"" default:"."`
-	Python util.Path `help:"Path to Python interpreter for this content, if it is Python-based. Default is the Python 3 on your PATH."`
+	Path    util.Path     `help:"Path to project directory containing files to publish." arg:"" default:"."`
+	Python  util.Path     `help:"Path to Python interpreter for this content, if it is Python-based. Default is the Python 3 on your PATH."`
+	Timeout time.Duration `help:"Stop scanning after this long (e.g. 30s) and show the packages found so far. Default is no limit."`
+	Unused  bool          `help:"Also show the packages listed in requirements.txt that are not imported by the project."`
 }
 
 func (cmd *ShowRequirementsCommand) Run(args *cli_types.CommonArgs, ctx *cli_types.CLIContext) error {
@@ -22,19 +25,37 @@ func (cmd *ShowRequirementsCommand) Run(args *cli_types.CommonArgs, ctx *cli_typ
 		return err
 	}
 	inspector := inspect.NewPythonInspector(absPath, cmd.Python, ctx.Logger)
-	reqs, incomplete, pythonExecutable, err := inspector.ScanRequirements(absPath)
+	var deadline time.Time
+	if cmd.Timeout > 0 {
+		deadline = time.Now().Add(cmd.Timeout)
+	}
+	packageFile := ""
+	if cmd.Unused {
+		packageFile = inspect.PythonRequirementsFilename
+	}
+	scan, err := inspector.ScanRequirements(absPath, packageFile, deadline)
 	if err != nil {
 		return err
 	}
-	if len(incomplete) > 0 {
+	if scan.Partial {
+		fmt.Println("# Warning: the scan timed out; these results are partial.")
+	}
+	if len(scan.Incomplete) > 0 {
 		fmt.Println("# Warning: could not find some package versions in your local Python library.")
 		fmt.Println("# Consider installing these packages and re-running.")
-		for _, pkg := range incomplete {
+		for _, pkg := range scan.Incomplete {
+			fmt.Println("#", pkg)
+		}
+	}
+	if len(scan.Unused) > 0 {
+		fmt.Println("# Note: these packages are listed in requirements.txt, but are not imported by the project.")
+		fmt.Println("# They may be removed unless they are needed indirectly.")
+		for _, pkg := range scan.Unused {
 			fmt.Println("#", pkg)
 		}
 	}
 	fmt.Println("# Project dependencies for", absPath)
-	fmt.Println("# Using package information from", pythonExecutable)
-	fmt.Println(strings.Join(reqs, "\n"))
+	fmt.Println("# Using package information from", scan.Python)
+	fmt.Println(strings.Join(scan.Requirements, "\n"))
 	return nil
 }
@@ -11,13 +11,16 @@ import (
 )
 
 type UICmd struct {
-	Path          util.Path `help:"Path to project directory containing files to publish." arg:"" default:"."`
-	Interactive   bool      `short:"i" help:"Launch a browser to show the UI."`
-	OpenBrowserAt string    `help:"Network address to use when launching the browser." placeholder:"HOST[:PORT]" hidden:""`
-	Theme         string    `help:"UI theme, 'light' or 'dark'." hidden:""`
-	Listen        string    `help:"Network address to listen on." placeholder:"HOST[:PORT]" default:"localhost:0"`
-	TLSKeyFile    string    `help:"Path to TLS private key file for the UI server."`
-	TLSCertFile   string    `help:"Path to TLS certificate chain file for the UI server."`
+	Path                    util.Path `help:"Path to project directory containing files to publish." arg:"" default:"."`
+	Interactive             bool      `short:"i" help:"Launch a browser to show the UI."`
+	OpenBrowserAt           string    `help:"Network address to use when launching the browser." placeholder:"HOST[:PORT]" hidden:""`
+	Theme                   string    `help:"UI theme, 'light' or 'dark'." hidden:""`
+	Listen                  string    `help:"Network address to listen on." placeholder:"HOST[:PORT]" default:"localhost:0"`
+	TLSKeyFile              string    `help:"Path to TLS private key file for the UI server."`
+	TLSCertFile             string    `help:"Path to TLS certificate chain file for the UI server."`
+	NoCompression           bool      `help:"Don't compress large API responses."`
+	MaxBodySize             int64     `help:"Maximum size of API request bodies, in bytes." default:"10485760"`
+	MaxConcurrentOperations int       `help:"Maximum number of file listings and dependency scans, and separately of deployments, to run at once. Other requests wait, then fail with 429. Use 0 for no limit." default:"${max_concurrent_operations}"`
 }
 
 func (cmd *UICmd) Run(args *cli_types.CommonArgs, ctx *cli_types.CLIContext) error {
@@ -45,6 +48,9 @@ func (cmd *UICmd) Run(args *cli_types.CommonArgs, ctx *cli_types.CLIContext) err
 		cmd.Theme,
 		cmd.Listen,
 		true,
+		!cmd.NoCompression,
+		cmd.MaxBodySize,
+		cmd.MaxConcurrentOperations,
 		cmd.TLSKeyFile,
 		cmd.TLSCertFile,
 		absPath,
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"runtime/pprof"
+	"strconv"
 	"strings"
 
 	"github.com/alecthomas/kong"
@@ -15,15 +16,19 @@ import (
 	"github.com/posit-dev/publisher/internal/events"
 	"github.com/posit-dev/publisher/internal/logging"
 	"github.com/posit-dev/publisher/internal/project"
+	"github.com/posit-dev/publisher/internal/services/middleware"
 	"github.com/spf13/afero"
 )
 
 type cliSpec struct {
 	cli_types.CommonArgs
 
+	Config       commands.ConfigCommands       `kong:"cmd" help:"Inspect configurations."`
 	Credentials  commands.CredentialsCommand   `kong:"cmd" help:"Manage credentials."`
+	Delete       commands.DeleteCmd            `kong:"cmd" help:"Delete a deployment's content from the server, and the deployment."`
 	Deploy       commands.DeployCmd            `kong:"cmd" help:"Create a new deployment."`
 	Init         commands.InitCommand          `kong:"cmd" help:"Create a configuration file based on the contents of the project directory."`
+	Manifest     commands.ManifestCommands     `kong:"cmd" help:"Inspect the manifest sent to the server."`
 	Redeploy     commands.RedeployCmd          `kong:"cmd" help:"Update an existing deployment."`
 	Requirements commands.RequirementsCommands `kong:"cmd" help:"Create a Python requirements.txt file."`
 	UI           commands.UICmd                `kong:"cmd" help:"Serve the publisher UI."`
@@ -51,7 +56,9 @@ func main() {
 		CommonArgs: cli_types.CommonArgs{},
 	}
 	// Dispatch to the Run() method of the selected command.
-	args := kong.Parse(&cli, kong.Bind(ctx))
+	args := kong.Parse(&cli, kong.Bind(ctx), kong.Vars{
+		"max_concurrent_operations": strconv.Itoa(middleware.DefaultMaxConcurrentOperations),
+	})
 	if cli.Profile != "" {
 		f, err := os.Create(cli.Profile)
 		if err != nil {
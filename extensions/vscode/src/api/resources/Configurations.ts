@@ -73,6 +73,27 @@ export class Configurations {
     });
   }
 
+  // Returns the named configuration, or the only one if no name is given,
+  // creating it from the detected content if it doesn't exist.
+  // Returns:
+  // 200 - success, existing configuration
+  // 201 - success, created configuration
+  // 400 - bad request
+  // 422 - no deployable content was detected
+  // 500 - internal server error
+  ensure(dir: string, configName?: string, python?: string) {
+    return this.client.post<Configuration | ConfigurationError>(
+      "/configurations/ensure",
+      {
+        configurationName: configName,
+        python,
+      },
+      {
+        params: { dir },
+      },
+    );
+  }
+
   // Inspect the project, returning all possible (detected) configurations
   // Returns:
   // 200 - success
@@ -81,7 +102,12 @@ export class Configurations {
   inspect(
     dir: string,
     python?: string,
-    params?: { entrypoint?: string; recursive?: boolean },
+    params?: {
+      entrypoint?: string;
+      recursive?: boolean;
+      searchDepth?: number;
+      scanImports?: boolean;
+    },
   ) {
     return this.client.post<ConfigurationInspectionResult[]>(
       "/inspect",
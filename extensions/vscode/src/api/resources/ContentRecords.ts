@@ -8,6 +8,7 @@ import {
   ContentRecord,
   Environment,
 } from "../types/contentRecords";
+import { Configuration } from "../types/configurations";
 
 export class ContentRecords {
   private client: AxiosInstance;
@@ -75,12 +76,16 @@ export class ContentRecords {
     insecure: boolean,
     dir: string,
     secrets?: Record<string, string>,
+    bundle?: string,
+    manifest?: string,
   ) {
     const data = {
       account: accountName,
       config: configName,
       secrets: secrets,
       insecure: insecure,
+      bundle: bundle,
+      manifest: manifest,
     };
     const encodedTarget = encodeURIComponent(targetName);
     return this.client.post<{ localId: string }>(
@@ -105,6 +110,20 @@ export class ContentRecords {
     });
   }
 
+  // Deletes the content from the server, then the content record.
+  // Returns:
+  // 204 - no content
+  // 400 - not deployed, or no credential for the server
+  // 404 - not found
+  // 500 - internal server error
+  // Errors from the server are passed through, and the record is kept.
+  deleteContent(saveName: string, dir: string) {
+    const encodedSaveName = encodeURIComponent(saveName);
+    return this.client.delete(`deployments/${encodedSaveName}/content`, {
+      params: { dir },
+    });
+  }
+
   // Returns:
   // 204 - no content
   // 404 - contentRecord or config file not found
@@ -143,4 +162,24 @@ export class ContentRecords {
       },
     );
   }
+
+  // Adds the names of the environment variables set on the server
+  // to the deployment's configuration as secrets.
+  // Returns:
+  // 200 - success
+  // 400 - bad request
+  // 404 - deployment or configuration not found
+  // 500 - internal server error
+  importEnv(deploymentName: string, dir: string) {
+    const encodedName = encodeURIComponent(deploymentName);
+    return this.client.post<Configuration>(
+      `deployments/${encodedName}/environment/import`,
+      undefined,
+      {
+        params: {
+          dir,
+        },
+      },
+    );
+  }
 }
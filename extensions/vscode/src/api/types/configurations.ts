@@ -15,8 +15,14 @@ export type ConfigurationError = {
   error: AgentError;
 } & ConfigurationLocation;
 
+export type ConfigurationLintWarning = {
+  code: string;
+  message: string;
+};
+
 export type Configuration = {
   configuration: ConfigurationDetails;
+  warnings?: ConfigurationLintWarning[];
 } & ConfigurationLocation;
 
 export type ConfigurationInspectionResult = {
@@ -51,6 +57,7 @@ export enum ContentType {
   PYTHON_DASH = "python-dash",
   PYTHON_FASTAPI = "python-fastapi",
   PYTHON_FLASK = "python-flask",
+  PYTHON_PANEL = "python-panel",
   PYTHON_SHINY = "python-shiny",
   PYTHON_STREAMLIT = "python-streamlit",
   QUARTO_SHINY = "quarto-shiny",
@@ -71,6 +78,7 @@ export const allValidContentTypes: ContentType[] = [
   ContentType.PYTHON_DASH,
   ContentType.PYTHON_FASTAPI,
   ContentType.PYTHON_FLASK,
+  ContentType.PYTHON_PANEL,
   ContentType.PYTHON_SHINY,
   ContentType.PYTHON_STREAMLIT,
   ContentType.QUARTO_SHINY,
@@ -90,6 +98,7 @@ export const contentTypeStrings = {
   [ContentType.PYTHON_DASH]: "run with Dash",
   [ContentType.PYTHON_FASTAPI]: "run with FastAPI",
   [ContentType.PYTHON_FLASK]: "run with Flask",
+  [ContentType.PYTHON_PANEL]: "run with Panel",
   [ContentType.PYTHON_SHINY]: "run with Python Shiny",
   [ContentType.PYTHON_STREAMLIT]: "run with Streamlit",
   [ContentType.QUARTO_SHINY]: "render with Quarto and run embedded Shiny app",
@@ -115,8 +124,10 @@ export type ConfigurationDetails = {
   python?: PythonConfig;
   r?: RConfig;
   quarto?: QuartoConfig;
+  prePublish?: PrePublishConfig;
   environment?: EnvironmentConfig;
   validate: boolean;
+  draft?: boolean;
   files?: string[];
   secrets?: string[];
   schedules?: ScheduleConfig[];
@@ -126,6 +137,7 @@ export type ConfigurationDetails = {
 
 export type PythonConfig = {
   version: string;
+  executable?: string;
   packageFile: string;
   packageManager: string;
 };
@@ -139,6 +151,12 @@ export type RConfig = {
 export type QuartoConfig = {
   version: string;
   engines?: string[];
+  hasOjs?: boolean;
+};
+
+export type PrePublishConfig = {
+  commands: string[];
+  timeout?: number;
 };
 
 export type EnvironmentConfig = Record<string, string>;
@@ -150,7 +168,7 @@ export type ScheduleConfig = {
 
 export enum AccessType {
   ANONYMOUS = "all",
-  LOGGED_IN = "logged-in",
+  LOGGED_IN = "logged_in",
   ACL = "acl",
 }
 
@@ -1,6 +1,9 @@
 // Copyright (C) 2023 by Posit Software, PBC.
 
+import { AccessType } from "./configurations";
+
 export type ConnectConfig = {
+  vanityUrl?: string;
   access?: ConnectAccess;
   runtime?: ConnectRuntime;
   kubernetes?: ConnectKubernetes;
@@ -9,6 +12,7 @@ export type ConnectConfig = {
 export type ConnectAccess = {
   runAs?: string;
   runAsCurrentUser?: boolean;
+  initialAccessType?: AccessType;
 };
 
 export type ConnectRuntime = {
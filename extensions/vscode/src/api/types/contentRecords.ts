@@ -47,6 +47,8 @@ type ContentRecordRecord = {
   configurationName: string;
   type: ContentType;
   deploymentError: AgentError | null;
+  note?: string;
+  labels?: Record<string, string>;
 } & ContentRecordLocation;
 
 export type PreContentRecord = {
@@ -61,6 +63,7 @@ export type ContentRecord = {
   id: string;
   bundleId: string;
   bundleUrl: string;
+  contentHash?: string;
   dashboardUrl: string;
   directUrl: string;
   logsUrl: string;
@@ -7,6 +7,8 @@ export type Credential = {
   name: string;
   url: string;
   apiKey: string;
+  token?: string;
+  secret?: string;
 };
 
 export type CredentialUser = {
@@ -74,6 +74,11 @@ export interface EventSubscriptionTargetCallbackMap {
   "publish/setEnvVars/success": OnPublishSetEnvVarsSuccessCallback;
   "publish/setEnvVars/failure": OnPublishSetEnvVarsFailureCallback;
 
+  "publish/runPrePublish/start": OnPublishRunPrePublishStartCallback;
+  "publish/runPrePublish/log": OnPublishRunPrePublishLogCallback;
+  "publish/runPrePublish/success": OnPublishRunPrePublishSuccessCallback;
+  "publish/runPrePublish/failure": OnPublishRunPrePublishFailureCallback;
+
   "publish/createBundle/start": OnPublishCreateBundleStartCallback;
   "publish/createBundle/log": OnPublishCreateBundleLogCallback;
   "publish/createBundle/success": OnPublishCreateBundleSuccessCallback;
@@ -184,6 +189,13 @@ const eventVerbToString = new Map<string, activeInactivePhrases>([
       inActive: "Check Capabilities",
     },
   ],
+  [
+    "publish/runPrePublish",
+    {
+      inActive: "Run Pre-Publish Commands",
+      active: "Running Pre-Publish Commands",
+    },
+  ],
   [
     "publish/createBundle",
     {
@@ -543,6 +555,66 @@ export function isPublishSetEnvVarsFailure(
   return arg.type === "publish/setEnvVars/failure";
 }
 
+export interface PublishRunPrePublishStart extends EventStreamMessage {
+  type: "publish/runPrePublish/start";
+  data: {
+    localId: string;
+    commands: string[];
+  };
+}
+export type OnPublishRunPrePublishStartCallback = (
+  msg: PublishRunPrePublishStart,
+) => void;
+export function isPublishRunPrePublishStart(
+  arg: Events,
+): arg is PublishRunPrePublishStart {
+  return arg.type === "publish/runPrePublish/start";
+}
+
+export interface PublishRunPrePublishLog extends EventStreamMessage {
+  type: "publish/runPrePublish/log";
+  // structured data not guaranteed, use selective or generic queries
+  // from data map
+}
+export type OnPublishRunPrePublishLogCallback = (
+  msg: PublishRunPrePublishLog,
+) => void;
+export function isPublishRunPrePublishLog(
+  arg: Events,
+): arg is PublishRunPrePublishLog {
+  return arg.type === "publish/runPrePublish/log";
+}
+
+export interface PublishRunPrePublishSuccess extends EventStreamMessage {
+  type: "publish/runPrePublish/success";
+  data: {
+    localId: string;
+  };
+}
+export type OnPublishRunPrePublishSuccessCallback = (
+  msg: PublishRunPrePublishSuccess,
+) => void;
+export function isPublishRunPrePublishSuccess(
+  arg: Events,
+): arg is PublishRunPrePublishSuccess {
+  return arg.type === "publish/runPrePublish/success";
+}
+
+export interface PublishRunPrePublishFailure extends EventStreamMessage {
+  type: "publish/runPrePublish/failure";
+  error: string; // translated internally
+  // structured data not guaranteed, use selective or generic queries
+  // from data map
+}
+export type OnPublishRunPrePublishFailureCallback = (
+  msg: PublishRunPrePublishFailure,
+) => void;
+export function isPublishRunPrePublishFailure(
+  arg: Events,
+): arg is PublishRunPrePublishFailure {
+  return arg.type === "publish/runPrePublish/failure";
+}
+
 export interface PublishCreateBundleStart extends EventStreamMessage {
   type: "publish/createBundle/start";
   data: {
@@ -1242,8 +1314,16 @@ export interface PublishSuccess extends EventStreamMessage {
     dashboardUrl: string;
     directUrl: string;
     serverUrl: string;
+    timings: PublishPhaseTiming[];
   };
 }
+
+export interface PublishPhaseTiming {
+  op: string;
+  // Milliseconds since publishing started
+  startMs: number;
+  durationMs: number;
+}
 export type OnPublishSuccessCallback = (msg: PublishSuccess) => void;
 export function isPublishSuccess(arg: Events): arg is PublishSuccess {
   return arg.type === "publish/success";
@@ -1286,6 +1366,10 @@ export type Events =
   | ErrorsUnknownEvent
   | OpenSse
   | PublishStart
+  | PublishRunPrePublishStart
+  | PublishRunPrePublishLog
+  | PublishRunPrePublishSuccess
+  | PublishRunPrePublishFailure
   | PublishCreateBundleStart
   | PublishCreateBundleLog
   | PublishCreateBundleSuccess
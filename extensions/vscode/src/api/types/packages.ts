@@ -7,7 +7,9 @@ export type PythonPackagesResponse = {
 export type ScanPythonPackagesResponse = {
   requirements: string[];
   incomplete: string[];
+  unused: string[];
   python: string;
+  partial: boolean;
 };
 
 export type RPackage = {
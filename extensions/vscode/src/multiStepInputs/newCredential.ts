@@ -138,7 +138,7 @@ export async function newCredential(
           if (err) {
             if (err.code === "errorCertificateVerification") {
               return Promise.resolve({
-                message: `Error: URL Not Accessible - ${err.msg}. If applicable, consider disabling [Verify TLS Certificates](${openConfigurationCommand}).`,
+                message: `Error: URL Not Accessible - ${err.msg} If applicable, consider disabling [Verify TLS Certificates](${openConfigurationCommand}).`,
                 severity: InputBoxValidationSeverity.Error,
               });
             }
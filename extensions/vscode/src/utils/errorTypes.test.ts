@@ -23,6 +23,8 @@ import {
   isErrTOMLValidationError,
   errTOMLValidationErrorMessage,
   isErrPythonExecNotFoundError,
+  isErrNoDeployableContentError,
+  isErrQuartoNotFoundError,
 } from "./errorTypes";
 
 // eslint-disable-next-line @typescript-eslint/no-explicit-any
@@ -315,6 +317,46 @@ describe("ErrPythonExecNotFoundError", () => {
   });
 });
 
+describe("ErrNoDeployableContentError", () => {
+  test("isErrNoDeployableContentError", () => {
+    let result = isErrNoDeployableContentError(
+      mkAxiosJsonErr({
+        code: "noDeployableContent",
+      }),
+    );
+
+    expect(result).toBe(true);
+
+    result = isErrNoDeployableContentError(
+      mkAxiosJsonErr({
+        code: "bricks_raining",
+      }),
+    );
+
+    expect(result).toBe(false);
+  });
+});
+
+describe("ErrQuartoNotFoundError", () => {
+  test("isErrQuartoNotFoundError", () => {
+    let result = isErrQuartoNotFoundError(
+      mkAxiosJsonErr({
+        code: "quartoNotFound",
+      }),
+    );
+
+    expect(result).toBe(true);
+
+    result = isErrQuartoNotFoundError(
+      mkAxiosJsonErr({
+        code: "bricks_raining",
+      }),
+    );
+
+    expect(result).toBe(false);
+  });
+});
+
 describe("resolveAgentJsonErrorMsg", () => {
   test("returns proper message based on the provided error", () => {
     let msg = resolveAgentJsonErrorMsg(
@@ -367,5 +409,13 @@ describe("resolveAgentJsonErrorMsg", () => {
     );
 
     expect(msg).toBe("Could not find a Python executable.");
+
+    msg = resolveAgentJsonErrorMsg(
+      mkAxiosJsonErr({
+        code: "noDeployableContent",
+      }) as axiosErrorWithJson,
+    );
+
+    expect(msg).toBe("No deployable content was detected in this directory.");
   });
 });
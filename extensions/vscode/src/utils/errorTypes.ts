@@ -17,7 +17,10 @@ export type ErrorCode =
   | "deployedContentNotRunning"
   | "tomlValidationError"
   | "tomlUnknownError"
-  | "pythonExecNotFound";
+  | "pythonExecNotFound"
+  | "invalidConfig"
+  | "noDeployableContent"
+  | "quartoNotFound";
 
 export type axiosErrorWithJson<T = { code: ErrorCode; details: unknown }> =
   AxiosError & {
@@ -154,6 +157,45 @@ export const errPythonExecNotFoundErrorMessage = (
   return "Could not find a Python executable.";
 };
 
+// No deployable content was detected when creating a configuration
+export type ErrNoDeployableContentError =
+  MkErrorDataType<"noDeployableContent">;
+export const isErrNoDeployableContentError =
+  mkErrorTypeGuard<ErrNoDeployableContentError>("noDeployableContent");
+export const errNoDeployableContentErrorMessage = (
+  _: axiosErrorWithJson<ErrNoDeployableContentError>,
+) => {
+  return "No deployable content was detected in this directory.";
+};
+
+// Quarto is needed to inspect the project, but isn't installed
+export type ErrQuartoNotFoundError = MkErrorDataType<"quartoNotFound">;
+export const isErrQuartoNotFoundError =
+  mkErrorTypeGuard<ErrQuartoNotFoundError>("quartoNotFound");
+export const errQuartoNotFoundErrorMessage = (
+  _: axiosErrorWithJson<ErrQuartoNotFoundError>,
+) => {
+  return "Quarto is required to deploy this project, but it could not be found. Install Quarto from https://quarto.org/docs/get-started/ and make sure it is on the PATH.";
+};
+
+// Configuration failed schema validation when saving
+export type ErrInvalidConfig = MkErrorDataType<
+  "invalidConfig",
+  {
+    errors: { field: string; message: string }[];
+  }
+>;
+export const isErrInvalidConfig =
+  mkErrorTypeGuard<ErrInvalidConfig>("invalidConfig");
+export const errInvalidConfigMessage = (
+  err: axiosErrorWithJson<ErrInvalidConfig>,
+) => {
+  const problems = err.response.data.details.errors.map(
+    ({ field, message }) => (field ? `${field}: ${message}` : message),
+  );
+  return `The Configuration is not valid: ${problems.join("; ")}`;
+};
+
 // Invalid configuration file(s)
 export type ErrInvalidConfigFiles = MkErrorDataType<
   "invalidConfigFile",
@@ -185,5 +227,17 @@ export function resolveAgentJsonErrorMsg(err: axiosErrorWithJson) {
     return errPythonExecNotFoundErrorMessage(err);
   }
 
+  if (isErrInvalidConfig(err)) {
+    return errInvalidConfigMessage(err);
+  }
+
+  if (isErrNoDeployableContentError(err)) {
+    return errNoDeployableContentErrorMessage(err);
+  }
+
+  if (isErrQuartoNotFoundError(err)) {
+    return errQuartoNotFoundErrorMessage(err);
+  }
+
   return errUnknownMessage(err as axiosErrorWithJson<ErrUnknown>);
 }
@@ -146,6 +146,38 @@ export function deployProject(localID: string, stream: EventStream) {
           },
         ),
       );
+      registrations.push(
+        stream.register(
+          "publish/runPrePublish/start",
+          (msg: EventStreamMessage) => {
+            handleProgressMessages(msg);
+          },
+        ),
+      );
+      registrations.push(
+        stream.register(
+          "publish/runPrePublish/success",
+          (msg: EventStreamMessage) => {
+            handleProgressMessages(msg);
+          },
+        ),
+      );
+      registrations.push(
+        stream.register(
+          "publish/runPrePublish/failure",
+          (msg: EventStreamMessage) => {
+            handleProgressMessages(msg);
+          },
+        ),
+      );
+      registrations.push(
+        stream.register(
+          "publish/runPrePublish/log",
+          (msg: EventStreamMessage) => {
+            handleProgressMessages(msg);
+          },
+        ),
+      );
       registrations.push(
         stream.register(
           "publish/createBundle/start",
@@ -5,13 +5,15 @@ package accounts
 type AccountAuthType string
 
 const (
-	AuthTypeNone   AccountAuthType = "none"    // No saved credentials
-	AuthTypeAPIKey AccountAuthType = "api-key" // Connect API key
+	AuthTypeNone        AccountAuthType = "none"         // No saved credentials
+	AuthTypeAPIKey      AccountAuthType = "api-key"      // Connect API key
+	AuthTypeTokenSecret AccountAuthType = "token-secret" // shinyapps.io or Posit Cloud token and secret
 )
 
 var authTypeDescriptions = map[AccountAuthType]string{
-	AuthTypeNone:   "No saved credentials",
-	AuthTypeAPIKey: "Connect API key",
+	AuthTypeNone:        "No saved credentials",
+	AuthTypeAPIKey:      "Connect API key",
+	AuthTypeTokenSecret: "Token and secret",
 }
 
 func (auth AccountAuthType) Description() string {
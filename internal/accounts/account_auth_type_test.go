@@ -20,5 +20,6 @@ func TestAccountAuthTypeSuite(t *testing.T) {
 func (s *AccountAuthTypeSuite) TestDescription() {
 	s.Equal("No saved credentials", AuthTypeNone.Description())
 	s.Equal("Connect API key", AuthTypeAPIKey.Description())
+	s.Equal("Token and secret", AuthTypeTokenSecret.Description())
 	s.Equal("hey", AccountAuthType("hey").Description())
 }
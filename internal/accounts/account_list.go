@@ -35,19 +35,46 @@ func NewAccountList(fs afero.Fs, log logging.Logger) (*defaultAccountList, error
 	}
 
 	return &defaultAccountList{
-		providers: []AccountProvider{cprovider},
+		// Saved credentials take precedence over
+		// the account from CONNECT_SERVER.
+		providers: []AccountProvider{cprovider, newEnvVarProvider(log)},
 		log:       log,
 	}, nil
 }
 
-func (l *defaultAccountList) GetAllAccounts() (accounts []Account, err error) {
-	for _, provider := range l.providers {
+// LoadAccounts loads the accounts from each provider. A provider
+// that fails doesn't prevent loading from the others; its error is
+// returned in errs instead. Accounts with the same name as an
+// earlier account are omitted, so earlier providers take precedence.
+func LoadAccounts(providers []AccountProvider) (accounts []Account, errs []error) {
+	seen := map[string]bool{}
+	for _, provider := range providers {
 		providerAccounts, err := provider.Load()
 		if err != nil {
-			return nil, err
+			errs = append(errs, fmt.Errorf("cannot load accounts from %T: %w", provider, err))
+			continue
 		}
-		accounts = append(accounts, providerAccounts...)
+		for _, account := range providerAccounts {
+			if seen[account.Name] {
+				continue
+			}
+			seen[account.Name] = true
+			accounts = append(accounts, account)
+		}
+	}
+	return accounts, errs
+}
 
+// GetAllAccounts returns the accounts from all providers.
+// It only fails if none of the providers could be loaded;
+// errors from individual providers are logged.
+func (l *defaultAccountList) GetAllAccounts() ([]Account, error) {
+	accounts, errs := LoadAccounts(l.providers)
+	if len(errs) != 0 && len(errs) == len(l.providers) {
+		return nil, errors.Join(errs...)
+	}
+	for _, err := range errs {
+		l.log.Warn("Skipping accounts that could not be loaded", "error", err.Error())
 	}
 	return accounts, nil
 }
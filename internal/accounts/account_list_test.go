@@ -7,7 +7,9 @@ import (
 	"testing"
 
 	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/logging/loggingtest"
 	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -43,7 +45,7 @@ func (s *AccountListSuite) TestNewAccountList() {
 	fs := utiltest.NewMockFs()
 	accountList, err := NewAccountList(fs, log)
 	s.NoError(err)
-	s.Len(accountList.providers, 1)
+	s.Len(accountList.providers, 2)
 	s.Equal(log, accountList.log)
 }
 
@@ -67,7 +69,7 @@ func (s *AccountListSuite) TestGetAllAccountsErr() {
 	log := logging.New()
 
 	accountList := defaultAccountList{
-		providers: []AccountProvider{&s.provider1, &s.erringProvider},
+		providers: []AccountProvider{&s.erringProvider},
 		log:       log,
 	}
 	allAccounts, err := accountList.GetAllAccounts()
@@ -75,6 +77,44 @@ func (s *AccountListSuite) TestGetAllAccountsErr() {
 	s.ErrorIs(err, s.testError)
 }
 
+func (s *AccountListSuite) TestGetAllAccountsPartialErr() {
+	log := loggingtest.NewMockLogger()
+	log.On("Warn", "Skipping accounts that could not be loaded", "error", mock.Anything)
+
+	accountList := defaultAccountList{
+		providers: []AccountProvider{&s.erringProvider, &s.provider1},
+		log:       log,
+	}
+	allAccounts, err := accountList.GetAllAccounts()
+	s.NoError(err)
+	s.Equal([]Account{
+		{Name: "myAcct"},
+		{Name: "yourAcct"},
+	}, allAccounts)
+	log.AssertExpectations(s.T())
+}
+
+func (s *AccountListSuite) TestLoadAccounts() {
+	duplicateProvider := MockAccountProvider{}
+	duplicateProvider.On("Load").Return([]Account{
+		{Name: "myAcct", URL: "https://shadowed.example.com"},
+		{Name: "anotherAcct"},
+	}, nil)
+
+	accounts, errs := LoadAccounts([]AccountProvider{
+		&s.provider1,
+		&s.erringProvider,
+		&duplicateProvider,
+	})
+	s.Equal([]Account{
+		{Name: "myAcct"},
+		{Name: "yourAcct"},
+		{Name: "anotherAcct"},
+	}, accounts)
+	s.Len(errs, 1)
+	s.ErrorIs(errs[0], s.testError)
+}
+
 func (s *AccountListSuite) TestGetAccountByName() {
 	log := logging.New()
 	accountList := defaultAccountList{
@@ -30,3 +30,12 @@ func (s *AccountSuite) TestInferAuthTypeApiKey() {
 	auth := account.InferAuthType()
 	s.Equal(AuthTypeAPIKey, auth)
 }
+
+func (s *AccountSuite) TestInferAuthTypeTokenSecret() {
+	account := Account{
+		Token:  "abc",
+		Secret: "def",
+	}
+	auth := account.InferAuthType()
+	s.Equal(AuthTypeTokenSecret, auth)
+}
@@ -26,19 +26,26 @@ func (p *CredentialsProvider) Load() ([]Account, error) {
 		return nil, err
 	}
 
-	accounts := make([]Account, len(creds))
-	i := 0
+	accounts := make([]Account, 0, len(creds))
 	for _, cred := range creds {
-		accounts[i] = Account{
-			Source:     AccountSourceKeychain,
-			ServerType: serverTypeFromURL(cred.URL),
-			Name:       cred.Name,
-			URL:        cred.URL,
-			AuthType:   AuthTypeAPIKey,
-			ApiKey:     cred.ApiKey,
-		}
-		i++
+		accounts = append(accounts, AccountFromCredential(cred))
 	}
-
 	return accounts, nil
 }
+
+// AccountFromCredential returns the account for
+// a credential from the credentials store.
+func AccountFromCredential(cred credentials.Credential) Account {
+	acct := Account{
+		Source:     AccountSourceKeychain,
+		ServerType: serverTypeFromURL(cred.URL),
+		Name:       cred.Name,
+		URL:        cred.URL,
+		ApiKey:     cred.ApiKey,
+		Token:      cred.Token,
+		Secret:     cred.Secret,
+		GUID:       cred.GUID,
+	}
+	acct.AuthType = acct.InferAuthType()
+	return acct
+}
@@ -3,6 +3,7 @@ package auth
 // Copyright (C) 2023 by Posit Software, PBC.
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/posit-dev/publisher/internal/accounts"
@@ -12,10 +13,18 @@ type AuthMethod interface {
 	AddAuthHeaders(req *http.Request) error
 }
 
-func NewClientAuth(acct *accounts.Account) AuthMethod {
-	switch acct.AuthType {
+// NewClientAuth returns the AuthMethod for the account's type of
+// credentials. It returns an error if the server type doesn't
+// support them yet, such as a token and secret. If the account
+// doesn't have an AuthType, it is inferred from the credentials.
+func NewClientAuth(acct *accounts.Account) (AuthMethod, error) {
+	authType := acct.AuthType
+	if authType == "" {
+		authType = acct.InferAuthType()
+	}
+	switch authType {
 	case accounts.AuthTypeAPIKey:
-		return NewApiKeyAuthenticator(acct.ApiKey, "")
+		return NewApiKeyAuthenticator(acct.ApiKey, ""), nil
 	case accounts.AuthTypeNone:
 		// This is bogus since we know we can't publish
 		// without authentication. Our workflow needs to do one
@@ -25,7 +34,7 @@ func NewClientAuth(acct *accounts.Account) AuthMethod {
 		// * Prompt the user interactively (via the CLI or UI)
 		//   or walk them through the token flow.
 		// * Err if neither of the above can be done.
-		return NewNullAuthenticator()
+		return NewNullAuthenticator(), nil
 	}
-	return nil
+	return nil, fmt.Errorf("%s authentication is not supported for account '%s'", authType.Description(), acct.Name)
 }
@@ -0,0 +1,59 @@
+package auth
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"testing"
+
+	"github.com/posit-dev/publisher/internal/accounts"
+	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/stretchr/testify/suite"
+)
+
+type AuthSuite struct {
+	utiltest.Suite
+}
+
+func TestAuthSuite(t *testing.T) {
+	suite.Run(t, new(AuthSuite))
+}
+
+func (s *AuthSuite) TestNewClientAuthApiKey() {
+	acct := &accounts.Account{
+		AuthType: accounts.AuthTypeAPIKey,
+		ApiKey:   "abc",
+	}
+	auth, err := NewClientAuth(acct)
+	s.NoError(err)
+	s.Equal(NewApiKeyAuthenticator("abc", ""), auth)
+}
+
+func (s *AuthSuite) TestNewClientAuthNone() {
+	acct := &accounts.Account{
+		AuthType: accounts.AuthTypeNone,
+	}
+	auth, err := NewClientAuth(acct)
+	s.NoError(err)
+	s.Equal(NewNullAuthenticator(), auth)
+}
+
+func (s *AuthSuite) TestNewClientAuthTokenSecret() {
+	acct := &accounts.Account{
+		Name:     "myAccount",
+		AuthType: accounts.AuthTypeTokenSecret,
+		Token:    "abc",
+		Secret:   "def",
+	}
+	auth, err := NewClientAuth(acct)
+	s.ErrorContains(err, "Token and secret authentication is not supported for account 'myAccount'")
+	s.Nil(auth)
+}
+
+func (s *AuthSuite) TestNewClientAuthInferred() {
+	acct := &accounts.Account{
+		ApiKey: "abc",
+	}
+	auth, err := NewClientAuth(acct)
+	s.NoError(err)
+	s.Equal(NewApiKeyAuthenticator("abc", ""), auth)
+}
@@ -0,0 +1,123 @@
+package bundles
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+
+	"github.com/posit-dev/publisher/internal/events"
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/util"
+)
+
+var ErrNoManifest = errors.New("bundle does not contain a manifest.json file")
+
+// archiveBundler is a Bundler for an existing bundle archive.
+// It doesn't build anything; CreateBundle copies the archive as-is.
+type archiveBundler struct {
+	path     util.AbsolutePath // Bundle archive (.tar.gz)
+	manifest *Manifest         // Manifest read from the archive
+	files    []FileSize        // Size of each file in the archive
+	log      logging.Logger
+}
+
+// NewBundlerForArchive creates a bundler for a previously built
+// bundle at `path`. The archive must be a gzipped tarball containing
+// a manifest.json at its root.
+func NewBundlerForArchive(path util.AbsolutePath, log logging.Logger) (*archiveBundler, error) {
+	f, err := path.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	manifest, files, err := readArchive(f)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read bundle '%s': %w", path, err)
+	}
+	return &archiveBundler{
+		path:     path,
+		manifest: manifest,
+		files:    files,
+		log:      log.WithArgs(logging.LogKeyOp, events.PublishCreateBundleOp),
+	}, nil
+}
+
+// readArchive returns the manifest and the file sizes
+// from a gzipped bundle archive.
+func readArchive(r io.Reader) (*Manifest, []FileSize, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer gz.Close()
+
+	var manifest *Manifest
+	files := []FileSize{}
+	archive := tar.NewReader(gz)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := path.Clean(header.Name)
+		if name == ManifestFilename {
+			manifest, err = ReadManifest(archive)
+			if err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+		files = append(files, FileSize{
+			Path: name,
+			Size: header.Size,
+		})
+	}
+	if manifest == nil {
+		return nil, nil, ErrNoManifest
+	}
+	return manifest, files, nil
+}
+
+func (b *archiveBundler) CreateManifest() (*Manifest, error) {
+	return b.manifest.Clone()
+}
+
+func (b *archiveBundler) CreateBundle(archive io.Writer) (*Manifest, error) {
+	b.log.Info("Using existing bundle", "path", b.path)
+	f, err := b.path.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(archive, f)
+	if err != nil {
+		return nil, err
+	}
+	return b.manifest.Clone()
+}
+
+// LargestFiles returns up to n files from the archive, largest first.
+// Files of equal size are ordered by path.
+func (b *archiveBundler) LargestFiles(n int) []FileSize {
+	return largestFiles(b.files, n)
+}
+
+// IsArchive returns true if the bundler deploys an existing bundle
+// archive, rather than files from the project directory.
+func IsArchive(b Bundler) bool {
+	_, ok := b.(*archiveBundler)
+	return ok
+}
@@ -0,0 +1,106 @@
+package bundles
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	"github.com/posit-dev/publisher/internal/clients/connect"
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/util"
+	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/suite"
+)
+
+type ArchiveBundlerSuite struct {
+	utiltest.Suite
+
+	cwd util.AbsolutePath
+}
+
+func TestArchiveBundlerSuite(t *testing.T) {
+	suite.Run(t, new(ArchiveBundlerSuite))
+}
+
+func (s *ArchiveBundlerSuite) SetupTest() {
+	cwd, err := util.Getwd(afero.NewMemMapFs())
+	s.NoError(err)
+	s.cwd = cwd
+	s.NoError(s.cwd.MkdirAll(0700))
+}
+
+// writeArchive writes a gzipped tarball containing the given files.
+func (s *ArchiveBundlerSuite) writeArchive(name string, files map[string]string) util.AbsolutePath {
+	buf := new(bytes.Buffer)
+	gz := gzip.NewWriter(buf)
+	archive := tar.NewWriter(gz)
+	for filename, content := range files {
+		err := archive.WriteHeader(&tar.Header{
+			Name:     filename,
+			Mode:     0600,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		})
+		s.NoError(err)
+		_, err = archive.Write([]byte(content))
+		s.NoError(err)
+	}
+	s.NoError(archive.Close())
+	s.NoError(gz.Close())
+
+	path := s.cwd.Join(name)
+	s.NoError(path.WriteFile(buf.Bytes(), 0600))
+	return path
+}
+
+func (s *ArchiveBundlerSuite) TestNewBundlerForArchive() {
+	manifest := NewManifest()
+	manifest.Metadata.AppMode = connect.PythonDashMode
+	manifest.Metadata.Entrypoint = "app.py"
+	manifestJSON, err := manifest.ToJSON()
+	s.NoError(err)
+
+	path := s.writeArchive("bundle.tar.gz", map[string]string{
+		"manifest.json":    string(manifestJSON),
+		"app.py":           "import dash\n",
+		"requirements.txt": "dash\n",
+	})
+	bundler, err := NewBundlerForArchive(path, logging.New())
+	s.NoError(err)
+
+	m, err := bundler.CreateManifest()
+	s.NoError(err)
+	s.Equal(connect.PythonDashMode, m.Metadata.AppMode)
+	s.Equal("app.py", m.Metadata.Entrypoint)
+	s.Equal([]FileSize{
+		{Path: "app.py", Size: 12},
+		{Path: "requirements.txt", Size: 5},
+	}, bundler.LargestFiles(5))
+
+	// The bundle is uploaded unchanged.
+	dest := new(bytes.Buffer)
+	_, err = bundler.CreateBundle(dest)
+	s.NoError(err)
+	original, err := path.ReadFile()
+	s.NoError(err)
+	s.Equal(original, dest.Bytes())
+}
+
+func (s *ArchiveBundlerSuite) TestNewBundlerForArchiveNoManifest() {
+	path := s.writeArchive("bundle.tar.gz", map[string]string{
+		"app.py": "import dash\n",
+	})
+	_, err := NewBundlerForArchive(path, logging.New())
+	s.ErrorIs(err, ErrNoManifest)
+}
+
+func (s *ArchiveBundlerSuite) TestNewBundlerForArchiveNotGzipped() {
+	path := s.cwd.Join("bundle.tar.gz")
+	s.NoError(path.WriteFile([]byte("not a bundle"), 0600))
+	_, err := NewBundlerForArchive(path, logging.New())
+	s.Error(err)
+}
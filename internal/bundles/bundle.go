@@ -7,10 +7,19 @@ import (
 	"bytes"
 	"compress/gzip"
 	"crypto/md5"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
+	"maps"
+	"path"
 	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/posit-dev/publisher/internal/bundles/matcher"
 	"github.com/posit-dev/publisher/internal/events"
@@ -21,6 +30,17 @@ import (
 type Bundler interface {
 	CreateManifest() (*Manifest, error)
 	CreateBundle(archive io.Writer) (*Manifest, error)
+	LargestFiles(n int) []FileSize
+}
+
+// FileSize records the uncompressed size of a single file in the bundle.
+type FileSize struct {
+	Path string `json:"path" mapstructure:"path"` // Posix path relative to the bundle root
+	Size int64  `json:"size" mapstructure:"size"` // Size in bytes
+}
+
+func (f FileSize) String() string {
+	return fmt.Sprintf("%s (%d bytes)", f.Path, f.Size)
 }
 
 // NewBundler creates a bundler that will archive the directory specified
@@ -28,8 +48,19 @@ type Bundler interface {
 // The provided manifest should contain the metadata for the app,
 // such as the entrypoint, Python version, R package dependencies, etc.
 // The bundler will fill in the `files` section and include the manifest.json
-// in the bundler.
-func NewBundler(path util.AbsolutePath, manifest *Manifest, filePatterns []string, log logging.Logger) (*bundler, error) {
+// in the bundler. Symlinks are handled according to `symlinkPolicy`;
+// if empty, util.DefaultSymlinkPolicy is used. Each of the project-relative
+// `emptyDirs` is added to the bundle as a directory entry, even
+// if it contains no files.
+func NewBundler(path util.AbsolutePath, manifest *Manifest, filePatterns []string, emptyDirs []string, symlinkPolicy util.SymlinkPolicy, log logging.Logger) (*bundler, error) {
+	err := util.ValidSymlinkPolicy(symlinkPolicy)
+	if err != nil {
+		return nil, err
+	}
+	cleanDirs, err := cleanEmptyDirs(emptyDirs)
+	if err != nil {
+		return nil, err
+	}
 	var dir util.AbsolutePath
 	var filename string
 	isDir, err := path.IsDir()
@@ -47,39 +78,163 @@ func NewBundler(path util.AbsolutePath, manifest *Manifest, filePatterns []strin
 		log.Info("No file patterns specified; using default pattern '*'")
 		filePatterns = []string{"*"}
 	}
-	matcher, err := matcher.NewMatchingWalker(filePatterns, dir, log)
+	patterns := slices.Concat(filePatterns, matcher.BundleExclusions)
+	matcher, err := matcher.NewMatchingWalker(patterns, dir, log)
 	if err != nil {
 		return nil, err
 	}
 
 	log = log.WithArgs(logging.LogKeyOp, events.PublishCreateBundleOp)
-	symlinkWalker := util.NewSymlinkWalker(matcher, log)
+	symlinkWalker := util.NewSymlinkWalker(matcher, symlinkPolicy, log)
 
 	return &bundler{
-		manifest: manifest,
-		baseDir:  dir,
-		filename: filename,
-		walker:   symlinkWalker,
-		log:      log,
+		manifest:    manifest,
+		baseDir:     dir,
+		filename:    filename,
+		emptyDirs:   cleanDirs,
+		walker:      symlinkWalker,
+		hashWorkers: defaultHashWorkers(),
+		log:         log,
 	}, nil
 }
 
+var ErrManifestFilesMissing = errors.New("files listed in the manifest are missing")
+var ErrManifestFilesChanged = errors.New("files have changed since the manifest was written")
+
+// NewBundlerForManifest creates a bundler that will archive exactly
+// the files listed in the manifest at `manifestPath`. Paths in the
+// manifest are relative to the directory containing it.
+// All of the listed files must exist. Symlinks are handled
+// according to `symlinkPolicy`, as in NewBundler.
+func NewBundlerForManifest(manifestPath util.AbsolutePath, symlinkPolicy util.SymlinkPolicy, log logging.Logger) (*bundler, error) {
+	err := util.ValidSymlinkPolicy(symlinkPolicy)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := ReadManifestFile(manifestPath.Path)
+	if err != nil {
+		return nil, err
+	}
+	dir := manifestPath.Dir()
+	filenames := manifest.GetFilenames()
+	missing := []string{}
+	for _, name := range filenames {
+		if !filepath.IsLocal(filepath.FromSlash(name)) {
+			return nil, fmt.Errorf("manifest file paths must be relative paths within the project directory: '%s'", name)
+		}
+		exists, err := dir.Join(filepath.FromSlash(name)).Exists()
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) != 0 {
+		return nil, fmt.Errorf("%w: %s", ErrManifestFilesMissing, strings.Join(missing, ", "))
+	}
+	// Checksums are recomputed as the files are added.
+	manifest.Files = NewManifestFileMap()
+
+	log = log.WithArgs(logging.LogKeyOp, events.PublishCreateBundleOp)
+	walker := util.NewSymlinkWalker(newManifestWalker(dir, filenames), symlinkPolicy, log)
+	return &bundler{
+		manifest:    manifest,
+		baseDir:     dir,
+		emptyDirs:   []string{},
+		walker:      walker,
+		hashWorkers: defaultHashWorkers(),
+		log:         log,
+	}, nil
+}
+
+var errBadEmptyDir = errors.New("empty directories must be relative paths within the project directory")
+
+// cleanEmptyDirs normalizes the list of empty directories to
+// Posix paths relative to the project directory.
+func cleanEmptyDirs(dirs []string) ([]string, error) {
+	cleanDirs := []string{}
+	for _, dir := range dirs {
+		dir = filepath.ToSlash(dir)
+		cleanDir := path.Clean(dir)
+		if !filepath.IsLocal(filepath.FromSlash(cleanDir)) || cleanDir == "." {
+			return nil, fmt.Errorf("%w: '%s'", errBadEmptyDir, dir)
+		}
+		cleanDirs = append(cleanDirs, cleanDir)
+	}
+	return cleanDirs, nil
+}
+
 type bundler struct {
-	baseDir  util.AbsolutePath // Directory being bundled
-	filename string            // Primary file being deployed
-	walker   util.Walker       // Only walks files matching patterns from the configuration
-	manifest *Manifest         // Manifest describing the bundle, if provided
-	log      logging.Logger
+	baseDir       util.AbsolutePath // Directory being bundled
+	filename      string            // Primary file being deployed
+	emptyDirs     []string          // Directories to include even if they have no files
+	walker        util.Walker       // Only walks files matching patterns from the configuration
+	manifest      *Manifest         // Manifest describing the bundle, if provided
+	files         []FileSize        // Sizes of the files included by the most recent pass
+	hashWorkers   int               // Number of files to hash concurrently when creating a manifest
+	deterministic bool              // Produce the same bundle bytes for the same files
+	generated     map[string][]byte // Files that aren't in the project directory, by Posix path
+	log           logging.Logger
 }
 
 type bundle struct {
 	*bundler
-	manifest *Manifest      // Manifest describing the bundle
-	archive  util.TarWriter // Archive containing the files
-	numFiles int64          // Number of files in the bundle
-	size     int64          // Total uncompressed size of the files, in bytes
+	manifest *Manifest       // Manifest describing the bundle
+	archive  util.TarWriter  // Archive containing the files
+	numFiles int64           // Number of files in the bundle
+	size     int64           // Total uncompressed size of the files, in bytes
+	files    []FileSize      // Size of each file in the bundle
+	dirs     map[string]bool // Directories already written to the archive
+	toHash   []fileToHash    // Files waiting to be hashed, in walk order
+}
+
+// fileToHash is a file found while creating a manifest,
+// whose checksum hasn't been computed yet.
+type fileToHash struct {
+	path    util.AbsolutePath
+	relPath string // Posix path relative to the bundle root
 }
 
+func defaultHashWorkers() int {
+	return runtime.NumCPU()
+}
+
+// SetHashWorkers sets the number of files that CreateManifest
+// hashes concurrently. The default is the number of CPUs.
+// Values less than 2 hash the files one at a time.
+// Bundles are always written one file at a time, since
+// the order of the files in the archive matters.
+func (b *bundler) SetHashWorkers(n int) {
+	b.hashWorkers = n
+}
+
+// SetDeterministic enables creating bundles that are byte-for-byte
+// identical when the files are the same, for reproducible deployments
+// and content-addressable caching. Files are added in order by path,
+// and modification times and owners are omitted from the archive.
+func (b *bundler) SetDeterministic(deterministic bool) {
+	b.deterministic = deterministic
+}
+
+// AddGeneratedFile adds a file that isn't in the project directory,
+// such as requirements exported from a lock file, to the bundle.
+// It replaces the project file with the same path, if there is one.
+func (b *bundler) AddGeneratedFile(name string, content []byte) {
+	if b.generated == nil {
+		b.generated = make(map[string][]byte)
+	}
+	b.generated[path.Clean(filepath.ToSlash(name))] = content
+}
+
+// deterministicModTime is the modification time of every
+// entry in a deterministic bundle.
+var deterministicModTime = time.Unix(0, 0)
+
+// deterministicGzipLevel is the compression level of deterministic
+// bundles, so that their bytes don't change if the default does.
+const deterministicGzipLevel = 6
+
 func (b *bundler) CreateManifest() (*Manifest, error) {
 	b.log.Info("Creating manifest from directory", "source_dir", b.baseDir)
 	return b.makeBundle(nil)
@@ -93,6 +248,7 @@ func (b *bundler) CreateBundle(archive io.Writer) (*Manifest, error) {
 func (b *bundler) makeBundle(dest io.Writer) (*Manifest, error) {
 	bundle := &bundle{
 		bundler: b,
+		dirs:    make(map[string]bool),
 	}
 	if b.manifest != nil {
 		manifestCopy, err := b.manifest.Clone()
@@ -102,10 +258,21 @@ func (b *bundler) makeBundle(dest io.Writer) (*Manifest, error) {
 		bundle.manifest = manifestCopy
 	}
 	if dest != nil {
-		gzipper := gzip.NewWriter(dest)
+		level := gzip.DefaultCompression
+		if b.deterministic {
+			level = deterministicGzipLevel
+		}
+		gzipper, err := gzip.NewWriterLevel(dest, level)
+		if err != nil {
+			return nil, err
+		}
 		defer gzipper.Close()
 
-		bundle.archive = tar.NewWriter(gzipper)
+		var archive util.TarWriter = tar.NewWriter(gzipper)
+		if b.deterministic {
+			archive = normalizingTarWriter{archive}
+		}
+		bundle.archive = archive
 		defer bundle.archive.Close()
 	}
 
@@ -119,6 +286,10 @@ func (b *bundler) makeBundle(dest io.Writer) (*Manifest, error) {
 	if err != nil {
 		return nil, fmt.Errorf("error creating bundle: %w", err)
 	}
+	err = bundle.hashFiles()
+	if err != nil {
+		return nil, fmt.Errorf("error creating bundle: %w", err)
+	}
 	if b.filename != "" {
 		// Ensure that the main file was not excluded
 		_, ok := bundle.manifest.Files[b.filename]
@@ -132,18 +303,55 @@ func (b *bundler) makeBundle(dest io.Writer) (*Manifest, error) {
 			if err != nil {
 				return nil, err
 			}
+			err = bundle.hashFiles()
+			if err != nil {
+				return nil, err
+			}
 		}
 	}
+	err = bundle.addGeneratedFiles()
+	if err != nil {
+		return nil, fmt.Errorf("error creating bundle: %w", err)
+	}
 	if dest != nil {
+		err = bundle.addEmptyDirs()
+		if err != nil {
+			return nil, err
+		}
 		err = bundle.addManifest()
 		if err != nil {
 			return nil, err
 		}
 	}
+	b.files = bundle.files
 	b.log.Info("Bundle created", "files", bundle.numFiles, "total_bytes", bundle.size)
 	return bundle.manifest, nil
 }
 
+// LargestFiles returns up to n files from the most recent
+// CreateManifest or CreateBundle call, largest first.
+// Files of equal size are ordered by path.
+func (b *bundler) LargestFiles(n int) []FileSize {
+	return largestFiles(b.files, n)
+}
+
+// largestFiles returns up to n of the files, largest first.
+// Files of equal size are ordered by path.
+func largestFiles(all []FileSize, n int) []FileSize {
+	files := make([]FileSize, len(all))
+	copy(files, all)
+	sort.SliceStable(files, func(i, j int) bool {
+		if files[i].Size != files[j].Size {
+			return files[i].Size > files[j].Size
+		}
+		return files[i].Path < files[j].Path
+	})
+	if n >= 0 && n < len(files) {
+		files = files[:n]
+	}
+	return files
+}
+
 // writeHeaderToTar writes a file or directory entry to the tar archive.
 func writeHeaderToTar(info fs.FileInfo, path string, archive util.TarWriter) error {
 	if archive == nil {
@@ -204,32 +412,97 @@ func (b *bundle) walkFunc(path util.AbsolutePath, info fs.FileInfo, err error) e
 		if err != nil {
 			return err
 		}
+		b.dirs[relPath.ToSlash()] = true
+	} else if _, ok := b.generated[relPath.ToSlash()]; ok && info.Mode().IsRegular() {
+		pathLogger.Debug("Skipping file replaced by a generated file")
 	} else if info.Mode().IsRegular() {
 		pathLogger.Debug("Adding file")
-		// Manifest filenames are always Posix paths, not Windows paths
-		err = writeHeaderToTar(info, relPath.ToSlash(), b.archive)
-		if err != nil {
-			return err
-		}
-		f, err := path.Open()
-		if err != nil {
-			return err
-		}
-		defer f.Close()
-		fileMD5, err := writeFileContentsToTar(f, b.archive)
-		if err != nil {
-			return err
+		if b.archive == nil && b.hashWorkers > 1 {
+			// Hashed later by hashFiles.
+			b.toHash = append(b.toHash, fileToHash{
+				path:    path,
+				relPath: relPath.ToSlash(),
+			})
+		} else {
+			// Manifest filenames are always Posix paths, not Windows paths
+			err = writeHeaderToTar(info, relPath.ToSlash(), b.archive)
+			if err != nil {
+				return err
+			}
+			f, err := path.Open()
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			fileMD5, err := writeFileContentsToTar(f, b.archive)
+			if err != nil {
+				return err
+			}
+			b.manifest.AddFile(relPath.ToSlash(), fileMD5)
 		}
-		b.manifest.AddFile(relPath.ToSlash(), fileMD5)
 		b.numFiles++
 		b.size += info.Size()
+		b.files = append(b.files, FileSize{
+			Path: relPath.ToSlash(),
+			Size: info.Size(),
+		})
 	} else {
 		pathLogger.Warn("Skipping non-regular file")
 	}
 	return nil
 }
 
+// hashFiles computes the checksums of the files found while
+// creating a manifest, using up to hashWorkers goroutines.
+// The files are added to the manifest in the order they were found,
+// so the result is the same as hashing them one at a time.
+func (b *bundle) hashFiles() error {
+	files := b.toHash
+	b.toHash = nil
+	if len(files) == 0 {
+		return nil
+	}
+	sums := make([][]byte, len(files))
+	errs := make([]error, len(files))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range min(b.hashWorkers, len(files)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				sums[i], errs[i] = hashFile(files[i].path)
+			}
+		}()
+	}
+	for i := range files {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	for i, file := range files {
+		if errs[i] != nil {
+			return errs[i]
+		}
+		b.manifest.AddFile(file.relPath, sums[i])
+	}
+	return nil
+}
+
+func hashFile(path util.AbsolutePath) ([]byte, error) {
+	f, err := path.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return writeFileContentsToTar(f, nil)
+}
+
 func (b *bundle) addDirectory(dir util.AbsolutePath) error {
+	if b.deterministic {
+		return b.addDirectorySorted(dir)
+	}
 	err := b.walker.Walk(dir, b.walkFunc)
 	if err != nil {
 		return err
@@ -237,6 +510,67 @@ func (b *bundle) addDirectory(dir util.AbsolutePath) error {
 	return nil
 }
 
+// walkEntry is a file or directory found while walking the project.
+type walkEntry struct {
+	path    util.AbsolutePath
+	relPath string
+	info    fs.FileInfo
+}
+
+// addDirectorySorted adds the files in the directory in order by
+// path, rather than the order the walker finds them.
+func (b *bundle) addDirectorySorted(dir util.AbsolutePath) error {
+	var entries []walkEntry
+	err := b.walker.Walk(dir, func(path util.AbsolutePath, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := path.Rel(b.baseDir)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, walkEntry{
+			path:    path,
+			relPath: relPath.ToSlash(),
+			info:    info,
+		})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].relPath < entries[j].relPath
+	})
+	for _, entry := range entries {
+		err = b.walkFunc(entry.path, entry.info, nil)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// normalizingTarWriter removes the details that differ between
+// runs or machines from each header: modification times and owners.
+type normalizingTarWriter struct {
+	util.TarWriter
+}
+
+func (w normalizingTarWriter) WriteHeader(header *tar.Header) error {
+	normalized := *header
+	normalized.ModTime = deterministicModTime
+	normalized.AccessTime = time.Time{}
+	normalized.ChangeTime = time.Time{}
+	normalized.Uid = 0
+	normalized.Gid = 0
+	normalized.Uname = ""
+	normalized.Gname = ""
+	normalized.PAXRecords = nil
+	normalized.Format = tar.FormatUnknown
+	return w.TarWriter.WriteHeader(&normalized)
+}
+
 func (b *bundle) addFile(name string, content []byte) error {
 	header := &tar.Header{
 		Name: name,
@@ -258,6 +592,61 @@ func (b *bundle) addFile(name string, content []byte) error {
 	return nil
 }
 
+// addEmptyDirs adds directory entries for the configured empty
+// directories (and their parents) that were not already added
+// while walking the project directory.
+func (b *bundle) addEmptyDirs() error {
+	for _, dir := range b.emptyDirs {
+		var toAdd []string
+		for d := dir; d != "."; d = path.Dir(d) {
+			if b.dirs[d] {
+				break
+			}
+			toAdd = append(toAdd, d)
+		}
+		// Add parents before children
+		for i := len(toAdd) - 1; i >= 0; i-- {
+			header := &tar.Header{
+				Name:     toAdd[i] + "/",
+				Typeflag: tar.TypeDir,
+				Mode:     0777,
+			}
+			err := b.archive.WriteHeader(header)
+			if err != nil {
+				return err
+			}
+			b.dirs[toAdd[i]] = true
+			b.log.Debug("Adding empty directory", "path", toAdd[i])
+		}
+	}
+	return nil
+}
+
+// addGeneratedFiles adds the generated files, in order by path.
+func (b *bundle) addGeneratedFiles() error {
+	names := slices.Sorted(maps.Keys(b.generated))
+	for _, name := range names {
+		content := b.generated[name]
+		b.log.Debug("Adding generated file", "path", name, "size", len(content))
+		if b.archive != nil {
+			err := b.addFile(name, content)
+			if err != nil {
+				return err
+			}
+		} else {
+			sum := md5.Sum(content)
+			b.manifest.AddFile(name, sum[:])
+		}
+		b.numFiles++
+		b.size += int64(len(content))
+		b.files = append(b.files, FileSize{
+			Path: name,
+			Size: int64(len(content)),
+		})
+	}
+	return nil
+}
+
 func (b *bundle) addManifest() error {
 	manifestJSON, err := b.manifest.ToJSON()
 	if err != nil {
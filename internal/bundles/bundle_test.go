@@ -6,7 +6,10 @@ import (
 	"archive/tar"
 	"bytes"
 	"compress/gzip"
+	"crypto/md5"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"io"
 	"io/fs"
 	"os"
@@ -165,7 +168,7 @@ func (s *BundlerSuite) TestNewBundlerDirectory() {
 	log := loggingtest.NewMockLogger()
 	log.On("WithArgs", logging.LogKeyOp, events.PublishCreateBundleOp).Return(log)
 	log.On("Info", mock.Anything)
-	bundler, err := NewBundler(s.cwd, NewManifest(), nil, log)
+	bundler, err := NewBundler(s.cwd, NewManifest(), nil, nil, util.SymlinkFollow, log)
 	s.Nil(err)
 	s.NotNil(bundler)
 	log.AssertExpectations(s.T())
@@ -178,7 +181,7 @@ func (s *BundlerSuite) TestNewBundlerFile() {
 	path := s.cwd.Join("app.py")
 	err := path.WriteFile([]byte("import flask\napp=flask.Flask(__name)\n"), 0600)
 	s.Nil(err)
-	bundler, err := NewBundler(path, NewManifest(), nil, log)
+	bundler, err := NewBundler(path, NewManifest(), nil, nil, util.SymlinkFollow, log)
 	s.Nil(err)
 	s.NotNil(bundler)
 	log.AssertExpectations(s.T())
@@ -204,7 +207,7 @@ func (s *BundlerSuite) TestCreateBundle() {
 	dest := new(bytes.Buffer)
 	log := logging.New()
 
-	bundler, err := NewBundler(s.cwd, NewManifest(), nil, log)
+	bundler, err := NewBundler(s.cwd, NewManifest(), nil, nil, util.SymlinkFollow, log)
 	s.Nil(err)
 	manifest, err := bundler.CreateBundle(dest)
 	s.Nil(err)
@@ -223,12 +226,220 @@ func (s *BundlerSuite) TestCreateBundle() {
 	}, s.getTarFileNames(dest))
 }
 
+func (s *BundlerSuite) TestCreateBundleExcludesPositDir() {
+	s.makeFile("app.py")
+	s.makeFile(filepath.Join(".posit", "publish", "default.toml"))
+	s.makeFile(filepath.Join(".posit", "publish", "deployments", "deployment.toml"))
+	s.makeFile(filepath.Join(".posit", "publish", "cache", "bundle.tar.gz"))
+	s.makeFile(filepath.Join("subproject", ".posit", "publish", "default.toml"))
+
+	for _, patterns := range [][]string{
+		nil,
+		{"/**"},
+		{"/app.py", "/.posit/", "/.posit/publish/default.toml"},
+	} {
+		dest := new(bytes.Buffer)
+		bundler, err := NewBundler(s.cwd, NewManifest(), patterns, nil, util.SymlinkFollow, logging.New())
+		s.Nil(err)
+		manifest, err := bundler.CreateBundle(dest)
+		s.Nil(err)
+		s.Equal([]string{"app.py"}, manifest.GetFilenames(), "patterns %v", patterns)
+		for _, name := range s.getTarFileNames(dest) {
+			s.NotContains(name, ".posit", "patterns %v", patterns)
+		}
+	}
+}
+
+func (s *BundlerSuite) TestCreateBundleEmptyDirs() {
+	s.makeFile("testfile")
+	s.makeFile(filepath.Join("subdir", "testfile"))
+	err := s.cwd.Join("cache").MkdirAll(0700)
+	s.Nil(err)
+
+	dest := new(bytes.Buffer)
+	log := logging.New()
+
+	emptyDirs := []string{"cache", "subdir/tmp", "data/tmp/"}
+	bundler, err := NewBundler(s.cwd, NewManifest(), []string{"*", "!cache"}, emptyDirs, util.SymlinkFollow, log)
+	s.Nil(err)
+	manifest, err := bundler.CreateBundle(dest)
+	s.Nil(err)
+	s.NotNil(manifest)
+	s.Equal([]string{
+		"subdir/testfile",
+		"testfile",
+	}, manifest.GetFilenames())
+	s.Equal([]string{
+		"cache/",
+		"data/",
+		"data/tmp/",
+		"manifest.json",
+		"subdir/",
+		"subdir/testfile",
+		"subdir/tmp/",
+		"testfile",
+	}, s.getTarFileNames(dest))
+}
+
+func (s *BundlerSuite) TestCreateBundleGeneratedFile() {
+	s.makeFile("app.py")
+	s.makeFileWithContents("requirements.txt", []byte("flask==2.0.0\n"))
+	generated := []byte("flask==3.0.3\n")
+
+	dest := new(bytes.Buffer)
+	bundler, err := NewBundler(s.cwd, NewManifest(), nil, nil, util.SymlinkFollow, logging.New())
+	s.Nil(err)
+	bundler.AddGeneratedFile("requirements.txt", generated)
+
+	manifest, err := bundler.CreateManifest()
+	s.Nil(err)
+	sum := md5.Sum(generated)
+	s.Equal(hex.EncodeToString(sum[:]), manifest.Files["requirements.txt"].Checksum)
+
+	manifest, err = bundler.CreateBundle(dest)
+	s.Nil(err)
+	s.Equal([]string{"app.py", "requirements.txt"}, manifest.GetFilenames())
+	s.Equal(hex.EncodeToString(sum[:]), manifest.Files["requirements.txt"].Checksum)
+
+	// The generated file replaces the project file.
+	unzipper, err := gzip.NewReader(dest)
+	s.NoError(err)
+	reader := tar.NewReader(unzipper)
+	contents := map[string][]byte{}
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		s.NoError(err)
+		content, err := io.ReadAll(reader)
+		s.NoError(err)
+		contents[header.Name] = content
+	}
+	s.Len(contents, 3)
+	s.Equal(generated, contents["requirements.txt"])
+
+	// The project file isn't changed.
+	content, err := s.cwd.Join("requirements.txt").ReadFile()
+	s.NoError(err)
+	s.Equal([]byte("flask==2.0.0\n"), content)
+}
+
+func (s *BundlerSuite) TestNewBundlerBadEmptyDirs() {
+	log := logging.New()
+	for _, dir := range []string{"../outside", "/abs", "."} {
+		bundler, err := NewBundler(s.cwd, NewManifest(), nil, []string{dir}, util.SymlinkFollow, log)
+		s.ErrorIs(err, errBadEmptyDir)
+		s.Nil(bundler)
+	}
+}
+
+func (s *BundlerSuite) writeManifest(files ...string) util.AbsolutePath {
+	return s.writeManifestIn(s.cwd, files...)
+}
+
+func (s *BundlerSuite) writeManifestIn(dir util.AbsolutePath, files ...string) util.AbsolutePath {
+	manifest := NewManifest()
+	manifest.Metadata.AppMode = "python-dash"
+	for _, f := range files {
+		manifest.AddFile(f, []byte("stale checksum"))
+	}
+	path := dir.Join(ManifestFilename)
+	s.Nil(manifest.WriteManifestFile(path.Path))
+	return path
+}
+
+func (s *BundlerSuite) TestNewBundlerForManifest() {
+	s.makeFile("app.py")
+	s.makeFile(filepath.Join("subdir", "testfile"))
+	s.makeFile("unlisted")
+	manifestPath := s.writeManifest("app.py", "subdir/testfile")
+
+	bundler, err := NewBundlerForManifest(manifestPath, util.SymlinkFollow, logging.New())
+	s.Nil(err)
+	dest := new(bytes.Buffer)
+	manifest, err := bundler.CreateBundle(dest)
+	s.Nil(err)
+	s.Equal("python-dash", string(manifest.Metadata.AppMode))
+	s.Equal([]string{"app.py", "subdir/testfile"}, manifest.GetFilenames())
+	s.NotEqual("7374616c6520636865636b73756d", manifest.Files["app.py"].Checksum)
+	s.Equal([]string{
+		"app.py",
+		"manifest.json",
+		"subdir/",
+		"subdir/testfile",
+	}, s.getTarFileNames(dest))
+}
+
+func (s *BundlerSuite) TestNewBundlerForManifestMissingFiles() {
+	s.makeFile("app.py")
+	manifestPath := s.writeManifest("app.py", "missing.py", "subdir/missing")
+
+	_, err := NewBundlerForManifest(manifestPath, util.SymlinkFollow, logging.New())
+	s.ErrorIs(err, ErrManifestFilesMissing)
+	s.ErrorContains(err, "missing.py, subdir/missing")
+}
+
+func (s *BundlerSuite) TestNewBundlerForManifestOutsideProject() {
+	manifestPath := s.writeManifest("../app.py")
+
+	_, err := NewBundlerForManifest(manifestPath, util.SymlinkFollow, logging.New())
+	s.ErrorContains(err, "must be relative paths within the project directory")
+}
+
+func (s *BundlerSuite) TestNewBundlerForManifestSymlinks() {
+	if runtime.GOOS == "windows" {
+		s.T().Skip()
+	}
+	// afero's MemFs doesn't have symlink support.
+	fs := afero.NewOsFs()
+	dirPath := util.NewAbsolutePath(s.T().TempDir(), fs)
+	s.NoError(dirPath.Join("target").MkdirAll(0700))
+	s.NoError(dirPath.Join("app.py").WriteFile([]byte("import dash\n"), 0600))
+	s.NoError(dirPath.Join("target", "data.csv").WriteFile([]byte("a,b\n"), 0600))
+	s.NoError(dirPath.Join("target", "unlisted.csv").WriteFile([]byte("c,d\n"), 0600))
+	s.NoError(os.Symlink("target", dirPath.Join("linked").String()))
+	manifestPath := s.writeManifestIn(dirPath, "app.py", "linked/data.csv")
+
+	bundler, err := NewBundlerForManifest(manifestPath, util.SymlinkFollow, logging.New())
+	s.Nil(err)
+	dest := new(bytes.Buffer)
+	manifest, err := bundler.CreateBundle(dest)
+	s.NoError(err)
+	s.Equal([]string{"app.py", "linked/data.csv"}, manifest.GetFilenames())
+
+	bundler, err = NewBundlerForManifest(manifestPath, util.SymlinkSkip, logging.New())
+	s.Nil(err)
+	manifest, err = bundler.CreateBundle(new(bytes.Buffer))
+	s.NoError(err)
+	s.Equal([]string{"app.py"}, manifest.GetFilenames())
+
+	bundler, err = NewBundlerForManifest(manifestPath, util.SymlinkError, logging.New())
+	s.Nil(err)
+	_, err = bundler.CreateBundle(new(bytes.Buffer))
+	s.ErrorIs(err, util.ErrSymlinkNotAllowed)
+}
+
+func (s *BundlerSuite) TestNewBundlerForManifestFileRemoved() {
+	s.makeFile("app.py")
+	s.makeFile("data.csv")
+	manifestPath := s.writeManifest("app.py", "data.csv")
+
+	bundler, err := NewBundlerForManifest(manifestPath, util.SymlinkFollow, logging.New())
+	s.Nil(err)
+	s.NoError(s.cwd.Join("data.csv").Remove())
+	_, err = bundler.CreateBundle(new(bytes.Buffer))
+	s.ErrorIs(err, ErrManifestFilesMissing)
+	s.ErrorContains(err, ": data.csv")
+	s.NotContains(err.Error(), s.cwd.String())
+}
+
 func (s *BundlerSuite) TestCreateBundleAutoDetect() {
 	s.makeFileWithContents("app.py", []byte("import flask"))
 	dest := new(bytes.Buffer)
 	log := logging.New()
 
-	bundler, err := NewBundler(s.cwd, NewManifest(), nil, log)
+	bundler, err := NewBundler(s.cwd, NewManifest(), nil, nil, util.SymlinkFollow, log)
 	s.Nil(err)
 	manifest, err := bundler.CreateBundle(dest)
 	s.Nil(err)
@@ -243,7 +454,7 @@ func (s *BundlerSuite) TestCreateBundleAutoDetect() {
 func (s *BundlerSuite) TestCreateBundleMissingDirectory() {
 	path := util.NewAbsolutePath("/nonexistent", s.fs)
 	log := logging.New()
-	bundler, err := NewBundler(path, NewManifest(), nil, log)
+	bundler, err := NewBundler(path, NewManifest(), nil, nil, util.SymlinkFollow, log)
 	s.NotNil(err)
 	s.ErrorIs(err, os.ErrNotExist)
 	s.Nil(bundler)
@@ -252,7 +463,7 @@ func (s *BundlerSuite) TestCreateBundleMissingDirectory() {
 func (s *BundlerSuite) TestCreateBundleMissingFile() {
 	log := logging.New()
 	path := s.cwd.Join("nonexistent")
-	bundler, err := NewBundler(path, NewManifest(), nil, log)
+	bundler, err := NewBundler(path, NewManifest(), nil, nil, util.SymlinkFollow, log)
 	s.NotNil(err)
 	s.ErrorIs(err, os.ErrNotExist)
 	s.Nil(bundler)
@@ -264,7 +475,7 @@ func (s *BundlerSuite) TestCreateBundleWalkError() {
 	testError := errors.New("test error from Walk")
 	walker.On("Walk", mock.Anything, mock.Anything).Return(testError)
 
-	bundler, err := NewBundler(s.cwd, NewManifest(), nil, log)
+	bundler, err := NewBundler(s.cwd, NewManifest(), nil, nil, util.SymlinkFollow, log)
 	s.Nil(err)
 	s.NotNil(bundler)
 	bundler.walker = walker
@@ -278,7 +489,7 @@ func (s *BundlerSuite) TestCreateBundleWalkError() {
 
 func (s *BundlerSuite) TestCreateBundleAddManifestError() {
 	log := logging.New()
-	bundler, err := NewBundler(s.cwd, NewManifest(), nil, log)
+	bundler, err := NewBundler(s.cwd, NewManifest(), nil, nil, util.SymlinkFollow, log)
 	s.Nil(err)
 	s.NotNil(bundler)
 
@@ -316,7 +527,7 @@ func (s *BundlerSuite) TestCreateManifest() {
 	s.makeFile(filepath.Join("subdir", "testfile"))
 
 	log := logging.New()
-	bundler, err := NewBundler(s.cwd, NewManifest(), nil, log)
+	bundler, err := NewBundler(s.cwd, NewManifest(), nil, nil, util.SymlinkFollow, log)
 	s.Nil(err)
 
 	manifest, err := bundler.CreateManifest()
@@ -329,6 +540,127 @@ func (s *BundlerSuite) TestCreateManifest() {
 	}, manifest.GetFilenames())
 }
 
+func (s *BundlerSuite) TestCreateManifestContentHash() {
+	s.makeFile("app.py")
+	s.makeFile(filepath.Join("subdir", "testfile"))
+
+	log := logging.New()
+	contentHash := func() string {
+		bundler, err := NewBundler(s.cwd, NewManifest(), nil, nil, util.SymlinkFollow, log)
+		s.Nil(err)
+		manifest, err := bundler.CreateManifest()
+		s.Nil(err)
+		return manifest.ContentHash()
+	}
+	hash := contentHash()
+	s.Equal(hash, contentHash())
+
+	s.makeFileWithContents("app.py", []byte("changed"))
+	s.NotEqual(hash, contentHash())
+}
+
+func (s *BundlerSuite) TestCreateManifestParallel() {
+	for i := range 50 {
+		s.makeFile(filepath.Join(fmt.Sprintf("dir%d", i%5), fmt.Sprintf("file%d", i)))
+	}
+	s.makeFile("app.py")
+
+	log := logging.New()
+	bundler, err := NewBundler(s.cwd, NewManifest(), nil, nil, util.SymlinkFollow, log)
+	s.Nil(err)
+	bundler.SetHashWorkers(1)
+	serial, err := bundler.CreateManifest()
+	s.Nil(err)
+	serialFiles := bundler.LargestFiles(-1)
+
+	bundler.SetHashWorkers(8)
+	parallel, err := bundler.CreateManifest()
+	s.Nil(err)
+	s.Equal(serial, parallel)
+	s.Len(parallel.Files, 51)
+	s.Equal(serialFiles, bundler.LargestFiles(-1))
+
+	serialJSON, err := serial.ToJSON()
+	s.Nil(err)
+	parallelJSON, err := parallel.ToJSON()
+	s.Nil(err)
+	s.Equal(string(serialJSON), string(parallelJSON))
+}
+
+func (s *BundlerSuite) TestCreateManifestParallelMainFile() {
+	s.makeFile("app.py")
+	s.makeFile("helper.py")
+
+	log := logging.New()
+	bundler, err := NewBundler(s.cwd.Join("app.py"), NewManifest(), []string{"helper.py"}, nil, util.SymlinkFollow, log)
+	s.Nil(err)
+	bundler.SetHashWorkers(4)
+	manifest, err := bundler.CreateManifest()
+	s.Nil(err)
+	s.Equal([]string{"app.py", "helper.py"}, manifest.GetFilenames())
+	s.NotEmpty(manifest.Files["app.py"].Checksum)
+}
+
+func BenchmarkCreateManifest(b *testing.B) {
+	fs := afero.NewMemMapFs()
+	cwd, err := util.Getwd(fs)
+	if err != nil {
+		b.Fatal(err)
+	}
+	content := bytes.Repeat([]byte("0123456789abcdef"), 4096)
+	for i := range 1000 {
+		path := cwd.Join(fmt.Sprintf("dir%d", i%10), fmt.Sprintf("file%d", i))
+		err = path.Dir().MkdirAll(0700)
+		if err != nil {
+			b.Fatal(err)
+		}
+		err = path.WriteFile(content, 0600)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+	for _, workers := range []int{1, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			bundler, err := NewBundler(cwd, NewManifest(), nil, nil, util.SymlinkFollow, logging.NewDiscardLogger())
+			if err != nil {
+				b.Fatal(err)
+			}
+			bundler.SetHashWorkers(workers)
+			b.ResetTimer()
+			for range b.N {
+				_, err = bundler.CreateManifest()
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func (s *BundlerSuite) TestLargestFiles() {
+	s.makeFileWithContents("small", []byte("a"))
+	s.makeFileWithContents("medium", []byte("abcde"))
+	s.makeFileWithContents(filepath.Join("subdir", "large"), []byte("abcdefghij"))
+	s.makeFileWithContents("also_medium", []byte("vwxyz"))
+
+	log := logging.New()
+	bundler, err := NewBundler(s.cwd, NewManifest(), nil, nil, util.SymlinkFollow, log)
+	s.Nil(err)
+	s.Len(bundler.LargestFiles(3), 0)
+
+	dest := new(bytes.Buffer)
+	_, err = bundler.CreateBundle(dest)
+	s.Nil(err)
+
+	s.Equal([]FileSize{
+		{Path: "subdir/large", Size: 10},
+		{Path: "also_medium", Size: 5},
+		{Path: "medium", Size: 5},
+	}, bundler.LargestFiles(3))
+	s.Len(bundler.LargestFiles(10), 4)
+	s.Len(bundler.LargestFiles(0), 0)
+}
+
 func (s *BundlerSuite) TestMultipleCallsFromDirectory() {
 	// The bundler should be reusable for multiple
 	// passes over the bundle directory.
@@ -336,7 +668,7 @@ func (s *BundlerSuite) TestMultipleCallsFromDirectory() {
 	s.makeFile(filepath.Join("subdir", "testfile"))
 
 	log := logging.New()
-	bundler, err := NewBundler(s.cwd, NewManifest(), nil, log)
+	bundler, err := NewBundler(s.cwd, NewManifest(), nil, nil, util.SymlinkFollow, log)
 	s.Nil(err)
 
 	manifest, err := bundler.CreateManifest()
@@ -376,7 +708,7 @@ func (s *BundlerSuite) TestNewBundleFromDirectorySymlinks() {
 	dest := new(bytes.Buffer)
 	log := logging.New()
 
-	bundler, err := NewBundler(dirPath, NewManifest(), nil, log)
+	bundler, err := NewBundler(dirPath, NewManifest(), nil, nil, util.SymlinkFollow, log)
 	s.Nil(err)
 	manifest, err := bundler.CreateBundle(dest)
 	s.Nil(err)
@@ -396,6 +728,70 @@ func (s *BundlerSuite) TestNewBundleFromDirectorySymlinks() {
 	}, s.getTarFileNames(dest))
 }
 
+func (s *BundlerSuite) TestNewBundleFromDirectorySymlinksSkip() {
+	if runtime.GOOS == "windows" {
+		s.T().Skip()
+	}
+	fs := afero.NewOsFs()
+	dirPath := s.cwd.Join("testdata", "symlink_test", "bundle_dir").WithFs(fs)
+	dest := new(bytes.Buffer)
+	log := logging.New()
+
+	bundler, err := NewBundler(dirPath, NewManifest(), nil, nil, util.SymlinkSkip, log)
+	s.Nil(err)
+	manifest, err := bundler.CreateBundle(dest)
+	s.Nil(err)
+	s.NotNil(manifest)
+	s.Equal([]string{
+		"somefile",
+	}, manifest.GetFilenames())
+	s.Equal([]string{
+		"manifest.json",
+		"somefile",
+	}, s.getTarFileNames(dest))
+}
+
+func (s *BundlerSuite) TestNewBundleFromDirectorySymlinksError() {
+	if runtime.GOOS == "windows" {
+		s.T().Skip()
+	}
+	fs := afero.NewOsFs()
+	dirPath := s.cwd.Join("testdata", "symlink_test", "bundle_dir").WithFs(fs)
+	dest := new(bytes.Buffer)
+	log := logging.New()
+
+	bundler, err := NewBundler(dirPath, NewManifest(), nil, nil, util.SymlinkError, log)
+	s.Nil(err)
+	manifest, err := bundler.CreateBundle(dest)
+	s.ErrorIs(err, util.ErrSymlinkNotAllowed)
+	s.Nil(manifest)
+}
+
+func (s *BundlerSuite) TestNewBundleFromDirectorySymlinksContain() {
+	if runtime.GOOS == "windows" {
+		s.T().Skip()
+	}
+	// linked_dir and linked_file point outside of bundle_dir
+	fs := afero.NewOsFs()
+	dirPath := s.cwd.Join("testdata", "symlink_test", "bundle_dir").WithFs(fs)
+	dest := new(bytes.Buffer)
+	log := logging.New()
+
+	bundler, err := NewBundler(dirPath, NewManifest(), nil, nil, util.SymlinkContain, log)
+	s.Nil(err)
+	manifest, err := bundler.CreateBundle(dest)
+	s.ErrorIs(err, util.ErrSymlinkOutsideRoot)
+	s.ErrorContains(err, "linked_dir")
+	s.Nil(manifest)
+}
+
+func (s *BundlerSuite) TestNewBundlerBadSymlinkPolicy() {
+	log := logging.New()
+	bundler, err := NewBundler(s.cwd, NewManifest(), nil, nil, "bogus", log)
+	s.ErrorContains(err, "unknown symlink policy")
+	s.Nil(bundler)
+}
+
 // We log the issues with symbolic links but not return them to not polute the user with error notifications
 // when another piece of software is dealing with the same directory.
 func (s *BundlerSuite) TestNewBundleFromDirectoryMissingSymlinkTarget() {
@@ -406,9 +802,125 @@ func (s *BundlerSuite) TestNewBundleFromDirectoryMissingSymlinkTarget() {
 	dest := new(bytes.Buffer)
 	log := logging.New()
 
-	bundler, err := NewBundler(dirPath, NewManifest(), nil, log)
+	bundler, err := NewBundler(dirPath, NewManifest(), nil, nil, util.SymlinkFollow, log)
 	s.Nil(err)
 	manifest, err := bundler.CreateBundle(dest)
 	s.NoError(err)
 	s.NotNil(manifest)
 }
+
+func (s *BundlerSuite) TestNewBundleFromDirectorySymlinkCycle() {
+	if runtime.GOOS == "windows" {
+		s.T().Skip()
+	}
+	// afero's MemFs doesn't have symlink support.
+	fs := afero.NewOsFs()
+	dirPath := util.NewAbsolutePath(s.T().TempDir(), fs).Join("project")
+	err := dirPath.Join("subdir").MkdirAll(0700)
+	s.NoError(err)
+	err = dirPath.Join("app.py").WriteFile([]byte("import flask\n"), 0600)
+	s.NoError(err)
+	err = dirPath.Join("subdir", "data.csv").WriteFile([]byte("a,b\n"), 0600)
+	s.NoError(err)
+	err = os.Symlink("..", dirPath.Join("a").String())
+	s.NoError(err)
+	err = os.Symlink("..", dirPath.Join("subdir", "up").String())
+	s.NoError(err)
+
+	dest := new(bytes.Buffer)
+	bundler, err := NewBundler(dirPath, NewManifest(), nil, nil, util.SymlinkFollow, logging.New())
+	s.Nil(err)
+	manifest, err := bundler.CreateBundle(dest)
+	s.NoError(err)
+	s.Equal([]string{
+		"app.py",
+		"subdir/data.csv",
+	}, manifest.GetFilenames())
+	s.Equal([]string{
+		"app.py",
+		"manifest.json",
+		"subdir/",
+		"subdir/data.csv",
+	}, s.getTarFileNames(dest))
+}
+
+func (s *BundlerSuite) createBundleTwice(deterministic bool) ([]byte, []byte) {
+	s.makeFile("app.py")
+	s.makeFile(filepath.Join("subdir", "data.csv"))
+	s.makeFile(filepath.Join("subdir-2", "other.csv"))
+
+	bundler, err := NewBundler(s.cwd, NewManifest(), nil, []string{"empty"}, util.SymlinkFollow, logging.New())
+	s.Nil(err)
+	bundler.SetDeterministic(deterministic)
+	first := new(bytes.Buffer)
+	_, err = bundler.CreateBundle(first)
+	s.Nil(err)
+
+	// Touch the files, as a fresh checkout would.
+	later := time.Now().Add(time.Hour)
+	for _, name := range []string{"app.py", filepath.Join("subdir", "data.csv"), filepath.Join("subdir-2", "other.csv")} {
+		err = s.cwd.Join(name).Chtimes(later, later)
+		s.Nil(err)
+	}
+	second := new(bytes.Buffer)
+	_, err = bundler.CreateBundle(second)
+	s.Nil(err)
+	return first.Bytes(), second.Bytes()
+}
+
+func (s *BundlerSuite) TestCreateBundleDeterministic() {
+	first, second := s.createBundleTwice(true)
+	s.Equal(first, second)
+
+	// Entries are in order by path, and have no modification times.
+	unzipper, err := gzip.NewReader(bytes.NewReader(first))
+	s.NoError(err)
+	reader := tar.NewReader(unzipper)
+	names := []string{}
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		s.NoError(err)
+		names = append(names, header.Name)
+		if header.Name != "empty/" && header.Name != ManifestFilename {
+			s.Equal(int64(0), header.ModTime.Unix())
+		}
+	}
+	s.Equal([]string{
+		"app.py",
+		"subdir/",
+		"subdir-2/",
+		"subdir-2/other.csv",
+		"subdir/data.csv",
+		"empty/",
+		"manifest.json",
+	}, names)
+}
+
+func (s *BundlerSuite) TestCreateBundleNotDeterministic() {
+	// Without the option, modification times are kept.
+	first, second := s.createBundleTwice(false)
+	s.NotEqual(first, second)
+}
+
+func (s *BundlerSuite) TestCreateBundleExcludesQuartoOutputDir() {
+	// The Quarto detector excludes the output directory
+	// of source projects, since they're rendered on the server.
+	s.makeFile("_quarto.yml")
+	s.makeFile("index.qmd")
+	s.makeFile(filepath.Join("_site", "index.html"))
+	s.makeFile(filepath.Join("_site", "search.json"))
+
+	dest := new(bytes.Buffer)
+	patterns := []string{"*", "!/_site/"}
+	bundler, err := NewBundler(s.cwd, NewManifest(), patterns, nil, util.SymlinkFollow, logging.New())
+	s.Nil(err)
+	manifest, err := bundler.CreateBundle(dest)
+	s.Nil(err)
+	s.Equal([]string{
+		"_quarto.yml",
+		"index.qmd",
+	}, manifest.GetFilenames())
+}
@@ -0,0 +1,244 @@
+package bundles
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/util"
+)
+
+const bundleCacheSuffix = ".tar.gz"
+
+// GetBundleCacheDir returns the directory where
+// bundles for the project in `base` are cached.
+func GetBundleCacheDir(base util.AbsolutePath) util.AbsolutePath {
+	return base.Join(".posit", "publish", "cache")
+}
+
+// BundleCache keeps previously built bundles on disk, keyed by
+// the hash of their manifest. When the cache grows beyond its
+// maximum size, the least recently used bundles are removed.
+type BundleCache struct {
+	dir     util.AbsolutePath
+	maxSize int64 // bytes
+	log     logging.Logger
+}
+
+func NewBundleCache(dir util.AbsolutePath, maxSize int64, log logging.Logger) *BundleCache {
+	return &BundleCache{
+		dir:     dir,
+		maxSize: maxSize,
+		log:     log,
+	}
+}
+
+// BundleCacheKey returns the cache key for a bundle with the given
+// manifest and empty directories. The manifest includes a checksum
+// of every file, so any change to the bundle contents changes the key.
+func BundleCacheKey(manifest *Manifest, emptyDirs []string) (string, error) {
+	manifestJSON, err := manifest.ToJSON()
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.New()
+	hash.Write(manifestJSON)
+	for _, dir := range emptyDirs {
+		hash.Write([]byte{0})
+		hash.Write([]byte(dir))
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func (c *BundleCache) path(key string) util.AbsolutePath {
+	return c.dir.Join(key + bundleCacheSuffix)
+}
+
+// Get copies the cached bundle for key to dest.
+// It returns false if there is no such bundle.
+func (c *BundleCache) Get(key string, dest io.Writer) (bool, error) {
+	path := c.path(key)
+	f, err := path.Open()
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(dest, f)
+	if err != nil {
+		return false, err
+	}
+	// Record the use for LRU eviction.
+	now := time.Now()
+	err = path.Chtimes(now, now)
+	if err != nil {
+		c.log.Warn("Could not update bundle cache entry", "path", path, "error", err.Error())
+	}
+	return true, nil
+}
+
+// Put adds a bundle to the cache. createFn writes the bundle;
+// if it fails, nothing is added. A bundle larger than the cache's
+// maximum size is not kept. Otherwise, the least recently used
+// bundles are evicted until the cache fits its maximum size.
+func (c *BundleCache) Put(key string, createFn func(w io.Writer) error) error {
+	err := c.dir.MkdirAll(0777)
+	if err != nil {
+		return err
+	}
+	f, err := c.dir.TempFile("bundle-*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := util.NewAbsolutePath(f.Name(), c.dir.Fs())
+	err = createFn(f)
+	closeErr := f.Close()
+	if err == nil {
+		err = closeErr
+	}
+	var info fs.FileInfo
+	if err == nil {
+		info, err = tmpPath.Stat()
+	}
+	if err == nil && info.Size() > c.maxSize {
+		c.log.Debug("Bundle is larger than the cache; not caching it", "size", info.Size(), "max_size", c.maxSize)
+		return tmpPath.Remove()
+	}
+	if err == nil {
+		err = tmpPath.Rename(c.path(key).Path)
+	}
+	if err != nil {
+		tmpPath.Remove()
+		return err
+	}
+	return c.evict(key)
+}
+
+// evict removes the least recently used bundles, other than
+// the one for `keep`, until the cache fits its maximum size.
+func (c *BundleCache) evict(keep string) error {
+	infos, err := c.dir.ReadDir()
+	if err != nil {
+		return err
+	}
+	var entries []os.FileInfo
+	var total int64
+	for _, info := range infos {
+		if info.IsDir() || !strings.HasSuffix(info.Name(), bundleCacheSuffix) {
+			continue
+		}
+		total += info.Size()
+		if info.Name() != keep+bundleCacheSuffix {
+			entries = append(entries, info)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ModTime().Before(entries[j].ModTime())
+	})
+	for _, info := range entries {
+		if total <= c.maxSize {
+			break
+		}
+		path := c.dir.Join(info.Name())
+		c.log.Debug("Evicting bundle from cache", "path", path, "size", info.Size())
+		err = path.Remove()
+		if err != nil {
+			return err
+		}
+		total -= info.Size()
+	}
+	return nil
+}
+
+// cachingBundler wraps a Bundler, reusing a cached bundle
+// when the manifest is unchanged since a previous build.
+type cachingBundler struct {
+	Bundler
+	cache     *BundleCache
+	emptyDirs []string
+	log       logging.Logger
+}
+
+// NewCachingBundler returns a bundler that builds bundles with
+// `bundler`, reusing them from `cache` when possible. Since the
+// manifest is needed to compute the cache key, the project files
+// are still scanned and checksummed, but not archived.
+func NewCachingBundler(bundler Bundler, cache *BundleCache, emptyDirs []string, log logging.Logger) *cachingBundler {
+	return &cachingBundler{
+		Bundler:   bundler,
+		cache:     cache,
+		emptyDirs: emptyDirs,
+		log:       log,
+	}
+}
+
+func (b *cachingBundler) CreateBundle(archive io.Writer) (*Manifest, error) {
+	manifest, err := b.Bundler.CreateManifest()
+	if err != nil {
+		return nil, err
+	}
+	key, err := BundleCacheKey(manifest, b.emptyDirs)
+	if err != nil {
+		return nil, err
+	}
+	found, err := b.cache.Get(key, archive)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		b.log.Info("Using cached bundle", "key", key)
+		return manifest, nil
+	}
+	// Write the bundle to the archive while caching it, since it
+	// may not be kept if it's larger than the cache. The cache is
+	// only an optimization, so if it can't be written, the bundle
+	// is still written to the archive.
+	var bundleErr error
+	created := false
+	err = b.cache.Put(key, func(w io.Writer) error {
+		created = true
+		cacheWriter := &cacheWriter{w: w}
+		manifest, bundleErr = b.Bundler.CreateBundle(io.MultiWriter(cacheWriter, archive))
+		if bundleErr != nil {
+			return bundleErr
+		}
+		return cacheWriter.err
+	})
+	if bundleErr != nil {
+		return nil, bundleErr
+	}
+	if err != nil {
+		b.log.Warn("Could not cache the bundle", "key", key, "error", err.Error())
+		if !created {
+			return b.Bundler.CreateBundle(archive)
+		}
+	}
+	return manifest, nil
+}
+
+// cacheWriter writes to a cache entry. After a write fails, it
+// discards the rest of the data instead of returning the error,
+// so that writing to the other destinations can continue.
+type cacheWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (c *cacheWriter) Write(p []byte) (int, error) {
+	if c.err == nil {
+		_, c.err = c.w.Write(p)
+	}
+	return len(p), nil
+}
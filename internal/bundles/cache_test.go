@@ -0,0 +1,249 @@
+package bundles
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/util"
+	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/suite"
+)
+
+type BundleCacheSuite struct {
+	utiltest.Suite
+
+	fs  afero.Fs
+	cwd util.AbsolutePath
+	log logging.Logger
+}
+
+func TestBundleCacheSuite(t *testing.T) {
+	suite.Run(t, new(BundleCacheSuite))
+}
+
+func (s *BundleCacheSuite) SetupTest() {
+	s.fs = afero.NewMemMapFs()
+	cwd, err := util.Getwd(s.fs)
+	s.NoError(err)
+	s.cwd = cwd
+	s.cwd.MkdirAll(0700)
+	s.log = logging.New()
+}
+
+// countingBundler records how many bundles it has built.
+type countingBundler struct {
+	Bundler
+	bundles int
+}
+
+func (b *countingBundler) CreateBundle(archive io.Writer) (*Manifest, error) {
+	b.bundles++
+	return b.Bundler.CreateBundle(archive)
+}
+
+func (s *BundleCacheSuite) newBundler(cache *BundleCache) (*cachingBundler, *countingBundler) {
+	inner, err := NewBundler(s.cwd, NewManifest(), nil, nil, util.SymlinkFollow, s.log)
+	s.NoError(err)
+	counter := &countingBundler{Bundler: inner}
+	return NewCachingBundler(counter, cache, nil, s.log), counter
+}
+
+func (s *BundleCacheSuite) writeFile(name string, contents string) {
+	err := s.cwd.Join(name).WriteFile([]byte(contents), 0600)
+	s.NoError(err)
+}
+
+func (s *BundleCacheSuite) cachedFiles() []string {
+	infos, err := GetBundleCacheDir(s.cwd).ReadDir()
+	s.NoError(err)
+	names := []string{}
+	for _, info := range infos {
+		names = append(names, info.Name())
+	}
+	return names
+}
+
+func (s *BundleCacheSuite) TestCacheHitSkipsBundling() {
+	s.writeFile("app.py", "import flask\n")
+	cache := NewBundleCache(GetBundleCacheDir(s.cwd), 1024*1024, s.log)
+	bundler, counter := s.newBundler(cache)
+
+	first := new(bytes.Buffer)
+	manifest, err := bundler.CreateBundle(first)
+	s.NoError(err)
+	s.Equal([]string{"app.py"}, manifest.GetFilenames())
+	s.Equal(1, counter.bundles)
+	s.Len(s.cachedFiles(), 1)
+
+	// The cache directory itself is not part of the bundle,
+	// so the second pass has the same manifest.
+	second := new(bytes.Buffer)
+	manifest, err = bundler.CreateBundle(second)
+	s.NoError(err)
+	s.Equal([]string{"app.py"}, manifest.GetFilenames())
+	s.Equal(1, counter.bundles)
+	s.Equal(first.Bytes(), second.Bytes())
+	s.Len(s.cachedFiles(), 1)
+}
+
+func (s *BundleCacheSuite) TestChangeInvalidatesCache() {
+	s.writeFile("app.py", "import flask\n")
+	cache := NewBundleCache(GetBundleCacheDir(s.cwd), 1024*1024, s.log)
+	bundler, counter := s.newBundler(cache)
+
+	first := new(bytes.Buffer)
+	_, err := bundler.CreateBundle(first)
+	s.NoError(err)
+	s.Equal(1, counter.bundles)
+
+	s.writeFile("app.py", "import flask\napp = flask.Flask(__name__)\n")
+	second := new(bytes.Buffer)
+	_, err = bundler.CreateBundle(second)
+	s.NoError(err)
+	s.Equal(2, counter.bundles)
+	s.NotEqual(first.Bytes(), second.Bytes())
+	s.Len(s.cachedFiles(), 2)
+}
+
+func (s *BundleCacheSuite) TestEvictsLeastRecentlyUsed() {
+	dir := GetBundleCacheDir(s.cwd)
+	cache := NewBundleCache(dir, 25, s.log)
+	put := func(key string) {
+		err := cache.Put(key, func(w io.Writer) error {
+			_, err := w.Write([]byte("0123456789"))
+			return err
+		})
+		s.NoError(err)
+	}
+	put("a")
+	put("b")
+	past := time.Now().Add(-time.Hour)
+	s.NoError(dir.Join("a.tar.gz").Chtimes(past, past))
+	s.NoError(dir.Join("b.tar.gz").Chtimes(past.Add(time.Minute), past.Add(time.Minute)))
+
+	// Using "a" makes "b" the least recently used.
+	found, err := cache.Get("a", io.Discard)
+	s.NoError(err)
+	s.True(found)
+
+	put("c")
+	s.Equal([]string{"a.tar.gz", "c.tar.gz"}, s.cachedFiles())
+
+	found, err = cache.Get("b", io.Discard)
+	s.NoError(err)
+	s.False(found)
+}
+
+func (s *BundleCacheSuite) TestBundleLargerThanCache() {
+	s.writeFile("app.py", "import flask\n")
+	cache := NewBundleCache(GetBundleCacheDir(s.cwd), 10, s.log)
+	bundler, counter := s.newBundler(cache)
+
+	archive := new(bytes.Buffer)
+	manifest, err := bundler.CreateBundle(archive)
+	s.NoError(err)
+	s.Equal([]string{"app.py"}, manifest.GetFilenames())
+	s.Equal(1, counter.bundles)
+	s.Greater(archive.Len(), 10)
+	s.Len(s.cachedFiles(), 0)
+
+	// Since it wasn't cached, it's built again.
+	second := new(bytes.Buffer)
+	_, err = bundler.CreateBundle(second)
+	s.NoError(err)
+	s.Equal(2, counter.bundles)
+	s.Greater(second.Len(), 10)
+}
+
+func (s *BundleCacheSuite) TestPutKeepsNewEntry() {
+	dir := GetBundleCacheDir(s.cwd)
+	cache := NewBundleCache(dir, 15, s.log)
+	put := func(key string, content string) {
+		err := cache.Put(key, func(w io.Writer) error {
+			_, err := w.Write([]byte(content))
+			return err
+		})
+		s.NoError(err)
+	}
+	put("a", "0123456789")
+	future := time.Now().Add(time.Hour)
+	s.NoError(dir.Join("a.tar.gz").Chtimes(future, future))
+
+	// "a" looks newer than "b", but "b" was just added.
+	put("b", "0123456789")
+	s.Equal([]string{"b.tar.gz"}, s.cachedFiles())
+}
+
+func (s *BundleCacheSuite) TestPutError() {
+	cache := NewBundleCache(GetBundleCacheDir(s.cwd), 1024, s.log)
+	testError := errors.New("test error from createFn")
+	err := cache.Put("a", func(w io.Writer) error {
+		return testError
+	})
+	s.ErrorIs(err, testError)
+	s.Len(s.cachedFiles(), 0)
+}
+
+func (s *BundleCacheSuite) TestCacheNotWritable() {
+	s.writeFile("app.py", "import flask\n")
+	dir := GetBundleCacheDir(s.cwd).WithFs(afero.NewReadOnlyFs(s.fs))
+	cache := NewBundleCache(dir, 1024*1024, s.log)
+	bundler, counter := s.newBundler(cache)
+
+	archive := new(bytes.Buffer)
+	manifest, err := bundler.CreateBundle(archive)
+	s.NoError(err)
+	s.Equal([]string{"app.py"}, manifest.GetFilenames())
+	s.Equal(1, counter.bundles)
+	s.Greater(archive.Len(), 0)
+}
+
+// failingWriteFs is a filesystem where writes to files fail.
+type failingWriteFs struct {
+	afero.Fs
+}
+
+func (f failingWriteFs) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	file, err := f.Fs.OpenFile(name, flag, perm)
+	if err != nil {
+		return nil, err
+	}
+	return failingWriteFile{file}, nil
+}
+
+type failingWriteFile struct {
+	afero.File
+}
+
+func (f failingWriteFile) Write([]byte) (int, error) {
+	return 0, errors.New("test error: no space left on device")
+}
+
+func (s *BundleCacheSuite) TestCacheWriteFails() {
+	s.writeFile("app.py", "import flask\n")
+
+	uncached := new(bytes.Buffer)
+	bundler, err := NewBundler(s.cwd, NewManifest(), nil, nil, util.SymlinkFollow, s.log)
+	s.NoError(err)
+	bundler.SetDeterministic(true)
+	_, err = bundler.CreateBundle(uncached)
+	s.NoError(err)
+
+	dir := GetBundleCacheDir(s.cwd).WithFs(failingWriteFs{s.fs})
+	cache := NewBundleCache(dir, 1024*1024, s.log)
+	cachingBundler := NewCachingBundler(bundler, cache, nil, s.log)
+	archive := new(bytes.Buffer)
+	manifest, err := cachingBundler.CreateBundle(archive)
+	s.NoError(err)
+	s.Equal([]string{"app.py"}, manifest.GetFilenames())
+	s.Equal(uncached.Bytes(), archive.Bytes())
+	s.Len(s.cachedFiles(), 0)
+}
@@ -0,0 +1,60 @@
+package bundles
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"bytes"
+	"net/url"
+	"os/exec"
+	"strings"
+
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/util"
+)
+
+// gitOutput runs git in the specified directory and returns
+// its trimmed output. Failures (including git not being installed)
+// are logged at debug level and return an empty string.
+func gitOutput(dir util.AbsolutePath, log logging.Logger, args ...string) string {
+	cmd := exec.Command("git", append([]string{"-C", dir.String()}, args...)...)
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	err := cmd.Run()
+	if err != nil {
+		log.Debug("Error running git", "args", strings.Join(args, " "), "error", err.Error())
+		return ""
+	}
+	return strings.TrimSpace(stdout.String())
+}
+
+// redactURL removes any credentials from a git remote URL.
+// SCP-style URLs such as git@github.com:org/repo.git are returned as-is.
+func redactURL(remote string) string {
+	u, err := url.Parse(remote)
+	if err != nil || u.User == nil || u.Scheme == "" {
+		return remote
+	}
+	u.User = nil
+	return u.String()
+}
+
+// AddGitMetadata records the commit, branch, and origin URL of the git
+// checkout containing `dir` in the manifest metadata. If `dir` is not in
+// a git repository, or git is not available, the manifest is unchanged.
+func (m *Manifest) AddGitMetadata(dir util.AbsolutePath, log logging.Logger) {
+	commit := gitOutput(dir, log, "rev-parse", "--verify", "HEAD")
+	if commit == "" {
+		// Not a repository, or there are no commits yet.
+		return
+	}
+	m.Metadata.SourceCommit = commit
+
+	branch := gitOutput(dir, log, "rev-parse", "--abbrev-ref", "HEAD")
+	if branch != "HEAD" {
+		// A detached HEAD has no branch.
+		m.Metadata.SourceBranch = branch
+	}
+	remote := gitOutput(dir, log, "config", "--get", "remote.origin.url")
+	m.Metadata.SourceRepo = redactURL(remote)
+	log.Debug("Recorded git metadata", "commit", commit, "branch", m.Metadata.SourceBranch, "repo", m.Metadata.SourceRepo)
+}
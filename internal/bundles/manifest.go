@@ -4,10 +4,13 @@ package bundles
 
 import (
 	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
+	"path/filepath"
 	"sort"
 
 	"github.com/posit-dev/publisher/internal/clients/connect"
@@ -50,6 +53,9 @@ type Metadata struct {
 	PrimaryRmd      string          `json:"primary_rmd,omitempty"`      // The rendering target for Rmd deployments.
 	PrimaryHtml     string          `json:"primary_html,omitempty"`     // The default document for static deployments.
 	HasParameters   bool            `json:"has_parameters,omitempty"`   // True if this is content allows parameter customization.
+	SourceRepo      string          `json:"source_repo,omitempty"`      // URL of the git remote the content was deployed from.
+	SourceBranch    string          `json:"source_branch,omitempty"`    // Git branch the content was deployed from.
+	SourceCommit    string          `json:"source_commit,omitempty"`    // Git commit SHA the content was deployed from.
 }
 
 type Environment struct {
@@ -167,10 +173,16 @@ func NewManifestFromConfig(cfg *config.Config) *Manifest {
 		m.Platform = cfg.R.Version
 	}
 	if cfg.Python != nil {
+		packageManager := cfg.Python.PackageManager
+		if cfg.Python.ExportsRequirements() {
+			// Poetry and Pipenv projects deploy requirements exported
+			// from their lock files, which are installed with pip.
+			packageManager = "pip"
+		}
 		m.Python = &Python{
 			Version: cfg.Python.Version,
 			PackageManager: PythonPackageManager{
-				Name:        cfg.Python.PackageManager,
+				Name:        packageManager,
 				PackageFile: cfg.Python.PackageFile,
 			},
 		}
@@ -204,6 +216,58 @@ func (manifest *Manifest) AddFile(path string, fileMD5 []byte) {
 	}
 }
 
+// ChangedFiles recomputes the checksums of the files listed in the
+// manifest, relative to dir, and returns the sorted names of the files
+// whose contents no longer match. Files without a recorded checksum
+// are not checked.
+func (manifest *Manifest) ChangedFiles(dir util.AbsolutePath) ([]string, error) {
+	changed := []string{}
+	for _, name := range manifest.GetFilenames() {
+		expected := manifest.Files[name].Checksum
+		if expected == "" {
+			continue
+		}
+		if !filepath.IsLocal(filepath.FromSlash(name)) {
+			return nil, fmt.Errorf("manifest file paths must be relative paths within the project directory: '%s'", name)
+		}
+		checksum, err := fileChecksum(dir.Join(filepath.FromSlash(name)))
+		if err != nil {
+			return nil, err
+		}
+		if checksum != expected {
+			changed = append(changed, name)
+		}
+	}
+	return changed, nil
+}
+
+func fileChecksum(path util.AbsolutePath) (string, error) {
+	f, err := path.Open()
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	hash := md5.New()
+	_, err = io.Copy(hash, f)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// ContentHash returns a hash of the names and checksums of the files
+// in the manifest. It is the same for any manifest with the same files
+// and contents, regardless of the order they were added, so it can be
+// compared with an earlier deployment to see whether any files changed.
+// Other manifest fields, such as the metadata, are not included.
+func (manifest *Manifest) ContentHash() string {
+	hash := sha256.New()
+	for _, name := range manifest.GetFilenames() {
+		fmt.Fprintf(hash, "%s\x00%s\n", name, manifest.Files[name].Checksum)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
 func (manifest *Manifest) ToJSON() ([]byte, error) {
 	return json.MarshalIndent(manifest, "", "\t")
 }
@@ -4,6 +4,8 @@ package bundles
 
 import (
 	"bytes"
+	"crypto/md5"
+	"encoding/hex"
 	"os"
 	"strings"
 	"testing"
@@ -54,6 +56,73 @@ func (s *ManifestSuite) TestAddFile() {
 		"subdir/test.Rmd": ManifestFile{Checksum: "030405"},
 	})
 }
+
+func (s *ManifestSuite) TestContentHash() {
+	manifest := NewManifest()
+	manifest.AddFile("app.py", []byte{0x00, 0x01, 0x02})
+	manifest.AddFile("subdir/data.csv", []byte{0x03, 0x04, 0x05})
+	hash := manifest.ContentHash()
+	s.Len(hash, 64)
+
+	// The order files are added doesn't matter.
+	other := NewManifest()
+	other.AddFile("subdir/data.csv", []byte{0x03, 0x04, 0x05})
+	other.AddFile("app.py", []byte{0x00, 0x01, 0x02})
+	s.Equal(hash, other.ContentHash())
+
+	other.AddFile("app.py", []byte{0x00, 0x01, 0x03})
+	s.NotEqual(hash, other.ContentHash())
+
+	other = NewManifest()
+	other.AddFile("app.py", []byte{0x00, 0x01, 0x02})
+	s.NotEqual(hash, other.ContentHash())
+}
+
+func (s *ManifestSuite) TestChangedFiles() {
+	dir := util.NewAbsolutePath(s.cwd, s.fs)
+	s.NoError(dir.Join("app.py").WriteFile([]byte("import flask\n"), 0600))
+	s.NoError(dir.Join("data.csv").WriteFile([]byte("a,b\n1,2\n"), 0600))
+	s.NoError(dir.Join("unchecked.txt").WriteFile([]byte("anything"), 0600))
+
+	manifest := NewManifest()
+	manifest.Files["data.csv"] = ManifestFile{Checksum: md5Hex("a,b\n1,2\n")}
+	manifest.Files["unchecked.txt"] = ManifestFile{}
+	manifest.Files["app.py"] = ManifestFile{Checksum: md5Hex("import flask\n")}
+
+	changed, err := manifest.ChangedFiles(dir)
+	s.NoError(err)
+	s.Empty(changed)
+
+	// The contents changed after the manifest was written.
+	s.NoError(dir.Join("app.py").WriteFile([]byte("import dash\n"), 0600))
+	changed, err = manifest.ChangedFiles(dir)
+	s.NoError(err)
+	s.Equal([]string{"app.py"}, changed)
+}
+
+func (s *ManifestSuite) TestChangedFilesMissing() {
+	dir := util.NewAbsolutePath(s.cwd, s.fs)
+	manifest := NewManifest()
+	manifest.Files["missing.py"] = ManifestFile{Checksum: md5Hex("")}
+
+	_, err := manifest.ChangedFiles(dir)
+	s.ErrorIs(err, os.ErrNotExist)
+}
+
+func (s *ManifestSuite) TestChangedFilesOutsideProject() {
+	dir := util.NewAbsolutePath(s.cwd, s.fs)
+	manifest := NewManifest()
+	manifest.Files["../app.py"] = ManifestFile{Checksum: md5Hex("")}
+
+	_, err := manifest.ChangedFiles(dir)
+	s.ErrorContains(err, "must be relative paths within the project directory")
+}
+
+func md5Hex(contents string) string {
+	sum := md5.Sum([]byte(contents))
+	return hex.EncodeToString(sum[:])
+}
+
 func (s *ManifestSuite) TestReadManifest() {
 	manifestJson := `{"version": 1, "platform": "4.1.0"}`
 	reader := strings.NewReader(manifestJson)
@@ -162,6 +231,27 @@ func (s *ManifestSuite) TestNewManifestFromConfig() {
 	}, m)
 }
 
+func (s *ManifestSuite) TestNewManifestFromConfigPoetry() {
+	cfg := config.New()
+	cfg.Type = config.ContentTypePythonFlask
+	cfg.Entrypoint = "app.py"
+	cfg.Python = &config.Python{
+		Version:        "3.11.3",
+		PackageFile:    "requirements.txt",
+		PackageManager: "poetry",
+	}
+	m := NewManifestFromConfig(cfg)
+	// The exported requirements are installed with pip.
+	s.Equal(PythonPackageManager{
+		Name:        "pip",
+		PackageFile: "requirements.txt",
+	}, m.Python.PackageManager)
+
+	cfg.Python.PackageManager = "pipenv"
+	m = NewManifestFromConfig(cfg)
+	s.Equal("pip", m.Python.PackageManager.Name)
+}
+
 func (s *ManifestSuite) TestNewManifestFromConfigWithJupyterOptions() {
 	cfg := &config.Config{
 		Schema:        schema.ConfigSchemaURL,
@@ -0,0 +1,78 @@
+package bundles
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/posit-dev/publisher/internal/util"
+)
+
+// manifestWalker visits only the files listed in a manifest,
+// and the directories that contain them. Like the other walkers,
+// it doesn't follow symlinks; wrap it in a SymlinkWalker to
+// apply a symlink policy.
+type manifestWalker struct {
+	root      util.AbsolutePath
+	filenames []string // Posix paths relative to the root, sorted
+}
+
+func newManifestWalker(root util.AbsolutePath, filenames []string) *manifestWalker {
+	return &manifestWalker{
+		root:      root,
+		filenames: filenames,
+	}
+}
+
+// Walk visits the listed files that are within start, which is
+// the root directory or, when a SymlinkWalker follows a link,
+// a path under it.
+func (w *manifestWalker) Walk(start util.AbsolutePath, fn util.AbsoluteWalkFunc) error {
+	rel, err := start.Rel(w.root)
+	if err != nil {
+		return err
+	}
+	startName := filepath.ToSlash(rel.String())
+	// isDir records the visited paths that are directories.
+	// Symlinks aren't; files under a symlinked directory are
+	// visited by the SymlinkWalker, if its policy allows.
+	isDir := map[string]bool{}
+
+	var visit func(name string) (bool, error)
+	visit = func(name string) (bool, error) {
+		if dir, ok := isDir[name]; ok {
+			return dir, nil
+		}
+		if name != startName {
+			// Parents before children
+			dir, err := visit(path.Dir(name))
+			if err != nil || !dir {
+				return false, err
+			}
+		}
+		p := w.root.Join(filepath.FromSlash(name))
+		info, _, err := p.LstatIfPossible()
+		if errors.Is(err, fs.ErrNotExist) {
+			err = fmt.Errorf("%w: %s", ErrManifestFilesMissing, name)
+		}
+		isDir[name] = err == nil && info.IsDir()
+		return isDir[name], fn(p, info, err)
+	}
+
+	for _, name := range w.filenames {
+		name = path.Clean(name)
+		if startName != "." && name != startName && !strings.HasPrefix(name, startName+"/") {
+			continue
+		}
+		_, err := visit(name)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
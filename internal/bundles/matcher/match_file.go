@@ -198,6 +198,12 @@ func patternFromString(line string, base util.AbsolutePath, filePath util.Absolu
 
 	rawRegex = "^" + rawRegex + "$"
 
+	if util.CaseInsensitivePaths() {
+		// Match the way the filesystem does, so
+		// that deployments are consistent.
+		rawRegex = "(?i)" + rawRegex
+	}
+
 	// If tests fail, uncomment this line to get detailed output
 	// fmt.Printf("pattern %s is regex %s\n", line, rawRegex)
 
@@ -29,8 +29,15 @@ func (s *MatchSuite) SetupTest() {
 		path = `C:\project`
 	}
 	s.cwd = util.NewAbsolutePath(path, afero.NewMemMapFs())
+	util.CaseInsensitivePaths = func() bool { return false }
 }
 
+func (s *MatchSuite) TearDownTest() {
+	util.CaseInsensitivePaths = defaultCaseInsensitivePaths
+}
+
+var defaultCaseInsensitivePaths = util.CaseInsensitivePaths
+
 type testCase struct {
 	pattern  string
 	path     string
@@ -72,6 +79,19 @@ func (s *MatchSuite) TestSpecialCharsWindows() {
 	s.runTestCases(windowsSpecialCharTestCases)
 }
 
+func (s *MatchSuite) TestCaseSensitive() {
+	cases := make([]testCase, len(caseTestCases))
+	for i, test := range caseTestCases {
+		cases[i] = testCase{test.pattern, test.path, false, false}
+	}
+	s.runTestCases(cases)
+}
+
+func (s *MatchSuite) TestCaseInsensitive() {
+	util.CaseInsensitivePaths = func() bool { return true }
+	s.runTestCases(caseTestCases)
+}
+
 func (s *MatchSuite) runTestCases(cases []testCase) {
 	for _, test := range cases {
 		matchList, err := NewMatchList(s.cwd, strings.Split(test.pattern, "\n"))
@@ -105,6 +125,17 @@ func (s *MatchSuite) runTestCases(cases []testCase) {
 	}
 }
 
+// caseTestCases match only if paths are case-insensitive.
+var caseTestCases = []testCase{
+	{"*.html", "INDEX.HTML", true, false},
+	{"*.HTML", "index.html", true, false},
+	{"App.py", "dir/app.py", true, false},
+	{"/Dir/app.py", "dir/APP.py", true, false},
+	{"!node_modules/", "Node_Modules/", true, true},
+	{"!.DS_Store", ".ds_store", true, true},
+	{"!renv/library", "RENV/Library/", true, true},
+}
+
 var fileTestCases = []testCase{
 	{"app.py", "app.py", true, false},
 	{"app.py", "dir/app.py", true, false},
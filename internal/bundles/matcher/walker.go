@@ -44,6 +44,19 @@ var StandardExclusions = []string{
 
 	// node_modules shouldn't be deployed and can be very large
 	"!node_modules/",
+
+	// Previously built bundles
+	"!.posit/publish/cache/",
+}
+
+// BundleExclusions are excluded from bundles, in addition to the
+// StandardExclusions. They are not standard exclusions because
+// other walkers need to find configurations and deployment
+// records in .posit directories.
+var BundleExclusions = []string{
+	// Configurations and deployment records are for the
+	// publisher, not the deployed content.
+	"!.posit/",
 }
 
 // matchingWalker is a Walker that excludes files and directories
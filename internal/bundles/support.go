@@ -0,0 +1,102 @@
+package bundles
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"path"
+	"sort"
+	"time"
+)
+
+// SupportStructureFilename names the file in a support bundle
+// that lists the directories and files that would be deployed.
+const SupportStructureFilename = "structure.json"
+
+// SupportStructure describes the contents of a bundle
+// without including any file contents.
+type SupportStructure struct {
+	Directories []string   `json:"directories"` // Posix paths relative to the bundle root
+	Files       []FileSize `json:"files"`
+	TotalSize   int64      `json:"totalSize"` // Total size of the files, in bytes
+}
+
+// NewSupportStructure returns the structure of a bundle containing
+// `files`. Directories are derived from the file paths, plus
+// `emptyDirs`, which are included even if they contain no files.
+func NewSupportStructure(files []FileSize, emptyDirs []string) *SupportStructure {
+	s := &SupportStructure{
+		Directories: []string{},
+		Files:       make([]FileSize, len(files)),
+	}
+	copy(s.Files, files)
+	sort.Slice(s.Files, func(i, j int) bool {
+		return s.Files[i].Path < s.Files[j].Path
+	})
+	dirs := make(map[string]bool)
+	addParents := func(p string) {
+		for d := p; d != "." && d != "/" && d != ""; d = path.Dir(d) {
+			dirs[d] = true
+		}
+	}
+	for _, f := range s.Files {
+		s.TotalSize += f.Size
+		addParents(path.Dir(f.Path))
+	}
+	for _, d := range emptyDirs {
+		addParents(path.Clean(d))
+	}
+	for d := range dirs {
+		s.Directories = append(s.Directories, d)
+	}
+	sort.Strings(s.Directories)
+	return s
+}
+
+// WriteSupportBundle writes a gzipped tar archive for sharing with
+// support. It contains the manifest and the bundle structure, but
+// none of the project's files.
+func WriteSupportBundle(dest io.Writer, manifest *Manifest, structure *SupportStructure) error {
+	gzipper := gzip.NewWriter(dest)
+	archive := tar.NewWriter(gzipper)
+
+	manifestJSON, err := manifest.ToJSON()
+	if err != nil {
+		return err
+	}
+	structureJSON, err := json.MarshalIndent(structure, "", "\t")
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, f := range []struct {
+		name    string
+		content []byte
+	}{
+		{ManifestFilename, manifestJSON},
+		{SupportStructureFilename, structureJSON},
+	} {
+		header := &tar.Header{
+			Name:    f.name,
+			Size:    int64(len(f.content)),
+			Mode:    0666,
+			ModTime: now,
+		}
+		err = archive.WriteHeader(header)
+		if err != nil {
+			return err
+		}
+		_, err = archive.Write(f.content)
+		if err != nil {
+			return err
+		}
+	}
+	err = archive.Close()
+	if err != nil {
+		return err
+	}
+	return gzipper.Close()
+}
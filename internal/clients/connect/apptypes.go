@@ -253,6 +253,7 @@ var connectContentTypeMap = map[config.ContentType]AppMode{
 	config.ContentTypePythonDash:       PythonDashMode,
 	config.ContentTypePythonFastAPI:    PythonFastAPIMode,
 	config.ContentTypePythonFlask:      PythonAPIMode,
+	config.ContentTypePythonPanel:      PythonBokehMode, // Panel apps run on Connect's Bokeh runtime
 	config.ContentTypePythonShiny:      PythonShinyMode,
 	config.ContentTypePythonStreamlit:  PythonStreamlitMode,
 	config.ContentTypeQuartoShiny:      ShinyQuartoMode,
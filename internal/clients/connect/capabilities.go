@@ -5,6 +5,8 @@ package connect
 import (
 	"errors"
 	"fmt"
+	"os"
+	"strconv"
 	"strings"
 
 	"github.com/posit-dev/publisher/internal/clients/connect/server_settings"
@@ -65,7 +67,7 @@ func (c *ConnectClient) CheckCapabilities(base util.AbsolutePath, cfg *config.Co
 			return err
 		}
 	}
-	if cfg.Python != nil {
+	if cfg.Python != nil && !cfg.Python.ExportsRequirements() {
 		err := checkRequirementsFile(base, cfg)
 		if err != nil {
 			return err
@@ -75,7 +77,56 @@ func (c *ConnectClient) CheckCapabilities(base util.AbsolutePath, cfg *config.Co
 	if err != nil {
 		return err
 	}
-	return settings.checkConfig(cfg)
+	err = settings.checkConfig(cfg)
+	if err != nil {
+		return err
+	}
+	return c.checkRunAsUser(cfg, log)
+}
+
+// CheckRuntimes reports whether the Python, R, and Quarto versions
+// requested by the configuration are available on the server.
+func (c *ConnectClient) CheckRuntimes(base util.AbsolutePath, cfg *config.Config, log logging.Logger) (*RuntimeReport, error) {
+	settings, err := c.getSettings(base, cfg, log)
+	if err != nil {
+		return nil, err
+	}
+	return settings.runtimeReport(cfg), nil
+}
+
+// VerifyRunAsUserEnvVar names an environment variable that enables
+// checking that the run_as user exists on the server. It is a boolean
+// such as "true". This is optional because run_as names a Unix account,
+// which is only a server user if the server authenticates with PAM.
+const VerifyRunAsUserEnvVar = "POSIT_PUBLISHER_VERIFY_RUN_AS_USER"
+
+const runAsUserNotFoundCode types.ErrorCode = "runAsUserNotFound"
+
+type runAsUserNotFoundDetails struct {
+	RunAs string `mapstructure:"runAs"`
+}
+
+func verifyRunAsUser() bool {
+	verify, err := strconv.ParseBool(os.Getenv(VerifyRunAsUserEnvVar))
+	return err == nil && verify
+}
+
+// checkRunAsUser looks up the run_as user on the server, so that
+// a misspelled name is reported before deploying.
+func (c *ConnectClient) checkRunAsUser(cfg *config.Config, log logging.Logger) error {
+	if !verifyRunAsUser() || cfg.Connect == nil || cfg.Connect.Access == nil || cfg.Connect.Access.RunAs == "" {
+		return nil
+	}
+	runAs := cfg.Connect.Access.RunAs
+	user, err := c.LookupUser(runAs, log)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		err = fmt.Errorf("run_as user '%s' was not found on the server", runAs)
+		return types.NewAgentError(runAsUserNotFoundCode, err, runAsUserNotFoundDetails{RunAs: runAs})
+	}
+	return nil
 }
 
 func (c *ConnectClient) getSettings(base util.AbsolutePath, cfg *config.Config, log logging.Logger) (*allSettings, error) {
@@ -122,8 +173,72 @@ func (c *ConnectClient) getSettings(base util.AbsolutePath, cfg *config.Config,
 	return settings, nil
 }
 
+// GetSupportedContentTypes returns the content types that the server
+// is licensed and configured to run.
+func (c *ConnectClient) GetSupportedContentTypes(log logging.Logger) ([]config.ContentType, error) {
+	settings := &allSettings{}
+	err := c.client.Get("/__api__/server_settings", &settings.general, log)
+	if err != nil {
+		return nil, err
+	}
+	err = c.client.Get("/__api__/v1/server_settings/python", &settings.python, log)
+	if err != nil {
+		return nil, err
+	}
+	err = c.client.Get("/__api__/v1/server_settings/r", &settings.r, log)
+	if err != nil {
+		return nil, err
+	}
+	err = c.client.Get("/__api__/v1/server_settings/quarto", &settings.quarto, log)
+	if err != nil {
+		return nil, err
+	}
+	return settings.supportedContentTypes(), nil
+}
+
+// supportedContentTypes derives the content types the server can run
+// from its license and installed Python, R, and Quarto versions.
+func (a *allSettings) supportedContentTypes() []config.ContentType {
+	hasPython := len(a.python.Installations) != 0
+	hasR := len(a.r.Installations) != 0
+	hasQuarto := len(a.quarto.Installations) != 0
+	allowAPIs := bool(a.general.License.AllowAPIs)
+
+	supported := []config.ContentType{}
+	for _, name := range config.AllValidContentTypeNames() {
+		t := config.ContentType(name)
+		ok := false
+		switch t {
+		case config.ContentTypeHTML:
+			ok = true
+		case config.ContentTypeQuartoDeprecated:
+			// Listed as quarto-static instead.
+			ok = false
+		case config.ContentTypeQuarto:
+			ok = hasQuarto
+		case config.ContentTypeQuartoShiny:
+			ok = hasQuarto && (hasPython || hasR)
+		case config.ContentTypeRPlumber:
+			ok = hasR && allowAPIs
+		case config.ContentTypeRShiny, config.ContentTypeRMarkdown, config.ContentTypeRMarkdownShiny:
+			ok = hasR
+		default:
+			if t.IsAPIContent() {
+				ok = hasPython && allowAPIs && a.python.APIEnabled
+			} else {
+				ok = t.IsPythonContent() && hasPython
+			}
+		}
+		if ok {
+			supported = append(supported, t)
+		}
+	}
+	return supported
+}
+
 var (
 	errDescriptionTooLong                = errors.New("the description cannot be longer than 4096 characters")
+	errThumbnailTooLarge                 = errors.New("the thumbnail image is larger than the maximum size allowed by this Connect server")
 	errCurrentUserExecutionNotLicensed   = errors.New("run_as_current_user is not licensed on this Connect server")
 	errCurrentUserExecutionNotConfigured = errors.New("run_as_current_user is not configured on this Connect server")
 	errOnlyAppsCanRACU                   = errors.New("run_as_current_user can only be used with application types, not APIs or reports")
@@ -139,7 +254,11 @@ func adminError(attr string) error {
 }
 
 func majorMinorVersion(version string) string {
-	return strings.Join(strings.Split(version, ".")[:2], ".")
+	parts := strings.Split(version, ".")
+	if len(parts) < 2 {
+		return version
+	}
+	return strings.Join(parts[:2], ".")
 }
 
 type pythonNotAvailableErr struct {
@@ -147,28 +266,37 @@ type pythonNotAvailableErr struct {
 	Available []string
 }
 
-func newPythonNotAvailableErr(requested string, installations []server_settings.PyInstallation) *pythonNotAvailableErr {
-	available := make([]string, 0, len(installations))
+func pythonVersions(installations []server_settings.PyInstallation) []string {
+	versions := make([]string, 0, len(installations))
 	for _, inst := range installations {
-		available = append(available, inst.Version)
+		versions = append(versions, inst.Version)
 	}
+	return versions
+}
+
+func newPythonNotAvailableErr(requested string, installations []server_settings.PyInstallation) *pythonNotAvailableErr {
 	return &pythonNotAvailableErr{
 		Requested: requested,
-		Available: available,
+		Available: pythonVersions(installations),
 	}
 }
 
 const pythonNotAvailableCode types.ErrorCode = "pythonNotAvailable"
+const pythonNotInstalledMsg = `Python %s is not available on the server, which does not have Python installed.`
 const pythonNotAvailableMsgSingle = `Python %s is not available on the server.
 Consider editing your configuration to use version %s.`
 const pythonNotAvailableMsgMultiple = `Python %s is not available on the server.
 Consider editing your configuration to use one of the available versions: %s.`
 
 func (e *pythonNotAvailableErr) Error() string {
-	if len(e.Available) > 1 {
+	switch len(e.Available) {
+	case 0:
+		return fmt.Sprintf(pythonNotInstalledMsg, e.Requested)
+	case 1:
+		return fmt.Sprintf(pythonNotAvailableMsgSingle, e.Requested, e.Available[0])
+	default:
 		return fmt.Sprintf(pythonNotAvailableMsgMultiple, e.Requested, strings.Join(e.Available, ", "))
 	}
-	return fmt.Sprintf(pythonNotAvailableMsgSingle, e.Requested, e.Available[0])
 }
 
 func (a *allSettings) checkMatchingPython(version string) error {
@@ -186,6 +314,167 @@ func (a *allSettings) checkMatchingPython(version string) error {
 		newPythonNotAvailableErr(requested, a.python.Installations), nil)
 }
 
+type rNotAvailableErr struct {
+	Requested string   `mapstructure:"requested"`
+	Available []string `mapstructure:"available"`
+}
+
+func rVersions(installations []server_settings.RInstallation) []string {
+	versions := make([]string, 0, len(installations))
+	for _, inst := range installations {
+		versions = append(versions, inst.Version)
+	}
+	return versions
+}
+
+func newRNotAvailableErr(requested string, installations []server_settings.RInstallation) *rNotAvailableErr {
+	return &rNotAvailableErr{
+		Requested: requested,
+		Available: rVersions(installations),
+	}
+}
+
+const rNotAvailableCode types.ErrorCode = "rNotAvailable"
+const rNotInstalledMsg = `R %s is not available on the server, which does not have R installed.`
+const rNotAvailableMsgSingle = `R %s is not available on the server.
+Consider editing your configuration to use version %s.`
+const rNotAvailableMsgMultiple = `R %s is not available on the server.
+Consider editing your configuration to use one of the available versions: %s.`
+
+func (e *rNotAvailableErr) Error() string {
+	switch len(e.Available) {
+	case 0:
+		return fmt.Sprintf(rNotInstalledMsg, e.Requested)
+	case 1:
+		return fmt.Sprintf(rNotAvailableMsgSingle, e.Requested, e.Available[0])
+	default:
+		return fmt.Sprintf(rNotAvailableMsgMultiple, e.Requested, strings.Join(e.Available, ", "))
+	}
+}
+
+func (a *allSettings) checkMatchingR(version string) error {
+	if version == "" {
+		// This is prevented by version being mandatory in the schema.
+		return nil
+	}
+	requested := majorMinorVersion(version)
+	for _, inst := range a.r.Installations {
+		if majorMinorVersion(inst.Version) == requested {
+			return nil
+		}
+	}
+	err := newRNotAvailableErr(requested, a.r.Installations)
+	return types.NewAgentError(rNotAvailableCode, err, err)
+}
+
+type quartoNotAvailableErr struct {
+	Requested string   `mapstructure:"requested"`
+	Available []string `mapstructure:"available"`
+}
+
+func quartoVersions(installations []server_settings.QuartoInstallation) []string {
+	versions := make([]string, 0, len(installations))
+	for _, inst := range installations {
+		versions = append(versions, inst.Version)
+	}
+	return versions
+}
+
+func newQuartoNotAvailableErr(requested string, installations []server_settings.QuartoInstallation) *quartoNotAvailableErr {
+	return &quartoNotAvailableErr{
+		Requested: requested,
+		Available: quartoVersions(installations),
+	}
+}
+
+const quartoNotAvailableCode types.ErrorCode = "quartoNotAvailable"
+const quartoNotInstalledMsg = `Quarto %s is not available on the server, which does not have Quarto installed.`
+const quartoNotAvailableMsgSingle = `Quarto %s is not available on the server.
+Consider editing your configuration to use version %s.`
+const quartoNotAvailableMsgMultiple = `Quarto %s is not available on the server.
+Consider editing your configuration to use one of the available versions: %s.`
+
+func (e *quartoNotAvailableErr) Error() string {
+	switch len(e.Available) {
+	case 0:
+		return fmt.Sprintf(quartoNotInstalledMsg, e.Requested)
+	case 1:
+		return fmt.Sprintf(quartoNotAvailableMsgSingle, e.Requested, e.Available[0])
+	default:
+		return fmt.Sprintf(quartoNotAvailableMsgMultiple, e.Requested, strings.Join(e.Available, ", "))
+	}
+}
+
+func (a *allSettings) checkMatchingQuarto(version string) error {
+	if version == "" {
+		// Quarto wasn't found when the configuration was created.
+		return nil
+	}
+	requested := majorMinorVersion(version)
+	for _, inst := range a.quarto.Installations {
+		if majorMinorVersion(inst.Version) == requested {
+			return nil
+		}
+	}
+	err := newQuartoNotAvailableErr(requested, a.quarto.Installations)
+	return types.NewAgentError(quartoNotAvailableCode, err, err)
+}
+
+// RuntimeStatus compares the version of a runtime requested by
+// the configuration with the versions installed on the server.
+type RuntimeStatus struct {
+	Requested string   `json:"requested"` // major.minor
+	Available []string `json:"available"`
+	OK        bool     `json:"ok"`
+}
+
+// RuntimeReport describes the availability of each runtime
+// requested by the configuration. Runtimes that the configuration
+// doesn't use are omitted.
+type RuntimeReport struct {
+	Python *RuntimeStatus `json:"python,omitempty"`
+	R      *RuntimeStatus `json:"r,omitempty"`
+	Quarto *RuntimeStatus `json:"quarto,omitempty"`
+}
+
+// OK returns true if all of the requested runtimes are available.
+func (r *RuntimeReport) OK() bool {
+	for _, status := range []*RuntimeStatus{r.Python, r.R, r.Quarto} {
+		if status != nil && !status.OK {
+			return false
+		}
+	}
+	return true
+}
+
+// runtimeReport checks all of the runtimes at once, instead
+// of stopping at the first one that isn't available.
+func (a *allSettings) runtimeReport(cfg *config.Config) *RuntimeReport {
+	report := &RuntimeReport{}
+	if cfg.Python != nil && cfg.Python.Version != "" {
+		report.Python = &RuntimeStatus{
+			Requested: majorMinorVersion(cfg.Python.Version),
+			Available: pythonVersions(a.python.Installations),
+			OK:        a.checkMatchingPython(cfg.Python.Version) == nil,
+		}
+	}
+	if cfg.R != nil && cfg.R.Version != "" {
+		report.R = &RuntimeStatus{
+			Requested: majorMinorVersion(cfg.R.Version),
+			Available: rVersions(a.r.Installations),
+			OK:        a.checkMatchingR(cfg.R.Version) == nil,
+		}
+	}
+	if cfg.Quarto != nil && cfg.Quarto.Version != "" {
+		report.Quarto = &RuntimeStatus{
+			Requested: majorMinorVersion(cfg.Quarto.Version),
+			Available: quartoVersions(a.quarto.Installations),
+			OK:        a.checkMatchingQuarto(cfg.Quarto.Version) == nil,
+		}
+	}
+	return report
+}
+
 func (a *allSettings) checkKubernetes(cfg *config.Config) error {
 	k := cfg.Connect.Kubernetes
 	if k == nil {
@@ -293,6 +582,54 @@ func (a *allSettings) checkAccess(cfg *config.Config) error {
 	if cfg.Connect.Access.RunAs != "" && !a.user.CanAdmin() {
 		return adminError("run_as")
 	}
+	return a.checkInitialAccessType(cfg.Connect.Access.InitialAccessType)
+}
+
+func (a *allSettings) checkInitialAccessType(accessType config.AccessType) error {
+	if accessType == "" {
+		return nil
+	}
+	if !accessType.IsValid() {
+		return fmt.Errorf("initial_access_type must be acl, logged_in, or all, not %s", accessType)
+	}
+	allowed := a.application.AccessTypes
+	if len(allowed) == 0 {
+		// Older servers don't report which access types they allow.
+		return nil
+	}
+	for _, t := range allowed {
+		if t == string(accessType) {
+			return nil
+		}
+	}
+	return fmt.Errorf("initial_access_type %s is not allowed on this Connect server; allowed values are %s",
+		accessType, strings.Join(allowed, ", "))
+}
+
+// Paths used by Connect itself can't be vanity paths.
+var reservedVanityPrefixes = []string{"/__", "/connect/", "/content/"}
+
+func checkVanityURL(vanityURL string) error {
+	if vanityURL == "" {
+		return nil
+	}
+	if !strings.HasPrefix(vanityURL, "/") {
+		return fmt.Errorf("vanity_url must start with /, not %s", vanityURL)
+	}
+	normalized := strings.TrimSuffix(vanityURL, "/") + "/"
+	if normalized == "/" {
+		return errors.New("vanity_url cannot be /")
+	}
+	for _, segment := range strings.Split(strings.Trim(vanityURL, "/"), "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return fmt.Errorf("vanity_url %s is not a valid path", vanityURL)
+		}
+	}
+	for _, prefix := range reservedVanityPrefixes {
+		if strings.HasPrefix(normalized, prefix) {
+			return fmt.Errorf("vanity_url %s is reserved by Connect", vanityURL)
+		}
+	}
 	return nil
 }
 
@@ -311,6 +648,25 @@ func (a *allSettings) checkFileExists(filename string, attr string) error {
 	return nil
 }
 
+func (a *allSettings) checkThumbnail(filename string) error {
+	if filename == "" {
+		return nil
+	}
+	err := a.checkFileExists(filename, "thumbnail")
+	if err != nil {
+		return err
+	}
+	info, err := a.base.Join(filename).Stat()
+	if err != nil {
+		return err
+	}
+	limit := a.general.MaximumAppImageSize
+	if limit > 0 && info.Size() > limit {
+		return fmt.Errorf("%w (%s is %d bytes; the limit is %d bytes)", errThumbnailTooLarge, filename, info.Size(), limit)
+	}
+	return nil
+}
+
 func (a *allSettings) checkConfig(cfg *config.Config) error {
 	var err error
 	if cfg.Type.IsAPIContent() {
@@ -321,25 +677,44 @@ func (a *allSettings) checkConfig(cfg *config.Config) error {
 	if len(cfg.Description) > 4096 {
 		return errDescriptionTooLong
 	}
-	// we don't upload thumbnails yet, but when we do, we will check MaximumAppImageSize
+	err = a.checkThumbnail(cfg.ThumbnailFile)
+	if err != nil {
+		return err
+	}
 
 	if cfg.Python != nil {
 		err = a.checkMatchingPython(cfg.Python.Version)
 		if err != nil {
 			return err
 		}
-		err = a.checkFileExists(cfg.Python.PackageFile, "python.package-file")
+		if !cfg.Python.ExportsRequirements() {
+			err = a.checkFileExists(cfg.Python.PackageFile, "python.package-file")
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if cfg.Quarto != nil {
+		err = a.checkMatchingQuarto(cfg.Quarto.Version)
 		if err != nil {
 			return err
 		}
 	}
 	if cfg.R != nil {
+		err = a.checkMatchingR(cfg.R.Version)
+		if err != nil {
+			return err
+		}
 		err = a.checkFileExists(cfg.R.PackageFile, "r.package-file")
 		if err != nil {
 			return err
 		}
 	}
 	if cfg.Connect != nil {
+		err = checkVanityURL(cfg.Connect.VanityURL)
+		if err != nil {
+			return err
+		}
 		err = a.checkAccess(cfg)
 		if err != nil {
 			return err
@@ -8,7 +8,10 @@ import (
 
 	"github.com/posit-dev/publisher/internal/clients/connect/server_settings"
 	"github.com/posit-dev/publisher/internal/config"
+	"github.com/posit-dev/publisher/internal/types"
+	"github.com/posit-dev/publisher/internal/util"
 	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/spf13/afero"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -50,6 +53,179 @@ func (s *CapabilitiesSuite) TestCheckMatchingPython() {
 	s.ErrorContains(err, "Python 3.9 is not available on the server")
 }
 
+func (s *CapabilitiesSuite) TestCheckPythonPackageFile() {
+	base := util.NewAbsolutePath("/project", afero.NewMemMapFs())
+	a := allSettings{
+		base: base,
+		python: server_settings.PyInfo{
+			Installations: []server_settings.PyInstallation{{Version: "3.11.2"}},
+		},
+	}
+	cfg := makePythonConfig("3.11.2")
+	cfg.Python.PackageFile = "requirements.txt"
+	cfg.Python.PackageManager = "pip"
+	err := a.checkConfig(cfg)
+	s.ErrorContains(err, "the file requirements.txt specified in python.package-file does not exist")
+
+	// Exported requirements are added to the bundle when publishing.
+	cfg.Python.PackageManager = "poetry"
+	s.NoError(a.checkConfig(cfg))
+}
+
+func makeRConfig(version string) *config.Config {
+	return &config.Config{
+		R: &config.R{
+			Version: version,
+		},
+	}
+}
+
+func (s *CapabilitiesSuite) TestCheckMatchingR() {
+	a := allSettings{
+		r: server_settings.RInfo{
+			Installations: []server_settings.RInstallation{
+				{Version: "4.2.3"},
+				{Version: "4.3.1"},
+			},
+		},
+	}
+	s.NoError(a.checkConfig(makeRConfig("4.2.3")))
+	s.NoError(a.checkConfig(makeRConfig("4.3.2")))
+	err := a.checkConfig(makeRConfig("4.4.0"))
+	s.NotNil(err)
+	s.ErrorContains(err, "R 4.4 is not available on the server")
+	s.ErrorContains(err, "one of the available versions: 4.2.3, 4.3.1")
+	agentErr, ok := types.IsAgentError(err)
+	s.True(ok)
+	s.Equal(rNotAvailableCode, agentErr.Code)
+	s.Equal("4.4", agentErr.Data["requested"])
+	s.Equal([]string{"4.2.3", "4.3.1"}, agentErr.Data["available"])
+}
+
+func (s *CapabilitiesSuite) TestCheckMatchingRSingle() {
+	a := allSettings{
+		r: server_settings.RInfo{
+			Installations: []server_settings.RInstallation{
+				{Version: "4.3.1"},
+			},
+		},
+	}
+	err := a.checkConfig(makeRConfig("4.2.3"))
+	s.ErrorContains(err, "Consider editing your configuration to use version 4.3.1")
+}
+
+func (s *CapabilitiesSuite) TestCheckMatchingRNotInstalled() {
+	a := allSettings{}
+	err := a.checkConfig(makeRConfig("4.3.1"))
+	s.ErrorContains(err, "R 4.3 is not available on the server, which does not have R installed")
+}
+
+func makeQuartoConfig(version string) *config.Config {
+	return &config.Config{
+		Quarto: &config.Quarto{
+			Version: version,
+		},
+	}
+}
+
+func (s *CapabilitiesSuite) TestCheckMatchingQuarto() {
+	a := allSettings{
+		quarto: server_settings.QuartoInfo{
+			Installations: []server_settings.QuartoInstallation{
+				{Version: "1.3.450"},
+				{Version: "1.4.557"},
+			},
+		},
+	}
+	s.NoError(a.checkConfig(makeQuartoConfig("1.4.550")))
+	s.NoError(a.checkConfig(makeQuartoConfig("1.3")))
+	s.NoError(a.checkConfig(makeQuartoConfig("")))
+
+	err := a.checkConfig(makeQuartoConfig("1.5.57"))
+	s.ErrorContains(err, "Quarto 1.5 is not available on the server")
+	s.ErrorContains(err, "one of the available versions: 1.3.450, 1.4.557")
+	agentErr, ok := types.IsAgentError(err)
+	s.True(ok)
+	s.Equal(quartoNotAvailableCode, agentErr.Code)
+	s.Equal("1.5", agentErr.Data["requested"])
+	s.Equal([]string{"1.3.450", "1.4.557"}, agentErr.Data["available"])
+}
+
+func (s *CapabilitiesSuite) TestCheckMatchingQuartoNotInstalled() {
+	a := allSettings{}
+	err := a.checkConfig(makeQuartoConfig("1.4.557"))
+	s.ErrorContains(err, "Quarto 1.4 is not available on the server, which does not have Quarto installed")
+}
+
+func (s *CapabilitiesSuite) TestRuntimeReport() {
+	a := allSettings{
+		python: server_settings.PyInfo{
+			Installations: []server_settings.PyInstallation{
+				{Version: "3.10.1"},
+				{Version: "3.11.2"},
+			},
+		},
+		r: server_settings.RInfo{
+			Installations: []server_settings.RInstallation{
+				{Version: "4.3.1"},
+			},
+		},
+	}
+	cfg := &config.Config{
+		Python: &config.Python{Version: "3.9.1"},
+		R:      &config.R{Version: "4.3.2"},
+		Quarto: &config.Quarto{Version: "1.4.557"},
+	}
+	// checkConfig stops at the first problem...
+	s.ErrorContains(a.checkConfig(cfg), "Python 3.9 is not available")
+
+	// ...but the report includes all of them.
+	report := a.runtimeReport(cfg)
+	s.False(report.OK())
+	s.Equal(&RuntimeReport{
+		Python: &RuntimeStatus{
+			Requested: "3.9",
+			Available: []string{"3.10.1", "3.11.2"},
+			OK:        false,
+		},
+		R: &RuntimeStatus{
+			Requested: "4.3",
+			Available: []string{"4.3.1"},
+			OK:        true,
+		},
+		Quarto: &RuntimeStatus{
+			Requested: "1.4",
+			Available: []string{},
+			OK:        false,
+		},
+	}, report)
+}
+
+func (s *CapabilitiesSuite) TestRuntimeReportNoPython() {
+	a := allSettings{}
+	report := a.runtimeReport(makePythonConfig("3.11.1"))
+	s.False(report.OK())
+	s.Equal([]string{}, report.Python.Available)
+}
+
+func (s *CapabilitiesSuite) TestRuntimeReportAllAvailable() {
+	a := allSettings{
+		python: server_settings.PyInfo{
+			Installations: []server_settings.PyInstallation{
+				{Version: "3.11.2"},
+			},
+		},
+	}
+	report := a.runtimeReport(makePythonConfig("3.11.1"))
+	s.True(report.OK())
+	s.Nil(report.R)
+	s.Nil(report.Quarto)
+
+	report = a.runtimeReport(&config.Config{})
+	s.True(report.OK())
+	s.Equal(&RuntimeReport{}, report)
+}
+
 func makeMinMaxProcs(min, max int32) *config.Config {
 	return &config.Config{
 		Type: config.ContentTypePythonShiny,
@@ -154,6 +330,60 @@ func (s *CapabilitiesSuite) TestRunAsCurrentUser() {
 	s.ErrorIs(goodSettings.checkConfig(&notAnApp), errOnlyAppsCanRACU)
 }
 
+func (s *CapabilitiesSuite) TestInitialAccessType() {
+	a := allSettings{
+		application: server_settings.ApplicationSettings{
+			AccessTypes: []string{"acl", "logged_in", "all"},
+		},
+	}
+	makeConfig := func(accessType config.AccessType) *config.Config {
+		return &config.Config{
+			Type: config.ContentTypePythonDash,
+			Connect: &config.Connect{
+				Access: &config.ConnectAccess{
+					InitialAccessType: accessType,
+				},
+			},
+		}
+	}
+	s.NoError(a.checkConfig(makeConfig("")))
+	s.NoError(a.checkConfig(makeConfig(config.AccessTypeACL)))
+	s.NoError(a.checkConfig(makeConfig(config.AccessTypeLoggedIn)))
+	s.NoError(a.checkConfig(makeConfig(config.AccessTypeAnonymous)))
+	s.ErrorContains(a.checkConfig(makeConfig("everyone")), "initial_access_type must be acl, logged_in, or all, not everyone")
+
+	noAnonymous := a
+	noAnonymous.application.AccessTypes = []string{"acl", "logged_in"}
+	s.NoError(noAnonymous.checkConfig(makeConfig(config.AccessTypeLoggedIn)))
+	s.ErrorContains(noAnonymous.checkConfig(makeConfig(config.AccessTypeAnonymous)),
+		"initial_access_type all is not allowed on this Connect server; allowed values are acl, logged_in")
+
+	// Servers that don't report the allowed types
+	unknown := allSettings{}
+	s.NoError(unknown.checkConfig(makeConfig(config.AccessTypeAnonymous)))
+}
+
+func (s *CapabilitiesSuite) TestVanityURL() {
+	a := allSettings{}
+	makeConfig := func(vanityURL string) *config.Config {
+		return &config.Config{
+			Type: config.ContentTypePythonDash,
+			Connect: &config.Connect{
+				VanityURL: vanityURL,
+			},
+		}
+	}
+	s.NoError(a.checkConfig(makeConfig("")))
+	s.NoError(a.checkConfig(makeConfig("/sales/dashboard/")))
+	s.NoError(a.checkConfig(makeConfig("/sales")))
+	s.ErrorContains(a.checkConfig(makeConfig("sales/")), "vanity_url must start with /, not sales/")
+	s.ErrorContains(a.checkConfig(makeConfig("/")), "vanity_url cannot be /")
+	s.ErrorContains(a.checkConfig(makeConfig("/sales//dashboard/")), "vanity_url /sales//dashboard/ is not a valid path")
+	s.ErrorContains(a.checkConfig(makeConfig("/sales/../admin/")), "vanity_url /sales/../admin/ is not a valid path")
+	s.ErrorContains(a.checkConfig(makeConfig("/__api__/")), "vanity_url /__api__/ is reserved by Connect")
+	s.ErrorContains(a.checkConfig(makeConfig("/connect")), "vanity_url /connect is reserved by Connect")
+}
+
 func (s *CapabilitiesSuite) TestAPILicense() {
 	allowed := allSettings{
 		general: server_settings.ServerSettings{
@@ -329,3 +559,141 @@ func (s *CapabilitiesSuite) TestKubernetesGPULimits() {
 	s.ErrorContains(a.checkConfig(makeGPURequest(5, 0)), "amd_gpu_limit value of 5 is higher than configured maximum of 1 on this server")
 	s.ErrorContains(a.checkConfig(makeGPURequest(0, 5)), "nvidia_gpu_limit value of 5 is higher than configured maximum of 2 on this server")
 }
+
+func (s *CapabilitiesSuite) TestCheckThumbnail() {
+	base := util.NewAbsolutePath("/project", afero.NewMemMapFs())
+	err := base.MkdirAll(0777)
+	s.NoError(err)
+	err = base.Join("thumbnail.png").WriteFile(make([]byte, 1000), 0666)
+	s.NoError(err)
+
+	a := allSettings{
+		base: base,
+		general: server_settings.ServerSettings{
+			MaximumAppImageSize: 1000,
+		},
+	}
+	cfg := &config.Config{ThumbnailFile: "thumbnail.png"}
+	s.NoError(a.checkConfig(cfg))
+
+	a.general.MaximumAppImageSize = 999
+	err = a.checkConfig(cfg)
+	s.ErrorIs(err, errThumbnailTooLarge)
+
+	// No limit
+	a.general.MaximumAppImageSize = 0
+	s.NoError(a.checkConfig(cfg))
+}
+
+func (s *CapabilitiesSuite) TestCheckThumbnailMissing() {
+	base := util.NewAbsolutePath("/project", afero.NewMemMapFs())
+	a := allSettings{
+		base: base,
+	}
+	cfg := &config.Config{ThumbnailFile: "thumbnail.png"}
+	err := a.checkConfig(cfg)
+	s.ErrorContains(err, "the file thumbnail.png specified in thumbnail does not exist")
+}
+
+func (s *CapabilitiesSuite) TestSupportedContentTypesNothingInstalled() {
+	a := allSettings{}
+	s.Equal([]config.ContentType{
+		config.ContentTypeHTML,
+	}, a.supportedContentTypes())
+}
+
+func (s *CapabilitiesSuite) TestSupportedContentTypesPythonNoAPIs() {
+	a := allSettings{
+		python: server_settings.PyInfo{
+			Installations: []server_settings.PyInstallation{{Version: "3.11.2"}},
+			APIEnabled:    true,
+		},
+	}
+	s.Equal([]config.ContentType{
+		config.ContentTypeHTML,
+		config.ContentTypeJupyterNotebook,
+		config.ContentTypeJupyterVoila,
+		config.ContentTypePythonBokeh,
+		config.ContentTypePythonDash,
+		config.ContentTypePythonPanel,
+		config.ContentTypePythonShiny,
+		config.ContentTypePythonStreamlit,
+	}, a.supportedContentTypes())
+}
+
+func (s *CapabilitiesSuite) TestSupportedContentTypesAll() {
+	a := allSettings{
+		general: server_settings.ServerSettings{
+			License: server_settings.LicenseStatus{
+				AllowAPIs: true,
+			},
+		},
+		python: server_settings.PyInfo{
+			Installations: []server_settings.PyInstallation{{Version: "3.11.2"}},
+			APIEnabled:    true,
+		},
+		r: server_settings.RInfo{
+			Installations: []server_settings.RInstallation{{Version: "4.3.1"}},
+		},
+		quarto: server_settings.QuartoInfo{
+			Installations: []server_settings.QuartoInstallation{{Version: "1.4.554"}},
+		},
+	}
+	expected := []config.ContentType{}
+	for _, name := range config.AllValidContentTypeNames() {
+		if name != string(config.ContentTypeQuartoDeprecated) {
+			expected = append(expected, config.ContentType(name))
+		}
+	}
+	s.Equal(expected, a.supportedContentTypes())
+}
+
+func (s *CapabilitiesSuite) TestSupportedContentTypesRAndQuarto() {
+	a := allSettings{
+		general: server_settings.ServerSettings{
+			License: server_settings.LicenseStatus{
+				AllowAPIs: true,
+			},
+		},
+		r: server_settings.RInfo{
+			Installations: []server_settings.RInstallation{{Version: "4.3.1"}},
+		},
+		quarto: server_settings.QuartoInfo{
+			Installations: []server_settings.QuartoInstallation{{Version: "1.4.554"}},
+		},
+	}
+	s.Equal([]config.ContentType{
+		config.ContentTypeHTML,
+		config.ContentTypeQuartoShiny,
+		config.ContentTypeQuarto,
+		config.ContentTypeRPlumber,
+		config.ContentTypeRShiny,
+		config.ContentTypeRMarkdownShiny,
+		config.ContentTypeRMarkdown,
+	}, a.supportedContentTypes())
+
+	// Quarto alone can only render static documents
+	a.r.Installations = nil
+	s.Equal([]config.ContentType{
+		config.ContentTypeHTML,
+		config.ContentTypeQuarto,
+	}, a.supportedContentTypes())
+}
+
+func (s *CapabilitiesSuite) TestSupportedContentTypesPythonAPIsDisabled() {
+	a := allSettings{
+		general: server_settings.ServerSettings{
+			License: server_settings.LicenseStatus{
+				AllowAPIs: true,
+			},
+		},
+		python: server_settings.PyInfo{
+			Installations: []server_settings.PyInstallation{{Version: "3.11.2"}},
+			APIEnabled:    false,
+		},
+	}
+	supported := a.supportedContentTypes()
+	s.NotContains(supported, config.ContentTypePythonFastAPI)
+	s.NotContains(supported, config.ContentTypePythonFlask)
+	s.Contains(supported, config.ContentTypePythonDash)
+}
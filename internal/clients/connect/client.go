@@ -3,7 +3,9 @@ package connect
 // Copyright (C) 2023 by Posit Software, PBC.
 
 import (
+	"context"
 	"io"
+	"time"
 
 	"github.com/posit-dev/publisher/internal/config"
 	"github.com/posit-dev/publisher/internal/logging"
@@ -20,16 +22,39 @@ type User struct {
 	Email     string       `json:"email"`
 }
 
+// LogEntry is a line of output from a process
+// running the content on the server.
+type LogEntry struct {
+	Source    string     `json:"source"` // stdout or stderr
+	Timestamp types.Time `json:"timestamp"`
+	Data      string     `json:"data"`
+}
+
+// TaskProgressFunc is called when more of a task's output has been
+// received. The position can be saved and passed to WaitForTask
+// to resume from there.
+type TaskProgressFunc func(position types.TaskPosition)
+
 type APIClient interface {
 	TestAuthentication(logging.Logger) (*User, error)
 	ContentDetails(contentID types.ContentID, body *ConnectContent, log logging.Logger) error
 	CreateDeployment(*ConnectContent, logging.Logger) (types.ContentID, error)
 	UpdateDeployment(types.ContentID, *ConnectContent, logging.Logger) error
+	DeleteContent(contentID types.ContentID, log logging.Logger) error
 	GetEnvVars(types.ContentID, logging.Logger) (*types.Environment, error)
+	GetContentLogs(contentID types.ContentID, since time.Time, log logging.Logger) ([]LogEntry, error)
 	SetEnvVars(types.ContentID, config.Environment, logging.Logger) error
+	DeleteEnvVars(contentID types.ContentID, names []string, log logging.Logger) error
 	UploadBundle(types.ContentID, io.Reader, logging.Logger) (types.BundleID, error)
+	SetThumbnail(contentID types.ContentID, image io.Reader, imageType string, log logging.Logger) error
+	SetVanityURL(contentID types.ContentID, path string, log logging.Logger) error
 	DeployBundle(types.ContentID, types.BundleID, logging.Logger) (types.TaskID, error)
-	WaitForTask(taskID types.TaskID, log logging.Logger) error
+	WaitForTask(position types.TaskPosition, onProgress TaskProgressFunc, log logging.Logger) error
 	ValidateDeployment(types.ContentID, logging.Logger) error
 	CheckCapabilities(util.AbsolutePath, *config.Config, *types.ContentID, logging.Logger) error
+	CheckRuntimes(util.AbsolutePath, *config.Config, logging.Logger) (*RuntimeReport, error)
+	GetSupportedContentTypes(logging.Logger) ([]config.ContentType, error)
+	// WithContext returns a client that stops
+	// making requests when ctx is done.
+	WithContext(ctx context.Context) APIClient
 }
@@ -3,7 +3,7 @@ package connect
 // Copyright (C) 2023 by Posit Software, PBC.
 
 import (
-	"crypto/tls"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -11,7 +11,10 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"os"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -27,6 +30,7 @@ type ConnectClient struct {
 	client  http_client.HTTPClient
 	account *accounts.Account
 	emitter events.Emitter
+	ctx     context.Context
 }
 
 func NewConnectClient(
@@ -35,7 +39,7 @@ func NewConnectClient(
 	emitter events.Emitter,
 	log logging.Logger) (APIClient, error) {
 
-	httpClient, err := http_client.NewDefaultHTTPClient(account, timeout, log)
+	httpClient, err := http_client.NewDefaultHTTPClient(account, timeout, compressRequests(log), log)
 	if err != nil {
 		return nil, err
 	}
@@ -43,9 +47,21 @@ func NewConnectClient(
 		client:  httpClient,
 		account: account,
 		emitter: emitter,
+		ctx:     context.Background(),
 	}, nil
 }
 
+// WithContext returns a client whose requests, and waits
+// between them, stop when ctx is done.
+func (c *ConnectClient) WithContext(ctx context.Context) APIClient {
+	return &ConnectClient{
+		client:  c.client.WithContext(ctx),
+		account: c.account,
+		emitter: c.emitter,
+		ctx:     ctx,
+	}
+}
+
 type UserDTO struct {
 	Email       string         `json:"email"`
 	Username    string         `json:"username"`
@@ -86,6 +102,28 @@ func (u *UserDTO) CanPublish() bool {
 	return u.UserRole == AuthRoleAdmin || u.UserRole == AuthRolePublisher
 }
 
+type usersDTO struct {
+	Results []UserDTO `json:"results"`
+}
+
+// LookupUser returns the server user with the given username,
+// or nil if there is no such user.
+func (c *ConnectClient) LookupUser(username string, log logging.Logger) (*User, error) {
+	var users usersDTO
+	path := fmt.Sprintf("/__api__/v1/users?prefix=%s&page_size=500", url.QueryEscape(username))
+	err := c.client.Get(path, &users, log)
+	if err != nil {
+		return nil, err
+	}
+	// The search is a prefix match, so look for the exact name.
+	for _, user := range users.Results {
+		if user.Username == username {
+			return user.toUser(), nil
+		}
+	}
+	return nil, nil
+}
+
 var errInvalidServerOrCredentials = errors.New("could not validate credentials; check server URL and API key or token")
 var errInvalidApiKey = errors.New("could not log in with the provided credentials")
 var errInvalidServer = errors.New("could not access the server; check the server URL and try again")
@@ -102,12 +140,6 @@ func isConnectAuthError(err error) bool {
 	return errors.As(err, &serr)
 }
 
-type certificationValidationFailedDetails struct {
-	url              string
-	accountName      string
-	certificateError string
-}
-
 func (c *ConnectClient) TestAuthentication(log logging.Logger) (*User, error) {
 	log.Info("Testing authentication", "method", c.account.AuthType.Description(), "url", c.account.URL)
 	var connectUser UserDTO
@@ -120,17 +152,10 @@ func (c *ConnectClient) TestAuthentication(log logging.Logger) (*User, error) {
 			log.Debug("Request to Connect timed out")
 			return nil, ErrTimedOut
 		}
-		if urlError, ok := err.(*url.Error); ok {
-			if certificateError, ok := urlError.Err.(*tls.CertificateVerificationError); ok {
-				returnErr := fmt.Errorf("unable to verify TLS certificate for server (%s)", certificateError.Err)
-				log.Error(returnErr.Error())
-				details := &certificationValidationFailedDetails{
-					url:              c.account.URL,
-					accountName:      c.account.Name,
-					certificateError: certificateError.Err.Error(),
-				}
-				return nil, types.NewAgentError(types.ErrorCertificateVerification, returnErr, details)
-			}
+		var certErr *http_client.CertificateError
+		if errors.As(err, &certErr) {
+			log.Error(certErr.Error())
+			return nil, types.NewAgentError(types.ErrorCertificateVerification, certErr, certErr)
 		}
 		if isConnectAuthError(err) {
 			if c.account.ApiKey != "" {
@@ -219,6 +244,13 @@ func (c *ConnectClient) UpdateDeployment(contentID types.ContentID, body *Connec
 	return c.client.Patch(url, body, nil, log)
 }
 
+// DeleteContent deletes the content item from the server,
+// including its bundles and settings.
+func (c *ConnectClient) DeleteContent(contentID types.ContentID, log logging.Logger) error {
+	url := fmt.Sprintf("/__api__/v1/content/%s", contentID)
+	return c.client.Delete(url, log)
+}
+
 func (c *ConnectClient) GetEnvVars(contentId types.ContentID, log logging.Logger) (*types.Environment, error) {
 	var env *types.Environment
 	url := fmt.Sprintf("/__api__/v1/content/%s/environment", contentId)
@@ -234,6 +266,74 @@ type connectEnvVar struct {
 	Value string `json:"value"`
 }
 
+// A null value removes the environment variable.
+type connectEnvVarRemoval struct {
+	Name  string  `json:"name"`
+	Value *string `json:"value"`
+}
+
+func (c *ConnectClient) DeleteEnvVars(contentID types.ContentID, names []string, log logging.Logger) error {
+	body := make([]connectEnvVarRemoval, 0, len(names))
+	for _, name := range names {
+		body = append(body, connectEnvVarRemoval{
+			Name: name,
+		})
+	}
+	url := fmt.Sprintf("/__api__/v1/content/%s/environment", contentID)
+	return c.client.Patch(url, body, nil, log)
+}
+
+type jobDTO struct {
+	Key       string         `json:"key"`
+	StartTime types.Time     `json:"start_time"`
+	EndTime   types.NullTime `json:"end_time"`
+}
+
+type jobLogDTO struct {
+	Source  string     `json:"source"`
+	Entries []LogEntry `json:"entries"`
+}
+
+// GetContentLogs returns the log entries written at or after since by
+// the processes that have run the content, oldest first. To follow the
+// logs, call it again with the timestamp of the last entry returned;
+// entries with that timestamp are returned again, since more may have
+// been written at the same time.
+func (c *ConnectClient) GetContentLogs(contentID types.ContentID, since time.Time, log logging.Logger) ([]LogEntry, error) {
+	var jobs []jobDTO
+	url := fmt.Sprintf("/__api__/v1/content/%s/jobs", contentID)
+	err := c.client.Get(url, &jobs, log)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(jobs, func(a, b jobDTO) int {
+		return a.StartTime.Compare(b.StartTime)
+	})
+	entries := []LogEntry{}
+	for _, job := range jobs {
+		endTime, ended := job.EndTime.Get()
+		if ended && endTime.Before(since) {
+			// This job didn't write anything new.
+			continue
+		}
+		var jobLog jobLogDTO
+		url = fmt.Sprintf("/__api__/v1/content/%s/jobs/%s/log", contentID, job.Key)
+		err = c.client.Get(url, &jobLog, log)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range jobLog.Entries {
+			if !entry.Timestamp.Before(since) {
+				entries = append(entries, entry)
+			}
+		}
+	}
+	slices.SortStableFunc(entries, func(a, b LogEntry) int {
+		return a.Timestamp.Compare(b.Timestamp)
+	})
+	return entries, nil
+}
+
 func (c *ConnectClient) SetEnvVars(contentID types.ContentID, env config.Environment, log logging.Logger) error {
 	body := make([]connectEnvVar, 0, len(env))
 	for name, value := range env {
@@ -269,9 +369,13 @@ type connectGetBundleDTO struct {
 	Metadata      bundleMetadataDTO `json:"metadata"`
 }
 
+// bundleUploadTimeout is how long a bundle upload may take. It is
+// much longer than other requests are allowed, since bundles can be large.
+var bundleUploadTimeout = 30 * time.Minute
+
 func (c *ConnectClient) UploadBundle(contentID types.ContentID, body io.Reader, log logging.Logger) (types.BundleID, error) {
 	url := fmt.Sprintf("/__api__/v1/content/%s/bundles", contentID)
-	resp, err := c.client.PostRaw(url, body, "application/gzip", log)
+	resp, err := c.client.PostRawWithTimeout(url, body, "application/gzip", bundleUploadTimeout, log)
 	if err != nil {
 		return "", err
 	}
@@ -283,6 +387,26 @@ func (c *ConnectClient) UploadBundle(contentID types.ContentID, body io.Reader,
 	return bundle.Id, nil
 }
 
+func (c *ConnectClient) SetThumbnail(contentID types.ContentID, image io.Reader, imageType string, log logging.Logger) error {
+	url := fmt.Sprintf("/__api__/v1/content/%s/thumbnail", contentID)
+	_, err := c.client.PutRaw(url, image, imageType, log)
+	return err
+}
+
+type vanityDTO struct {
+	Path string `json:"path"`
+}
+
+// SetVanityURL sets the vanity path of the content.
+// The server responds with 409 Conflict if the path is in use.
+func (c *ConnectClient) SetVanityURL(contentID types.ContentID, path string, log logging.Logger) error {
+	body := vanityDTO{
+		Path: path,
+	}
+	url := fmt.Sprintf("/__api__/v1/content/%s/vanity", contentID)
+	return c.client.Put(url, body, nil, log)
+}
+
 type deployInputDTO struct {
 	BundleID types.BundleID `json:"bundle_id"`
 }
@@ -315,12 +439,8 @@ type taskDTO struct {
 	Last     int32        `json:"last"`
 }
 
-func (c *ConnectClient) getTask(taskID types.TaskID, previous *taskDTO, log logging.Logger) (*taskDTO, error) {
+func (c *ConnectClient) getTask(taskID types.TaskID, firstLine int32, log logging.Logger) (*taskDTO, error) {
 	var task taskDTO
-	var firstLine int32
-	if previous != nil {
-		firstLine = previous.Last
-	}
 	url := fmt.Sprintf("/__api__/v1/tasks/%s?first=%d", taskID, firstLine)
 	err := c.client.Get(url, &task, log)
 	if err != nil {
@@ -454,21 +574,101 @@ func (c *ConnectClient) handleTaskUpdate(task *taskDTO, op types.Operation, log
 	return op, nil
 }
 
-func (c *ConnectClient) WaitForTask(taskID types.TaskID, log logging.Logger) error {
-	var previous *taskDTO
+// TaskPollRetriesEnvVar names an environment variable containing the
+// number of consecutive transient errors, such as network failures or
+// 5xx responses, to tolerate while waiting for a task to finish.
+const TaskPollRetriesEnvVar = "POSIT_PUBLISHER_TASK_POLL_RETRIES"
+
+const defaultTaskPollRetries = 5
+
+var taskPollInterval = 500 * time.Millisecond
+
+func taskPollRetries(log logging.Logger) int {
+	value := os.Getenv(TaskPollRetriesEnvVar)
+	if value == "" {
+		return defaultTaskPollRetries
+	}
+	retries, err := strconv.Atoi(value)
+	if err != nil || retries < 0 {
+		log.Warn("Ignoring invalid value", "name", TaskPollRetriesEnvVar, "value", value)
+		return defaultTaskPollRetries
+	}
+	return retries
+}
+
+// CompressRequestsEnvVar names an environment variable that enables
+// gzip compression of large JSON request bodies, for servers
+// behind slow links.
+const CompressRequestsEnvVar = "POSIT_PUBLISHER_COMPRESS_REQUESTS"
+
+func compressRequests(log logging.Logger) bool {
+	value := os.Getenv(CompressRequestsEnvVar)
+	if value == "" {
+		return false
+	}
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Warn("Ignoring invalid value", "name", CompressRequestsEnvVar, "value", value)
+		return false
+	}
+	return enabled
+}
+
+// isTransientError returns true if the request might succeed
+// if it is repeated.
+func isTransientError(err error) bool {
+	agentErr, ok := err.(*types.AgentError)
+	if !ok {
+		return false
+	}
+	switch agentErr.Code {
+	case events.OperationTimedOutCode, events.ConnectionFailedCode:
+		return true
+	}
+	httpErr, ok := agentErr.Err.(*http_client.HTTPError)
+	return ok && httpErr.Status >= 500
+}
+
+// WaitForTask waits for the task to finish, logging its output
+// starting from the given position. If onProgress is not nil,
+// it is called with the new position as output is received.
+func (c *ConnectClient) WaitForTask(position types.TaskPosition, onProgress TaskProgressFunc, log logging.Logger) error {
 	var op events.Operation
+	maxRetries := taskPollRetries(log)
+	failures := 0
+	taskID := position.TaskID
 
 	for {
-		task, err := c.getTask(taskID, previous, log)
+		task, err := c.getTask(taskID, position.Offset, log)
 		if err != nil {
-			return err
+			if !isTransientError(err) || failures >= maxRetries {
+				return err
+			}
+			// The task may still be running. Try again,
+			// starting from the last output we received.
+			failures++
+			log.Warn("Error checking task status; retrying", "task", taskID, "attempt", failures, "error", err.Error())
+			err = http_client.Sleep(c.ctx, taskPollInterval)
+			if err != nil {
+				return err
+			}
+			continue
 		}
+		failures = 0
 		op, err = c.handleTaskUpdate(task, op, log)
+		if task.Last != position.Offset {
+			position.Offset = task.Last
+			if onProgress != nil {
+				onProgress(position)
+			}
+		}
 		if err != nil || task.Finished {
 			return err
 		}
-		previous = task
-		time.Sleep(500 * time.Millisecond)
+		err = http_client.Sleep(c.ctx, taskPollInterval)
+		if err != nil {
+			return err
+		}
 	}
 }
 
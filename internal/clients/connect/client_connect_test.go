@@ -3,6 +3,8 @@ package connect
 // Copyright (C) 2023 by Posit Software, PBC.
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"io/fs"
 	"net/http"
@@ -348,6 +350,151 @@ func (s *ConnectClientSuite) TestWaitForTaskErr() {
 	log.AssertExpectations(s.T())
 }
 
+func (s *ConnectClientSuite) newTaskPollClient(httpClient http_client.HTTPClient) *ConnectClient {
+	return &ConnectClient{
+		client:  httpClient,
+		account: &accounts.Account{},
+		emitter: events.NewNullEmitter(),
+		ctx:     context.Background(),
+	}
+}
+
+func (s *ConnectClientSuite) setTaskPollInterval() {
+	oldInterval := taskPollInterval
+	taskPollInterval = time.Millisecond
+	s.T().Cleanup(func() { taskPollInterval = oldInterval })
+}
+
+func (s *ConnectClientSuite) TestWaitForTaskTransientErrors() {
+	s.setTaskPollInterval()
+	lgr := logging.New()
+	httpClient := &http_client.MockHTTPClient{}
+	connectionErr := types.NewAgentError(events.ConnectionFailedCode, errors.New("connection reset"), nil)
+	serverErr := types.NewAgentError(events.ServerErrorCode,
+		http_client.NewHTTPError("https://connect.example.com", "GET", http.StatusBadGateway), nil)
+
+	httpClient.On("Get", "/__api__/v1/tasks/myTask?first=0", mock.Anything, lgr).Return(connectionErr).Once()
+	httpClient.On("Get", "/__api__/v1/tasks/myTask?first=0", mock.Anything, lgr).Return(nil).Run(func(args mock.Arguments) {
+		task := args.Get(1).(*taskDTO)
+		task.Output = []string{"Building Jupyter notebook..."}
+		task.Last = 1
+	}).Once()
+	// Polling resumes from the last position after errors.
+	httpClient.On("Get", "/__api__/v1/tasks/myTask?first=1", mock.Anything, lgr).Return(serverErr).Twice()
+	httpClient.On("Get", "/__api__/v1/tasks/myTask?first=1", mock.Anything, lgr).Return(nil).Run(func(args mock.Arguments) {
+		task := args.Get(1).(*taskDTO)
+		task.Finished = true
+		task.Last = 1
+	}).Once()
+
+	client := s.newTaskPollClient(httpClient)
+	err := client.WaitForTask(types.TaskPosition{TaskID: "myTask"}, nil, lgr)
+	s.NoError(err)
+	httpClient.AssertExpectations(s.T())
+}
+
+func (s *ConnectClientSuite) TestWaitForTaskTooManyTransientErrors() {
+	s.setTaskPollInterval()
+	s.T().Setenv(TaskPollRetriesEnvVar, "2")
+	lgr := logging.New()
+	httpClient := &http_client.MockHTTPClient{}
+	timeoutErr := types.NewAgentError(events.OperationTimedOutCode, errors.New("timeout"), nil)
+	httpClient.On("Get", "/__api__/v1/tasks/myTask?first=0", mock.Anything, lgr).Return(timeoutErr)
+
+	client := s.newTaskPollClient(httpClient)
+	err := client.WaitForTask(types.TaskPosition{TaskID: "myTask"}, nil, lgr)
+	s.ErrorIs(err, timeoutErr)
+	httpClient.AssertNumberOfCalls(s.T(), "Get", 3)
+}
+
+func (s *ConnectClientSuite) TestWaitForTaskHardError() {
+	s.setTaskPollInterval()
+	lgr := logging.New()
+	httpClient := &http_client.MockHTTPClient{}
+	authErr := types.NewAgentError(events.AuthenticationFailedCode,
+		http_client.NewHTTPError("https://connect.example.com", "GET", http.StatusUnauthorized), nil)
+	httpClient.On("Get", "/__api__/v1/tasks/myTask?first=0", mock.Anything, lgr).Return(authErr)
+
+	client := s.newTaskPollClient(httpClient)
+	err := client.WaitForTask(types.TaskPosition{TaskID: "myTask"}, nil, lgr)
+	s.ErrorIs(err, authErr)
+	httpClient.AssertNumberOfCalls(s.T(), "Get", 1)
+}
+
+func (s *ConnectClientSuite) TestWaitForTaskFailedAfterTransientError() {
+	s.setTaskPollInterval()
+	lgr := logging.New()
+	httpClient := &http_client.MockHTTPClient{}
+	connectionErr := types.NewAgentError(events.ConnectionFailedCode, errors.New("connection reset"), nil)
+	httpClient.On("Get", "/__api__/v1/tasks/myTask?first=0", mock.Anything, lgr).Return(connectionErr).Once()
+	httpClient.On("Get", "/__api__/v1/tasks/myTask?first=0", mock.Anything, lgr).Return(nil).Run(func(args mock.Arguments) {
+		task := args.Get(1).(*taskDTO)
+		task.Finished = true
+		task.Error = "the task failed"
+	}).Once()
+
+	client := s.newTaskPollClient(httpClient)
+	err := client.WaitForTask(types.TaskPosition{TaskID: "myTask"}, nil, lgr)
+	s.ErrorContains(err, "the task failed")
+	httpClient.AssertExpectations(s.T())
+}
+
+func (s *ConnectClientSuite) TestWaitForTaskCancelled() {
+	lgr := logging.New()
+	httpClient := &http_client.MockHTTPClient{}
+	ctx, cancel := context.WithCancel(context.Background())
+	httpClient.On("Get", "/__api__/v1/tasks/myTask?first=0", mock.Anything, lgr).Return(nil).Run(func(args mock.Arguments) {
+		// The user cancels while the task is still running.
+		cancel()
+	})
+
+	client := s.newTaskPollClient(httpClient).WithContext(ctx)
+	err := client.WaitForTask(types.TaskPosition{TaskID: "myTask"}, nil, lgr)
+	s.ErrorIs(err, context.Canceled)
+	httpClient.AssertNumberOfCalls(s.T(), "Get", 1)
+}
+
+func (s *ConnectClientSuite) TestWaitForTaskResume() {
+	s.setTaskPollInterval()
+	lines := []string{}
+	log := loggingtest.NewMockLogger()
+	log.On("Info", mock.AnythingOfType("string"), mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		lines = append(lines, args.String(0))
+	})
+	log.On("Warn", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	httpClient := &http_client.MockHTTPClient{}
+	httpClient.On("Get", "/__api__/v1/tasks/myTask?first=0", mock.Anything, log).Return(nil).Run(func(args mock.Arguments) {
+		task := args.Get(1).(*taskDTO)
+		task.Output = []string{"line 1", "line 2"}
+		task.Last = 2
+	}).Once()
+	// Waiting is interrupted, for example because the process exits.
+	httpClient.On("Get", "/__api__/v1/tasks/myTask?first=2", mock.Anything, log).Return(errors.New("interrupted")).Once()
+	httpClient.On("Get", "/__api__/v1/tasks/myTask?first=2", mock.Anything, log).Return(nil).Run(func(args mock.Arguments) {
+		task := args.Get(1).(*taskDTO)
+		task.Output = []string{"line 3"}
+		task.Last = 3
+		task.Finished = true
+	}).Once()
+
+	client := s.newTaskPollClient(httpClient)
+	var saved types.TaskPosition
+	onProgress := func(position types.TaskPosition) {
+		saved = position
+	}
+	err := client.WaitForTask(types.TaskPosition{TaskID: "myTask"}, onProgress, log)
+	s.ErrorContains(err, "interrupted")
+	s.Equal(types.TaskPosition{TaskID: "myTask", Offset: 2}, saved)
+
+	// Resume from the saved position.
+	err = client.WaitForTask(saved, onProgress, log)
+	s.NoError(err)
+	s.Equal(types.TaskPosition{TaskID: "myTask", Offset: 3}, saved)
+	s.Equal([]string{"line 1", "line 2", "line 3"}, lines)
+	httpClient.AssertExpectations(s.T())
+}
+
 func (s *ConnectClientSuite) TestValidateDeployment() {
 	httpClient := &http_client.MockHTTPClient{}
 	httpClient.On("GetRaw", mock.Anything, mock.Anything).Return(nil, nil)
@@ -952,3 +1099,216 @@ func (s *ConnectClientSuite) TestCheckCapabilities_requirementsFileNotInConfig()
 	s.Equal(aerr.Code, types.ErrorRequirementsFileReading)
 	s.Contains(aerr.Message, "Missing dependency file requirements.txt. This file must be included in the deployment.")
 }
+
+func (s *ConnectClientSuite) TestGetContentLogs() {
+	lgr := logging.New()
+	httpClient := &http_client.MockHTTPClient{}
+	contentID := types.ContentID("e8922765-4880-43cd-abc0-d59fe59b8b4b")
+
+	respondWith := func(body string) func(mock.Arguments) {
+		return func(args mock.Arguments) {
+			err := json.Unmarshal([]byte(body), args.Get(1))
+			s.NoError(err)
+		}
+	}
+	// The jobs are out of order, and the old one ended before since.
+	httpClient.On("Get", "/__api__/v1/content/e8922765-4880-43cd-abc0-d59fe59b8b4b/jobs", mock.Anything, lgr).Return(nil).Run(respondWith(`[
+		{"key": "new", "start_time": "2024-06-01T12:00:00Z", "end_time": null},
+		{"key": "old", "start_time": "2024-06-01T10:00:00Z", "end_time": "2024-06-01T10:30:00Z"},
+		{"key": "recent", "start_time": "2024-06-01T11:00:00Z", "end_time": "2024-06-01T12:30:00Z"}
+	]`))
+	httpClient.On("Get", "/__api__/v1/content/e8922765-4880-43cd-abc0-d59fe59b8b4b/jobs/recent/log", mock.Anything, lgr).Return(nil).Run(respondWith(`{
+		"source": "bundle",
+		"entries": [
+			{"source": "stdout", "timestamp": "2024-06-01T11:00:00Z", "data": "before since"},
+			{"source": "stdout", "timestamp": "2024-06-01T12:00:00Z", "data": "at since"},
+			{"source": "stderr", "timestamp": "2024-06-01T12:10:00Z", "data": "recent 1"},
+			{"source": "stdout", "timestamp": "2024-06-01T12:30:00Z", "data": "recent 2"}
+		]
+	}`))
+	httpClient.On("Get", "/__api__/v1/content/e8922765-4880-43cd-abc0-d59fe59b8b4b/jobs/new/log", mock.Anything, lgr).Return(nil).Run(respondWith(`{
+		"source": "bundle",
+		"entries": [
+			{"source": "stdout", "timestamp": "2024-06-01T12:20:00Z", "data": "new 1"}
+		]
+	}`))
+
+	client := &ConnectClient{
+		client: httpClient,
+	}
+	since := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	entries, err := client.GetContentLogs(contentID, since, lgr)
+	s.NoError(err)
+	httpClient.AssertExpectations(s.T())
+	httpClient.AssertNotCalled(s.T(), "Get", "/__api__/v1/content/e8922765-4880-43cd-abc0-d59fe59b8b4b/jobs/old/log", mock.Anything, lgr)
+
+	data := []string{}
+	for _, entry := range entries {
+		data = append(data, entry.Data)
+	}
+	// Entries at since are included, since they may not have been seen.
+	s.Equal([]string{"at since", "recent 1", "new 1", "recent 2"}, data)
+	s.Equal("stderr", entries[1].Source)
+}
+
+func (s *ConnectClientSuite) TestGetContentLogsErr() {
+	lgr := logging.New()
+	httpClient := &http_client.MockHTTPClient{}
+	expectedErr := errors.New("unreachable")
+	httpClient.On("Get", "/__api__/v1/content/e8922765-4880-43cd-abc0-d59fe59b8b4b/jobs", mock.Anything, lgr).Return(expectedErr)
+
+	client := &ConnectClient{
+		client: httpClient,
+	}
+	entries, err := client.GetContentLogs("e8922765-4880-43cd-abc0-d59fe59b8b4b", time.Time{}, lgr)
+	s.ErrorIs(err, expectedErr)
+	s.Nil(entries)
+}
+
+func (s *ConnectClientSuite) mockUserSearch(httpClient *http_client.MockHTTPClient, prefix string, lgr logging.Logger) {
+	httpClient.On("Get", "/__api__/v1/users?prefix="+prefix+"&page_size=500", mock.Anything, lgr).Return(nil).Run(func(args mock.Arguments) {
+		users := args.Get(1).(*usersDTO)
+		users.Results = []UserDTO{
+			{Username: "someuser2", GUID: "guid-2"},
+			{Username: "someuser", GUID: "guid-1"},
+		}
+	})
+}
+
+func (s *ConnectClientSuite) TestLookupUser() {
+	lgr := logging.New()
+	httpClient := &http_client.MockHTTPClient{}
+	s.mockUserSearch(httpClient, "someuser", lgr)
+	client := &ConnectClient{
+		client: httpClient,
+	}
+
+	user, err := client.LookupUser("someuser", lgr)
+	s.NoError(err)
+	s.NotNil(user)
+	s.Equal(types.UserID("guid-1"), user.Id)
+}
+
+func (s *ConnectClientSuite) TestLookupUserNotFound() {
+	lgr := logging.New()
+	httpClient := &http_client.MockHTTPClient{}
+	s.mockUserSearch(httpClient, "some", lgr)
+	client := &ConnectClient{
+		client: httpClient,
+	}
+
+	user, err := client.LookupUser("some", lgr)
+	s.NoError(err)
+	s.Nil(user)
+}
+
+func makeRunAsConfig(runAs string) *config.Config {
+	return &config.Config{
+		Connect: &config.Connect{
+			Access: &config.ConnectAccess{
+				RunAs: runAs,
+			},
+		},
+	}
+}
+
+func (s *ConnectClientSuite) TestCheckRunAsUser() {
+	s.T().Setenv(VerifyRunAsUserEnvVar, "true")
+	lgr := logging.New()
+	httpClient := &http_client.MockHTTPClient{}
+	s.mockUserSearch(httpClient, "someuser", lgr)
+	client := &ConnectClient{
+		client: httpClient,
+	}
+	s.NoError(client.checkRunAsUser(makeRunAsConfig("someuser"), lgr))
+	httpClient.AssertExpectations(s.T())
+}
+
+func (s *ConnectClientSuite) TestCheckRunAsUserNotFound() {
+	s.T().Setenv(VerifyRunAsUserEnvVar, "true")
+	lgr := logging.New()
+	httpClient := &http_client.MockHTTPClient{}
+	s.mockUserSearch(httpClient, "someusr", lgr)
+	client := &ConnectClient{
+		client: httpClient,
+	}
+	err := client.checkRunAsUser(makeRunAsConfig("someusr"), lgr)
+	agentErr, ok := types.IsAgentErrorOf(err, runAsUserNotFoundCode)
+	s.True(ok)
+	s.Equal("Run_as user 'someusr' was not found on the server.", agentErr.Message)
+	s.Equal("someusr", agentErr.Data["runAs"])
+}
+
+func (s *ConnectClientSuite) TestCheckRunAsUserDisabled() {
+	lgr := logging.New()
+	httpClient := &http_client.MockHTTPClient{}
+	client := &ConnectClient{
+		client: httpClient,
+	}
+	s.NoError(client.checkRunAsUser(makeRunAsConfig("someusr"), lgr))
+	httpClient.AssertNotCalled(s.T(), "Get", mock.Anything, mock.Anything, mock.Anything)
+
+	s.T().Setenv(VerifyRunAsUserEnvVar, "true")
+	s.NoError(client.checkRunAsUser(&config.Config{}, lgr))
+	httpClient.AssertNotCalled(s.T(), "Get", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *ConnectClientSuite) TestDeleteContent() {
+	lgr := logging.New()
+	httpClient := &http_client.MockHTTPClient{}
+	httpClient.On("Delete", "/__api__/v1/content/myContentID", lgr).Return(nil)
+	client := &ConnectClient{
+		client: httpClient,
+	}
+	err := client.DeleteContent("myContentID", lgr)
+	s.NoError(err)
+	httpClient.AssertExpectations(s.T())
+}
+
+func (s *ConnectClientSuite) TestSetVanityURL() {
+	lgr := logging.New()
+	httpClient := &http_client.MockHTTPClient{}
+	httpClient.On("Put", "/__api__/v1/content/myContentID/vanity", mock.Anything, nil, lgr).Return(nil).Run(func(args mock.Arguments) {
+		body, err := json.Marshal(args.Get(1))
+		s.NoError(err)
+		s.JSONEq(`{"path": "/sales/dashboard/"}`, string(body))
+	})
+	client := &ConnectClient{
+		client: httpClient,
+	}
+	err := client.SetVanityURL("myContentID", "/sales/dashboard/", lgr)
+	s.NoError(err)
+	httpClient.AssertExpectations(s.T())
+}
+
+func (s *ConnectClientSuite) TestUploadBundle() {
+	lgr := logging.New()
+	httpClient := &http_client.MockHTTPClient{}
+	body := strings.NewReader("bundle contents")
+	// Uploads are allowed much longer than other requests.
+	httpClient.On("PostRawWithTimeout", "/__api__/v1/content/myContentID/bundles", body, "application/gzip", bundleUploadTimeout, lgr).
+		Return([]byte(`{"id": "myBundleID"}`), nil)
+	client := &ConnectClient{
+		client: httpClient,
+	}
+	bundleID, err := client.UploadBundle("myContentID", body, lgr)
+	s.NoError(err)
+	s.Equal(types.BundleID("myBundleID"), bundleID)
+	httpClient.AssertExpectations(s.T())
+}
+
+func (s *ConnectClientSuite) TestDeleteEnvVars() {
+	lgr := logging.New()
+	httpClient := &http_client.MockHTTPClient{}
+	httpClient.On("Patch", "/__api__/v1/content/myContentID/environment", mock.Anything, nil, lgr).Return(nil).Run(func(args mock.Arguments) {
+		body, err := json.Marshal(args.Get(1))
+		s.NoError(err)
+		s.JSONEq(`[{"name": "OLD", "value": null}]`, string(body))
+	})
+	client := &ConnectClient{
+		client: httpClient,
+	}
+	err := client.DeleteEnvVars("myContentID", []string{"OLD"}, lgr)
+	s.NoError(err)
+	httpClient.AssertExpectations(s.T())
+}
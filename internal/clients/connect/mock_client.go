@@ -3,7 +3,9 @@ package connect
 // Copyright (C) 2023 by Posit Software, PBC.
 
 import (
+	"context"
 	"io"
+	"time"
 
 	"github.com/posit-dev/publisher/internal/config"
 	"github.com/posit-dev/publisher/internal/logging"
@@ -67,23 +69,53 @@ func (m *MockClient) GetEnvVars(id types.ContentID, log logging.Logger) (*types.
 	}
 }
 
+func (m *MockClient) GetContentLogs(id types.ContentID, since time.Time, log logging.Logger) ([]LogEntry, error) {
+	args := m.Called(id, since, log)
+	entries := args.Get(0)
+	if entries == nil {
+		return nil, args.Error(1)
+	} else {
+		return entries.([]LogEntry), args.Error(1)
+	}
+}
+
 func (m *MockClient) SetEnvVars(id types.ContentID, env config.Environment, log logging.Logger) error {
 	args := m.Called(id, env, log)
 	return args.Error(0)
 }
 
+func (m *MockClient) DeleteContent(id types.ContentID, log logging.Logger) error {
+	args := m.Called(id, log)
+	return args.Error(0)
+}
+
+func (m *MockClient) DeleteEnvVars(id types.ContentID, names []string, log logging.Logger) error {
+	args := m.Called(id, names, log)
+	return args.Error(0)
+}
+
 func (m *MockClient) UploadBundle(id types.ContentID, r io.Reader, log logging.Logger) (types.BundleID, error) {
 	args := m.Called(id, r, log)
 	return args.Get(0).(types.BundleID), args.Error(1)
 }
 
+func (m *MockClient) SetThumbnail(id types.ContentID, r io.Reader, imageType string, log logging.Logger) error {
+	args := m.Called(id, r, imageType, log)
+	return args.Error(0)
+}
+
+func (m *MockClient) SetVanityURL(id types.ContentID, path string, log logging.Logger) error {
+	args := m.Called(id, path, log)
+	return args.Error(0)
+}
+
 func (m *MockClient) DeployBundle(cid types.ContentID, bid types.BundleID, log logging.Logger) (types.TaskID, error) {
 	args := m.Called(cid, bid, log)
 	return args.Get(0).(types.TaskID), args.Error(1)
 }
 
-func (m *MockClient) WaitForTask(taskID types.TaskID, log logging.Logger) error {
-	args := m.Called(taskID, log)
+func (m *MockClient) WaitForTask(position types.TaskPosition, onProgress TaskProgressFunc, log logging.Logger) error {
+	args := m.Called(position, onProgress, log)
 	return args.Error(0)
 }
 
@@ -97,7 +129,31 @@ func (m *MockClient) CheckCapabilities(base util.AbsolutePath, cfg *config.Confi
 	return args.Error(0)
 }
 
+func (m *MockClient) CheckRuntimes(base util.AbsolutePath, cfg *config.Config, log logging.Logger) (*RuntimeReport, error) {
+	args := m.Called(base, cfg, log)
+	report := args.Get(0)
+	if report == nil {
+		return nil, args.Error(1)
+	} else {
+		return report.(*RuntimeReport), args.Error(1)
+	}
+}
+
+func (m *MockClient) GetSupportedContentTypes(log logging.Logger) ([]config.ContentType, error) {
+	args := m.Called(log)
+	contentTypes := args.Get(0)
+	if contentTypes == nil {
+		return nil, args.Error(1)
+	} else {
+		return contentTypes.([]config.ContentType), args.Error(1)
+	}
+}
+
 func (m *MockClient) ValidateDeploymentTarget(contentID types.ContentID, log logging.Logger) error {
 	args := m.Called(contentID, log)
 	return args.Error(0)
 }
+
+func (m *MockClient) WithContext(ctx context.Context) APIClient {
+	return m
+}
@@ -38,7 +38,7 @@ type ServerSettings struct {
 	// QueueUI                               bool                   `json:"queue_ui"`
 	Runtimes []string `json:"runtimes"`
 	// DefaultContentListView                string                 `json:"default_content_list_view"`
-	// MaximumAppImageSize                   int                    `json:"maximum_app_image_size"`
+	MaximumAppImageSize int64 `json:"maximum_app_image_size"`
 	// ServerSettingsToggler                 bool                   `json:"server_settings_toggler"`
 	GitEnabled   bool `json:"git_enabled"`
 	GitAvailable bool `json:"git_available"`
@@ -0,0 +1,72 @@
+package http_client
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+)
+
+// Reasons a server's TLS certificate can fail verification.
+const (
+	CertificateExpired          = "expired"
+	CertificateUnknownAuthority = "unknownAuthority"
+	CertificateHostnameMismatch = "hostnameMismatch"
+	CertificateInvalid          = "invalid"
+)
+
+// CertificateError reports that the server's TLS certificate
+// could not be verified.
+type CertificateError struct {
+	URL    string `mapstructure:"url"`
+	Reason string `mapstructure:"reason"`
+	Detail string `mapstructure:"certificateError"` // Error from the x509 package
+}
+
+// newCertificateError returns a CertificateError if err
+// is (or wraps) a TLS certificate verification error.
+func newCertificateError(url string, err error) (*CertificateError, bool) {
+	var verifyErr *tls.CertificateVerificationError
+	if !errors.As(err, &verifyErr) {
+		return nil, false
+	}
+	reason := CertificateInvalid
+	var invalidErr x509.CertificateInvalidError
+	var authorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+
+	switch {
+	case errors.As(verifyErr.Err, &invalidErr) && invalidErr.Reason == x509.Expired:
+		reason = CertificateExpired
+	case errors.As(verifyErr.Err, &authorityErr):
+		reason = CertificateUnknownAuthority
+	case errors.As(verifyErr.Err, &hostnameErr):
+		reason = CertificateHostnameMismatch
+	}
+	return &CertificateError{
+		URL:    url,
+		Reason: reason,
+		Detail: verifyErr.Err.Error(),
+	}, true
+}
+
+func (e *CertificateError) Error() string {
+	switch e.Reason {
+	case CertificateExpired:
+		return fmt.Sprintf("the server's TLS certificate has expired or is not yet valid (%s). "+
+			"Renew the certificate on the server, or use the Insecure option to skip verification", e.Detail)
+	case CertificateUnknownAuthority:
+		return fmt.Sprintf("the server's TLS certificate is signed by an unknown authority (%s). "+
+			"Use the Certificate option to trust the authority that signed it, "+
+			"or the Insecure option to skip verification", e.Detail)
+	case CertificateHostnameMismatch:
+		return fmt.Sprintf("the server's TLS certificate does not match the server URL (%s). "+
+			"Check the server URL, or use the Insecure option to skip verification", e.Detail)
+	default:
+		return fmt.Sprintf("unable to verify TLS certificate for server (%s). "+
+			"Use the Certificate option to trust the authority that signed it, "+
+			"or the Insecure option to skip verification", e.Detail)
+	}
+}
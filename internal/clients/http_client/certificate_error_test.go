@@ -0,0 +1,162 @@
+package http_client
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/posit-dev/publisher/internal/accounts"
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/types"
+	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/stretchr/testify/suite"
+)
+
+type CertificateErrorSuite struct {
+	utiltest.Suite
+}
+
+func TestCertificateErrorSuite(t *testing.T) {
+	suite.Run(t, new(CertificateErrorSuite))
+}
+
+func (s *CertificateErrorSuite) SetupTest() {
+	s.T().Setenv(DefaultCABundleEnvVar, "")
+}
+
+func (s *CertificateErrorSuite) newServer() *httptest.Server {
+	return httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+}
+
+// expiredCertificate returns a self-signed certificate for 127.0.0.1
+// that expired yesterday, and the path to its PEM file.
+func (s *CertificateErrorSuite) expiredCertificate() (tls.Certificate, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.NoError(err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Expired"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:             time.Now().Add(-48 * time.Hour),
+		NotAfter:              time.Now().Add(-24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	s.NoError(err)
+
+	path := filepath.Join(s.T().TempDir(), "expired.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	s.NoError(os.WriteFile(path, certPEM, 0600))
+
+	return tls.Certificate{
+		Certificate: [][]byte{der},
+		PrivateKey:  key,
+	}, path
+}
+
+func (s *CertificateErrorSuite) writeServerCA(srv *httptest.Server) string {
+	path := filepath.Join(s.T().TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: srv.Certificate().Raw,
+	})
+	s.NoError(os.WriteFile(path, certPEM, 0600))
+	return path
+}
+
+// get requests the server root and returns the AgentError.
+func (s *CertificateErrorSuite) get(account *accounts.Account) *types.AgentError {
+	client, err := NewDefaultHTTPClient(account, 10*time.Second, false, logging.New())
+	s.NoError(err)
+	_, err = client.GetRaw("/", logging.New())
+	s.Error(err)
+
+	agentErr, ok := err.(*types.AgentError)
+	s.True(ok)
+	s.Equal(types.ErrorCertificateVerification, agentErr.Code)
+	return agentErr
+}
+
+func (s *CertificateErrorSuite) TestUnknownAuthority() {
+	srv := s.newServer()
+	srv.StartTLS()
+	defer srv.Close()
+
+	agentErr := s.get(&accounts.Account{URL: srv.URL})
+	s.Equal(CertificateUnknownAuthority, agentErr.Data["reason"])
+	s.Equal(srv.URL+"/", agentErr.Data["url"])
+	s.Contains(agentErr.Message, "signed by an unknown authority")
+	s.Contains(agentErr.Message, "Certificate option")
+	s.Contains(agentErr.Message, "Insecure option")
+}
+
+func (s *CertificateErrorSuite) TestHostnameMismatch() {
+	srv := s.newServer()
+	srv.StartTLS()
+	defer srv.Close()
+
+	// The test server's certificate is valid for 127.0.0.1, not localhost.
+	url := strings.Replace(srv.URL, "127.0.0.1", "localhost", 1)
+	agentErr := s.get(&accounts.Account{
+		URL:         url,
+		Certificate: s.writeServerCA(srv),
+	})
+	s.Equal(CertificateHostnameMismatch, agentErr.Data["reason"])
+	s.Contains(agentErr.Message, "does not match the server URL")
+}
+
+func (s *CertificateErrorSuite) TestExpired() {
+	cert, certPath := s.expiredCertificate()
+	srv := s.newServer()
+	srv.TLS = &tls.Config{Certificates: []tls.Certificate{cert}}
+	srv.StartTLS()
+	defer srv.Close()
+
+	agentErr := s.get(&accounts.Account{
+		URL:         srv.URL,
+		Certificate: certPath,
+	})
+	s.Equal(CertificateExpired, agentErr.Data["reason"])
+	s.Contains(agentErr.Message, "has expired")
+	s.Contains(agentErr.Message, "Insecure option")
+}
+
+func (s *CertificateErrorSuite) TestInsecure() {
+	cert, _ := s.expiredCertificate()
+	srv := s.newServer()
+	srv.TLS = &tls.Config{Certificates: []tls.Certificate{cert}}
+	srv.StartTLS()
+	defer srv.Close()
+
+	client, err := NewDefaultHTTPClient(&accounts.Account{
+		URL:      srv.URL,
+		Insecure: true,
+	}, 10*time.Second, false, logging.New())
+	s.NoError(err)
+	_, err = client.GetRaw("/", logging.New())
+	s.NoError(err)
+}
+
+func (s *CertificateErrorSuite) TestNewCertificateErrorOther() {
+	_, ok := newCertificateError("https://connect.example.com", errors.New("connection refused"))
+	s.False(ok)
+}
@@ -0,0 +1,132 @@
+package http_client
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/posit-dev/publisher/internal/accounts"
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/stretchr/testify/suite"
+)
+
+type CompressionSuite struct {
+	utiltest.Suite
+	log logging.Logger
+}
+
+func TestCompressionSuite(t *testing.T) {
+	suite.Run(t, new(CompressionSuite))
+}
+
+func (s *CompressionSuite) SetupTest() {
+	s.log = logging.New()
+}
+
+type capturedRequest struct {
+	contentEncoding string
+	contentLength   int64
+	body            []byte
+}
+
+// newServer returns a server that records the last request it received.
+func (s *CompressionSuite) newServer(captured *capturedRequest) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		s.NoError(err)
+		*captured = capturedRequest{
+			contentEncoding: req.Header.Get("Content-Encoding"),
+			contentLength:   req.ContentLength,
+			body:            body,
+		}
+		w.Write([]byte(`{}`))
+	}))
+	s.T().Cleanup(srv.Close)
+	return srv
+}
+
+func (s *CompressionSuite) newClient(srv *httptest.Server, threshold int) *defaultHTTPClient {
+	return &defaultHTTPClient{
+		client:            srv.Client(),
+		baseURL:           srv.URL,
+		maxAttempts:       1,
+		retryDelay:        time.Millisecond,
+		ctx:               context.Background(),
+		compressThreshold: threshold,
+	}
+}
+
+type bigBody struct {
+	Data string `json:"data"`
+}
+
+func (s *CompressionSuite) TestCompressedBody() {
+	var captured capturedRequest
+	srv := s.newServer(&captured)
+	client := s.newClient(srv, 1024)
+
+	body := bigBody{Data: strings.Repeat("abcdef", 1000)}
+	err := client.Post("/", body, nil, s.log)
+	s.NoError(err)
+
+	s.Equal("gzip", captured.contentEncoding)
+	s.Equal(int64(len(captured.body)), captured.contentLength)
+
+	r, err := gzip.NewReader(bytes.NewReader(captured.body))
+	s.NoError(err)
+	decompressed, err := io.ReadAll(r)
+	s.NoError(err)
+	var received bigBody
+	err = json.Unmarshal(decompressed, &received)
+	s.NoError(err)
+	s.Equal(body, received)
+	s.Less(len(captured.body), len(decompressed))
+}
+
+func (s *CompressionSuite) TestSmallBodyNotCompressed() {
+	var captured capturedRequest
+	srv := s.newServer(&captured)
+	client := s.newClient(srv, 1024)
+
+	err := client.Post("/", bigBody{Data: "abc"}, nil, s.log)
+	s.NoError(err)
+	s.Equal("", captured.contentEncoding)
+	s.Equal(`{"data":"abc"}`, string(captured.body))
+}
+
+func (s *CompressionSuite) TestCompressionDisabled() {
+	var captured capturedRequest
+	srv := s.newServer(&captured)
+	client := s.newClient(srv, 0)
+
+	body := bigBody{Data: strings.Repeat("abcdef", 1000)}
+	err := client.Post("/", body, nil, s.log)
+	s.NoError(err)
+	s.Equal("", captured.contentEncoding)
+	s.Equal(int64(len(captured.body)), captured.contentLength)
+
+	var received bigBody
+	err = json.Unmarshal(captured.body, &received)
+	s.NoError(err)
+	s.Equal(body, received)
+}
+
+func (s *CompressionSuite) TestNewClientCompression() {
+	client, err := NewDefaultHTTPClient(&accounts.Account{}, 10*time.Second, true, s.log)
+	s.NoError(err)
+	s.Equal(defaultCompressThreshold, client.compressThreshold)
+
+	client, err = NewDefaultHTTPClient(&accounts.Account{}, 10*time.Second, false, s.log)
+	s.NoError(err)
+	s.Equal(0, client.compressThreshold)
+}
@@ -0,0 +1,131 @@
+package http_client
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/posit-dev/publisher/internal/events"
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/types"
+	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/stretchr/testify/suite"
+)
+
+type ContextSuite struct {
+	utiltest.Suite
+	log logging.Logger
+}
+
+func TestContextSuite(t *testing.T) {
+	suite.Run(t, new(ContextSuite))
+}
+
+func (s *ContextSuite) SetupTest() {
+	s.log = logging.New()
+}
+
+func (s *ContextSuite) newClient(srv *httptest.Server) *defaultHTTPClient {
+	return &defaultHTTPClient{
+		client:      srv.Client(),
+		baseURL:     srv.URL,
+		maxAttempts: 3,
+		retryDelay:  time.Hour,
+		ctx:         context.Background(),
+	}
+}
+
+func (s *ContextSuite) TestCancelDuringRequest() {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		close(started)
+		select {
+		case <-req.Context().Done():
+		case <-release:
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	client := s.newClient(srv).WithContext(ctx)
+	go func() {
+		<-started
+		cancel()
+	}()
+	err := client.Get("/__api__/v1/tasks/abc", nil, s.log)
+	s.ErrorIs(err, context.Canceled)
+}
+
+func (s *ContextSuite) TestCancelDuringRetryDelay() {
+	var count atomic.Int32
+	ctx, cancel := context.WithCancel(context.Background())
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		count.Add(1)
+		// The client would wait an hour before retrying.
+		cancel()
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	client := s.newClient(srv).WithContext(ctx)
+	err := client.Get("/__api__/v1/content", nil, s.log)
+	s.ErrorIs(err, context.Canceled)
+	s.Equal(int32(1), count.Load())
+}
+
+func (s *ContextSuite) TestWithContextDoesNotModifyClient() {
+	c := &defaultHTTPClient{ctx: context.Background()}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	scoped := c.WithContext(ctx)
+	s.Equal(ctx, scoped.(*defaultHTTPClient).ctx)
+	s.Equal(context.Background(), c.ctx)
+}
+
+// newSlowServer returns a server that takes the given time to respond.
+func (s *ContextSuite) newSlowServer(delay time.Duration) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		select {
+		case <-time.After(delay):
+			w.Write([]byte(`{"id": "123"}`))
+		case <-req.Context().Done():
+		}
+	}))
+	s.T().Cleanup(srv.Close)
+	return srv
+}
+
+func (s *ContextSuite) TestRequestTimeout() {
+	srv := s.newSlowServer(200 * time.Millisecond)
+	client := s.newClient(srv)
+	client.timeout = 20 * time.Millisecond
+
+	_, err := client.PostRaw("/__api__/v1/content/abc/bundles", strings.NewReader("bundle"), "application/gzip", s.log)
+	s.NotNil(err)
+	agentErr, ok := err.(*types.AgentError)
+	s.True(ok)
+	s.Equal(events.OperationTimedOutCode, agentErr.Code)
+}
+
+func (s *ContextSuite) TestRequestWithLongerTimeout() {
+	srv := s.newSlowServer(200 * time.Millisecond)
+	client := s.newClient(srv)
+	client.timeout = 20 * time.Millisecond
+
+	respBody, err := client.PostRawWithTimeout("/__api__/v1/content/abc/bundles", strings.NewReader("bundle"), "application/gzip", 10*time.Second, s.log)
+	s.NoError(err)
+	s.Equal(`{"id": "123"}`, string(respBody))
+
+	// Other requests still use the client's timeout.
+	err = client.Get("/__api__/v1/user", nil, s.log)
+	s.NotNil(err)
+}
@@ -4,6 +4,7 @@ package http_client
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"crypto/tls"
 	"crypto/x509"
@@ -11,10 +12,13 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"math/rand/v2"
 	"net"
 	"net/http"
 	"net/http/cookiejar"
 	"os"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/posit-dev/publisher/internal/accounts"
@@ -30,33 +34,97 @@ import (
 type HTTPClient interface {
 	GetRaw(path string, log logging.Logger) ([]byte, error)
 	PostRaw(path string, body io.Reader, bodyType string, log logging.Logger) ([]byte, error)
+	PostRawWithTimeout(path string, body io.Reader, bodyType string, timeout time.Duration, log logging.Logger) ([]byte, error)
+	PutRaw(path string, body io.Reader, bodyType string, log logging.Logger) ([]byte, error)
 	Get(path string, into any, log logging.Logger) error
 	Post(path string, body any, into any, log logging.Logger) error
 	Put(path string, body any, into any, log logging.Logger) error
 	Patch(path string, body any, into any, log logging.Logger) error
 	Delete(path string, log logging.Logger) error
+	// WithContext returns a client whose requests are
+	// cancelled when ctx is done.
+	WithContext(ctx context.Context) HTTPClient
 }
 
 type defaultHTTPClient struct {
-	client  *http.Client
-	baseURL string
+	client      *http.Client
+	baseURL     string
+	maxAttempts int
+	retryDelay  time.Duration
+	timeout     time.Duration
+	ctx         context.Context
+	// JSON request bodies larger than this are gzipped.
+	// Zero disables compression.
+	compressThreshold int
 }
 
-func NewDefaultHTTPClient(account *accounts.Account, timeout time.Duration, log logging.Logger) (*defaultHTTPClient, error) {
+// defaultCompressThreshold is the size of JSON request bodies above
+// which they are compressed, when compression is enabled. Smaller
+// bodies aren't worth the overhead.
+const defaultCompressThreshold = 16 * 1024
+
+// NewDefaultHTTPClient creates a client for the account's server.
+// If compressRequests is true, large JSON request bodies are gzipped.
+func NewDefaultHTTPClient(account *accounts.Account, timeout time.Duration, compressRequests bool, log logging.Logger) (*defaultHTTPClient, error) {
 	baseClient, err := NewHTTPClientForAccount(account, timeout, log)
 	if err != nil {
 		return nil, err
 	}
+	// Each request has its own deadline instead,
+	// so that some can be given longer.
+	baseClient.Timeout = 0
+	compressThreshold := 0
+	if compressRequests {
+		compressThreshold = defaultCompressThreshold
+	}
 	return &defaultHTTPClient{
-		client:  baseClient,
-		baseURL: account.URL,
+		client:            baseClient,
+		baseURL:           account.URL,
+		maxAttempts:       maxAttempts(log),
+		retryDelay:        defaultRetryDelay,
+		timeout:           timeout,
+		ctx:               context.Background(),
+		compressThreshold: compressThreshold,
 	}, nil
 }
 
+func (c *defaultHTTPClient) WithContext(ctx context.Context) HTTPClient {
+	scoped := *c
+	scoped.ctx = ctx
+	return &scoped
+}
+
+// MaxAttemptsEnvVar names an environment variable containing the
+// number of times to try a request that fails with a transient error,
+// such as a network failure or a 502 from a load balancer.
+const MaxAttemptsEnvVar = "POSIT_PUBLISHER_HTTP_MAX_ATTEMPTS"
+
+const defaultMaxAttempts = 3
+
+// defaultRetryDelay is the delay before the first retry.
+// It doubles for each retry after that.
+const defaultRetryDelay = time.Second
+
+func maxAttempts(log logging.Logger) int {
+	value := os.Getenv(MaxAttemptsEnvVar)
+	if value == "" {
+		return defaultMaxAttempts
+	}
+	attempts, err := strconv.Atoi(value)
+	if err != nil || attempts < 1 {
+		log.Warn("Ignoring invalid value", "name", MaxAttemptsEnvVar, "value", value)
+		return defaultMaxAttempts
+	}
+	return attempts
+}
+
 type HTTPError struct {
 	URL    string `mapstructure:"url"`
 	Method string `mapstructure:"method"`
 	Status int    `mapstructure:"status"`
+	// CorrelationID identifies the request in the server's logs,
+	// which helps Posit support diagnose server errors.
+	CorrelationID string `mapstructure:"correlationId,omitempty"`
 }
 
 func NewHTTPError(url, method string, status int) *HTTPError {
@@ -67,21 +135,169 @@ func NewHTTPError(url, method string, status int) *HTTPError {
 	}
 }
 
+// CorrelationIDHeader is the response header in which
+// Connect returns the ID that it logs for the request.
+const CorrelationIDHeader = "X-Correlation-Id"
+
 func (e *HTTPError) Error() string {
+	if e.CorrelationID != "" {
+		return fmt.Sprintf("unexpected response from the server (%d, correlation ID %s)", e.Status, e.CorrelationID)
+	}
 	return fmt.Sprintf("unexpected response from the server (%d)", e.Status)
 }
 
+// isRetryableMethod returns true for requests that can be repeated
+// without side effects. POST is only retried for uploads (PostRaw),
+// since a failed upload doesn't create a bundle.
+func isRetryableMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// isRetryableError returns true if the request failed
+// in a way that might not happen again.
+func isRetryableError(err error) bool {
+	agentErr, ok := err.(*types.AgentError)
+	if !ok {
+		return false
+	}
+	if agentErr.Code == events.ConnectionFailedCode {
+		return true
+	}
+	httpErr, ok := agentErr.Err.(*HTTPError)
+	if !ok {
+		return false
+	}
+	switch httpErr.Status {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// backoff returns the delay before the given retry (starting at 1),
+// doubling each time, with jitter so that clients don't retry in step.
+func (c *defaultHTTPClient) backoff(retry int) time.Duration {
+	delay := c.retryDelay << (retry - 1)
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// Sleep waits for the given duration, or until ctx is done,
+// in which case it returns the context's error.
+func Sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// nopCloser keeps the transport from closing a request body,
+// so it can be rewound and sent again.
+type nopCloser struct {
+	io.Reader
+}
+
+// rewindable returns a body that can be sent again after calling
+// rewind, or false if the body can't be rewound.
+func rewindable(body io.Reader) (io.Reader, func() error, bool) {
+	if body == nil {
+		return nil, func() error { return nil }, true
+	}
+	seeker, ok := body.(io.Seeker)
+	if !ok {
+		return body, nil, false
+	}
+	start, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return body, nil, false
+	}
+	rewind := func() error {
+		_, err := seeker.Seek(start, io.SeekStart)
+		return err
+	}
+	if _, isCloser := body.(io.Closer); isCloser {
+		body = nopCloser{body}
+	}
+	return body, rewind, true
+}
+
 func (c *defaultHTTPClient) do(method string, path string, body io.Reader, bodyType string, log logging.Logger) ([]byte, error) {
+	return c.doWithRetries(method, path, body, bodyType, "", isRetryableMethod(method), c.timeout, log)
+}
+
+// doWithRetries sends the request, retrying transient failures if
+// retryable is true. Each attempt must finish within timeout, if nonzero.
+// contentEncoding is the encoding of the body, if any, such as gzip.
+func (c *defaultHTTPClient) doWithRetries(method string, path string, body io.Reader, bodyType string, contentEncoding string, retryable bool, timeout time.Duration, log logging.Logger) ([]byte, error) {
+	attempts := 1
+	rewind := func() error { return nil }
+	if retryable {
+		var ok bool
+		body, rewind, ok = rewindable(body)
+		if ok {
+			attempts = max(c.maxAttempts, 1)
+		}
+	}
+	for attempt := 1; ; attempt++ {
+		respBody, err := c.doOnce(method, path, body, bodyType, contentEncoding, timeout, log)
+		if err == nil || attempt >= attempts || !isRetryableError(err) {
+			return respBody, err
+		}
+		delay := c.backoff(attempt)
+		log.Warn("Request failed; retrying",
+			"method", method,
+			"path", path,
+			"attempt", attempt,
+			"delay", delay,
+			"error", err.Error())
+		err = Sleep(c.ctx, delay)
+		if err != nil {
+			return nil, err
+		}
+		err = rewind()
+		if err != nil {
+			return nil, fmt.Errorf("can't resend request body: %w", err)
+		}
+	}
+}
+
+func (c *defaultHTTPClient) doOnce(method string, path string, body io.Reader, bodyType string, contentEncoding string, timeout time.Duration, log logging.Logger) ([]byte, error) {
 	apiURL := util.URLJoin(c.baseURL, path)
-	req, err := http.NewRequest(method, apiURL, body)
+	ctx := c.ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, apiURL, body)
 	if err != nil {
 		return nil, err
 	}
+	if contentEncoding != "" {
+		req.Header.Set("Content-Encoding", contentEncoding)
+	}
 	resp, err := c.client.Do(req)
 	if err != nil {
+		if ctxErr := c.ctx.Err(); ctxErr != nil {
+			// Cancelled requests are not retried.
+			return nil, ctxErr
+		}
 		if e, ok := err.(net.Error); ok && e.Timeout() {
 			return nil, types.NewAgentError(events.OperationTimedOutCode, err, nil)
 		}
+		if certErr, ok := newCertificateError(apiURL, err); ok {
+			return nil, types.NewAgentError(
+				types.ErrorCertificateVerification,
+				certErr,
+				certErr) // the error object contains its own details
+		}
 		return nil, types.NewAgentError(events.ConnectionFailedCode, err, nil)
 	}
 	defer resp.Body.Close()
@@ -108,12 +324,16 @@ func (c *defaultHTTPClient) do(method string, path string, body io.Reader, bodyT
 			errCode = events.PermissionsCode
 		}
 		httpErr := NewHTTPError(apiURL, method, resp.StatusCode)
+		httpErr.CorrelationID = resp.Header.Get(CorrelationIDHeader)
 		if errDetails == nil {
 			err = types.NewAgentError(
 				errCode,
 				httpErr,
 				httpErr) // the error object contains its own details
 		} else {
+			if httpErr.CorrelationID != "" {
+				errDetails["correlationId"] = httpErr.CorrelationID
+			}
 			err = types.NewAgentError(
 				errCode,
 				httpErr,
@@ -128,14 +348,25 @@ func (c *defaultHTTPClient) doJSON(method string, path string, body any, into an
 	bodyJSON := []byte(nil)
 	var err error
 
+	contentEncoding := ""
 	if body != nil {
 		bodyJSON, err = json.Marshal(body)
 		if err != nil {
 			return err
 		}
 		reqBody = bytes.NewReader(bodyJSON)
+		if c.compressThreshold > 0 && len(bodyJSON) > c.compressThreshold {
+			compressed, err := gzipBytes(bodyJSON)
+			if err != nil {
+				return err
+			}
+			// A bytes.Reader lets the request set Content-Length
+			// to the compressed size.
+			reqBody = bytes.NewReader(compressed)
+			contentEncoding = "gzip"
+		}
 	}
-	respBody, err := c.do(method, path, reqBody, "application/json", log)
+	respBody, err := c.doWithRetries(method, path, reqBody, "application/json", contentEncoding, isRetryableMethod(method), c.timeout, log)
 	if log.Enabled(context.Background(), slog.LevelDebug) {
 		const maxBody = 2000
 		trimmedRespBody := respBody
@@ -159,12 +390,36 @@ func (c *defaultHTTPClient) doJSON(method string, path string, body any, into an
 	return nil
 }
 
+func gzipBytes(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write(data)
+	if err != nil {
+		return nil, err
+	}
+	err = w.Close()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
 func (c *defaultHTTPClient) GetRaw(path string, log logging.Logger) ([]byte, error) {
 	return c.do("GET", path, nil, "", log)
 }
 
 func (c *defaultHTTPClient) PostRaw(path string, body io.Reader, bodyType string, log logging.Logger) ([]byte, error) {
-	return c.do("POST", path, body, bodyType, log)
+	return c.doWithRetries("POST", path, body, bodyType, "", true, c.timeout, log)
+}
+
+// PostRawWithTimeout is like PostRaw, but allows the request
+// a different amount of time than the client's default.
+func (c *defaultHTTPClient) PostRawWithTimeout(path string, body io.Reader, bodyType string, timeout time.Duration, log logging.Logger) ([]byte, error) {
+	return c.doWithRetries("POST", path, body, bodyType, "", true, timeout, log)
+}
+
+func (c *defaultHTTPClient) PutRaw(path string, body io.Reader, bodyType string, log logging.Logger) ([]byte, error) {
+	return c.do("PUT", path, body, bodyType, log)
 }
 
 func (c *defaultHTTPClient) Get(path string, into any, log logging.Logger) error {
@@ -187,31 +442,105 @@ func (c *defaultHTTPClient) Delete(path string, log logging.Logger) error {
 	return c.doJSON("DELETE", path, nil, nil, log)
 }
 
-func loadCACertificates(path string, log logging.Logger) (*x509.CertPool, error) {
-	if path == "" {
-		return nil, nil
-	}
+// DefaultCABundleEnvVar names an environment variable containing the
+// path to a PEM file of CA certificates that are trusted for all accounts,
+// in addition to the system's roots and any per-account certificate.
+const DefaultCABundleEnvVar = "POSIT_PUBLISHER_CA_BUNDLE"
+
+func appendCACertificates(certPool *x509.CertPool, path string, log logging.Logger) error {
 	log.Info("Loading CA certificate", "path", path)
 	certificate, err := os.ReadFile(path)
 	if err != nil {
-		return nil, fmt.Errorf("Error reading certificate file: %w", err)
+		return fmt.Errorf("Error reading certificate file: %w", err)
 	}
-	certPool := x509.NewCertPool()
 	ok := certPool.AppendCertsFromPEM(certificate)
 	if !ok {
-		return nil, fmt.Errorf("no PEM certificates were found in the certificate file '%s'", path)
+		return fmt.Errorf("no PEM certificates were found in the certificate file '%s'", path)
+	}
+	return nil
+}
+
+func loadCACertificates(path string, log logging.Logger) (*x509.CertPool, error) {
+	if path == "" {
+		return nil, nil
+	}
+	certPool := x509.NewCertPool()
+	err := appendCACertificates(certPool, path, log)
+	if err != nil {
+		return nil, err
+	}
+	return certPool, nil
+}
+
+// certPoolForAccount returns the root CAs to use for the account,
+// or nil to use the system's roots. Without a default CA bundle,
+// a per-account certificate replaces the system's roots. With one,
+// the default bundle and any per-account certificate are both
+// added to the system's roots.
+func certPoolForAccount(account *accounts.Account, log logging.Logger) (*x509.CertPool, error) {
+	defaultPath := os.Getenv(DefaultCABundleEnvVar)
+	if defaultPath == "" {
+		return loadCACertificates(account.Certificate, log)
+	}
+	certPool, err := x509.SystemCertPool()
+	if err != nil {
+		log.Warn("Cannot load the system's CA certificates", "error", err.Error())
+		certPool = x509.NewCertPool()
+	}
+	for _, path := range []string{defaultPath, account.Certificate} {
+		if path == "" {
+			continue
+		}
+		err = appendCACertificates(certPool, path, log)
+		if err != nil {
+			return nil, err
+		}
 	}
 	return certPool, nil
 }
 
+// insecureWarned records the accounts that have already been warned
+// about, so the warning isn't repeated for every client.
+var insecureWarned sync.Map
+
+func warnInsecure(account *accounts.Account, log logging.Logger) {
+	key := account.Name + "\x00" + account.URL
+	_, alreadyWarned := insecureWarned.LoadOrStore(key, true)
+	if alreadyWarned {
+		return
+	}
+	log.Warn("TLS certificate verification is disabled for this account; the connection to the server is not secure",
+		"account", account.Name,
+		"server", account.URL)
+}
+
+// NewHTTPClientForAccount creates a client for the account's server,
+// using the TLS options from the environment.
 func NewHTTPClientForAccount(account *accounts.Account, timeout time.Duration, log logging.Logger) (*http.Client, error) {
+	tlsOptions, err := TLSOptionsFromEnvironment()
+	if err != nil {
+		return nil, err
+	}
+	return NewHTTPClientForAccountWithTLS(account, timeout, tlsOptions, log)
+}
+
+// NewHTTPClientForAccountWithTLS creates a client for the account's
+// server that uses only the TLS versions and cipher suites in tlsOptions.
+func NewHTTPClientForAccountWithTLS(account *accounts.Account, timeout time.Duration, tlsOptions TLSOptions, log logging.Logger) (*http.Client, error) {
+	err := tlsOptions.validate()
+	if err != nil {
+		return nil, err
+	}
+	if account.Insecure {
+		warnInsecure(account, log)
+	}
 	cookieJar, err := cookiejar.New(&cookiejar.Options{
 		PublicSuffixList: publicsuffix.List,
 	})
 	if err != nil {
 		return nil, err
 	}
-	certPool, err := loadCACertificates(account.Certificate, log)
+	certPool, err := certPoolForAccount(account, log)
 	if err != nil {
 		return nil, err
 	}
@@ -234,7 +563,12 @@ func NewHTTPClientForAccount(account *accounts.Account, timeout time.Duration, l
 			RootCAs:            certPool,
 		},
 	}
-	authTransport := NewAuthenticatedTransport(transport, auth.NewClientAuth(account))
+	tlsOptions.apply(transport.TLSClientConfig)
+	clientAuth, err := auth.NewClientAuth(account)
+	if err != nil {
+		return nil, err
+	}
+	authTransport := NewAuthenticatedTransport(transport, clientAuth)
 	return &http.Client{
 		Jar:       cookieJar,
 		Timeout:   timeout,
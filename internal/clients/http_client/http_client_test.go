@@ -1,13 +1,29 @@
 package http_client
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"errors"
+	"math/big"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/posit-dev/publisher/internal/accounts"
 	"github.com/posit-dev/publisher/internal/events"
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/logging/loggingtest"
 	"github.com/posit-dev/publisher/internal/types"
 	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -43,3 +59,154 @@ func (s *HttpClientSuite) TestIsHTTPAgentErrorStatusOf() {
 	s.Equal(yesItIs, false)
 	s.Nil(resultingErr)
 }
+
+func (s *HttpClientSuite) TestHTTPErrorCorrelationID() {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set(CorrelationIDHeader, "f0e1d2c3")
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	account := &accounts.Account{URL: srv.URL}
+	client, err := NewDefaultHTTPClient(account, 10*time.Second, false, logging.New())
+	s.NoError(err)
+	err = client.Get("/__api__/v1/content", nil, logging.New())
+	agentErr, ok := IsHTTPAgentErrorStatusOf(err, http.StatusInternalServerError)
+	s.True(ok)
+	httpErr := agentErr.Err.(*HTTPError)
+	s.Equal("f0e1d2c3", httpErr.CorrelationID)
+	s.Equal("unexpected response from the server (500, correlation ID f0e1d2c3)", err.Error())
+	s.Equal("f0e1d2c3", agentErr.Data["correlationId"])
+}
+
+func (s *HttpClientSuite) TestHTTPErrorCorrelationIDWithDetails() {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set(CorrelationIDHeader, "f0e1d2c3")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"code": 3, "error": "bad request"}`))
+	}))
+	defer srv.Close()
+
+	account := &accounts.Account{URL: srv.URL}
+	client, err := NewDefaultHTTPClient(account, 10*time.Second, false, logging.New())
+	s.NoError(err)
+	err = client.Get("/__api__/v1/content", nil, logging.New())
+	agentErr, ok := IsHTTPAgentErrorStatusOf(err, http.StatusBadRequest)
+	s.True(ok)
+	s.Equal("bad request", agentErr.Data["error"])
+	s.Equal("f0e1d2c3", agentErr.Data["correlationId"])
+}
+
+func (s *HttpClientSuite) TestHTTPErrorWithoutCorrelationID() {
+	err := NewHTTPError("https://connect.example.com/__api__/v1/content", "GET", http.StatusBadGateway)
+	s.Equal("unexpected response from the server (502)", err.Error())
+}
+
+// writeServerCA writes the test server's certificate to a PEM file.
+func (s *HttpClientSuite) writeServerCA(srv *httptest.Server) string {
+	path := filepath.Join(s.T().TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: srv.Certificate().Raw,
+	})
+	s.NoError(os.WriteFile(path, certPEM, 0600))
+	return path
+}
+
+func (s *HttpClientSuite) get(srv *httptest.Server, account *accounts.Account) error {
+	account.URL = srv.URL
+	client, err := NewHTTPClientForAccount(account, 10*time.Second, logging.New())
+	s.NoError(err)
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s *HttpClientSuite) TestNewHTTPClientForAccountDefaultCA() {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	defer srv.Close()
+
+	s.T().Setenv(DefaultCABundleEnvVar, "")
+	err := s.get(srv, &accounts.Account{})
+	s.Error(err)
+
+	// The default CA is used when the account has no certificate.
+	s.T().Setenv(DefaultCABundleEnvVar, s.writeServerCA(srv))
+	err = s.get(srv, &accounts.Account{})
+	s.NoError(err)
+}
+
+// writeUnrelatedCA writes a self-signed certificate that
+// didn't sign the test server's certificate.
+func (s *HttpClientSuite) writeUnrelatedCA() string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.NoError(err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Unrelated CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	s.NoError(err)
+
+	path := filepath.Join(s.T().TempDir(), "unrelated.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	s.NoError(os.WriteFile(path, certPEM, 0600))
+	return path
+}
+
+func (s *HttpClientSuite) TestNewHTTPClientForAccountDefaultAndAccountCA() {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	defer srv.Close()
+
+	s.T().Setenv(DefaultCABundleEnvVar, s.writeUnrelatedCA())
+	err := s.get(srv, &accounts.Account{})
+	s.Error(err)
+
+	// The per-account certificate is trusted along with the default.
+	err = s.get(srv, &accounts.Account{Certificate: s.writeServerCA(srv)})
+	s.NoError(err)
+}
+
+func (s *HttpClientSuite) TestNewHTTPClientForAccountBadDefaultCA() {
+	path := filepath.Join(s.T().TempDir(), "ca.pem")
+	s.NoError(os.WriteFile(path, []byte("not a certificate"), 0600))
+	s.T().Setenv(DefaultCABundleEnvVar, path)
+
+	_, err := NewHTTPClientForAccount(&accounts.Account{}, 10*time.Second, logging.New())
+	s.ErrorContains(err, "no PEM certificates were found")
+}
+
+func (s *HttpClientSuite) TestNewHTTPClientForAccountInsecureWarning() {
+	account := &accounts.Account{
+		Name:     "insecure-test",
+		URL:      "https://insecure.example.com",
+		Insecure: true,
+	}
+	log := loggingtest.NewMockLogger()
+	log.On("Warn", mock.MatchedBy(func(msg string) bool {
+		return strings.Contains(msg, "TLS certificate verification is disabled")
+	}), "account", "insecure-test", "server", "https://insecure.example.com").Return().Once()
+
+	// The warning is only logged once per account.
+	for i := 0; i < 2; i++ {
+		client, err := NewHTTPClientForAccount(account, 10*time.Second, log)
+		s.NoError(err)
+		s.True(transportTLSConfig(client).InsecureSkipVerify)
+	}
+	log.AssertExpectations(s.T())
+}
+
+func (s *HttpClientSuite) TestNewHTTPClientForAccountSecureNoWarning() {
+	log := loggingtest.NewMockLogger()
+	_, err := NewHTTPClientForAccount(&accounts.Account{URL: "https://secure.example.com"}, 10*time.Second, log)
+	s.NoError(err)
+	log.AssertNotCalled(s.T(), "Warn", mock.Anything, mock.Anything)
+}
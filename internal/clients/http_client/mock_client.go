@@ -3,7 +3,9 @@ package http_client
 // Copyright (C) 2023 by Posit Software, PBC.
 
 import (
+	"context"
 	"io"
+	"time"
 
 	"github.com/posit-dev/publisher/internal/logging"
 	"github.com/stretchr/testify/mock"
@@ -33,6 +35,26 @@ func (m *MockHTTPClient) PostRaw(path string, body io.Reader, bodyType string, l
 	}
 }
 
+func (m *MockHTTPClient) PostRawWithTimeout(path string, body io.Reader, bodyType string, timeout time.Duration, log logging.Logger) ([]byte, error) {
+	args := m.Called(path, body, bodyType, timeout, log)
+	data := args.Get(0)
+	if data == nil {
+		return nil, args.Error(1)
+	} else {
+		return data.([]byte), args.Error(1)
+	}
+}
+
+func (m *MockHTTPClient) PutRaw(path string, body io.Reader, bodyType string, log logging.Logger) ([]byte, error) {
+	args := m.Called(path, body, bodyType, log)
+	data := args.Get(0)
+	if data == nil {
+		return nil, args.Error(1)
+	} else {
+		return data.([]byte), args.Error(1)
+	}
+}
+
 func (m *MockHTTPClient) Get(path string, into any, log logging.Logger) error {
 	args := m.Called(path, into, log)
 	return args.Error(0)
@@ -57,3 +79,7 @@ func (m *MockHTTPClient) Delete(path string, log logging.Logger) error {
 	args := m.Called(path, log)
 	return args.Error(0)
 }
+
+func (m *MockHTTPClient) WithContext(ctx context.Context) HTTPClient {
+	return m
+}
@@ -0,0 +1,222 @@
+package http_client
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/posit-dev/publisher/internal/events"
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/logging/loggingtest"
+	"github.com/posit-dev/publisher/internal/types"
+	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type RetrySuite struct {
+	utiltest.Suite
+	log logging.Logger
+}
+
+func TestRetrySuite(t *testing.T) {
+	suite.Run(t, new(RetrySuite))
+}
+
+func (s *RetrySuite) SetupTest() {
+	s.log = logging.New()
+}
+
+// newServer returns a server that responds with the given
+// statuses in order, then 200, and a count of the requests.
+// Each request body is recorded in bodies.
+func (s *RetrySuite) newServer(statuses []int, bodies *[]string) (*httptest.Server, *atomic.Int32) {
+	var count atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		n := int(count.Add(1))
+		if bodies != nil {
+			body, err := io.ReadAll(req.Body)
+			s.NoError(err)
+			*bodies = append(*bodies, string(body))
+		}
+		if n <= len(statuses) {
+			w.WriteHeader(statuses[n-1])
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	s.T().Cleanup(srv.Close)
+	return srv, &count
+}
+
+func (s *RetrySuite) newClient(srv *httptest.Server) *defaultHTTPClient {
+	return &defaultHTTPClient{
+		client:      srv.Client(),
+		baseURL:     srv.URL,
+		maxAttempts: 3,
+		retryDelay:  time.Millisecond,
+		ctx:         context.Background(),
+	}
+}
+
+func (s *RetrySuite) TestRetryTransient() {
+	for _, status := range []int{
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	} {
+		srv, count := s.newServer([]int{status, status}, nil)
+		err := s.newClient(srv).Get("/__api__/v1/content", nil, s.log)
+		s.NoError(err)
+		s.Equal(int32(3), count.Load())
+	}
+}
+
+func (s *RetrySuite) TestRetryGivesUp() {
+	srv, count := s.newServer([]int{502, 502, 502, 502}, nil)
+	err := s.newClient(srv).Delete("/__api__/v1/content/123", s.log)
+	_, isBadGateway := IsHTTPAgentErrorStatusOf(err, http.StatusBadGateway)
+	s.True(isBadGateway)
+	s.Equal(int32(3), count.Load())
+}
+
+func (s *RetrySuite) TestNoRetryClientError() {
+	for _, status := range []int{
+		http.StatusBadRequest,
+		http.StatusUnauthorized,
+		http.StatusNotFound,
+		http.StatusConflict,
+	} {
+		srv, count := s.newServer([]int{status}, nil)
+		err := s.newClient(srv).Get("/__api__/v1/content", nil, s.log)
+		s.Error(err)
+		s.Equal(int32(1), count.Load())
+	}
+}
+
+func (s *RetrySuite) TestNoRetryInternalServerError() {
+	srv, count := s.newServer([]int{http.StatusInternalServerError}, nil)
+	err := s.newClient(srv).Get("/__api__/v1/content", nil, s.log)
+	s.Error(err)
+	s.Equal(int32(1), count.Load())
+}
+
+func (s *RetrySuite) TestNoRetryJSONPost() {
+	// Repeating a POST might create something twice.
+	srv, count := s.newServer([]int{http.StatusBadGateway}, nil)
+	err := s.newClient(srv).Post("/__api__/v1/content", map[string]string{"name": "x"}, nil, s.log)
+	s.Error(err)
+	s.Equal(int32(1), count.Load())
+
+	srv, count = s.newServer([]int{http.StatusBadGateway}, nil)
+	err = s.newClient(srv).Patch("/__api__/v1/content/123", map[string]string{"name": "x"}, nil, s.log)
+	s.Error(err)
+	s.Equal(int32(1), count.Load())
+}
+
+func (s *RetrySuite) TestRetryPutResendsBody() {
+	var bodies []string
+	srv, count := s.newServer([]int{http.StatusBadGateway}, &bodies)
+	err := s.newClient(srv).Put("/__api__/v1/content/123/vanity", map[string]string{"path": "/x/"}, nil, s.log)
+	s.NoError(err)
+	s.Equal(int32(2), count.Load())
+	s.Equal([]string{`{"path":"/x/"}`, `{"path":"/x/"}`}, bodies)
+}
+
+func (s *RetrySuite) TestRetryUploadFromFile() {
+	path := filepath.Join(s.T().TempDir(), "bundle.tar.gz")
+	err := os.WriteFile(path, []byte("bundle contents"), 0600)
+	s.NoError(err)
+	f, err := os.Open(path)
+	s.NoError(err)
+	defer f.Close()
+
+	var bodies []string
+	srv, count := s.newServer([]int{http.StatusBadGateway, http.StatusServiceUnavailable}, &bodies)
+	_, err = s.newClient(srv).PostRaw("/__api__/v1/content/123/bundles", f, "application/gzip", s.log)
+	s.NoError(err)
+	s.Equal(int32(3), count.Load())
+	s.Equal([]string{"bundle contents", "bundle contents", "bundle contents"}, bodies)
+}
+
+func (s *RetrySuite) TestNoRetryUnrewindableBody() {
+	srv, count := s.newServer([]int{http.StatusBadGateway}, nil)
+	body := io.MultiReader(bytes.NewReader([]byte("bundle contents")))
+	_, err := s.newClient(srv).PostRaw("/__api__/v1/content/123/bundles", body, "application/gzip", s.log)
+	s.Error(err)
+	s.Equal(int32(1), count.Load())
+}
+
+// unrewindableReader can find its current position,
+// but can't seek back to it.
+type unrewindableReader struct {
+	*bytes.Reader
+}
+
+var errSeek = errors.New("test seek error")
+
+func (r unrewindableReader) Seek(offset int64, whence int) (int64, error) {
+	if whence == io.SeekStart {
+		return 0, errSeek
+	}
+	return r.Reader.Seek(offset, whence)
+}
+
+func (s *RetrySuite) TestRetryRewindFails() {
+	srv, count := s.newServer([]int{http.StatusBadGateway}, nil)
+	body := unrewindableReader{bytes.NewReader([]byte("bundle contents"))}
+	respBody, err := s.newClient(srv).PostRaw("/__api__/v1/content/123/bundles", body, "application/gzip", s.log)
+	s.ErrorIs(err, errSeek)
+	s.Nil(respBody)
+	s.Equal(int32(1), count.Load())
+}
+
+func (s *RetrySuite) TestRetryConnectionFailed() {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	client := s.newClient(srv)
+	srv.Close()
+
+	log := loggingtest.NewMockLogger()
+	log.On("Warn", "Request failed; retrying", "method", "GET", "path", "/", "attempt", mock.Anything, "delay", mock.Anything, "error", mock.Anything).Return()
+	_, err := client.GetRaw("/", log)
+	agentErr, ok := types.IsAgentErrorOf(err, events.ConnectionFailedCode)
+	s.True(ok)
+	s.NotNil(agentErr)
+	log.AssertNumberOfCalls(s.T(), "Warn", 2)
+}
+
+func (s *RetrySuite) TestBackoff() {
+	c := &defaultHTTPClient{retryDelay: time.Second}
+	for retry, max := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second} {
+		delay := c.backoff(retry + 1)
+		s.GreaterOrEqual(delay, max/2)
+		s.LessOrEqual(delay, max)
+	}
+}
+
+func (s *RetrySuite) TestMaxAttempts() {
+	log := loggingtest.NewMockLogger()
+	s.T().Setenv(MaxAttemptsEnvVar, "")
+	s.Equal(defaultMaxAttempts, maxAttempts(log))
+
+	s.T().Setenv(MaxAttemptsEnvVar, "5")
+	s.Equal(5, maxAttempts(log))
+
+	// 1 disables retries
+	s.T().Setenv(MaxAttemptsEnvVar, "1")
+	s.Equal(1, maxAttempts(log))
+
+	log.On("Warn", "Ignoring invalid value", "name", MaxAttemptsEnvVar, "value", "0").Return()
+	s.T().Setenv(MaxAttemptsEnvVar, "0")
+	s.Equal(defaultMaxAttempts, maxAttempts(log))
+}
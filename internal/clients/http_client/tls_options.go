@@ -0,0 +1,105 @@
+package http_client
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"crypto/tls"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Environment variables used to configure TLS for all accounts.
+const (
+	TLSMinVersionEnvVar   = "POSIT_PUBLISHER_TLS_MIN_VERSION"
+	TLSMaxVersionEnvVar   = "POSIT_PUBLISHER_TLS_MAX_VERSION"
+	TLSCipherSuitesEnvVar = "POSIT_PUBLISHER_TLS_CIPHER_SUITES"
+)
+
+// TLSOptions restricts the TLS versions and cipher suites used
+// when connecting to a server. Zero values use Go's defaults.
+type TLSOptions struct {
+	MinVersion uint16
+	MaxVersion uint16
+	// CipherSuites only applies to TLS 1.2 and earlier;
+	// TLS 1.3 cipher suites are not configurable.
+	CipherSuites []uint16
+}
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+func parseTLSVersion(envVar string) (uint16, error) {
+	value := strings.TrimSpace(os.Getenv(envVar))
+	if value == "" {
+		return 0, nil
+	}
+	version, ok := tlsVersions[strings.TrimPrefix(strings.ToLower(value), "tls")]
+	if !ok {
+		return 0, fmt.Errorf("%s: unsupported TLS version '%s'; use 1.0, 1.1, 1.2, or 1.3", envVar, value)
+	}
+	return version, nil
+}
+
+func parseCipherSuites(envVar string) ([]uint16, error) {
+	value := strings.TrimSpace(os.Getenv(envVar))
+	if value == "" {
+		return nil, nil
+	}
+	// Only the cipher suites Go considers secure are allowed.
+	known := map[string]uint16{}
+	for _, suite := range tls.CipherSuites() {
+		known[suite.Name] = suite.ID
+	}
+	var suites []uint16
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		id, ok := known[name]
+		if !ok {
+			return nil, fmt.Errorf("%s: unknown or insecure cipher suite '%s'", envVar, name)
+		}
+		suites = append(suites, id)
+	}
+	return suites, nil
+}
+
+// TLSOptionsFromEnvironment reads the TLS options from
+// the POSIT_PUBLISHER_TLS_* environment variables.
+func TLSOptionsFromEnvironment() (TLSOptions, error) {
+	minVersion, err := parseTLSVersion(TLSMinVersionEnvVar)
+	if err != nil {
+		return TLSOptions{}, err
+	}
+	maxVersion, err := parseTLSVersion(TLSMaxVersionEnvVar)
+	if err != nil {
+		return TLSOptions{}, err
+	}
+	suites, err := parseCipherSuites(TLSCipherSuitesEnvVar)
+	if err != nil {
+		return TLSOptions{}, err
+	}
+	opts := TLSOptions{
+		MinVersion:   minVersion,
+		MaxVersion:   maxVersion,
+		CipherSuites: suites,
+	}
+	return opts, opts.validate()
+}
+
+func (o TLSOptions) validate() error {
+	if o.MinVersion != 0 && o.MaxVersion != 0 && o.MinVersion > o.MaxVersion {
+		return fmt.Errorf("the minimum TLS version (%s) is greater than the maximum (%s)",
+			tls.VersionName(o.MinVersion), tls.VersionName(o.MaxVersion))
+	}
+	return nil
+}
+
+func (o TLSOptions) apply(cfg *tls.Config) {
+	cfg.MinVersion = o.MinVersion
+	cfg.MaxVersion = o.MaxVersion
+	cfg.CipherSuites = o.CipherSuites
+}
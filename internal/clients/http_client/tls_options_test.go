@@ -0,0 +1,107 @@
+package http_client
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"crypto/tls"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/posit-dev/publisher/internal/accounts"
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/stretchr/testify/suite"
+)
+
+type TLSOptionsSuite struct {
+	utiltest.Suite
+	envVarHelper utiltest.EnvVarHelper
+}
+
+func TestTLSOptionsSuite(t *testing.T) {
+	suite.Run(t, new(TLSOptionsSuite))
+}
+
+func (s *TLSOptionsSuite) SetupTest() {
+	s.envVarHelper.Setup(TLSMinVersionEnvVar, TLSMaxVersionEnvVar, TLSCipherSuitesEnvVar)
+}
+
+func (s *TLSOptionsSuite) TearDownTest() {
+	s.envVarHelper.Teardown()
+}
+
+func transportTLSConfig(client *http.Client) *tls.Config {
+	authTransport := client.Transport.(*AuthenticatedTransport)
+	return authTransport.base.(*http.Transport).TLSClientConfig
+}
+
+func (s *TLSOptionsSuite) TestNewHTTPClientForAccountWithTLS() {
+	opts := TLSOptions{
+		MinVersion:   tls.VersionTLS12,
+		MaxVersion:   tls.VersionTLS13,
+		CipherSuites: []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256},
+	}
+	client, err := NewHTTPClientForAccountWithTLS(&accounts.Account{}, time.Second, opts, logging.New())
+	s.NoError(err)
+
+	cfg := transportTLSConfig(client)
+	s.Equal(uint16(tls.VersionTLS12), cfg.MinVersion)
+	s.Equal(uint16(tls.VersionTLS13), cfg.MaxVersion)
+	s.Equal([]uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256}, cfg.CipherSuites)
+}
+
+func (s *TLSOptionsSuite) TestNewHTTPClientForAccountDefaults() {
+	client, err := NewHTTPClientForAccount(&accounts.Account{}, time.Second, logging.New())
+	s.NoError(err)
+
+	cfg := transportTLSConfig(client)
+	s.Equal(uint16(0), cfg.MinVersion)
+	s.Equal(uint16(0), cfg.MaxVersion)
+	s.Nil(cfg.CipherSuites)
+}
+
+func (s *TLSOptionsSuite) TestNewHTTPClientForAccountFromEnvironment() {
+	s.T().Setenv(TLSMinVersionEnvVar, "1.2")
+	client, err := NewHTTPClientForAccount(&accounts.Account{}, time.Second, logging.New())
+	s.NoError(err)
+	s.Equal(uint16(tls.VersionTLS12), transportTLSConfig(client).MinVersion)
+}
+
+func (s *TLSOptionsSuite) TestTLSOptionsFromEnvironment() {
+	s.T().Setenv(TLSMinVersionEnvVar, "TLS1.2")
+	s.T().Setenv(TLSMaxVersionEnvVar, "1.3")
+	s.T().Setenv(TLSCipherSuitesEnvVar, "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384")
+
+	opts, err := TLSOptionsFromEnvironment()
+	s.NoError(err)
+	s.Equal(TLSOptions{
+		MinVersion: tls.VersionTLS12,
+		MaxVersion: tls.VersionTLS13,
+		CipherSuites: []uint16{
+			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+		},
+	}, opts)
+}
+
+func (s *TLSOptionsSuite) TestTLSOptionsFromEnvironmentBadVersion() {
+	s.T().Setenv(TLSMinVersionEnvVar, "2.0")
+	_, err := TLSOptionsFromEnvironment()
+	s.ErrorContains(err, "unsupported TLS version '2.0'")
+}
+
+func (s *TLSOptionsSuite) TestTLSOptionsFromEnvironmentInsecureCipher() {
+	s.T().Setenv(TLSCipherSuitesEnvVar, "TLS_RSA_WITH_RC4_128_SHA")
+	_, err := TLSOptionsFromEnvironment()
+	s.ErrorContains(err, "unknown or insecure cipher suite 'TLS_RSA_WITH_RC4_128_SHA'")
+}
+
+func (s *TLSOptionsSuite) TestTLSOptionsMinGreaterThanMax() {
+	opts := TLSOptions{
+		MinVersion: tls.VersionTLS13,
+		MaxVersion: tls.VersionTLS12,
+	}
+	_, err := NewHTTPClientForAccountWithTLS(&accounts.Account{}, time.Second, opts, logging.New())
+	s.ErrorContains(err, "the minimum TLS version (TLS 1.3) is greater than the maximum (TLS 1.2)")
+}
@@ -3,9 +3,11 @@ package config
 // Copyright (C) 2023 by Posit Software, PBC.
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
+	"slices"
 	"strings"
 
 	"github.com/pelletier/go-toml/v2"
@@ -43,6 +45,28 @@ func ListConfigFiles(base util.AbsolutePath) ([]util.AbsolutePath, error) {
 	return dir.Glob("*.toml")
 }
 
+var ErrMultipleConfigs = errors.New("there are multiple configurations; please specify which one to use")
+
+// DiscoverConfigName returns the name of the configuration to use
+// when none was specified. If the project has exactly one
+// configuration, that one is used. If it has none, DefaultConfigName
+// is returned so it can be created. If it has more than one,
+// the caller must choose, and ErrMultipleConfigs is returned.
+func DiscoverConfigName(base util.AbsolutePath) (string, error) {
+	paths, err := ListConfigFiles(base)
+	if err != nil {
+		return "", err
+	}
+	switch len(paths) {
+	case 0:
+		return DefaultConfigName, nil
+	case 1:
+		return strings.TrimSuffix(paths[0].Base(), ".toml"), nil
+	default:
+		return "", ErrMultipleConfigs
+	}
+}
+
 func readLeadingComments(path util.AbsolutePath) ([]string, error) {
 	var comments []string
 	contents, err := path.ReadFile()
@@ -82,9 +106,28 @@ func ValidateFile(path util.AbsolutePath) error {
 	if err != nil {
 		return err
 	}
+	draft, err := isDraftFile(path)
+	if err != nil {
+		return err
+	}
+	if draft {
+		// Drafts may be missing required settings;
+		// they are checked before deploying instead.
+		return validator.ValidatePartialTOMLFile(path)
+	}
 	return validator.ValidateTOMLFile(path)
 }
 
+func isDraftFile(path util.AbsolutePath) (bool, error) {
+	var content map[string]any
+	err := util.ReadTOMLFile(path, &content)
+	if err != nil {
+		return false, err
+	}
+	draft, _ := content["draft"].(bool)
+	return draft, nil
+}
+
 func (cfg *Config) Write(w io.Writer) error {
 	for _, comment := range cfg.Comments {
 		_, err := fmt.Fprintln(w, "#"+comment)
@@ -96,6 +139,23 @@ func (cfg *Config) Write(w io.Writer) error {
 	return enc.Encode(cfg)
 }
 
+// Clone returns a copy of the configuration that
+// can be modified without changing the original.
+func (cfg *Config) Clone() (*Config, error) {
+	buf := new(bytes.Buffer)
+	err := toml.NewEncoder(buf).Encode(cfg)
+	if err != nil {
+		return nil, err
+	}
+	clone := &Config{}
+	err = toml.NewDecoder(buf).Decode(clone)
+	if err != nil {
+		return nil, err
+	}
+	clone.Comments = slices.Clone(cfg.Comments)
+	return clone, nil
+}
+
 func (cfg *Config) WriteFile(path util.AbsolutePath) error {
 	err := path.Dir().MkdirAll(0777)
 	if err != nil {
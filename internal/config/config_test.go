@@ -63,6 +63,27 @@ func (s *ConfigSuite) TestGetConfigPathEmpty() {
 	s.Equal(path, s.cwd.Join(".posit", "publish", "default.toml"))
 }
 
+func (s *ConfigSuite) TestDiscoverConfigNameNone() {
+	name, err := DiscoverConfigName(s.cwd)
+	s.NoError(err)
+	s.Equal(DefaultConfigName, name)
+}
+
+func (s *ConfigSuite) TestDiscoverConfigNameOne() {
+	s.createConfigFile("staging")
+	name, err := DiscoverConfigName(s.cwd)
+	s.NoError(err)
+	s.Equal("staging", name)
+}
+
+func (s *ConfigSuite) TestDiscoverConfigNameMany() {
+	s.createConfigFile("default")
+	s.createConfigFile("staging")
+	name, err := DiscoverConfigName(s.cwd)
+	s.ErrorIs(err, ErrMultipleConfigs)
+	s.Equal("", name)
+}
+
 func (s *ConfigSuite) TestFromFile() {
 	s.createConfigFile("myConfig")
 	path := GetConfigPath(s.cwd, "myConfig")
@@ -85,6 +106,32 @@ func (s *ConfigSuite) TestFromExampleFile() {
 	s.Equal(true, *valuePtr)
 }
 
+func (s *ConfigSuite) TestClone() {
+	realDir, err := util.Getwd(nil)
+	s.NoError(err)
+	path := realDir.Join("..", "schema", "schemas", "config.toml")
+	cfg, err := FromFile(path)
+	s.NoError(err)
+
+	clone, err := cfg.Clone()
+	s.NoError(err)
+	expected := new(bytes.Buffer)
+	s.NoError(cfg.Write(expected))
+	actual := new(bytes.Buffer)
+	s.NoError(clone.Write(actual))
+	s.Equal(expected.String(), actual.String())
+
+	// Changes to the clone don't affect the original.
+	*clone.Connect.Kubernetes.DefaultPyEnvironmentManagement = false
+	clone.Python.Version = "3.99"
+	clone.Files[0] = "changed"
+	clone.Comments = append(clone.Comments, "changed")
+	s.True(*cfg.Connect.Kubernetes.DefaultPyEnvironmentManagement)
+	s.NotEqual("3.99", cfg.Python.Version)
+	s.NotEqual("changed", cfg.Files[0])
+	s.NotEqual(cfg.Comments, clone.Comments)
+}
+
 func (s *ConfigSuite) TestFromFileFillsDefaultsForPython() {
 	configFile := GetConfigPath(s.cwd, "defaults")
 	cfg := New()
@@ -0,0 +1,85 @@
+package config
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/posit-dev/publisher/internal/util"
+)
+
+var ErrFileOutsideProject = errors.New("file patterns must refer to files within the project directory")
+
+// normalizeFilePattern returns a cleaned version of a single `files`
+// entry. Entries keep their gitignore-style meaning: a leading '!'
+// excludes, a leading '/' anchors the pattern to the project directory,
+// and a trailing '/' matches only directories. An absolute path within
+// the project directory is converted to an anchored pattern.
+func normalizeFilePattern(projectDir util.AbsolutePath, pattern string) (string, error) {
+	original := pattern
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" {
+		return "", nil
+	}
+	negate := strings.HasPrefix(pattern, "!")
+	pattern = strings.TrimPrefix(pattern, "!")
+
+	projectPrefix := projectDir.ToSlash() + "/"
+	if strings.HasPrefix(filepath.ToSlash(pattern), projectPrefix) {
+		// Absolute path within the project
+		pattern = "/" + strings.TrimPrefix(filepath.ToSlash(pattern), projectPrefix)
+	} else if filepath.VolumeName(pattern) != "" {
+		// Windows absolute path outside the project. A leading '/'
+		// is not an absolute path; it anchors the pattern.
+		return "", fmt.Errorf("%w: '%s'", ErrFileOutsideProject, original)
+	}
+	// A leading './' also refers to the project directory.
+	anchored := strings.HasPrefix(pattern, "/") || strings.HasPrefix(pattern, "./")
+	dirOnly := strings.HasSuffix(pattern, "/")
+
+	cleaned := path.Clean(strings.TrimLeft(pattern, "/"))
+	if cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", fmt.Errorf("%w: '%s'", ErrFileOutsideProject, original)
+	}
+	if cleaned == "." {
+		// The project directory itself
+		cleaned = "*"
+		anchored = false
+		dirOnly = false
+	}
+	if anchored {
+		cleaned = "/" + cleaned
+	}
+	if dirOnly {
+		cleaned += "/"
+	}
+	if negate {
+		cleaned = "!" + cleaned
+	}
+	return cleaned, nil
+}
+
+// NormalizeFiles cleans the configuration's `files` list so that each
+// entry is a clean, project-relative pattern, and removes blank and
+// duplicate entries. It returns an error wrapping ErrFileOutsideProject
+// if an entry refers to a location outside the project directory.
+func (cfg *Config) NormalizeFiles(projectDir util.AbsolutePath) error {
+	files := []string{}
+	for _, pattern := range cfg.Files {
+		cleaned, err := normalizeFilePattern(projectDir, pattern)
+		if err != nil {
+			return err
+		}
+		if cleaned == "" || slices.Contains(files, cleaned) {
+			continue
+		}
+		files = append(files, cleaned)
+	}
+	cfg.Files = files
+	return nil
+}
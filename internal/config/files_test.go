@@ -0,0 +1,84 @@
+package config
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"testing"
+
+	"github.com/posit-dev/publisher/internal/util"
+	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/suite"
+)
+
+type FilesSuite struct {
+	utiltest.Suite
+	projectDir util.AbsolutePath
+}
+
+func TestFilesSuite(t *testing.T) {
+	suite.Run(t, new(FilesSuite))
+}
+
+func (s *FilesSuite) SetupTest() {
+	fs := afero.NewMemMapFs()
+	cwd, err := util.Getwd(fs)
+	s.NoError(err)
+	s.projectDir = cwd.Join("project")
+}
+
+func (s *FilesSuite) normalize(files ...string) ([]string, error) {
+	cfg := New()
+	cfg.Files = files
+	err := cfg.NormalizeFiles(s.projectDir)
+	return cfg.Files, err
+}
+
+func (s *FilesSuite) TestNormalizeUnchanged() {
+	files := []string{"/app.py", "*.py", "!*.csv", "/data/", "requirements.txt"}
+	normalized, err := s.normalize(files...)
+	s.NoError(err)
+	s.Equal(files, normalized)
+}
+
+func (s *FilesSuite) TestNormalizeClean() {
+	normalized, err := s.normalize(
+		" /app.py ",
+		"//data//",
+		"./lib/util.py",
+		"/lib/../app.py",
+		"",
+		"/.",
+	)
+	s.NoError(err)
+	s.Equal([]string{"/app.py", "/data/", "/lib/util.py", "*"}, normalized)
+}
+
+func (s *FilesSuite) TestNormalizeDuplicates() {
+	normalized, err := s.normalize("/app.py", "*.py", "/app.py", "./app.py", "!*.csv", "!*.csv")
+	s.NoError(err)
+	s.Equal([]string{"/app.py", "*.py", "!*.csv"}, normalized)
+}
+
+func (s *FilesSuite) TestNormalizeAbsoluteWithinProject() {
+	normalized, err := s.normalize(
+		s.projectDir.Join("app.py").String(),
+		"!"+s.projectDir.Join("data", "big.csv").String(),
+	)
+	s.NoError(err)
+	s.Equal([]string{"/app.py", "!/data/big.csv"}, normalized)
+}
+
+func (s *FilesSuite) TestNormalizeTraversal() {
+	for _, pattern := range []string{
+		"../secrets.txt",
+		"/../secrets.txt",
+		"lib/../../secrets.txt",
+		"!../other/",
+		"..",
+	} {
+		_, err := s.normalize("/app.py", pattern)
+		s.ErrorIs(err, ErrFileOutsideProject, pattern)
+		s.ErrorContains(err, pattern)
+	}
+}
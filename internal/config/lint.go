@@ -0,0 +1,202 @@
+package config
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/posit-dev/publisher/internal/bundles/matcher"
+	"github.com/posit-dev/publisher/internal/util"
+	"gopkg.in/yaml.v3"
+)
+
+type LintCode string
+
+const (
+	LintRuntimeForStaticContent LintCode = "runtimeForStaticContent"
+	LintRunAsCurrentUserForAPI  LintCode = "runAsCurrentUserForAPI"
+	LintDescriptionTooLong      LintCode = "descriptionTooLong"
+	LintEntrypointNotIncluded   LintCode = "entrypointNotIncluded"
+	LintEntrypointCaseMismatch  LintCode = "entrypointCaseMismatch"
+	LintSiteEntrypointNotIndex  LintCode = "siteEntrypointNotIndex"
+)
+
+// LintWarning describes a likely mistake in a configuration.
+// Unlike schema or capability errors, warnings don't prevent deployment.
+type LintWarning struct {
+	Code    LintCode `json:"code"`
+	Message string   `json:"message"`
+}
+
+// maxDescriptionLength is the longest description Connect accepts.
+const maxDescriptionLength = 4096
+
+// Lint checks the configuration for common mistakes, without
+// contacting a server. base is the project directory.
+func (c *Config) Lint(base util.AbsolutePath) []LintWarning {
+	warnings := []LintWarning{}
+	warn := func(code LintCode, format string, args ...any) {
+		warnings = append(warnings, LintWarning{
+			Code:    code,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+	if c.Connect != nil && c.Connect.Runtime != nil && c.Type.isStaticContent() {
+		warn(LintRuntimeForStaticContent,
+			"connect.runtime settings have no effect on %s content, which does not run on the server", c.Type)
+	}
+	if c.Connect != nil && c.Connect.Access != nil {
+		racu := c.Connect.Access.RunAsCurrentUser
+		if racu != nil && *racu && c.Type.IsAPIContent() {
+			warn(LintRunAsCurrentUserForAPI,
+				"run_as_current_user can only be used with applications, not %s APIs", c.Type)
+		}
+	}
+	if len(c.Description) > maxDescriptionLength {
+		warn(LintDescriptionTooLong,
+			"the description is %d characters long; the limit is %d characters", len(c.Description), maxDescriptionLength)
+	}
+	if actual, ok := c.EntrypointCaseMismatch(base); ok {
+		warn(LintEntrypointCaseMismatch,
+			"the entrypoint %s does not match the case of the file %s; set entrypoint = \"%s\"",
+			c.Entrypoint, actual, actual)
+	}
+	if projectType, index, ok := c.siteEntrypointNotIndex(base); ok {
+		warn(LintSiteEntrypointNotIndex,
+			"the entrypoint %s is not the index document of this Quarto %s; set entrypoint = \"%s\"",
+			c.Entrypoint, projectType, index)
+	}
+	if !c.entrypointIncluded(base) {
+		warn(LintEntrypointNotIncluded,
+			"the entrypoint %s is not included in the files list", c.Entrypoint)
+	}
+	return warnings
+}
+
+// isStaticContent returns true for content types that are
+// rendered or served as-is, without a running process.
+func (t ContentType) isStaticContent() bool {
+	switch t {
+	case ContentTypeHTML,
+		ContentTypeJupyterNotebook,
+		ContentTypeQuarto,
+		ContentTypeQuartoDeprecated,
+		ContentTypeRMarkdown:
+		return true
+	}
+	return false
+}
+
+// EntrypointCaseMismatch returns the name of the entrypoint file,
+// as it is on disk, if the configured entrypoint matches it only
+// when case is ignored. Servers usually have case-sensitive
+// filesystems, so the entrypoint wouldn't be found once deployed.
+func (c *Config) EntrypointCaseMismatch(base util.AbsolutePath) (string, bool) {
+	if c.Entrypoint == "" || strings.Contains(c.Entrypoint, ":") {
+		return "", false
+	}
+	entrypoint := util.NewRelativePath(filepath.FromSlash(c.Entrypoint), base.Fs())
+	actual, ok, err := util.FindCaseMismatch(base, entrypoint)
+	if err != nil || !ok {
+		return "", false
+	}
+	return actual.ToSlash(), true
+}
+
+// QuartoSiteTypes are the Quarto project types whose documents
+// are all rendered together, starting from the index document.
+var QuartoSiteTypes = []string{"website", "book"}
+
+// QuartoIndexFilenames are the names the index document
+// of a Quarto website or book can have.
+var QuartoIndexFilenames = []string{"index.qmd", "index.md", "index.ipynb", "index.Rmd", "index.rmd"}
+
+type quartoProjectFile struct {
+	Project struct {
+		Type string `yaml:"type"`
+	} `yaml:"project"`
+}
+
+// quartoProjectType returns the type of the Quarto project in base,
+// such as "website" or "book", or "" if there isn't one.
+func quartoProjectType(base util.AbsolutePath) string {
+	for _, name := range []string{"_quarto.yml", "_quarto.yaml"} {
+		content, err := base.Join(name).ReadFile()
+		if err != nil {
+			continue
+		}
+		var project quartoProjectFile
+		err = yaml.Unmarshal(content, &project)
+		if err != nil {
+			return ""
+		}
+		return project.Project.Type
+	}
+	return ""
+}
+
+// siteEntrypointNotIndex checks that the entrypoint of a Quarto
+// website or book is its index document. The other documents are
+// rendered along with it, so they don't need to be listed. If the
+// entrypoint is some other document, it returns the project type and
+// the name of the index document.
+func (c *Config) siteEntrypointNotIndex(base util.AbsolutePath) (string, string, bool) {
+	if c.Type != ContentTypeQuarto && c.Type != ContentTypeQuartoDeprecated {
+		return "", "", false
+	}
+	projectType := quartoProjectType(base)
+	if !slices.Contains(QuartoSiteTypes, projectType) {
+		return "", "", false
+	}
+	entrypoint := path.Clean(filepath.ToSlash(c.Entrypoint))
+	for _, name := range QuartoIndexFilenames {
+		exists, err := base.Join(name).Exists()
+		if err != nil || !exists {
+			continue
+		}
+		if entrypoint == name {
+			return "", "", false
+		}
+		return projectType, name, true
+	}
+	// Without an index document, there's nothing to suggest.
+	return "", "", false
+}
+
+// entrypointIncluded returns false only if the entrypoint is a file
+// in the project directory that is not matched by the files list.
+// Entrypoints that aren't files (like Python module:object references)
+// are not checked.
+func (c *Config) entrypointIncluded(base util.AbsolutePath) bool {
+	if c.Entrypoint == "" || strings.Contains(c.Entrypoint, ":") {
+		return true
+	}
+	entrypointPath := base.Join(c.Entrypoint)
+	exists, err := entrypointPath.Exists()
+	if err != nil || !exists {
+		return true
+	}
+	matchList, err := matcher.NewMatchList(base, c.Files)
+	if err != nil {
+		// Invalid patterns are reported elsewhere.
+		return true
+	}
+	// A file is included if it, or a directory containing it, is
+	// matched by the files list, and neither it nor any directory
+	// containing it is excluded.
+	included := false
+	for path := entrypointPath; len(path.String()) > len(base.String()); path = path.Dir() {
+		m := matchList.Match(path)
+		if m != nil {
+			if m.Exclude {
+				return false
+			}
+			included = true
+		}
+	}
+	return included
+}
@@ -0,0 +1,220 @@
+package config
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/posit-dev/publisher/internal/util"
+	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/suite"
+)
+
+type LintSuite struct {
+	utiltest.Suite
+	projectDir util.AbsolutePath
+}
+
+func TestLintSuite(t *testing.T) {
+	suite.Run(t, new(LintSuite))
+}
+
+func (s *LintSuite) SetupTest() {
+	fs := afero.NewMemMapFs()
+	cwd, err := util.Getwd(fs)
+	s.NoError(err)
+	s.projectDir = cwd.Join("project")
+	s.NoError(s.projectDir.Join("app", "static").MkdirAll(0777))
+	s.NoError(s.projectDir.Join("app.py").WriteFile(nil, 0666))
+	s.NoError(s.projectDir.Join("index.html").WriteFile(nil, 0666))
+	s.NoError(s.projectDir.Join("app", "main.py").WriteFile(nil, 0666))
+}
+
+func (s *LintSuite) codes(warnings []LintWarning) []LintCode {
+	codes := []LintCode{}
+	for _, w := range warnings {
+		codes = append(codes, w.Code)
+	}
+	return codes
+}
+
+func (s *LintSuite) TestLintClean() {
+	cfg := New()
+	cfg.Type = ContentTypePythonDash
+	cfg.Entrypoint = "app.py"
+	cfg.Files = []string{"/app.py", "/requirements.txt"}
+	s.Equal([]LintWarning{}, cfg.Lint(s.projectDir))
+}
+
+func (s *LintSuite) TestLintRuntimeForStaticContent() {
+	maxProcs := int32(3)
+	cfg := New()
+	cfg.Type = ContentTypeHTML
+	cfg.Entrypoint = "index.html"
+	cfg.Files = []string{"/index.html"}
+	cfg.Connect = &Connect{
+		Runtime: &ConnectRuntime{
+			MaxProcesses: &maxProcs,
+		},
+	}
+	warnings := cfg.Lint(s.projectDir)
+	s.Equal([]LintCode{LintRuntimeForStaticContent}, s.codes(warnings))
+	s.Contains(warnings[0].Message, "html")
+
+	// Fine for applications
+	cfg.Type = ContentTypePythonShiny
+	s.Empty(cfg.Lint(s.projectDir))
+}
+
+func (s *LintSuite) TestLintRunAsCurrentUserForAPI() {
+	racu := true
+	cfg := New()
+	cfg.Type = ContentTypePythonFastAPI
+	cfg.Entrypoint = "app.py"
+	cfg.Files = []string{"*.py"}
+	cfg.Connect = &Connect{
+		Access: &ConnectAccess{
+			RunAsCurrentUser: &racu,
+		},
+	}
+	s.Equal([]LintCode{LintRunAsCurrentUserForAPI}, s.codes(cfg.Lint(s.projectDir)))
+
+	racu = false
+	s.Empty(cfg.Lint(s.projectDir))
+}
+
+func (s *LintSuite) TestLintDescriptionTooLong() {
+	cfg := New()
+	cfg.Type = ContentTypePythonDash
+	cfg.Entrypoint = "app.py"
+	cfg.Files = []string{"/app.py"}
+	cfg.Description = strings.Repeat("x", 4096)
+	s.Empty(cfg.Lint(s.projectDir))
+
+	cfg.Description += "x"
+	warnings := cfg.Lint(s.projectDir)
+	s.Equal([]LintCode{LintDescriptionTooLong}, s.codes(warnings))
+	s.Contains(warnings[0].Message, "4097")
+}
+
+func (s *LintSuite) TestLintEntrypointNotIncluded() {
+	cfg := New()
+	cfg.Type = ContentTypePythonDash
+	cfg.Entrypoint = "app.py"
+	cfg.Files = []string{"/requirements.txt"}
+	warnings := cfg.Lint(s.projectDir)
+	s.Equal([]LintCode{LintEntrypointNotIncluded}, s.codes(warnings))
+	s.Contains(warnings[0].Message, "app.py")
+
+	cfg.Files = []string{"*.py", "!app.py"}
+	s.Equal([]LintCode{LintEntrypointNotIncluded}, s.codes(cfg.Lint(s.projectDir)))
+}
+
+func (s *LintSuite) TestLintEntrypointIncludedByDirectory() {
+	cfg := New()
+	cfg.Type = ContentTypePythonFastAPI
+	cfg.Entrypoint = "app/main.py"
+	cfg.Files = []string{"/app/"}
+	s.Empty(cfg.Lint(s.projectDir))
+
+	cfg.Files = []string{"*.py", "!/app/"}
+	s.Equal([]LintCode{LintEntrypointNotIncluded}, s.codes(cfg.Lint(s.projectDir)))
+}
+
+func (s *LintSuite) TestLintEntrypointCaseMismatch() {
+	cfg := New()
+	cfg.Type = ContentTypePythonFastAPI
+	cfg.Entrypoint = "App/Main.py"
+	cfg.Files = []string{"/app/"}
+	warnings := cfg.Lint(s.projectDir)
+	s.Equal([]LintCode{LintEntrypointCaseMismatch}, s.codes(warnings))
+	s.Contains(warnings[0].Message, `entrypoint = "app/main.py"`)
+
+	actual, ok := cfg.EntrypointCaseMismatch(s.projectDir)
+	s.True(ok)
+	s.Equal("app/main.py", actual)
+
+	cfg.Entrypoint = "app/main.py"
+	_, ok = cfg.EntrypointCaseMismatch(s.projectDir)
+	s.False(ok)
+}
+
+// makeQuartoProject creates a Quarto project of the given type
+// in a new directory, with an index and an about page.
+func (s *LintSuite) makeQuartoProject(projectType string) util.AbsolutePath {
+	dir := s.projectDir.Join("site")
+	s.NoError(dir.MkdirAll(0777))
+	quartoYml := "project:\n  type: " + projectType + "\n"
+	s.NoError(dir.Join("_quarto.yml").WriteFile([]byte(quartoYml), 0666))
+	s.NoError(dir.Join("index.qmd").WriteFile(nil, 0666))
+	s.NoError(dir.Join("about.qmd").WriteFile(nil, 0666))
+	return dir
+}
+
+func (s *LintSuite) TestLintWebsiteIndexEntrypoint() {
+	dir := s.makeQuartoProject("website")
+	cfg := New()
+	cfg.Type = ContentTypeQuarto
+	cfg.Entrypoint = "index.qmd"
+	cfg.Files = []string{"/index.qmd", "/about.qmd", "/_quarto.yml", "!/_site/"}
+	s.Empty(cfg.Lint(dir))
+}
+
+func (s *LintSuite) TestLintWebsiteEntrypointNotIndex() {
+	dir := s.makeQuartoProject("website")
+	cfg := New()
+	cfg.Type = ContentTypeQuarto
+	cfg.Entrypoint = "about.qmd"
+	cfg.Files = []string{"/index.qmd", "/about.qmd", "/_quarto.yml", "!/_site/"}
+	warnings := cfg.Lint(dir)
+	s.Equal([]LintCode{LintSiteEntrypointNotIndex}, s.codes(warnings))
+	s.Equal(`the entrypoint about.qmd is not the index document of this Quarto website; set entrypoint = "index.qmd"`,
+		warnings[0].Message)
+}
+
+func (s *LintSuite) TestLintBookEntrypointNotIndex() {
+	dir := s.makeQuartoProject("book")
+	cfg := New()
+	cfg.Type = ContentTypeQuarto
+	cfg.Entrypoint = "about.qmd"
+	cfg.Files = []string{"*"}
+	s.Equal([]LintCode{LintSiteEntrypointNotIndex}, s.codes(cfg.Lint(dir)))
+}
+
+func (s *LintSuite) TestLintQuartoProjectNotSite() {
+	// Documents in default projects are rendered separately.
+	dir := s.makeQuartoProject("default")
+	cfg := New()
+	cfg.Type = ContentTypeQuarto
+	cfg.Entrypoint = "about.qmd"
+	cfg.Files = []string{"*"}
+	s.Empty(cfg.Lint(dir))
+}
+
+func (s *LintSuite) TestLintEntrypointNotAFile() {
+	cfg := New()
+	cfg.Type = ContentTypePythonFlask
+	cfg.Entrypoint = "app:app"
+	cfg.Files = []string{"/requirements.txt"}
+	s.Empty(cfg.Lint(s.projectDir))
+}
+
+func (s *LintSuite) TestLintMultiple() {
+	racu := true
+	cfg := New()
+	cfg.Type = ContentTypeRPlumber
+	cfg.Entrypoint = "app.py"
+	cfg.Description = strings.Repeat("x", 5000)
+	cfg.Connect = &Connect{
+		Access: &ConnectAccess{
+			RunAsCurrentUser: &racu,
+		},
+	}
+	s.Equal([]LintCode{
+		LintRunAsCurrentUserForAPI,
+		LintDescriptionTooLong,
+		LintEntrypointNotIncluded,
+	}, s.codes(cfg.Lint(s.projectDir)))
+}
@@ -12,6 +12,7 @@ const (
 	ContentTypePythonDash       ContentType = "python-dash"
 	ContentTypePythonFastAPI    ContentType = "python-fastapi"
 	ContentTypePythonFlask      ContentType = "python-flask"
+	ContentTypePythonPanel      ContentType = "python-panel"
 	ContentTypePythonShiny      ContentType = "python-shiny"
 	ContentTypePythonStreamlit  ContentType = "python-streamlit"
 	ContentTypeQuartoShiny      ContentType = "quarto-shiny"
@@ -33,6 +34,7 @@ func AllValidContentTypeNames() []string {
 		string(ContentTypePythonDash),
 		string(ContentTypePythonFastAPI),
 		string(ContentTypePythonFlask),
+		string(ContentTypePythonPanel),
 		string(ContentTypePythonShiny),
 		string(ContentTypePythonStreamlit),
 		string(ContentTypeQuartoShiny),
@@ -55,6 +57,7 @@ func (t ContentType) IsPythonContent() bool {
 		ContentTypePythonDash,
 		ContentTypePythonFastAPI,
 		ContentTypePythonFlask,
+		ContentTypePythonPanel,
 		ContentTypePythonShiny,
 		ContentTypePythonStreamlit:
 		return true
@@ -62,6 +65,18 @@ func (t ContentType) IsPythonContent() bool {
 	return false
 }
 
+func (t ContentType) IsRContent() bool {
+	switch t {
+	case
+		ContentTypeRPlumber,
+		ContentTypeRShiny,
+		ContentTypeRMarkdownShiny,
+		ContentTypeRMarkdown:
+		return true
+	}
+	return false
+}
+
 func (t ContentType) IsAPIContent() bool {
 	switch t {
 	case ContentTypePythonFlask,
@@ -78,6 +93,7 @@ func (t ContentType) IsAppContent() bool {
 		ContentTypeRShiny,
 		ContentTypePythonBokeh,
 		ContentTypePythonDash,
+		ContentTypePythonPanel,
 		ContentTypePythonStreamlit:
 		return true
 	}
@@ -90,8 +106,10 @@ type Config struct {
 	Type          ContentType `toml:"type" json:"type"`
 	Entrypoint    string      `toml:"entrypoint" json:"entrypoint,omitempty"`
 	Validate      bool        `toml:"validate" json:"validate"`
+	Draft         bool        `toml:"draft,omitempty" json:"draft,omitempty"`
 	HasParameters bool        `toml:"has_parameters,omitempty" json:"hasParameters"`
 	Files         []string    `toml:"files,multiline" json:"files"`
+	EmptyDirs     []string    `toml:"empty_dirs,omitempty" json:"emptyDirs,omitempty"`
 	Title         string      `toml:"title,omitempty" json:"title,omitempty"`
 	Description   string      `toml:"description,multiline,omitempty" json:"description,omitempty"`
 	ThumbnailFile string      `toml:"thumbnail,omitempty" json:"thumbnail,omitempty"`
@@ -100,6 +118,7 @@ type Config struct {
 	R             *R          `toml:"r,omitempty" json:"r,omitempty"`
 	Jupyter       *Jupyter    `toml:"jupyter,omitempty" json:"jupyter,omitempty"`
 	Quarto        *Quarto     `toml:"quarto,omitempty" json:"quarto,omitempty"`
+	PrePublish    *PrePublish `toml:"pre_publish,omitempty" json:"prePublish,omitempty"`
 	Environment   Environment `toml:"environment,omitempty" json:"environment,omitempty"`
 	Secrets       []string    `toml:"secrets,omitempty" json:"secrets,omitempty"`
 	Schedules     []Schedule  `toml:"schedules,omitempty" json:"schedules,omitempty"`
@@ -120,10 +139,18 @@ type Environment = map[string]string
 
 type Python struct {
 	Version        string `toml:"version" json:"version"`
+	Executable     string `toml:"executable,omitempty" json:"executable,omitempty"`
 	PackageFile    string `toml:"package_file,omitempty" json:"packageFile"`
 	PackageManager string `toml:"package_manager,omitempty" json:"packageManager"`
 }
 
+// ExportsRequirements returns true if the package manager's locked
+// requirements are exported into the bundle when publishing, instead
+// of deploying the package file from the project.
+func (p *Python) ExportsRequirements() bool {
+	return p.PackageManager == "poetry" || p.PackageManager == "pipenv"
+}
+
 type R struct {
 	Version        string `toml:"version" json:"version"`
 	PackageFile    string `toml:"package_file,omitempty" json:"packageFile"`
@@ -138,6 +165,18 @@ type Jupyter struct {
 type Quarto struct {
 	Version string   `toml:"version" json:"version"`
 	Engines []string `toml:"engines" json:"engines"`
+	// The content has Observable JS cells, which run in the
+	// browser, so they don't need Python or R on the server.
+	HasOJS bool `toml:"has_ojs,omitempty" json:"hasOjs,omitempty"`
+}
+
+// PrePublish lists commands, such as a build step,
+// to run in the project directory before bundling.
+type PrePublish struct {
+	Commands []string `toml:"commands,multiline" json:"commands"`
+	// Timeout is the maximum number of seconds each command
+	// may run. If zero, a default is used.
+	Timeout int `toml:"timeout,omitempty" json:"timeout,omitempty"`
 }
 
 type Schedule struct {
@@ -149,10 +188,25 @@ type AccessType string
 
 const (
 	AccessTypeAnonymous AccessType = "all"
-	AccessTypeLoggedIn  AccessType = "logged-in"
+	AccessTypeLoggedIn  AccessType = "logged_in"
 	AccessTypeACL       AccessType = "acl"
 )
 
+var accessTypes = []AccessType{
+	AccessTypeACL,
+	AccessTypeLoggedIn,
+	AccessTypeAnonymous,
+}
+
+func (t AccessType) IsValid() bool {
+	for _, valid := range accessTypes {
+		if t == valid {
+			return true
+		}
+	}
+	return false
+}
+
 type Access struct {
 	Type   AccessType `toml:"type" json:"type"`
 	Users  []User     `toml:"users,omitempty" json:"users,omitempty"`
@@ -174,6 +228,9 @@ type Group struct {
 }
 
 type Connect struct {
+	// Vanity path for the content, such as /sales/dashboard/.
+	// It is set after each deployment.
+	VanityURL  string             `toml:"vanity_url,omitempty" json:"vanityUrl,omitempty"`
 	Access     *ConnectAccess     `toml:"access,omitempty" json:"access,omitempty"`
 	Runtime    *ConnectRuntime    `toml:"runtime,omitempty" json:"runtime,omitempty"`
 	Kubernetes *ConnectKubernetes `toml:"kubernetes,omitempty" json:"kubernetes,omitempty"`
@@ -182,6 +239,9 @@ type Connect struct {
 type ConnectAccess struct {
 	RunAs            string `toml:"run_as,omitempty" json:"runAs,omitempty"`
 	RunAsCurrentUser *bool  `toml:"run_as_current_user,omitempty" json:"runAsCurrentUser,omitempty"`
+	// Who can view the content when it is first created.
+	// Later changes made on the server are kept.
+	InitialAccessType AccessType `toml:"initial_access_type,omitempty" json:"initialAccessType,omitempty"`
 }
 
 type ConnectRuntime struct {
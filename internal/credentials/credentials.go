@@ -7,8 +7,8 @@
 // A distributed write lock is required to ensure threads do not overwrite the credential store.
 //
 // Support for breaking changes to the Credentials schema is supported via version system.
-// The current implementation supports a single version (Version 0), but is designed to be extendable to future versions.
-// For example, adding a new field to Credential.
+// Version 0 records only have an API key; Version 1 added Token and Secret. Records of either version
+// can be read, and new or updated credentials are written in the current version.
 //
 // Migration instructions:
 // - Modify the current version to retain the current Credential structure (i.e., copy the struct of Credential to CredentialV0)
@@ -22,7 +22,8 @@
 // - Credential: The main structure representing a single credential.
 // - CredentialRecord: A structure for storing credential data along with its version for future compatibility.
 // - CredentialsService (interface): A service that provides methods for managing credentials.
-//   - keyringCredentialsService: The service using the system's native keyring.
+//   - keyringCredentialsService: The service using the system's native keyring,
+//     or an encrypted file when POSIT_PUBLISHER_CREDENTIALS_FILE is set.
 //   - fileCredentialsService: Fallback service for persising credentials in file when keyring is not available.
 //
 // Author: Posit Software, PBC
@@ -31,23 +32,80 @@ package credentials
 
 import (
 	"encoding/json"
+	"os"
+	"slices"
+	"strings"
 
 	"github.com/posit-dev/publisher/internal/logging"
 	"github.com/posit-dev/publisher/internal/types"
+	"github.com/posit-dev/publisher/internal/util"
 )
 
 const ServiceName = "Posit Publisher Safe Storage"
 
-const CurrentVersion = 0
+const CurrentVersion = 1
 
 type Credential struct {
 	GUID   string `json:"guid"`
 	Name   string `json:"name"`
 	URL    string `json:"url"`
 	ApiKey string `json:"apiKey"`
+	// Token and Secret are used instead of an API key
+	// by shinyapps.io and Posit Cloud accounts.
+	Token  string `json:"token,omitempty"`
+	Secret string `json:"secret,omitempty"`
 }
 
-type CredentialV0 = Credential
+type CredentialV0 struct {
+	GUID   string `json:"guid"`
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	ApiKey string `json:"apiKey"`
+}
+
+type CredentialV1 = Credential
+
+// CreateCredentialDetails holds the fields of a new or
+// updated Credential. Either ApiKey, or both Token and
+// Secret, must be provided.
+type CreateCredentialDetails struct {
+	Name   string
+	URL    string
+	ApiKey string
+	Token  string
+	Secret string
+}
+
+func (d *CreateCredentialDetails) validate() error {
+	if d.Name == "" || d.URL == "" {
+		return NewIncompleteCredentialError()
+	}
+	if d.ApiKey == "" && (d.Token == "" || d.Secret == "") {
+		return NewIncompleteCredentialError()
+	}
+	return nil
+}
+
+// toCredential returns the Credential with these details,
+// with its URL normalized.
+func (d *CreateCredentialDetails) toCredential(guid string) (*Credential, error) {
+	err := d.validate()
+	if err != nil {
+		return nil, err
+	}
+	normalizedUrl, err := util.NormalizeServerURL(d.URL)
+	if err != nil {
+		return nil, err
+	}
+	return &Credential{
+		GUID:   guid,
+		Name:   d.Name,
+		URL:    normalizedUrl,
+		ApiKey: d.ApiKey,
+		Token:  d.Token,
+		Secret: d.Secret,
+	}, nil
+}
 
 func (c *Credential) ConflictCheck(compareWith Credential) error {
 	if compareWith.URL == c.URL {
@@ -59,6 +117,37 @@ func (c *Credential) ConflictCheck(compareWith Credential) error {
 	return nil
 }
 
+// findConflicts groups credentials whose URLs refer to the same server,
+// such as credentials stored before URLs were normalized, or with
+// an /__api__ suffix. Credentials without conflicts are omitted.
+func findConflicts(creds []Credential) [][]Credential {
+	byURL := make(map[string][]Credential)
+	for _, cred := range creds {
+		key, err := util.NormalizeServerURL(cred.URL)
+		if err != nil {
+			key = cred.URL
+		}
+		byURL[key] = append(byURL[key], cred)
+	}
+	keys := make([]string, 0, len(byURL))
+	for key, group := range byURL {
+		if len(group) > 1 {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	conflicts := make([][]Credential, 0, len(keys))
+	for _, key := range keys {
+		group := byURL[key]
+		slices.SortFunc(group, func(a, b Credential) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+		conflicts = append(conflicts, group)
+	}
+	return conflicts
+}
+
 type CredentialRecord struct {
 	GUID    string          `json:"guid"`
 	Version uint            `json:"version"`
@@ -75,6 +164,17 @@ func (cr *CredentialRecord) ToCredential() (*Credential, error) {
 		if err := json.Unmarshal(cr.Data, &cred); err != nil {
 			return nil, NewCorruptedError(cr.GUID)
 		}
+		return &Credential{
+			GUID:   cred.GUID,
+			Name:   cred.Name,
+			URL:    cred.URL,
+			ApiKey: cred.ApiKey,
+		}, nil
+	case 1:
+		var cred CredentialV1
+		if err := json.Unmarshal(cr.Data, &cred); err != nil {
+			return nil, NewCorruptedError(cr.GUID)
+		}
 		return &cred, nil
 	default:
 		return nil, NewVersionError(cr.Version)
@@ -85,18 +185,34 @@ type CredentialsService interface {
 	Delete(guid string) error
 	Get(guid string) (*Credential, error)
 	List() ([]Credential, error)
-	Set(name string, url string, ak string) (*Credential, error)
+	Set(details CreateCredentialDetails) (*Credential, error)
+	Update(guid string, details CreateCredentialDetails) (*Credential, error)
+	Reset() error
+	FindConflicts() ([][]Credential, error)
 }
 
 // The main credentials service constructor that determines if the system's keyring is available to be used,
 // if not, returns a file based credentials service.
 func NewCredentialsService(log logging.Logger) (CredentialsService, error) {
-	krService := NewKeyringCredentialsService(log)
-	if krService.IsSupported() {
-		return krService, nil
+	store, err := encryptedFileStoreFromEnv()
+	if err != nil {
+		return nil, types.NewAgentError(types.ErrorCredentialServiceUnavailable, err, nil)
+	}
+	if store != nil {
+		log.Debug("Using encrypted file credentials service", "env", CredentialsFileEnvVar)
+		return &keyringCredentialsService{log: log, store: store}, nil
+	}
+
+	if os.Getenv(CredentialsDirEnvVar) != "" {
+		log.Debug("Using file managed credentials service", "env", CredentialsDirEnvVar)
+	} else {
+		krService := NewKeyringCredentialsService(log)
+		if krService.IsSupported() {
+			return krService, nil
+		}
+		log.Debug("Fallback to file managed credentials service due to unavailable system keyring")
 	}
 
-	log.Debug("Fallback to file managed credentials service due to unavailable system keyring")
 	fcService, err := NewFileCredentialsService(log)
 	if err != nil {
 		return nil, types.NewAgentError(types.ErrorCredentialServiceUnavailable, err, nil)
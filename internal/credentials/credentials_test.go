@@ -4,9 +4,11 @@ package credentials
 
 import (
 	"errors"
+	"runtime"
 	"testing"
 
 	"github.com/posit-dev/publisher/internal/logging/loggingtest"
+	"github.com/posit-dev/publisher/internal/util"
 	"github.com/posit-dev/publisher/internal/util/utiltest"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/suite"
@@ -75,6 +77,49 @@ func (s *CredentialsServiceTestSuite) TestCredentialRecord() {
 	})
 }
 
+func (s *CredentialsServiceTestSuite) TestCredentialRecordV1() {
+	record := CredentialRecord{
+		GUID:    "18cd5640-bee5-4b2a-992a-a2725ab6103d",
+		Version: 1,
+		Data: []byte(`
+		{"guid":"18cd5640-bee5-4b2a-992a-a2725ab6103d","name":"friedtofu",
+		"url": "https://api.shinyapps.io","apiKey":"","token":"abc","secret":"def"}`),
+	}
+
+	credResult, err := record.ToCredential()
+	s.NoError(err)
+	s.Equal(credResult, &Credential{
+		GUID:   "18cd5640-bee5-4b2a-992a-a2725ab6103d",
+		Name:   "friedtofu",
+		URL:    "https://api.shinyapps.io",
+		Token:  "abc",
+		Secret: "def",
+	})
+}
+
+func (s *CredentialsServiceTestSuite) TestCreateCredentialDetailsValidate() {
+	testCases := map[string]struct {
+		details CreateCredentialDetails
+		valid   bool
+	}{
+		"api key":          {CreateCredentialDetails{Name: "a", URL: "https://a.example.com", ApiKey: "12345"}, true},
+		"token and secret": {CreateCredentialDetails{Name: "a", URL: "https://a.example.com", Token: "abc", Secret: "def"}, true},
+		"neither":          {CreateCredentialDetails{Name: "a", URL: "https://a.example.com"}, false},
+		"token only":       {CreateCredentialDetails{Name: "a", URL: "https://a.example.com", Token: "abc"}, false},
+		"secret only":      {CreateCredentialDetails{Name: "a", URL: "https://a.example.com", Secret: "def"}, false},
+		"no name":          {CreateCredentialDetails{URL: "https://a.example.com", ApiKey: "12345"}, false},
+		"no URL":           {CreateCredentialDetails{Name: "a", ApiKey: "12345"}, false},
+	}
+	for name, tc := range testCases {
+		err := tc.details.validate()
+		if tc.valid {
+			s.NoError(err, name)
+		} else {
+			s.IsType(&IncompleteCredentialError{}, err, name)
+		}
+	}
+}
+
 func (s *CredentialsServiceTestSuite) TestCredentialRecord_CorruptedErr() {
 	record := CredentialRecord{
 		GUID:    "18cd5640-bee5-4b2a-992a-a2725ab6103d",
@@ -124,3 +169,75 @@ func (s *CredentialsServiceTestSuite) TestNewCredentialsService_KeyringErrFallba
 	s.NoError(err)
 	s.Implements((*CredentialsService)(nil), credservice)
 }
+
+func (s *CredentialsServiceTestSuite) TestNewCredentialsService_DirOverride() {
+	_, filename, _, ok := runtime.Caller(0)
+	s.True(ok)
+	dir := util.NewAbsolutePath(filename, nil).Dir().Join("testdata", "dir")
+	s.T().Setenv(CredentialsDirEnvVar, dir.String())
+
+	// The override applies even when the keyring is available.
+	keyring.MockInit()
+	s.log.On("Debug", "Using file managed credentials service", "env", CredentialsDirEnvVar).Return()
+
+	credservice, err := NewCredentialsService(s.log)
+	s.NoError(err)
+	s.IsType(&fileCredentialsService{}, credservice)
+
+	creds, err := credservice.List()
+	s.NoError(err)
+	s.Equal([]Credential{
+		{
+			GUID:   "6e7a2ad1-0b6f-4a5c-9d1e-6b0b2a4f5c3d",
+			Name:   "fixture",
+			URL:    "https://fixture.connect-server:3939/connect",
+			ApiKey: "abcdeC2aqbh7dg8TO43XPu7r56YDh005",
+		},
+	}, creds)
+}
+
+func (s *CredentialsServiceTestSuite) TestNewCredentialsService_EncryptedFile() {
+	fsys = afero.NewMemMapFs()
+	defer func() { fsys = afero.NewOsFs() }()
+	s.T().Setenv(CredentialsFileEnvVar, "/ci/credentials.json")
+	s.T().Setenv(CredentialsPassphraseEnvVar, "hunter2")
+
+	// The encrypted file is used even when the keyring is available.
+	keyring.MockInit()
+	s.log.On("Debug", "Using encrypted file credentials service", "env", CredentialsFileEnvVar).Return()
+
+	credservice, err := NewCredentialsService(s.log)
+	s.NoError(err)
+	ks, ok := credservice.(*keyringCredentialsService)
+	s.True(ok)
+	s.IsType(&encryptedFileStore{}, ks.store)
+}
+
+func (s *CredentialsServiceTestSuite) TestNewCredentialsService_EncryptedFileAltEnvVars() {
+	fsys = afero.NewMemMapFs()
+	defer func() { fsys = afero.NewOsFs() }()
+	s.T().Setenv(CredentialsFileEnvVar, "")
+	s.T().Setenv(CredentialsPassphraseEnvVar, "")
+	s.T().Setenv("POSIT_CREDENTIALS_FILE", "/ci/credentials.json")
+	s.T().Setenv("POSIT_CREDENTIALS_PASSPHRASE", "hunter2")
+
+	keyring.MockInit()
+	s.log.On("Debug", "Using encrypted file credentials service", "env", CredentialsFileEnvVar).Return()
+
+	credservice, err := NewCredentialsService(s.log)
+	s.NoError(err)
+	ks, ok := credservice.(*keyringCredentialsService)
+	s.True(ok)
+	store, ok := ks.store.(*encryptedFileStore)
+	s.True(ok)
+	s.Equal("/ci/credentials.json", store.path.String())
+	s.Equal("hunter2", store.passphrase)
+}
+
+func (s *CredentialsServiceTestSuite) TestNewCredentialsService_EncryptedFileNoPassphrase() {
+	s.T().Setenv(CredentialsFileEnvVar, "/ci/credentials.json")
+	s.T().Setenv(CredentialsPassphraseEnvVar, "")
+
+	_, err := NewCredentialsService(s.log)
+	s.ErrorContains(err, "POSIT_PUBLISHER_CREDENTIALS_PASSPHRASE must be set")
+}
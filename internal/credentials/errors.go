@@ -87,5 +87,5 @@ func NewIncompleteCredentialError() *IncompleteCredentialError {
 }
 
 func (e *IncompleteCredentialError) Error() string {
-	return "New credentials require non-empty Name, URL and Api Key fields"
+	return "New credentials require non-empty Name and URL fields, and either an Api Key or a Token and Secret"
 }
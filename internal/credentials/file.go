@@ -3,6 +3,7 @@
 package credentials
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"sync"
@@ -16,17 +17,51 @@ import (
 
 var fsys = afero.NewOsFs()
 
+// userHomeDir is replaceable for testing.
+var userHomeDir = util.UserHomeDir
+
+// CredentialsDirEnvVar names an environment variable that overrides
+// the directory containing the credentials file. When set, the file
+// is always used, even if the system keyring is available.
+const CredentialsDirEnvVar = "POSIT_PUBLISHER_CREDENTIALS_DIR"
+
+var ErrNoHomeDir = errors.New("cannot determine your home directory, where the credentials file is stored; set the HOME environment variable (USERPROFILE on Windows), or " + CredentialsDirEnvVar)
+
 const ondiskFilename = ".connect-credentials"
 
 type fileCredential struct {
 	GUID    string `toml:"guid"`
 	Version uint   `toml:"version"`
 	URL     string `toml:"url"`
-	ApiKey  string `toml:"api_key"`
+	ApiKey  string `toml:"api_key,omitempty"`
+	Token   string `toml:"token,omitempty"`
+	Secret  string `toml:"secret,omitempty"`
 }
 
 func (cr *fileCredential) IsValid() bool {
-	return cr.URL != "" && cr.ApiKey != ""
+	return cr.URL != "" && (cr.ApiKey != "" || (cr.Token != "" && cr.Secret != ""))
+}
+
+func newFileCredential(cred *Credential) fileCredential {
+	return fileCredential{
+		GUID:    cred.GUID,
+		Version: CurrentVersion,
+		URL:     cred.URL,
+		ApiKey:  cred.ApiKey,
+		Token:   cred.Token,
+		Secret:  cred.Secret,
+	}
+}
+
+func (cr *fileCredential) toCredential(name string) Credential {
+	return Credential{
+		Name:   name,
+		GUID:   cr.GUID,
+		URL:    cr.URL,
+		ApiKey: cr.ApiKey,
+		Token:  cr.Token,
+		Secret: cr.Secret,
+	}
 }
 
 type fileCredentials struct {
@@ -42,27 +77,15 @@ func newFileCredentials() fileCredentials {
 func (fcs *fileCredentials) CredentialsList() []Credential {
 	list := []Credential{}
 	for credName, fileCred := range fcs.Credentials {
-		list = append(list, Credential{
-			Name:   credName,
-			GUID:   fileCred.GUID,
-			URL:    fileCred.URL,
-			ApiKey: fileCred.ApiKey,
-		})
+		list = append(list, fileCred.toCredential(credName))
 	}
 	return list
 }
 
 func (fcs *fileCredentials) CredentialByGuid(guid string) (Credential, error) {
-	var cred Credential
 	for credName, fileCred := range fcs.Credentials {
 		if fileCred.GUID == guid {
-			cred = Credential{
-				Name:   credName,
-				GUID:   fileCred.GUID,
-				URL:    fileCred.URL,
-				ApiKey: fileCred.ApiKey,
-			}
-			return cred, nil
+			return fileCred.toCredential(credName), nil
 		}
 	}
 	return Credential{}, NewNotFoundError(guid)
@@ -83,12 +106,11 @@ func NewFileCredentialsService(log logging.Logger) (*fileCredentialsService, err
 		log: log,
 	}
 
-	// Set home dir credentials file path
-	homeDir, err := util.UserHomeDir(fsys)
+	dir, err := credentialsDir()
 	if err != nil {
 		return nil, err
 	}
-	fservice.credsFilepath = homeDir.Join(ondiskFilename)
+	fservice.credsFilepath = dir.Join(ondiskFilename)
 
 	// Verify file can be modified, will create if not exists
 	err = fservice.setup()
@@ -99,12 +121,26 @@ func NewFileCredentialsService(log logging.Logger) (*fileCredentialsService, err
 	return fservice, nil
 }
 
-func (c *fileCredentialsService) Set(name, url, ak string) (*Credential, error) {
+// credentialsDir returns the directory named by CredentialsDirEnvVar,
+// if set, or the user's home directory.
+func credentialsDir() (util.AbsolutePath, error) {
+	if dir := os.Getenv(CredentialsDirEnvVar); dir != "" {
+		return util.NewPath(dir, fsys).Abs()
+	}
+	homeDir, err := userHomeDir(fsys)
+	if err != nil {
+		return util.AbsolutePath{}, fmt.Errorf("%w: %w", ErrNoHomeDir, err)
+	}
+	return homeDir, nil
+}
+
+func (c *fileCredentialsService) Set(details CreateCredentialDetails) (*Credential, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if name == "" || url == "" || ak == "" {
-		return nil, NewIncompleteCredentialError()
+	cred, err := details.toCredential(uuid.New().String())
+	if err != nil {
+		return nil, err
 	}
 
 	creds, err := c.load()
@@ -112,39 +148,62 @@ func (c *fileCredentialsService) Set(name, url, ak string) (*Credential, error)
 		return nil, err
 	}
 
-	normalizedUrl, err := util.NormalizeServerURL(url)
+	err = c.checkForConflicts(creds, *cred)
 	if err != nil {
+		c.log.Debug("Conflicts storing new credential to file", "error", err.Error(), "filename", c.credsFilepath.String())
 		return nil, err
 	}
 
-	guid := uuid.New().String()
-	cred := Credential{
-		GUID:   guid,
-		Name:   name,
-		URL:    normalizedUrl,
-		ApiKey: ak,
+	creds.Credentials[cred.Name] = newFileCredential(cred)
+
+	err = c.saveFile(creds)
+	if err != nil {
+		c.log.Debug("Could not update credentials file", "error", err.Error(), "filename", c.credsFilepath.String())
+		return nil, err
 	}
 
-	err = c.checkForConflicts(creds, cred)
+	return cred, nil
+}
+
+func (c *fileCredentialsService) Update(guid string, details CreateCredentialDetails) (*Credential, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cred, err := details.toCredential(guid)
 	if err != nil {
-		c.log.Debug("Conflicts storing new credential to file", "error", err.Error(), "filename", c.credsFilepath.String())
 		return nil, err
 	}
 
-	creds.Credentials[name] = fileCredential{
-		GUID:    guid,
-		Version: CurrentVersion,
-		URL:     normalizedUrl,
-		ApiKey:  ak,
+	creds, err := c.load()
+	if err != nil {
+		c.log.Debug("Cannot update credential, error loading credentials from file", "error", err.Error(), "filename", c.credsFilepath.String())
+		return nil, err
 	}
 
+	existing, err := creds.CredentialByGuid(guid)
+	if err != nil {
+		c.log.Debug("Cannot update credential that does not exist", "error", err.Error(), "filename", c.credsFilepath.String())
+		return nil, err
+	}
+
+	err = c.checkForConflicts(creds, *cred)
+	if err != nil {
+		c.log.Debug("Conflicts updating credential in file", "error", err.Error(), "filename", c.credsFilepath.String())
+		return nil, err
+	}
+
+	// Credentials are keyed by name in the file, so a rename
+	// replaces the entry.
+	creds.RemoveByName(existing.Name)
+	creds.Credentials[cred.Name] = newFileCredential(cred)
+
 	err = c.saveFile(creds)
 	if err != nil {
 		c.log.Debug("Could not update credentials file", "error", err.Error(), "filename", c.credsFilepath.String())
 		return nil, err
 	}
 
-	return &cred, nil
+	return cred, nil
 }
 
 func (c *fileCredentialsService) Get(guid string) (*Credential, error) {
@@ -207,6 +266,29 @@ func (c *fileCredentialsService) Delete(guid string) error {
 	return nil
 }
 
+// Reset removes all Credentials from the file. It can be used
+// to recover when the file is corrupted.
+func (c *fileCredentialsService) Reset() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	err := c.saveFile(newFileCredentials())
+	if err != nil {
+		c.log.Debug("Could not reset credentials file", "error", err.Error(), "filename", c.credsFilepath.String())
+		return err
+	}
+	return nil
+}
+
+// FindConflicts returns groups of Credentials for the same server.
+func (c *fileCredentialsService) FindConflicts() ([][]Credential, error) {
+	creds, err := c.List()
+	if err != nil {
+		return nil, err
+	}
+	return findConflicts(creds), nil
+}
+
 func (c *fileCredentialsService) setup() error {
 	_, err := c.credsFilepath.Stat()
 	if os.IsNotExist(err) {
@@ -271,6 +353,10 @@ func (c *fileCredentialsService) normalizeAll(creds *fileCredentials) error {
 func (c *fileCredentialsService) checkForConflicts(creds fileCredentials, newCred Credential) error {
 	// Check if URL or name are already used by another credential
 	for _, cred := range creds.CredentialsList() {
+		if cred.GUID == newCred.GUID {
+			// A credential being updated doesn't conflict with itself.
+			continue
+		}
 		err := cred.ConflictCheck(newCred)
 		if err != nil {
 			return err
@@ -3,6 +3,7 @@
 package credentials
 
 import (
+	"errors"
 	"os"
 	"runtime"
 	"testing"
@@ -93,6 +94,39 @@ func (s *FileCredentialsServiceSuite) TestNewFileCredentialsService() {
 	})
 }
 
+func (s *FileCredentialsServiceSuite) TestNewFileCredentialsServiceNoHomeDir() {
+	userHomeDir = func(afero.Fs) (util.AbsolutePath, error) {
+		return util.AbsolutePath{}, errors.New("$HOME is not defined")
+	}
+	defer func() { userHomeDir = util.UserHomeDir }()
+
+	fcs, err := NewFileCredentialsService(s.loggerMock)
+	s.Nil(fcs)
+	s.ErrorIs(err, ErrNoHomeDir)
+	s.ErrorContains(err, "$HOME is not defined")
+}
+
+func (s *FileCredentialsServiceSuite) TestNewFileCredentialsServiceDirOverride() {
+	fsys = afero.NewMemMapFs()
+	defer func() { fsys = afero.NewOsFs() }()
+
+	dir := util.NewAbsolutePath("/fixtures", fsys)
+	s.NoError(dir.MkdirAll(0700))
+	s.T().Setenv(CredentialsDirEnvVar, dir.String())
+	userHomeDir = func(afero.Fs) (util.AbsolutePath, error) {
+		return util.AbsolutePath{}, errors.New("home directory should not be used")
+	}
+	defer func() { userHomeDir = util.UserHomeDir }()
+
+	fcs, err := NewFileCredentialsService(s.loggerMock)
+	s.NoError(err)
+	s.Equal(dir.Join(".connect-credentials"), fcs.credsFilepath)
+
+	exists, err := fcs.credsFilepath.Exists()
+	s.NoError(err)
+	s.True(exists)
+}
+
 func (s *FileCredentialsServiceSuite) TestSetupService() {
 	cs := &fileCredentialsService{
 		log:           s.loggerMock,
@@ -385,7 +419,7 @@ func (s *FileCredentialsServiceSuite) TestSet() {
 		},
 	})
 
-	newcred, err := cs.Set("newcred", "https://b2.connect-server:3939/connect", "abcdeC2aqbh7dg8TO43XPu7r56YDh002")
+	newcred, err := cs.Set(CreateCredentialDetails{Name: "newcred", URL: "https://b2.connect-server:3939/connect", ApiKey: "abcdeC2aqbh7dg8TO43XPu7r56YDh002"})
 	s.NoError(err)
 
 	s.Equal(newcred.Name, "newcred")
@@ -406,14 +440,14 @@ func (s *FileCredentialsServiceSuite) TestSet() {
 			},
 			"newcred": {
 				GUID:    newcred.GUID,
-				Version: 0,
+				Version: 1,
 				URL:     "https://b2.connect-server:3939/connect",
 				ApiKey:  "abcdeC2aqbh7dg8TO43XPu7r56YDh002",
 			},
 		},
 	})
 
-	newcred2, err := cs.Set("brand new cred wspaces", "https://b3.connect-server:3939/connect", "abcdeC2aqbh7dg8TO43XPu7r56YDh003")
+	newcred2, err := cs.Set(CreateCredentialDetails{Name: "brand new cred wspaces", URL: "https://b3.connect-server:3939/connect", ApiKey: "abcdeC2aqbh7dg8TO43XPu7r56YDh003"})
 	s.NoError(err)
 
 	s.Equal(newcred2.Name, "brand new cred wspaces")
@@ -434,13 +468,13 @@ func (s *FileCredentialsServiceSuite) TestSet() {
 			},
 			"newcred": {
 				GUID:    newcred.GUID,
-				Version: 0,
+				Version: 1,
 				URL:     "https://b2.connect-server:3939/connect",
 				ApiKey:  "abcdeC2aqbh7dg8TO43XPu7r56YDh002",
 			},
 			"brand new cred wspaces": {
 				GUID:    newcred2.GUID,
-				Version: 0,
+				Version: 1,
 				URL:     "https://b3.connect-server:3939/connect",
 				ApiKey:  "abcdeC2aqbh7dg8TO43XPu7r56YDh003",
 			},
@@ -461,9 +495,9 @@ func (s *FileCredentialsServiceSuite) TestSet_BlankDataErr() {
 	}
 
 	for _, params := range testCases {
-		_, err := cs.Set(params[0], params[1], params[2])
+		_, err := cs.Set(CreateCredentialDetails{Name: params[0], URL: params[1], ApiKey: params[2]})
 		s.Error(err)
-		s.Equal(err.Error(), "New credentials require non-empty Name, URL and Api Key fields")
+		s.Equal(err.Error(), "New credentials require non-empty Name and URL fields, and either an Api Key or a Token and Secret")
 
 		creds, err := cs.load()
 		s.NoError(err)
@@ -503,7 +537,7 @@ func (s *FileCredentialsServiceSuite) TestSet_ConflictErr() {
 		expectedErrMessage := params[3]
 		s.loggerMock.On("Debug", "Conflicts storing new credential to file", "error", expectedErrMessage, "filename", cs.credsFilepath.String()).Return()
 
-		_, err := cs.Set(params[0], params[1], params[2])
+		_, err := cs.Set(CreateCredentialDetails{Name: params[0], URL: params[1], ApiKey: params[2]})
 		s.Error(err)
 		s.loggerMock.AssertExpectations(s.T())
 
@@ -523,3 +557,155 @@ func (s *FileCredentialsServiceSuite) TestSet_ConflictErr() {
 		})
 	}
 }
+
+func (s *FileCredentialsServiceSuite) TestUpdate() {
+	cs := &fileCredentialsService{
+		log:           s.loggerMock,
+		credsFilepath: s.testdata.Join("testdelete.toml"),
+	}
+
+	cred, err := cs.Update("79077898-7e26-4909-9eb7-596d1a6d0b6f", CreateCredentialDetails{Name: "renamed", URL: "https://b2.connect-server:3939/connect/", ApiKey: "newkey"})
+	s.NoError(err)
+	s.Equal(&Credential{
+		GUID:   "79077898-7e26-4909-9eb7-596d1a6d0b6f",
+		Name:   "renamed",
+		URL:    "https://b2.connect-server:3939/connect",
+		ApiKey: "newkey",
+	}, cred)
+
+	creds, err := cs.load()
+	s.NoError(err)
+	s.Equal(creds, fileCredentials{
+		Credentials: map[string]fileCredential{
+			"tokeep": {
+				GUID:    "18cd5640-bee5-4b2a-992a-a2725ab6103d",
+				Version: 0,
+				URL:     "https://a1.connect-server:3939/connect",
+				ApiKey:  "abcdeC2aqbh7dg8TO43XPu7r56YDh000",
+			},
+			"renamed": {
+				GUID:    "79077898-7e26-4909-9eb7-596d1a6d0b6f",
+				Version: 1,
+				URL:     "https://b2.connect-server:3939/connect",
+				ApiKey:  "newkey",
+			},
+			"alsotokeep": {
+				GUID:    "3bb375e4-6f01-4fd6-942a-ac32a5e4d7cc",
+				Version: 0,
+				URL:     "https://c3.connect-server:3939/connect",
+				ApiKey:  "abcdeC2aqbh7dg8TO43XPu7r56YDh003",
+			},
+		},
+	})
+}
+
+func (s *FileCredentialsServiceSuite) TestUpdate_NotFoundErr() {
+	cs := &fileCredentialsService{
+		log:           s.loggerMock,
+		credsFilepath: s.testdata.Join("testdelete.toml"),
+	}
+
+	s.loggerMock.On("Debug", "Cannot update credential that does not exist", "error", "credential not found: not-a-guid", "filename", cs.credsFilepath.String()).Return()
+
+	_, err := cs.Update("not-a-guid", CreateCredentialDetails{Name: "newname", URL: "https://d4.connect-server:3939/connect", ApiKey: "newkey"})
+	s.IsType(&NotFoundError{}, err)
+	s.loggerMock.AssertExpectations(s.T())
+}
+
+func (s *FileCredentialsServiceSuite) TestUpdate_ConflictErr() {
+	cs := &fileCredentialsService{
+		log:           s.loggerMock,
+		credsFilepath: s.testdata.Join("testdelete.toml"),
+	}
+
+	expectedErrMessage := "Name value conflicts with existing credential (tokeep) URL: https://a1.connect-server:3939/connect"
+	s.loggerMock.On("Debug", "Conflicts updating credential in file", "error", expectedErrMessage, "filename", cs.credsFilepath.String()).Return()
+
+	_, err := cs.Update("79077898-7e26-4909-9eb7-596d1a6d0b6f", CreateCredentialDetails{Name: "tokeep", URL: "https://b2.connect-server:3939/connect", ApiKey: "newkey"})
+	s.IsType(&NameCollisionError{}, err)
+	s.loggerMock.AssertExpectations(s.T())
+
+	cred, err := cs.Get("79077898-7e26-4909-9eb7-596d1a6d0b6f")
+	s.NoError(err)
+	s.Equal("willdelete", cred.Name)
+}
+
+func (s *FileCredentialsServiceSuite) TestSet_TokenSecret() {
+	cs := &fileCredentialsService{
+		log:           s.loggerMock,
+		credsFilepath: s.testdata.Join("testset.toml"),
+	}
+
+	newcred, err := cs.Set(CreateCredentialDetails{Name: "cloud", URL: "https://api.posit.cloud", Token: "abc", Secret: "def"})
+	s.NoError(err)
+
+	creds, err := cs.load()
+	s.NoError(err)
+	s.Equal(fileCredential{
+		GUID:    newcred.GUID,
+		Version: CurrentVersion,
+		URL:     "https://api.posit.cloud",
+		Token:   "abc",
+		Secret:  "def",
+	}, creds.Credentials["cloud"])
+
+	cred, err := cs.Get(newcred.GUID)
+	s.NoError(err)
+	s.Equal(newcred, cred)
+}
+
+func (s *FileCredentialsServiceSuite) TestReset() {
+	path := s.testdata.Join("testreset.toml")
+	err := path.WriteFile([]byte("[credentials.bad\n"), 0644)
+	s.NoError(err)
+	defer path.Remove()
+
+	cs := &fileCredentialsService{
+		log:           s.loggerMock,
+		credsFilepath: path,
+	}
+	s.loggerMock.On("Debug", "Error loading credentials from file", "error", mock.Anything, "filename", path.String()).Return()
+	_, err = cs.List()
+	s.Error(err)
+
+	err = cs.Reset()
+	s.NoError(err)
+	creds, err := cs.List()
+	s.NoError(err)
+	s.Equal([]Credential{}, creds)
+}
+
+func (s *FileCredentialsServiceSuite) TestFindConflicts() {
+	path := s.testdata.Join("testconflicts.toml")
+	err := path.WriteFile([]byte(`[credentials.imported]
+guid = "1"
+version = 1
+url = "https://connect.example.com/__api__"
+api_key = "12345"
+
+[credentials.mine]
+guid = "2"
+version = 1
+url = "https://connect.example.com/"
+api_key = "67890"
+
+[credentials.other]
+guid = "3"
+version = 1
+url = "https://other.example.com"
+api_key = "abcde"
+`), 0644)
+	s.NoError(err)
+	defer path.Remove()
+
+	cs := &fileCredentialsService{
+		log:           s.loggerMock,
+		credsFilepath: path,
+	}
+	conflicts, err := cs.FindConflicts()
+	s.NoError(err)
+	s.Len(conflicts, 1)
+	s.Len(conflicts[0], 2)
+	s.Equal("imported", conflicts[0][0].Name)
+	s.Equal("mine", conflicts[0][1].Name)
+}
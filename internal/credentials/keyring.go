@@ -8,17 +8,17 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/posit-dev/publisher/internal/logging"
-	"github.com/posit-dev/publisher/internal/util"
-	"github.com/zalando/go-keyring"
 )
 
 type keyringCredentialsService struct {
-	log logging.Logger
+	log   logging.Logger
+	store tableStore
 }
 
 func NewKeyringCredentialsService(log logging.Logger) *keyringCredentialsService {
 	return &keyringCredentialsService{
-		log: log,
+		log:   log,
+		store: keyringStore{},
 	}
 }
 
@@ -84,47 +84,74 @@ func (ks *keyringCredentialsService) List() ([]Credential, error) {
 
 // Set creates a Credential.
 // A guid is assigned to the Credential using the UUIDv4 specification.
-func (ks *keyringCredentialsService) Set(name string, url string, ak string) (*Credential, error) {
+func (ks *keyringCredentialsService) Set(details CreateCredentialDetails) (*Credential, error) {
+	cred, err := details.toCredential(uuid.New().String())
+	if err != nil {
+		return nil, err
+	}
+
 	table, err := ks.load()
 	if err != nil {
 		return nil, err
 	}
 
-	normalizedUrl, err := util.NormalizeServerURL(url)
+	err = ks.checkForConflicts(&table, cred)
 	if err != nil {
 		return nil, err
 	}
 
-	guid := uuid.New().String()
-	cred := Credential{
-		GUID:   guid,
-		Name:   name,
-		URL:    normalizedUrl,
-		ApiKey: ak,
+	err = ks.saveCredential(table, cred)
+	if err != nil {
+		return nil, err
 	}
+	return cred, nil
+}
 
-	err = ks.checkForConflicts(&table, &cred)
+// Update modifies the Credential with the given guid, keeping its guid.
+// If lookup by guid fails, a NotFoundError is returned.
+func (ks *keyringCredentialsService) Update(guid string, details CreateCredentialDetails) (*Credential, error) {
+	cred, err := details.toCredential(guid)
 	if err != nil {
 		return nil, err
 	}
 
-	raw, err := json.Marshal(cred)
+	table, err := ks.load()
 	if err != nil {
-		return nil, fmt.Errorf("error marshalling credential: %v", err)
+		return nil, err
 	}
 
-	table[guid] = CredentialRecord{
-		GUID:    guid,
-		Version: CurrentVersion,
-		Data:    json.RawMessage(raw),
+	_, exists := table[guid]
+	if !exists {
+		ks.log.Debug("Credential does not exist", "credential", guid)
+		return nil, NewNotFoundError(guid)
 	}
 
-	err = ks.save(table)
+	err = ks.checkForConflicts(&table, cred)
 	if err != nil {
 		return nil, err
 	}
 
-	return &cred, nil
+	err = ks.saveCredential(table, cred)
+	if err != nil {
+		return nil, err
+	}
+	return cred, nil
+}
+
+// saveCredential adds the Credential to the table,
+// in the current version, and saves the table.
+func (ks *keyringCredentialsService) saveCredential(table CredentialTable, cred *Credential) error {
+	raw, err := json.Marshal(cred)
+	if err != nil {
+		return fmt.Errorf("error marshalling credential: %v", err)
+	}
+
+	table[cred.GUID] = CredentialRecord{
+		GUID:    cred.GUID,
+		Version: CurrentVersion,
+		Data:    json.RawMessage(raw),
+	}
+	return ks.save(table)
 }
 
 func (ks *keyringCredentialsService) checkForConflicts(
@@ -132,6 +159,10 @@ func (ks *keyringCredentialsService) checkForConflicts(
 	c *Credential) error {
 	// Check if Credential attributes (URL or name) are already used by another credential
 	for guid, record := range *table {
+		if guid == c.GUID {
+			// A credential being updated doesn't conflict with itself.
+			continue
+		}
 		cred, err := record.ToCredential()
 		if err != nil {
 			return NewCorruptedError(guid)
@@ -145,6 +176,25 @@ func (ks *keyringCredentialsService) checkForConflicts(
 	return nil
 }
 
+// Reset removes all Credentials. It can be used to recover
+// when the stored credentials are corrupted.
+func (ks *keyringCredentialsService) Reset() error {
+	err := ks.store.delete()
+	if err != nil {
+		return fmt.Errorf("failed to reset credentials: %v", err)
+	}
+	return nil
+}
+
+// FindConflicts returns groups of Credentials for the same server.
+func (ks *keyringCredentialsService) FindConflicts() ([][]Credential, error) {
+	creds, err := ks.List()
+	if err != nil {
+		return nil, err
+	}
+	return findConflicts(creds), nil
+}
+
 // Saves the CredentialTable
 func (ks *keyringCredentialsService) save(table CredentialTable) error {
 	data, err := json.Marshal(table)
@@ -152,25 +202,25 @@ func (ks *keyringCredentialsService) save(table CredentialTable) error {
 		return fmt.Errorf("failed to serialize credentials: %v", err)
 	}
 
-	err = keyring.Set(ServiceName, "credentials", string(data))
+	err = ks.store.set(data)
 	if err != nil {
 		return fmt.Errorf("failed to set credentials: %v", err)
 	}
 	return nil
 }
 
-// Loads the CredentialTable from keyRing
+// Loads the CredentialTable from the store
 func (ks *keyringCredentialsService) load() (CredentialTable, error) {
-	data, err := keyring.Get(ServiceName, "credentials")
+	data, err := ks.store.get()
 	if err != nil {
-		if err == keyring.ErrNotFound {
+		if err == errNoStoredCredentials {
 			return make(map[string]CredentialRecord), nil
 		}
 		return nil, NewLoadError(err)
 	}
 
 	var table CredentialTable
-	err = json.Unmarshal([]byte(data), &table)
+	err = json.Unmarshal(data, &table)
 	if err != nil {
 		return nil, fmt.Errorf("failed to deserialize credentials: %v", err)
 	}
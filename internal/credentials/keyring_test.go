@@ -27,16 +27,17 @@ func (s *KeyringCredentialsTestSuite) SetupTest() {
 
 func (s *KeyringCredentialsTestSuite) TestNewKeyringCredentialsService() {
 	ks := NewKeyringCredentialsService(s.log)
-	s.Equal(ks, &keyringCredentialsService{s.log})
+	s.Equal(ks, &keyringCredentialsService{s.log, keyringStore{}})
 	s.Implements((*CredentialsService)(nil), ks)
 }
 
 func (s *KeyringCredentialsTestSuite) TestSet() {
 	cs := keyringCredentialsService{
-		log: s.log,
+		log:   s.log,
+		store: keyringStore{},
 	}
 
-	cred, err := cs.Set("example", "https://example.com", "12345")
+	cred, err := cs.Set(CreateCredentialDetails{Name: "example", URL: "https://example.com", ApiKey: "12345"})
 	s.NoError(err)
 	s.NotNil(cred.GUID)
 	s.Equal(cred.Name, "example")
@@ -46,19 +47,31 @@ func (s *KeyringCredentialsTestSuite) TestSet() {
 
 func (s *KeyringCredentialsTestSuite) TestSetURLCollisionError() {
 	cs := keyringCredentialsService{
-		log: s.log,
+		log:   s.log,
+		store: keyringStore{},
 	}
 
-	_, err := cs.Set("example", "https://example.com", "12345")
+	_, err := cs.Set(CreateCredentialDetails{Name: "example", URL: "https://example.com", ApiKey: "12345"})
 	s.NoError(err)
-	_, err = cs.Set("example", "https://example.com", "12345")
+	_, err = cs.Set(CreateCredentialDetails{Name: "example", URL: "https://example.com", ApiKey: "12345"})
 	s.Error(err)
 	s.IsType(&URLCollisionError{}, err)
 }
 
+func (s *KeyringCredentialsTestSuite) TestSetIncomplete() {
+	cs := keyringCredentialsService{
+		log:   s.log,
+		store: keyringStore{},
+	}
+
+	_, err := cs.Set(CreateCredentialDetails{Name: "example", URL: "https://example.com", ApiKey: ""})
+	s.IsType(&IncompleteCredentialError{}, err)
+}
+
 func (s *KeyringCredentialsTestSuite) TestGet() {
 	cs := keyringCredentialsService{
-		log: s.log,
+		log:   s.log,
+		store: keyringStore{},
 	}
 
 	testGuid := "5ede880a-acd8-4206-b9fa-7d788c42fbe4"
@@ -72,7 +85,7 @@ func (s *KeyringCredentialsTestSuite) TestGet() {
 	s.log.AssertExpectations(s.T())
 
 	// pass if exists
-	cred, err := cs.Set("example", "https://example.com", "12345")
+	cred, err := cs.Set(CreateCredentialDetails{Name: "example", URL: "https://example.com", ApiKey: "12345"})
 	s.NoError(err)
 	res, err := cs.Get(cred.GUID)
 	s.NoError(err)
@@ -81,18 +94,19 @@ func (s *KeyringCredentialsTestSuite) TestGet() {
 
 func (s *KeyringCredentialsTestSuite) TestNormalizedSet() {
 	cs := keyringCredentialsService{
-		log: s.log,
+		log:   s.log,
+		store: keyringStore{},
 	}
 
 	// pass if no change (already normalized)
-	cred, err := cs.Set("example", "https://example.com", "12345")
+	cred, err := cs.Set(CreateCredentialDetails{Name: "example", URL: "https://example.com", ApiKey: "12345"})
 	s.NoError(err)
 	res, err := cs.Get(cred.GUID)
 	s.NoError(err)
 	s.Equal(res.URL, cred.URL)
 
 	// pass if URL ends up normalized
-	cred, err = cs.Set("example2", "https://example.com///another/seg/", "12345")
+	cred, err = cs.Set(CreateCredentialDetails{Name: "example2", URL: "https://example.com///another/seg/", ApiKey: "12345"})
 	s.NoError(err)
 	s.NotEqual(cred.URL, "https://example.com///another/seg/")
 
@@ -104,27 +118,29 @@ func (s *KeyringCredentialsTestSuite) TestNormalizedSet() {
 
 func (s *KeyringCredentialsTestSuite) TestSetCollisions() {
 	cs := keyringCredentialsService{
-		log: s.log,
+		log:   s.log,
+		store: keyringStore{},
 	}
 
 	// add a credential
-	_, err := cs.Set("example", "https://example.com", "12345")
+	_, err := cs.Set(CreateCredentialDetails{Name: "example", URL: "https://example.com", ApiKey: "12345"})
 	s.NoError(err)
 
 	// name collision
-	_, err = cs.Set("example", "https://more_examples.com", "12345")
+	_, err = cs.Set(CreateCredentialDetails{Name: "example", URL: "https://more_examples.com", ApiKey: "12345"})
 	s.Error(err)
 	s.IsType(&NameCollisionError{}, err)
 
 	// URL collision
-	_, err = cs.Set("another_example", "https://example.com", "12345")
+	_, err = cs.Set(CreateCredentialDetails{Name: "another_example", URL: "https://example.com", ApiKey: "12345"})
 	s.Error(err)
 	s.IsType(&URLCollisionError{}, err)
 }
 
 func (s *KeyringCredentialsTestSuite) TestList() {
 	cs := keyringCredentialsService{
-		log: s.log,
+		log:   s.log,
+		store: keyringStore{},
 	}
 
 	creds, err := cs.List()
@@ -132,9 +148,9 @@ func (s *KeyringCredentialsTestSuite) TestList() {
 	s.Equal(creds, []Credential{})
 
 	// Add a couple creds to be assert on the list again
-	nc1, err := cs.Set("example", "https://a.example.com", "12345")
+	nc1, err := cs.Set(CreateCredentialDetails{Name: "example", URL: "https://a.example.com", ApiKey: "12345"})
 	s.NoError(err)
-	nc2, err := cs.Set("example2", "https://b.example.com", "12345")
+	nc2, err := cs.Set(CreateCredentialDetails{Name: "example2", URL: "https://b.example.com", ApiKey: "12345"})
 	s.NoError(err)
 
 	creds, err = cs.List()
@@ -146,10 +162,11 @@ func (s *KeyringCredentialsTestSuite) TestList() {
 
 func (s *KeyringCredentialsTestSuite) TestDelete() {
 	cs := keyringCredentialsService{
-		log: s.log,
+		log:   s.log,
+		store: keyringStore{},
 	}
 
-	cred, err := cs.Set("example", "https://example.com", "12345")
+	cred, err := cs.Set(CreateCredentialDetails{Name: "example", URL: "https://example.com", ApiKey: "12345"})
 	s.NoError(err)
 
 	// no error if exists
@@ -162,3 +179,208 @@ func (s *KeyringCredentialsTestSuite) TestDelete() {
 	s.Error(err)
 	s.log.AssertExpectations(s.T())
 }
+
+func (s *KeyringCredentialsTestSuite) TestUpdate() {
+	cs := keyringCredentialsService{
+		log:   s.log,
+		store: keyringStore{},
+	}
+
+	cred, err := cs.Set(CreateCredentialDetails{Name: "example", URL: "https://example.com", ApiKey: "12345"})
+	s.NoError(err)
+
+	// Changing only the API key doesn't conflict with itself.
+	updated, err := cs.Update(cred.GUID, CreateCredentialDetails{Name: "example", URL: "https://example.com/", ApiKey: "67890"})
+	s.NoError(err)
+	s.Equal(&Credential{
+		GUID:   cred.GUID,
+		Name:   "example",
+		URL:    "https://example.com",
+		ApiKey: "67890",
+	}, updated)
+
+	updated, err = cs.Update(cred.GUID, CreateCredentialDetails{Name: "renamed", URL: "https://example.com", ApiKey: "67890"})
+	s.NoError(err)
+	res, err := cs.Get(cred.GUID)
+	s.NoError(err)
+	s.Equal(updated, res)
+
+	creds, err := cs.List()
+	s.NoError(err)
+	s.Len(creds, 1)
+}
+
+func (s *KeyringCredentialsTestSuite) TestUpdateNotFound() {
+	cs := keyringCredentialsService{
+		log:   s.log,
+		store: keyringStore{},
+	}
+
+	testGuid := "5ede880a-acd8-4206-b9fa-7d788c42fbe4"
+	s.log.On("Debug", "Credential does not exist", "credential", testGuid).Return()
+
+	_, err := cs.Update(testGuid, CreateCredentialDetails{Name: "example", URL: "https://example.com", ApiKey: "12345"})
+	s.IsType(&NotFoundError{}, err)
+	s.log.AssertExpectations(s.T())
+}
+
+func (s *KeyringCredentialsTestSuite) TestUpdateIncomplete() {
+	cs := keyringCredentialsService{
+		log:   s.log,
+		store: keyringStore{},
+	}
+
+	cred, err := cs.Set(CreateCredentialDetails{Name: "example", URL: "https://example.com", ApiKey: "12345"})
+	s.NoError(err)
+	_, err = cs.Update(cred.GUID, CreateCredentialDetails{Name: "example", URL: "https://example.com", ApiKey: ""})
+	s.IsType(&IncompleteCredentialError{}, err)
+}
+
+func (s *KeyringCredentialsTestSuite) TestUpdateCollisions() {
+	cs := keyringCredentialsService{
+		log:   s.log,
+		store: keyringStore{},
+	}
+
+	_, err := cs.Set(CreateCredentialDetails{Name: "example", URL: "https://example.com", ApiKey: "12345"})
+	s.NoError(err)
+	cred, err := cs.Set(CreateCredentialDetails{Name: "another_example", URL: "https://more_examples.com", ApiKey: "12345"})
+	s.NoError(err)
+
+	// name collision
+	_, err = cs.Update(cred.GUID, CreateCredentialDetails{Name: "example", URL: "https://more_examples.com", ApiKey: "12345"})
+	s.IsType(&NameCollisionError{}, err)
+
+	// URL collision
+	_, err = cs.Update(cred.GUID, CreateCredentialDetails{Name: "another_example", URL: "https://example.com", ApiKey: "12345"})
+	s.IsType(&URLCollisionError{}, err)
+
+	// unchanged
+	res, err := cs.Get(cred.GUID)
+	s.NoError(err)
+	s.Equal(cred, res)
+}
+
+func (s *KeyringCredentialsTestSuite) TestSetTokenSecret() {
+	cs := keyringCredentialsService{
+		log:   s.log,
+		store: keyringStore{},
+	}
+
+	cred, err := cs.Set(CreateCredentialDetails{Name: "cloud", URL: "https://api.posit.cloud", Token: "abc", Secret: "def"})
+	s.NoError(err)
+	res, err := cs.Get(cred.GUID)
+	s.NoError(err)
+	s.Equal(&Credential{
+		GUID:   cred.GUID,
+		Name:   "cloud",
+		URL:    "https://api.posit.cloud",
+		Token:  "abc",
+		Secret: "def",
+	}, res)
+}
+
+func (s *KeyringCredentialsTestSuite) TestLoadV0() {
+	cs := keyringCredentialsService{
+		log:   s.log,
+		store: keyringStore{},
+	}
+
+	// A table saved before Version 1 existed.
+	guid := "18cd5640-bee5-4b2a-992a-a2725ab6103d"
+	err := keyring.Set(ServiceName, "credentials", `{"18cd5640-bee5-4b2a-992a-a2725ab6103d":{
+		"guid":"18cd5640-bee5-4b2a-992a-a2725ab6103d","version":0,
+		"data":{"guid":"18cd5640-bee5-4b2a-992a-a2725ab6103d","name":"friedtofu","url":"https://example.com","apiKey":"12345"}}}`)
+	s.NoError(err)
+
+	cred, err := cs.Get(guid)
+	s.NoError(err)
+	v0 := &Credential{
+		GUID:   guid,
+		Name:   "friedtofu",
+		URL:    "https://example.com",
+		ApiKey: "12345",
+	}
+	s.Equal(v0, cred)
+
+	// Adding a credential leaves the V0 record readable.
+	_, err = cs.Set(CreateCredentialDetails{Name: "cloud", URL: "https://api.posit.cloud", Token: "abc", Secret: "def"})
+	s.NoError(err)
+	table, err := cs.load()
+	s.NoError(err)
+	s.Equal(uint(0), table[guid].Version)
+	creds, err := cs.List()
+	s.NoError(err)
+	s.Len(creds, 2)
+	s.Contains(creds, *v0)
+
+	// Updating it saves it in the current version.
+	_, err = cs.Update(guid, CreateCredentialDetails{Name: "friedtofu", URL: "https://example.com", ApiKey: "67890"})
+	s.NoError(err)
+	table, err = cs.load()
+	s.NoError(err)
+	s.Equal(uint(CurrentVersion), table[guid].Version)
+	cred, err = cs.Get(guid)
+	s.NoError(err)
+	s.Equal("67890", cred.ApiKey)
+}
+
+func (s *KeyringCredentialsTestSuite) TestReset() {
+	cs := keyringCredentialsService{
+		log:   s.log,
+		store: keyringStore{},
+	}
+
+	err := keyring.Set(ServiceName, "credentials", `{"18cd5640-bee5-4b2a`)
+	s.NoError(err)
+	_, err = cs.List()
+	s.Error(err)
+
+	err = cs.Reset()
+	s.NoError(err)
+	creds, err := cs.List()
+	s.NoError(err)
+	s.Equal([]Credential{}, creds)
+
+	// No error if there is nothing to reset.
+	err = cs.Reset()
+	s.NoError(err)
+}
+
+func (s *KeyringCredentialsTestSuite) TestFindConflicts() {
+	cs := keyringCredentialsService{
+		log:   s.log,
+		store: keyringStore{},
+	}
+
+	// Set normalizes URLs, so these were stored by an older version
+	// or imported from elsewhere.
+	err := keyring.Set(ServiceName, "credentials", `{
+		"1": {"guid": "1", "version": 1, "data": {"guid": "1", "name": "imported", "url": "https://connect.example.com/__api__", "apiKey": "12345"}},
+		"2": {"guid": "2", "version": 1, "data": {"guid": "2", "name": "mine", "url": "https://connect.example.com/", "apiKey": "67890"}},
+		"3": {"guid": "3", "version": 1, "data": {"guid": "3", "name": "other", "url": "https://other.example.com", "apiKey": "abcde"}}
+	}`)
+	s.NoError(err)
+
+	conflicts, err := cs.FindConflicts()
+	s.NoError(err)
+	s.Equal([][]Credential{
+		{
+			{GUID: "1", Name: "imported", URL: "https://connect.example.com/__api__", ApiKey: "12345"},
+			{GUID: "2", Name: "mine", URL: "https://connect.example.com/", ApiKey: "67890"},
+		},
+	}, conflicts)
+}
+
+func (s *KeyringCredentialsTestSuite) TestFindConflictsNone() {
+	cs := keyringCredentialsService{
+		log:   s.log,
+		store: keyringStore{},
+	}
+	_, err := cs.Set(CreateCredentialDetails{Name: "example", URL: "https://example.com", ApiKey: "12345"})
+	s.NoError(err)
+
+	conflicts, err := cs.FindConflicts()
+	s.NoError(err)
+	s.Len(conflicts, 0)
+}
@@ -0,0 +1,235 @@
+// Copyright (C) 2024 by Posit Software, PBC.
+
+package credentials
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/posit-dev/publisher/internal/util"
+	"github.com/zalando/go-keyring"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const CredentialsFileEnvVar = "POSIT_PUBLISHER_CREDENTIALS_FILE"
+const CredentialsPassphraseEnvVar = "POSIT_PUBLISHER_CREDENTIALS_PASSPHRASE"
+
+// The shorter names are accepted too, for
+// compatibility with other Posit tools.
+const credentialsFileAltEnvVar = "POSIT_CREDENTIALS_FILE"
+const credentialsPassphraseAltEnvVar = "POSIT_CREDENTIALS_PASSPHRASE"
+
+var errNoStoredCredentials = errors.New("no stored credentials")
+
+// tableStore holds the serialized CredentialTable.
+// get returns errNoStoredCredentials if nothing has been saved yet.
+type tableStore interface {
+	get() ([]byte, error)
+	set(data []byte) error
+	delete() error
+}
+
+// keyringStore keeps the table in the system keyring.
+type keyringStore struct{}
+
+func (keyringStore) get() ([]byte, error) {
+	data, err := keyring.Get(ServiceName, "credentials")
+	if err != nil {
+		if err == keyring.ErrNotFound {
+			return nil, errNoStoredCredentials
+		}
+		return nil, err
+	}
+	return []byte(data), nil
+}
+
+func (keyringStore) set(data []byte) error {
+	return keyring.Set(ServiceName, "credentials", string(data))
+}
+
+func (keyringStore) delete() error {
+	err := keyring.Delete(ServiceName, "credentials")
+	if err != nil && err != keyring.ErrNotFound {
+		return err
+	}
+	return nil
+}
+
+// encryptedFileStore keeps the table in a file, encrypted with
+// AES-GCM using a key derived from a passphrase. It is used in
+// headless and CI environments where there is no keyring.
+type encryptedFileStore struct {
+	path       util.AbsolutePath
+	passphrase string
+}
+
+// encryptedFile is the on-disk format of an encryptedFileStore.
+type encryptedFile struct {
+	Version    int    `json:"version"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Data       []byte `json:"data"`
+}
+
+const encryptedFileVersion = 1
+const saltSize = 16
+
+// keyDerivationIterations is the PBKDF2 work factor for new files.
+var keyDerivationIterations = 600_000
+
+// The work factor read from a file must be within these limits,
+// so a modified file can't weaken the key or make it take
+// too long to derive.
+var minKeyDerivationIterations = 100_000
+
+const maxKeyDerivationIterations = 10_000_000
+
+var errWrongPassphrase = errors.New("credentials file could not be decrypted; check the passphrase")
+
+func (s *encryptedFileStore) get() ([]byte, error) {
+	content, err := s.path.ReadFile()
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, errNoStoredCredentials
+		}
+		return nil, err
+	}
+	var f encryptedFile
+	err = json.Unmarshal(content, &f)
+	if err != nil {
+		return nil, fmt.Errorf("credentials file %s is not valid: %w", s.path, err)
+	}
+	if f.Version != encryptedFileVersion {
+		return nil, fmt.Errorf("credentials file %s has unsupported version %d", s.path, f.Version)
+	}
+	if f.Iterations < minKeyDerivationIterations || f.Iterations > maxKeyDerivationIterations {
+		return nil, fmt.Errorf("credentials file %s is not valid: iterations must be from %d to %d",
+			s.path, minKeyDerivationIterations, maxKeyDerivationIterations)
+	}
+	gcm, err := newGCM(s.passphrase, f.Salt, f.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	if len(f.Nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("credentials file %s is not valid: bad nonce", s.path)
+	}
+	data, err := gcm.Open(nil, f.Nonce, f.Data, nil)
+	if err != nil {
+		return nil, errWrongPassphrase
+	}
+	return data, nil
+}
+
+func (s *encryptedFileStore) set(data []byte) error {
+	// A new salt and nonce are used each time the file is written.
+	salt := make([]byte, saltSize)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return err
+	}
+	gcm, err := newGCM(s.passphrase, salt, keyDerivationIterations)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return err
+	}
+	content, err := json.Marshal(encryptedFile{
+		Version:    encryptedFileVersion,
+		Iterations: keyDerivationIterations,
+		Salt:       salt,
+		Nonce:      nonce,
+		Data:       gcm.Seal(nil, nonce, data, nil),
+	})
+	if err != nil {
+		return err
+	}
+	dir := s.path.Dir()
+	err = dir.MkdirAll(0700)
+	if err != nil {
+		return err
+	}
+	// Write a new file and replace the old one, so the credentials
+	// aren't lost if writing is interrupted.
+	f, err := dir.TempFile(s.path.Base() + "-*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := util.NewAbsolutePath(f.Name(), dir.Fs())
+	_, err = f.Write(content)
+	closeErr := f.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = tmpPath.Chmod(0600)
+	}
+	if err == nil {
+		err = tmpPath.Rename(s.path.Path)
+	}
+	if err != nil {
+		tmpPath.Remove()
+		return err
+	}
+	return nil
+}
+
+func (s *encryptedFileStore) delete() error {
+	err := s.path.Remove()
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func newGCM(passphrase string, salt []byte, iterations int) (cipher.AEAD, error) {
+	key := pbkdf2.Key([]byte(passphrase), salt, iterations, 32, sha256.New)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// getenv returns the value of the first of the
+// environment variables that is set, and its name.
+func getenv(names ...string) (string, string) {
+	for _, name := range names {
+		if value := os.Getenv(name); value != "" {
+			return value, name
+		}
+	}
+	return "", names[0]
+}
+
+// encryptedFileStoreFromEnv returns an encryptedFileStore if
+// POSIT_PUBLISHER_CREDENTIALS_FILE or POSIT_CREDENTIALS_FILE
+// is set, or nil if neither is.
+func encryptedFileStoreFromEnv() (*encryptedFileStore, error) {
+	path, pathVar := getenv(CredentialsFileEnvVar, credentialsFileAltEnvVar)
+	if path == "" {
+		return nil, nil
+	}
+	passphrase, passphraseVar := getenv(CredentialsPassphraseEnvVar, credentialsPassphraseAltEnvVar)
+	if passphrase == "" {
+		return nil, fmt.Errorf("%s must be set when %s is set", passphraseVar, pathVar)
+	}
+	absPath, err := util.NewPath(path, fsys).Abs()
+	if err != nil {
+		return nil, err
+	}
+	return &encryptedFileStore{
+		path:       absPath,
+		passphrase: passphrase,
+	}, nil
+}
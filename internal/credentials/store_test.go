@@ -0,0 +1,150 @@
+// Copyright (C) 2024 by Posit Software, PBC.
+
+package credentials
+
+import (
+	"encoding/json"
+	"io/fs"
+	"testing"
+
+	"github.com/posit-dev/publisher/internal/logging/loggingtest"
+	"github.com/posit-dev/publisher/internal/util"
+	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/suite"
+)
+
+type EncryptedFileStoreTestSuite struct {
+	utiltest.Suite
+	log  *loggingtest.MockLogger
+	path util.AbsolutePath
+}
+
+func TestEncryptedFileStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(EncryptedFileStoreTestSuite))
+}
+
+func (s *EncryptedFileStoreTestSuite) SetupTest() {
+	s.log = loggingtest.NewMockLogger()
+	s.path = util.NewAbsolutePath("/ci/credentials.json", afero.NewMemMapFs())
+
+	// Keep the tests fast.
+	iterations := keyDerivationIterations
+	minIterations := minKeyDerivationIterations
+	keyDerivationIterations = 1000
+	minKeyDerivationIterations = 1000
+	s.T().Cleanup(func() {
+		keyDerivationIterations = iterations
+		minKeyDerivationIterations = minIterations
+	})
+}
+
+func (s *EncryptedFileStoreTestSuite) newService(passphrase string) *keyringCredentialsService {
+	return &keyringCredentialsService{
+		log: s.log,
+		store: &encryptedFileStore{
+			path:       s.path,
+			passphrase: passphrase,
+		},
+	}
+}
+
+func (s *EncryptedFileStoreTestSuite) TestEmpty() {
+	cs := s.newService("hunter2")
+	creds, err := cs.List()
+	s.NoError(err)
+	s.Len(creds, 0)
+}
+
+func (s *EncryptedFileStoreTestSuite) TestRoundTrip() {
+	cs := s.newService("hunter2")
+	cred, err := cs.Set(CreateCredentialDetails{Name: "example", URL: "https://example.com", ApiKey: "12345"})
+	s.NoError(err)
+
+	// The file doesn't contain the credential in the clear.
+	content, err := s.path.ReadFile()
+	s.NoError(err)
+	s.NotContains(string(content), "12345")
+	s.NotContains(string(content), "example.com")
+
+	// A new service with the same passphrase can read it.
+	cs = s.newService("hunter2")
+	got, err := cs.Get(cred.GUID)
+	s.NoError(err)
+	s.Equal(cred, got)
+
+	err = cs.Delete(cred.GUID)
+	s.NoError(err)
+	creds, err := cs.List()
+	s.NoError(err)
+	s.Len(creds, 0)
+}
+
+func (s *EncryptedFileStoreTestSuite) TestWrongPassphrase() {
+	cs := s.newService("hunter2")
+	_, err := cs.Set(CreateCredentialDetails{Name: "example", URL: "https://example.com", ApiKey: "12345"})
+	s.NoError(err)
+
+	cs = s.newService("letmein")
+	_, err = cs.List()
+	s.ErrorContains(err, errWrongPassphrase.Error())
+}
+
+func (s *EncryptedFileStoreTestSuite) TestReset() {
+	cs := s.newService("hunter2")
+	_, err := cs.Set(CreateCredentialDetails{Name: "example", URL: "https://example.com", ApiKey: "12345"})
+	s.NoError(err)
+
+	// Reset recovers from a forgotten passphrase.
+	cs = s.newService("letmein")
+	err = cs.Reset()
+	s.NoError(err)
+	exists, err := s.path.Exists()
+	s.NoError(err)
+	s.False(exists)
+
+	creds, err := cs.List()
+	s.NoError(err)
+	s.Len(creds, 0)
+}
+
+func (s *EncryptedFileStoreTestSuite) TestIterationsOutOfRange() {
+	cs := s.newService("hunter2")
+	_, err := cs.Set(CreateCredentialDetails{Name: "example", URL: "https://example.com", ApiKey: "12345"})
+	s.NoError(err)
+
+	for _, iterations := range []int{1, maxKeyDerivationIterations + 1} {
+		content, err := s.path.ReadFile()
+		s.NoError(err)
+		var f encryptedFile
+		s.NoError(json.Unmarshal(content, &f))
+		f.Iterations = iterations
+		content, err = json.Marshal(f)
+		s.NoError(err)
+		s.NoError(s.path.WriteFile(content, 0600))
+
+		_, err = cs.List()
+		s.ErrorContains(err, "iterations must be from 1000 to 10000000")
+	}
+}
+
+func (s *EncryptedFileStoreTestSuite) TestSetReplacesFile() {
+	cs := s.newService("hunter2")
+	_, err := cs.Set(CreateCredentialDetails{Name: "example", URL: "https://example.com", ApiKey: "12345"})
+	s.NoError(err)
+	_, err = cs.Set(CreateCredentialDetails{Name: "other", URL: "https://other.example.com", ApiKey: "67890"})
+	s.NoError(err)
+
+	// Only the credentials file is left.
+	entries, err := s.path.Dir().ReadDir()
+	s.NoError(err)
+	s.Len(entries, 1)
+	s.Equal("credentials.json", entries[0].Name())
+	info, err := s.path.Stat()
+	s.NoError(err)
+	s.Equal(fs.FileMode(0600), info.Mode().Perm())
+
+	creds, err := cs.List()
+	s.NoError(err)
+	s.Len(creds, 2)
+}
@@ -31,16 +31,20 @@ type Deployment struct {
 	DashboardURL  string              `toml:"dashboard_url,omitempty" json:"dashboardUrl"`
 	DirectURL     string              `toml:"direct_url,omitempty" json:"directUrl"`
 	LogsURL       string              `toml:"logs_url,omitempty" json:"logsUrl"`
+	Note          string              `toml:"note,omitempty" json:"note,omitempty"`
+	Labels        map[string]string   `toml:"labels,omitempty" json:"labels,omitempty"`
 
 	// Full deployment fields
-	DeployedAt    string            `toml:"deployed_at,omitempty" json:"deployedAt"`
-	BundleID      types.BundleID    `toml:"bundle_id,omitempty" json:"bundleId"`
-	BundleURL     string            `toml:"bundle_url,omitempty" json:"bundleUrl"`
-	Error         *types.AgentError `toml:"deployment_error,omitempty" json:"deploymentError"`
-	Files         []string          `toml:"files,multiline,omitempty" json:"files"`
-	Requirements  []string          `toml:"requirements,multiline,omitempty" json:"requirements"`
-	Configuration *config.Config    `toml:"configuration,omitempty" json:"configuration"`
-	Renv          *renv.Lockfile    `toml:"renv,omitempty" json:"renv"`
+	DeployedAt    string              `toml:"deployed_at,omitempty" json:"deployedAt"`
+	BundleID      types.BundleID      `toml:"bundle_id,omitempty" json:"bundleId"`
+	BundleURL     string              `toml:"bundle_url,omitempty" json:"bundleUrl"`
+	ContentHash   string              `toml:"content_hash,omitempty" json:"contentHash,omitempty"`
+	Error         *types.AgentError   `toml:"deployment_error,omitempty" json:"deploymentError"`
+	Task          *types.TaskPosition `toml:"task,omitempty" json:"task,omitempty"`
+	Files         []string            `toml:"files,multiline,omitempty" json:"files"`
+	Requirements  []string            `toml:"requirements,multiline,omitempty" json:"requirements"`
+	Configuration *config.Config      `toml:"configuration,omitempty" json:"configuration"`
+	Renv          *renv.Lockfile      `toml:"renv,omitempty" json:"renv"`
 }
 
 func New() *Deployment {
@@ -53,6 +57,20 @@ func New() *Deployment {
 	}
 }
 
+// Annotate sets the note, if one is given, and adds the labels,
+// replacing any existing labels with the same keys.
+func (d *Deployment) Annotate(note string, labels map[string]string) {
+	if note != "" {
+		d.Note = note
+	}
+	if len(labels) != 0 && d.Labels == nil {
+		d.Labels = make(map[string]string, len(labels))
+	}
+	for k, v := range labels {
+		d.Labels[k] = v
+	}
+}
+
 func GetDeploymentsPath(base util.AbsolutePath) util.AbsolutePath {
 	return base.Join(".posit", "publish", "deployments")
 }
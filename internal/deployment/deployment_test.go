@@ -98,6 +98,8 @@ func (s *DeploymentSuite) TestFromExampleFile() {
 	s.Equal("https://connect.example.com/__api__/v1/content/de2e7bdb-b085-401e-a65c-443e40009749/bundles/123/download", d.BundleURL)
 	s.Equal("https://connect.example.com/connect/#/apps/de2e7bdb-b085-401e-a65c-443e40009749", d.DashboardURL)
 	s.Equal("https://connect.example.com/connect/#/apps/de2e7bdb-b085-401e-a65c-443e40009749/logs", d.LogsURL)
+	s.Equal("Production release 2024-06", d.Note)
+	s.Equal(map[string]string{"environment": "production"}, d.Labels)
 }
 
 func (s *DeploymentSuite) TestFromFileErr() {
@@ -130,6 +132,38 @@ func (s *DeploymentSuite) TestWriteFile() {
 	s.NotContains(stringContent, "[configuration")
 }
 
+func (s *DeploymentSuite) TestWriteFileNoteAndLabels() {
+	path := GetDeploymentPath(s.cwd, "myTargetName")
+	d := New()
+	d.ServerURL = "https://connect.example.com"
+	d.Annotate("prod release 2024-06", map[string]string{
+		"environment": "production",
+		"team":        "data science",
+	})
+	err := d.WriteFile(path)
+	s.NoError(err)
+
+	actual, err := FromFile(path)
+	s.NoError(err)
+	s.Equal("prod release 2024-06", actual.Note)
+	s.Equal(map[string]string{
+		"environment": "production",
+		"team":        "data science",
+	}, actual.Labels)
+}
+
+func (s *DeploymentSuite) TestAnnotate() {
+	d := New()
+	d.Annotate("", nil)
+	s.Equal("", d.Note)
+	s.Nil(d.Labels)
+
+	d.Annotate("first", map[string]string{"a": "1", "b": "2"})
+	d.Annotate("", map[string]string{"b": "3"})
+	s.Equal("first", d.Note)
+	s.Equal(map[string]string{"a": "1", "b": "3"}, d.Labels)
+}
+
 func (s *DeploymentSuite) TestWriteFileErr() {
 	deploymentFile := GetDeploymentPath(s.cwd, "myTargetName")
 	readonlyFs := afero.NewReadOnlyFs(deploymentFile.Fs())
@@ -24,6 +24,7 @@ var opNameMap = map[Operation]string{
 	PublishCheckCapabilitiesOp:   "Check Configuration",
 	PublishCreateNewDeploymentOp: "Create New Deployment",
 	PublishSetEnvVarsOp:          "Set Environment Variables",
+	PublishSetThumbnailOp:        "Set Thumbnail",
 	PublishCreateBundleOp:        "Prepare Files",
 	PublishUploadBundleOp:        "Upload Files",
 	PublishUpdateDeploymentOp:    "Update Deployment Settings",
@@ -48,6 +48,8 @@ const (
 	PublishGetRPackageDescriptionsOp Operation = "publish/getRPackageDescriptions"
 	PublishCreateNewDeploymentOp     Operation = "publish/createNewDeployment"
 	PublishSetEnvVarsOp              Operation = "publish/setEnvVars"
+	PublishSetThumbnailOp            Operation = "publish/setThumbnail"
+	PublishRunPrePublishOp           Operation = "publish/runPrePublish"
 	PublishCreateBundleOp            Operation = "publish/createBundle"
 	PublishUpdateDeploymentOp        Operation = "publish/createDeployment"
 	PublishUploadBundleOp            Operation = "publish/uploadBundle"
@@ -0,0 +1,76 @@
+package events
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// OpTiming records how long an operation took, from its
+// start event to its success or failure event.
+type OpTiming struct {
+	Op         Operation `mapstructure:"op" json:"op"`
+	StartMs    int64     `mapstructure:"startMs" json:"startMs"` // Relative to the first event
+	DurationMs int64     `mapstructure:"durationMs" json:"durationMs"`
+}
+
+type timingEmitter struct {
+	emitter Emitter
+	mu      sync.Mutex
+	first   time.Time
+	started map[Operation]time.Time
+	timings []OpTiming
+}
+
+// NewTimingEmitter returns an emitter that passes events through
+// to `emitter`, recording the duration of each operation.
+func NewTimingEmitter(emitter Emitter) *timingEmitter {
+	return &timingEmitter{
+		emitter: emitter,
+		started: make(map[Operation]time.Time),
+	}
+}
+
+func (e *timingEmitter) Emit(event *Event) error {
+	e.record(event)
+	return e.emitter.Emit(event)
+}
+
+func (e *timingEmitter) record(event *Event) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.first.IsZero() {
+		e.first = event.Time
+	}
+	switch event.phase {
+	case StartPhase:
+		e.started[event.op] = event.Time
+	case SuccessPhase, FailurePhase:
+		start, ok := e.started[event.op]
+		if !ok {
+			return
+		}
+		delete(e.started, event.op)
+		e.timings = append(e.timings, OpTiming{
+			Op:         event.op,
+			StartMs:    start.Sub(e.first).Milliseconds(),
+			DurationMs: event.Time.Sub(start).Milliseconds(),
+		})
+	}
+}
+
+// Timings returns the operations that have completed so far,
+// in the order they started.
+func (e *timingEmitter) Timings() []OpTiming {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	timings := slices.Clone(e.timings)
+	slices.SortStableFunc(timings, func(a, b OpTiming) int {
+		return int(a.StartMs - b.StartMs)
+	})
+	return timings
+}
@@ -0,0 +1,66 @@
+package events
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"testing"
+	"time"
+
+	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/stretchr/testify/suite"
+)
+
+type TimingEmitterSuite struct {
+	utiltest.Suite
+}
+
+func TestTimingEmitterSuite(t *testing.T) {
+	suite.Run(t, new(TimingEmitterSuite))
+}
+
+func (s *TimingEmitterSuite) TestTimings() {
+	base := NewCapturingEmitter()
+	emitter := NewTimingEmitter(base)
+
+	t0 := time.Now()
+	emit := func(op Operation, phase Phase, offset time.Duration) {
+		event := New(op, phase, NoError, NoData)
+		event.Time = t0.Add(offset)
+		s.NoError(emitter.Emit(event))
+	}
+	emit(PublishOp, StartPhase, 0)
+	emit(PublishCreateBundleOp, StartPhase, 10*time.Millisecond)
+	emit(PublishCreateBundleOp, SuccessPhase, 40*time.Millisecond)
+	emit(PublishUploadBundleOp, StartPhase, 50*time.Millisecond)
+	emit(PublishUploadBundleOp, LogPhase, 60*time.Millisecond)
+	emit(PublishUploadBundleOp, FailurePhase, 150*time.Millisecond)
+
+	// Events are passed through.
+	s.Len(base.Events, 6)
+
+	// The publish operation hasn't finished.
+	s.Equal([]OpTiming{
+		{Op: PublishCreateBundleOp, StartMs: 10, DurationMs: 30},
+		{Op: PublishUploadBundleOp, StartMs: 50, DurationMs: 100},
+	}, emitter.Timings())
+}
+
+func (s *TimingEmitterSuite) TestTimingsOrderedByStart() {
+	emitter := NewTimingEmitter(NewNullEmitter())
+
+	t0 := time.Now()
+	emit := func(op Operation, phase Phase, offset time.Duration) {
+		event := New(op, phase, NoError, NoData)
+		event.Time = t0.Add(offset)
+		s.NoError(emitter.Emit(event))
+	}
+	emit(PublishRestorePythonEnvOp, StartPhase, 0)
+	emit(PublishRunContentOp, StartPhase, 5*time.Millisecond)
+	emit(PublishRunContentOp, SuccessPhase, 10*time.Millisecond)
+	emit(PublishRestorePythonEnvOp, SuccessPhase, 20*time.Millisecond)
+
+	timings := emitter.Timings()
+	s.Len(timings, 2)
+	s.Equal(PublishRestorePythonEnvOp, timings[0].Op)
+	s.Equal(PublishRunContentOp, timings[1].Op)
+}
@@ -0,0 +1,60 @@
+package initialize
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/posit-dev/publisher/internal/config"
+	"github.com/posit-dev/publisher/internal/util"
+)
+
+// companionFiles lists, for each content type, the files and directories
+// (with a trailing slash) that the content commonly needs at runtime
+// alongside its entrypoint. Paths are relative to the entrypoint's
+// directory. These are only added to the file list if they exist, so
+// they should be limited to well-known names.
+var companionFiles = map[config.ContentType][]string{
+	config.ContentTypePythonDash:      {"assets/"},
+	config.ContentTypePythonShiny:     {"www/"},
+	config.ContentTypePythonStreamlit: {".streamlit/config.toml"},
+	config.ContentTypeQuarto:          {"_quarto.yml", "_quarto.yaml"},
+	config.ContentTypeQuartoShiny:     {"_quarto.yml", "_quarto.yaml", "www/"},
+	config.ContentTypeRShiny:          {"global.R", "R/", "www/"},
+	config.ContentTypeRMarkdownShiny:  {"www/"},
+}
+
+// addCompanionFiles adds the existing companion files for the
+// content type to the configuration's file list.
+func addCompanionFiles(cfg *config.Config, base util.AbsolutePath) error {
+	dir := path.Dir(cfg.Entrypoint)
+	for _, name := range companionFiles[cfg.Type] {
+		isDir := strings.HasSuffix(name, "/")
+		relPath := path.Join(dir, name)
+		p := base.Join(relPath)
+		exists, err := p.Exists()
+		if err != nil {
+			return err
+		}
+		if !exists {
+			continue
+		}
+		pathIsDir, err := p.IsDir()
+		if err != nil {
+			return err
+		}
+		if pathIsDir != isDir {
+			continue
+		}
+		pattern := "/" + relPath
+		if isDir {
+			pattern += "/"
+		}
+		if !slices.Contains(cfg.Files, pattern) {
+			cfg.Files = append(cfg.Files, pattern)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package initialize
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"testing"
+
+	"github.com/posit-dev/publisher/internal/config"
+	"github.com/posit-dev/publisher/internal/util"
+	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/suite"
+)
+
+type CompanionFilesSuite struct {
+	utiltest.Suite
+	base util.AbsolutePath
+}
+
+func TestCompanionFilesSuite(t *testing.T) {
+	suite.Run(t, new(CompanionFilesSuite))
+}
+
+func (s *CompanionFilesSuite) SetupTest() {
+	s.base = util.NewAbsolutePath("/project", afero.NewMemMapFs())
+	err := s.base.MkdirAll(0777)
+	s.NoError(err)
+}
+
+func (s *CompanionFilesSuite) createFiles(names ...string) {
+	for _, name := range names {
+		p := s.base.Join(name)
+		err := p.Dir().MkdirAll(0777)
+		s.NoError(err)
+		err = p.WriteFile([]byte("\n"), 0666)
+		s.NoError(err)
+	}
+}
+
+func (s *CompanionFilesSuite) companions(contentType config.ContentType, entrypoint string) []string {
+	cfg := config.New()
+	cfg.Type = contentType
+	cfg.Entrypoint = entrypoint
+	cfg.Files = []string{"/" + entrypoint}
+	err := addCompanionFiles(cfg, s.base)
+	s.NoError(err)
+	return cfg.Files
+}
+
+func (s *CompanionFilesSuite) TestRShiny() {
+	s.createFiles("app.R", "global.R", "R/utils.R", "www/style.css", "data/data.csv")
+	s.Equal([]string{"/app.R", "/global.R", "/R/", "/www/"},
+		s.companions(config.ContentTypeRShiny, "app.R"))
+}
+
+func (s *CompanionFilesSuite) TestRMarkdownShiny() {
+	s.createFiles("report.Rmd", "www/style.css", "global.R")
+	s.Equal([]string{"/report.Rmd", "/www/"},
+		s.companions(config.ContentTypeRMarkdownShiny, "report.Rmd"))
+}
+
+func (s *CompanionFilesSuite) TestPythonShiny() {
+	s.createFiles("app.py", "www/logo.png")
+	s.Equal([]string{"/app.py", "/www/"},
+		s.companions(config.ContentTypePythonShiny, "app.py"))
+}
+
+func (s *CompanionFilesSuite) TestPythonDash() {
+	s.createFiles("app.py", "assets/style.css")
+	s.Equal([]string{"/app.py", "/assets/"},
+		s.companions(config.ContentTypePythonDash, "app.py"))
+}
+
+func (s *CompanionFilesSuite) TestPythonStreamlit() {
+	// Secrets are not included.
+	s.createFiles("app.py", ".streamlit/config.toml", ".streamlit/secrets.toml")
+	s.Equal([]string{"/app.py", "/.streamlit/config.toml"},
+		s.companions(config.ContentTypePythonStreamlit, "app.py"))
+}
+
+func (s *CompanionFilesSuite) TestQuarto() {
+	s.createFiles("report.qmd", "_quarto.yml")
+	s.Equal([]string{"/report.qmd", "/_quarto.yml"},
+		s.companions(config.ContentTypeQuarto, "report.qmd"))
+}
+
+func (s *CompanionFilesSuite) TestQuartoShiny() {
+	s.createFiles("report.qmd", "_quarto.yaml", "www/style.css")
+	s.Equal([]string{"/report.qmd", "/_quarto.yaml", "/www/"},
+		s.companions(config.ContentTypeQuartoShiny, "report.qmd"))
+}
+
+func (s *CompanionFilesSuite) TestNoCompanions() {
+	s.createFiles("api.py", "www/index.html")
+	s.Equal([]string{"/api.py"},
+		s.companions(config.ContentTypePythonFastAPI, "api.py"))
+}
+
+func (s *CompanionFilesSuite) TestMissingCompanions() {
+	s.createFiles("app.R")
+	s.Equal([]string{"/app.R"},
+		s.companions(config.ContentTypeRShiny, "app.R"))
+}
+
+func (s *CompanionFilesSuite) TestWrongKind() {
+	// A file named www is not the www directory
+	s.createFiles("app.R", "www", "global.R/file")
+	s.Equal([]string{"/app.R"},
+		s.companions(config.ContentTypeRShiny, "app.R"))
+}
+
+func (s *CompanionFilesSuite) TestAlreadyIncluded() {
+	s.createFiles("report.qmd", "_quarto.yml")
+	cfg := config.New()
+	cfg.Type = config.ContentTypeQuarto
+	cfg.Entrypoint = "report.qmd"
+	cfg.Files = []string{"/report.qmd", "/_quarto.yml"}
+	err := addCompanionFiles(cfg, s.base)
+	s.NoError(err)
+	s.Equal([]string{"/report.qmd", "/_quarto.yml"}, cfg.Files)
+}
+
+func (s *CompanionFilesSuite) TestNestedEntrypoint() {
+	s.createFiles("src/app.py", "src/assets/style.css", "assets/other.css")
+	s.Equal([]string{"/src/app.py", "/src/assets/"},
+		s.companions(config.ContentTypePythonDash, "src/app.py"))
+}
@@ -11,10 +11,11 @@ import (
 	"github.com/posit-dev/publisher/internal/inspect"
 	"github.com/posit-dev/publisher/internal/inspect/detectors"
 	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/types"
 	"github.com/posit-dev/publisher/internal/util"
 )
 
-var ContentDetectorFactory = detectors.NewContentTypeDetector
+var ContentDetectorFactory = newContentDetector
 var PythonInspectorFactory = inspect.NewPythonInspector
 var RInspectorFactory = inspect.NewRInspector
 
@@ -24,16 +25,64 @@ const initialComment = ` Configuration file generated by Posit Publisher.
  Please review and modify as needed. See the documentation for more options:
  https://github.com/posit-dev/publisher/blob/main/docs/configuration.md`
 
-func inspectProject(base util.AbsolutePath, python util.Path, rExecutable util.Path, log logging.Logger) (*config.Config, error) {
-	log.Info("Detecting deployment type and entrypoint...", "path", base.String())
+func newContentDetector(log logging.Logger) detectors.ContentTypeInferer {
+	return detectors.NewContentTypeDetector(log)
+}
+
+// DetectionOptions enable detection of content
+// that isn't found by default.
+type DetectionOptions struct {
+	// SearchDepth is how many levels of subdirectories
+	// to search for entrypoints, up to detectors.MaxSearchDepth.
+	SearchDepth int
+
+	// ScanImports enables detection of Python apps whose
+	// framework is imported by a project module rather than
+	// the entrypoint itself.
+	ScanImports bool
+
+	// Cache holds the results of earlier detections,
+	// which are reused if the project hasn't changed.
+	// If it is nil, results aren't cached.
+	Cache *detectors.DetectionCache
+
+	// RequireContent fails with ErrorNoDeployableContent,
+	// instead of creating a configuration with an unknown
+	// type, if no deployable content is detected.
+	RequireContent bool
+}
+
+func newConfiguredContentDetector(opts DetectionOptions, log logging.Logger) detectors.ContentTypeInferer {
 	typeDetector := ContentDetectorFactory(log)
+	if d, ok := typeDetector.(detectors.NestedDetector); ok {
+		d.SetSearchDepth(opts.SearchDepth)
+	}
+	if d, ok := typeDetector.(detectors.ImportScanningDetector); ok {
+		d.SetScanImports(opts.ScanImports)
+	}
+	if d, ok := typeDetector.(detectors.CachingDetector); ok && opts.Cache != nil {
+		d.SetCache(opts.Cache)
+	}
+	return typeDetector
+}
+
+func inspectProject(base util.AbsolutePath, python util.Path, rExecutable util.Path, opts DetectionOptions, log logging.Logger) (*config.Config, error) {
+	log.Info("Detecting deployment type and entrypoint...", "path", base.String())
+	typeDetector := newConfiguredContentDetector(opts, log)
 
 	configs, err := typeDetector.InferType(base, util.RelativePath{})
 	if err != nil {
+		if _, ok := err.(*types.AgentError); ok {
+			// Already actionable, like a missing Quarto installation.
+			return nil, err
+		}
 		return nil, fmt.Errorf("error detecting content type: %w", err)
 	}
-	if len(configs) == 0 {
-		return nil, errNoDeployableContent
+	// Detectors return a lone configuration with an
+	// unknown type when nothing deployable was found.
+	onlyUnknown := len(configs) == 1 && configs[0].Type == config.ContentTypeUnknown
+	if len(configs) == 0 || (onlyUnknown && opts.RequireContent) {
+		return nil, types.NewAgentError(types.ErrorNoDeployableContent, errNoDeployableContent, nil)
 	}
 	// Command line `init` takes the first detected configuration.
 	cfg := configs[0]
@@ -76,6 +125,22 @@ func inspectProject(base util.AbsolutePath, python util.Path, rExecutable util.P
 	return cfg, nil
 }
 
+// pythonExecutable returns the Python interpreter to inspect: the one
+// specified by the caller, or else the one set in the configuration.
+// If neither is set, the inspector will look for Python on PATH.
+func pythonExecutable(cfg *config.Config, base util.AbsolutePath, python util.Path) util.Path {
+	if python.String() != "" || cfg.Python == nil || cfg.Python.Executable == "" {
+		return python
+	}
+	configured := util.NewPath(cfg.Python.Executable, base.Fs())
+	if !configured.IsAbs() && strings.ContainsAny(cfg.Python.Executable, `/\`) {
+		// Relative paths are relative to the project directory.
+		// A bare name is looked up on PATH by the inspector.
+		return base.Join(cfg.Python.Executable).Path
+	}
+	return configured
+}
+
 func requiresPython(cfg *config.Config, base util.AbsolutePath) (bool, error) {
 	if cfg.Python != nil && cfg.Python.Version == "" {
 		// InferType returned a python configuration for us to fill in.
@@ -86,10 +151,15 @@ func requiresPython(cfg *config.Config, base util.AbsolutePath) (bool, error) {
 	// doesn't rely on environment inspection.
 	requirementsPath := base.Join(bundles.PythonRequirementsFilename)
 	exists, err := requirementsPath.Exists()
-	if err != nil {
-		return false, err
+	if err != nil || exists {
+		return exists, err
 	}
-	return exists, nil
+	// So does a Poetry or Pipenv project.
+	isPoetry, err := inspect.IsPoetryProject(base)
+	if err != nil || isPoetry {
+		return isPoetry, err
+	}
+	return base.Join(inspect.PipfileFilename).Exists()
 }
 
 func requiresR(cfg *config.Config, base util.AbsolutePath, rExecutable util.Path) (bool, error) {
@@ -149,6 +219,10 @@ func normalizeConfig(
 	} else {
 		log.Debug("Inspector populate files list", "total_files", len(cfg.Files))
 	}
+	err := addCompanionFiles(cfg, base)
+	if err != nil {
+		return err
+	}
 
 	needPython, err := requiresPython(cfg, base)
 	if err != nil {
@@ -157,7 +231,7 @@ func normalizeConfig(
 	}
 	if needPython {
 		log.Debug("Determined that Python is required")
-		inspector := PythonInspectorFactory(base, python, log)
+		inspector := PythonInspectorFactory(base, pythonExecutable(cfg, base, python), log)
 		pyConfig, err := inspector.InspectPython()
 		if err != nil {
 			log.Debug("Error while inspecting to generate a Python based configuration", "error", err.Error())
@@ -191,12 +265,21 @@ func GetPossibleConfigs(
 	python util.Path,
 	rExecutable util.Path,
 	entrypoint util.RelativePath,
+	opts DetectionOptions,
 	log logging.Logger) ([]*config.Config, error) {
 
 	log.Info("Detecting deployment type and entrypoint...", "path", base.String())
-	typeDetector := ContentDetectorFactory(log)
+	entrypoint, err := detectors.ResolveEntrypoint(base, entrypoint)
+	if err != nil {
+		return nil, err
+	}
+	typeDetector := newConfiguredContentDetector(opts, log)
 	configs, err := typeDetector.InferType(base, entrypoint)
 	if err != nil {
+		if _, ok := err.(*types.AgentError); ok {
+			// Already actionable, like a missing Quarto installation.
+			return nil, err
+		}
 		return nil, fmt.Errorf("error detecting content type: %w", err)
 	}
 
@@ -209,11 +292,11 @@ func GetPossibleConfigs(
 	return configs, nil
 }
 
-func Init(base util.AbsolutePath, configName string, python util.Path, rExecutable util.Path, log logging.Logger) (*config.Config, error) {
+func Init(base util.AbsolutePath, configName string, python util.Path, rExecutable util.Path, opts DetectionOptions, log logging.Logger) (*config.Config, error) {
 	if configName == "" {
 		configName = config.DefaultConfigName
 	}
-	cfg, err := inspectProject(base, python, rExecutable, log)
+	cfg, err := inspectProject(base, python, rExecutable, opts, log)
 	if err != nil {
 		return nil, err
 	}
@@ -230,18 +313,35 @@ func Init(base util.AbsolutePath, configName string, python util.Path, rExecutab
 }
 
 // InitIfNeeded runs an auto-initialize if the specified config file does not exist.
+// If no config name is given, an existing configuration is used
+// if there is exactly one.
 func InitIfNeeded(path util.AbsolutePath, configName string, log logging.Logger) error {
+	_, _, err := EnsureConfig(path, configName, util.Path{}, util.Path{}, log)
+	return err
+}
+
+// EnsureConfig is like InitIfNeeded, but also returns the name of the
+// configuration and whether it was created.
+func EnsureConfig(path util.AbsolutePath, configName string, python util.Path, rExecutable util.Path, log logging.Logger) (string, bool, error) {
+	if configName == "" {
+		var err error
+		configName, err = config.DiscoverConfigName(path)
+		if err != nil {
+			return "", false, err
+		}
+	}
 	configPath := config.GetConfigPath(path, configName)
 	exists, err := configPath.Exists()
 	if err != nil {
-		return err
+		return "", false, err
 	}
-	if !exists {
-		log.Info("Configuration file does not exist; creating it", "path", configPath.String())
-		_, err = Init(path, configName, util.Path{}, util.Path{}, log)
-		if err != nil {
-			return err
-		}
+	if exists {
+		return configName, false, nil
 	}
-	return nil
+	log.Info("Configuration file does not exist; creating it", "path", configPath.String())
+	_, err = Init(path, configName, python, rExecutable, DetectionOptions{RequireContent: true}, log)
+	if err != nil {
+		return "", false, err
+	}
+	return configName, true, nil
 }
@@ -3,12 +3,14 @@ package initialize
 // Copyright (C) 2023 by Posit Software, PBC.
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/posit-dev/publisher/internal/config"
 	"github.com/posit-dev/publisher/internal/inspect"
 	"github.com/posit-dev/publisher/internal/inspect/detectors"
 	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/types"
 	"github.com/posit-dev/publisher/internal/util"
 	"github.com/posit-dev/publisher/internal/util/utiltest"
 	"github.com/spf13/afero"
@@ -26,7 +28,7 @@ func TestInitializeSuite(t *testing.T) {
 
 func (s *InitializeSuite) SetupTest() {
 	// Restore default factories for each test
-	ContentDetectorFactory = detectors.NewContentTypeDetector
+	ContentDetectorFactory = newContentDetector
 	PythonInspectorFactory = inspect.NewPythonInspector
 
 	cwd, err := util.Getwd(afero.NewMemMapFs())
@@ -43,7 +45,7 @@ func (s *InitializeSuite) TestInitEmpty() {
 	err := path.Mkdir(0777)
 	s.NoError(err)
 
-	cfg, err := Init(path, "", util.Path{}, util.Path{}, log)
+	cfg, err := Init(path, "", util.Path{}, util.Path{}, DetectionOptions{}, log)
 	s.Nil(err)
 	s.Equal(config.ContentTypeUnknown, cfg.Type)
 	s.Equal("My App", cfg.Title)
@@ -93,7 +95,7 @@ func (s *InitializeSuite) TestInitInferredType() {
 	s.createAppPy()
 	PythonInspectorFactory = makeMockPythonInspector
 	configName := ""
-	cfg, err := Init(s.cwd, configName, util.Path{}, util.Path{}, log)
+	cfg, err := Init(s.cwd, configName, util.Path{}, util.Path{}, DetectionOptions{}, log)
 	s.NoError(err)
 	configPath := config.GetConfigPath(s.cwd, configName)
 	cfg2, err := config.FromFile(configPath)
@@ -103,13 +105,52 @@ func (s *InitializeSuite) TestInitInferredType() {
 	s.Equal(cfg, cfg2)
 }
 
+func (s *InitializeSuite) TestInitPoetryProject() {
+	log := logging.New()
+	s.createHTML()
+	err := s.cwd.Join(inspect.PoetryLockFilename).WriteFile([]byte("[metadata]\n"), 0666)
+	s.NoError(err)
+	poetryConfig := &config.Python{
+		Version:        "3.4.5",
+		PackageManager: "poetry",
+		PackageFile:    "requirements.txt",
+	}
+	PythonInspectorFactory = func(util.AbsolutePath, util.Path, logging.Logger) inspect.PythonInspector {
+		pyInspector := inspect.NewMockPythonInspector()
+		pyInspector.On("InspectPython").Return(poetryConfig, nil)
+		return pyInspector
+	}
+	configName := ""
+	cfg, err := Init(s.cwd, configName, util.Path{}, util.Path{}, DetectionOptions{}, log)
+	s.NoError(err)
+	configPath := config.GetConfigPath(s.cwd, configName)
+	cfg2, err := config.FromFile(configPath)
+	s.NoError(err)
+	s.Equal(poetryConfig, cfg.Python)
+	s.Equal(cfg, cfg2)
+}
+
+func (s *InitializeSuite) TestRequiresPythonPipenv() {
+	cfg := config.New()
+	cfg.Type = config.ContentTypeHTML
+	needPython, err := requiresPython(cfg, s.cwd)
+	s.NoError(err)
+	s.False(needPython)
+
+	err = s.cwd.Join(inspect.PipfileFilename).WriteFile([]byte("[packages]\n"), 0666)
+	s.NoError(err)
+	needPython, err = requiresPython(cfg, s.cwd)
+	s.NoError(err)
+	s.True(needPython)
+}
+
 func (s *InitializeSuite) TestInitRequirementsFile() {
 	log := logging.New()
 	s.createHTML()
 	s.createRequirementsFile()
 	PythonInspectorFactory = makeMockPythonInspector
 	configName := ""
-	cfg, err := Init(s.cwd, configName, util.Path{}, util.Path{}, log)
+	cfg, err := Init(s.cwd, configName, util.Path{}, util.Path{}, DetectionOptions{}, log)
 	s.NoError(err)
 	configPath := config.GetConfigPath(s.cwd, configName)
 	cfg2, err := config.FromFile(configPath)
@@ -133,6 +174,18 @@ func (s *InitializeSuite) TestInitIfNeededWhenNeeded() {
 	s.Equal("3.4.5", cfg.Python.Version)
 }
 
+func (s *InitializeSuite) TestInitIfNeededNoDeployableContent() {
+	// Unlike Init, a configuration with an unknown type isn't created.
+	log := logging.New()
+	err := InitIfNeeded(s.cwd, "", log)
+	_, ok := types.IsAgentErrorOf(err, types.ErrorNoDeployableContent)
+	s.True(ok)
+
+	exists, err := config.GetConfigPath(s.cwd, "").Exists()
+	s.NoError(err)
+	s.False(exists)
+}
+
 func (s *InitializeSuite) TestInitIfNeededWhenNotNeeded() {
 	log := logging.New()
 	configName := ""
@@ -165,7 +218,7 @@ func (s *InitializeSuite) TestGetPossibleConfigs() {
 	s.NoError(err)
 
 	PythonInspectorFactory = makeMockPythonInspector
-	configs, err := GetPossibleConfigs(s.cwd, util.Path{}, util.Path{}, util.RelativePath{}, log)
+	configs, err := GetPossibleConfigs(s.cwd, util.Path{}, util.Path{}, util.RelativePath{}, DetectionOptions{}, log)
 	s.NoError(err)
 
 	s.Len(configs, 2)
@@ -183,7 +236,7 @@ func (s *InitializeSuite) TestGetPossibleConfigs() {
 func (s *InitializeSuite) TestGetPossibleConfigsEmpty() {
 	log := logging.New()
 
-	configs, err := GetPossibleConfigs(s.cwd, util.Path{}, util.Path{}, util.RelativePath{}, log)
+	configs, err := GetPossibleConfigs(s.cwd, util.Path{}, util.Path{}, util.RelativePath{}, DetectionOptions{}, log)
 	s.NoError(err)
 
 	s.Len(configs, 1)
@@ -197,7 +250,7 @@ func (s *InitializeSuite) TestGetPossibleConfigsWithMissingEntrypoint() {
 	s.createAppPy()
 
 	entrypoint := util.NewRelativePath("nonexistent.py", s.cwd.Fs())
-	configs, err := GetPossibleConfigs(s.cwd, util.Path{}, util.Path{}, entrypoint, log)
+	configs, err := GetPossibleConfigs(s.cwd, util.Path{}, util.Path{}, entrypoint, DetectionOptions{}, log)
 	s.NoError(err)
 
 	s.Len(configs, 1)
@@ -206,6 +259,109 @@ func (s *InitializeSuite) TestGetPossibleConfigsWithMissingEntrypoint() {
 	s.Nil(configs[0].Python)
 }
 
+func (s *InitializeSuite) TestGetPossibleConfigsWithEntrypointGlob() {
+	log := logging.New()
+	s.createAppPy()
+
+	PythonInspectorFactory = makeMockPythonInspector
+	entrypoint := util.NewRelativePath("a*.py", s.cwd.Fs())
+	configs, err := GetPossibleConfigs(s.cwd, util.Path{}, util.Path{}, entrypoint, DetectionOptions{}, log)
+	s.NoError(err)
+
+	s.Len(configs, 1)
+	s.Equal(config.ContentTypePythonFlask, configs[0].Type)
+	s.Equal("app.py", configs[0].Entrypoint)
+}
+
+func (s *InitializeSuite) TestGetPossibleConfigsWithUnmatchedEntrypointGlob() {
+	log := logging.New()
+	s.createAppPy()
+
+	entrypoint := util.NewRelativePath("main_*.py", s.cwd.Fs())
+	_, err := GetPossibleConfigs(s.cwd, util.Path{}, util.Path{}, entrypoint, DetectionOptions{}, log)
+	s.ErrorIs(err, detectors.ErrNoEntrypointMatch)
+}
+
+func (s *InitializeSuite) TestGetPossibleConfigsSearchDepth() {
+	log := logging.New()
+	srcDir := s.cwd.Join("src")
+	err := srcDir.MkdirAll(0777)
+	s.NoError(err)
+	err = srcDir.Join("app.py").WriteFile([]byte("from flask import Flask\n"), 0666)
+	s.NoError(err)
+
+	PythonInspectorFactory = makeMockPythonInspector
+	configs, err := GetPossibleConfigs(s.cwd, util.Path{}, util.Path{}, util.RelativePath{}, DetectionOptions{}, log)
+	s.NoError(err)
+	s.Len(configs, 1)
+	s.Equal(config.ContentTypeUnknown, configs[0].Type)
+
+	opts := DetectionOptions{SearchDepth: 1}
+	configs, err = GetPossibleConfigs(s.cwd, util.Path{}, util.Path{}, util.RelativePath{}, opts, log)
+	s.NoError(err)
+	s.Len(configs, 1)
+	s.Equal(config.ContentTypePythonFlask, configs[0].Type)
+	s.Equal("src/app.py", configs[0].Entrypoint)
+}
+
+func (s *InitializeSuite) TestGetPossibleConfigsScanImports() {
+	log := logging.New()
+	err := s.cwd.Join("myapp").MkdirAll(0777)
+	s.NoError(err)
+	err = s.cwd.Join("app.py").WriteFile([]byte("from myapp.server import app\n"), 0666)
+	s.NoError(err)
+	err = s.cwd.Join("myapp", "server.py").WriteFile([]byte("from flask import Flask\napp = Flask(__name__)\n"), 0666)
+	s.NoError(err)
+
+	PythonInspectorFactory = makeMockPythonInspector
+	opts := DetectionOptions{ScanImports: true}
+	configs, err := GetPossibleConfigs(s.cwd, util.Path{}, util.Path{}, util.RelativePath{}, opts, log)
+	s.NoError(err)
+	s.Len(configs, 1)
+	s.Equal(config.ContentTypePythonFlask, configs[0].Type)
+	s.Equal("app.py", configs[0].Entrypoint)
+}
+
+func (s *InitializeSuite) TestInitScanImports() {
+	log := logging.New()
+	err := s.cwd.Join("myapp").MkdirAll(0777)
+	s.NoError(err)
+	err = s.cwd.Join("app.py").WriteFile([]byte("import myapp.server\n"), 0666)
+	s.NoError(err)
+	err = s.cwd.Join("myapp", "server.py").WriteFile([]byte("import streamlit as st\n"), 0666)
+	s.NoError(err)
+
+	PythonInspectorFactory = makeMockPythonInspector
+	cfg, err := Init(s.cwd, "", util.Path{}, util.Path{}, DetectionOptions{}, log)
+	s.NoError(err)
+	s.Equal(config.ContentTypeUnknown, cfg.Type)
+
+	cfg, err = Init(s.cwd, "", util.Path{}, util.Path{}, DetectionOptions{ScanImports: true}, log)
+	s.NoError(err)
+	s.Equal(config.ContentTypePythonStreamlit, cfg.Type)
+	s.Equal("app.py", cfg.Entrypoint)
+}
+
+type failingDetector struct {
+	err error
+}
+
+func (d failingDetector) InferType(base util.AbsolutePath, entrypoint util.RelativePath) ([]*config.Config, error) {
+	return nil, d.err
+}
+
+func (s *InitializeSuite) TestGetPossibleConfigsAgentError() {
+	// Agent errors are returned as-is, so the UI can
+	// recognize them and show what to do.
+	agentErr := types.NewAgentError(types.ErrorQuartoNotFound, errors.New("quarto is not installed"), nil)
+	ContentDetectorFactory = func(log logging.Logger) detectors.ContentTypeInferer {
+		return failingDetector{err: agentErr}
+	}
+	_, err := GetPossibleConfigs(s.cwd, util.Path{}, util.Path{}, util.RelativePath{}, DetectionOptions{}, logging.New())
+	_, ok := types.IsAgentErrorOf(err, types.ErrorQuartoNotFound)
+	s.True(ok)
+}
+
 func (s *InitializeSuite) TestNormalizeConfigHandlesUnknownConfigs() {
 	log := logging.New()
 
@@ -0,0 +1,47 @@
+package initialize
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"github.com/posit-dev/publisher/internal/config"
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/util"
+)
+
+// ResolveConfig loads the named configuration and returns it as it will
+// be used for deployment: with defaults applied, and with Python and R
+// versions that the configuration leaves unspecified filled in by
+// inspecting the local interpreters. The `python` interpreter, if
+// specified, takes precedence over the configured python.executable.
+func ResolveConfig(
+	base util.AbsolutePath,
+	configName string,
+	python util.Path,
+	rExecutable util.Path,
+	log logging.Logger) (*config.Config, error) {
+
+	// FromFile applies the defaults.
+	cfg, err := config.FromFile(config.GetConfigPath(base, configName))
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Python != nil && cfg.Python.Version == "" {
+		log.Debug("Python version is not configured; inspecting")
+		inspector := PythonInspectorFactory(base, pythonExecutable(cfg, base, python), log)
+		pyConfig, err := inspector.InspectPython()
+		if err != nil {
+			return nil, err
+		}
+		cfg.Python.Version = pyConfig.Version
+	}
+	if cfg.R != nil && cfg.R.Version == "" {
+		log.Debug("R version is not configured; inspecting")
+		inspector := RInspectorFactory(base, rExecutable, log)
+		rConfig, err := inspector.InspectR()
+		if err != nil {
+			return nil, err
+		}
+		cfg.R.Version = rConfig.Version
+	}
+	return cfg, nil
+}
@@ -0,0 +1,184 @@
+package initialize
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/posit-dev/publisher/internal/config"
+	"github.com/posit-dev/publisher/internal/inspect"
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/types"
+	"github.com/posit-dev/publisher/internal/util"
+	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/suite"
+)
+
+type ResolveConfigSuite struct {
+	utiltest.Suite
+	cwd util.AbsolutePath
+}
+
+func TestResolveConfigSuite(t *testing.T) {
+	suite.Run(t, new(ResolveConfigSuite))
+}
+
+func (s *ResolveConfigSuite) SetupTest() {
+	PythonInspectorFactory = inspect.NewPythonInspector
+	RInspectorFactory = inspect.NewRInspector
+
+	cwd, err := util.Getwd(afero.NewMemMapFs())
+	s.NoError(err)
+	s.cwd = cwd
+	err = cwd.MkdirAll(0700)
+	s.NoError(err)
+}
+
+func (s *ResolveConfigSuite) TearDownTest() {
+	PythonInspectorFactory = inspect.NewPythonInspector
+	RInspectorFactory = inspect.NewRInspector
+}
+
+func (s *ResolveConfigSuite) writeConfig(cfg *config.Config) {
+	err := cfg.WriteFile(config.GetConfigPath(s.cwd, "myConfig"))
+	s.NoError(err)
+}
+
+func makeMockRInspector(util.AbsolutePath, util.Path, logging.Logger) inspect.RInspector {
+	rInspector := inspect.NewMockRInspector()
+	rInspector.On("InspectR").Return(&config.R{
+		Version:        "4.3.2",
+		PackageFile:    "renv.lock",
+		PackageManager: "renv",
+	}, nil)
+	return rInspector
+}
+
+func (s *ResolveConfigSuite) TestResolveConfigInspectsVersions() {
+	cfg := config.New()
+	cfg.Type = config.ContentTypeRMarkdown
+	cfg.Entrypoint = "report.Rmd"
+	cfg.Python = &config.Python{}
+	cfg.R = &config.R{}
+	s.writeConfig(cfg)
+
+	PythonInspectorFactory = makeMockPythonInspector
+	RInspectorFactory = makeMockRInspector
+
+	resolved, err := ResolveConfig(s.cwd, "myConfig", util.Path{}, util.Path{}, logging.New())
+	s.NoError(err)
+	s.Equal(&config.Python{
+		Version:        "3.4.5",
+		PackageFile:    "requirements.txt",
+		PackageManager: "pip",
+	}, resolved.Python)
+	s.Equal(&config.R{
+		Version:        "4.3.2",
+		PackageFile:    "renv.lock",
+		PackageManager: "renv",
+	}, resolved.R)
+}
+
+func (s *ResolveConfigSuite) TestResolveConfigKeepsConfiguredValues() {
+	cfg := config.New()
+	cfg.Type = config.ContentTypePythonDash
+	cfg.Entrypoint = "app.py"
+	cfg.Python = &config.Python{
+		Version:     "3.11.3",
+		PackageFile: "reqs.txt",
+	}
+	s.writeConfig(cfg)
+
+	// Inspection is not needed
+	PythonInspectorFactory = func(util.AbsolutePath, util.Path, logging.Logger) inspect.PythonInspector {
+		s.Fail("unexpected Python inspection")
+		return nil
+	}
+	resolved, err := ResolveConfig(s.cwd, "myConfig", util.Path{}, util.Path{}, logging.New())
+	s.NoError(err)
+	s.Equal(&config.Python{
+		Version:        "3.11.3",
+		PackageFile:    "reqs.txt",
+		PackageManager: "pip",
+	}, resolved.Python)
+	s.Nil(resolved.R)
+}
+
+func (s *ResolveConfigSuite) TestResolveConfigInspectionErr() {
+	cfg := config.New()
+	cfg.Type = config.ContentTypePythonDash
+	cfg.Entrypoint = "app.py"
+	cfg.Python = &config.Python{}
+	s.writeConfig(cfg)
+
+	testError := errors.New("test error from InspectPython")
+	PythonInspectorFactory = func(util.AbsolutePath, util.Path, logging.Logger) inspect.PythonInspector {
+		i := inspect.NewMockPythonInspector()
+		i.On("InspectPython").Return(nil, testError)
+		return i
+	}
+	_, err := ResolveConfig(s.cwd, "myConfig", util.Path{}, util.Path{}, logging.New())
+	s.ErrorIs(err, testError)
+}
+
+func (s *ResolveConfigSuite) TestResolveConfigUsesConfiguredExecutable() {
+	cfg := config.New()
+	cfg.Type = config.ContentTypePythonDash
+	cfg.Entrypoint = "app.py"
+	cfg.Python = &config.Python{
+		Executable: ".venv/bin/python",
+	}
+	s.writeConfig(cfg)
+
+	var inspectedPython util.Path
+	PythonInspectorFactory = func(base util.AbsolutePath, python util.Path, log logging.Logger) inspect.PythonInspector {
+		inspectedPython = python
+		return makeMockPythonInspector(base, python, log)
+	}
+	resolved, err := ResolveConfig(s.cwd, "myConfig", util.Path{}, util.Path{}, logging.New())
+	s.NoError(err)
+	s.Equal(s.cwd.Join(".venv", "bin", "python").String(), inspectedPython.String())
+	s.Equal("3.4.5", resolved.Python.Version)
+	s.Equal(".venv/bin/python", resolved.Python.Executable)
+}
+
+func (s *ResolveConfigSuite) TestResolveConfigExplicitPythonOverridesConfigured() {
+	cfg := config.New()
+	cfg.Type = config.ContentTypePythonDash
+	cfg.Entrypoint = "app.py"
+	cfg.Python = &config.Python{
+		Executable: ".venv/bin/python",
+	}
+	s.writeConfig(cfg)
+
+	var inspectedPython util.Path
+	PythonInspectorFactory = func(base util.AbsolutePath, python util.Path, log logging.Logger) inspect.PythonInspector {
+		inspectedPython = python
+		return makeMockPythonInspector(base, python, log)
+	}
+	python := util.NewPath("/usr/bin/python3", nil)
+	_, err := ResolveConfig(s.cwd, "myConfig", python, util.Path{}, logging.New())
+	s.NoError(err)
+	s.Equal(python, inspectedPython)
+}
+
+func (s *ResolveConfigSuite) TestResolveConfigConfiguredExecutableNotFound() {
+	cfg := config.New()
+	cfg.Type = config.ContentTypePythonDash
+	cfg.Entrypoint = "app.py"
+	cfg.Python = &config.Python{
+		Executable: "bin/nonexistent-python",
+	}
+	s.writeConfig(cfg)
+
+	_, err := ResolveConfig(s.cwd, "myConfig", util.Path{}, util.Path{}, logging.New())
+	_, isPythonExecErr := types.IsAgentErrorOf(err, types.ErrorPythonExecNotFound)
+	s.True(isPythonExecErr)
+}
+
+func (s *ResolveConfigSuite) TestResolveConfigNotFound() {
+	_, err := ResolveConfig(s.cwd, "nonexistent", util.Path{}, util.Path{}, logging.New())
+	s.ErrorContains(err, "nonexistent.toml")
+}
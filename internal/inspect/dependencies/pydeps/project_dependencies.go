@@ -3,12 +3,14 @@ package pydeps
 // Copyright (C) 2023 by Posit Software, PBC.
 
 import (
+	"time"
+
 	"github.com/posit-dev/publisher/internal/logging"
 	"github.com/posit-dev/publisher/internal/util"
 )
 
 type DependencyScanner interface {
-	ScanDependencies(base util.AbsolutePath, pythonExecutable string) ([]*PackageSpec, error)
+	ScanDependencies(base util.AbsolutePath, pythonExecutable string, deadline time.Time) ([]*PackageSpec, bool, error)
 }
 
 type defaultDependencyScanner struct {
@@ -25,14 +27,24 @@ func NewDependencyScanner(log logging.Logger) *defaultDependencyScanner {
 	}
 }
 
-func (s *defaultDependencyScanner) ScanDependencies(base util.AbsolutePath, pythonExecutable string) ([]*PackageSpec, error) {
-	importNames, err := s.scanner.ScanProjectImports(base)
+// ScanDependencies returns the packages imported by the project, with
+// versions from the packages installed for the specified Python.
+// If the deadline (which may be zero for none) passes, it returns
+// the packages found so far, without versions if they were not yet
+// resolved, and true to indicate that the result is partial.
+func (s *defaultDependencyScanner) ScanDependencies(base util.AbsolutePath, pythonExecutable string, deadline time.Time) ([]*PackageSpec, bool, error) {
+	importNames, partial, err := s.scanner.ScanProjectImports(base, deadline)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
-	mapping, err := s.mapper.GetPackageMap(pythonExecutable)
-	if err != nil {
-		return nil, err
+	mapping := PackageMap{}
+	if deadlineExceeded(deadline) {
+		partial = true
+	} else {
+		mapping, err = s.mapper.GetPackageMap(pythonExecutable)
+		if err != nil {
+			return nil, false, err
+		}
 	}
 	specs := []*PackageSpec{}
 	for _, importName := range importNames {
@@ -40,7 +52,8 @@ func (s *defaultDependencyScanner) ScanDependencies(base util.AbsolutePath, pyth
 		if ok {
 			specs = append(specs, spec)
 		} else {
-			// We didn't see this package installed or in our stdlib list.
+			// We didn't see this package installed or in our stdlib list,
+			// or ran out of time before looking.
 			// Assume it's installable under its import name.
 			specs = append(specs, &PackageSpec{
 				Name:    PackageName(importName),
@@ -48,5 +61,5 @@ func (s *defaultDependencyScanner) ScanDependencies(base util.AbsolutePath, pyth
 			})
 		}
 	}
-	return specs, nil
+	return specs, partial, nil
 }
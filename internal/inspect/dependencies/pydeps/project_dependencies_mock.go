@@ -3,6 +3,8 @@ package pydeps
 // Copyright (C) 2023 by Posit Software, PBC.
 
 import (
+	"time"
+
 	"github.com/posit-dev/publisher/internal/util"
 	"github.com/stretchr/testify/mock"
 )
@@ -15,12 +17,12 @@ func NewMockDependencyScanner() *MockDependencyScanner {
 	return &MockDependencyScanner{}
 }
 
-func (m *MockDependencyScanner) ScanDependencies(base util.AbsolutePath, pythonExecutable string) ([]*PackageSpec, error) {
-	args := m.Called(base, pythonExecutable)
+func (m *MockDependencyScanner) ScanDependencies(base util.AbsolutePath, pythonExecutable string, deadline time.Time) ([]*PackageSpec, bool, error) {
+	args := m.Called(base, pythonExecutable, deadline)
 	specs := args.Get(0)
 	if specs == nil {
-		return nil, args.Error(1)
+		return nil, args.Bool(1), args.Error(2)
 	} else {
-		return specs.([]*PackageSpec), args.Error(1)
+		return specs.([]*PackageSpec), args.Bool(1), args.Error(2)
 	}
 }
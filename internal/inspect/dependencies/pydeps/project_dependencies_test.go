@@ -0,0 +1,107 @@
+package pydeps
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"testing"
+	"time"
+
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/util"
+	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/suite"
+)
+
+type DependencyScannerSuite struct {
+	utiltest.Suite
+	base util.AbsolutePath
+}
+
+func TestDependencyScannerSuite(t *testing.T) {
+	suite.Run(t, new(DependencyScannerSuite))
+}
+
+func (s *DependencyScannerSuite) SetupTest() {
+	s.base = util.NewAbsolutePath("/project", afero.NewMemMapFs())
+}
+
+type fakeProjectImportScanner struct {
+	imports []ImportName
+	partial bool
+	delay   time.Duration
+}
+
+func (f *fakeProjectImportScanner) ScanProjectImports(util.AbsolutePath, time.Time) ([]ImportName, bool, error) {
+	time.Sleep(f.delay)
+	return f.imports, f.partial, nil
+}
+
+type fakePackageMapper struct {
+	mapping PackageMap
+	called  bool
+}
+
+func (f *fakePackageMapper) GetPackageMap(string) (PackageMap, error) {
+	f.called = true
+	return f.mapping, nil
+}
+
+func (s *DependencyScannerSuite) makeScanner(imports *fakeProjectImportScanner) (*defaultDependencyScanner, *fakePackageMapper) {
+	mapper := &fakePackageMapper{
+		mapping: PackageMap{
+			"numpy":   {Name: "numpy", Version: "1.26.1"},
+			"sklearn": {Name: "scikit-learn", Version: "1.3.2"},
+		},
+	}
+	scanner := NewDependencyScanner(logging.New())
+	scanner.scanner = imports
+	scanner.mapper = mapper
+	return scanner, mapper
+}
+
+func (s *DependencyScannerSuite) TestScanDependencies() {
+	scanner, _ := s.makeScanner(&fakeProjectImportScanner{
+		imports: []ImportName{"numpy", "sklearn", "unknown"},
+	})
+	specs, partial, err := scanner.ScanDependencies(s.base, "python3", time.Time{})
+	s.NoError(err)
+	s.False(partial)
+	s.Equal([]*PackageSpec{
+		{Name: "numpy", Version: "1.26.1"},
+		{Name: "scikit-learn", Version: "1.3.2"},
+		{Name: "unknown", Version: ""},
+	}, specs)
+}
+
+func (s *DependencyScannerSuite) TestScanDependenciesPartialImports() {
+	scanner, mapper := s.makeScanner(&fakeProjectImportScanner{
+		imports: []ImportName{"numpy"},
+		partial: true,
+	})
+	specs, partial, err := scanner.ScanDependencies(s.base, "python3", time.Now().Add(time.Minute))
+	s.NoError(err)
+	s.True(partial)
+	s.True(mapper.called)
+	s.Equal([]*PackageSpec{
+		{Name: "numpy", Version: "1.26.1"},
+	}, specs)
+}
+
+func (s *DependencyScannerSuite) TestScanDependenciesDeadlineBeforeVersions() {
+	// The import scan is slow enough that the deadline passes
+	// before versions can be looked up.
+	scanner, mapper := s.makeScanner(&fakeProjectImportScanner{
+		imports: []ImportName{"numpy", "sklearn"},
+		delay:   50 * time.Millisecond,
+	})
+	deadline := time.Now().Add(10 * time.Millisecond)
+	specs, partial, err := scanner.ScanDependencies(s.base, "python3", deadline)
+	s.NoError(err)
+	s.True(partial)
+	s.False(mapper.called)
+	s.Equal([]*PackageSpec{
+		{Name: "numpy", Version: ""},
+		{Name: "sklearn", Version: ""},
+	}, specs)
+}
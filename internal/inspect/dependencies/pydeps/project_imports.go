@@ -3,10 +3,12 @@ package pydeps
 // Copyright (C) 2023 by Posit Software, PBC.
 
 import (
+	"errors"
 	"fmt"
 	"io/fs"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/posit-dev/publisher/internal/bundles/matcher"
 	"github.com/posit-dev/publisher/internal/logging"
@@ -14,7 +16,14 @@ import (
 )
 
 type ProjectImportScanner interface {
-	ScanProjectImports(base util.AbsolutePath) ([]ImportName, error)
+	ScanProjectImports(base util.AbsolutePath, deadline time.Time) ([]ImportName, bool, error)
+}
+
+var errDeadlineExceeded = errors.New("scan deadline exceeded")
+
+// deadlineExceeded returns true if the (optional) deadline has passed.
+func deadlineExceeded(deadline time.Time) bool {
+	return !deadline.IsZero() && time.Now().After(deadline)
 }
 
 type defaultProjectImportScanner struct {
@@ -29,12 +38,18 @@ func NewProjectImportScanner(log logging.Logger) *defaultProjectImportScanner {
 	}
 }
 
-func (s *defaultProjectImportScanner) ScanProjectImports(base util.AbsolutePath) ([]ImportName, error) {
+// ScanProjectImports returns the names of the packages imported by the
+// project's Python, notebook, and Quarto files. If the deadline
+// (which may be zero for none) passes before all files are scanned,
+// it returns the imports found so far, and true to indicate
+// that the result is partial.
+func (s *defaultProjectImportScanner) ScanProjectImports(base util.AbsolutePath, deadline time.Time) ([]ImportName, bool, error) {
 	// Scanning is not currently driven by the configured file list - we scan everything.
 	matchList, err := matcher.NewMatchingWalker([]string{"*"}, base, s.log)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
+	partial := false
 
 	projectImports := []ImportName{}
 
@@ -42,6 +57,9 @@ func (s *defaultProjectImportScanner) ScanProjectImports(base util.AbsolutePath)
 		if err != nil {
 			return err
 		}
+		if deadlineExceeded(deadline) {
+			return errDeadlineExceeded
+		}
 		code := ""
 		if info.IsDir() {
 			return nil
@@ -80,11 +98,14 @@ func (s *defaultProjectImportScanner) ScanProjectImports(base util.AbsolutePath)
 		}
 		return nil
 	})
-	if err != nil {
-		return nil, fmt.Errorf("error scanning project imports: %w", err)
+	if errors.Is(err, errDeadlineExceeded) {
+		s.log.Warn("Dependency scan deadline exceeded; returning partial results", "path", base)
+		partial = true
+	} else if err != nil {
+		return nil, false, fmt.Errorf("error scanning project imports: %w", err)
 	}
 	// Sort and de-dup
 	slices.Sort(projectImports)
 	projectImports = slices.Compact(projectImports)
-	return projectImports, nil
+	return projectImports, partial, nil
 }
@@ -3,11 +3,14 @@ package pydeps
 // Copyright (C) 2023 by Posit Software, PBC.
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/posit-dev/publisher/internal/logging"
 	"github.com/posit-dev/publisher/internal/util"
 	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/spf13/afero"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -34,8 +37,9 @@ func (s *ProjectDepsSuite) TestScanProjectImports() {
 	s.NoError(err)
 	path := cwd.Join("testdata")
 
-	importNames, err := scanner.ScanProjectImports(path)
+	importNames, partial, err := scanner.ScanProjectImports(path, time.Time{})
 	s.NoError(err)
+	s.False(partial)
 
 	// "lib" and "example" are not included because they are
 	// local imports, not dependencies.
@@ -46,3 +50,44 @@ func (s *ProjectDepsSuite) TestScanProjectImports() {
 		"that",
 	}, importNames)
 }
+
+type slowImportScanner struct {
+	delay time.Duration
+}
+
+func (s *slowImportScanner) ScanImports(code string) []ImportName {
+	time.Sleep(s.delay)
+	return []ImportName{ImportName(strings.TrimSpace(code))}
+}
+
+func (s *ProjectDepsSuite) TestScanProjectImportsDeadline() {
+	base := util.NewAbsolutePath("/project", afero.NewMemMapFs())
+	err := base.MkdirAll(0777)
+	s.NoError(err)
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		err = base.Join(name+".py").WriteFile([]byte("pkg_"+name), 0666)
+		s.NoError(err)
+	}
+	scanner := NewProjectImportScanner(logging.New())
+	scanner.scanner = &slowImportScanner{delay: 50 * time.Millisecond}
+
+	// The deadline passes while scanning the second file.
+	deadline := time.Now().Add(75 * time.Millisecond)
+	importNames, partial, err := scanner.ScanProjectImports(base, deadline)
+	s.NoError(err)
+	s.True(partial)
+	s.NotEmpty(importNames)
+	s.Less(len(importNames), 5)
+	s.Equal(ImportName("pkg_a"), importNames[0])
+}
+
+func (s *ProjectDepsSuite) TestScanProjectImportsDeadlinePassed() {
+	cwd, err := util.Getwd(nil)
+	s.NoError(err)
+	scanner := NewProjectImportScanner(logging.New())
+
+	importNames, partial, err := scanner.ScanProjectImports(cwd.Join("testdata"), time.Now().Add(-time.Second))
+	s.NoError(err)
+	s.True(partial)
+	s.Empty(importNames)
+}
@@ -0,0 +1,80 @@
+package pydeps
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"time"
+
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/util"
+)
+
+// ScannerRegistry is a DependencyScanner that dispatches to the scanner
+// for the project's packaging ecosystem. The ecosystem is selected by
+// marker files (such as poetry.lock) in the project directory.
+// Projects without any registered markers use the default scanner.
+type ScannerRegistry struct {
+	entries  []scannerEntry
+	fallback DependencyScanner
+	log      logging.Logger
+}
+
+type scannerEntry struct {
+	name    string
+	markers []string
+	scanner DependencyScanner
+}
+
+var _ DependencyScanner = &ScannerRegistry{}
+
+const defaultScannerName = "pip"
+
+func NewScannerRegistry(fallback DependencyScanner, log logging.Logger) *ScannerRegistry {
+	return &ScannerRegistry{
+		fallback: fallback,
+		log:      log,
+	}
+}
+
+// NewDefaultScannerRegistry returns a registry containing
+// the built-in scanners.
+func NewDefaultScannerRegistry(log logging.Logger) *ScannerRegistry {
+	return NewScannerRegistry(NewDependencyScanner(log), log)
+}
+
+// Register adds a scanner for the named ecosystem, selected when any of
+// the marker files exists in the project directory. Scanners are
+// checked in the order they were registered.
+func (r *ScannerRegistry) Register(name string, markers []string, scanner DependencyScanner) {
+	r.entries = append(r.entries, scannerEntry{
+		name:    name,
+		markers: markers,
+		scanner: scanner,
+	})
+}
+
+// ScannerFor returns the name of the ecosystem and
+// the scanner to use for the project in base.
+func (r *ScannerRegistry) ScannerFor(base util.AbsolutePath) (string, DependencyScanner, error) {
+	for _, entry := range r.entries {
+		for _, marker := range entry.markers {
+			exists, err := base.Join(marker).Exists()
+			if err != nil {
+				return "", nil, err
+			}
+			if exists {
+				return entry.name, entry.scanner, nil
+			}
+		}
+	}
+	return defaultScannerName, r.fallback, nil
+}
+
+func (r *ScannerRegistry) ScanDependencies(base util.AbsolutePath, pythonExecutable string, deadline time.Time) ([]*PackageSpec, bool, error) {
+	name, scanner, err := r.ScannerFor(base)
+	if err != nil {
+		return nil, false, err
+	}
+	r.log.Debug("Scanning Python dependencies", "scanner", name)
+	return scanner.ScanDependencies(base, pythonExecutable, deadline)
+}
@@ -0,0 +1,109 @@
+package pydeps
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/util"
+	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/suite"
+)
+
+type ScannerRegistrySuite struct {
+	utiltest.Suite
+	cwd util.AbsolutePath
+}
+
+func TestScannerRegistrySuite(t *testing.T) {
+	suite.Run(t, new(ScannerRegistrySuite))
+}
+
+func (s *ScannerRegistrySuite) SetupTest() {
+	cwd, err := util.Getwd(afero.NewMemMapFs())
+	s.NoError(err)
+	s.cwd = cwd
+	err = cwd.MkdirAll(0700)
+	s.NoError(err)
+}
+
+func (s *ScannerRegistrySuite) makeRegistry() (*ScannerRegistry, *MockDependencyScanner, *MockDependencyScanner, *MockDependencyScanner) {
+	pipScanner := NewMockDependencyScanner()
+	poetryScanner := NewMockDependencyScanner()
+	condaScanner := NewMockDependencyScanner()
+
+	registry := NewScannerRegistry(pipScanner, logging.New())
+	registry.Register("poetry", []string{"poetry.lock"}, poetryScanner)
+	registry.Register("conda", []string{"environment.yml", "environment.yaml"}, condaScanner)
+	return registry, pipScanner, poetryScanner, condaScanner
+}
+
+func (s *ScannerRegistrySuite) TestNewDefaultScannerRegistry() {
+	log := logging.New()
+	registry := NewDefaultScannerRegistry(log)
+	s.IsType(&defaultDependencyScanner{}, registry.fallback)
+	s.Empty(registry.entries)
+	s.Equal(log, registry.log)
+}
+
+func (s *ScannerRegistrySuite) TestScanDefault() {
+	registry, pipScanner, poetryScanner, condaScanner := s.makeRegistry()
+	specs := []*PackageSpec{{Name: "numpy", Version: "1.26.1"}}
+	pipScanner.On("ScanDependencies", s.cwd, "/usr/bin/python3", time.Time{}).Return(specs, false, nil)
+
+	result, partial, err := registry.ScanDependencies(s.cwd, "/usr/bin/python3", time.Time{})
+	s.NoError(err)
+	s.Equal(specs, result)
+	s.False(partial)
+	pipScanner.AssertExpectations(s.T())
+	poetryScanner.AssertNotCalled(s.T(), "ScanDependencies")
+	condaScanner.AssertNotCalled(s.T(), "ScanDependencies")
+}
+
+func (s *ScannerRegistrySuite) TestScanDispatchesByMarker() {
+	registry, pipScanner, poetryScanner, condaScanner := s.makeRegistry()
+	err := s.cwd.Join("environment.yaml").WriteFile(nil, 0666)
+	s.NoError(err)
+	specs := []*PackageSpec{{Name: "pandas", Version: "2.1.0"}}
+	deadline := time.Now().Add(time.Minute)
+	condaScanner.On("ScanDependencies", s.cwd, "/usr/bin/python3", deadline).Return(specs, true, nil)
+
+	name, scanner, err := registry.ScannerFor(s.cwd)
+	s.NoError(err)
+	s.Equal("conda", name)
+	s.Equal(condaScanner, scanner)
+
+	result, partial, err := registry.ScanDependencies(s.cwd, "/usr/bin/python3", deadline)
+	s.NoError(err)
+	s.Equal(specs, result)
+	s.True(partial)
+	condaScanner.AssertExpectations(s.T())
+	pipScanner.AssertNotCalled(s.T(), "ScanDependencies")
+	poetryScanner.AssertNotCalled(s.T(), "ScanDependencies")
+}
+
+func (s *ScannerRegistrySuite) TestScanFirstRegisteredWins() {
+	registry, _, poetryScanner, _ := s.makeRegistry()
+	err := s.cwd.Join("environment.yml").WriteFile(nil, 0666)
+	s.NoError(err)
+	err = s.cwd.Join("poetry.lock").WriteFile(nil, 0666)
+	s.NoError(err)
+
+	name, scanner, err := registry.ScannerFor(s.cwd)
+	s.NoError(err)
+	s.Equal("poetry", name)
+	s.Equal(poetryScanner, scanner)
+}
+
+func (s *ScannerRegistrySuite) TestScanErr() {
+	registry, pipScanner, _, _ := s.makeRegistry()
+	testError := errors.New("test error from ScanDependencies")
+	pipScanner.On("ScanDependencies", s.cwd, "python", time.Time{}).Return(nil, false, testError)
+
+	_, _, err := registry.ScanDependencies(s.cwd, "python", time.Time{})
+	s.ErrorIs(err, testError)
+}
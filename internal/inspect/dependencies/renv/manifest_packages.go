@@ -191,6 +191,15 @@ func (m *defaultPackageMapper) GetManifestPackages(
 	}
 
 	repos := lockfile.R.Repositories
+	if len(repos) == 0 {
+		repos, err = ReadProjectRepositories(base)
+		if err != nil {
+			return nil, err
+		}
+		if len(repos) != 0 {
+			log.Info("The lockfile doesn't list any repositories; using the ones from .Rprofile and .Renviron", "repos", repoUrlsAsStrings(repos))
+		}
+	}
 	available, err := m.lister.ListAvailablePackages(repos, log)
 	if err != nil {
 		return nil, err
@@ -102,6 +102,36 @@ func (s *ManifestPackagesSuite) TestCRAN() {
 	s.Equal(expected, manifestPackages)
 }
 
+func (s *ManifestPackagesSuite) TestRprofileRepositories() {
+	// The lockfile doesn't list any repositories,
+	// but .Rprofile sets a CRAN mirror.
+	base := s.testdata.Join("rprofile_project")
+	lockfilePath := base.Join("renv.lock")
+	libPath := base.Join("renv_library")
+
+	mapper := NewPackageMapper(base, util.Path{})
+	lister := &mockPackageLister{}
+	mirror := []Repository{
+		{Name: "CRAN", URL: "https://cran.example.org/latest"},
+	}
+	lister.On("GetLibPaths", mock.Anything).Return([]util.AbsolutePath{libPath}, nil)
+	lister.On("GetBioconductorRepos", mock.Anything, mock.Anything).Return(nil, nil)
+	lister.On("ListAvailablePackages", mirror, mock.Anything).Return([]AvailablePackage{
+		{
+			Name:       "mypkg",
+			Version:    "1.2.3",
+			Repository: "https://cran.example.org/latest/src/contrib",
+		},
+	}, nil)
+	mapper.lister = lister
+
+	manifestPackages, err := mapper.GetManifestPackages(base, lockfilePath, logging.New())
+	s.NoError(err)
+	lister.AssertExpectations(s.T())
+	s.Equal("CRAN", manifestPackages["mypkg"].Source)
+	s.Equal("https://cran.example.org/latest/src/contrib", manifestPackages["mypkg"].Repository)
+}
+
 func (s *ManifestPackagesSuite) TestBioconductor() {
 	base := s.testdata.Join("bioc_project")
 	lockfilePath := base.Join("renv.lock")
@@ -0,0 +1,157 @@
+package renv
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"errors"
+	"io/fs"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/posit-dev/publisher/internal/util"
+)
+
+const RprofileFilename = ".Rprofile"
+const RenvironFilename = ".Renviron"
+
+// reposOverrideEnvVar replaces the configured repositories
+// when renv installs packages.
+const reposOverrideEnvVar = "RENV_CONFIG_REPOS_OVERRIDE"
+
+// Patterns for the common ways of setting repositories in .Rprofile:
+//
+//	options(repos = c(CRAN = "https://cran.example.com"))
+//	options(repos = "https://cran.example.com")
+//	r["CRAN"] <- "https://cran.example.com"
+var reposVectorRE = regexp.MustCompile(`repos\s*=\s*c\s*\(([^)]*)\)`)
+var reposStringRE = regexp.MustCompile(`repos\s*=\s*["']([^"']+)["']`)
+var reposAssignRE = regexp.MustCompile(`\w+\s*\[\[?\s*["']([^"']+)["']\s*\]\]?\s*(?:<-|=)\s*["']([^"']+)["']`)
+var namedURLRE = regexp.MustCompile(`(?:["']([^"']+)["']|([\w.]+))\s*=\s*["']([^"']+)["']`)
+
+// ReadProjectRepositories returns the package repositories configured
+// in the project's .Rprofile and .Renviron files. It is used when the
+// lockfile doesn't list any repositories.
+func ReadProjectRepositories(base util.AbsolutePath) ([]Repository, error) {
+	repos := []Repository{}
+	content, err := readOptionalFile(base.Join(RprofileFilename))
+	if err != nil {
+		return nil, err
+	}
+	if content != "" {
+		repos = reposFromRprofile(content)
+	}
+	content, err = readOptionalFile(base.Join(RenvironFilename))
+	if err != nil {
+		return nil, err
+	}
+	if url := renvironValue(content, reposOverrideEnvVar); isRepoURL(url) {
+		repos = []Repository{{Name: "CRAN", URL: RepoURL(url)}}
+	}
+	return repos, nil
+}
+
+func readOptionalFile(path util.AbsolutePath) (string, error) {
+	content, err := path.ReadFile()
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", nil
+		}
+		return "", err
+	}
+	return string(content), nil
+}
+
+// reposFromRprofile finds repository settings in R code.
+// Later settings for the same repository name replace earlier ones.
+func reposFromRprofile(content string) []Repository {
+	repos := []Repository{}
+	add := func(name, url string) {
+		if !isRepoURL(url) {
+			// For example, the "@CRAN@" placeholder.
+			return
+		}
+		for i := range repos {
+			if repos[i].Name == name {
+				repos[i].URL = RepoURL(url)
+				return
+			}
+		}
+		repos = append(repos, Repository{Name: name, URL: RepoURL(url)})
+	}
+	// Settings can span lines, like
+	//
+	//	options(repos = c(
+	//	  CRAN = "https://cran.example.com"
+	//	))
+	//
+	// so the patterns are matched in the whole file, in order.
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		lines[i] = stripRComment(line)
+	}
+	code := strings.Join(lines, "\n")
+
+	type setting struct {
+		start int
+		name  string
+		url   string
+	}
+	settings := []setting{}
+	for _, m := range reposVectorRE.FindAllStringSubmatchIndex(code, -1) {
+		vector := code[m[2]:m[3]]
+		for _, pair := range namedURLRE.FindAllStringSubmatch(vector, -1) {
+			settings = append(settings, setting{m[0], pair[1] + pair[2], pair[3]})
+		}
+	}
+	for _, m := range reposStringRE.FindAllStringSubmatchIndex(code, -1) {
+		settings = append(settings, setting{m[0], "CRAN", code[m[2]:m[3]]})
+	}
+	for _, m := range reposAssignRE.FindAllStringSubmatchIndex(code, -1) {
+		settings = append(settings, setting{m[0], code[m[2]:m[3]], code[m[4]:m[5]]})
+	}
+	slices.SortStableFunc(settings, func(a, b setting) int {
+		return a.start - b.start
+	})
+	for _, s := range settings {
+		add(s.name, s.url)
+	}
+	return repos
+}
+
+// stripRComment removes a trailing comment from a line of R code.
+func stripRComment(line string) string {
+	var quote rune
+	for i, c := range line {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// renvironValue returns the value of a variable set in .Renviron content.
+func renvironValue(content string, name string) string {
+	value := ""
+	for _, line := range strings.Split(content, "\n") {
+		key, v, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok || strings.TrimSpace(key) != name {
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(v), `"'`)
+	}
+	return value
+}
+
+func isRepoURL(s string) bool {
+	return strings.HasPrefix(s, "https://") ||
+		strings.HasPrefix(s, "http://") ||
+		strings.HasPrefix(s, "file://")
+}
@@ -0,0 +1,101 @@
+package renv
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"testing"
+
+	"github.com/posit-dev/publisher/internal/util"
+	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/suite"
+)
+
+type RepositoriesSuite struct {
+	utiltest.Suite
+	base util.AbsolutePath
+}
+
+func TestRepositoriesSuite(t *testing.T) {
+	suite.Run(t, new(RepositoriesSuite))
+}
+
+func (s *RepositoriesSuite) SetupTest() {
+	cwd, err := util.Getwd(afero.NewMemMapFs())
+	s.NoError(err)
+	s.base = cwd
+	s.NoError(s.base.MkdirAll(0700))
+}
+
+func (s *RepositoriesSuite) writeFile(name string, content string) {
+	s.NoError(s.base.Join(name).WriteFile([]byte(content), 0600))
+}
+
+func (s *RepositoriesSuite) TestNoFiles() {
+	repos, err := ReadProjectRepositories(s.base)
+	s.NoError(err)
+	s.Empty(repos)
+}
+
+func (s *RepositoriesSuite) TestRprofileVector() {
+	s.writeFile(RprofileFilename, `
+options(repos = c(CRAN = "https://cran.example.org", "Internal" = 'https://r.example.com/internal'))
+`)
+	repos, err := ReadProjectRepositories(s.base)
+	s.NoError(err)
+	s.Equal([]Repository{
+		{Name: "CRAN", URL: "https://cran.example.org"},
+		{Name: "Internal", URL: "https://r.example.com/internal"},
+	}, repos)
+}
+
+func (s *RepositoriesSuite) TestRprofileVectorMultiline() {
+	s.writeFile(RprofileFilename, `
+options(repos = c(
+  CRAN = "https://cran.example.org", # our mirror
+  Internal = "https://r.example.com/internal"
+))
+`)
+	repos, err := ReadProjectRepositories(s.base)
+	s.NoError(err)
+	s.Equal([]Repository{
+		{Name: "CRAN", URL: "https://cran.example.org"},
+		{Name: "Internal", URL: "https://r.example.com/internal"},
+	}, repos)
+}
+
+func (s *RepositoriesSuite) TestRprofileString() {
+	s.writeFile(RprofileFilename, `options(repos = "https://cran.example.org")`)
+	repos, err := ReadProjectRepositories(s.base)
+	s.NoError(err)
+	s.Equal([]Repository{
+		{Name: "CRAN", URL: "https://cran.example.org"},
+	}, repos)
+}
+
+func (s *RepositoriesSuite) TestRprofileAssignment() {
+	s.writeFile(RprofileFilename, `
+local({
+  r <- getOption("repos")
+  r["CRAN"] <- "@CRAN@"
+  r["CRAN"] <- "https://cran.example.org" # our mirror
+  # r["Old"] <- "https://old.example.org"
+  options(repos = r)
+})
+`)
+	repos, err := ReadProjectRepositories(s.base)
+	s.NoError(err)
+	s.Equal([]Repository{
+		{Name: "CRAN", URL: "https://cran.example.org"},
+	}, repos)
+}
+
+func (s *RepositoriesSuite) TestRenvironOverride() {
+	s.writeFile(RprofileFilename, `options(repos = c(CRAN = "https://cran.example.org"))`)
+	s.writeFile(RenvironFilename, "R_LIBS_USER=~/R\nRENV_CONFIG_REPOS_OVERRIDE=\"https://packages.example.com/cran/latest\"\n")
+	repos, err := ReadProjectRepositories(s.base)
+	s.NoError(err)
+	s.Equal([]Repository{
+		{Name: "CRAN", URL: "https://packages.example.com/cran/latest"},
+	}, repos)
+}
@@ -13,7 +13,10 @@ import (
 )
 
 type ContentTypeDetector struct {
-	detectors []ContentTypeInferer
+	detectors   []ContentTypeInferer
+	cache       *DetectionCache // Results of earlier detections, or nil
+	searchDepth int
+	scanImports bool
 }
 
 func NewContentTypeDetector(log logging.Logger) *ContentTypeDetector {
@@ -34,12 +37,71 @@ func NewContentTypeDetector(log logging.Logger) *ContentTypeDetector {
 			NewFlaskDetector(),
 			NewDashDetector(),
 			NewStreamlitDetector(),
+			NewPanelDetector(),
 			NewBokehDetector(),
 			NewStaticHTMLDetector(),
 		},
 	}
 }
 
+// CachingDetector is implemented by detectors that can
+// reuse the results of earlier detections.
+type CachingDetector interface {
+	SetCache(cache *DetectionCache)
+}
+
+// SetCache enables reusing the results of earlier detections
+// from the cache, until a file in the project changes.
+// Detectors are created for each request, so they share a cache.
+func (t *ContentTypeDetector) SetCache(cache *DetectionCache) {
+	t.cache = cache
+}
+
+// NestedDetector is implemented by detectors that can
+// search subdirectories of the base directory for entrypoints.
+type NestedDetector interface {
+	SetSearchDepth(depth int)
+}
+
+// SetSearchDepth enables detection of apps in subdirectories
+// of the base directory, up to MaxSearchDepth levels deep, for the
+// detectors that support it. Nested entrypoints are reported
+// relative to the base directory, e.g. `src/app.py`.
+func (t *ContentTypeDetector) SetSearchDepth(depth int) {
+	t.searchDepth = depth
+	for _, detector := range t.detectors {
+		if d, ok := detector.(NestedDetector); ok {
+			d.SetSearchDepth(depth)
+		}
+	}
+}
+
+// ImportScanningDetector is implemented by detectors that can
+// look for framework imports in the modules an entrypoint imports.
+type ImportScanningDetector interface {
+	SetScanImports(scan bool)
+}
+
+// SetScanImports enables detection of Python apps whose framework
+// is imported by a project module rather than the entrypoint itself.
+// At most MaxImportScanFiles modules are checked for each entrypoint.
+func (t *ContentTypeDetector) SetScanImports(scan bool) {
+	t.scanImports = scan
+	for _, detector := range t.detectors {
+		if d, ok := detector.(ImportScanningDetector); ok {
+			d.SetScanImports(scan)
+		}
+	}
+}
+
+// toolDetector is implemented by detectors that need
+// a tool, like quarto, to inspect some projects.
+type toolDetector interface {
+	// MissingToolError returns an error if the last
+	// project inspected needs a tool that isn't installed.
+	MissingToolError() error
+}
+
 func newUnknownConfig() *config.Config {
 	cfg := config.New()
 	cfg.Type = config.ContentTypeUnknown
@@ -58,13 +120,48 @@ func filenameStem(filename string) string {
 	return strings.TrimSuffix(filename, ext)
 }
 
+// InferType returns the configurations detected in the base directory.
 func (t *ContentTypeDetector) InferType(base util.AbsolutePath, entrypoint util.RelativePath) ([]*config.Config, error) {
-	allConfigs := []*config.Config{}
-
 	_, err := base.Stat()
 	if err != nil {
 		return nil, err
 	}
+	if t.cache == nil {
+		return t.inferType(base, entrypoint)
+	}
+	key := detectionKey{
+		fs:          base.Fs(),
+		base:        base.String(),
+		entrypoint:  entrypoint.String(),
+		searchDepth: t.searchDepth,
+		scanImports: t.scanImports,
+		tools:       toolsFingerprint(),
+	}
+	fingerprint, err := projectFingerprint(base)
+	if err != nil {
+		return nil, err
+	}
+	configs, ok, err := t.cache.get(key, fingerprint)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return configs, nil
+	}
+	configs, err = t.inferType(base, entrypoint)
+	if err != nil {
+		return nil, err
+	}
+	err = t.cache.put(key, fingerprint, configs)
+	if err != nil {
+		return nil, err
+	}
+	return configs, nil
+}
+
+func (t *ContentTypeDetector) inferType(base util.AbsolutePath, entrypoint util.RelativePath) ([]*config.Config, error) {
+	allConfigs := []*config.Config{}
+	var missingToolErr error
 
 	for _, detector := range t.detectors {
 		configs, err := detector.InferType(base, entrypoint)
@@ -74,12 +171,38 @@ func (t *ContentTypeDetector) InferType(base util.AbsolutePath, entrypoint util.
 		if configs != nil {
 			allConfigs = append(allConfigs, configs...)
 		}
+		if d, ok := detector.(toolDetector); ok && missingToolErr == nil {
+			missingToolErr = d.MissingToolError()
+		}
 	}
 	if len(allConfigs) == 0 {
+		if missingToolErr != nil {
+			// Nothing else was detected, so the project
+			// can't be deployed until the tool is installed.
+			return nil, missingToolErr
+		}
 		allConfigs = append(allConfigs, newUnknownConfig())
 	}
 
+	// R projects often contain HTML rendered from their sources
+	// (e.g. a knitted report), so when there is R content,
+	// it takes precedence over static HTML.
+	hasRContent := slices.ContainsFunc(allConfigs, func(cfg *config.Config) bool {
+		return cfg.Type.IsRContent()
+	})
+	isDemoted := func(cfg *config.Config) bool {
+		return hasRContent && cfg.Type == config.ContentTypeHTML
+	}
+
 	compareConfigs := func(a, b *config.Config) int {
+		aIsDemoted := isDemoted(a)
+		bIsDemoted := isDemoted(b)
+		if aIsDemoted != bIsDemoted {
+			if bIsDemoted {
+				return -1
+			}
+			return 1
+		}
 		entrypointA := a.Entrypoint
 		entrypointB := b.Entrypoint
 		stemA := filenameStem(entrypointA)
@@ -106,3 +229,6 @@ func (t *ContentTypeDetector) InferType(base util.AbsolutePath, entrypoint util.
 }
 
 var _ ContentTypeInferer = &ContentTypeDetector{}
+var _ NestedDetector = &ContentTypeDetector{}
+var _ ImportScanningDetector = &ContentTypeDetector{}
+var _ CachingDetector = &ContentTypeDetector{}
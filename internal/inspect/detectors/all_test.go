@@ -58,6 +58,72 @@ func (s *AllSuite) TestInferTypeDirectory() {
 	}, configs[1])
 }
 
+func (s *AllSuite) TestInferTypeDirectoryRShinyAndHTML() {
+	base := util.NewAbsolutePath("/project", afero.NewMemMapFs())
+	err := base.MkdirAll(0777)
+	s.NoError(err)
+
+	err = base.Join("index.html").WriteFile([]byte("<html></html>\n"), 0600)
+	s.NoError(err)
+	err = base.Join("app.R").WriteFile([]byte("library(shiny)\n"), 0600)
+	s.NoError(err)
+
+	detector := NewContentTypeDetector(logging.New())
+	configs, err := detector.InferType(base, util.RelativePath{})
+	s.NoError(err)
+	s.Len(configs, 2)
+
+	// R source takes precedence over rendered HTML,
+	// even though index is a preferred name.
+	s.Equal(config.ContentTypeRShiny, configs[0].Type)
+	s.Equal("app.R", configs[0].Entrypoint)
+	s.Equal(config.ContentTypeHTML, configs[1].Type)
+}
+
+func (s *AllSuite) TestInferTypeDirectoryPlumberAndHTML() {
+	base := util.NewAbsolutePath("/project", afero.NewMemMapFs())
+	err := base.MkdirAll(0777)
+	s.NoError(err)
+
+	err = base.Join("api.html").WriteFile([]byte("<html></html>\n"), 0600)
+	s.NoError(err)
+	err = base.Join("plumber.R").WriteFile([]byte("library(plumber)\n"), 0600)
+	s.NoError(err)
+
+	detector := NewContentTypeDetector(logging.New())
+	configs, err := detector.InferType(base, util.RelativePath{})
+	s.NoError(err)
+	s.Len(configs, 2)
+
+	s.Equal(config.ContentTypeRPlumber, configs[0].Type)
+	s.Equal("plumber.R", configs[0].Entrypoint)
+	s.Equal(config.ContentTypeHTML, configs[1].Type)
+}
+
+func (s *AllSuite) TestInferTypeDirectoryRmdAndRenderedHTML() {
+	base := util.NewAbsolutePath("/project", afero.NewMemMapFs())
+	err := base.MkdirAll(0777)
+	s.NoError(err)
+
+	err = base.Join("index.html").WriteFile([]byte("<html></html>\n"), 0600)
+	s.NoError(err)
+	err = base.Join("report.html").WriteFile([]byte("<html></html>\n"), 0600)
+	s.NoError(err)
+	err = base.Join("report.Rmd").WriteFile([]byte(basicRmdContent), 0600)
+	s.NoError(err)
+
+	detector := NewContentTypeDetector(logging.New())
+	configs, err := detector.InferType(base, util.RelativePath{})
+	s.NoError(err)
+	s.Len(configs, 3)
+
+	s.Equal(config.ContentTypeRMarkdown, configs[0].Type)
+	s.Equal("report.Rmd", configs[0].Entrypoint)
+	// The HTML files keep their usual order among themselves.
+	s.Equal("index.html", configs[1].Entrypoint)
+	s.Equal("report.html", configs[2].Entrypoint)
+}
+
 func (s *AllSuite) TestInferTypeDirectoryIndeterminate() {
 	base := util.NewAbsolutePath("/project", afero.NewMemMapFs())
 	err := base.MkdirAll(0777)
@@ -134,3 +200,30 @@ func (s *AllSuite) TestInferAllIndeterminate() {
 	s.Len(configs, 1)
 	s.Equal(config.ContentTypeUnknown, configs[0].Type)
 }
+
+func (s *AllSuite) TestInferNested() {
+	base := util.NewAbsolutePath("/project", afero.NewMemMapFs())
+	pagesDir := base.Join("src", "pages")
+	err := pagesDir.MkdirAll(0777)
+	s.NoError(err)
+
+	err = base.Join("src", "app.py").WriteFile([]byte("import streamlit as st\n"), 0600)
+	s.NoError(err)
+	err = pagesDir.Join("1_Page.py").WriteFile([]byte("import streamlit as st\n"), 0600)
+	s.NoError(err)
+
+	detector := NewContentTypeDetector(logging.New())
+	detector.SetSearchDepth(MaxSearchDepth)
+	t, err := detector.InferType(base, util.RelativePath{})
+	s.NoError(err)
+	s.Equal([]*config.Config{
+		{
+			Schema:     schema.ConfigSchemaURL,
+			Type:       config.ContentTypePythonStreamlit,
+			Entrypoint: "src/app.py",
+			Validate:   true,
+			Files:      []string{"/src/app.py", "/src/pages/"},
+			Python:     &config.Python{},
+		},
+	}, t)
+}
@@ -0,0 +1,174 @@
+package detectors
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/posit-dev/publisher/internal/bundles/matcher"
+	"github.com/posit-dev/publisher/internal/config"
+	"github.com/posit-dev/publisher/internal/util"
+	"github.com/spf13/afero"
+)
+
+// maxCachedDetections limits the number of projects
+// whose detection results are kept.
+const maxCachedDetections = 50
+
+// DetectionCache holds the results of content type detection,
+// so that detection isn't repeated when the UI asks again
+// about a project that hasn't changed.
+type DetectionCache struct {
+	mu      sync.Mutex
+	entries map[detectionKey]detectionEntry
+}
+
+// detectionKey identifies a detection request. The detector
+// options, and the tools detectors run, are included since
+// they change the results.
+type detectionKey struct {
+	fs          afero.Fs
+	base        string
+	entrypoint  string
+	searchDepth int
+	scanImports bool
+	tools       string
+}
+
+type detectionEntry struct {
+	fingerprint []byte
+	configs     []*config.Config
+}
+
+func NewDetectionCache() *DetectionCache {
+	return &DetectionCache{
+		entries: make(map[detectionKey]detectionEntry),
+	}
+}
+
+// get returns the cached configurations for the key,
+// if the project's fingerprint hasn't changed.
+func (c *DetectionCache) get(key detectionKey, fingerprint []byte) ([]*config.Config, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok || !slices.Equal(entry.fingerprint, fingerprint) {
+		return nil, false, nil
+	}
+	configs, err := cloneConfigs(entry.configs)
+	if err != nil {
+		return nil, false, err
+	}
+	return configs, true, nil
+}
+
+func (c *DetectionCache) put(key detectionKey, fingerprint []byte, configs []*config.Config) error {
+	clones, err := cloneConfigs(configs)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= maxCachedDetections {
+		// Make room by dropping an arbitrary entry.
+		for k := range c.entries {
+			delete(c.entries, k)
+			break
+		}
+	}
+	c.entries[key] = detectionEntry{
+		fingerprint: fingerprint,
+		configs:     clones,
+	}
+	return nil
+}
+
+// detectionTools are the programs that detectors run. Quarto
+// runs Python to inspect Jupyter documents, so it is included.
+var detectionTools = []string{"quarto", "python3", "python"}
+
+// toolsFingerprint summarizes the paths, sizes, and modification
+// times of the tools on the PATH that detectors run, so that the
+// results are detected again when one is installed or upgraded.
+func toolsFingerprint() string {
+	var fingerprint strings.Builder
+	for _, name := range detectionTools {
+		path, err := exec.LookPath(name)
+		if err != nil {
+			fmt.Fprintf(&fingerprint, "%s\x00\n", name)
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			fmt.Fprintf(&fingerprint, "%s\x00%s\n", name, path)
+			continue
+		}
+		fmt.Fprintf(&fingerprint, "%s\x00%s\x00%d\x00%d\n", name, path, info.Size(), info.ModTime().UnixNano())
+	}
+	return fingerprint.String()
+}
+
+// projectFingerprint summarizes the names, sizes, and modification
+// times of the files in the project. It changes when any file that
+// detection might read is added, removed, or modified. Directories
+// that are never deployed, such as .git and Python environments,
+// are skipped.
+func projectFingerprint(base util.AbsolutePath) ([]byte, error) {
+	exclusions, err := matcher.NewMatchList(base, slices.Concat(matcher.StandardExclusions, matcher.BundleExclusions))
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.New()
+	err = base.Walk(func(path util.AbsolutePath, info fs.FileInfo, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) || errors.Is(err, os.ErrPermission) {
+				return nil
+			}
+			return err
+		}
+		if path.String() != base.String() {
+			m := exclusions.Match(path)
+			if m != nil && m.Exclude {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if info.IsDir() && (util.IsPythonEnvironmentDir(path) || util.IsRenvLibraryDir(path)) {
+				return filepath.SkipDir
+			}
+		}
+		fmt.Fprintf(hash, "%s\x00%d\x00%d\x00%v\n", path, info.Size(), info.ModTime().UnixNano(), info.Mode())
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return hash.Sum(nil), nil
+}
+
+// cloneConfigs returns copies of the configurations that
+// can be modified without changing the cached ones.
+func cloneConfigs(configs []*config.Config) ([]*config.Config, error) {
+	if configs == nil {
+		return nil, nil
+	}
+	clones := make([]*config.Config, len(configs))
+	for i, cfg := range configs {
+		clone, err := cfg.Clone()
+		if err != nil {
+			return nil, err
+		}
+		clones[i] = clone
+	}
+	return clones, nil
+}
@@ -0,0 +1,173 @@
+package detectors
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/posit-dev/publisher/internal/config"
+	"github.com/posit-dev/publisher/internal/util"
+	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type CacheSuite struct {
+	utiltest.Suite
+	base     util.AbsolutePath
+	inferer  *mockInferer
+	detector *ContentTypeDetector
+}
+
+func TestCacheSuite(t *testing.T) {
+	suite.Run(t, new(CacheSuite))
+}
+
+type mockInferer struct {
+	mock.Mock
+}
+
+func (m *mockInferer) InferType(base util.AbsolutePath, entrypoint util.RelativePath) ([]*config.Config, error) {
+	args := m.Called(base, entrypoint)
+	return args.Get(0).([]*config.Config), args.Error(1)
+}
+
+func (s *CacheSuite) SetupTest() {
+	s.base = util.NewAbsolutePath("/project", afero.NewMemMapFs())
+	err := s.base.MkdirAll(0777)
+	s.NoError(err)
+	err = s.base.Join("app.py").WriteFile([]byte("import flask\n"), 0600)
+	s.NoError(err)
+
+	cfg := config.New()
+	cfg.Type = config.ContentTypePythonFlask
+	cfg.Entrypoint = "app.py"
+	cfg.Python = &config.Python{}
+	s.inferer = &mockInferer{}
+	s.inferer.On("InferType", mock.Anything, mock.Anything).Return([]*config.Config{cfg}, nil)
+	s.detector = &ContentTypeDetector{
+		detectors: []ContentTypeInferer{s.inferer},
+		cache:     NewDetectionCache(),
+	}
+}
+
+func (s *CacheSuite) infer() []*config.Config {
+	configs, err := s.detector.InferType(s.base, util.RelativePath{})
+	s.NoError(err)
+	s.Len(configs, 1)
+	return configs
+}
+
+func (s *CacheSuite) TestCacheHit() {
+	first := s.infer()
+	second := s.infer()
+	s.inferer.AssertNumberOfCalls(s.T(), "InferType", 1)
+	s.Equal(first, second)
+
+	// Callers get their own copy.
+	second[0].Title = "Changed"
+	second[0].Python.Version = "3.12"
+	third := s.infer()
+	s.Equal("", third[0].Title)
+	s.Equal("", third[0].Python.Version)
+}
+
+func (s *CacheSuite) TestCacheHitCopiesAllSettings() {
+	cfg := config.New()
+	cfg.Type = config.ContentTypePythonFlask
+	cfg.Entrypoint = "app.py"
+	cfg.PrePublish = &config.PrePublish{Commands: []string{"make"}}
+	cfg.Connect = &config.Connect{VanityURL: "/app/"}
+	s.inferer = &mockInferer{}
+	s.inferer.On("InferType", mock.Anything, mock.Anything).Return([]*config.Config{cfg}, nil)
+	s.detector.detectors = []ContentTypeInferer{s.inferer}
+
+	s.infer()
+	second := s.infer()
+	s.Equal([]string{"make"}, second[0].PrePublish.Commands)
+	s.Equal("/app/", second[0].Connect.VanityURL)
+
+	second[0].PrePublish.Commands[0] = "changed"
+	second[0].Connect.VanityURL = "/changed/"
+	third := s.infer()
+	s.Equal([]string{"make"}, third[0].PrePublish.Commands)
+	s.Equal("/app/", third[0].Connect.VanityURL)
+}
+
+func (s *CacheSuite) TestToolChanged() {
+	if runtime.GOOS == "windows" {
+		s.T().Skip("uses a shell script as quarto")
+	}
+	binDir := s.T().TempDir()
+	s.T().Setenv("PATH", binDir)
+	s.infer()
+
+	// Installing or upgrading quarto changes the results.
+	quartoPath := filepath.Join(binDir, "quarto")
+	err := os.WriteFile(quartoPath, []byte("#!/bin/sh\n"), 0755)
+	s.NoError(err)
+	s.infer()
+	s.inferer.AssertNumberOfCalls(s.T(), "InferType", 2)
+
+	err = os.WriteFile(quartoPath, []byte("#!/bin/sh\necho 1.5\n"), 0755)
+	s.NoError(err)
+	s.infer()
+	s.inferer.AssertNumberOfCalls(s.T(), "InferType", 3)
+
+	s.infer()
+	s.inferer.AssertNumberOfCalls(s.T(), "InferType", 3)
+}
+
+func (s *CacheSuite) TestFileChanged() {
+	s.infer()
+	path := s.base.Join("app.py")
+	err := path.WriteFile([]byte("import dash\n"), 0600)
+	s.NoError(err)
+	err = path.Chtimes(time.Now(), time.Now().Add(time.Second))
+	s.NoError(err)
+	s.infer()
+	s.inferer.AssertNumberOfCalls(s.T(), "InferType", 2)
+}
+
+func (s *CacheSuite) TestFileAdded() {
+	s.infer()
+	err := s.base.Join("other.py").WriteFile([]byte("import dash\n"), 0600)
+	s.NoError(err)
+	s.infer()
+	s.inferer.AssertNumberOfCalls(s.T(), "InferType", 2)
+}
+
+func (s *CacheSuite) TestExcludedFileChanged() {
+	s.infer()
+	// Files that are never deployed don't affect detection.
+	err := s.base.Join(".git").MkdirAll(0777)
+	s.NoError(err)
+	err = s.base.Join(".git", "index").WriteFile([]byte("changed"), 0600)
+	s.NoError(err)
+	s.infer()
+	s.inferer.AssertNumberOfCalls(s.T(), "InferType", 1)
+}
+
+func (s *CacheSuite) TestOptionsChanged() {
+	s.infer()
+	s.detector.SetSearchDepth(1)
+	s.infer()
+	s.inferer.AssertNumberOfCalls(s.T(), "InferType", 2)
+}
+
+func (s *CacheSuite) TestErrorNotCached() {
+	s.inferer = &mockInferer{}
+	s.inferer.On("InferType", mock.Anything, mock.Anything).Return([]*config.Config(nil), afero.ErrFileNotFound)
+	s.detector.detectors = []ContentTypeInferer{s.inferer}
+
+	_, err := s.detector.InferType(s.base, util.RelativePath{})
+	s.ErrorIs(err, afero.ErrFileNotFound)
+	_, err = s.detector.InferType(s.base, util.RelativePath{})
+	s.ErrorIs(err, afero.ErrFileNotFound)
+	s.inferer.AssertNumberOfCalls(s.T(), "InferType", 2)
+}
@@ -3,10 +3,14 @@ package detectors
 // Copyright (C) 2023 by Posit Software, PBC.
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"regexp"
+	"strings"
 
+	"github.com/posit-dev/publisher/internal/bundles/matcher"
 	"github.com/posit-dev/publisher/internal/util"
 )
 
@@ -39,3 +43,230 @@ func (h defaultInferenceHelper) HasPythonImports(r io.Reader, packages []string)
 	}
 	return false, nil
 }
+
+// MaxImportScanFiles limits the number of local modules that
+// ModulesHavePythonImports reads, so that large projects
+// don't slow down detection.
+const MaxImportScanFiles = 20
+
+// pythonImportRE matches the module names in import statements:
+//
+//	import helpers, lib.util
+//	from lib import util
+//	from .views import index
+var pythonImportRE = regexp.MustCompile(`(?m)^\s*(?:from\s+(\.*[\w.]*)\s+import\s+([\w., ]+)|import\s+([\w., ]+))`)
+
+// ModulesHavePythonImports returns true if the file at path, or one
+// of the modules in the project that it imports, directly or
+// indirectly, imports one of the packages. Apps often import their
+// framework in a submodule rather than the entrypoint. At most
+// MaxImportScanFiles files are read.
+func (h defaultInferenceHelper) ModulesHavePythonImports(base util.AbsolutePath, path util.AbsolutePath, packages []string) (bool, error) {
+	queue := []util.AbsolutePath{path}
+	seen := map[string]bool{path.String(): true}
+	for len(queue) != 0 && len(seen) <= MaxImportScanFiles {
+		current := queue[0]
+		queue = queue[1:]
+		content, err := current.ReadFile()
+		if err != nil {
+			return false, err
+		}
+		matches, err := h.HasPythonImports(bytes.NewReader(content), packages)
+		if err != nil {
+			return false, err
+		}
+		if matches {
+			return true, nil
+		}
+		for _, module := range localPythonImports(base, current, content) {
+			if !seen[module.String()] {
+				seen[module.String()] = true
+				queue = append(queue, module)
+			}
+		}
+	}
+	return false, nil
+}
+
+// localPythonImports returns the files in the project that
+// provide the modules imported by the Python source in content.
+// Imports of installed packages are ignored.
+func localPythonImports(base util.AbsolutePath, path util.AbsolutePath, content []byte) []util.AbsolutePath {
+	var modules []util.AbsolutePath
+	dir := path.Dir()
+	for _, m := range pythonImportRE.FindAllSubmatch(content, -1) {
+		var names []string
+		if m[1] != nil {
+			// from module import names; the names may be submodules.
+			module := string(m[1])
+			for _, name := range splitImportNames(string(m[2])) {
+				names = append(names, strings.TrimSuffix(module, ".")+"."+name)
+			}
+			names = append(names, module)
+		} else {
+			names = splitImportNames(string(m[3]))
+		}
+		for _, name := range names {
+			if module, ok := findLocalModule(base, dir, name); ok {
+				modules = append(modules, module)
+			}
+		}
+	}
+	return modules
+}
+
+// splitImportNames splits a list of imported names, such as
+// "a, b as c", into its names, e.g. ["a", "b"].
+func splitImportNames(list string) []string {
+	var names []string
+	for _, item := range strings.Split(list, ",") {
+		fields := strings.Fields(item)
+		if len(fields) != 0 {
+			names = append(names, fields[0])
+		}
+	}
+	return names
+}
+
+// findLocalModule returns the file in the project that provides
+// the named module, relative to the directory of the importing file.
+func findLocalModule(base util.AbsolutePath, dir util.AbsolutePath, name string) (util.AbsolutePath, bool) {
+	relative := strings.TrimLeft(name, ".")
+	// Each leading dot after the first refers to a parent directory.
+	for i := 1; i < len(name)-len(relative); i++ {
+		dir = dir.Dir()
+	}
+	if relative == "" {
+		return util.AbsolutePath{}, false
+	}
+	if rel, err := dir.Rel(base); err != nil || !rel.IsLocal() {
+		// Outside the project.
+		return util.AbsolutePath{}, false
+	}
+	parts := strings.Split(relative, ".")
+	modulePath := dir.Join(parts...)
+	last := parts[len(parts)-1]
+	for _, candidate := range []util.AbsolutePath{
+		modulePath.Dir().Join(last + ".py"),
+		modulePath.Join("__init__.py"),
+	} {
+		info, err := candidate.Stat()
+		if err == nil && info.Mode().IsRegular() {
+			return candidate, true
+		}
+	}
+	return util.AbsolutePath{}, false
+}
+
+var ErrNoEntrypointMatch = errors.New("no files match the entrypoint pattern")
+var ErrMultipleEntrypointMatches = errors.New("more than one file matches the entrypoint pattern")
+
+// IsEntrypointGlob returns true if the entrypoint contains
+// glob wildcards (*, ?, or [...]).
+func IsEntrypointGlob(entrypoint string) bool {
+	return strings.ContainsAny(entrypoint, "*?[")
+}
+
+// ResolveEntrypoint resolves an entrypoint containing glob wildcards,
+// such as `app_*.py`, to the single file in the base directory that it
+// matches. It is an error if the pattern matches no files, or more
+// than one. Entrypoints without wildcards are returned unchanged,
+// unless they match a file only when case is ignored; then the
+// file's name is returned, so that the entrypoint will be found on
+// a case-sensitive server.
+func ResolveEntrypoint(base util.AbsolutePath, entrypoint util.RelativePath) (util.RelativePath, error) {
+	pattern := entrypoint.String()
+	if !IsEntrypointGlob(pattern) {
+		actual, mismatch, err := util.FindCaseMismatch(base, entrypoint)
+		if err != nil {
+			return util.RelativePath{}, err
+		}
+		if mismatch {
+			return actual, nil
+		}
+		return entrypoint, nil
+	}
+	paths, err := base.Glob(pattern)
+	if err != nil {
+		return util.RelativePath{}, fmt.Errorf("invalid entrypoint pattern '%s': %w", pattern, err)
+	}
+	matches := []util.RelativePath{}
+	for _, path := range paths {
+		isDir, err := path.IsDir()
+		if err != nil {
+			return util.RelativePath{}, err
+		}
+		if isDir {
+			continue
+		}
+		relPath, err := path.Rel(base)
+		if err != nil {
+			return util.RelativePath{}, err
+		}
+		matches = append(matches, relPath)
+	}
+	switch len(matches) {
+	case 0:
+		return util.RelativePath{}, fmt.Errorf("%w: '%s'", ErrNoEntrypointMatch, pattern)
+	case 1:
+		return matches[0], nil
+	default:
+		names := make([]string, len(matches))
+		for i, match := range matches {
+			names[i] = match.String()
+		}
+		return util.RelativePath{}, fmt.Errorf("%w: '%s' matches %s", ErrMultipleEntrypointMatches, pattern, strings.Join(names, ", "))
+	}
+}
+
+// MaxSearchDepth is the deepest level of subdirectories
+// that detectors will search for nested entrypoints.
+const MaxSearchDepth = 2
+
+// findEntrypoints returns the files matching the glob pattern
+// in the base directory and, if depth is greater than zero, in its
+// subdirectories up to `depth` levels deep. Directories excluded from
+// deployment, such as .git, and Python and renv environments are
+// not searched.
+func findEntrypoints(base util.AbsolutePath, pattern string, depth int) ([]util.AbsolutePath, error) {
+	depth = min(max(depth, 0), MaxSearchDepth)
+	exclusions, err := matcher.NewMatchList(base, matcher.StandardExclusions)
+	if err != nil {
+		return nil, err
+	}
+	var paths []util.AbsolutePath
+	dirs := []util.AbsolutePath{base}
+	for level := 0; level <= depth && len(dirs) != 0; level++ {
+		var subdirs []util.AbsolutePath
+		for _, dir := range dirs {
+			matches, err := dir.Glob(pattern)
+			if err != nil {
+				return nil, err
+			}
+			paths = append(paths, matches...)
+			if level == depth {
+				continue
+			}
+			entries, err := dir.ReadDir()
+			if err != nil {
+				return nil, err
+			}
+			for _, entry := range entries {
+				if !entry.IsDir() {
+					continue
+				}
+				subdir := dir.Join(entry.Name())
+				m := exclusions.Match(subdir)
+				if m != nil && m.Exclude {
+					continue
+				}
+				if util.IsPythonEnvironmentDir(subdir) || util.IsRenvLibraryDir(subdir) {
+					continue
+				}
+				subdirs = append(subdirs, subdir)
+			}
+		}
+		dirs = subdirs
+	}
+	return paths, nil
+}
@@ -3,147 +3,190 @@ package detectors
 // Copyright (C) 2023 by Posit Software, PBC.
 
 import (
-	"errors"
-	"strings"
+	"fmt"
 	"testing"
 
 	"github.com/posit-dev/publisher/internal/util"
 	"github.com/posit-dev/publisher/internal/util/utiltest"
 	"github.com/spf13/afero"
-	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
 type EntrypointSuite struct {
 	utiltest.Suite
+	base util.AbsolutePath
 }
 
 func TestEntrypointSuite(t *testing.T) {
 	suite.Run(t, new(EntrypointSuite))
 }
 
-func (s *EntrypointSuite) TestFileHasPythonImports() {
-	base, err := util.Getwd(afero.NewMemMapFs())
+func (s *EntrypointSuite) SetupTest() {
+	s.base = util.NewAbsolutePath("/project", afero.NewMemMapFs())
+	err := s.base.MkdirAll(0777)
 	s.NoError(err)
+	util.CaseInsensitivePaths = func() bool { return false }
+}
 
-	path := base.Join("test.py")
-	err = path.WriteFile([]byte("import flask"), 0600)
-	s.Nil(err)
-
-	h := defaultInferenceHelper{}
-	isFlask, err := h.FileHasPythonImports(path, []string{"flask"})
-	s.Nil(err)
-	s.True(isFlask)
+func (s *EntrypointSuite) TearDownTest() {
+	util.CaseInsensitivePaths = defaultCaseInsensitivePaths
 }
 
-func (s *EntrypointSuite) TestFileHasPythonImportsOpenErr() {
-	fs := utiltest.NewMockFs()
-	testError := errors.New("test error from Open")
-	fs.On("Open", mock.Anything).Return(nil, testError)
+var defaultCaseInsensitivePaths = util.CaseInsensitivePaths
 
-	base, err := util.Getwd(fs)
+func (s *EntrypointSuite) createFile(name string) {
+	err := s.base.Join(name).WriteFile([]byte("import streamlit\n"), 0600)
 	s.NoError(err)
+}
 
-	h := defaultInferenceHelper{}
+func (s *EntrypointSuite) resolve(pattern string) (util.RelativePath, error) {
+	return ResolveEntrypoint(s.base, util.NewRelativePath(pattern, s.base.Fs()))
+}
 
-	path := base.Join("test.py")
-	isFlask, err := h.FileHasPythonImports(path, []string{"flask"})
-	s.NotNil(err)
-	s.ErrorIs(err, testError)
-	s.False(isFlask)
-	fs.AssertExpectations(s.T())
+func (s *EntrypointSuite) TestIsEntrypointGlob() {
+	s.True(IsEntrypointGlob("app_*.py"))
+	s.True(IsEntrypointGlob("app?.py"))
+	s.True(IsEntrypointGlob("app[12].py"))
+	s.False(IsEntrypointGlob("app.py"))
+	s.False(IsEntrypointGlob(""))
 }
 
-func (s *EntrypointSuite) TestHasPythonImports() {
-	r := strings.NewReader("import flask")
-	h := defaultInferenceHelper{}
-	isFlask, err := h.HasPythonImports(r, []string{"flask"})
-	s.Nil(err)
-	s.True(isFlask)
+func (s *EntrypointSuite) TestResolveNotGlob() {
+	// Entrypoints without wildcards don't need to exist.
+	ep, err := s.resolve("app.py")
+	s.NoError(err)
+	s.Equal("app.py", ep.String())
 }
 
-func (s *EntrypointSuite) TestHasPythonImportsFrom() {
-	r := strings.NewReader("from flask import api")
-	h := defaultInferenceHelper{}
-	isFlask, err := h.HasPythonImports(r, []string{"flask"})
-	s.Nil(err)
-	s.True(isFlask)
+func (s *EntrypointSuite) TestResolveCaseMismatch() {
+	// Only the case differs from the file's name.
+	s.createFile("app.py")
+	ep, err := s.resolve("App.py")
+	s.NoError(err)
+	s.Equal("app.py", ep.String())
+
+	ep, err = s.resolve("app.py")
+	s.NoError(err)
+	s.Equal("app.py", ep.String())
 }
 
-func (s *EntrypointSuite) TestHasPythonImportsSubpackage() {
-	r := strings.NewReader("import flask.api")
-	h := defaultInferenceHelper{}
-	isFlask, err := h.HasPythonImports(r, []string{"flask"})
-	s.Nil(err)
-	s.True(isFlask)
+func (s *EntrypointSuite) TestResolveCaseSensitive() {
+	s.createFile("APP_V2.PY")
+
+	_, err := s.resolve("app_*.py")
+	s.ErrorIs(err, ErrNoEntrypointMatch)
 }
 
-func (s *EntrypointSuite) TestHasPythonImportsFromSubpackage() {
-	r := strings.NewReader("from flask.api import foo")
-	h := defaultInferenceHelper{}
-	isFlask, err := h.HasPythonImports(r, []string{"flask"})
-	s.Nil(err)
-	s.True(isFlask)
+func (s *EntrypointSuite) TestResolveCaseInsensitive() {
+	util.CaseInsensitivePaths = func() bool { return true }
+	s.createFile("APP_V2.PY")
+	s.createFile("README.md")
+
+	ep, err := s.resolve("app_*.py")
+	s.NoError(err)
+	s.Equal("APP_V2.PY", ep.String())
+}
+
+func (s *EntrypointSuite) TestResolveOneMatch() {
+	s.createFile("app_v2.py")
+	s.createFile("README.md")
+
+	ep, err := s.resolve("app_*.py")
+	s.NoError(err)
+	s.Equal("app_v2.py", ep.String())
 }
 
-func (s *EntrypointSuite) TestFileHasPythonImportsRelatedPackage() {
-	base, err := util.Getwd(afero.NewMemMapFs())
+func (s *EntrypointSuite) TestResolveIgnoresDirectories() {
+	s.createFile("app_v2.py")
+	err := s.base.Join("app_old.py").MkdirAll(0777)
 	s.NoError(err)
-	path := base.Join("test.py")
 
-	err = path.WriteFile([]byte("import flask_api"), 0600)
-	s.Nil(err)
-	h := defaultInferenceHelper{}
-	isFlask, err := h.FileHasPythonImports(path, []string{"flask"})
-	s.Nil(err)
-	s.True(isFlask)
+	ep, err := s.resolve("app_*.py")
+	s.NoError(err)
+	s.Equal("app_v2.py", ep.String())
+}
+
+func (s *EntrypointSuite) TestResolveNoMatches() {
+	s.createFile("main.py")
+
+	_, err := s.resolve("app_*.py")
+	s.ErrorIs(err, ErrNoEntrypointMatch)
+	s.ErrorContains(err, "app_*.py")
+}
+
+func (s *EntrypointSuite) TestResolveMultipleMatches() {
+	s.createFile("app_v1.py")
+	s.createFile("app_v2.py")
+
+	_, err := s.resolve("app_*.py")
+	s.ErrorIs(err, ErrMultipleEntrypointMatches)
+	s.ErrorContains(err, "app_v1.py, app_v2.py")
 }
 
-func (s *EntrypointSuite) TestFileHasPythonImportsFromRelatedPackage() {
-	base, err := util.Getwd(afero.NewMemMapFs())
+func (s *EntrypointSuite) writeModule(name string, content string) util.AbsolutePath {
+	path := s.base.Join(name)
+	err := path.Dir().MkdirAll(0777)
+	s.NoError(err)
+	err = path.WriteFile([]byte(content), 0600)
 	s.NoError(err)
-	path := base.Join("test.py")
+	return path
+}
+
+func (s *EntrypointSuite) TestModulesHavePythonImports() {
+	app := s.writeModule("app.py", "from server import create_app\napp = create_app()\n")
+	s.writeModule("server.py", "import flask\n")
 
-	err = path.WriteFile([]byte("from flask_api import foo"), 0600)
-	s.Nil(err)
 	h := defaultInferenceHelper{}
-	isFlask, err := h.FileHasPythonImports(path, []string{"flask"})
-	s.Nil(err)
-	s.True(isFlask)
+	matches, err := h.ModulesHavePythonImports(s.base, app, []string{"flask"})
+	s.NoError(err)
+	s.True(matches)
+
+	matches, err = h.ModulesHavePythonImports(s.base, app, []string{"fastapi"})
+	s.NoError(err)
+	s.False(matches)
 }
 
-func (s *EntrypointSuite) TestHasPythonImportsNotPresent() {
-	r := strings.NewReader("import dash")
-	h := defaultInferenceHelper{}
-	isFlask, err := h.HasPythonImports(r, []string{"flask"})
-	s.Nil(err)
-	s.False(isFlask)
+func (s *EntrypointSuite) TestModulesHavePythonImportsPackages() {
+	app := s.writeModule("app.py", "import os\nfrom myapp import views as v, models\n")
+	s.writeModule("myapp/__init__.py", "from . import config\n")
+	s.writeModule("myapp/config.py", "")
+	s.writeModule("myapp/views.py", "from .routes import router\n")
+	s.writeModule("myapp/routes.py", "from fastapi import APIRouter\n")
+
+	matches, err := defaultInferenceHelper{}.ModulesHavePythonImports(s.base, app, []string{"fastapi"})
+	s.NoError(err)
+	s.True(matches)
 }
 
-func (s *EntrypointSuite) TestHasPythonImportsMultiple() {
-	r := strings.NewReader("import dash")
-	h := defaultInferenceHelper{}
-	isDash, err := h.HasPythonImports(r, []string{"dash_core_components", "dash"})
-	s.Nil(err)
-	s.True(isDash)
+func (s *EntrypointSuite) TestModulesHavePythonImportsCycle() {
+	app := s.writeModule("app.py", "import a\n")
+	s.writeModule("a.py", "import b\n")
+	s.writeModule("b.py", "import a\n")
+
+	matches, err := defaultInferenceHelper{}.ModulesHavePythonImports(s.base, app, []string{"flask"})
+	s.NoError(err)
+	s.False(matches)
 }
 
-func (s *EntrypointSuite) TestHasPythonImportsReadErr() {
-	r := utiltest.NewMockReader()
-	testError := errors.New("test error from Read")
-	r.On("Read", mock.Anything).Return(0, testError)
-	h := defaultInferenceHelper{}
-	isFlask, err := h.HasPythonImports(r, []string{""})
-	s.NotNil(err)
-	s.ErrorIs(err, testError)
-	s.False(isFlask)
-	r.AssertExpectations(s.T())
+func (s *EntrypointSuite) TestModulesHavePythonImportsLimit() {
+	// A chain of modules longer than the limit.
+	app := s.writeModule("app.py", "import m1\n")
+	for i := 1; i <= MaxImportScanFiles; i++ {
+		s.writeModule(fmt.Sprintf("m%d.py", i), fmt.Sprintf("import m%d\n", i+1))
+	}
+	s.writeModule(fmt.Sprintf("m%d.py", MaxImportScanFiles+1), "import flask\n")
+
+	matches, err := defaultInferenceHelper{}.ModulesHavePythonImports(s.base, app, []string{"flask"})
+	s.NoError(err)
+	s.False(matches)
 }
 
-func (s *EntrypointSuite) TestHasPythonImportsRegexpErr() {
-	r := strings.NewReader("")
-	h := defaultInferenceHelper{}
-	isFlask, err := h.HasPythonImports(r, []string{"("})
-	s.NotNil(err)
-	s.False(isFlask)
+func (s *EntrypointSuite) TestModulesHavePythonImportsOutsideProject() {
+	app := s.writeModule("app.py", "from .. import server\n")
+	err := s.base.Dir().Join("server.py").WriteFile([]byte("import flask\n"), 0600)
+	s.NoError(err)
+
+	matches, err := defaultInferenceHelper{}.ModulesHavePythonImports(s.base, app, []string{"flask"})
+	s.NoError(err)
+	s.False(matches)
 }
@@ -21,4 +21,5 @@ type ContentTypeInferer interface {
 type inferenceHelper interface {
 	HasPythonImports(r io.Reader, packages []string) (bool, error)
 	FileHasPythonImports(path util.AbsolutePath, packages []string) (bool, error)
+	ModulesHavePythonImports(base util.AbsolutePath, path util.AbsolutePath, packages []string) (bool, error)
 }
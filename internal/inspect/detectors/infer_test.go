@@ -41,3 +41,8 @@ func (m *MockInferenceHelper) FileHasPythonImports(path util.AbsolutePath, packa
 	args := m.Called(path, packages)
 	return args.Bool(0), args.Error(1)
 }
+
+func (m *MockInferenceHelper) ModulesHavePythonImports(base util.AbsolutePath, path util.AbsolutePath, packages []string) (bool, error) {
+	args := m.Called(base, path, packages)
+	return args.Bool(0), args.Error(1)
+}
@@ -12,6 +12,8 @@ import (
 
 type pyShinyDetector struct {
 	inferenceHelper
+	searchDepth int  // Levels of subdirectories to search for entrypoints
+	scanImports bool // Also check the local modules that entrypoints import
 }
 
 func NewPyShinyDetector() *pyShinyDetector {
@@ -20,6 +22,18 @@ func NewPyShinyDetector() *pyShinyDetector {
 	}
 }
 
+// SetSearchDepth enables searching for entrypoints in subdirectories
+// of the base directory, up to MaxSearchDepth levels deep.
+func (d *pyShinyDetector) SetSearchDepth(depth int) {
+	d.searchDepth = depth
+}
+
+// SetScanImports enables checking the project modules imported by
+// each entrypoint for the shiny import.
+func (d *pyShinyDetector) SetScanImports(scan bool) {
+	d.scanImports = scan
+}
+
 var shinyExpressImportRE = regexp.MustCompile(`(import\s+shiny.express)|(from\s+shiny.express\s+import)|(from\s+shiny\s+import.*\bexpress\b)`)
 
 func hasShinyExpressImport(content string) bool {
@@ -53,7 +67,7 @@ func (d *pyShinyDetector) InferType(base util.AbsolutePath, entrypoint util.Rela
 		}
 	}
 	var configs []*config.Config
-	entrypointPaths, err := base.Glob("*.py")
+	entrypointPaths, err := findEntrypoints(base, "*.py", d.searchDepth)
 	if err != nil {
 		return nil, err
 	}
@@ -66,7 +80,12 @@ func (d *pyShinyDetector) InferType(base util.AbsolutePath, entrypoint util.Rela
 			// Only inspect the specified file
 			continue
 		}
-		matches, err := d.FileHasPythonImports(entrypointPath, []string{"shiny"})
+		var matches bool
+		if d.scanImports {
+			matches, err = d.ModulesHavePythonImports(base, entrypointPath, []string{"shiny"})
+		} else {
+			matches, err = d.FileHasPythonImports(entrypointPath, []string{"shiny"})
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -81,11 +100,11 @@ func (d *pyShinyDetector) InferType(base util.AbsolutePath, entrypoint util.Rela
 		cfg := config.New()
 
 		if isShinyExpress {
-			cfg.Entrypoint = shinyExpressEntrypoint(relEntrypoint.String())
+			cfg.Entrypoint = shinyExpressEntrypoint(relEntrypoint.ToSlash())
 		} else {
-			cfg.Entrypoint = relEntrypoint.String()
+			cfg.Entrypoint = relEntrypoint.ToSlash()
 		}
-		cfg.Files = append(cfg.Files, fmt.Sprint("/", relEntrypoint.String()))
+		cfg.Files = append(cfg.Files, fmt.Sprint("/", relEntrypoint.ToSlash()))
 
 		cfg.Type = config.ContentTypePythonShiny
 		// indicate that Python inspection is needed
@@ -128,3 +128,20 @@ func (s *PyShinySuite) TestInferTypeWithExtraFile() {
 		Python:     &config.Python{},
 	}, configs[0])
 }
+
+func (s *PyShinySuite) TestInferTypeImportInHelperModule() {
+	base := util.NewAbsolutePath("/project", afero.NewMemMapFs())
+	err := base.MkdirAll(0777)
+	s.NoError(err)
+	err = base.Join("app.py").WriteFile([]byte("from ui import app_ui\n"), 0600)
+	s.NoError(err)
+	err = base.Join("ui.py").WriteFile([]byte("from shiny import ui\n"), 0600)
+	s.NoError(err)
+
+	detector := NewPyShinyDetector()
+	detector.SetScanImports(true)
+	configs, err := detector.InferType(base, util.NewRelativePath("app.py", base.Fs()))
+	s.NoError(err)
+	s.Len(configs, 1)
+	s.Equal("app.py", configs[0].Entrypoint)
+}
@@ -3,6 +3,9 @@ package detectors
 // Copyright (C) 2023 by Posit Software, PBC.
 
 import (
+	"regexp"
+	"unicode/utf8"
+
 	"github.com/posit-dev/publisher/internal/config"
 	"github.com/posit-dev/publisher/internal/util"
 )
@@ -11,6 +14,9 @@ type PythonAppDetector struct {
 	inferenceHelper
 	contentType config.ContentType
 	imports     []string
+	titleREs    []*regexp.Regexp // Patterns that extract a title from the source
+	searchDepth int              // Levels of subdirectories to search for entrypoints
+	scanImports bool             // Also check the local modules that entrypoints import
 }
 
 func NewPythonAppDetector(contentType config.ContentType, imports []string) *PythonAppDetector {
@@ -21,6 +27,28 @@ func NewPythonAppDetector(contentType config.ContentType, imports []string) *Pyt
 	}
 }
 
+// SetSearchDepth enables searching for entrypoints in subdirectories
+// of the base directory, up to MaxSearchDepth levels deep.
+// The default is zero, which only searches the base directory.
+func (d *PythonAppDetector) SetSearchDepth(depth int) {
+	d.searchDepth = depth
+}
+
+// SetScanImports enables checking the project modules imported by
+// each entrypoint for the framework import, when the entrypoint
+// doesn't import it directly.
+func (d *PythonAppDetector) SetScanImports(scan bool) {
+	d.scanImports = scan
+}
+
+// hasImports returns true if the entrypoint imports the framework.
+func (d *PythonAppDetector) hasImports(base util.AbsolutePath, entrypointPath util.AbsolutePath) (bool, error) {
+	if d.scanImports {
+		return d.ModulesHavePythonImports(base, entrypointPath, d.imports)
+	}
+	return d.FileHasPythonImports(entrypointPath, d.imports)
+}
+
 func NewFlaskDetector() *PythonAppDetector {
 	return NewPythonAppDetector(config.ContentTypePythonFlask, []string{
 		"flask", // also matches flask_api, flask_openapi3, etc.
@@ -42,15 +70,27 @@ func NewFastAPIDetector() *PythonAppDetector {
 	})
 }
 
+// titleArgPattern matches a quoted string literal, capturing its contents.
+const titleArgPattern = `(?:"([^"\n]*)"|'([^'\n]*)')`
+
+var dashTitleREs = []*regexp.Regexp{
+	// app.title = "My App"
+	regexp.MustCompile(`(?m)^\s*app\.title\s*=\s*` + titleArgPattern),
+	// app = dash.Dash(__name__, title="My App")
+	regexp.MustCompile(`Dash\([^)]*\btitle\s*=\s*` + titleArgPattern),
+}
+
 func NewDashDetector() *PythonAppDetector {
-	return NewPythonAppDetector(config.ContentTypePythonDash, []string{
+	d := NewPythonAppDetector(config.ContentTypePythonDash, []string{
 		"dash", // also matches dash_core_components, dash_bio, etc.
 	})
+	d.titleREs = dashTitleREs
+	return d
 }
 
-func NewStreamlitDetector() *PythonAppDetector {
-	return NewPythonAppDetector(config.ContentTypePythonStreamlit, []string{
-		"streamlit",
+func NewPanelDetector() *PythonAppDetector {
+	return NewPythonAppDetector(config.ContentTypePythonPanel, []string{
+		"panel", // matches `import panel as pn`, used with `pn.extension()`
 	})
 }
 
@@ -60,6 +100,39 @@ func NewBokehDetector() *PythonAppDetector {
 	})
 }
 
+// isValidAppTitle returns true if the title meets the requirements
+// of the configuration schema: a single line of 3-1000 characters.
+func isValidAppTitle(title string) bool {
+	length := utf8.RuneCountInString(title)
+	return length >= 3 && length <= 1000
+}
+
+// findTitle returns the title set in the app source,
+// or an empty string if there isn't a valid one.
+func (d *PythonAppDetector) findTitle(path util.AbsolutePath) (string, error) {
+	if len(d.titleREs) == 0 {
+		return "", nil
+	}
+	content, err := path.ReadFile()
+	if err != nil {
+		return "", err
+	}
+	for _, re := range d.titleREs {
+		m := re.FindSubmatch(content)
+		if m == nil {
+			continue
+		}
+		// One group for each quote style; only one will match.
+		for _, group := range m[1:] {
+			title := string(group)
+			if isValidAppTitle(title) {
+				return title, nil
+			}
+		}
+	}
+	return "", nil
+}
+
 func (d *PythonAppDetector) InferType(base util.AbsolutePath, entrypoint util.RelativePath) ([]*config.Config, error) {
 	if entrypoint.String() != "" {
 		// Optimization: skip inspection if there's a specified entrypoint
@@ -69,8 +142,7 @@ func (d *PythonAppDetector) InferType(base util.AbsolutePath, entrypoint util.Re
 		}
 	}
 	var configs []*config.Config
-	entrypointPaths, err := base.Glob("*.py")
-
+	entrypointPaths, err := findEntrypoints(base, "*.py", d.searchDepth)
 	if err != nil {
 		return nil, err
 	}
@@ -83,14 +155,18 @@ func (d *PythonAppDetector) InferType(base util.AbsolutePath, entrypoint util.Re
 			// Only inspect the specified file
 			continue
 		}
-		matches, err := d.FileHasPythonImports(entrypointPath, d.imports)
+		matches, err := d.hasImports(base, entrypointPath)
 		if err != nil {
 			return nil, err
 		}
 		if matches {
 			cfg := config.New()
-			cfg.Entrypoint = relEntrypoint.String()
+			cfg.Entrypoint = relEntrypoint.ToSlash()
 			cfg.Type = d.contentType
+			cfg.Title, err = d.findTitle(entrypointPath)
+			if err != nil {
+				return nil, err
+			}
 			// indicate that Python inspection is needed
 			cfg.Python = &config.Python{}
 			configs = append(configs, cfg)
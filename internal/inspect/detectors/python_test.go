@@ -168,3 +168,180 @@ func (s *PythonSuite) TestInferTypeWithEntrypoint() {
 		Python:     &config.Python{},
 	}, configs[0])
 }
+
+func (s *PythonSuite) TestInferTypePanel() {
+	base := util.NewAbsolutePath("/project", afero.NewMemMapFs())
+	err := base.MkdirAll(0777)
+	s.NoError(err)
+
+	filename := "dashboard.py"
+	err = base.Join(filename).WriteFile([]byte(
+		"import panel as pn\n"+
+			"pn.extension()\n"+
+			"slider = pn.widgets.IntSlider(name='Value', start=0, end=10)\n"+
+			"pn.Column(slider, pn.bind(lambda v: v * 2, slider)).servable()\n"), 0600)
+	s.Nil(err)
+
+	// a distraction
+	err = base.Join("util.py").WriteFile([]byte("import os\n"), 0600)
+	s.Nil(err)
+
+	detector := NewPanelDetector()
+	configs, err := detector.InferType(base, util.RelativePath{})
+	s.Nil(err)
+	s.Len(configs, 1)
+
+	s.Equal(&config.Config{
+		Schema:     schema.ConfigSchemaURL,
+		Type:       config.ContentTypePythonPanel,
+		Entrypoint: filename,
+		Validate:   true,
+		Files:      []string{},
+		Python:     &config.Python{},
+	}, configs[0])
+}
+
+func (s *PythonSuite) TestInferTypeDashTitle() {
+	base := util.NewAbsolutePath("/project", afero.NewMemMapFs())
+	err := base.MkdirAll(0777)
+	s.NoError(err)
+
+	err = base.Join("app.py").WriteFile([]byte(
+		"from dash import Dash, html\n"+
+			"app = Dash(__name__)\n"+
+			"app.title = \"Quarterly Sales\"\n"+
+			"app.layout = html.Div('hello')\n"), 0600)
+	s.Nil(err)
+
+	err = base.Join("other.py").WriteFile([]byte(
+		"import dash\n"+
+			"app = dash.Dash(__name__, title='Other Sales')\n"), 0600)
+	s.Nil(err)
+
+	detector := NewDashDetector()
+	configs, err := detector.InferType(base, util.RelativePath{})
+	s.Nil(err)
+	s.Len(configs, 2)
+
+	s.Equal(&config.Config{
+		Schema:     schema.ConfigSchemaURL,
+		Type:       config.ContentTypePythonDash,
+		Entrypoint: "app.py",
+		Title:      "Quarterly Sales",
+		Validate:   true,
+		Files:      []string{},
+		Python:     &config.Python{},
+	}, configs[0])
+	s.Equal("other.py", configs[1].Entrypoint)
+	s.Equal("Other Sales", configs[1].Title)
+}
+
+func (s *PythonSuite) TestInferTypeDashNoTitle() {
+	base := util.NewAbsolutePath("/project", afero.NewMemMapFs())
+	err := base.MkdirAll(0777)
+	s.NoError(err)
+
+	err = base.Join("app.py").WriteFile([]byte("import dash\napp = dash.Dash(__name__)\n"), 0600)
+	s.Nil(err)
+
+	detector := NewDashDetector()
+	configs, err := detector.InferType(base, util.RelativePath{})
+	s.Nil(err)
+	s.Len(configs, 1)
+	s.Equal("", configs[0].Title)
+}
+
+func (s *PythonSuite) TestInferTypeNested() {
+	base := util.NewAbsolutePath("/project", afero.NewMemMapFs())
+	srcDir := base.Join("src")
+	err := srcDir.MkdirAll(0777)
+	s.NoError(err)
+
+	err = srcDir.Join("app.py").WriteFile([]byte("import flask\napp = flask.Flask(__name__)\n"), 0600)
+	s.NoError(err)
+
+	detector := NewFlaskDetector()
+
+	// Subdirectories are not searched by default
+	configs, err := detector.InferType(base, util.RelativePath{})
+	s.NoError(err)
+	s.Len(configs, 0)
+
+	detector.SetSearchDepth(1)
+	configs, err = detector.InferType(base, util.RelativePath{})
+	s.NoError(err)
+	s.Len(configs, 1)
+
+	s.Equal(&config.Config{
+		Schema:     schema.ConfigSchemaURL,
+		Type:       config.ContentTypePythonFlask,
+		Entrypoint: "src/app.py",
+		Validate:   true,
+		Files:      []string{},
+		Python:     &config.Python{},
+	}, configs[0])
+
+	// The specified entrypoint is relative to the base directory
+	entrypoint := util.NewRelativePath("src/app.py", base.Fs())
+	configs, err = detector.InferType(base, entrypoint)
+	s.NoError(err)
+	s.Len(configs, 1)
+	s.Equal("src/app.py", configs[0].Entrypoint)
+}
+
+func (s *PythonSuite) TestInferTypeNestedSkipsExcludedDirs() {
+	base := util.NewAbsolutePath("/project", afero.NewMemMapFs())
+	content := []byte("import flask\napp = flask.Flask(__name__)\n")
+
+	// Python environment
+	venvDir := base.Join("src", "venv")
+	err := venvDir.Join("bin").MkdirAll(0777)
+	s.NoError(err)
+	err = venvDir.Join("bin", "python3").WriteFile(nil, 0700)
+	s.NoError(err)
+	err = venvDir.Join("app.py").WriteFile(content, 0600)
+	s.NoError(err)
+
+	// Built-in exclusion
+	cacheDir := base.Join("__pycache__")
+	err = cacheDir.MkdirAll(0777)
+	s.NoError(err)
+	err = cacheDir.Join("app.py").WriteFile(content, 0600)
+	s.NoError(err)
+
+	// Too deep
+	deepDir := base.Join("a", "b", "c")
+	err = deepDir.MkdirAll(0777)
+	s.NoError(err)
+	err = deepDir.Join("app.py").WriteFile(content, 0600)
+	s.NoError(err)
+
+	detector := NewFlaskDetector()
+	detector.SetSearchDepth(MaxSearchDepth)
+	configs, err := detector.InferType(base, util.RelativePath{})
+	s.NoError(err)
+	s.Len(configs, 0)
+}
+
+func (s *PythonSuite) TestInferTypeImportInHelperModule() {
+	base := util.NewAbsolutePath("/project", afero.NewMemMapFs())
+	err := base.Join("myapp").MkdirAll(0777)
+	s.NoError(err)
+	err = base.Join("app.py").WriteFile([]byte("from myapp.server import app\n"), 0600)
+	s.NoError(err)
+	err = base.Join("myapp", "__init__.py").WriteFile(nil, 0600)
+	s.NoError(err)
+	err = base.Join("myapp", "server.py").WriteFile([]byte("from flask import Flask\napp = Flask(__name__)\n"), 0600)
+	s.NoError(err)
+
+	detector := NewFlaskDetector()
+	configs, err := detector.InferType(base, util.RelativePath{})
+	s.NoError(err)
+	s.Len(configs, 0)
+
+	detector.SetScanImports(true)
+	configs, err = detector.InferType(base, util.RelativePath{})
+	s.NoError(err)
+	s.Len(configs, 1)
+	s.Equal("app.py", configs[0].Entrypoint)
+}
@@ -4,8 +4,12 @@ package detectors
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"os/exec"
+	"path"
 	"path/filepath"
+	"regexp"
 	"slices"
 	"strings"
 
@@ -13,6 +17,7 @@ import (
 	"github.com/posit-dev/publisher/internal/executor"
 	"github.com/posit-dev/publisher/internal/inspect/dependencies/pydeps"
 	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/types"
 	"github.com/posit-dev/publisher/internal/util"
 )
 
@@ -20,6 +25,10 @@ type QuartoDetector struct {
 	inferenceHelper
 	executor executor.Executor
 	log      logging.Logger
+
+	// quartoNotFound is set by InferType if the project
+	// can only be deployed with Quarto, which isn't installed.
+	quartoNotFound bool
 }
 
 func NewQuartoDetector() *QuartoDetector {
@@ -36,6 +45,67 @@ type quartoMetadata struct {
 	Server  any    `json:"server"`
 }
 
+type quartoFormat struct {
+	Metadata quartoMetadata `json:"metadata"`
+	Pandoc   struct {
+		OutputFile string `json:"output-file"`
+	} `json:"pandoc"`
+}
+
+// deployableFormats are the formats that Connect can serve,
+// in order of preference.
+var deployableFormats = []string{"html", "dashboard", "revealjs"}
+
+// deployableFormat returns the name and details of the format to
+// deploy, or the first one by name if none of them are known to be
+// deployable. It returns nil if there are no formats.
+func (o *quartoInspectOutput) deployableFormat() (string, *quartoFormat) {
+	for _, name := range deployableFormats {
+		if format, ok := o.Formats[name]; ok {
+			return name, &format
+		}
+	}
+	names := []string{}
+	for name := range o.Formats {
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return "", nil
+	}
+	slices.Sort(names)
+	format := o.Formats[names[0]]
+	return names[0], &format
+}
+
+// contentType returns the Connect content type for the document.
+// Documents and dashboards with a Shiny server are interactive;
+// everything else, including static dashboards and unknown formats,
+// is rendered as static Quarto content.
+func (o *quartoInspectOutput) contentType() config.ContentType {
+	for _, format := range o.Formats {
+		if isQuartoShiny(&format.Metadata) {
+			return config.ContentTypeQuartoShiny
+		}
+	}
+	return config.ContentTypeQuarto
+}
+
+// outputDir returns the project's output directory, such as _site,
+// as a Posix path relative to the project directory. It returns an
+// empty string if the project renders in place, or the directory
+// isn't within the project.
+func (o *quartoInspectOutput) outputDir() string {
+	dir := o.Project.Config.Project.OutputDir
+	if dir == "" {
+		return ""
+	}
+	dir = path.Clean(filepath.ToSlash(dir))
+	if dir == "." || !filepath.IsLocal(filepath.FromSlash(dir)) {
+		return ""
+	}
+	return dir
+}
+
 type quartoInspectOutput struct {
 	// Only the fields we use are included; the rest
 	// are discarded by the JSON decoder.
@@ -45,6 +115,7 @@ type quartoInspectOutput struct {
 	Project struct {
 		Config struct {
 			Project struct {
+				Type       string   `json:"type"`
 				Title      string   `json:"title"`
 				PreRender  []string `json:"pre-render"`
 				PostRender []string `json:"post-render"`
@@ -62,31 +133,26 @@ type quartoInspectOutput struct {
 	Files   struct {
 		Input []string `json:"input"`
 	} `json:"files"`
-	// For single quarto docs without _quarto.yml
-	Formats struct {
-		HTML struct {
-			Metadata quartoMetadata `json:"metadata"`
-			Pandoc   struct {
-				OutputFile string `json:"output-file"`
-			} `json:"pandoc"`
-		} `json:"html"`
-		RevealJS struct {
-			Metadata quartoMetadata `json:"metadata"`
-			Pandoc   struct {
-				OutputFile string `json:"output-file"`
-			} `json:"pandoc"`
-		} `json:"revealjs"`
-	} `json:"formats"`
+	// For single quarto docs without _quarto.yml,
+	// keyed by format name (html, revealjs, dashboard, pdf, ...)
+	Formats map[string]quartoFormat `json:"formats"`
 
 	// For single quarto docs without _quarto.yml,
 	// there is no project section in the output.
 	FileInformation map[string]any `json:"fileInformation"`
 }
 
-func (d *QuartoDetector) quartoInspect(path util.AbsolutePath) (*quartoInspectOutput, error) {
+var errQuartoNotInstalled = errors.New("quarto is not installed or is not on the PATH; install Quarto from https://quarto.org/docs/get-started/ to deploy this project")
+
+// quartoInspect runs `quarto inspect` on path in the project
+// directory, so relative resources in the project are found.
+func (d *QuartoDetector) quartoInspect(base util.AbsolutePath, path util.AbsolutePath) (*quartoInspectOutput, error) {
 	args := []string{"inspect", path.String()}
-	out, _, err := d.executor.RunCommand("quarto", args, util.AbsolutePath{}, d.log)
+	out, _, err := d.executor.RunCommand("quarto", args, base, d.log)
 	if err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return nil, errQuartoNotInstalled
+		}
 		return nil, fmt.Errorf("quarto inspect failed: %w", err)
 	}
 	var inspectOutput quartoInspectOutput
@@ -158,11 +224,8 @@ func (d *QuartoDetector) getTitle(inspectOutput *quartoInspectOutput, entrypoint
 	isValidTitle := func(title string) bool {
 		return title != "" && title != entrypointName
 	}
-	if isValidTitle(inspectOutput.Formats.HTML.Metadata.Title) {
-		return inspectOutput.Formats.HTML.Metadata.Title
-	}
-	if isValidTitle(inspectOutput.Formats.RevealJS.Metadata.Title) {
-		return inspectOutput.Formats.RevealJS.Metadata.Title
+	if _, format := inspectOutput.deployableFormat(); format != nil && isValidTitle(format.Metadata.Title) {
+		return format.Metadata.Title
 	}
 	if isValidTitle(inspectOutput.Project.Config.Website.Title) {
 		return inspectOutput.Project.Config.Website.Title
@@ -173,6 +236,16 @@ func (d *QuartoDetector) getTitle(inspectOutput *quartoInspectOutput, entrypoint
 	return ""
 }
 
+// ojsBlockRE matches Observable JS code chunks, like ```{ojs}
+var ojsBlockRE = regexp.MustCompile("(?m)^```[{]ojs[ ,}]")
+
+// hasOJSCells returns true if the content contains Observable JS
+// code chunks. These run in the browser, so they don't need
+// Python or R on the server.
+func hasOJSCells(content []byte) bool {
+	return ojsBlockRE.Match(content)
+}
+
 var quartoSuffixes = []string{".qmd", ".Rmd", ".ipynb", ".R", ".py", ".jl"}
 
 func (d *QuartoDetector) findEntrypoints(base util.AbsolutePath) ([]util.AbsolutePath, error) {
@@ -188,6 +261,24 @@ func (d *QuartoDetector) findEntrypoints(base util.AbsolutePath) ([]util.Absolut
 	return allPaths, nil
 }
 
+// isClearlyQuarto returns true if the file can only be deployed
+// with Quarto: it is a .qmd file, or the project has a _quarto.yml.
+func isClearlyQuarto(base util.AbsolutePath, path util.AbsolutePath) (bool, error) {
+	if path.HasSuffix(".qmd") {
+		return true, nil
+	}
+	for _, filename := range []string{"_quarto.yml", "_quarto.yaml"} {
+		exists, err := base.Join(filename).Exists()
+		if err != nil {
+			return false, err
+		}
+		if exists {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func isQuartoShiny(metadata *quartoMetadata) bool {
 	if metadata == nil {
 		return false
@@ -201,7 +292,17 @@ func isQuartoShiny(metadata *quartoMetadata) bool {
 	return false
 }
 
+// MissingToolError returns an error if the project inspected by
+// the last call to InferType needs Quarto, but it isn't installed.
+func (d *QuartoDetector) MissingToolError() error {
+	if d.quartoNotFound {
+		return types.NewAgentError(types.ErrorQuartoNotFound, errQuartoNotInstalled, nil)
+	}
+	return nil
+}
+
 func (d *QuartoDetector) InferType(base util.AbsolutePath, entrypoint util.RelativePath) ([]*config.Config, error) {
+	d.quartoNotFound = false
 	if entrypoint.String() != "" {
 		// Optimization: skip inspection if there's a specified entrypoint
 		// and it's not one of ours.
@@ -210,6 +311,11 @@ func (d *QuartoDetector) InferType(base util.AbsolutePath, entrypoint util.Relat
 		}
 	}
 	var configs []*config.Config
+	// Documents of a Quarto website or book are rendered together,
+	// starting from the index, so the other documents
+	// aren't offered as entrypoints.
+	nonIndexSiteDocs := map[*config.Config]bool{}
+	hasSiteIndex := false
 	entrypointPaths, err := d.findEntrypoints(base)
 	if err != nil {
 		return nil, err
@@ -223,9 +329,22 @@ func (d *QuartoDetector) InferType(base util.AbsolutePath, entrypoint util.Relat
 			// Only inspect the specified file
 			continue
 		}
-		inspectOutput, err := d.quartoInspect(entrypointPath)
+		inspectOutput, err := d.quartoInspect(base, entrypointPath)
+		if errors.Is(err, errQuartoNotInstalled) {
+			isQuarto, err := isClearlyQuarto(base, entrypointPath)
+			if err != nil {
+				return nil, err
+			}
+			// Let the other detectors handle the project; it
+			// may be an app with a stray README.qmd. Other files,
+			// like notebooks and scripts, can be deployed without
+			// Quarto. There's no point inspecting the rest.
+			d.quartoNotFound = isQuarto
+			d.log.Warn("quarto is not installed; skipping Quarto detection", "file", entrypointPath.String())
+			return configs, nil
+		}
 		if err != nil {
-			// Maybe this isn't really a quarto project, or maybe the user doesn't have quarto.
+			// Maybe this isn't really a quarto project.
 			// We log this error and continue checking the other files.
 			d.log.Warn("quarto inspect failed", "file", entrypointPath.String(), "error", err)
 			continue
@@ -235,14 +354,9 @@ func (d *QuartoDetector) InferType(base util.AbsolutePath, entrypoint util.Relat
 		cfg.Entrypoint = relEntrypoint.String()
 		cfg.Title = d.getTitle(inspectOutput, relEntrypoint.String())
 
-		if isQuartoShiny(&inspectOutput.Formats.HTML.Metadata) ||
-			isQuartoShiny(&inspectOutput.Formats.RevealJS.Metadata) {
-			cfg.Type = config.ContentTypeQuartoShiny
-		} else {
-			cfg.Type = config.ContentTypeQuarto
-		}
+		cfg.Type = inspectOutput.contentType()
 
-		var needR, needPython bool
+		var needR, needPython, usesOJS bool
 
 		if entrypointPath.HasSuffix(".ipynb") {
 			needPython = true
@@ -253,6 +367,7 @@ func (d *QuartoDetector) InferType(base util.AbsolutePath, entrypoint util.Relat
 				return nil, err
 			}
 			needR, needPython = pydeps.DetectMarkdownLanguagesInContent(content)
+			usesOJS = hasOJSCells(content)
 		}
 		engines := inspectOutput.Engines
 		if needPython || d.needsPython(inspectOutput) {
@@ -274,6 +389,9 @@ func (d *QuartoDetector) InferType(base util.AbsolutePath, entrypoint util.Relat
 		cfg.Quarto = &config.Quarto{
 			Version: inspectOutput.Quarto.Version,
 			Engines: engines,
+			// Quarto runs OJS cells with the markdown engine,
+			// so they aren't listed in the engines.
+			HasOJS: usesOJS,
 		}
 
 		// Only include the entrypoint and its associated files.
@@ -301,7 +419,29 @@ func (d *QuartoDetector) InferType(base util.AbsolutePath, entrypoint util.Relat
 				cfg.Files = append(cfg.Files, fmt.Sprint("/", filename))
 			}
 		}
+		if outputDir := inspectOutput.outputDir(); outputDir != "" {
+			// The project is rendered on the server, so don't
+			// deploy stale output from local renders.
+			cfg.Files = append(cfg.Files, fmt.Sprintf("!/%s/", outputDir))
+		}
 		configs = append(configs, cfg)
+		if inspectOutput.isSite() {
+			if slices.Contains(config.QuartoIndexFilenames, cfg.Entrypoint) {
+				hasSiteIndex = true
+			} else {
+				nonIndexSiteDocs[cfg] = true
+			}
+		}
+	}
+	if hasSiteIndex {
+		configs = slices.DeleteFunc(configs, func(cfg *config.Config) bool {
+			return nonIndexSiteDocs[cfg]
+		})
 	}
 	return configs, nil
 }
+
+// isSite returns true if the document is part of a Quarto website or book.
+func (o *quartoInspectOutput) isSite() bool {
+	return slices.Contains(config.QuartoSiteTypes, o.Project.Config.Project.Type)
+}
@@ -5,7 +5,9 @@ package detectors
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"os/exec"
 	"runtime"
 	"strings"
 	"testing"
@@ -13,8 +15,10 @@ import (
 	"github.com/posit-dev/publisher/internal/config"
 	"github.com/posit-dev/publisher/internal/executor/executortest"
 	"github.com/posit-dev/publisher/internal/schema"
+	"github.com/posit-dev/publisher/internal/types"
 	"github.com/posit-dev/publisher/internal/util"
 	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/spf13/afero"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
@@ -72,6 +76,23 @@ func (s *QuartoDetectorSuite) runInferType(testName string) []*config.Config {
 	return configs
 }
 
+func (s *QuartoDetectorSuite) TestQuartoInspectWorkDir() {
+	cwd, err := util.Getwd(nil)
+	s.NoError(err)
+	base := cwd.Join("testdata", "quarto-doc-none")
+	path := base.Join("quarto-doc-none.qmd")
+
+	detector := NewQuartoDetector()
+	executor := executortest.NewMockExecutor()
+	detector.executor = executor
+
+	// quarto runs in the project directory.
+	executor.On("RunCommand", "quarto", []string{"inspect", path.String()}, base, mock.Anything).Return([]byte("{}"), nil, nil).Once()
+	_, err = detector.quartoInspect(base, path)
+	s.NoError(err)
+	executor.AssertExpectations(s.T())
+}
+
 func (s *QuartoDetectorSuite) TestInferTypeMarkdownDoc() {
 	if runtime.GOOS == "windows" {
 		s.T().Skip("This test does not run on Windows")
@@ -92,6 +113,107 @@ func (s *QuartoDetectorSuite) TestInferTypeMarkdownDoc() {
 	}, configs[0])
 }
 
+func (s *QuartoDetectorSuite) TestInferTypeShinyDashboard() {
+	if runtime.GOOS == "windows" {
+		s.T().Skip("This test does not run on Windows")
+	}
+	configs := s.runInferType("quarto-doc-dashboard")
+	s.Len(configs, 1)
+	// A dashboard with a Shiny server is interactive.
+	s.Equal(&config.Config{
+		Schema:     schema.ConfigSchemaURL,
+		Type:       config.ContentTypeQuartoShiny,
+		Entrypoint: "quarto-doc-dashboard.qmd",
+		Title:      "Sales Dashboard",
+		Validate:   true,
+		Files:      []string{"/quarto-doc-dashboard.qmd"},
+		Python:     &config.Python{},
+		Quarto: &config.Quarto{
+			Version: "1.4.553",
+			Engines: []string{"jupyter"},
+		},
+	}, configs[0])
+}
+
+func (s *QuartoDetectorSuite) TestInferTypeRevealJSDoc() {
+	if runtime.GOOS == "windows" {
+		s.T().Skip("This test does not run on Windows")
+	}
+	configs := s.runInferType("quarto-doc-revealjs")
+	s.Len(configs, 1)
+	// The title comes from the revealjs format, not the PDF.
+	s.Equal(&config.Config{
+		Schema:     schema.ConfigSchemaURL,
+		Type:       config.ContentTypeQuarto,
+		Entrypoint: "quarto-doc-revealjs.qmd",
+		Title:      "Quarterly Review",
+		Validate:   true,
+		Files:      []string{},
+		Quarto: &config.Quarto{
+			Version: "1.4.553",
+			Engines: []string{"markdown"},
+		},
+	}, configs[0])
+}
+
+func (s *QuartoDetectorSuite) TestDeployableFormat() {
+	output := &quartoInspectOutput{}
+	name, format := output.deployableFormat()
+	s.Equal("", name)
+	s.Nil(format)
+
+	output.Formats = map[string]quartoFormat{
+		"pdf":       {},
+		"dashboard": {},
+		"revealjs":  {},
+	}
+	name, _ = output.deployableFormat()
+	s.Equal("dashboard", name)
+	// Static dashboards don't need a Shiny server.
+	s.Equal(config.ContentTypeQuarto, output.contentType())
+
+	output.Formats["dashboard"] = quartoFormat{Metadata: quartoMetadata{Server: "shiny"}}
+	s.Equal(config.ContentTypeQuartoShiny, output.contentType())
+
+	// Unknown formats are treated as static Quarto content.
+	output.Formats = map[string]quartoFormat{
+		"typst": {Metadata: quartoMetadata{Title: "Report"}},
+		"docx":  {},
+	}
+	name, _ = output.deployableFormat()
+	s.Equal("docx", name)
+	s.Equal(config.ContentTypeQuarto, output.contentType())
+}
+
+func (s *QuartoDetectorSuite) TestInferTypeOJSDoc() {
+	if runtime.GOOS == "windows" {
+		s.T().Skip("This test does not run on Windows")
+	}
+	configs := s.runInferType("quarto-doc-ojs")
+	s.Len(configs, 1)
+	// OJS-only content is static and needs neither Python nor R.
+	s.Equal(&config.Config{
+		Schema:     schema.ConfigSchemaURL,
+		Type:       config.ContentTypeQuarto,
+		Entrypoint: "quarto-doc-ojs.qmd",
+		Title:      "Penguin Explorer",
+		Validate:   true,
+		Files:      []string{},
+		Quarto: &config.Quarto{
+			Version: "1.4.553",
+			Engines: []string{"markdown"},
+			HasOJS:  true,
+		},
+	}, configs[0])
+}
+
+func (s *QuartoDetectorSuite) TestHasOJSCells() {
+	s.True(hasOJSCells([]byte("# Title\n```{ojs}\n1 + 1\n```\n")))
+	s.True(hasOJSCells([]byte("```{ojs echo=false}\nx = 1\n```\n")))
+	s.False(hasOJSCells([]byte("```{python}\nx = 1\n```\n")))
+	s.False(hasOJSCells([]byte("Inline `ojs x` is not a chunk\n")))
+}
+
 func (s *QuartoDetectorSuite) TestInferTypeMarkdownProject() {
 	if runtime.GOOS == "windows" {
 		s.T().Skip("This test does not run on Windows")
@@ -221,32 +343,22 @@ func (s *QuartoDetectorSuite) TestInferTypeQuartoWebsite() {
 	if runtime.GOOS == "windows" {
 		s.T().Skip("This test does not run on Windows")
 	}
+	// The website is rendered from its index document,
+	// so about.qmd isn't offered as an entrypoint.
 	configs := s.runInferType("quarto-website-none")
-	s.Len(configs, 2)
-	s.Equal(&config.Config{
-		Schema:     schema.ConfigSchemaURL,
-		Type:       config.ContentTypeQuarto,
-		Entrypoint: "about.qmd",
-		Title:      "About",
-		Validate:   true,
-		Files:      []string{"/index.qmd", "/about.qmd", "/_quarto.yml"},
-		Quarto: &config.Quarto{
-			Version: "1.4.553",
-			Engines: []string{"markdown"},
-		},
-	}, configs[0])
+	s.Len(configs, 1)
 	s.Equal(&config.Config{
 		Schema:     schema.ConfigSchemaURL,
 		Type:       config.ContentTypeQuarto,
 		Entrypoint: "index.qmd",
 		Title:      "quarto-website-none",
 		Validate:   true,
-		Files:      []string{"/index.qmd", "/about.qmd", "/_quarto.yml"},
+		Files:      []string{"/index.qmd", "/about.qmd", "/_quarto.yml", "!/_site/"},
 		Quarto: &config.Quarto{
 			Version: "1.4.553",
 			Engines: []string{"markdown"},
 		},
-	}, configs[1])
+	}, configs[0])
 }
 
 func (s *QuartoDetectorSuite) TestInferTypeRMarkdownDoc() {
@@ -385,3 +497,126 @@ func (s *QuartoDetectorSuite) TestInferTypeQuartoScriptR() {
 		R: &config.R{},
 	}, configs[0])
 }
+
+func (s *QuartoDetectorSuite) quartoMissingDetector(runErr error) *QuartoDetector {
+	detector := NewQuartoDetector()
+	executor := executortest.NewMockExecutor()
+	executor.On("RunCommand", "quarto", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil, runErr)
+	detector.executor = executor
+	return detector
+}
+
+var errQuartoExecNotFound = &exec.Error{Name: "quarto", Err: exec.ErrNotFound}
+
+func (s *QuartoDetectorSuite) TestInferTypeQuartoNotInstalled() {
+	base := util.NewAbsolutePath("/project", afero.NewMemMapFs())
+	err := base.Join("report.qmd").WriteFile([]byte("# Report\n"), 0600)
+	s.NoError(err)
+
+	detector := s.quartoMissingDetector(errQuartoExecNotFound)
+	configs, err := detector.InferType(base, util.RelativePath{})
+	s.NoError(err)
+	s.Len(configs, 0)
+	aerr, ok := types.IsAgentErrorOf(detector.MissingToolError(), types.ErrorQuartoNotFound)
+	s.True(ok)
+	s.Contains(aerr.Message, "install Quarto")
+}
+
+func (s *QuartoDetectorSuite) TestInferTypeQuartoNotInstalledProject() {
+	base := util.NewAbsolutePath("/project", afero.NewMemMapFs())
+	err := base.Join("_quarto.yml").WriteFile([]byte("project:\n  type: default\n"), 0600)
+	s.NoError(err)
+	err = base.Join("analysis.ipynb").WriteFile([]byte("{}"), 0600)
+	s.NoError(err)
+
+	detector := s.quartoMissingDetector(errQuartoExecNotFound)
+	_, err = detector.InferType(base, util.RelativePath{})
+	s.NoError(err)
+	_, ok := types.IsAgentErrorOf(detector.MissingToolError(), types.ErrorQuartoNotFound)
+	s.True(ok)
+}
+
+func (s *QuartoDetectorSuite) TestInferTypeQuartoNotInstalledOtherContent() {
+	// Without Quarto, notebooks and scripts are left
+	// to the other detectors.
+	base := util.NewAbsolutePath("/project", afero.NewMemMapFs())
+	err := base.Join("analysis.ipynb").WriteFile([]byte("{}"), 0600)
+	s.NoError(err)
+	err = base.Join("app.py").WriteFile([]byte("import dash\n"), 0600)
+	s.NoError(err)
+
+	detector := s.quartoMissingDetector(errQuartoExecNotFound)
+	configs, err := detector.InferType(base, util.RelativePath{})
+	s.NoError(err)
+	s.Len(configs, 0)
+	s.NoError(detector.MissingToolError())
+	// Inspection stops after the first file.
+	detector.executor.(*executortest.MockExecutor).AssertNumberOfCalls(s.T(), "RunCommand", 1)
+}
+
+func (s *QuartoDetectorSuite) TestInferAllQuartoNotInstalled() {
+	// An app with a stray .qmd file is still detected.
+	base := util.NewAbsolutePath("/project", afero.NewMemMapFs())
+	err := base.Join("README.qmd").WriteFile([]byte("# About\n"), 0600)
+	s.NoError(err)
+	err = base.Join("app.py").WriteFile([]byte("from flask import Flask\n"), 0600)
+	s.NoError(err)
+
+	detector := &ContentTypeDetector{
+		detectors: []ContentTypeInferer{
+			s.quartoMissingDetector(errQuartoExecNotFound),
+			NewFlaskDetector(),
+		},
+	}
+	configs, err := detector.InferType(base, util.RelativePath{})
+	s.NoError(err)
+	s.Len(configs, 1)
+	s.Equal(config.ContentTypePythonFlask, configs[0].Type)
+	s.Equal("app.py", configs[0].Entrypoint)
+}
+
+func (s *QuartoDetectorSuite) TestInferAllQuartoNotInstalledOnlyQuarto() {
+	// If nothing else is detected, report the missing tool.
+	base := util.NewAbsolutePath("/project", afero.NewMemMapFs())
+	err := base.Join("report.qmd").WriteFile([]byte("# Report\n"), 0600)
+	s.NoError(err)
+
+	detector := &ContentTypeDetector{
+		detectors: []ContentTypeInferer{
+			s.quartoMissingDetector(errQuartoExecNotFound),
+			NewFlaskDetector(),
+		},
+	}
+	configs, err := detector.InferType(base, util.RelativePath{})
+	s.Nil(configs)
+	_, ok := types.IsAgentErrorOf(err, types.ErrorQuartoNotFound)
+	s.True(ok)
+}
+
+func (s *QuartoDetectorSuite) TestInferTypeQuartoInspectError() {
+	// Other failures, e.g. a document with invalid YAML, are skipped.
+	base := util.NewAbsolutePath("/project", afero.NewMemMapFs())
+	err := base.Join("report.qmd").WriteFile([]byte("---\ntitle: [\n---\n"), 0600)
+	s.NoError(err)
+
+	detector := s.quartoMissingDetector(errors.New("exit status 1"))
+	configs, err := detector.InferType(base, util.RelativePath{})
+	s.NoError(err)
+	s.Len(configs, 0)
+}
+
+func (s *QuartoDetectorSuite) TestOutputDir() {
+	for dir, expected := range map[string]string{
+		"":           "",
+		"_site":      "_site",
+		"_book/":     "_book",
+		"docs/site":  "docs/site",
+		".":          "",
+		"../public":  "",
+		"/tmp/build": "",
+	} {
+		var output quartoInspectOutput
+		output.Project.Config.Project.OutputDir = dir
+		s.Equal(expected, output.outputDir(), dir)
+	}
+}
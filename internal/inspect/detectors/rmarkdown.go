@@ -54,14 +54,6 @@ func (d *RMarkdownDetector) getRmdMetadata(rmdContent string) (*RMarkdownMetadat
 	return &metadata, nil
 }
 
-func (d *RMarkdownDetector) getRmdFileMetadata(path util.AbsolutePath) (*RMarkdownMetadata, error) {
-	content, err := path.ReadFile()
-	if err != nil {
-		return nil, err
-	}
-	return d.getRmdMetadata(string(content))
-}
-
 func isShinyRmd(metadata *RMarkdownMetadata) bool {
 	if metadata == nil {
 		return false
@@ -102,7 +94,11 @@ func (d *RMarkdownDetector) InferType(base util.AbsolutePath, entrypoint util.Re
 			// Only inspect the specified file
 			continue
 		}
-		metadata, err := d.getRmdFileMetadata(entrypointPath)
+		content, err := entrypointPath.ReadFile()
+		if err != nil {
+			return nil, err
+		}
+		metadata, err := d.getRmdMetadata(string(content))
 		if err != nil {
 			d.log.Warn("Failed to read RMarkdown metadata", "path", entrypointPath, "error", err)
 			continue
@@ -126,15 +122,12 @@ func (d *RMarkdownDetector) InferType(base util.AbsolutePath, entrypoint util.Re
 				cfg.HasParameters = true
 			}
 		}
-		needsR, needsPython, err := pydeps.DetectMarkdownLanguages(base)
-		if err != nil {
-			return nil, err
-		}
-		if needsR {
-			// Indicate that R inspection is needed.
-			d.log.Info("RMarkdown: detected R code; configuration will include R")
-			cfg.R = &config.R{}
-		}
+		// R Markdown documents are always rendered by R (knitr),
+		// even if all of the code chunks are in another language.
+		// Indicate that R inspection is needed.
+		cfg.R = &config.R{}
+
+		_, needsPython := pydeps.DetectMarkdownLanguagesInContent(content)
 		if needsPython {
 			// Indicate that Python inspection is needed.
 			d.log.Info("RMarkdown: detected Python code; configuration will include Python")
@@ -94,10 +94,66 @@ func (s *RMarkdownSuite) TestInferTypeWithPython() {
 		Entrypoint: filename,
 		Validate:   true,
 		Files:      []string{},
+		R:          &config.R{},
 		Python:     &config.Python{},
 	}, configs[0])
 }
 
+var outputRmdContent = `---
+title: Plain Report
+output: html_document
+---
+
+# A Report Without Code
+`
+
+var outputShinyRmdContent = `---
+title: Interactive Report
+output:
+  html_document:
+    toc: true
+runtime: shiny
+---
+
+# A Very Interactive Report
+`
+
+func (s *RMarkdownSuite) TestInferTypeOutput() {
+	base := util.NewAbsolutePath("/project", afero.NewMemMapFs())
+	err := base.MkdirAll(0777)
+	s.NoError(err)
+
+	err = base.Join("plain.Rmd").WriteFile([]byte(outputRmdContent), 0600)
+	s.Nil(err)
+	err = base.Join("shiny.Rmd").WriteFile([]byte(outputShinyRmdContent), 0600)
+	s.Nil(err)
+
+	detector := NewRMarkdownDetector(logging.New())
+	configs, err := detector.InferType(base, util.RelativePath{})
+	s.Nil(err)
+	s.Len(configs, 2)
+
+	// R is needed to render the documents even without any R code.
+	s.Equal(&config.Config{
+		Schema:     schema.ConfigSchemaURL,
+		Type:       config.ContentTypeRMarkdown,
+		Title:      "Plain Report",
+		Entrypoint: "plain.Rmd",
+		Validate:   true,
+		Files:      []string{},
+		R:          &config.R{},
+	}, configs[0])
+	s.Equal(&config.Config{
+		Schema:     schema.ConfigSchemaURL,
+		Type:       config.ContentTypeRMarkdownShiny,
+		Title:      "Interactive Report",
+		Entrypoint: "shiny.Rmd",
+		Validate:   true,
+		Files:      []string{},
+		R:          &config.R{},
+	}, configs[1])
+}
+
 var parameterizedRmdContent = fmt.Sprintf(`---
 title: Special Report
 params:
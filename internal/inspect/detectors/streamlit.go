@@ -0,0 +1,77 @@
+package detectors
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+
+	"github.com/posit-dev/publisher/internal/config"
+	"github.com/posit-dev/publisher/internal/util"
+)
+
+// streamlitPagesDir is the directory, next to the main script,
+// where Streamlit looks for the additional pages of a multipage app.
+const streamlitPagesDir = "pages"
+
+var streamlitTitleREs = []*regexp.Regexp{
+	// st.set_page_config(page_title="My App", layout="wide")
+	regexp.MustCompile(`set_page_config\([^)]*\bpage_title\s*=\s*` + titleArgPattern),
+}
+
+type streamlitDetector struct {
+	*PythonAppDetector
+}
+
+func NewStreamlitDetector() *streamlitDetector {
+	d := NewPythonAppDetector(config.ContentTypePythonStreamlit, []string{
+		"streamlit",
+	})
+	d.titleREs = streamlitTitleREs
+	return &streamlitDetector{
+		PythonAppDetector: d,
+	}
+}
+
+// hasStreamlitPages returns true if the base directory contains
+// a pages directory with at least one Python script.
+func hasStreamlitPages(base util.AbsolutePath) (bool, error) {
+	pagesDir := base.Join(streamlitPagesDir)
+	exists, err := pagesDir.DirExists()
+	if err != nil || !exists {
+		return false, err
+	}
+	pages, err := pagesDir.Glob("*.py")
+	if err != nil {
+		return false, err
+	}
+	return len(pages) != 0, nil
+}
+
+func (d *streamlitDetector) InferType(base util.AbsolutePath, entrypoint util.RelativePath) ([]*config.Config, error) {
+	configs, err := d.PythonAppDetector.InferType(base, entrypoint)
+	if err != nil || len(configs) == 0 {
+		return configs, err
+	}
+	var appConfigs []*config.Config
+	for _, cfg := range configs {
+		appDir := path.Dir(cfg.Entrypoint)
+		if path.Base(appDir) == streamlitPagesDir {
+			// Scripts in a pages directory (found when searching
+			// subdirectories) are pages of an app, not entrypoints.
+			continue
+		}
+		isMultipage, err := hasStreamlitPages(base.Join(appDir))
+		if err != nil {
+			return nil, err
+		}
+		if isMultipage {
+			cfg.Files = append(cfg.Files,
+				fmt.Sprint("/", cfg.Entrypoint),
+				fmt.Sprint("/", path.Join(appDir, streamlitPagesDir), "/"))
+		}
+		appConfigs = append(appConfigs, cfg)
+	}
+	return appConfigs, nil
+}
@@ -0,0 +1,146 @@
+package detectors
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"testing"
+
+	"github.com/posit-dev/publisher/internal/config"
+	"github.com/posit-dev/publisher/internal/schema"
+	"github.com/posit-dev/publisher/internal/util"
+	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/suite"
+)
+
+type StreamlitSuite struct {
+	utiltest.Suite
+}
+
+func TestStreamlitSuite(t *testing.T) {
+	suite.Run(t, new(StreamlitSuite))
+}
+
+func (s *StreamlitSuite) TestInferTypeSinglePage() {
+	base := util.NewAbsolutePath("/project", afero.NewMemMapFs())
+	err := base.MkdirAll(0777)
+	s.NoError(err)
+
+	err = base.Join("app.py").WriteFile([]byte("import streamlit as st\nst.write('hi')\n"), 0600)
+	s.NoError(err)
+
+	detector := NewStreamlitDetector()
+	configs, err := detector.InferType(base, util.RelativePath{})
+	s.NoError(err)
+	s.Len(configs, 1)
+
+	s.Equal(&config.Config{
+		Schema:     schema.ConfigSchemaURL,
+		Type:       config.ContentTypePythonStreamlit,
+		Entrypoint: "app.py",
+		Validate:   true,
+		Files:      []string{},
+		Python:     &config.Python{},
+	}, configs[0])
+}
+
+func (s *StreamlitSuite) TestInferTypeMultipage() {
+	base := util.NewAbsolutePath("/project", afero.NewMemMapFs())
+	pagesDir := base.Join("pages")
+	err := pagesDir.MkdirAll(0777)
+	s.NoError(err)
+
+	err = base.Join("Home.py").WriteFile([]byte("import streamlit as st\nst.write('home')\n"), 0600)
+	s.NoError(err)
+	err = pagesDir.Join("1_Plots.py").WriteFile([]byte("import streamlit as st\nst.write('plots')\n"), 0600)
+	s.NoError(err)
+	err = pagesDir.Join("2_Data.py").WriteFile([]byte("import streamlit as st\nst.write('data')\n"), 0600)
+	s.NoError(err)
+
+	detector := NewStreamlitDetector()
+	configs, err := detector.InferType(base, util.RelativePath{})
+	s.NoError(err)
+	s.Len(configs, 1)
+
+	s.Equal(&config.Config{
+		Schema:     schema.ConfigSchemaURL,
+		Type:       config.ContentTypePythonStreamlit,
+		Entrypoint: "Home.py",
+		Validate:   true,
+		Files:      []string{"/Home.py", "/pages/"},
+		Python:     &config.Python{},
+	}, configs[0])
+}
+
+func (s *StreamlitSuite) TestInferTypePagesDirWithoutScripts() {
+	base := util.NewAbsolutePath("/project", afero.NewMemMapFs())
+	pagesDir := base.Join("pages")
+	err := pagesDir.MkdirAll(0777)
+	s.NoError(err)
+
+	err = base.Join("app.py").WriteFile([]byte("import streamlit as st\n"), 0600)
+	s.NoError(err)
+	err = pagesDir.Join("index.html").WriteFile([]byte("<html></html>\n"), 0600)
+	s.NoError(err)
+
+	detector := NewStreamlitDetector()
+	configs, err := detector.InferType(base, util.RelativePath{})
+	s.NoError(err)
+	s.Len(configs, 1)
+	s.Equal("app.py", configs[0].Entrypoint)
+	s.Equal([]string{}, configs[0].Files)
+}
+
+func (s *StreamlitSuite) TestInferTypeMultipageNotStreamlit() {
+	base := util.NewAbsolutePath("/project", afero.NewMemMapFs())
+	pagesDir := base.Join("pages")
+	err := pagesDir.MkdirAll(0777)
+	s.NoError(err)
+
+	err = base.Join("app.py").WriteFile([]byte("import flask\n"), 0600)
+	s.NoError(err)
+	err = pagesDir.Join("page.py").WriteFile([]byte("import streamlit as st\n"), 0600)
+	s.NoError(err)
+
+	detector := NewStreamlitDetector()
+	configs, err := detector.InferType(base, util.RelativePath{})
+	s.NoError(err)
+	s.Len(configs, 0)
+}
+
+func (s *StreamlitSuite) TestInferTypeTitle() {
+	base := util.NewAbsolutePath("/project", afero.NewMemMapFs())
+	err := base.MkdirAll(0777)
+	s.NoError(err)
+
+	err = base.Join("app.py").WriteFile([]byte(
+		"import streamlit as st\n"+
+			"st.set_page_config(\n"+
+			"    layout=\"wide\",\n"+
+			"    page_title='Sales Dashboard',\n"+
+			")\n"), 0600)
+	s.NoError(err)
+
+	detector := NewStreamlitDetector()
+	configs, err := detector.InferType(base, util.RelativePath{})
+	s.NoError(err)
+	s.Len(configs, 1)
+	s.Equal("Sales Dashboard", configs[0].Title)
+}
+
+func (s *StreamlitSuite) TestInferTypeInvalidTitle() {
+	base := util.NewAbsolutePath("/project", afero.NewMemMapFs())
+	err := base.MkdirAll(0777)
+	s.NoError(err)
+
+	err = base.Join("app.py").WriteFile([]byte(
+		"import streamlit as st\n"+
+			"st.set_page_config(page_title=\"X\")\n"), 0600)
+	s.NoError(err)
+
+	detector := NewStreamlitDetector()
+	configs, err := detector.InferType(base, util.RelativePath{})
+	s.NoError(err)
+	s.Len(configs, 1)
+	s.Equal("", configs[0].Title)
+}
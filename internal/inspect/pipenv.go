@@ -0,0 +1,86 @@
+package inspect
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/posit-dev/publisher/internal/util"
+)
+
+const PipfileFilename = "Pipfile"
+const PipfileLockFilename = "Pipfile.lock"
+
+type pipfileLock struct {
+	Default map[string]pipfileLockPackage `json:"default"`
+}
+
+type pipfileLockPackage struct {
+	Version string   `json:"version"`
+	Extras  []string `json:"extras"`
+	Markers string   `json:"markers"`
+	Git     string   `json:"git"`
+	Ref     string   `json:"ref"`
+	File    string   `json:"file"`
+	Path    string   `json:"path"`
+}
+
+// ReadPipenvRequirements returns the packages locked in the project's
+// Pipfile.lock file as requirements file lines, like `pipenv requirements`.
+// Only the default packages are included; the develop packages, such as
+// test tools, are left out. So are packages installed from local paths,
+// which won't exist on the server.
+func ReadPipenvRequirements(base util.AbsolutePath) ([]string, error) {
+	content, err := base.Join(PipfileLockFilename).ReadFile()
+	if err != nil {
+		return nil, err
+	}
+	var lock pipfileLock
+	err = json.Unmarshal(content, &lock)
+	if err != nil {
+		return nil, fmt.Errorf("can't parse %s: %w", PipfileLockFilename, err)
+	}
+	names := make([]string, 0, len(lock.Default))
+	for name := range lock.Default {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	reqs := []string{}
+	for _, name := range names {
+		if req, ok := lock.Default[name].requirement(name); ok {
+			reqs = append(reqs, req)
+		}
+	}
+	return reqs, nil
+}
+
+func (pkg pipfileLockPackage) requirement(name string) (string, bool) {
+	if len(pkg.Extras) != 0 {
+		name = fmt.Sprintf("%s[%s]", name, strings.Join(pkg.Extras, ","))
+	}
+	var req string
+	switch {
+	case pkg.Git != "":
+		req = fmt.Sprintf("%s @ git+%s", name, pkg.Git)
+		if pkg.Ref != "" {
+			req += "@" + pkg.Ref
+		}
+	case pkg.File != "":
+		req = fmt.Sprintf("%s @ %s", name, pkg.File)
+	case pkg.Path != "":
+		return "", false
+	case pkg.Version == "" || pkg.Version == "*":
+		req = name
+	default:
+		// Locked versions include the operator, as in "==3.0.3".
+		req = name + pkg.Version
+	}
+	if pkg.Markers != "" {
+		req += "; " + pkg.Markers
+	}
+	return req, true
+}
@@ -0,0 +1,155 @@
+package inspect
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"testing"
+
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/util"
+	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/suite"
+)
+
+type PipenvSuite struct {
+	utiltest.Suite
+	testdata util.AbsolutePath
+	base     util.AbsolutePath
+}
+
+func TestPipenvSuite(t *testing.T) {
+	suite.Run(t, new(PipenvSuite))
+}
+
+func (s *PipenvSuite) SetupTest() {
+	cwd, err := util.Getwd(nil)
+	s.NoError(err)
+	s.testdata = cwd.Join("testdata")
+
+	s.base = util.NewAbsolutePath(cwd.String(), afero.NewMemMapFs())
+	s.NoError(s.base.MkdirAll(0700))
+}
+
+// copyFixture copies the pipenv_project files into the base directory.
+func (s *PipenvSuite) copyFixture(names ...string) {
+	for _, name := range names {
+		content, err := s.testdata.Join("pipenv_project", name).ReadFile()
+		s.NoError(err)
+		s.NoError(s.base.Join(name).WriteFile(content, 0600))
+	}
+}
+
+var pipenvFixtureRequirements = []string{
+	"flask==3.0.3; python_version >= '3.8'",
+	"mylib @ git+https://github.com/example/mylib.git@4f1e2a3b5c6d7e8f90a1b2c3d4e5f60718293a4b",
+	"requests[socks]==2.32.3",
+	"werkzeug==3.0.3",
+}
+
+func (s *PipenvSuite) TestReadPipenvRequirements() {
+	reqs, err := ReadPipenvRequirements(s.testdata.Join("pipenv_project"))
+	s.NoError(err)
+	// The develop packages, pytest and iniconfig, are left out.
+	s.Equal(pipenvFixtureRequirements, reqs)
+}
+
+func (s *PipenvSuite) TestReadPipenvRequirementsInvalid() {
+	s.NoError(s.base.Join(PipfileLockFilename).WriteFile([]byte("{"), 0600))
+	_, err := ReadPipenvRequirements(s.base)
+	s.ErrorContains(err, "can't parse Pipfile.lock")
+}
+
+func (s *PipenvSuite) newInspector() *defaultPythonInspector {
+	return NewPythonInspector(s.base, util.Path{}, logging.New()).(*defaultPythonInspector)
+}
+
+func (s *PipenvSuite) TestInspectPackageManagerPipenv() {
+	s.copyFixture(PipfileFilename, PipfileLockFilename)
+	inspector := s.newInspector()
+
+	packageManager, err := inspector.inspectPackageManager()
+	s.NoError(err)
+	s.Equal("pipenv", packageManager)
+
+	// Requirements are exported when publishing, not while inspecting.
+	exists, err := s.base.Join(PythonRequirementsFilename).Exists()
+	s.NoError(err)
+	s.False(exists)
+}
+
+func (s *PipenvSuite) TestInspectPackageManagerPreviouslyExported() {
+	s.copyFixture(PipfileFilename, PipfileLockFilename)
+	exported := []byte("# exported from old.lock by Posit Publisher\nflask==2.0.0\n")
+	s.NoError(s.base.Join(PythonRequirementsFilename).WriteFile(exported, 0600))
+	inspector := s.newInspector()
+
+	packageManager, err := inspector.inspectPackageManager()
+	s.NoError(err)
+	s.Equal("pipenv", packageManager)
+
+	content, err := s.base.Join(PythonRequirementsFilename).ReadFile()
+	s.NoError(err)
+	s.Equal(exported, content)
+}
+
+func (s *PipenvSuite) TestInspectPackageManagerRequirementsFirst() {
+	s.copyFixture(PipfileFilename, PipfileLockFilename)
+	requirements := []byte("flask\n")
+	s.NoError(s.base.Join(PythonRequirementsFilename).WriteFile(requirements, 0600))
+	inspector := s.newInspector()
+
+	packageManager, err := inspector.inspectPackageManager()
+	s.NoError(err)
+	s.Equal("pip", packageManager)
+
+	content, err := s.base.Join(PythonRequirementsFilename).ReadFile()
+	s.NoError(err)
+	s.Equal(requirements, content)
+}
+
+func (s *PipenvSuite) TestInspectPackageManagerNoLockfile() {
+	s.copyFixture(PipfileFilename)
+	inspector := s.newInspector()
+
+	packageManager, err := inspector.inspectPackageManager()
+	s.NoError(err)
+	s.Equal("pip", packageManager)
+
+	exists, err := s.base.Join(PythonRequirementsFilename).Exists()
+	s.NoError(err)
+	s.False(exists)
+}
+
+func (s *PipenvSuite) TestExportRequirements() {
+	s.copyFixture(PipfileLockFilename)
+	dest := s.base.Join(PythonRequirementsFilename)
+
+	contents, exported, err := ExportRequirements("pipenv", s.base)
+	s.NoError(err)
+	s.True(exported)
+	s.NoError(dest.WriteFile(contents, 0600))
+	isExport, err := IsExportedRequirements(dest)
+	s.NoError(err)
+	s.True(isExport)
+	reqs, err := s.newInspector().ReadRequirementsFile(dest)
+	s.NoError(err)
+	s.Equal(pipenvFixtureRequirements, reqs)
+
+	contents, exported, err = ExportRequirements("pip", s.base)
+	s.NoError(err)
+	s.False(exported)
+	s.Nil(contents)
+}
+
+func (s *PipenvSuite) TestReadLockedRequirements() {
+	s.copyFixture(PipfileLockFilename)
+	reqs, ok, err := ReadLockedRequirements("pipenv", s.base)
+	s.NoError(err)
+	s.True(ok)
+	s.Equal(pipenvFixtureRequirements, reqs)
+
+	_, ok, err = ReadLockedRequirements("pip", s.base)
+	s.NoError(err)
+	s.False(ok)
+}
@@ -0,0 +1,208 @@
+package inspect
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"slices"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/posit-dev/publisher/internal/util"
+)
+
+const PoetryLockFilename = "poetry.lock"
+const PyprojectFilename = "pyproject.toml"
+
+type pyprojectFile struct {
+	Project struct {
+		Dependencies []string `toml:"dependencies"`
+	} `toml:"project"`
+	Tool struct {
+		Poetry *struct {
+			Dependencies map[string]any `toml:"dependencies"`
+		} `toml:"poetry"`
+	} `toml:"tool"`
+}
+
+type poetryLockFile struct {
+	Package []poetryPackage `toml:"package"`
+}
+
+type poetryPackage struct {
+	Name         string         `toml:"name"`
+	Version      string         `toml:"version"`
+	Category     string         `toml:"category"`
+	Groups       []string       `toml:"groups"`
+	Dependencies map[string]any `toml:"dependencies"`
+	Markers      any            `toml:"markers"` // A marker, or a marker for each group
+	Source       struct {
+		Type              string `toml:"type"`
+		URL               string `toml:"url"`
+		ResolvedReference string `toml:"resolved_reference"`
+	} `toml:"source"`
+}
+
+// IsPoetryProject returns true if the project's packages are managed
+// by Poetry: it has a poetry.lock file, or a pyproject.toml file
+// with a [tool.poetry] table.
+func IsPoetryProject(base util.AbsolutePath) (bool, error) {
+	exists, err := base.Join(PoetryLockFilename).Exists()
+	if err != nil || exists {
+		return exists, err
+	}
+	pyproject, err := readPyproject(base)
+	if err != nil || pyproject == nil {
+		return false, err
+	}
+	return pyproject.Tool.Poetry != nil, nil
+}
+
+// readPyproject reads the project's pyproject.toml file.
+// It returns nil if there isn't one.
+func readPyproject(base util.AbsolutePath) (*pyprojectFile, error) {
+	content, err := base.Join(PyprojectFilename).ReadFile()
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	pyproject := &pyprojectFile{}
+	err = toml.Unmarshal(content, pyproject)
+	if err != nil {
+		return nil, fmt.Errorf("can't parse %s: %w", PyprojectFilename, err)
+	}
+	return pyproject, nil
+}
+
+// ReadPoetryRequirements returns the packages locked in the project's
+// poetry.lock file as requirements file lines, like `poetry export`.
+// Packages that are only needed for development, such as test tools,
+// are left out, as are packages installed from local paths, which
+// won't exist on the server.
+func ReadPoetryRequirements(base util.AbsolutePath) ([]string, error) {
+	content, err := base.Join(PoetryLockFilename).ReadFile()
+	if err != nil {
+		return nil, err
+	}
+	lock := poetryLockFile{}
+	err = toml.Unmarshal(content, &lock)
+	if err != nil {
+		return nil, fmt.Errorf("can't parse %s: %w", PoetryLockFilename, err)
+	}
+	pyproject, err := readPyproject(base)
+	if err != nil {
+		return nil, err
+	}
+	main := mainPoetryPackages(lock.Package, pyproject)
+	reqs := []string{}
+	for _, pkg := range lock.Package {
+		if !main[normalizePackageName(pkg.Name)] {
+			continue
+		}
+		if req, ok := pkg.requirement(); ok {
+			reqs = append(reqs, req)
+		}
+	}
+	return reqs, nil
+}
+
+func (pkg *poetryPackage) requirement() (string, bool) {
+	var req string
+	switch pkg.Source.Type {
+	case "git":
+		req = fmt.Sprintf("%s @ git+%s@%s", pkg.Name, pkg.Source.URL, pkg.Source.ResolvedReference)
+	case "url":
+		req = fmt.Sprintf("%s @ %s", pkg.Name, pkg.Source.URL)
+	case "directory", "file":
+		return "", false
+	default:
+		req = fmt.Sprintf("%s==%s", pkg.Name, pkg.Version)
+	}
+	if marker := pkg.mainMarker(); marker != "" {
+		req += " ; " + marker
+	}
+	return req, true
+}
+
+// mainMarker returns the environment marker, such as
+// `sys_platform == "win32"`, that limits where the package is
+// installed for the main group. Lock files from Poetry 2 record
+// markers as a string, or as a table with a marker for each group.
+// Older lock files don't record them.
+func (pkg *poetryPackage) mainMarker() string {
+	switch markers := pkg.Markers.(type) {
+	case string:
+		return markers
+	case map[string]any:
+		if marker, ok := markers["main"].(string); ok {
+			return marker
+		}
+	}
+	return ""
+}
+
+// dependencyNameRE matches the package name at the start of a
+// pyproject.toml dependency, e.g. `flask` in `flask (>=3.0,<4.0)`.
+var dependencyNameRE = regexp.MustCompile(`^\s*([A-Za-z0-9][A-Za-z0-9._-]*)`)
+
+// mainPoetryPackages returns the normalized names of the locked
+// packages that are needed to run the project. Depending on the
+// Poetry version, lock files record each package's category or its
+// groups. If they record neither, the packages are found by following
+// dependencies from the ones listed in pyproject.toml.
+func mainPoetryPackages(packages []poetryPackage, pyproject *pyprojectFile) map[string]bool {
+	main := make(map[string]bool, len(packages))
+	byName := make(map[string]*poetryPackage, len(packages))
+	recorded := false
+	for i := range packages {
+		pkg := &packages[i]
+		name := normalizePackageName(pkg.Name)
+		byName[name] = pkg
+		if pkg.Groups != nil || pkg.Category != "" {
+			recorded = true
+			if slices.Contains(pkg.Groups, "main") || pkg.Category == "main" {
+				main[name] = true
+			}
+		}
+	}
+	if recorded {
+		return main
+	}
+	if pyproject == nil {
+		// Without pyproject.toml, there's no way to tell.
+		for name := range byName {
+			main[name] = true
+		}
+		return main
+	}
+	pending := []string{}
+	if pyproject.Tool.Poetry != nil {
+		for name := range pyproject.Tool.Poetry.Dependencies {
+			if name != "python" {
+				pending = append(pending, name)
+			}
+		}
+	}
+	for _, dep := range pyproject.Project.Dependencies {
+		if m := dependencyNameRE.FindStringSubmatch(dep); m != nil {
+			pending = append(pending, m[1])
+		}
+	}
+	for len(pending) != 0 {
+		name := normalizePackageName(pending[0])
+		pending = pending[1:]
+		pkg, ok := byName[name]
+		if !ok || main[name] {
+			continue
+		}
+		main[name] = true
+		for dep := range pkg.Dependencies {
+			pending = append(pending, dep)
+		}
+	}
+	return main
+}
@@ -0,0 +1,250 @@
+package inspect
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"testing"
+
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/util"
+	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/suite"
+)
+
+type PoetrySuite struct {
+	utiltest.Suite
+	testdata util.AbsolutePath
+	base     util.AbsolutePath
+}
+
+func TestPoetrySuite(t *testing.T) {
+	suite.Run(t, new(PoetrySuite))
+}
+
+func (s *PoetrySuite) SetupTest() {
+	cwd, err := util.Getwd(nil)
+	s.NoError(err)
+	s.testdata = cwd.Join("testdata")
+
+	s.base = util.NewAbsolutePath(cwd.String(), afero.NewMemMapFs())
+	s.NoError(s.base.MkdirAll(0700))
+}
+
+// copyFixture copies the poetry_project files into the base directory.
+func (s *PoetrySuite) copyFixture(names ...string) {
+	for _, name := range names {
+		content, err := s.testdata.Join("poetry_project", name).ReadFile()
+		s.NoError(err)
+		s.NoError(s.base.Join(name).WriteFile(content, 0600))
+	}
+}
+
+var poetryFixtureRequirements = []string{
+	"blinker==1.8.2",
+	"flask==3.0.3",
+	"markupsafe==2.1.5",
+	"mylib @ git+https://github.com/example/mylib.git@4f1e2a3b5c6d7e8f90a1b2c3d4e5f60718293a4b",
+	"werkzeug==3.0.3",
+}
+
+func (s *PoetrySuite) TestReadPoetryRequirements() {
+	// The lock file doesn't record groups, so the dev dependencies
+	// are found from pyproject.toml.
+	reqs, err := ReadPoetryRequirements(s.testdata.Join("poetry_project"))
+	s.NoError(err)
+	s.Equal(poetryFixtureRequirements, reqs)
+}
+
+func (s *PoetrySuite) TestReadPoetryRequirementsNoPyproject() {
+	s.copyFixture(PoetryLockFilename)
+	reqs, err := ReadPoetryRequirements(s.base)
+	s.NoError(err)
+	s.Contains(reqs, "pytest==8.2.2")
+	s.Contains(reqs, "iniconfig==2.0.0")
+	s.Contains(reqs, "flask==3.0.3")
+}
+
+func (s *PoetrySuite) TestReadPoetryRequirementsGroups() {
+	lock := `
+[[package]]
+name = "flask"
+version = "3.0.3"
+groups = ["main"]
+
+[[package]]
+name = "pytest"
+version = "8.2.2"
+groups = ["dev"]
+
+[[package]]
+name = "requests"
+version = "2.32.3"
+groups = ["main", "dev"]
+`
+	s.NoError(s.base.Join(PoetryLockFilename).WriteFile([]byte(lock), 0600))
+	reqs, err := ReadPoetryRequirements(s.base)
+	s.NoError(err)
+	s.Equal([]string{"flask==3.0.3", "requests==2.32.3"}, reqs)
+}
+
+func (s *PoetrySuite) TestReadPoetryRequirementsMarkers() {
+	lock := `
+[[package]]
+name = "flask"
+version = "3.0.3"
+groups = ["main"]
+
+[[package]]
+name = "pywin32"
+version = "306"
+groups = ["main"]
+markers = "sys_platform == \"win32\""
+
+[[package]]
+name = "colorama"
+version = "0.4.6"
+groups = ["main", "dev"]
+markers = {main = "platform_system == \"Windows\"", dev = "sys_platform == \"win32\""}
+
+[[package]]
+name = "mylib"
+version = "1.0.0"
+groups = ["main"]
+markers = "python_version < \"3.12\""
+
+[package.source]
+type = "url"
+url = "https://example.com/mylib-1.0.0.tar.gz"
+`
+	s.NoError(s.base.Join(PoetryLockFilename).WriteFile([]byte(lock), 0600))
+	reqs, err := ReadPoetryRequirements(s.base)
+	s.NoError(err)
+	s.Equal([]string{
+		"flask==3.0.3",
+		`pywin32==306 ; sys_platform == "win32"`,
+		`colorama==0.4.6 ; platform_system == "Windows"`,
+		`mylib @ https://example.com/mylib-1.0.0.tar.gz ; python_version < "3.12"`,
+	}, reqs)
+}
+
+func (s *PoetrySuite) TestReadPoetryRequirementsCategory() {
+	lock := `
+[[package]]
+name = "flask"
+version = "3.0.3"
+category = "main"
+
+[[package]]
+name = "pytest"
+version = "8.2.2"
+category = "dev"
+`
+	s.NoError(s.base.Join(PoetryLockFilename).WriteFile([]byte(lock), 0600))
+	reqs, err := ReadPoetryRequirements(s.base)
+	s.NoError(err)
+	s.Equal([]string{"flask==3.0.3"}, reqs)
+}
+
+func (s *PoetrySuite) TestReadPoetryRequirementsProjectDependencies() {
+	// Poetry 2 uses the standard [project] table.
+	pyproject := `
+[project]
+name = "myapp"
+dependencies = ["flask (>=3.0.0,<4.0.0)"]
+`
+	s.copyFixture(PoetryLockFilename)
+	s.NoError(s.base.Join(PyprojectFilename).WriteFile([]byte(pyproject), 0600))
+	reqs, err := ReadPoetryRequirements(s.base)
+	s.NoError(err)
+	s.Equal([]string{"blinker==1.8.2", "flask==3.0.3", "markupsafe==2.1.5", "werkzeug==3.0.3"}, reqs)
+}
+
+func (s *PoetrySuite) TestReadPoetryRequirementsInvalid() {
+	s.NoError(s.base.Join(PoetryLockFilename).WriteFile([]byte("[[package"), 0600))
+	_, err := ReadPoetryRequirements(s.base)
+	s.ErrorContains(err, "can't parse poetry.lock")
+}
+
+func (s *PoetrySuite) TestIsPoetryProject() {
+	isPoetry, err := IsPoetryProject(s.base)
+	s.NoError(err)
+	s.False(isPoetry)
+
+	s.NoError(s.base.Join(PyprojectFilename).WriteFile([]byte("[project]\nname = \"myapp\"\n"), 0600))
+	isPoetry, err = IsPoetryProject(s.base)
+	s.NoError(err)
+	s.False(isPoetry)
+
+	s.copyFixture(PyprojectFilename)
+	isPoetry, err = IsPoetryProject(s.base)
+	s.NoError(err)
+	s.True(isPoetry)
+
+	s.NoError(s.base.Join(PyprojectFilename).Remove())
+	s.copyFixture(PoetryLockFilename)
+	isPoetry, err = IsPoetryProject(s.base)
+	s.NoError(err)
+	s.True(isPoetry)
+}
+
+func (s *PoetrySuite) newInspector() *defaultPythonInspector {
+	return NewPythonInspector(s.base, util.Path{}, logging.New()).(*defaultPythonInspector)
+}
+
+func (s *PoetrySuite) TestInspectPackageManagerPoetry() {
+	s.copyFixture(PyprojectFilename, PoetryLockFilename)
+	inspector := s.newInspector()
+
+	packageManager, err := inspector.inspectPackageManager()
+	s.NoError(err)
+	s.Equal("poetry", packageManager)
+
+	// Requirements are exported when publishing, not while inspecting.
+	exists, err := s.base.Join(PythonRequirementsFilename).Exists()
+	s.NoError(err)
+	s.False(exists)
+}
+
+func (s *PoetrySuite) TestInspectPackageManagerPreviouslyExported() {
+	s.copyFixture(PyprojectFilename, PoetryLockFilename)
+	exported := []byte("# exported from old.lock by Posit Publisher\nflask==2.0.0\n")
+	s.NoError(s.base.Join(PythonRequirementsFilename).WriteFile(exported, 0600))
+	inspector := s.newInspector()
+
+	packageManager, err := inspector.inspectPackageManager()
+	s.NoError(err)
+	s.Equal("poetry", packageManager)
+
+	content, err := s.base.Join(PythonRequirementsFilename).ReadFile()
+	s.NoError(err)
+	s.Equal(exported, content)
+}
+
+func (s *PoetrySuite) TestInspectPackageManagerRequirementsFirst() {
+	s.copyFixture(PyprojectFilename, PoetryLockFilename)
+	requirements := []byte("flask\n")
+	s.NoError(s.base.Join(PythonRequirementsFilename).WriteFile(requirements, 0600))
+	inspector := s.newInspector()
+
+	packageManager, err := inspector.inspectPackageManager()
+	s.NoError(err)
+	s.Equal("pip", packageManager)
+
+	content, err := s.base.Join(PythonRequirementsFilename).ReadFile()
+	s.NoError(err)
+	s.Equal(requirements, content)
+}
+
+func (s *PoetrySuite) TestInspectPackageManagerNoLockfile() {
+	s.copyFixture(PyprojectFilename)
+	inspector := s.newInspector()
+
+	packageManager, err := inspector.inspectPackageManager()
+	s.NoError(err)
+	s.Equal("pip", packageManager)
+
+	exists, err := s.base.Join(PythonRequirementsFilename).Exists()
+	s.NoError(err)
+	s.False(exists)
+}
@@ -9,8 +9,10 @@ import (
 	"os"
 	"os/exec"
 	"regexp"
+	"runtime"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/posit-dev/publisher/internal/config"
 	"github.com/posit-dev/publisher/internal/executor"
@@ -24,7 +26,23 @@ type PythonInspector interface {
 	InspectPython() (*config.Python, error)
 	ReadRequirementsFile(path util.AbsolutePath) ([]string, error)
 	WriteRequirementsFile(dest util.AbsolutePath, reqs []string) error
-	ScanRequirements(base util.AbsolutePath) ([]string, []string, string, error)
+	ScanRequirements(base util.AbsolutePath, packageFile string, deadline time.Time) (*RequirementsScan, error)
+}
+
+// RequirementsScan is the result of scanning a project
+// for the Python packages it imports.
+type RequirementsScan struct {
+	// Requirements lists the imported packages, with versions if known.
+	Requirements []string
+	// Incomplete lists packages whose installed version was not found.
+	Incomplete []string
+	// Unused lists packages in the project's existing requirements
+	// file that are not imported anywhere.
+	Unused []string
+	// Python is the Python executable that was used.
+	Python string
+	// Partial is true if the deadline passed before the scan finished.
+	Partial bool
 }
 
 type defaultPythonInspector struct {
@@ -46,7 +64,7 @@ func NewPythonInspector(base util.AbsolutePath, pythonPath util.Path, log loggin
 	return &defaultPythonInspector{
 		executor:   executor.NewExecutor(),
 		pathLooker: util.NewPathLooker(),
-		scanner:    pydeps.NewDependencyScanner(log),
+		scanner:    pydeps.NewDefaultScannerRegistry(log),
 		base:       base,
 		pythonPath: pythonPath,
 		log:        log,
@@ -55,7 +73,9 @@ func NewPythonInspector(base util.AbsolutePath, pythonPath util.Path, log loggin
 
 // InspectPython inspects the specified project directory,
 // returning a Python configuration.
-// If requirements.txt does not exist, it will be created.
+// If requirements.txt does not exist and the project uses Poetry
+// or Pipenv, the package manager is poetry or pipenv; requirements
+// are exported from poetry.lock or Pipfile.lock when publishing.
 // The python version (and packages if needed) will
 // be determined by the specified pythonExecutable,
 // or by `python3` or `python` on $PATH.
@@ -77,14 +97,14 @@ func (i *defaultPythonInspector) InspectPython() (*config.Python, error) {
 	if err != nil {
 		return nil, err
 	}
-	err = i.warnIfNoRequirementsFile()
+	packageManager, err := i.inspectPackageManager()
 	if err != nil {
 		return nil, err
 	}
 	return &config.Python{
 		Version:        pythonVersion,
 		PackageFile:    PythonRequirementsFilename,
-		PackageManager: "pip",
+		PackageManager: packageManager,
 	}, nil
 }
 
@@ -122,6 +142,7 @@ func (i *defaultPythonInspector) getPythonExecutable() (string, error) {
 	// Find the executable on PATH
 	var path string
 	var err error
+	foundStoreStub := false
 
 	i.log.Info("Looking for Python on PATH", "PATH", os.Getenv("PATH"))
 	for _, executableName := range executableNames {
@@ -135,9 +156,30 @@ func (i *defaultPythonInspector) getPythonExecutable() (string, error) {
 			if err == nil {
 				return path, nil
 			}
+			if isWindowsStoreStub(err) {
+				i.log.Debug("Skipping Microsoft Store Python alias", "path", path)
+				foundStoreStub = true
+			}
+		}
+	}
+
+	if runtime.GOOS == "windows" {
+		// python.org installers include the `py` launcher,
+		// which is not affected by app execution aliases.
+		launcherPath, launcherErr := i.getPythonFromLauncher()
+		if launcherErr == nil {
+			return launcherPath, nil
 		}
+		i.log.Debug("Could not find Python using the py launcher", "error", launcherErr.Error())
 	}
 
+	if foundStoreStub {
+		stubErr := fmt.Errorf(
+			"%w; the Python on PATH is the Microsoft Store app execution alias, not a Python installation. "+
+				"Install Python, or turn off the python app execution aliases in Windows Settings",
+			err)
+		return "", types.NewAgentError(types.ErrorPythonExecNotFound, stubErr, nil)
+	}
 	if errors.Is(err, exec.ErrNotFound) {
 		return "", types.NewAgentError(types.ErrorPythonExecNotFound, err, nil)
 	}
@@ -145,6 +187,43 @@ func (i *defaultPythonInspector) getPythonExecutable() (string, error) {
 	return "", err
 }
 
+// windowsStoreStubExitCode is the exit status of the Microsoft Store
+// `python` and `python3` app execution aliases when the Store version
+// of Python is not installed.
+const windowsStoreStubExitCode = 9009
+
+// isWindowsStoreStub returns true if running Python exited with the
+// status of the Store alias. The executor returns an *exec.ExitError.
+func isWindowsStoreStub(err error) bool {
+	var exitErr interface{ ExitCode() int }
+	return errors.As(err, &exitErr) && exitErr.ExitCode() == windowsStoreStubExitCode
+}
+
+// getPythonFromLauncher uses the Windows `py` launcher to find
+// the path to the default Python 3 interpreter.
+func (i *defaultPythonInspector) getPythonFromLauncher() (string, error) {
+	launcher, err := i.pathLooker.LookPath("py")
+	if err != nil {
+		return "", err
+	}
+	args := []string{
+		`-3`, // select the default Python 3 interpreter
+		`-E`, // ignore python-specific environment variables
+		`-c`, // execute the next argument as python code
+		`import sys; print(sys.executable)`,
+	}
+	output, _, err := i.executor.RunCommand(launcher, args, i.base, i.log)
+	if err != nil {
+		return "", err
+	}
+	pythonExecutable := strings.TrimSpace(string(output))
+	err = i.validatePythonExecutable(pythonExecutable)
+	if err != nil {
+		return "", err
+	}
+	return pythonExecutable, nil
+}
+
 func (i *defaultPythonInspector) getPythonVersion(pythonExecutable string) (string, error) {
 	if version, ok := pythonVersionCache[pythonExecutable]; ok {
 		return version, nil
@@ -170,20 +249,72 @@ func (i *defaultPythonInspector) getPythonVersion(pythonExecutable string) (stri
 	return version, nil
 }
 
-func (i *defaultPythonInspector) warnIfNoRequirementsFile() error {
-	requirementsFilename := i.base.Join("requirements.txt")
+// inspectPackageManager returns the package manager for the project.
+// An existing requirements.txt file is used with pip. Otherwise,
+// Poetry and Pipenv projects use their lock files. Inspection
+// doesn't write any files into the project.
+func (i *defaultPythonInspector) inspectPackageManager() (string, error) {
+	requirementsFilename := i.base.Join(PythonRequirementsFilename)
 	exists, err := requirementsFilename.Exists()
 	if err != nil {
-		return err
+		return "", err
 	}
 	if exists {
+		exported, err := IsExportedRequirements(requirementsFilename)
+		if err != nil {
+			return "", err
+		}
+		if !exported {
+			i.log.Info("Using Python packages", "source", requirementsFilename)
+			return "pip", nil
+		}
+	}
+	for _, exporter := range lockfileExporters {
+		lockFilename := i.base.Join(exporter.lockFilename)
+		lockExists, err := lockFilename.Exists()
+		if err != nil {
+			return "", err
+		}
+		if !lockExists {
+			continue
+		}
+		i.log.Info("Using Python packages", "source", lockFilename)
+		return exporter.packageManager, nil
+	}
+	isPoetry, err := IsPoetryProject(i.base)
+	if err != nil {
+		return "", err
+	}
+	isPipenv, err := i.base.Join(PipfileFilename).Exists()
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case isPoetry:
+		i.log.Warn("can't find poetry.lock; run `poetry lock` to create it")
+	case isPipenv:
+		i.log.Warn("can't find Pipfile.lock; run `pipenv lock` to create it")
+	case exists:
 		i.log.Info("Using Python packages", "source", requirementsFilename)
-	} else {
+	default:
 		i.log.Warn("can't find requirements.txt")
 	}
-	return nil
+	return "pip", nil
 }
 
+// indexDirectiveRE matches requirements file lines that tell pip where
+// to find packages, such as `--index-url https://pypi.example.com/simple`.
+var indexDirectiveRE = regexp.MustCompile(`^\s*(--index-url|--extra-index-url|--find-links|-i|-f)(\s|=|$)`)
+
+// IsIndexDirective returns true if the requirements file line is
+// an index or find-links directive rather than a package.
+func IsIndexDirective(line string) bool {
+	return indexDirectiveRE.MatchString(line)
+}
+
+// ReadRequirementsFile returns the non-comment lines of a requirements
+// file. Lines are returned unchanged, including pip directives such as
+// `--index-url`; use IsIndexDirective to tell them apart from packages.
 func (i *defaultPythonInspector) ReadRequirementsFile(path util.AbsolutePath) ([]string, error) {
 	content, err := path.ReadFile()
 	if err != nil {
@@ -197,20 +328,24 @@ func (i *defaultPythonInspector) ReadRequirementsFile(path util.AbsolutePath) ([
 	return lines, nil
 }
 
-func (i *defaultPythonInspector) ScanRequirements(base util.AbsolutePath) ([]string, []string, string, error) {
+// ScanRequirements scans the project for imported packages.
+// If packageFile is not empty, the packages it lists that aren't
+// imported are reported as Unused. If the deadline (which may be zero for none) passes before the
+// scan finishes, it returns the packages found so far, marked Partial.
+func (i *defaultPythonInspector) ScanRequirements(base util.AbsolutePath, packageFile string, deadline time.Time) (*RequirementsScan, error) {
 	oldWD, err := util.Chdir(base.String())
 	if err != nil {
-		return nil, nil, "", err
+		return nil, err
 	}
 	defer util.Chdir(oldWD)
 
 	pythonExecutable, err := i.getPythonExecutable()
 	if err != nil {
-		return nil, nil, "", err
+		return nil, err
 	}
-	specs, err := i.scanner.ScanDependencies(base, pythonExecutable)
+	specs, partial, err := i.scanner.ScanDependencies(base, pythonExecutable, deadline)
 	if err != nil {
-		return nil, nil, "", err
+		return nil, err
 	}
 	reqs := make([]string, 0, len(specs))
 	incomplete := []string{}
@@ -221,16 +356,85 @@ func (i *defaultPythonInspector) ScanRequirements(base util.AbsolutePath) ([]str
 			incomplete = append(incomplete, string(spec.Name))
 		}
 	}
-	return reqs, incomplete, pythonExecutable, nil
+	unused := []string{}
+	if packageFile != "" && !partial {
+		// A partial scan would report packages it didn't get to as unused.
+		unused, err = i.findUnusedRequirements(base.Join(packageFile), specs)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &RequirementsScan{
+		Requirements: reqs,
+		Incomplete:   incomplete,
+		Unused:       unused,
+		Python:       pythonExecutable,
+		Partial:      partial,
+	}, nil
+}
+
+// requirementNameRE matches the package name at the start of a
+// requirements.txt line, e.g. `pandas` in `pandas[excel]>=2.0`.
+// Options (`-r other.txt`), paths, and URLs do not match.
+var requirementNameRE = regexp.MustCompile(`^\s*([A-Za-z0-9][A-Za-z0-9._-]*)\s*($|[\[<>=!~;@#])`)
+
+var packageNameSeparatorRE = regexp.MustCompile(`[-_.]+`)
+
+// normalizePackageName returns the normalized form of a package name,
+// under which e.g. `Typing_Extensions` and `typing-extensions` are equal.
+// See https://packaging.python.org/en/latest/specifications/name-normalization/
+func normalizePackageName(name string) string {
+	return strings.ToLower(packageNameSeparatorRE.ReplaceAllString(name, "-"))
+}
+
+// findUnusedRequirements returns the names of packages listed
+// in the requirements file at path that are not in specs.
+// These are only informational; they may still be needed
+// indirectly, for example as a dependency of another package.
+func (i *defaultPythonInspector) findUnusedRequirements(path util.AbsolutePath, specs []*pydeps.PackageSpec) ([]string, error) {
+	unused := []string{}
+	exists, err := path.Exists()
+	if err != nil || !exists {
+		return unused, err
+	}
+	lines, err := i.ReadRequirementsFile(path)
+	if err != nil {
+		return nil, err
+	}
+	used := make(map[string]bool, len(specs))
+	for _, spec := range specs {
+		used[normalizePackageName(string(spec.Name))] = true
+	}
+	for _, line := range lines {
+		m := requirementNameRE.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		if !used[normalizePackageName(m[1])] {
+			unused = append(unused, m[1])
+		}
+	}
+	return unused, nil
 }
 
+// WriteRequirementsFile writes reqs to the dest requirements file.
+// Index directives in an existing dest file are kept,
+// so packages continue to be installed from the same index.
 func (i *defaultPythonInspector) WriteRequirementsFile(dest util.AbsolutePath, reqs []string) error {
 	pythonExecutable, err := i.getPythonExecutable()
 	if err != nil {
 		return err
 	}
+	directives, err := i.readIndexDirectives(dest)
+	if err != nil {
+		return err
+	}
+	directives = slices.DeleteFunc(directives, func(d string) bool {
+		return slices.Contains(reqs, d)
+	})
+	lines := append(directives, reqs...)
 	autogenComment := fmt.Sprintf("# requirements.txt auto-generated by Posit Publisher\n# using %s\n", pythonExecutable)
-	contents := autogenComment + strings.Join(reqs, "\n") + "\n"
+	contents := autogenComment + strings.Join(lines, "\n") + "\n"
 
 	err = dest.WriteFile([]byte(contents), 0666)
 	if err != nil {
@@ -238,3 +442,23 @@ func (i *defaultPythonInspector) WriteRequirementsFile(dest util.AbsolutePath, r
 	}
 	return nil
 }
+
+// readIndexDirectives returns the index directives in the
+// requirements file at path, if it exists.
+func (i *defaultPythonInspector) readIndexDirectives(path util.AbsolutePath) ([]string, error) {
+	exists, err := path.Exists()
+	if err != nil || !exists {
+		return nil, err
+	}
+	lines, err := i.ReadRequirementsFile(path)
+	if err != nil {
+		return nil, err
+	}
+	directives := []string{}
+	for _, line := range lines {
+		if IsIndexDirective(line) {
+			directives = append(directives, strings.TrimSpace(line))
+		}
+	}
+	return directives, nil
+}
@@ -3,6 +3,8 @@ package inspect
 // Copyright (C) 2023 by Posit Software, PBC.
 
 import (
+	"time"
+
 	"github.com/posit-dev/publisher/internal/config"
 	"github.com/posit-dev/publisher/internal/util"
 	"github.com/stretchr/testify/mock"
@@ -41,19 +43,12 @@ func (m *MockPythonInspector) WriteRequirementsFile(base util.AbsolutePath, reqs
 	return args.Error(0)
 }
 
-func (m *MockPythonInspector) ScanRequirements(base util.AbsolutePath) ([]string, []string, string, error) {
-	args := m.Called()
-
-	reqs := args.Get(0)
-	var reqsStrings []string
-	if reqs != nil {
-		reqsStrings = reqs.([]string)
-	}
-
-	incomplete := args.Get(1)
-	var incompleteStrings []string
-	if incomplete != nil {
-		incompleteStrings = incomplete.([]string)
+func (m *MockPythonInspector) ScanRequirements(base util.AbsolutePath, packageFile string, deadline time.Time) (*RequirementsScan, error) {
+	args := m.Called(base, packageFile, deadline)
+	result := args.Get(0)
+	if result == nil {
+		return nil, args.Error(1)
+	} else {
+		return result.(*RequirementsScan), args.Error(1)
 	}
-	return reqsStrings, incompleteStrings, args.String(2), args.Error(3)
 }
@@ -4,9 +4,12 @@ package inspect
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"os/exec"
+	"runtime"
 	"testing"
+	"time"
 
 	"github.com/posit-dev/publisher/internal/executor/executortest"
 	"github.com/posit-dev/publisher/internal/inspect/dependencies/pydeps"
@@ -19,6 +22,20 @@ import (
 	"github.com/stretchr/testify/suite"
 )
 
+// exitError is like the *exec.ExitError for
+// a command that exited with the given status.
+type exitError struct {
+	code int
+}
+
+func (e exitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.code)
+}
+
+func (e exitError) ExitCode() int {
+	return e.code
+}
+
 type PythonSuite struct {
 	utiltest.Suite
 	cwd util.AbsolutePath
@@ -44,6 +61,7 @@ func (s *PythonSuite) TestNewPythonInspector() {
 	i := NewPythonInspector(s.cwd, pythonPath, log)
 	inspector := i.(*defaultPythonInspector)
 	s.Equal(pythonPath, inspector.pythonPath)
+	s.IsType(&pydeps.ScannerRegistry{}, inspector.scanner)
 	s.Equal(log, inspector.log)
 }
 
@@ -56,7 +74,8 @@ func (s *PythonSuite) TestGetPythonVersionFromExecutable() {
 	inspector := i.(*defaultPythonInspector)
 
 	executor := executortest.NewMockExecutor()
-	executor.On("RunCommand", pythonPath.String(), mock.Anything, mock.Anything, mock.Anything).Return([]byte("3.10.4"), nil, nil)
+	// Python runs in the project directory.
+	executor.On("RunCommand", pythonPath.String(), mock.Anything, s.cwd, mock.Anything).Return([]byte("3.10.4"), nil, nil)
 	inspector.executor = executor
 	version, err := inspector.getPythonVersion(pythonPath.String())
 	s.NoError(err)
@@ -106,7 +125,7 @@ func (s *PythonSuite) TestGetPythonExecutablePython3NotRunnable() {
 	// python exists and is runnable
 	log := logging.New()
 	executor := executortest.NewMockExecutor()
-	testError := errors.New("exit status 9009")
+	testError := exitError{windowsStoreStubExitCode}
 	executor.On("RunCommand", "/some/python3", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil, testError)
 	executor.On("RunCommand", "/some/python", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil, nil)
 
@@ -129,7 +148,7 @@ func (s *PythonSuite) TestGetPythonExecutableNoRunnablePython() {
 	// python exists but is not runnable
 	log := logging.New()
 	executor := executortest.NewMockExecutor()
-	testError := errors.New("exit status 9009")
+	testError := exitError{windowsStoreStubExitCode}
 	executor.On("RunCommand", "/some/python3", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil, testError)
 	executor.On("RunCommand", "/some/python", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil, testError)
 
@@ -141,11 +160,95 @@ func (s *PythonSuite) TestGetPythonExecutableNoRunnablePython() {
 	pathLooker := util.NewMockPathLooker()
 	pathLooker.On("LookPath", "python3").Return("/some/python3", nil)
 	pathLooker.On("LookPath", "python").Return("/some/python", nil)
+	pathLooker.On("LookPath", "py").Return("", exec.ErrNotFound).Maybe()
 	i.pathLooker = pathLooker
 	executable, err := i.getPythonExecutable()
-	s.NotNil(err)
-	s.ErrorIs(err, testError)
+	_, ok := types.IsAgentErrorOf(err, types.ErrorPythonExecNotFound)
+	s.True(ok)
 	s.ErrorContains(err, "could not run python executable")
+	s.ErrorContains(err, "Microsoft Store app execution alias")
+	s.Equal("", executable)
+}
+
+func (s *PythonSuite) TestGetPythonExecutableNotRunnableNotStoreStub() {
+	// python3 and python exist but fail for some other reason
+	log := logging.New()
+	executor := executortest.NewMockExecutor()
+	testError := exitError{1}
+	executor.On("RunCommand", "/some/python3", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil, testError)
+	executor.On("RunCommand", "/some/python", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil, testError)
+
+	i := &defaultPythonInspector{
+		executor: executor,
+		log:      log,
+	}
+
+	pathLooker := util.NewMockPathLooker()
+	pathLooker.On("LookPath", "python3").Return("/some/python3", nil)
+	pathLooker.On("LookPath", "python").Return("/some/python", nil)
+	pathLooker.On("LookPath", "py").Return("", exec.ErrNotFound).Maybe()
+	i.pathLooker = pathLooker
+	_, err := i.getPythonExecutable()
+	s.ErrorIs(err, testError)
+	s.NotContains(err.Error(), "Microsoft Store")
+}
+
+func (s *PythonSuite) TestGetPythonExecutablePyLauncher() {
+	if runtime.GOOS != "windows" {
+		s.T().Skip("The py launcher is only used on Windows")
+	}
+	// python3 and python are Store aliases
+	// py launcher exists and points to a runnable python
+	log := logging.New()
+	executor := executortest.NewMockExecutor()
+	stubError := exitError{windowsStoreStubExitCode}
+	realPython := `C:\Python312\python.exe`
+	executor.On("RunCommand", "/some/python3", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil, stubError)
+	executor.On("RunCommand", "/some/python", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil, stubError)
+	executor.On("RunCommand", "/some/py", mock.Anything, mock.Anything, mock.Anything).Return([]byte(realPython+"\r\n"), nil, nil)
+	executor.On("RunCommand", realPython, mock.Anything, mock.Anything, mock.Anything).Return([]byte("3.12.1"), nil, nil)
+
+	i := &defaultPythonInspector{
+		executor: executor,
+		log:      log,
+	}
+
+	pathLooker := util.NewMockPathLooker()
+	pathLooker.On("LookPath", "python3").Return("/some/python3", nil)
+	pathLooker.On("LookPath", "python").Return("/some/python", nil)
+	pathLooker.On("LookPath", "py").Return("/some/py", nil)
+	i.pathLooker = pathLooker
+	executable, err := i.getPythonExecutable()
+	s.NoError(err)
+	s.Equal(realPython, executable)
+	executor.AssertExpectations(s.T())
+}
+
+func (s *PythonSuite) TestGetPythonExecutablePyLauncherNotRunnable() {
+	if runtime.GOOS != "windows" {
+		s.T().Skip("The py launcher is only used on Windows")
+	}
+	// python3 is a Store alias, python is not found,
+	// and the py launcher has no Python 3 installed.
+	log := logging.New()
+	executor := executortest.NewMockExecutor()
+	stubError := exitError{windowsStoreStubExitCode}
+	launcherError := errors.New("exit status 103")
+	executor.On("RunCommand", "/some/python3", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil, stubError)
+	executor.On("RunCommand", "/some/py", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil, launcherError)
+
+	i := &defaultPythonInspector{
+		executor: executor,
+		log:      log,
+	}
+
+	pathLooker := util.NewMockPathLooker()
+	pathLooker.On("LookPath", "python3").Return("/some/python3", nil)
+	pathLooker.On("LookPath", "python").Return("", exec.ErrNotFound)
+	pathLooker.On("LookPath", "py").Return("/some/py", nil)
+	i.pathLooker = pathLooker
+	executable, err := i.getPythonExecutable()
+	s.ErrorContains(err, "Microsoft Store app execution alias")
 	s.Equal("", executable)
 }
 
@@ -162,22 +265,112 @@ func (s *PythonSuite) TestScanRequirements() {
 		{Name: "numpy", Version: "1.26.1"},
 		{Name: "pandas", Version: ""},
 	}
-	scanner.On("ScanDependencies", s.cwd, pythonPath.String()).Return(specs, nil)
+	scanner.On("ScanDependencies", s.cwd, pythonPath.String(), time.Time{}).Return(specs, false, nil)
 	inspector.scanner = scanner
 
-	reqs, incomplete, python, err := inspector.ScanRequirements(s.cwd)
+	scan, err := inspector.ScanRequirements(s.cwd, PythonRequirementsFilename, time.Time{})
 	s.NoError(err)
-	s.Equal([]string{
-		"numpy==1.26.1",
-		"pandas",
-	}, reqs)
-	s.Equal([]string{
-		"pandas",
-	}, incomplete)
-	s.Equal(pythonPath.String(), python)
+	s.Equal(&RequirementsScan{
+		Requirements: []string{
+			"numpy==1.26.1",
+			"pandas",
+		},
+		Incomplete: []string{
+			"pandas",
+		},
+		Unused:  []string{},
+		Python:  pythonPath.String(),
+		Partial: false,
+	}, scan)
 	scanner.AssertExpectations(s.T())
 }
 
+func (s *PythonSuite) TestScanRequirementsPartial() {
+	pythonPath := s.cwd.Join("bin", "python3")
+	pythonPath.Dir().MkdirAll(0777)
+	pythonPath.WriteFile(nil, 0777)
+	log := logging.New()
+	i := NewPythonInspector(s.cwd, pythonPath.Path, log)
+	inspector := i.(*defaultPythonInspector)
+
+	err := s.cwd.Join("requirements.txt").WriteFile([]byte("numpy\nscipy\n"), 0666)
+	s.NoError(err)
+
+	scanner := pydeps.NewMockDependencyScanner()
+	specs := []*pydeps.PackageSpec{
+		{Name: "numpy", Version: ""},
+	}
+	deadline := time.Now().Add(time.Second)
+	scanner.On("ScanDependencies", s.cwd, pythonPath.String(), deadline).Return(specs, true, nil)
+	inspector.scanner = scanner
+
+	scan, err := inspector.ScanRequirements(s.cwd, PythonRequirementsFilename, deadline)
+	s.NoError(err)
+	s.True(scan.Partial)
+	s.Equal([]string{"numpy"}, scan.Requirements)
+	s.Equal([]string{"numpy"}, scan.Incomplete)
+	// scipy may be imported by a file that wasn't scanned
+	s.Equal([]string{}, scan.Unused)
+}
+
+func (s *PythonSuite) TestScanRequirementsUnused() {
+	pythonPath := s.cwd.Join("bin", "python3")
+	pythonPath.Dir().MkdirAll(0777)
+	pythonPath.WriteFile(nil, 0777)
+	log := logging.New()
+	i := NewPythonInspector(s.cwd, pythonPath.Path, log)
+	inspector := i.(*defaultPythonInspector)
+
+	requirements := "# comment\n" +
+		"-r other-requirements.txt\n" +
+		"--index-url https://pypi.example.com/simple\n" +
+		"numpy==1.26.1\n" +
+		"Typing_Extensions>=4.0\r\n" +
+		"requests[socks] ; python_version >= '3.8'\n" +
+		"git+https://github.com/example/pkg.git\n" +
+		"scipy\n"
+	err := s.cwd.Join("requirements.txt").WriteFile([]byte(requirements), 0666)
+	s.NoError(err)
+
+	scanner := pydeps.NewMockDependencyScanner()
+	specs := []*pydeps.PackageSpec{
+		{Name: "numpy", Version: "1.26.1"},
+		{Name: "typing-extensions", Version: "4.9.0"},
+	}
+	scanner.On("ScanDependencies", s.cwd, pythonPath.String(), time.Time{}).Return(specs, false, nil)
+	inspector.scanner = scanner
+
+	scan, err := inspector.ScanRequirements(s.cwd, PythonRequirementsFilename, time.Time{})
+	s.NoError(err)
+	s.Equal([]string{
+		"requests",
+		"scipy",
+	}, scan.Unused)
+}
+
+func (s *PythonSuite) TestScanRequirementsUnusedNotRequested() {
+	pythonPath := s.cwd.Join("bin", "python3")
+	pythonPath.Dir().MkdirAll(0777)
+	pythonPath.WriteFile(nil, 0777)
+	log := logging.New()
+	i := NewPythonInspector(s.cwd, pythonPath.Path, log)
+	inspector := i.(*defaultPythonInspector)
+
+	err := s.cwd.Join("requirements.txt").WriteFile([]byte("numpy\nscipy\n"), 0666)
+	s.NoError(err)
+
+	scanner := pydeps.NewMockDependencyScanner()
+	specs := []*pydeps.PackageSpec{
+		{Name: "numpy", Version: "1.26.1"},
+	}
+	scanner.On("ScanDependencies", s.cwd, pythonPath.String(), time.Time{}).Return(specs, false, nil)
+	inspector.scanner = scanner
+
+	scan, err := inspector.ScanRequirements(s.cwd, "", time.Time{})
+	s.NoError(err)
+	s.Equal([]string{}, scan.Unused)
+}
+
 func (s *PythonSuite) TestReadRequirementsFile() {
 	log := logging.New()
 	i := NewPythonInspector(s.cwd, util.Path{}, log)
@@ -193,6 +386,81 @@ func (s *PythonSuite) TestReadRequirementsFile() {
 	}, reqs)
 }
 
+func (s *PythonSuite) TestIsIndexDirective() {
+	s.True(IsIndexDirective("--index-url https://pypi.example.com/simple"))
+	s.True(IsIndexDirective("--index-url=https://pypi.example.com/simple"))
+	s.True(IsIndexDirective("  --extra-index-url https://pypi.example.com/simple"))
+	s.True(IsIndexDirective("--find-links ./wheels"))
+	s.True(IsIndexDirective("-i https://pypi.example.com/simple"))
+	s.True(IsIndexDirective("-f ./wheels"))
+
+	s.False(IsIndexDirective("numpy==1.26.1"))
+	s.False(IsIndexDirective("-r other-requirements.txt"))
+	s.False(IsIndexDirective("--index-urls"))
+	s.False(IsIndexDirective("# --index-url https://pypi.example.com/simple"))
+}
+
+func (s *PythonSuite) makeRequirementsInspector() *defaultPythonInspector {
+	pythonPath := s.cwd.Join("bin", "python3")
+	pythonPath.Dir().MkdirAll(0777)
+	pythonPath.WriteFile(nil, 0777)
+	i := NewPythonInspector(s.cwd, pythonPath.Path, logging.New())
+	return i.(*defaultPythonInspector)
+}
+
+func (s *PythonSuite) TestRequirementsIndexDirectivesRoundTrip() {
+	inspector := s.makeRequirementsInspector()
+
+	original := s.cwd.Join("requirements.txt")
+	err := original.WriteFile([]byte(
+		"--index-url https://pypi.example.com/simple\n"+
+			"--extra-index-url https://pypi.org/simple\n"+
+			"--find-links ./wheels\n"+
+			"numpy==1.26.1\n"+
+			"internal-pkg==0.3.0\n"), 0666)
+	s.NoError(err)
+
+	reqs, err := inspector.ReadRequirementsFile(original)
+	s.NoError(err)
+	s.Equal([]string{
+		"--index-url https://pypi.example.com/simple",
+		"--extra-index-url https://pypi.org/simple",
+		"--find-links ./wheels",
+		"numpy==1.26.1",
+		"internal-pkg==0.3.0",
+	}, reqs)
+
+	copied := s.cwd.Join("requirements-copy.txt")
+	err = inspector.WriteRequirementsFile(copied, reqs)
+	s.NoError(err)
+	reqs2, err := inspector.ReadRequirementsFile(copied)
+	s.NoError(err)
+	s.Equal(reqs, reqs2)
+}
+
+func (s *PythonSuite) TestWriteRequirementsFileKeepsIndexDirectives() {
+	inspector := s.makeRequirementsInspector()
+
+	dest := s.cwd.Join("requirements.txt")
+	err := dest.WriteFile([]byte(
+		"-i https://pypi.example.com/simple\n"+
+			"--extra-index-url https://pypi.org/simple\n"+
+			"oldpackage==1.0\n"), 0666)
+	s.NoError(err)
+
+	// Regenerating the file from scanned packages keeps the directives.
+	err = inspector.WriteRequirementsFile(dest, []string{"numpy==1.26.1", "pandas"})
+	s.NoError(err)
+	reqs, err := inspector.ReadRequirementsFile(dest)
+	s.NoError(err)
+	s.Equal([]string{
+		"-i https://pypi.example.com/simple",
+		"--extra-index-url https://pypi.org/simple",
+		"numpy==1.26.1",
+		"pandas",
+	}, reqs)
+}
+
 func (s *PythonSuite) TestInspectPython_SpecifiedPathNotFound() {
 	log := logging.New()
 	pathLooker := util.NewMockPathLooker()
@@ -224,6 +492,7 @@ func (s *PythonSuite) TestInspectPython_NotFoundInPATH() {
 	// Won't find any exec names
 	pathLooker.On("LookPath", "python3").Return("", exec.ErrNotFound)
 	pathLooker.On("LookPath", "python").Return("", exec.ErrNotFound)
+	pathLooker.On("LookPath", "py").Return("", exec.ErrNotFound).Maybe()
 	_, err := inspector.InspectPython()
 	s.NotNil(err)
 
@@ -188,7 +188,7 @@ func (i *defaultRInspector) getRVersion(rExecutable string) (string, error) {
 	}
 	i.log.Info("Getting R version", "r", rExecutable)
 	args := []string{"--version"}
-	output, stderr, err := i.executor.RunCommand(rExecutable, args, util.AbsolutePath{}, i.log)
+	output, stderr, err := i.executor.RunCommand(rExecutable, args, i.base, i.log)
 	if err != nil {
 		return "", err
 	}
@@ -119,7 +119,8 @@ func (s *RSuite) TestGetRVersionFromExecutable() {
 		inspector := i.(*defaultRInspector)
 
 		executor := executortest.NewMockExecutor()
-		executor.On("RunCommand", rPath.String(), []string{"--version"}, mock.Anything, mock.Anything).Return([]byte(tc.output), nil, nil)
+		// R runs in the project directory.
+		executor.On("RunCommand", rPath.String(), []string{"--version"}, s.cwd, mock.Anything).Return([]byte(tc.output), nil, nil)
 		inspector.executor = executor
 		version, err := inspector.getRVersion(rPath.String())
 		s.NoError(err)
@@ -0,0 +1,84 @@
+package inspect
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"bufio"
+	"strings"
+
+	"github.com/posit-dev/publisher/internal/util"
+)
+
+// lockfileExporter reads the requirements from the lock file
+// of a package manager that pip can't read directly.
+type lockfileExporter struct {
+	packageManager string
+	lockFilename   string
+	read           func(base util.AbsolutePath) ([]string, error)
+}
+
+// lockfileExporters are tried in order when a
+// project doesn't have its own requirements file.
+var lockfileExporters = []lockfileExporter{
+	{"poetry", PoetryLockFilename, ReadPoetryRequirements},
+	{"pipenv", PipfileLockFilename, ReadPipenvRequirements},
+}
+
+func findLockfileExporter(packageManager string) (lockfileExporter, bool) {
+	for _, exporter := range lockfileExporters {
+		if exporter.packageManager == packageManager {
+			return exporter, true
+		}
+	}
+	return lockfileExporter{}, false
+}
+
+// ReadLockedRequirements returns the requirements from the lock file
+// of the package manager, and true. It returns false if requirements
+// aren't exported for the package manager, like pip.
+func ReadLockedRequirements(packageManager string, base util.AbsolutePath) ([]string, bool, error) {
+	exporter, ok := findLockfileExporter(packageManager)
+	if !ok {
+		return nil, false, nil
+	}
+	reqs, err := exporter.read(base)
+	if err != nil {
+		return nil, false, err
+	}
+	return reqs, true, nil
+}
+
+// ExportRequirements returns the contents of a requirements file
+// listing the packages in the lock file of the package manager,
+// so they can be installed with pip, and true. It returns false
+// if requirements aren't exported for the package manager, like pip.
+func ExportRequirements(packageManager string, base util.AbsolutePath) ([]byte, bool, error) {
+	reqs, ok, err := ReadLockedRequirements(packageManager, base)
+	if err != nil || !ok {
+		return nil, ok, err
+	}
+	exporter, _ := findLockfileExporter(packageManager)
+	contents := exportCommentPrefix + exporter.lockFilename + exportCommentSuffix + "\n" + strings.Join(reqs, "\n") + "\n"
+	return []byte(contents), true, nil
+}
+
+// Exported requirements files start with this comment,
+// so they can be told apart from ones the user wrote.
+const exportCommentPrefix = "# exported from "
+const exportCommentSuffix = " by Posit Publisher"
+
+// IsExportedRequirements returns true if the requirements
+// file was exported from a lock file.
+func IsExportedRequirements(path util.AbsolutePath) (bool, error) {
+	f, err := path.Open()
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() {
+		return false, scanner.Err()
+	}
+	line := strings.TrimSpace(scanner.Text())
+	return strings.HasPrefix(line, exportCommentPrefix) && strings.HasSuffix(line, exportCommentSuffix), nil
+}
@@ -16,9 +16,14 @@ import (
 	"github.com/posit-dev/publisher/internal/util"
 )
 
+// largestFilesCount is the number of files reported in the
+// largestFiles field of the createBundle success event.
+const largestFilesCount = 5
+
 type createBundleStartData struct{}
 type createBundleSuccessData struct {
-	Filename string `mapstructure:"filename"`
+	Filename     string             `mapstructure:"filename"`
+	LargestFiles []bundles.FileSize `mapstructure:"largestFiles"`
 }
 
 type uploadBundleStartData struct{}
@@ -52,9 +57,11 @@ func (p *defaultPublisher) createAndUploadBundle(
 	if err != nil {
 		return "", types.OperationError(op, err)
 	}
-	prepareLog.Info("Done preparing files", "filename", bundleFile.Name())
+	largestFiles := bundler.LargestFiles(largestFilesCount)
+	prepareLog.Info("Done preparing files", "filename", bundleFile.Name(), "largest_files", largestFiles)
 	p.emitter.Emit(events.New(op, events.SuccessPhase, events.NoError, createBundleSuccessData{
-		Filename: bundleFile.Name(),
+		Filename:     bundleFile.Name(),
+		LargestFiles: largestFiles,
 	}))
 
 	// Upload Bundle step
@@ -74,7 +81,34 @@ func (p *defaultPublisher) createAndUploadBundle(
 	p.Target.Files = manifest.GetFilenames()
 	p.Target.BundleID = bundleID
 	p.Target.BundleURL = util.GetBundleURL(p.Account.URL, contentID, bundleID)
+	p.Target.ContentHash = manifest.ContentHash()
+
+	if bundles.IsArchive(bundler) {
+		// The project directory may not have the packages that were
+		// bundled, so they aren't recorded for a prebuilt bundle.
+		p.Target.Requirements = nil
+		p.Target.Renv = nil
+	} else {
+		err = p.recordDependencies()
+		if err != nil {
+			return "", err
+		}
+	}
 
+	err = p.writeDeploymentRecord()
+	if err != nil {
+		return "", err
+	}
+	uploadLog.Info("Done uploading files", "bundle_id", bundleID)
+	p.emitter.Emit(events.New(op, events.SuccessPhase, events.NoError, uploadBundleSuccessData{
+		BundleID: bundleID,
+	}))
+	return bundleID, nil
+}
+
+// recordDependencies saves the Python requirements and renv lockfile
+// from the project directory in the deployment record.
+func (p *defaultPublisher) recordDependencies() error {
 	if p.Config.Python != nil {
 		filename := p.Config.Python.PackageFile
 		if filename == "" {
@@ -82,11 +116,16 @@ func (p *defaultPublisher) createAndUploadBundle(
 		}
 		p.log.Debug("Python configuration present", "filename", filename)
 
-		inspector := inspect.NewPythonInspector(p.Dir, util.Path{}, p.log)
-		requirements, err := inspector.ReadRequirementsFile(p.Dir.Join(filename))
+		// Exported requirements are in the bundle,
+		// not the project directory.
+		requirements, exported, err := inspect.ReadLockedRequirements(p.Config.Python.PackageManager, p.Dir)
+		if err == nil && !exported {
+			inspector := inspect.NewPythonInspector(p.Dir, util.Path{}, p.log)
+			requirements, err = inspector.ReadRequirementsFile(p.Dir.Join(filename))
+		}
 		p.log.Debug("Python requirements file in use", "requirements", requirements)
 		if err != nil {
-			return "", err
+			return err
 		}
 		p.Target.Requirements = requirements
 	}
@@ -99,19 +138,10 @@ func (p *defaultPublisher) createAndUploadBundle(
 		p.log.Debug("R configuration present", "filename", filename)
 		lockfile, err := renv.ReadLockfile(p.Dir.Join(filename))
 		if err != nil {
-			return "", err
+			return err
 		}
 		p.log.Debug("Renv lockfile in use", "lockfile", lockfile)
 		p.Target.Renv = lockfile
 	}
-
-	err = p.writeDeploymentRecord()
-	if err != nil {
-		return "", err
-	}
-	uploadLog.Info("Done uploading files", "bundle_id", bundleID)
-	p.emitter.Emit(events.New(op, events.SuccessPhase, events.NoError, uploadBundleSuccessData{
-		BundleID: bundleID,
-	}))
-	return bundleID, nil
+	return nil
 }
@@ -3,6 +3,8 @@ package publish
 // Copyright (C) 2023 by Posit Software, PBC.
 
 import (
+	"time"
+
 	"github.com/posit-dev/publisher/internal/clients/connect"
 	"github.com/posit-dev/publisher/internal/events"
 	"github.com/posit-dev/publisher/internal/logging"
@@ -36,3 +38,51 @@ func (p *defaultPublisher) deployBundle(
 	}))
 	return taskID, nil
 }
+
+// taskPositionSaveInterval limits how often the task position is
+// written to the deployment record while waiting for a task.
+const taskPositionSaveInterval = 5 * time.Second
+
+// waitForTask waits for the deployment task to finish. The position in
+// the task's output is saved in the deployment record as it progresses,
+// so the output isn't repeated if waiting resumes later. If the record
+// already has a position for this task, waiting resumes from there.
+func (p *defaultPublisher) waitForTask(client connect.APIClient, taskID types.TaskID) error {
+	taskLogger := p.log.WithArgs("source", "server.log")
+	position := types.TaskPosition{TaskID: taskID}
+	if p.Target.Task != nil && p.Target.Task.TaskID == taskID {
+		position = *p.Target.Task
+		p.log.Debug("Resuming task output", "task", taskID, "offset", position.Offset)
+	}
+	p.Target.Task = &position
+	p.saveTaskPosition()
+
+	lastSaved := time.Now()
+	onProgress := func(position types.TaskPosition) {
+		p.Target.Task = &position
+		if time.Since(lastSaved) >= taskPositionSaveInterval {
+			p.saveTaskPosition()
+			lastSaved = time.Now()
+		}
+	}
+	err := client.WaitForTask(position, onProgress, taskLogger)
+	if err != nil {
+		if agentErr, ok := err.(*types.AgentError); ok && agentErr.Code == events.DeploymentFailedCode {
+			// The task finished, so there is nothing to resume.
+			p.Target.Task = nil
+		}
+		// Save the latest position, which may not have been
+		// written yet, so waiting can resume from there.
+		p.saveTaskPosition()
+		return err
+	}
+	p.Target.Task = nil
+	return p.writeDeploymentRecord()
+}
+
+func (p *defaultPublisher) saveTaskPosition() {
+	err := p.writeDeploymentRecord()
+	if err != nil {
+		p.log.Warn("failed to write updated deployment record", "name", p.TargetName, "err", err)
+	}
+}
@@ -0,0 +1,74 @@
+package publish
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"io"
+
+	"github.com/posit-dev/publisher/internal/bundles"
+	"github.com/posit-dev/publisher/internal/config"
+	"github.com/posit-dev/publisher/internal/inspect/dependencies/renv"
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/util"
+)
+
+// newProjectBundler returns a bundler for the project in dir,
+// with the manifest metadata filled in from cfg.
+func newProjectBundler(
+	dir util.AbsolutePath,
+	cfg *config.Config,
+	symlinkPolicy util.SymlinkPolicy,
+	log logging.Logger) (bundles.Bundler, error) {
+
+	manifest := bundles.NewManifestFromConfig(cfg)
+	manifest.AddGitMetadata(dir, log)
+
+	if cfg.R != nil {
+		mapper := renv.NewPackageMapper(dir, util.Path{})
+		rPackages, err := readRPackages(dir, cfg.R, mapper, log)
+		if err != nil {
+			return nil, err
+		}
+		manifest.Packages = rPackages
+	}
+	return newDirectoryBundler(dir, cfg, manifest, symlinkPolicy, false, log)
+}
+
+// CreateManifest returns the manifest that deploying the project in
+// dir with cfg would send to the server. The project files are
+// scanned and checksummed, but no bundle is created.
+func CreateManifest(
+	dir util.AbsolutePath,
+	cfg *config.Config,
+	symlinkPolicy util.SymlinkPolicy,
+	log logging.Logger) (*bundles.Manifest, error) {
+
+	bundler, err := newProjectBundler(dir, cfg, symlinkPolicy, log)
+	if err != nil {
+		return nil, err
+	}
+	return bundler.CreateManifest()
+}
+
+// CreateSupportBundle writes an archive describing what deploying
+// the project in dir with cfg would send to the server: the manifest,
+// and the directories and files with their sizes. File contents
+// are not included, so the archive can be shared with support.
+func CreateSupportBundle(
+	dir util.AbsolutePath,
+	cfg *config.Config,
+	symlinkPolicy util.SymlinkPolicy,
+	dest io.Writer,
+	log logging.Logger) error {
+
+	bundler, err := newProjectBundler(dir, cfg, symlinkPolicy, log)
+	if err != nil {
+		return err
+	}
+	manifest, err := bundler.CreateManifest()
+	if err != nil {
+		return err
+	}
+	structure := bundles.NewSupportStructure(bundler.LargestFiles(-1), cfg.EmptyDirs)
+	return bundles.WriteSupportBundle(dest, manifest, structure)
+}
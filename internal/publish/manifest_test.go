@@ -0,0 +1,150 @@
+package publish
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/posit-dev/publisher/internal/bundles"
+
+	"github.com/posit-dev/publisher/internal/clients/connect"
+	"github.com/posit-dev/publisher/internal/config"
+	"github.com/posit-dev/publisher/internal/inspect"
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/util"
+	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/suite"
+)
+
+type CreateManifestSuite struct {
+	utiltest.Suite
+	cwd util.AbsolutePath
+}
+
+func TestCreateManifestSuite(t *testing.T) {
+	suite.Run(t, new(CreateManifestSuite))
+}
+
+func (s *CreateManifestSuite) SetupTest() {
+	fs := afero.NewMemMapFs()
+	cwd, err := util.Getwd(fs)
+	s.NoError(err)
+	s.cwd = cwd
+	s.cwd.MkdirAll(0700)
+}
+
+func (s *CreateManifestSuite) TestCreateManifest() {
+	s.NoError(s.cwd.Join("app.py").WriteFile([]byte("import flask\n"), 0600))
+	s.NoError(s.cwd.Join("requirements.txt").WriteFile([]byte("flask\n"), 0600))
+	s.NoError(s.cwd.Join("__pycache__").MkdirAll(0700))
+	s.NoError(s.cwd.Join("__pycache__", "app.pyc").WriteFile([]byte{0}, 0600))
+
+	cfg := config.New()
+	cfg.Type = config.ContentTypePythonFlask
+	cfg.Entrypoint = "app.py"
+	cfg.Python = &config.Python{
+		Version:        "3.11.3",
+		PackageManager: "pip",
+		PackageFile:    "requirements.txt",
+	}
+	manifest, err := CreateManifest(s.cwd, cfg, util.SymlinkFollow, logging.New())
+	s.NoError(err)
+
+	s.Equal([]string{"app.py", "requirements.txt"}, manifest.GetFilenames())
+	s.Equal(connect.AppModeFromType(config.ContentTypePythonFlask), manifest.Metadata.AppMode)
+	s.Equal("app.py", manifest.Metadata.Entrypoint)
+	s.Equal("3.11.3", manifest.Python.Version)
+	s.Equal("requirements.txt", manifest.Python.PackageManager.PackageFile)
+	s.NotEmpty(manifest.Files["app.py"].Checksum)
+
+	// The project is not bundled, so nothing else is written.
+	infos, err := s.cwd.ReadDir()
+	s.NoError(err)
+	s.Len(infos, 3)
+}
+
+func (s *CreateManifestSuite) TestCreateManifestExportedRequirements() {
+	s.NoError(s.cwd.Join("app.py").WriteFile([]byte("import flask\n"), 0600))
+	lock := "[[package]]\nname = \"flask\"\nversion = \"3.0.3\"\ngroups = [\"main\"]\n"
+	s.NoError(s.cwd.Join(inspect.PoetryLockFilename).WriteFile([]byte(lock), 0600))
+
+	cfg := config.New()
+	cfg.Type = config.ContentTypePythonFlask
+	cfg.Entrypoint = "app.py"
+	cfg.Python = &config.Python{
+		PackageManager: "poetry",
+		PackageFile:    "requirements.txt",
+	}
+	manifest, err := CreateManifest(s.cwd, cfg, util.SymlinkFollow, logging.New())
+	s.NoError(err)
+
+	// The manifest lists the same files as a deployment would.
+	s.Contains(manifest.GetFilenames(), "requirements.txt")
+	exists, err := s.cwd.Join("requirements.txt").Exists()
+	s.NoError(err)
+	s.False(exists)
+}
+
+func (s *CreateManifestSuite) TestCreateManifestMissingLockfile() {
+	cfg := config.New()
+	cfg.Type = config.ContentTypeRShiny
+	cfg.Entrypoint = "app.R"
+	cfg.R = &config.R{Version: "4.3.1"}
+	_, err := CreateManifest(s.cwd, cfg, util.SymlinkFollow, logging.New())
+	s.ErrorContains(err, "renv.lock")
+}
+
+func (s *CreateManifestSuite) TestCreateSupportBundle() {
+	secret := "SECRET_CONTENTS_DO_NOT_SHARE"
+	s.NoError(s.cwd.Join("static", "css").MkdirAll(0700))
+	s.NoError(s.cwd.Join("index.html").WriteFile([]byte("<html>"+secret+"</html>"), 0600))
+	s.NoError(s.cwd.Join("static", "css", "style.css").WriteFile([]byte(secret), 0600))
+
+	cfg := config.New()
+	cfg.Type = config.ContentTypeHTML
+	cfg.Entrypoint = "index.html"
+	cfg.EmptyDirs = []string{"logs"}
+
+	dest := new(bytes.Buffer)
+	err := CreateSupportBundle(s.cwd, cfg, util.SymlinkFollow, dest, logging.New())
+	s.NoError(err)
+
+	gz, err := gzip.NewReader(bytes.NewReader(dest.Bytes()))
+	s.NoError(err)
+	archive := tar.NewReader(gz)
+	contents := map[string][]byte{}
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+		s.NoError(err)
+		contents[header.Name], err = io.ReadAll(archive)
+		s.NoError(err)
+	}
+	s.Len(contents, 2)
+
+	manifest, err := bundles.ReadManifest(bytes.NewReader(contents[bundles.ManifestFilename]))
+	s.NoError(err)
+	s.Equal([]string{"index.html", "static/css/style.css"}, manifest.GetFilenames())
+
+	var structure bundles.SupportStructure
+	err = json.Unmarshal(contents[bundles.SupportStructureFilename], &structure)
+	s.NoError(err)
+	s.Equal([]string{"logs", "static", "static/css"}, structure.Directories)
+	s.Equal([]bundles.FileSize{
+		{Path: "index.html", Size: int64(len(secret) + 13)},
+		{Path: "static/css/style.css", Size: int64(len(secret))},
+	}, structure.Files)
+	s.Equal(int64(2*len(secret)+13), structure.TotalSize)
+
+	for _, content := range contents {
+		s.NotContains(string(content), secret)
+	}
+}
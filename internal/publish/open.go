@@ -0,0 +1,55 @@
+package publish
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"os"
+	"runtime"
+
+	"github.com/pkg/browser"
+	"github.com/posit-dev/publisher/internal/state"
+	"github.com/posit-dev/publisher/internal/util"
+)
+
+// ContentURL returns the URL to show the user after a successful
+// deployment. APIs don't have a page of their own, so for them it
+// is the dashboard URL; for other content, it is the direct URL.
+func ContentURL(s *state.State) string {
+	if s.Target == nil || s.Target.ID == "" || s.Account == nil {
+		return ""
+	}
+	if s.Config != nil && s.Config.Type.IsAPIContent() {
+		return util.GetDashboardURL(s.Account.URL, s.Target.ID)
+	}
+	return util.GetDirectURL(s.Account.URL, s.Target.ID)
+}
+
+// These are replaceable for testing.
+var openURL = browser.OpenURL
+var getenv = os.Getenv
+var goos = runtime.GOOS
+
+// canOpenBrowser reports whether there is a display to open a
+// browser on. macOS and Windows always have one; elsewhere,
+// there is none unless X or Wayland is available.
+func canOpenBrowser() bool {
+	switch goos {
+	case "darwin", "windows":
+		return true
+	default:
+		return getenv("DISPLAY") != "" || getenv("WAYLAND_DISPLAY") != ""
+	}
+}
+
+// OpenInBrowser opens the URL in the default browser. It does
+// nothing, and returns false, on headless systems.
+func OpenInBrowser(url string) (bool, error) {
+	if !canOpenBrowser() {
+		return false, nil
+	}
+	err := openURL(url)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,109 @@
+package publish
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"errors"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/pkg/browser"
+	"github.com/posit-dev/publisher/internal/accounts"
+	"github.com/posit-dev/publisher/internal/config"
+	"github.com/posit-dev/publisher/internal/deployment"
+	"github.com/posit-dev/publisher/internal/state"
+	"github.com/posit-dev/publisher/internal/types"
+	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/stretchr/testify/suite"
+)
+
+type OpenSuite struct {
+	utiltest.Suite
+	env    map[string]string
+	opened []string
+}
+
+func TestOpenSuite(t *testing.T) {
+	suite.Run(t, new(OpenSuite))
+}
+
+func (s *OpenSuite) SetupTest() {
+	s.env = map[string]string{}
+	s.opened = nil
+	getenv = func(name string) string {
+		return s.env[name]
+	}
+	openURL = func(url string) error {
+		s.opened = append(s.opened, url)
+		return nil
+	}
+}
+
+func (s *OpenSuite) TearDownTest() {
+	getenv = os.Getenv
+	openURL = browser.OpenURL
+	goos = runtime.GOOS
+}
+
+func (s *OpenSuite) makeState(contentType config.ContentType, id string) *state.State {
+	cfg := config.New()
+	cfg.Type = contentType
+	target := deployment.New()
+	target.ID = types.ContentID(id)
+	return &state.State{
+		Account: &accounts.Account{URL: "https://connect.example.com"},
+		Config:  cfg,
+		Target:  target,
+	}
+}
+
+func (s *OpenSuite) TestContentURLDirect() {
+	st := s.makeState(config.ContentTypePythonShiny, "abc123")
+	s.Equal("https://connect.example.com/content/abc123/", ContentURL(st))
+}
+
+func (s *OpenSuite) TestContentURLAPI() {
+	st := s.makeState(config.ContentTypePythonFastAPI, "abc123")
+	s.Equal("https://connect.example.com/connect/#/apps/abc123", ContentURL(st))
+}
+
+func (s *OpenSuite) TestContentURLNotDeployed() {
+	st := s.makeState(config.ContentTypeHTML, "")
+	s.Equal("", ContentURL(st))
+}
+
+func (s *OpenSuite) TestOpenInBrowser() {
+	goos = "darwin"
+	opened, err := OpenInBrowser("https://connect.example.com/content/abc123/")
+	s.NoError(err)
+	s.True(opened)
+	s.Equal([]string{"https://connect.example.com/content/abc123/"}, s.opened)
+}
+
+func (s *OpenSuite) TestOpenInBrowserHeadless() {
+	goos = "linux"
+	opened, err := OpenInBrowser("https://connect.example.com/content/abc123/")
+	s.NoError(err)
+	s.False(opened)
+	s.Nil(s.opened)
+}
+
+func (s *OpenSuite) TestOpenInBrowserDisplay() {
+	goos = "linux"
+	s.env["WAYLAND_DISPLAY"] = "wayland-0"
+	opened, err := OpenInBrowser("https://connect.example.com/content/abc123/")
+	s.NoError(err)
+	s.True(opened)
+}
+
+func (s *OpenSuite) TestOpenInBrowserErr() {
+	goos = "windows"
+	testErr := errors.New("test error")
+	openURL = func(string) error {
+		return testErr
+	}
+	opened, err := OpenInBrowser("https://connect.example.com/content/abc123/")
+	s.ErrorIs(err, testErr)
+	s.False(opened)
+}
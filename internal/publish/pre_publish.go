@@ -0,0 +1,113 @@
+package publish
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"runtime"
+	"time"
+
+	"github.com/posit-dev/publisher/internal/events"
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/types"
+	"github.com/posit-dev/publisher/internal/util"
+)
+
+type runPrePublishStartData struct {
+	Commands []string `mapstructure:"commands"`
+}
+type runPrePublishSuccessData struct{}
+
+// defaultPrePublishTimeout limits how long each pre-publish
+// command can run, if the configuration doesn't set a timeout.
+const defaultPrePublishTimeout = 10 * time.Minute
+
+// prePublishWaitDelay is how long to wait for a command's output
+// to be closed after it exits or is stopped, since processes
+// started by the command may still be holding it open.
+const prePublishWaitDelay = 5 * time.Second
+
+// runPrePublish runs the configured pre-publish commands, such as
+// a build step, in the project directory. Their output is logged
+// as it is written, so it's streamed to the client as log events.
+func (p *defaultPublisher) runPrePublish() error {
+	prePublish := p.Config.PrePublish
+	if prePublish == nil || len(prePublish.Commands) == 0 {
+		return nil
+	}
+	op := events.PublishRunPrePublishOp
+	log := p.log.WithArgs(logging.LogKeyOp, op)
+
+	p.emitter.Emit(events.New(op, events.StartPhase, events.NoError, runPrePublishStartData{
+		Commands: prePublish.Commands,
+	}))
+	timeout := defaultPrePublishTimeout
+	if prePublish.Timeout > 0 {
+		timeout = time.Duration(prePublish.Timeout) * time.Second
+	}
+	ctx := p.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	for _, command := range prePublish.Commands {
+		log.Info("Running pre-publish command", "command", command)
+		err := runPrePublishCommand(ctx, command, p.Dir, timeout, log)
+		if err != nil {
+			return types.OperationError(op, err)
+		}
+	}
+	log.Info("Done running pre-publish commands")
+	p.emitter.Emit(events.New(op, events.SuccessPhase, events.NoError, runPrePublishSuccessData{}))
+	return nil
+}
+
+func runPrePublishCommand(
+	ctx context.Context,
+	command string,
+	dir util.AbsolutePath,
+	timeout time.Duration,
+	log logging.Logger) error {
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	cmd.Dir = dir.String()
+	cmd.WaitDelay = prePublishWaitDelay
+
+	reader, writer := io.Pipe()
+	cmd.Stdout = writer
+	cmd.Stderr = writer
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		scanner := bufio.NewScanner(reader)
+		for scanner.Scan() {
+			log.Info(scanner.Text())
+		}
+		// Keep draining so the command isn't blocked
+		// by a line too long for the scanner.
+		io.Copy(io.Discard, reader)
+	}()
+	err := cmd.Run()
+	writer.Close()
+	<-done
+
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("pre-publish command '%s' did not finish within %s", command, timeout)
+		}
+		return fmt.Errorf("pre-publish command '%s' failed: %w", command, err)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package publish
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"bytes"
+	"log/slog"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/posit-dev/publisher/internal/config"
+	"github.com/posit-dev/publisher/internal/events"
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/state"
+	"github.com/posit-dev/publisher/internal/types"
+	"github.com/posit-dev/publisher/internal/util"
+	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/suite"
+)
+
+type PrePublishSuite struct {
+	utiltest.Suite
+	stateStore *state.State
+	logBuffer  *bytes.Buffer
+	publisher  *defaultPublisher
+}
+
+func TestPrePublishSuite(t *testing.T) {
+	suite.Run(t, new(PrePublishSuite))
+}
+
+// shellCommand returns the command for the shell that runs
+// pre-publish commands, which is cmd on Windows and sh elsewhere.
+func shellCommand(sh string, cmd string) string {
+	if runtime.GOOS == "windows" {
+		return cmd
+	}
+	return sh
+}
+
+func (s *PrePublishSuite) SetupTest() {
+	// Commands run in a real directory.
+	s.stateStore = state.Empty()
+	s.stateStore.Dir = util.NewAbsolutePath(s.T().TempDir(), afero.NewOsFs())
+	s.logBuffer = new(bytes.Buffer)
+	s.publisher = &defaultPublisher{
+		State: s.stateStore,
+		log:   logging.FromStdLogger(slog.New(slog.NewTextHandler(s.logBuffer, nil))),
+	}
+}
+
+func (s *PrePublishSuite) TestNotConfigured() {
+	emitter := events.NewCapturingEmitter()
+	s.publisher.emitter = emitter
+	err := s.publisher.runPrePublish()
+	s.NoError(err)
+	s.Len(emitter.Events, 0)
+}
+
+func (s *PrePublishSuite) TestSuccess() {
+	commands := []string{
+		shellCommand("mkdir dist && echo built > dist/index.html", `mkdir dist && echo built> dist\index.html`),
+		"echo build complete",
+	}
+	s.stateStore.Config.PrePublish = &config.PrePublish{
+		Commands: commands,
+	}
+	emitter := events.NewCapturingEmitter()
+	s.publisher.emitter = emitter
+	err := s.publisher.runPrePublish()
+	s.NoError(err)
+
+	// Commands run in the project directory.
+	content, err := s.stateStore.Dir.Join("dist", "index.html").ReadFile()
+	s.NoError(err)
+	s.Equal("built", strings.TrimSpace(string(content)))
+
+	// Output is logged with the operation, which sends it as log events.
+	s.Contains(s.logBuffer.String(), `msg="build complete" event_op=publish/runPrePublish`)
+
+	s.Len(emitter.Events, 2)
+	s.Equal("publish/runPrePublish/start", emitter.Events[0].Type)
+	s.Equal(commands, emitter.Events[0].Data["commands"])
+	s.Equal("publish/runPrePublish/success", emitter.Events[1].Type)
+}
+
+func (s *PrePublishSuite) TestFailure() {
+	failing := shellCommand("echo compile error >&2; exit 3", "echo compile error>&2 & exit 3")
+	s.stateStore.Config.PrePublish = &config.PrePublish{
+		Commands: []string{
+			failing,
+			shellCommand("touch ran-after-failure", "type nul > ran-after-failure"),
+		},
+	}
+	emitter := events.NewCapturingEmitter()
+	s.publisher.emitter = emitter
+	err := s.publisher.runPrePublish()
+	s.ErrorContains(err, "pre-publish command '"+failing+"' failed: exit status 3")
+	agentErr, ok := types.IsAgentError(err)
+	s.True(ok)
+	s.Equal(events.PublishRunPrePublishOp, agentErr.GetOperation())
+
+	// Error output is logged, too.
+	s.Contains(s.logBuffer.String(), `msg="compile error"`)
+
+	// Later commands don't run.
+	exists, err := s.stateStore.Dir.Join("ran-after-failure").Exists()
+	s.NoError(err)
+	s.False(exists)
+
+	s.Len(emitter.Events, 1)
+	s.Equal("publish/runPrePublish/start", emitter.Events[0].Type)
+}
+
+func (s *PrePublishSuite) TestTimeout() {
+	sleep := shellCommand("exec sleep 10", "ping -n 11 127.0.0.1 > nul")
+	s.stateStore.Config.PrePublish = &config.PrePublish{
+		Commands: []string{sleep},
+		Timeout:  1,
+	}
+	emitter := events.NewCapturingEmitter()
+	s.publisher.emitter = emitter
+	err := s.publisher.runPrePublish()
+	s.ErrorContains(err, "pre-publish command '"+sleep+"' did not finish within 1s")
+}
@@ -3,10 +3,14 @@ package publish
 // Copyright (C) 2023 by Posit Software, PBC.
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"maps"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/mitchellh/mapstructure"
@@ -16,6 +20,7 @@ import (
 	"github.com/posit-dev/publisher/internal/config"
 	"github.com/posit-dev/publisher/internal/deployment"
 	"github.com/posit-dev/publisher/internal/events"
+	"github.com/posit-dev/publisher/internal/inspect"
 	"github.com/posit-dev/publisher/internal/inspect/dependencies/renv"
 	"github.com/posit-dev/publisher/internal/logging"
 	"github.com/posit-dev/publisher/internal/project"
@@ -27,10 +32,13 @@ import (
 
 type Publisher interface {
 	PublishDirectory() error
+	PublishBundle(path util.AbsolutePath) error
+	PublishManifest(path util.AbsolutePath) error
 }
 
 type defaultPublisher struct {
 	*state.State
+	ctx            context.Context
 	log            logging.Logger
 	emitter        events.Emitter
 	rPackageMapper renv.PackageMapper
@@ -46,11 +54,12 @@ type publishStartData struct {
 }
 
 type publishSuccessData struct {
-	ContentID    types.ContentID `mapstructure:"contentId"`
-	DashboardURL string          `mapstructure:"dashboardUrl"`
-	LogsURL      string          `mapstructure:"logsUrl"`
-	DirectURL    string          `mapstructure:"directUrl"`
-	ServerURL    string          `mapstructure:"serverUrl"`
+	ContentID    types.ContentID   `mapstructure:"contentId"`
+	DashboardURL string            `mapstructure:"dashboardUrl"`
+	LogsURL      string            `mapstructure:"logsUrl"`
+	DirectURL    string            `mapstructure:"directUrl"`
+	ServerURL    string            `mapstructure:"serverUrl"`
+	Timings      []events.OpTiming `mapstructure:"timings"` // Duration of each publishing phase
 }
 
 type publishFailureData struct {
@@ -63,7 +72,9 @@ type publishDeployedFailureData struct {
 	DirectURL    string `mapstructure:"url"`
 }
 
-func NewFromState(s *state.State, emitter events.Emitter, log logging.Logger) (Publisher, error) {
+// NewFromState returns a publisher for the given state.
+// Requests to the server stop when ctx is cancelled.
+func NewFromState(ctx context.Context, s *state.State, emitter events.Emitter, log logging.Logger) (Publisher, error) {
 	if s.LocalID != "" {
 		data := baseEventData{
 			LocalID: s.LocalID,
@@ -77,13 +88,14 @@ func NewFromState(s *state.State, emitter events.Emitter, log logging.Logger) (P
 	}
 	return &defaultPublisher{
 		State:          s,
+		ctx:            ctx,
 		log:            log,
 		emitter:        emitter,
 		rPackageMapper: renv.NewPackageMapper(s.Dir, util.Path{}),
 	}, nil
 }
 
-func logAppInfo(w io.Writer, accountURL string, contentID types.ContentID, log logging.Logger, publishingErr error) {
+func logAppInfo(w io.Writer, accountURL string, contentID types.ContentID, insecure bool, log logging.Logger, publishingErr error) {
 	dashboardURL := util.GetDashboardURL(accountURL, contentID)
 	logsURL := util.GetLogsURL(accountURL, contentID)
 	directURL := util.GetDirectURL(accountURL, contentID)
@@ -106,6 +118,10 @@ func logAppInfo(w io.Writer, accountURL string, contentID types.ContentID, log l
 		fmt.Fprintln(w)
 		fmt.Fprintln(w, "Dashboard URL: ", dashboardURL)
 		fmt.Fprintln(w, "Direct URL:    ", directURL)
+		if insecure {
+			fmt.Fprintln(w)
+			fmt.Fprintln(w, "Warning: TLS certificate verification was disabled; the connection to the server was not secure.")
+		}
 	}
 }
 
@@ -168,22 +184,158 @@ func (p *defaultPublisher) emitErrorEvents(err error) {
 
 func (p *defaultPublisher) PublishDirectory() error {
 	p.log.Info("Publishing from directory", logging.LogKeyOp, events.AgentOp, "path", p.Dir)
+	return p.publish(p.publishWithClient)
+}
+
+// PublishBundle deploys a previously built bundle archive
+// instead of bundling the project directory.
+func (p *defaultPublisher) PublishBundle(path util.AbsolutePath) error {
+	p.log.Info("Publishing from bundle", logging.LogKeyOp, events.AgentOp, "path", path)
+	return p.publish(func(account *accounts.Account, client connect.APIClient) error {
+		bundler, err := p.bundlerForArchive(path)
+		if err != nil {
+			return types.OperationError(events.PublishCreateBundleOp, err)
+		}
+		return p.publishBundleWithClient(account, client, bundler)
+	})
+}
+
+// PublishManifest deploys exactly the files listed in an existing
+// manifest.json, using the manifest as-is instead of building one
+// from the configuration.
+func (p *defaultPublisher) PublishManifest(path util.AbsolutePath) error {
+	p.log.Info("Publishing from manifest", logging.LogKeyOp, events.AgentOp, "path", path)
+	return p.publish(func(account *accounts.Account, client connect.APIClient) error {
+		bundler, err := p.bundlerForManifest(path)
+		if err != nil {
+			return types.OperationError(events.PublishCreateBundleOp, err)
+		}
+		return p.publishBundleWithClient(account, client, bundler)
+	})
+}
+
+var ErrBundleTypeMismatch = errors.New("the bundle's content type doesn't match the configuration")
+
+// bundlerForArchive returns a bundler for an existing bundle,
+// after checking that its manifest is consistent with the configuration.
+func (p *defaultPublisher) bundlerForArchive(path util.AbsolutePath) (bundles.Bundler, error) {
+	bundler, err := bundles.NewBundlerForArchive(path, p.log)
+	if err != nil {
+		return nil, err
+	}
+	err = p.checkBundleType(bundler)
+	if err != nil {
+		return nil, err
+	}
+	return bundler, nil
+}
+
+// bundlerForManifest returns a bundler for the files listed in
+// an existing manifest, after checking that the manifest is
+// consistent with the configuration and the files.
+func (p *defaultPublisher) bundlerForManifest(path util.AbsolutePath) (bundles.Bundler, error) {
+	bundler, err := bundles.NewBundlerForManifest(path, p.SymlinkPolicy, p.log)
+	if err != nil {
+		return nil, err
+	}
+	err = p.verifyManifestFiles(path)
+	if err != nil {
+		return nil, err
+	}
+	err = p.checkBundleType(bundler)
+	if err != nil {
+		return nil, err
+	}
+	return bundler, nil
+}
+
+// verifyManifestFiles reports files whose contents don't match the
+// checksums in the manifest, since the rest of the manifest may be out
+// of date too. Normally this is a warning; in strict mode it is an error.
+func (p *defaultPublisher) verifyManifestFiles(path util.AbsolutePath) error {
+	manifest, err := bundles.ReadManifestFile(path.Path)
+	if err != nil {
+		return err
+	}
+	changed, err := manifest.ChangedFiles(path.Dir())
+	if err != nil {
+		return err
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	err = fmt.Errorf("%w: %s", bundles.ErrManifestFilesChanged, strings.Join(changed, ", "))
+	if p.Strict {
+		return err
+	}
+	p.log.Warn(err.Error())
+	return nil
+}
+
+func (p *defaultPublisher) checkBundleType(bundler bundles.Bundler) error {
+	manifest, err := bundler.CreateManifest()
+	if err != nil {
+		return err
+	}
+	appMode := manifest.Metadata.AppMode
+	if appMode != connect.AppModeFromType(p.Config.Type) {
+		return fmt.Errorf("%w: bundle is '%s', configuration is '%s'",
+			ErrBundleTypeMismatch, connect.ContentTypeFromAppMode(appMode), p.Config.Type)
+	}
+	return nil
+}
+
+type publishFunc func(account *accounts.Account, client connect.APIClient) error
+
+var clientFactory = connect.NewConnectClient
+
+// logTimings logs the duration of each publishing phase.
+func logTimings(timings []events.OpTiming, log logging.Logger) {
+	args := []any{logging.LogKeyOp, events.AgentOp}
+	for _, t := range timings {
+		args = append(args, string(t.Op), time.Duration(t.DurationMs)*time.Millisecond)
+	}
+	log.Info("Publishing phase durations", args...)
+}
+
+// publish connects to the server and runs publishFn,
+// emitting the events for the publishing operation as a whole.
+func (p *defaultPublisher) publish(publishFn publishFunc) error {
+	// Time each phase using its start and success/failure events.
+	timer := events.NewTimingEmitter(p.emitter)
+	p.emitter = timer
+
 	p.emitter.Emit(events.New(events.PublishOp, events.StartPhase, events.NoError, publishStartData{
 		Server: p.Account.URL,
 		Title:  p.Config.Title,
 	}))
 	p.log.Info("Starting deployment to server", "server", p.Account.URL)
 
+	err := p.checkEntrypointCase()
+	if err != nil {
+		p.emitErrorEvents(err)
+		return err
+	}
+	if p.Strict {
+		err := p.checkStrictMode()
+		if err != nil {
+			p.emitErrorEvents(err)
+			return err
+		}
+	}
+
 	// TODO: factory method to create client based on server type
 	// TODO: timeout option
-	client, err := connect.NewConnectClient(p.Account, 2*time.Minute, p.emitter, p.log)
+	client, err := clientFactory(p.Account, 2*time.Minute, p.emitter, p.log)
 	if err != nil {
 		return err
 	}
-	err = p.publishWithClient(p.Account, client)
+	err = publishFn(p.Account, client.WithContext(p.ctx))
 	if p.isDeployed() {
-		logAppInfo(os.Stderr, p.Account.URL, p.Target.ID, p.log, err)
+		logAppInfo(os.Stderr, p.Account.URL, p.Target.ID, p.Account.Insecure, p.log, err)
 	}
+	timings := timer.Timings()
+	logTimings(timings, p.log)
 	if err != nil {
 		p.emitErrorEvents(err)
 	} else {
@@ -193,11 +345,34 @@ func (p *defaultPublisher) PublishDirectory() error {
 			DirectURL:    util.GetDirectURL(p.Account.URL, p.Target.ID),
 			ServerURL:    p.Account.URL,
 			ContentID:    p.Target.ID,
+			Timings:      timings,
 		}))
 	}
 	return err
 }
 
+type entrypointCaseMismatchDetails struct {
+	Entrypoint string `mapstructure:"entrypoint"`
+	Filename   string `mapstructure:"filename"`
+}
+
+// checkEntrypointCase returns an error if the configured entrypoint
+// matches its file only when case is ignored. This works locally on
+// a case-insensitive filesystem, but fails on the server.
+func (p *defaultPublisher) checkEntrypointCase() error {
+	filename, ok := p.Config.EntrypointCaseMismatch(p.Dir)
+	if !ok {
+		return nil
+	}
+	msg := fmt.Sprintf("the entrypoint %s does not match the case of the file %s; set entrypoint = \"%s\" in the configuration",
+		p.Config.Entrypoint, filename, filename)
+	err := types.NewAgentError(types.ErrorEntrypointCaseMismatch, errors.New(msg), entrypointCaseMismatchDetails{
+		Entrypoint: p.Config.Entrypoint,
+		Filename:   filename,
+	})
+	return types.OperationError(events.PublishCheckCapabilitiesOp, err)
+}
+
 func (p *defaultPublisher) writeDeploymentRecord() error {
 	if p.SaveName == "" {
 		// Redeployment
@@ -230,9 +405,13 @@ func (p *defaultPublisher) createDeploymentRecord(
 
 	created := ""
 	var contentType config.ContentType
+	var note string
+	var labels map[string]string
 
 	if p.Target != nil {
 		created = p.Target.CreatedAt
+		note = p.Target.Note
+		labels = p.Target.Labels
 		contentType = p.Target.Type
 		if contentType == "" || contentType == config.ContentTypeUnknown {
 			contentType = cfg.Type
@@ -258,6 +437,8 @@ func (p *defaultPublisher) createDeploymentRecord(
 		DashboardURL:  util.GetDashboardURL(p.Account.URL, contentID),
 		DirectURL:     util.GetDirectURL(p.Account.URL, contentID),
 		LogsURL:       util.GetLogsURL(p.Account.URL, contentID),
+		Note:          note,
+		Labels:        labels,
 		Error:         nil,
 	}
 
@@ -269,8 +450,13 @@ func (p *defaultPublisher) publishWithClient(
 	account *accounts.Account,
 	client connect.APIClient) error {
 
+	err := p.runPrePublish()
+	if err != nil {
+		return err
+	}
 	manifest := bundles.NewManifestFromConfig(p.Config)
 	p.log.Debug("Built manifest from config", "config", p.ConfigName)
+	manifest.AddGitMetadata(p.Dir, p.log)
 
 	if p.Config.R != nil {
 		rPackages, err := p.getRPackages()
@@ -279,18 +465,152 @@ func (p *defaultPublisher) publishWithClient(
 		}
 		manifest.Packages = rPackages
 	}
-	bundler, err := bundles.NewBundler(p.Dir, manifest, p.Config.Files, p.log)
+	bundler, err := p.directoryBundler(manifest)
 	if err != nil {
 		return err
 	}
+	return p.publishBundleWithClient(account, client, bundler)
+}
+
+// directoryBundler returns a bundler for the project directory,
+// which uses the bundle cache if it is enabled.
+func (p *defaultPublisher) directoryBundler(manifest *bundles.Manifest) (bundles.Bundler, error) {
+	cacheSize, err := bundleCacheSize()
+	if err != nil {
+		return nil, err
+	}
+	// A cached bundle is deployed in place of a new one with the
+	// same files, so they must be identical.
+	deterministic := cacheSize > 0
+	dirBundler, err := newDirectoryBundler(p.Dir, p.Config, manifest, p.SymlinkPolicy, deterministic, p.log)
+	if err != nil {
+		return nil, err
+	}
+	if cacheSize == 0 {
+		return dirBundler, nil
+	}
+	cache := bundles.NewBundleCache(bundles.GetBundleCacheDir(p.Dir), cacheSize, p.log)
+	return bundles.NewCachingBundler(dirBundler, cache, p.Config.EmptyDirs, p.log), nil
+}
 
-	err = p.preFlightChecks(client)
+// newDirectoryBundler returns a bundler for the project in dir, which
+// includes the requirements file exported from the lock file, if any.
+// Deployments and manifests both use it, so they list the same files.
+func newDirectoryBundler(
+	dir util.AbsolutePath,
+	cfg *config.Config,
+	manifest *bundles.Manifest,
+	symlinkPolicy util.SymlinkPolicy,
+	deterministic bool,
+	log logging.Logger) (bundles.Bundler, error) {
+
+	bundler, err := bundles.NewBundler(dir, manifest, cfg.Files, cfg.EmptyDirs, symlinkPolicy, log)
+	if err != nil {
+		return nil, err
+	}
+	bundler.SetDeterministic(deterministic)
+	workers, err := hashWorkers()
+	if err != nil {
+		return nil, err
+	}
+	if workers > 0 {
+		bundler.SetHashWorkers(workers)
+	}
+	if cfg.Python != nil {
+		filename, contents, err := exportRequirements(dir, cfg.Python, log)
+		if err != nil {
+			return nil, types.OperationError(events.PublishCreateBundleOp, err)
+		}
+		if contents != nil {
+			bundler.AddGeneratedFile(filename, contents)
+		}
+	}
+	return bundler, nil
+}
+
+// exportRequirements returns the name and contents of a requirements
+// file exported from the lock file of the package manager, such as
+// Poetry, so the deployed packages match the locked ones. The file is
+// added to the bundle; the project directory isn't changed. It returns
+// nil contents if the package manager's requirements aren't exported.
+func exportRequirements(dir util.AbsolutePath, python *config.Python, log logging.Logger) (string, []byte, error) {
+	filename := python.PackageFile
+	if filename == "" {
+		filename = inspect.PythonRequirementsFilename
+	}
+	packageManager := python.PackageManager
+	contents, exported, err := inspect.ExportRequirements(packageManager, dir)
+	if err != nil || !exported {
+		return "", nil, err
+	}
+	// Don't replace a requirements file the user wrote.
+	path := dir.Join(filename)
+	exists, err := path.Exists()
+	if err != nil {
+		return "", nil, err
+	}
+	if exists {
+		isExport, err := inspect.IsExportedRequirements(path)
+		if err != nil {
+			return "", nil, err
+		}
+		if !isExport {
+			return "", nil, fmt.Errorf(
+				"%s was not exported from the %s lock file; remove it, or set package_manager = \"pip\" to deploy it",
+				filename, packageManager)
+		}
+	}
+	log.Info("Exported Python packages", "package_manager", packageManager, "filename", filename)
+	return filename, contents, nil
+}
+
+// BundleCacheSizeEnvVar names an environment variable containing
+// the maximum size, in megabytes, of the bundle cache in
+// .posit/publish/cache. If it is unset or zero, bundles are not cached.
+const BundleCacheSizeEnvVar = "POSIT_PUBLISHER_BUNDLE_CACHE_SIZE"
+
+func bundleCacheSize() (int64, error) {
+	value := os.Getenv(BundleCacheSizeEnvVar)
+	if value == "" {
+		return 0, nil
+	}
+	size, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || size < 0 {
+		return 0, fmt.Errorf("%s must be a number of megabytes, not '%s'", BundleCacheSizeEnvVar, value)
+	}
+	return size * 1024 * 1024, nil
+}
+
+// HashWorkersEnvVar names an environment variable containing the
+// number of files to checksum at the same time while scanning the
+// project. If it is unset or zero, the number of CPUs is used.
+const HashWorkersEnvVar = "POSIT_PUBLISHER_HASH_WORKERS"
+
+func hashWorkers() (int, error) {
+	value := os.Getenv(HashWorkersEnvVar)
+	if value == "" {
+		return 0, nil
+	}
+	workers, err := strconv.Atoi(value)
+	if err != nil || workers < 0 {
+		return 0, fmt.Errorf("%s must be a number of files, not '%s'", HashWorkersEnvVar, value)
+	}
+	return workers, nil
+}
+
+func (p *defaultPublisher) publishBundleWithClient(
+	account *accounts.Account,
+	client connect.APIClient,
+	bundler bundles.Bundler) error {
+
+	err := p.preFlightChecks(client)
 	if err != nil {
 		return err
 	}
 
 	var contentID types.ContentID
-	if p.isDeployed() {
+	created := !p.isDeployed()
+	if !created {
 		contentID = p.Target.ID
 		p.log.Info("Updating deployment", "content_id", contentID)
 	} else {
@@ -311,7 +631,7 @@ func (p *defaultPublisher) publishWithClient(
 		return err
 	}
 
-	err = p.updateContent(client, contentID)
+	err = p.updateContent(client, contentID, created)
 	if err != nil {
 		return err
 	}
@@ -326,8 +646,17 @@ func (p *defaultPublisher) publishWithClient(
 		return err
 	}
 
-	taskLogger := p.log.WithArgs("source", "server.log")
-	err = client.WaitForTask(taskID, taskLogger)
+	err = p.waitForTask(client, taskID)
+	if err != nil {
+		return err
+	}
+
+	err = p.setThumbnail(client, contentID)
+	if err != nil {
+		return err
+	}
+
+	err = p.setVanityURL(client, contentID)
 	if err != nil {
 		return err
 	}
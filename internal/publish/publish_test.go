@@ -2,7 +2,10 @@ package publish
 
 // Copyright (C) 2023 by Posit Software, PBC.
 import (
+	"archive/tar"
 	"bytes"
+	"compress/gzip"
+	"context"
 	"errors"
 	"log/slog"
 	"strings"
@@ -15,6 +18,7 @@ import (
 	"github.com/posit-dev/publisher/internal/config"
 	"github.com/posit-dev/publisher/internal/deployment"
 	"github.com/posit-dev/publisher/internal/events"
+	"github.com/posit-dev/publisher/internal/inspect"
 	"github.com/posit-dev/publisher/internal/inspect/dependencies/renv"
 	"github.com/posit-dev/publisher/internal/logging"
 	"github.com/posit-dev/publisher/internal/logging/loggingtest"
@@ -115,7 +119,7 @@ func (s *PublishSuite) SetupTest() {
 
 func (s *PublishSuite) TestNewFromState() {
 	stateStore := state.Empty()
-	publisher, err := NewFromState(stateStore, events.NewNullEmitter(), logging.New())
+	publisher, err := NewFromState(context.Background(), stateStore, events.NewNullEmitter(), logging.New())
 	s.NoError(err)
 	s.Equal(stateStore, publisher.(*defaultPublisher).State)
 }
@@ -280,7 +284,7 @@ func (s *PublishSuite) publishWithClient(
 	client.On("SetEnvVars", myContentID, mock.Anything, mock.Anything).Return(errsMock.envVarErr)
 	client.On("UploadBundle", myContentID, mock.Anything, mock.Anything).Return(myBundleID, errsMock.uploadErr)
 	client.On("DeployBundle", myContentID, myBundleID, mock.Anything).Return(myTaskID, errsMock.deployErr)
-	client.On("WaitForTask", myTaskID, mock.Anything, mock.Anything).Return(errsMock.waitErr)
+	client.On("WaitForTask", types.TaskPosition{TaskID: myTaskID}, mock.Anything, mock.Anything).Return(errsMock.waitErr)
 	client.On("ValidateDeployment", myContentID, mock.Anything).Return(errsMock.validateErr)
 
 	cfg := config.New()
@@ -377,6 +381,10 @@ func (s *PublishSuite) publishWithClient(
 		s.NotEqual("", record.DeployedAt)
 		s.Equal("myConfig", record.ConfigName)
 		s.NotNil(record.Configuration)
+		if expectedErr == nil {
+			// The task is done, so there is nothing to resume.
+			s.Nil(record.Task)
+		}
 
 		if couldCreateDeployment && record.ID == myContentID {
 			logs := s.logBuffer.String()
@@ -389,6 +397,7 @@ func (s *PublishSuite) publishWithClient(
 			if errsMock.uploadErr == nil {
 				s.Contains(record.Files, "app.py")
 				s.Contains(record.Files, "requirements.txt")
+				s.Len(record.ContentHash, 64)
 				s.Equal([]string{"flask"}, record.Requirements)
 				s.Contains(record.Renv.Packages, renv.PackageName("mypkg"))
 			}
@@ -401,6 +410,343 @@ func (s *PublishSuite) publishWithClient(
 	s.False(exists)
 }
 
+func (s *PublishSuite) TestWaitForTaskSavesPosition() {
+	myTaskID := types.TaskID("myTaskID")
+	waitErr := errors.New("error from WaitForTask")
+	client := connect.NewMockClient()
+	client.On("WaitForTask", types.TaskPosition{TaskID: myTaskID}, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		onProgress := args.Get(1).(connect.TaskProgressFunc)
+		onProgress(types.TaskPosition{TaskID: myTaskID, Offset: 5})
+	}).Return(waitErr)
+
+	publisher := &defaultPublisher{
+		State: &state.State{
+			Dir:      s.cwd,
+			Config:   config.New(),
+			Target:   deployment.New(),
+			SaveName: "myDeployment",
+		},
+		log:     s.log,
+		emitter: events.NewCapturingEmitter(),
+	}
+	err := publisher.waitForTask(client, myTaskID)
+	s.ErrorIs(err, waitErr)
+
+	// The position is saved so waiting can resume later.
+	record, err := deployment.FromFile(deployment.GetDeploymentPath(s.cwd, "myDeployment"))
+	s.NoError(err)
+	s.Equal(&types.TaskPosition{TaskID: myTaskID, Offset: 5}, record.Task)
+}
+
+func (s *PublishSuite) TestWaitForTaskResumesFromSavedPosition() {
+	myTaskID := types.TaskID("myTaskID")
+	client := connect.NewMockClient()
+	client.On("WaitForTask", types.TaskPosition{TaskID: myTaskID, Offset: 7}, mock.Anything, mock.Anything).Return(nil)
+
+	target := deployment.New()
+	target.Task = &types.TaskPosition{TaskID: myTaskID, Offset: 7}
+	publisher := &defaultPublisher{
+		State: &state.State{
+			Dir:      s.cwd,
+			Config:   config.New(),
+			Target:   target,
+			SaveName: "myDeployment",
+		},
+		log:     s.log,
+		emitter: events.NewCapturingEmitter(),
+	}
+	err := publisher.waitForTask(client, myTaskID)
+	s.NoError(err)
+	client.AssertExpectations(s.T())
+
+	record, err := deployment.FromFile(deployment.GetDeploymentPath(s.cwd, "myDeployment"))
+	s.NoError(err)
+	s.Nil(record.Task)
+}
+
+func (s *PublishSuite) TestWaitForTaskIgnoresOtherTaskPosition() {
+	myTaskID := types.TaskID("myTaskID")
+	client := connect.NewMockClient()
+	client.On("WaitForTask", types.TaskPosition{TaskID: myTaskID}, mock.Anything, mock.Anything).Return(nil)
+
+	// A position saved by an earlier deployment doesn't apply.
+	target := deployment.New()
+	target.Task = &types.TaskPosition{TaskID: "oldTaskID", Offset: 7}
+	publisher := &defaultPublisher{
+		State: &state.State{
+			Dir:      s.cwd,
+			Config:   config.New(),
+			Target:   target,
+			SaveName: "myDeployment",
+		},
+		log:     s.log,
+		emitter: events.NewCapturingEmitter(),
+	}
+	err := publisher.waitForTask(client, myTaskID)
+	s.NoError(err)
+	client.AssertExpectations(s.T())
+}
+
+// writeBundle bundles the project directory using cfg
+// and saves the bundle as bundle.tar.gz.
+func (s *PublishSuite) writeBundle(cfg *config.Config) util.AbsolutePath {
+	manifest := bundles.NewManifestFromConfig(cfg)
+	bundler, err := bundles.NewBundler(s.cwd, manifest, nil, nil, util.SymlinkFollow, s.log)
+	s.NoError(err)
+	buf := new(bytes.Buffer)
+	_, err = bundler.CreateBundle(buf)
+	s.NoError(err)
+
+	path := s.cwd.Join("bundle.tar.gz")
+	s.NoError(path.WriteFile(buf.Bytes(), 0600))
+	return path
+}
+
+func (s *PublishSuite) newBundlePublisher(cfg *config.Config) *defaultPublisher {
+	return &defaultPublisher{
+		State: &state.State{
+			Dir: s.cwd,
+			Account: &accounts.Account{
+				ServerType: accounts.ServerTypeConnect,
+				URL:        "https://connect.example.com",
+			},
+			Config:     cfg,
+			ConfigName: "myConfig",
+			SaveName:   "saveAsThis",
+		},
+		log:     s.log,
+		emitter: events.NewCapturingEmitter(),
+	}
+}
+
+func (s *PublishSuite) TestPublishBundle() {
+	cfg := config.New()
+	cfg.Type = config.ContentTypeHTML
+	cfg.Entrypoint = "index.html"
+	s.NoError(s.cwd.Join("index.html").WriteFile([]byte("<html></html>"), 0600))
+	bundlePath := s.writeBundle(cfg)
+
+	publisher := s.newBundlePublisher(cfg)
+	bundler, err := publisher.bundlerForArchive(bundlePath)
+	s.NoError(err)
+	record := s.publishBundler(publisher, bundler)
+	s.Contains(record.Files, "index.html")
+}
+
+func (s *PublishSuite) TestPublishBundleDoesNotRecordProjectDependencies() {
+	cfg := config.New()
+	cfg.Type = config.ContentTypeHTML
+	cfg.Entrypoint = "index.html"
+	s.NoError(s.cwd.Join("index.html").WriteFile([]byte("<html></html>"), 0600))
+	bundlePath := s.writeBundle(cfg)
+
+	// The project's requirements aren't necessarily the ones
+	// in the bundle, so they aren't read or recorded.
+	s.NoError(s.cwd.Join("requirements.txt").Remove())
+	cfg.Python = &config.Python{
+		PackageManager: "pip",
+		PackageFile:    "requirements.txt",
+	}
+	publisher := s.newBundlePublisher(cfg)
+	bundler, err := publisher.bundlerForArchive(bundlePath)
+	s.NoError(err)
+	record := s.publishBundler(publisher, bundler)
+	s.Nil(record.Requirements)
+	s.Nil(record.Renv)
+}
+
+// newBundleClient returns a mock client for a successful deployment.
+func newBundleClient() *connect.MockClient {
+	myContentID := types.ContentID("myContentID")
+	myBundleID := types.BundleID("myBundleID")
+	myTaskID := types.TaskID("myTaskID")
+
+	client := connect.NewMockClient()
+	client.On("TestAuthentication", mock.Anything).Return(&connect.User{}, nil)
+	client.On("CheckCapabilities", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	client.On("CreateDeployment", mock.Anything, mock.Anything).Return(myContentID, nil)
+	client.On("UpdateDeployment", myContentID, mock.Anything, mock.Anything).Return(nil)
+	client.On("SetEnvVars", myContentID, mock.Anything, mock.Anything).Return(nil)
+	client.On("UploadBundle", myContentID, mock.Anything, mock.Anything).Return(myBundleID, nil)
+	client.On("DeployBundle", myContentID, myBundleID, mock.Anything).Return(myTaskID, nil)
+	client.On("WaitForTask", types.TaskPosition{TaskID: myTaskID}, mock.Anything, mock.Anything).Return(nil)
+	client.On("ValidateDeployment", myContentID, mock.Anything).Return(nil)
+	return client
+}
+
+// publishBundler deploys the bundle using a mock client, and
+// returns the resulting deployment record.
+func (s *PublishSuite) publishBundler(publisher *defaultPublisher, bundler bundles.Bundler) *deployment.Deployment {
+	myContentID := types.ContentID("myContentID")
+	myBundleID := types.BundleID("myBundleID")
+
+	client := newBundleClient()
+	err := publisher.publishBundleWithClient(publisher.Account, client, bundler)
+	s.NoError(err)
+
+	record, err := deployment.FromFile(deployment.GetDeploymentPath(s.cwd, "saveAsThis"))
+	s.NoError(err)
+	s.Equal(myContentID, record.ID)
+	s.Equal(myBundleID, record.BundleID)
+	return record
+}
+
+func (s *PublishSuite) TestPublishTimings() {
+	cfg := config.New()
+	cfg.Type = config.ContentTypeHTML
+	cfg.Entrypoint = "index.html"
+	s.NoError(s.cwd.Join("index.html").WriteFile([]byte("<html></html>"), 0600))
+	bundlePath := s.writeBundle(cfg)
+
+	client := newBundleClient()
+	clientFactory = func(*accounts.Account, time.Duration, events.Emitter, logging.Logger) (connect.APIClient, error) {
+		return client, nil
+	}
+	defer func() {
+		clientFactory = connect.NewConnectClient
+	}()
+
+	emitter := events.NewCapturingEmitter()
+	publisher := s.newBundlePublisher(cfg)
+	publisher.emitter = emitter
+	s.NoError(publisher.PublishBundle(bundlePath))
+
+	success := emitter.Events[len(emitter.Events)-1]
+	s.Equal("publish/success", success.Type)
+	timings, ok := success.Data["timings"].([]events.OpTiming)
+	s.True(ok)
+
+	ops := []events.Operation{}
+	for i, t := range timings {
+		ops = append(ops, t.Op)
+		s.GreaterOrEqual(t.DurationMs, int64(0))
+		if i > 0 {
+			// Phases are in the order they started.
+			s.GreaterOrEqual(t.StartMs, timings[i-1].StartMs)
+			s.GreaterOrEqual(t.StartMs, timings[i-1].StartMs+timings[i-1].DurationMs)
+		}
+	}
+	s.Equal([]events.Operation{
+		events.PublishCheckCapabilitiesOp,
+		events.PublishCreateNewDeploymentOp,
+		events.PublishCreateBundleOp,
+		events.PublishUploadBundleOp,
+		events.PublishUpdateDeploymentOp,
+		events.PublishDeployBundleOp,
+		events.PublishValidateDeploymentOp,
+	}, ops)
+
+	logs := s.logBuffer.String()
+	s.Contains(logs, "Publishing phase durations")
+	s.Contains(logs, "publish/uploadBundle=")
+}
+
+func (s *PublishSuite) writeManifest(cfg *config.Config, files ...string) util.AbsolutePath {
+	manifest := bundles.NewManifestFromConfig(cfg)
+	for _, f := range files {
+		manifest.AddFile(f, nil)
+	}
+	path := s.cwd.Join(bundles.ManifestFilename)
+	s.NoError(manifest.WriteManifestFile(path.Path))
+	return path
+}
+
+func (s *PublishSuite) TestPublishManifest() {
+	cfg := config.New()
+	cfg.Type = config.ContentTypePythonFlask
+	cfg.Entrypoint = "app.py"
+	cfg.Python = &config.Python{
+		Version:        "3.11.3",
+		PackageManager: "pip",
+	}
+	manifestPath := s.writeManifest(cfg, "app.py")
+
+	publisher := s.newBundlePublisher(cfg)
+	bundler, err := publisher.bundlerForManifest(manifestPath)
+	s.NoError(err)
+	record := s.publishBundler(publisher, bundler)
+
+	// Only the listed files are deployed.
+	s.Equal([]string{"app.py"}, record.Files)
+}
+
+func (s *PublishSuite) TestPublishManifestMissingFiles() {
+	cfg := config.New()
+	cfg.Type = config.ContentTypePythonFlask
+	cfg.Entrypoint = "app.py"
+	manifestPath := s.writeManifest(cfg, "app.py", "missing.py")
+
+	publisher := s.newBundlePublisher(cfg)
+	_, err := publisher.bundlerForManifest(manifestPath)
+	s.ErrorIs(err, bundles.ErrManifestFilesMissing)
+}
+
+func (s *PublishSuite) TestPublishManifestChangedFiles() {
+	cfg := config.New()
+	cfg.Type = config.ContentTypePythonFlask
+	cfg.Entrypoint = "app.py"
+	manifest := bundles.NewManifestFromConfig(cfg)
+	manifest.AddFile("app.py", []byte("stale checksum"))
+	manifestPath := s.cwd.Join(bundles.ManifestFilename)
+	s.NoError(manifest.WriteManifestFile(manifestPath.Path))
+
+	publisher := s.newBundlePublisher(cfg)
+	_, err := publisher.bundlerForManifest(manifestPath)
+	s.NoError(err)
+	s.Contains(s.logBuffer.String(), "files have changed since the manifest was written: app.py")
+
+	publisher.Strict = true
+	_, err = publisher.bundlerForManifest(manifestPath)
+	s.ErrorIs(err, bundles.ErrManifestFilesChanged)
+}
+
+func (s *PublishSuite) TestPublishManifestTypeMismatch() {
+	cfg := config.New()
+	cfg.Type = config.ContentTypePythonFlask
+	manifestPath := s.writeManifest(cfg, "app.py")
+
+	cfg = config.New()
+	cfg.Type = config.ContentTypePythonDash
+	publisher := s.newBundlePublisher(cfg)
+	_, err := publisher.bundlerForManifest(manifestPath)
+	s.ErrorIs(err, ErrBundleTypeMismatch)
+}
+
+func (s *PublishSuite) TestPublishBundleNoManifest() {
+	bundlePath := s.cwd.Join("bundle.tar.gz")
+	buf := new(bytes.Buffer)
+	gz := gzip.NewWriter(buf)
+	archive := tar.NewWriter(gz)
+	s.NoError(archive.WriteHeader(&tar.Header{
+		Name:     "index.html",
+		Mode:     0600,
+		Size:     0,
+		Typeflag: tar.TypeReg,
+	}))
+	s.NoError(archive.Close())
+	s.NoError(gz.Close())
+	s.NoError(bundlePath.WriteFile(buf.Bytes(), 0600))
+
+	cfg := config.New()
+	cfg.Type = config.ContentTypeHTML
+	publisher := s.newBundlePublisher(cfg)
+	_, err := publisher.bundlerForArchive(bundlePath)
+	s.ErrorIs(err, bundles.ErrNoManifest)
+}
+
+func (s *PublishSuite) TestPublishBundleTypeMismatch() {
+	cfg := config.New()
+	cfg.Type = config.ContentTypePythonFlask
+	cfg.Entrypoint = "app.py"
+	bundlePath := s.writeBundle(cfg)
+
+	cfg = config.New()
+	cfg.Type = config.ContentTypePythonDash
+	publisher := s.newBundlePublisher(cfg)
+	_, err := publisher.bundlerForArchive(bundlePath)
+	s.ErrorIs(err, ErrBundleTypeMismatch)
+}
+
 func (s *PublishSuite) TestEmitErrorEventsNoTarget() {
 	expectedErr := errors.New("test error")
 	log := logging.New()
@@ -488,10 +834,24 @@ func (s *PublishSuite) TestLogAppInfo() {
 	log := loggingtest.NewMockLogger()
 	log.On("Info", "Deployment information", a, a, a, a, a, a, a, a, a, a, a, a).Return()
 
-	logAppInfo(buf, accountURL, contentID, log, nil)
+	logAppInfo(buf, accountURL, contentID, false, log, nil)
 	str := buf.String()
 	s.Contains(str, directURL)
 	s.Contains(str, dashboardURL)
+	s.NotContains(str, "TLS certificate verification")
+}
+
+func (s *PublishSuite) TestLogAppInfoInsecure() {
+	accountURL := "https://connect.example.com:1234"
+	contentID := types.ContentID("myContentID")
+
+	buf := new(bytes.Buffer)
+	a := mock.Anything
+	log := loggingtest.NewMockLogger()
+	log.On("Info", "Deployment information", a, a, a, a, a, a, a, a, a, a, a, a).Return()
+
+	logAppInfo(buf, accountURL, contentID, true, log, nil)
+	s.Contains(buf.String(), "TLS certificate verification was disabled")
 }
 
 func (s *PublishSuite) TestLogAppInfoErr() {
@@ -504,7 +864,7 @@ func (s *PublishSuite) TestLogAppInfoErr() {
 	buf := new(bytes.Buffer)
 
 	testError := errors.New("test error")
-	logAppInfo(buf, accountURL, contentID, nil, testError)
+	logAppInfo(buf, accountURL, contentID, false, nil, testError)
 	str := buf.String()
 	s.NotContains(str, directURL)
 	s.Contains(str, dashboardURL)
@@ -517,6 +877,115 @@ func (s *PublishSuite) TestLogAppInfoErrNoContentID() {
 
 	buf := new(bytes.Buffer)
 	testError := errors.New("test error")
-	logAppInfo(buf, accountURL, contentID, nil, testError)
+	logAppInfo(buf, accountURL, contentID, false, nil, testError)
 	s.Equal("", buf.String())
 }
+
+func (s *PublishSuite) newPoetryPublisher() *defaultPublisher {
+	s.NoError(s.cwd.Join("requirements.txt").Remove())
+	lock := "[[package]]\nname = \"flask\"\nversion = \"3.0.3\"\ngroups = [\"main\"]\n"
+	s.NoError(s.cwd.Join(inspect.PoetryLockFilename).WriteFile([]byte(lock), 0600))
+	cfg := config.New()
+	cfg.Type = config.ContentTypePythonFlask
+	cfg.Entrypoint = "app.py"
+	cfg.Python = &config.Python{
+		PackageManager: "poetry",
+		PackageFile:    "requirements.txt",
+	}
+	return s.newBundlePublisher(cfg)
+}
+
+func (s *PublishSuite) TestExportRequirements() {
+	publisher := s.newPoetryPublisher()
+	filename, contents, err := exportRequirements(publisher.Dir, publisher.Config.Python, publisher.log)
+	s.NoError(err)
+	s.Equal("requirements.txt", filename)
+	s.Contains(string(contents), "\nflask==3.0.3\n")
+
+	// The project directory isn't changed.
+	exists, err := s.cwd.Join("requirements.txt").Exists()
+	s.NoError(err)
+	s.False(exists)
+}
+
+func (s *PublishSuite) TestExportRequirementsPreviouslyExported() {
+	publisher := s.newPoetryPublisher()
+	exported := []byte("# exported from poetry.lock by Posit Publisher\nflask==2.0.0\n")
+	s.NoError(s.cwd.Join("requirements.txt").WriteFile(exported, 0600))
+
+	_, contents, err := exportRequirements(publisher.Dir, publisher.Config.Python, publisher.log)
+	s.NoError(err)
+	s.Contains(string(contents), "\nflask==3.0.3\n")
+}
+
+func (s *PublishSuite) TestExportRequirementsUserFile() {
+	publisher := s.newPoetryPublisher()
+	requirements := []byte("flask\n")
+	s.NoError(s.cwd.Join("requirements.txt").WriteFile(requirements, 0600))
+
+	_, _, err := exportRequirements(publisher.Dir, publisher.Config.Python, publisher.log)
+	s.ErrorContains(err, "requirements.txt was not exported from the poetry lock file")
+
+	content, err := s.cwd.Join("requirements.txt").ReadFile()
+	s.NoError(err)
+	s.Equal(requirements, content)
+}
+
+func (s *PublishSuite) TestPublishExportedRequirements() {
+	publisher := s.newPoetryPublisher()
+	err := publisher.publishWithClient(publisher.Account, newBundleClient())
+	s.NoError(err)
+
+	record, err := deployment.FromFile(deployment.GetDeploymentPath(s.cwd, "saveAsThis"))
+	s.NoError(err)
+	s.Contains(record.Files, "requirements.txt")
+	s.Equal([]string{"flask==3.0.3"}, record.Requirements)
+
+	exists, err := s.cwd.Join("requirements.txt").Exists()
+	s.NoError(err)
+	s.False(exists)
+}
+
+func (s *PublishSuite) TestDirectoryBundlerCache() {
+	s.T().Setenv(BundleCacheSizeEnvVar, "10")
+	cfg := config.New()
+	cfg.Type = config.ContentTypeHTML
+	cfg.Entrypoint = "index.html"
+	s.NoError(s.cwd.Join("index.html").WriteFile([]byte("<html></html>"), 0600))
+	publisher := s.newBundlePublisher(cfg)
+
+	createBundle := func() []byte {
+		bundler, err := publisher.directoryBundler(bundles.NewManifestFromConfig(cfg))
+		s.NoError(err)
+		buf := new(bytes.Buffer)
+		_, err = bundler.CreateBundle(buf)
+		s.NoError(err)
+		return buf.Bytes()
+	}
+	first := createBundle()
+
+	// Without the cached bundle, a new one is created,
+	// and it's the same even though the file times changed.
+	s.NoError(bundles.GetBundleCacheDir(s.cwd).RemoveAll())
+	modTime := time.Now().Add(time.Hour)
+	s.NoError(s.fs.Chtimes(s.cwd.Join("index.html").String(), modTime, modTime))
+	s.Equal(first, createBundle())
+}
+
+func (s *PublishSuite) TestHashWorkers() {
+	workers, err := hashWorkers()
+	s.NoError(err)
+	s.Equal(0, workers)
+
+	s.T().Setenv(HashWorkersEnvVar, "4")
+	workers, err = hashWorkers()
+	s.NoError(err)
+	s.Equal(4, workers)
+
+	s.T().Setenv(HashWorkersEnvVar, "many")
+	_, err = hashWorkers()
+	s.ErrorContains(err, "POSIT_PUBLISHER_HASH_WORKERS must be a number of files, not 'many'")
+
+	_, err = s.newBundlePublisher(config.New()).directoryBundler(bundles.NewManifest())
+	s.ErrorContains(err, "POSIT_PUBLISHER_HASH_WORKERS")
+}
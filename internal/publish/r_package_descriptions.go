@@ -8,10 +8,13 @@ import (
 	"os"
 
 	"github.com/posit-dev/publisher/internal/bundles"
+	"github.com/posit-dev/publisher/internal/config"
 	"github.com/posit-dev/publisher/internal/events"
 	"github.com/posit-dev/publisher/internal/inspect"
+	"github.com/posit-dev/publisher/internal/inspect/dependencies/renv"
 	"github.com/posit-dev/publisher/internal/logging"
 	"github.com/posit-dev/publisher/internal/types"
+	"github.com/posit-dev/publisher/internal/util"
 )
 
 type getRPackageDescriptionsStartData struct{}
@@ -30,14 +33,26 @@ func (p *defaultPublisher) getRPackages() (bundles.PackageMap, error) {
 	p.emitter.Emit(events.New(op, events.StartPhase, events.NoError, getRPackageDescriptionsStartData{}))
 	log.Info("Collecting R package descriptions")
 
-	lockfileString := p.Config.R.PackageFile
+	rPackages, err := readRPackages(p.Dir, p.Config.R, p.rPackageMapper, log)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("Done collecting R package descriptions")
+	p.emitter.Emit(events.New(op, events.SuccessPhase, events.NoError, getRPackageDescriptionsSuccessData{}))
+	return rPackages, nil
+}
+
+// readRPackages returns the manifest descriptions of the
+// packages in the project's renv lockfile.
+func readRPackages(dir util.AbsolutePath, rConfig *config.R, mapper renv.PackageMapper, log logging.Logger) (bundles.PackageMap, error) {
+	lockfileString := rConfig.PackageFile
 	if lockfileString == "" {
 		lockfileString = inspect.DefaultRenvLockfile
 	}
-	lockfilePath := p.Dir.Join(lockfileString)
+	lockfilePath := dir.Join(lockfileString)
 
 	log.Debug("Collecting manifest R packages", "lockfile", lockfilePath)
-	rPackages, err := p.rPackageMapper.GetManifestPackages(p.Dir, lockfilePath, log)
+	rPackages, err := mapper.GetManifestPackages(dir, lockfilePath, log)
 	if err != nil {
 		// If error is an already well detailed agent error, pass it along
 		if aerr, isAgentErr := types.IsAgentError(err); isAgentErr {
@@ -50,7 +65,5 @@ func (p *defaultPublisher) getRPackages() (bundles.PackageMap, error) {
 		}
 		return nil, agentErr
 	}
-	log.Info("Done collecting R package descriptions")
-	p.emitter.Emit(events.New(op, events.SuccessPhase, events.NoError, getRPackageDescriptionsSuccessData{}))
 	return rPackages, nil
 }
@@ -3,7 +3,10 @@ package publish
 // Copyright (C) 2023 by Posit Software, PBC.
 
 import (
+	"fmt"
 	"maps"
+	"regexp"
+	"slices"
 
 	"github.com/posit-dev/publisher/internal/clients/connect"
 	"github.com/posit-dev/publisher/internal/events"
@@ -14,13 +17,32 @@ import (
 type setEnvVarsStartData struct{}
 type setEnvVarsSuccessData struct{}
 
+type invalidEnvVarNameDetails struct {
+	Name string `mapstructure:"name"`
+}
+
+// Connect requires environment variable names to be valid shell identifiers.
+var envVarNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// validateEnvVarNames returns an error for the first name,
+// in sorted order, that Connect would reject.
+func validateEnvVarNames(env map[string]string) error {
+	for _, name := range slices.Sorted(maps.Keys(env)) {
+		if !envVarNamePattern.MatchString(name) {
+			err := fmt.Errorf("'%s' is not a valid environment variable name; names must start with a letter or underscore, and contain only letters, digits, and underscores", name)
+			return types.NewAgentError(types.ErrorInvalidEnvVarName, err, invalidEnvVarNameDetails{Name: name})
+		}
+	}
+	return nil
+}
+
 func (p *defaultPublisher) setEnvVars(
 	client connect.APIClient,
 	contentID types.ContentID) error {
 
 	env := p.Config.Environment
 	secrets := p.Secrets
-	if len(env) == 0 && len(secrets) == 0 {
+	if len(env) == 0 && len(secrets) == 0 && !p.PruneEnvVars {
 		return nil
 	}
 
@@ -43,12 +65,52 @@ func (p *defaultPublisher) setEnvVars(
 	maps.Copy(combinedEnv, env)
 	maps.Copy(combinedEnv, secrets)
 
-	err := client.SetEnvVars(contentID, combinedEnv, log)
-	if err != nil {
-		return types.OperationError(op, err)
+	if len(combinedEnv) != 0 {
+		err := validateEnvVarNames(combinedEnv)
+		if err != nil {
+			return types.OperationError(op, err)
+		}
+		err = client.SetEnvVars(contentID, combinedEnv, log)
+		if err != nil {
+			return types.OperationError(op, err)
+		}
+	}
+	if p.PruneEnvVars {
+		err := p.pruneEnvVars(client, contentID, log)
+		if err != nil {
+			return types.OperationError(op, err)
+		}
 	}
 
 	log.Info("Done setting environment variables")
 	p.emitter.Emit(events.New(op, events.SuccessPhase, events.NoError, setEnvVarsSuccessData{}))
 	return nil
 }
+
+// pruneEnvVars removes environment variables from the content if
+// they are no longer in the configuration. Secrets named in the
+// configuration are kept even if no value was provided, since
+// their values are only sent when they change.
+func (p *defaultPublisher) pruneEnvVars(
+	client connect.APIClient,
+	contentID types.ContentID,
+	log logging.Logger) error {
+
+	current, err := client.GetEnvVars(contentID, log)
+	if err != nil {
+		return err
+	}
+	stale := []string{}
+	for _, name := range *current {
+		_, inEnv := p.Config.Environment[name]
+		_, inSecrets := p.Secrets[name]
+		if !inEnv && !inSecrets && !slices.Contains(p.Config.Secrets, name) {
+			log.Info("Removing environment variable", "name", name)
+			stale = append(stale, name)
+		}
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+	return client.DeleteEnvVars(contentID, stale, log)
+}
@@ -3,6 +3,9 @@ package publish
 // Copyright (C) 2024 by Posit Software, PBC.
 
 import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
 	"testing"
 
 	"github.com/posit-dev/publisher/internal/clients/connect"
@@ -113,3 +116,155 @@ func (s *SetEnvVarsSuite) TestSetEnvVarsWithSecretsAndEnvironment() {
 
 	client.AssertExpectations(s.T())
 }
+
+func (s *SetEnvVarsSuite) TestSetEnvVarsInvalidName() {
+	stateStore := state.Empty()
+	log := logging.New()
+	emitter := events.NewCapturingEmitter()
+
+	stateStore.Config.Environment = map[string]string{"VALID_NAME": "value", "1NVALID-NAME": "value"}
+
+	publisher := &defaultPublisher{
+		State:   stateStore,
+		log:     log,
+		emitter: emitter,
+	}
+	client := connect.NewMockClient()
+
+	err := publisher.setEnvVars(client, types.ContentID("test-content-id"))
+	agentErr, ok := types.IsAgentErrorOf(err, types.ErrorInvalidEnvVarName)
+	s.True(ok)
+	s.Contains(agentErr.Message, "'1NVALID-NAME' is not a valid environment variable name")
+	s.Equal("1NVALID-NAME", agentErr.Data["name"])
+	s.Equal(events.PublishSetEnvVarsOp, agentErr.Op)
+
+	// The server is not called.
+	client.AssertNotCalled(s.T(), "SetEnvVars", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *SetEnvVarsSuite) TestValidateEnvVarNames() {
+	s.NoError(validateEnvVarNames(nil))
+	s.NoError(validateEnvVarNames(map[string]string{
+		"PATH":     "",
+		"_private": "",
+		"a1_B2":    "",
+	}))
+	for _, name := range []string{"", "1ABC", "MY-VAR", "MY VAR", "MY.VAR", "É"} {
+		err := validateEnvVarNames(map[string]string{name: "value"})
+		s.ErrorContains(err, "not a valid environment variable name", name)
+	}
+}
+
+func (s *SetEnvVarsSuite) TestSetEnvVarsPrune() {
+	stateStore := state.Empty()
+	log := logging.New()
+	emitter := events.NewCapturingEmitter()
+
+	stateStore.PruneEnvVars = true
+	stateStore.Config.Environment = map[string]string{"KEEP": "value"}
+	stateStore.Config.Secrets = []string{"DECLARED_SECRET", "SENT_SECRET"}
+	stateStore.Secrets = map[string]string{"SENT_SECRET": "secret-value"}
+
+	publisher := &defaultPublisher{
+		State:   stateStore,
+		log:     log,
+		emitter: emitter,
+	}
+	client := connect.NewMockClient()
+	contentID := types.ContentID("test-content-id")
+
+	client.On("SetEnvVars", contentID, map[string]string{
+		"KEEP":        "value",
+		"SENT_SECRET": "secret-value",
+	}, mock.Anything).Return(nil)
+	current := types.Environment{"DECLARED_SECRET", "DROPPED", "KEEP", "SENT_SECRET"}
+	client.On("GetEnvVars", contentID, mock.Anything).Return(&current, nil)
+	client.On("DeleteEnvVars", contentID, []string{"DROPPED"}, mock.Anything).Return(nil)
+
+	err := publisher.setEnvVars(client, contentID)
+	s.NoError(err)
+	client.AssertExpectations(s.T())
+}
+
+func (s *SetEnvVarsSuite) TestSetEnvVarsPruneEmptyConfig() {
+	stateStore := state.Empty()
+	log := logging.New()
+	emitter := events.NewCapturingEmitter()
+
+	stateStore.PruneEnvVars = true
+
+	publisher := &defaultPublisher{
+		State:   stateStore,
+		log:     log,
+		emitter: emitter,
+	}
+	client := connect.NewMockClient()
+	contentID := types.ContentID("test-content-id")
+
+	current := types.Environment{"DROPPED"}
+	client.On("GetEnvVars", contentID, mock.Anything).Return(&current, nil)
+	client.On("DeleteEnvVars", contentID, []string{"DROPPED"}, mock.Anything).Return(nil)
+
+	err := publisher.setEnvVars(client, contentID)
+	s.NoError(err)
+	client.AssertExpectations(s.T())
+	client.AssertNotCalled(s.T(), "SetEnvVars", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *SetEnvVarsSuite) TestSetEnvVarsNoPrune() {
+	stateStore := state.Empty()
+	log := logging.New()
+	emitter := events.NewCapturingEmitter()
+
+	stateStore.Config.Environment = map[string]string{"KEEP": "value"}
+
+	publisher := &defaultPublisher{
+		State:   stateStore,
+		log:     log,
+		emitter: emitter,
+	}
+	client := connect.NewMockClient()
+	contentID := types.ContentID("test-content-id")
+	client.On("SetEnvVars", contentID, stateStore.Config.Environment, mock.Anything).Return(nil)
+
+	err := publisher.setEnvVars(client, contentID)
+	s.NoError(err)
+	client.AssertNotCalled(s.T(), "GetEnvVars", mock.Anything, mock.Anything)
+	client.AssertNotCalled(s.T(), "DeleteEnvVars", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *SetEnvVarsSuite) TestSetEnvVarsRedactsSecrets() {
+	stateStore := state.Empty()
+	logBuffer := new(bytes.Buffer)
+	log := logging.FromStdLogger(slog.New(slog.NewTextHandler(logBuffer, nil)))
+	emitter := events.NewCapturingEmitter()
+
+	stateStore.Config.Environment = map[string]string{"PLAIN_VAR": "plain-value"}
+	stateStore.Config.Secrets = []string{"API_KEY"}
+	stateStore.Secrets = map[string]string{"API_KEY": "secret-value"}
+
+	publisher := &defaultPublisher{
+		State:   stateStore,
+		log:     log,
+		emitter: emitter,
+	}
+	client := connect.NewMockClient()
+	client.On("SetEnvVars", types.ContentID("test-content-id"), map[string]string{
+		"PLAIN_VAR": "plain-value",
+		"API_KEY":   "secret-value",
+	}, mock.Anything).Return(nil)
+
+	err := publisher.setEnvVars(client, types.ContentID("test-content-id"))
+	s.NoError(err)
+	client.AssertExpectations(s.T())
+
+	// Secret names are logged, but not their values.
+	logs := logBuffer.String()
+	s.Contains(logs, "API_KEY")
+	s.NotContains(logs, "secret-value")
+	s.Contains(logs, "plain-value")
+
+	eventData, err := json.Marshal(emitter.Events)
+	s.NoError(err)
+	s.NotContains(string(eventData), "secret-value")
+}
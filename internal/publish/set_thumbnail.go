@@ -0,0 +1,52 @@
+package publish
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"mime"
+
+	"github.com/posit-dev/publisher/internal/clients/connect"
+	"github.com/posit-dev/publisher/internal/events"
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/types"
+)
+
+type setThumbnailStartData struct {
+	Path string `mapstructure:"path"`
+}
+type setThumbnailSuccessData struct{}
+
+func (p *defaultPublisher) setThumbnail(
+	client connect.APIClient,
+	contentID types.ContentID) error {
+
+	filename := p.Config.ThumbnailFile
+	if filename == "" {
+		return nil
+	}
+
+	op := events.PublishSetThumbnailOp
+	log := p.log.WithArgs(logging.LogKeyOp, op)
+
+	p.emitter.Emit(events.New(op, events.StartPhase, events.NoError, setThumbnailStartData{
+		Path: filename,
+	}))
+	log.Info("Setting thumbnail image", "path", filename)
+
+	path := p.Dir.Join(filename)
+	f, err := path.Open()
+	if err != nil {
+		return types.OperationError(op, err)
+	}
+	defer f.Close()
+
+	imageType := mime.TypeByExtension(path.Ext())
+	err = client.SetThumbnail(contentID, f, imageType, log)
+	if err != nil {
+		return types.OperationError(op, err)
+	}
+
+	log.Info("Done setting thumbnail image")
+	p.emitter.Emit(events.New(op, events.SuccessPhase, events.NoError, setThumbnailSuccessData{}))
+	return nil
+}
@@ -0,0 +1,94 @@
+package publish
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/posit-dev/publisher/internal/clients/connect"
+	"github.com/posit-dev/publisher/internal/events"
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/state"
+	"github.com/posit-dev/publisher/internal/types"
+	"github.com/posit-dev/publisher/internal/util"
+	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type SetThumbnailSuite struct {
+	utiltest.Suite
+	stateStore *state.State
+	publisher  *defaultPublisher
+}
+
+func TestSetThumbnailSuite(t *testing.T) {
+	suite.Run(t, new(SetThumbnailSuite))
+}
+
+func (s *SetThumbnailSuite) SetupTest() {
+	s.stateStore = state.Empty()
+	s.stateStore.Dir = util.NewAbsolutePath("/project", afero.NewMemMapFs())
+	err := s.stateStore.Dir.MkdirAll(0777)
+	s.NoError(err)
+
+	s.publisher = &defaultPublisher{
+		State:   s.stateStore,
+		log:     logging.New(),
+		emitter: events.NewCapturingEmitter(),
+	}
+}
+
+func (s *SetThumbnailSuite) TestSetThumbnailNoThumbnail() {
+	client := connect.NewMockClient()
+
+	err := s.publisher.setThumbnail(client, types.ContentID("test-content-id"))
+	s.NoError(err)
+
+	// No calls to the Connect API to set the thumbnail should be made
+	s.Equal(0, len(client.Calls))
+}
+
+func (s *SetThumbnailSuite) TestSetThumbnail() {
+	err := s.stateStore.Dir.Join("thumbnail.png").WriteFile([]byte("not really a png"), 0666)
+	s.NoError(err)
+	s.stateStore.Config.ThumbnailFile = "thumbnail.png"
+
+	client := connect.NewMockClient()
+	client.On("SetThumbnail", types.ContentID("test-content-id"), mock.Anything, "image/png", mock.Anything).Return(nil)
+
+	err = s.publisher.setThumbnail(client, types.ContentID("test-content-id"))
+	s.NoError(err)
+
+	client.AssertExpectations(s.T())
+}
+
+func (s *SetThumbnailSuite) TestSetThumbnailMissingFile() {
+	s.stateStore.Config.ThumbnailFile = "thumbnail.png"
+
+	client := connect.NewMockClient()
+
+	err := s.publisher.setThumbnail(client, types.ContentID("test-content-id"))
+	s.ErrorContains(err, "thumbnail.png")
+	agentErr, ok := types.IsAgentError(err)
+	s.True(ok)
+	s.Equal(events.PublishSetThumbnailOp, agentErr.Op)
+
+	// The upload is not attempted
+	s.Equal(0, len(client.Calls))
+}
+
+func (s *SetThumbnailSuite) TestSetThumbnailErr() {
+	err := s.stateStore.Dir.Join("thumbnail.jpg").WriteFile([]byte("not really a jpeg"), 0666)
+	s.NoError(err)
+	s.stateStore.Config.ThumbnailFile = "thumbnail.jpg"
+
+	client := connect.NewMockClient()
+	testError := errors.New("test error from SetThumbnail")
+	client.On("SetThumbnail", types.ContentID("test-content-id"), mock.Anything, "image/jpeg", mock.Anything).Return(testError)
+
+	err = s.publisher.setThumbnail(client, types.ContentID("test-content-id"))
+	s.ErrorContains(err, testError.Error())
+}
@@ -0,0 +1,61 @@
+package publish
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"net/http"
+
+	"github.com/posit-dev/publisher/internal/clients/connect"
+	"github.com/posit-dev/publisher/internal/clients/http_client"
+	"github.com/posit-dev/publisher/internal/events"
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/types"
+)
+
+type setVanityURLStartData struct {
+	VanityURL string `mapstructure:"vanityUrl"`
+}
+type setVanityURLSuccessData struct{}
+
+type VanityURLNotAvailableErrorDetails struct {
+	ContentID types.ContentID `mapstructure:"contentId"`
+	VanityURL string          `mapstructure:"vanityUrl"`
+}
+
+func (p *defaultPublisher) setVanityURL(
+	client connect.APIClient,
+	contentID types.ContentID) error {
+
+	if p.Config.Connect == nil || p.Config.Connect.VanityURL == "" {
+		return nil
+	}
+	vanityURL := p.Config.Connect.VanityURL
+
+	op := events.PublishSetVanityUrlOp
+	log := p.log.WithArgs(logging.LogKeyOp, op)
+
+	p.emitter.Emit(events.New(op, events.StartPhase, events.NoError, setVanityURLStartData{
+		VanityURL: vanityURL,
+	}))
+	log.Info("Setting vanity URL", "vanity_url", vanityURL)
+
+	err := client.SetVanityURL(contentID, vanityURL, log)
+	if err != nil {
+		if _, isConflict := http_client.IsHTTPAgentErrorStatusOf(err, http.StatusConflict); isConflict {
+			// Another content item claimed the path
+			// after the configuration was checked.
+			details := VanityURLNotAvailableErrorDetails{
+				ContentID: contentID,
+				VanityURL: vanityURL,
+			}
+			agentErr := types.NewAgentError(events.VanityURLNotAvailableCode, err, details)
+			agentErr.SetOperation(op)
+			return agentErr
+		}
+		return types.OperationError(op, err)
+	}
+
+	log.Info("Done setting vanity URL")
+	p.emitter.Emit(events.New(op, events.SuccessPhase, events.NoError, setVanityURLSuccessData{}))
+	return nil
+}
@@ -0,0 +1,93 @@
+package publish
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/posit-dev/publisher/internal/clients/connect"
+	"github.com/posit-dev/publisher/internal/clients/http_client"
+	"github.com/posit-dev/publisher/internal/config"
+	"github.com/posit-dev/publisher/internal/events"
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/state"
+	"github.com/posit-dev/publisher/internal/types"
+	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type SetVanityURLSuite struct {
+	utiltest.Suite
+	stateStore *state.State
+	publisher  *defaultPublisher
+}
+
+func TestSetVanityURLSuite(t *testing.T) {
+	suite.Run(t, new(SetVanityURLSuite))
+}
+
+func (s *SetVanityURLSuite) SetupTest() {
+	s.stateStore = state.Empty()
+	s.publisher = &defaultPublisher{
+		State:   s.stateStore,
+		log:     logging.New(),
+		emitter: events.NewCapturingEmitter(),
+	}
+}
+
+func (s *SetVanityURLSuite) TestSetVanityURLNotConfigured() {
+	client := connect.NewMockClient()
+
+	err := s.publisher.setVanityURL(client, types.ContentID("test-content-id"))
+	s.NoError(err)
+	s.Equal(0, len(client.Calls))
+}
+
+func (s *SetVanityURLSuite) TestSetVanityURL() {
+	s.stateStore.Config.Connect = &config.Connect{
+		VanityURL: "/sales/dashboard/",
+	}
+	client := connect.NewMockClient()
+	client.On("SetVanityURL", types.ContentID("test-content-id"), "/sales/dashboard/", mock.Anything).Return(nil)
+
+	err := s.publisher.setVanityURL(client, types.ContentID("test-content-id"))
+	s.NoError(err)
+	client.AssertExpectations(s.T())
+}
+
+func (s *SetVanityURLSuite) TestSetVanityURLTaken() {
+	// The path was available when the configuration was checked,
+	// but another content item claimed it before it was set.
+	s.stateStore.Config.Connect = &config.Connect{
+		VanityURL: "/sales/dashboard/",
+	}
+	httpErr := http_client.NewHTTPError("https://connect.example.com/__api__/v1/content/test-content-id/vanity", "PUT", http.StatusConflict)
+	client := connect.NewMockClient()
+	client.On("SetVanityURL", types.ContentID("test-content-id"), "/sales/dashboard/", mock.Anything).Return(
+		types.NewAgentError(events.ServerErrorCode, httpErr, nil))
+
+	err := s.publisher.setVanityURL(client, types.ContentID("test-content-id"))
+	agentErr, ok := types.IsAgentError(err)
+	s.True(ok)
+	s.Equal(events.VanityURLNotAvailableCode, agentErr.Code)
+	s.Equal(events.PublishSetVanityUrlOp, agentErr.Op)
+	s.Equal("/sales/dashboard/", agentErr.Data["vanityUrl"])
+}
+
+func (s *SetVanityURLSuite) TestSetVanityURLErr() {
+	s.stateStore.Config.Connect = &config.Connect{
+		VanityURL: "/sales/dashboard/",
+	}
+	httpErr := http_client.NewHTTPError("https://connect.example.com/__api__/v1/content/test-content-id/vanity", "PUT", http.StatusForbidden)
+	client := connect.NewMockClient()
+	client.On("SetVanityURL", types.ContentID("test-content-id"), "/sales/dashboard/", mock.Anything).Return(
+		types.NewAgentError(events.PermissionsCode, httpErr, nil))
+
+	err := s.publisher.setVanityURL(client, types.ContentID("test-content-id"))
+	agentErr, ok := types.IsAgentError(err)
+	s.True(ok)
+	s.Equal(events.PermissionsCode, agentErr.Code)
+	s.Equal(events.PublishSetVanityUrlOp, agentErr.Op)
+}
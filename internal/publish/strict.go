@@ -0,0 +1,74 @@
+package publish
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/posit-dev/publisher/internal/config"
+	"github.com/posit-dev/publisher/internal/events"
+	"github.com/posit-dev/publisher/internal/inspect"
+	"github.com/posit-dev/publisher/internal/types"
+	"github.com/posit-dev/publisher/internal/util"
+)
+
+type strictModeDetails struct {
+	Warnings []string `mapstructure:"warnings"`
+}
+
+var pythonInspectorFactory = inspect.NewPythonInspector
+
+// collectWarnings returns the warnings about this deployment.
+// Normally they are only reported; in strict mode they
+// prevent deployment.
+func (p *defaultPublisher) collectWarnings() []string {
+	warnings := []string{}
+	for _, warning := range p.Config.Lint(p.Dir) {
+		warnings = append(warnings, warning.Message)
+	}
+	if p.Config.Type == config.ContentTypeUnknown {
+		warnings = append(warnings, "the content type is unknown; set the type in the configuration")
+	}
+	if p.Account.Insecure {
+		warnings = append(warnings, fmt.Sprintf(
+			"TLS certificate verification is disabled for account %s; the connection to the server is not secure",
+			p.Account.Name))
+	}
+	if p.Config.Python != nil {
+		packageFile := p.Config.Python.PackageFile
+		if packageFile == "" {
+			packageFile = inspect.PythonRequirementsFilename
+		}
+		python := util.NewPath(p.Config.Python.Executable, nil)
+		inspector := pythonInspectorFactory(p.Dir, python, p.log)
+		scan, err := inspector.ScanRequirements(p.Dir, packageFile, time.Time{})
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("could not check the Python requirements for unused packages: %s", err))
+		} else if len(scan.Unused) > 0 {
+			warnings = append(warnings, fmt.Sprintf(
+				"%s lists packages that are not imported by the project: %s",
+				packageFile,
+				strings.Join(scan.Unused, ", ")))
+		}
+	}
+	return warnings
+}
+
+// checkStrictMode returns an error listing all of the
+// warnings, if there are any.
+func (p *defaultPublisher) checkStrictMode() error {
+	warnings := p.collectWarnings()
+	if len(warnings) == 0 {
+		return nil
+	}
+	msg := fmt.Sprintf("strict mode is enabled and there are %d warnings:\n- %s",
+		len(warnings),
+		strings.Join(warnings, "\n- "))
+	err := types.NewAgentError(types.ErrorStrictModeWarnings, errors.New(msg), strictModeDetails{
+		Warnings: warnings,
+	})
+	return types.OperationError(events.PublishCheckCapabilitiesOp, err)
+}
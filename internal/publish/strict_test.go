@@ -0,0 +1,125 @@
+package publish
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"time"
+
+	"github.com/posit-dev/publisher/internal/accounts"
+	"github.com/posit-dev/publisher/internal/clients/connect"
+	"github.com/posit-dev/publisher/internal/config"
+	"github.com/posit-dev/publisher/internal/events"
+	"github.com/posit-dev/publisher/internal/inspect"
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/types"
+	"github.com/posit-dev/publisher/internal/util"
+	"github.com/stretchr/testify/mock"
+)
+
+// publishStrict deploys the project directory with a mock client,
+// using an insecure account and a package file with an unused
+// package, which are warnings.
+func (s *PublishSuite) publishStrict(strict bool, unused []string) (*connect.MockClient, error) {
+	s.NoError(s.cwd.Join("requirements-prod.txt").WriteFile([]byte("flask\npandas\n"), 0600))
+	cfg := config.New()
+	cfg.Type = config.ContentTypePythonFlask
+	cfg.Entrypoint = "app.py"
+	cfg.Files = []string{"/app.py", "/requirements-prod.txt"}
+	cfg.Python = &config.Python{
+		Version:        "3.11.3",
+		Executable:     "/opt/python/3.11.3/bin/python3",
+		PackageManager: "pip",
+		PackageFile:    "requirements-prod.txt",
+	}
+
+	client := newBundleClient()
+	clientFactory = func(*accounts.Account, time.Duration, events.Emitter, logging.Logger) (connect.APIClient, error) {
+		return client, nil
+	}
+	pythonInspectorFactory = func(_ util.AbsolutePath, python util.Path, _ logging.Logger) inspect.PythonInspector {
+		s.Equal(cfg.Python.Executable, python.String())
+		i := inspect.NewMockPythonInspector()
+		i.On("ScanRequirements", s.cwd, "requirements-prod.txt", mock.Anything).Return(&inspect.RequirementsScan{
+			Requirements: []string{"flask==3.0.0"},
+			Unused:       unused,
+		}, nil)
+		return i
+	}
+	defer func() {
+		clientFactory = connect.NewConnectClient
+		pythonInspectorFactory = inspect.NewPythonInspector
+	}()
+
+	publisher := s.newBundlePublisher(cfg)
+	publisher.Account.Name = "myAccount"
+	publisher.Account.Insecure = true
+	publisher.Strict = strict
+	return client, publisher.PublishDirectory()
+}
+
+func (s *PublishSuite) TestPublishWarningsNotStrict() {
+	client, err := s.publishStrict(false, []string{"pandas"})
+	s.NoError(err)
+	client.AssertCalled(s.T(), "CreateDeployment", mock.Anything, mock.Anything)
+}
+
+func (s *PublishSuite) TestPublishStrictBlocksWarnings() {
+	client, err := s.publishStrict(true, []string{"pandas"})
+	s.Error(err)
+
+	agentErr, ok := err.(*types.AgentError)
+	s.True(ok)
+	s.Equal(types.ErrorStrictModeWarnings, agentErr.Code)
+	s.Equal(events.PublishCheckCapabilitiesOp, agentErr.Op)
+	s.Contains(agentErr.Message, "there are 2 warnings")
+	s.Equal([]string{
+		"TLS certificate verification is disabled for account myAccount; the connection to the server is not secure",
+		"requirements-prod.txt lists packages that are not imported by the project: pandas",
+	}, agentErr.Data["warnings"])
+
+	// Nothing was deployed.
+	client.AssertNotCalled(s.T(), "TestAuthentication", mock.Anything)
+	client.AssertNotCalled(s.T(), "CreateDeployment", mock.Anything, mock.Anything)
+}
+
+func (s *PublishSuite) TestCollectWarnings() {
+	cfg := config.New()
+	cfg.Type = config.ContentTypeUnknown
+	cfg.Entrypoint = "app.py"
+	cfg.Files = []string{"requirements.txt"}
+
+	publisher := s.newBundlePublisher(cfg)
+	s.Equal([]string{
+		"the entrypoint app.py is not included in the files list",
+		"the content type is unknown; set the type in the configuration",
+	}, publisher.collectWarnings())
+}
+
+func (s *PublishSuite) TestPublishEntrypointCaseMismatch() {
+	cfg := config.New()
+	cfg.Type = config.ContentTypePythonFlask
+	cfg.Entrypoint = "App.py"
+	cfg.Files = []string{"/app.py", "/requirements.txt"}
+
+	client := newBundleClient()
+	clientFactory = func(*accounts.Account, time.Duration, events.Emitter, logging.Logger) (connect.APIClient, error) {
+		return client, nil
+	}
+	defer func() {
+		clientFactory = connect.NewConnectClient
+	}()
+
+	publisher := s.newBundlePublisher(cfg)
+	err := publisher.PublishDirectory()
+	s.Error(err)
+
+	agentErr, ok := err.(*types.AgentError)
+	s.True(ok)
+	s.Equal(types.ErrorEntrypointCaseMismatch, agentErr.Code)
+	s.Contains(agentErr.Message, `set entrypoint = "app.py"`)
+	s.Equal("App.py", agentErr.Data["entrypoint"])
+	s.Equal("app.py", agentErr.Data["filename"])
+
+	// Nothing was deployed.
+	client.AssertNotCalled(s.T(), "CreateDeployment", mock.Anything, mock.Anything)
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/posit-dev/publisher/internal/clients/connect"
 	"github.com/posit-dev/publisher/internal/clients/http_client"
+	"github.com/posit-dev/publisher/internal/config"
 	"github.com/posit-dev/publisher/internal/events"
 	"github.com/posit-dev/publisher/internal/logging"
 	"github.com/posit-dev/publisher/internal/types"
@@ -24,7 +25,8 @@ type DeploymentNotFoundErrorDetails struct {
 
 func (p *defaultPublisher) updateContent(
 	client connect.APIClient,
-	contentID types.ContentID) error {
+	contentID types.ContentID,
+	created bool) error {
 
 	op := events.PublishUpdateDeploymentOp
 	log := p.log.WithArgs(logging.LogKeyOp, op)
@@ -36,6 +38,13 @@ func (p *defaultPublisher) updateContent(
 	log.Info("Updating deployment settings", "content_id", contentID, "save_name", p.SaveName)
 
 	connectContent := connect.ConnectContentFromConfig(p.Config)
+	if created {
+		accessType := p.initialAccessType()
+		if accessType != "" {
+			log.Info("Setting initial access type", "access_type", accessType)
+			connectContent.AccessType = string(accessType)
+		}
+	}
 	err := client.UpdateDeployment(contentID, connectContent, log)
 	if err != nil {
 		httpErr, ok := err.(*http_client.HTTPError)
@@ -53,3 +62,12 @@ func (p *defaultPublisher) updateContent(
 	p.emitter.Emit(events.New(op, events.SuccessPhase, events.NoError, updateContentSuccessData{}))
 	return nil
 }
+
+// initialAccessType returns the configured access type
+// for newly created content, if any.
+func (p *defaultPublisher) initialAccessType() config.AccessType {
+	if p.Config.Connect == nil || p.Config.Connect.Access == nil {
+		return ""
+	}
+	return p.Config.Connect.Access.InitialAccessType
+}
@@ -0,0 +1,72 @@
+package publish
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"testing"
+
+	"github.com/posit-dev/publisher/internal/clients/connect"
+	"github.com/posit-dev/publisher/internal/config"
+	"github.com/posit-dev/publisher/internal/events"
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/state"
+	"github.com/posit-dev/publisher/internal/types"
+	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type UpdateContentSuite struct {
+	utiltest.Suite
+}
+
+func TestUpdateContentSuite(t *testing.T) {
+	suite.Run(t, new(UpdateContentSuite))
+}
+
+// updateContent runs updateContent with the initial access type
+// configured, and returns the content settings sent to the server.
+func (s *UpdateContentSuite) updateContent(accessType config.AccessType, created bool) *connect.ConnectContent {
+	stateStore := state.Empty()
+	stateStore.Config.Connect = &config.Connect{
+		Access: &config.ConnectAccess{
+			InitialAccessType: accessType,
+		},
+	}
+	publisher := &defaultPublisher{
+		State:   stateStore,
+		log:     logging.New(),
+		emitter: events.NewCapturingEmitter(),
+	}
+
+	var sent *connect.ConnectContent
+	client := connect.NewMockClient()
+	client.On("UpdateDeployment", types.ContentID("myContentID"), mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		sent = args.Get(1).(*connect.ConnectContent)
+	})
+	err := publisher.updateContent(client, "myContentID", created)
+	s.NoError(err)
+	return sent
+}
+
+func (s *UpdateContentSuite) TestInitialAccessType() {
+	for _, accessType := range []config.AccessType{
+		config.AccessTypeACL,
+		config.AccessTypeLoggedIn,
+		config.AccessTypeAnonymous,
+	} {
+		sent := s.updateContent(accessType, true)
+		s.Equal(string(accessType), sent.AccessType)
+	}
+}
+
+func (s *UpdateContentSuite) TestInitialAccessTypeExistingContent() {
+	// Access settings changed on the server are kept.
+	sent := s.updateContent(config.AccessTypeAnonymous, false)
+	s.Equal("", sent.AccessType)
+}
+
+func (s *UpdateContentSuite) TestNoInitialAccessType() {
+	sent := s.updateContent("", true)
+	s.Equal("", sent.AccessType)
+}
@@ -7,6 +7,7 @@ import (
 	"embed"
 	"fmt"
 	"io"
+	"regexp"
 	"strings"
 
 	"github.com/posit-dev/publisher/internal/types"
@@ -44,6 +45,9 @@ type tomlValidationError struct {
 	Key             string `mapstructure:"key"`
 	Problem         string `mapstructure:"problem"`
 	SchemaReference string `mapstructure:"schema-reference"`
+
+	// All of the problems found; Key and Problem describe the first.
+	causes []*jsonschema.ValidationError
 }
 
 func (e *tomlValidationError) Error() string {
@@ -54,17 +58,19 @@ func toTomlValidationError(e *jsonschema.ValidationError) *tomlValidationError {
 	if len(e.Causes) != 0 {
 		e = e.Causes[0]
 	}
-	key := e.InstanceLocation
-	key = strings.TrimPrefix(key, "/")
-	key = strings.ReplaceAll(key, "/", ".")
-
 	return &tomlValidationError{
-		Key:             key,
+		Key:             keyFromLocation(e.InstanceLocation),
 		Problem:         e.Message,
 		SchemaReference: e.AbsoluteKeywordLocation,
 	}
 }
 
+// keyFromLocation converts a JSON pointer to a dotted TOML key.
+func keyFromLocation(location string) string {
+	key := strings.TrimPrefix(location, "/")
+	return strings.ReplaceAll(key, "/", ".")
+}
+
 func (v *Validator[T]) ValidateContent(data any) error {
 	err := v.schema.Validate(data)
 	if err != nil {
@@ -72,6 +78,7 @@ func (v *Validator[T]) ValidateContent(data any) error {
 		if ok {
 			// Return all causes in the Data field of a single error.
 			e := toTomlValidationError(validationErr)
+			e.causes = leafCauses(validationErr)
 			return types.NewAgentError(tomlValidationErrorCode, e, e)
 		} else {
 			return err
@@ -81,13 +88,58 @@ func (v *Validator[T]) ValidateContent(data any) error {
 }
 
 func (v *Validator[T]) ValidateTOMLFile(path util.AbsolutePath) error {
+	anyContent, err := v.readTOMLFile(path)
+	if err != nil {
+		return err
+	}
+	return v.ValidateContent(anyContent)
+}
+
+// ValidatePartialContent validates the data like ValidateContent,
+// but allows required properties to be missing. Anything that
+// is present must still be valid.
+func (v *Validator[T]) ValidatePartialContent(data any) error {
+	err := v.schema.Validate(data)
+	if err != nil {
+		validationErr, ok := err.(*jsonschema.ValidationError)
+		if ok {
+			var causes []*jsonschema.ValidationError
+			for _, cause := range leafCauses(validationErr) {
+				if !isMissingRequired(cause) {
+					causes = append(causes, cause)
+				}
+			}
+			if len(causes) == 0 {
+				return nil
+			}
+			e := toTomlValidationError(causes[0])
+			e.causes = causes
+			return types.NewAgentError(tomlValidationErrorCode, e, e)
+		} else {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidatePartialTOMLFile validates the file like ValidateTOMLFile,
+// but allows required properties to be missing.
+func (v *Validator[T]) ValidatePartialTOMLFile(path util.AbsolutePath) error {
+	anyContent, err := v.readTOMLFile(path)
+	if err != nil {
+		return err
+	}
+	return v.ValidatePartialContent(anyContent)
+}
+
+func (v *Validator[T]) readTOMLFile(path util.AbsolutePath) (any, error) {
 	// First, try to read the TOML into the object.
 	// This will return nicer errors from the toml package
 	// for things like fields that cannot be mapped.
 	var typedContent T
 	err := util.ReadTOMLFile(path, &typedContent)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	// Read the TOML generically to get the anyContent.
 	// Can't use v.object here because Validate
@@ -95,9 +147,65 @@ func (v *Validator[T]) ValidateTOMLFile(path util.AbsolutePath) error {
 	var anyContent any
 	err = util.ReadTOMLFile(path, &anyContent)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return v.ValidateContent(anyContent)
+	return anyContent, nil
+}
+
+// leafCauses returns the innermost errors that caused e.
+func leafCauses(e *jsonschema.ValidationError) []*jsonschema.ValidationError {
+	if len(e.Causes) == 0 {
+		return []*jsonschema.ValidationError{e}
+	}
+	var leaves []*jsonschema.ValidationError
+	for _, cause := range e.Causes {
+		leaves = append(leaves, leafCauses(cause)...)
+	}
+	return leaves
+}
+
+func isMissingRequired(e *jsonschema.ValidationError) bool {
+	return strings.HasSuffix(e.KeywordLocation, "/required")
+}
+
+var quotedNameRE = regexp.MustCompile(`'([^']*)'`)
+
+// FieldErrors lists each of the problems found by ValidateContent
+// or ValidatePartialContent, with one entry per field. It returns nil
+// if err is not a schema validation error.
+func FieldErrors(err error) []types.FieldError {
+	aerr, ok := types.IsAgentErrorOf(err, tomlValidationErrorCode)
+	if !ok {
+		return nil
+	}
+	e, ok := aerr.Err.(*tomlValidationError)
+	if !ok {
+		return nil
+	}
+	fieldErrors := []types.FieldError{}
+	for _, cause := range e.causes {
+		key := keyFromLocation(cause.InstanceLocation)
+		if isMissingRequired(cause) {
+			// The validator reports all of the missing properties
+			// of an object together; report them separately.
+			for _, match := range quotedNameRE.FindAllStringSubmatch(cause.Message, -1) {
+				field := match[1]
+				if key != "" {
+					field = key + "." + field
+				}
+				fieldErrors = append(fieldErrors, types.FieldError{
+					Field:   field,
+					Message: "missing required property",
+				})
+			}
+			continue
+		}
+		fieldErrors = append(fieldErrors, types.FieldError{
+			Field:   key,
+			Message: cause.Message,
+		})
+	}
+	return fieldErrors
 }
 
 func loadSchema(url string) (io.ReadCloser, error) {
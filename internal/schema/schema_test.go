@@ -2,6 +2,7 @@ package schema
 
 // Copyright (C) 2023 by Posit Software, PBC.
 import (
+	"errors"
 	"testing"
 
 	"github.com/posit-dev/publisher/internal/types"
@@ -80,3 +81,52 @@ func (s *SchemaSuite) TestValidationError() {
 	s.True(ok)
 	s.Equal(agentErr.Code, tomlValidationErrorCode)
 }
+
+func (s *SchemaSuite) TestValidatePartialContent() {
+	validator, err := NewValidator[genericContent](ConfigSchemaURL)
+	s.NoError(err)
+
+	// Missing required properties are allowed.
+	partial := map[string]any{
+		"title":  "Work in progress",
+		"python": map[string]any{},
+	}
+	s.NoError(validator.ValidatePartialContent(partial))
+	s.Error(validator.ValidateContent(partial))
+
+	// Invalid values are not.
+	partial["type"] = "this-is-not-valid"
+	err = validator.ValidatePartialContent(partial)
+	agentErr, ok := err.(*types.AgentError)
+	s.True(ok)
+	s.Equal(agentErr.Code, tomlValidationErrorCode)
+	s.Equal("type", agentErr.Data["key"])
+}
+
+func (s *SchemaSuite) TestFieldErrors() {
+	validator, err := NewValidator[genericContent](ConfigSchemaURL)
+	s.NoError(err)
+
+	content := map[string]any{
+		"$schema":    ConfigSchemaURL,
+		"type":       "html",
+		"validate":   "yes",
+		"files":      "*",
+		"connect":    map[string]any{"runtime": map[string]any{"max_processes": "many"}},
+		"quarto":     map[string]any{},
+		"entrypoint": "index.html",
+	}
+	err = validator.ValidateContent(content)
+	s.ElementsMatch([]types.FieldError{
+		{Field: "validate", Message: "expected boolean, but got string"},
+		{Field: "files", Message: "expected array, but got string"},
+		{Field: "connect.runtime.max_processes", Message: "expected integer, but got string"},
+		{Field: "quarto.version", Message: "missing required property"},
+	}, FieldErrors(err))
+
+	// Partial validation doesn't report missing properties.
+	err = validator.ValidatePartialContent(content)
+	s.Len(FieldErrors(err), 3)
+
+	s.Nil(FieldErrors(errors.New("not a validation error")))
+}
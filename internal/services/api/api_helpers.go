@@ -3,17 +3,27 @@ package api
 // Copyright (C) 2023 by Posit Software, PBC.
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"html"
 	"net/http"
+	"path/filepath"
+	"strings"
 
 	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/types"
 	"github.com/posit-dev/publisher/internal/util"
 )
 
 func InternalError(w http.ResponseWriter, req *http.Request, log logging.Logger, err error) {
+	if isRequestTooLarge(err) {
+		RequestTooLarge(w, req, log, err)
+		return
+	}
 	status := http.StatusInternalServerError
 	text := html.EscapeString(err.Error())
 	w.Header().Add("Content-Type", "text/plain")
@@ -22,6 +32,25 @@ func InternalError(w http.ResponseWriter, req *http.Request, log logging.Logger,
 	log.Error(text, "method", req.Method, "url", req.URL.String(), "error", err)
 }
 
+// InspectionError returns an error response for a failed inspection.
+// Missing tools, like Python or Quarto, are reported with their
+// own error types so the client can tell the user how to fix them.
+func InspectionError(w http.ResponseWriter, req *http.Request, log logging.Logger, err error) {
+	if aerr, ok := types.IsAgentErrorOf(err, types.ErrorPythonExecNotFound); ok {
+		apiErr := types.APIErrorPythonExecNotFoundFromAgentError(*aerr)
+		log.Error("Python executable not found", "error", err.Error())
+		apiErr.JSONResponse(w)
+		return
+	}
+	if aerr, ok := types.IsAgentErrorOf(err, types.ErrorQuartoNotFound); ok {
+		apiErr := types.APIErrorQuartoNotFoundFromAgentError(*aerr)
+		log.Error("Quarto not found", "error", err.Error())
+		apiErr.JSONResponse(w)
+		return
+	}
+	InternalError(w, req, log, err)
+}
+
 func MethodNotAllowed(w http.ResponseWriter, req *http.Request, log logging.Logger) {
 	status := http.StatusMethodNotAllowed
 	text := http.StatusText(status)
@@ -31,6 +60,10 @@ func MethodNotAllowed(w http.ResponseWriter, req *http.Request, log logging.Logg
 }
 
 func BadRequest(w http.ResponseWriter, req *http.Request, log logging.Logger, err error) {
+	if isRequestTooLarge(err) {
+		RequestTooLarge(w, req, log, err)
+		return
+	}
 	status := http.StatusBadRequest
 	text := http.StatusText(status)
 	w.WriteHeader(status)
@@ -38,6 +71,22 @@ func BadRequest(w http.ResponseWriter, req *http.Request, log logging.Logger, er
 	log.Error(text, "method", req.Method, "url", req.URL.String(), "error", err)
 }
 
+// RequestTooLarge responds with a 413. Handlers don't usually need
+// to call it; BadRequest and InternalError use it when reading
+// the request body failed because the body is over the size limit.
+func RequestTooLarge(w http.ResponseWriter, req *http.Request, log logging.Logger, err error) {
+	status := http.StatusRequestEntityTooLarge
+	text := http.StatusText(status)
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "%s: %s\n", text, err.Error())
+	log.Error(text, "method", req.Method, "url", req.URL.String(), "error", err)
+}
+
+func isRequestTooLarge(err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	return errors.As(err, &maxBytesErr)
+}
+
 func NotFound(w http.ResponseWriter, log logging.Logger, err error) {
 	msg := err.Error()
 	log.Error(msg)
@@ -50,14 +99,78 @@ func JsonResult(w http.ResponseWriter, status int, result any) {
 	json.NewEncoder(w).Encode(result)
 }
 
+// JsonResultWithETag writes a successful JSON result like JsonResult,
+// with an ETag header computed from the response body. If the request's
+// If-None-Match header shows that the client already has this version,
+// it returns 304 Not Modified without a body instead.
+func JsonResultWithETag(w http.ResponseWriter, req *http.Request, log logging.Logger, result any) {
+	var body bytes.Buffer
+	err := json.NewEncoder(&body).Encode(result)
+	if err != nil {
+		InternalError(w, req, log, err)
+		return
+	}
+	sum := sha256.Sum256(body.Bytes())
+	etag := `"` + hex.EncodeToString(sum[:]) + `"`
+	w.Header().Set("ETag", etag)
+	if etagMatches(req.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("content-type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(body.Bytes())
+}
+
+// etagMatches returns true if the If-None-Match header value
+// includes the etag. Weak comparison is used, as specified for
+// If-None-Match by RFC 9110.
+func etagMatches(ifNoneMatch string, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		if strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
 var errProjectDirNotFound = errors.New("project directory not found")
+var errProjectDirNotDirectory = errors.New("project directory is not a directory")
+var errConflictingProjectDirs = fmt.Errorf("the dir query parameter and %s header specify different directories", ProjectDirHeader)
 
-// ProjectDirFromRequest returns the project directory from the request query parameter "dir".
+// ProjectDirHeader is an alternative to the "dir" query parameter,
+// for clients that would rather not add it to every URL.
+const ProjectDirHeader = "X-Project-Dir"
+
+// projectDirParam returns the project subdirectory requested by the client,
+// from either the "dir" query parameter or the X-Project-Dir header.
+func projectDirParam(req *http.Request) (string, error) {
+	dir := req.URL.Query().Get("dir")
+	headerDir := req.Header.Get(ProjectDirHeader)
+	if headerDir == "" {
+		return dir, nil
+	}
+	if dir != "" && filepath.Clean(dir) != filepath.Clean(headerDir) {
+		return "", errConflictingProjectDirs
+	}
+	return headerDir, nil
+}
+
+// ProjectDirFromRequest returns the project directory from the request query parameter "dir",
+// or the X-Project-Dir header. If both are present, they must agree.
 // If the directory does not exist, it returns a 404.
 // If the directory is not a subdirectory of the base directory, it returns a 400.
 // Other errors return a 500.
 func ProjectDirFromRequest(base util.AbsolutePath, w http.ResponseWriter, req *http.Request, log logging.Logger) (util.AbsolutePath, util.RelativePath, error) {
-	dir := req.URL.Query().Get("dir")
+	dir, err := projectDirParam(req)
+	if err != nil {
+		BadRequest(w, req, log, err)
+		return util.AbsolutePath{}, util.RelativePath{}, err
+	}
 	log.Debug("Picking directory from request", "directory", dir)
 	projectDir, err := base.SafeJoin(dir)
 	if err != nil {
@@ -74,6 +187,16 @@ func ProjectDirFromRequest(base util.AbsolutePath, w http.ResponseWriter, req *h
 		NotFound(w, log, err)
 		return util.AbsolutePath{}, util.RelativePath{}, err
 	}
+	isDir, err := projectDir.IsDir()
+	if err != nil {
+		InternalError(w, req, log, err)
+		return util.AbsolutePath{}, util.RelativePath{}, err
+	}
+	if !isDir {
+		err = errProjectDirNotDirectory
+		BadRequest(w, req, log, err)
+		return util.AbsolutePath{}, util.RelativePath{}, err
+	}
 	// We will return a normalized version of the project directory
 	relProjectDir, err := projectDir.Rel(base)
 	if err != nil {
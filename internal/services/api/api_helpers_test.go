@@ -0,0 +1,101 @@
+package api
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/util"
+	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/suite"
+)
+
+type ApiHelpersSuite struct {
+	utiltest.Suite
+	log  logging.Logger
+	base util.AbsolutePath
+}
+
+func TestApiHelpersSuite(t *testing.T) {
+	suite.Run(t, new(ApiHelpersSuite))
+}
+
+func (s *ApiHelpersSuite) SetupTest() {
+	s.log = logging.New()
+	base, err := util.Getwd(afero.NewMemMapFs())
+	s.NoError(err)
+	s.base = base
+	s.NoError(s.base.Join("subproject", "subdir").MkdirAll(0777))
+	s.NoError(s.base.Join("app.py").WriteFile(nil, 0666))
+}
+
+func (s *ApiHelpersSuite) projectDir(url string, header string) (util.AbsolutePath, util.RelativePath, int) {
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", url, nil)
+	s.NoError(err)
+	if header != "" {
+		req.Header.Set(ProjectDirHeader, header)
+	}
+	projectDir, relProjectDir, err := ProjectDirFromRequest(s.base, rec, req, s.log)
+	if err != nil {
+		return projectDir, relProjectDir, rec.Result().StatusCode
+	}
+	return projectDir, relProjectDir, http.StatusOK
+}
+
+func (s *ApiHelpersSuite) TestProjectDirDefault() {
+	projectDir, relProjectDir, status := s.projectDir("/api/configurations", "")
+	s.Equal(http.StatusOK, status)
+	s.Equal(s.base, projectDir)
+	s.Equal(".", relProjectDir.String())
+}
+
+func (s *ApiHelpersSuite) TestProjectDirQuery() {
+	projectDir, relProjectDir, status := s.projectDir("/api/configurations?dir=subproject/subdir", "")
+	s.Equal(http.StatusOK, status)
+	s.Equal(s.base.Join("subproject", "subdir"), projectDir)
+	s.Equal("subproject/subdir", relProjectDir.ToSlash())
+}
+
+func (s *ApiHelpersSuite) TestProjectDirHeader() {
+	projectDir, relProjectDir, status := s.projectDir("/api/configurations", "subproject/subdir")
+	s.Equal(http.StatusOK, status)
+	s.Equal(s.base.Join("subproject", "subdir"), projectDir)
+	s.Equal("subproject/subdir", relProjectDir.ToSlash())
+}
+
+func (s *ApiHelpersSuite) TestProjectDirQueryAndHeaderAgree() {
+	projectDir, _, status := s.projectDir("/api/configurations?dir=subproject/subdir/", "subproject/subdir")
+	s.Equal(http.StatusOK, status)
+	s.Equal(s.base.Join("subproject", "subdir"), projectDir)
+}
+
+func (s *ApiHelpersSuite) TestProjectDirQueryAndHeaderConflict() {
+	_, _, status := s.projectDir("/api/configurations?dir=subproject", "subproject/subdir")
+	s.Equal(http.StatusBadRequest, status)
+}
+
+func (s *ApiHelpersSuite) TestProjectDirEscapesBase() {
+	_, _, status := s.projectDir("/api/configurations?dir=../other", "")
+	s.Equal(http.StatusBadRequest, status)
+
+	_, _, status = s.projectDir("/api/configurations", "../other")
+	s.Equal(http.StatusBadRequest, status)
+
+	_, _, status = s.projectDir("/api/configurations", "subproject/../../other")
+	s.Equal(http.StatusBadRequest, status)
+}
+
+func (s *ApiHelpersSuite) TestProjectDirNotFound() {
+	_, _, status := s.projectDir("/api/configurations", "nonexistent")
+	s.Equal(http.StatusNotFound, status)
+}
+
+func (s *ApiHelpersSuite) TestProjectDirNotDirectory() {
+	_, _, status := s.projectDir("/api/configurations", "app.py")
+	s.Equal(http.StatusBadRequest, status)
+}
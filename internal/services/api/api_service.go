@@ -8,9 +8,10 @@ import (
 
 	"github.com/posit-dev/publisher/internal/accounts"
 	"github.com/posit-dev/publisher/internal/events"
+	"github.com/posit-dev/publisher/internal/inspect/detectors"
 	"github.com/posit-dev/publisher/internal/logging"
 	"github.com/posit-dev/publisher/internal/services/api/files"
-	"github.com/posit-dev/publisher/internal/services/api/paths"
+	"github.com/posit-dev/publisher/internal/services/middleware"
 	"github.com/posit-dev/publisher/internal/util"
 	"github.com/rs/cors"
 
@@ -27,6 +28,9 @@ func NewService(
 	theme string,
 	listen string,
 	accessLog bool,
+	compress bool,
+	maxRequestBodySize int64,
+	maxConcurrentOperations int,
 	tlsKeyFile string,
 	tlsCertFile string,
 	dir util.AbsolutePath,
@@ -35,7 +39,9 @@ func NewService(
 	eventServer *sse.Server,
 	emitter events.Emitter) *Service {
 
-	handler := RouterHandlerFunc(dir, lister, log, eventServer, emitter)
+	limiter := middleware.NewConcurrencyLimiter(maxConcurrentOperations, middleware.DefaultConcurrencyWait)
+	deployLimiter := middleware.NewConcurrencyLimiter(maxConcurrentOperations, middleware.DefaultConcurrencyWait)
+	handler := RouterHandlerFunc(dir, lister, limiter, deployLimiter, log, eventServer, emitter)
 
 	return newHTTPService(
 		handler,
@@ -46,19 +52,41 @@ func NewService(
 		interactive,
 		openBrowserAt,
 		accessLog,
+		compress,
+		maxRequestBodySize,
 		log,
 	)
 }
 
-func RouterHandlerFunc(base util.AbsolutePath, lister accounts.AccountList, log logging.Logger, eventServer *sse.Server, emitter events.Emitter) http.HandlerFunc {
-	filesService := files.CreateFilesService(base, log)
-	pathsService := paths.CreatePathsService(base, log)
+// apiSymlinkPolicy is the symlink policy used for requests from the UI.
+// Symlinks that point outside the project directory are refused so
+// that unrelated files can't be included by accident.
+const apiSymlinkPolicy = util.SymlinkContain
+
+// RouterHandlerFunc returns the API handler. Expensive requests
+// (file walks and dependency scans) share the limiter. Deployments
+// hold a slot until publishing finishes, so they have their own
+// limiter and can't starve the requests the UI is waiting on.
+func RouterHandlerFunc(base util.AbsolutePath, lister accounts.AccountList, limiter *middleware.ConcurrencyLimiter, deployLimiter *middleware.ConcurrencyLimiter, log logging.Logger, eventServer *sse.Server, emitter events.Emitter) http.HandlerFunc {
+	filesService := files.CreateFilesService(base, apiSymlinkPolicy, log)
+	// The UI inspects projects repeatedly, so keep
+	// the results until the project changes.
+	detectionCache := detectors.NewDetectionCache()
+	runningDeployments := NewRunningDeployments()
 
 	r := mux.NewRouter()
 	// GET /api/accounts
 	r.Handle(ToPath("accounts"), GetAccountsHandlerFunc(lister, log)).
 		Methods(http.MethodGet)
 
+	// POST /api/accounts
+	r.Handle(ToPath("accounts"), PostAccountsHandlerFunc(log)).
+		Methods(http.MethodPost)
+
+	// DELETE /api/accounts/{guid}
+	r.Handle(ToPath("accounts", "{guid}"), DeleteAccountHandlerFunc(log)).
+		Methods(http.MethodDelete)
+
 	// GET /api/accounts/{name}
 	r.Handle(ToPath("accounts", "{name}"), GetAccountHandlerFunc(lister, log)).
 		Methods(http.MethodGet)
@@ -67,11 +95,19 @@ func RouterHandlerFunc(base util.AbsolutePath, lister accounts.AccountList, log
 	r.Handle(ToPath("accounts", "{name}", "verify"), PostAccountVerifyHandlerFunc(lister, log)).
 		Methods(http.MethodPost)
 
+	// GET /api/accounts/{name}/content-types
+	r.Handle(ToPath("accounts", "{name}", "content-types"), GetAccountContentTypesHandlerFunc(lister, log)).
+		Methods(http.MethodGet)
+
 	// GET /api/events
 	r.HandleFunc(ToPath("events"), eventServer.ServeHTTP)
 
 	// GET /api/files
-	r.Handle(ToPath("files"), GetFileHandlerFunc(base, filesService, pathsService, log)).
+	r.Handle(ToPath("files"), limiter.Limit(GetFileHandlerFunc(base, filesService, log))).
+		Methods(http.MethodGet)
+
+	// GET /api/files/watch
+	r.Handle(ToPath("files", "watch"), GetFilesWatchHandlerFunc(base, files.DefaultDebounce, log)).
 		Methods(http.MethodGet)
 
 	// POST /api/entrypoints
@@ -79,7 +115,7 @@ func RouterHandlerFunc(base util.AbsolutePath, lister accounts.AccountList, log
 		Methods(http.MethodPost)
 
 	// POST /api/inspect
-	r.Handle(ToPath("inspect"), PostInspectHandlerFunc(base, log)).
+	r.Handle(ToPath("inspect"), limiter.Limit(PostInspectHandlerFunc(base, detectionCache, log))).
 		Methods(http.MethodPost)
 
 	// GET /api/credentials
@@ -106,10 +142,19 @@ func RouterHandlerFunc(base util.AbsolutePath, lister accounts.AccountList, log
 	r.Handle(ToPath("configurations"), GetConfigurationsHandlerFunc(base, log)).
 		Methods(http.MethodGet)
 
+	// POST /api/configurations/ensure returns a configuration,
+	// creating it from the detected content if needed
+	r.Handle(ToPath("configurations", "ensure"), limiter.Limit(PostConfigurationEnsureHandlerFunc(base, log))).
+		Methods(http.MethodPost)
+
 	// GET /api/configurations/$NAME
 	r.Handle(ToPath("configurations", "{name}"), GetConfigurationHandlerFunc(base, log)).
 		Methods(http.MethodGet)
 
+	// GET /api/configurations/$NAME/resolved
+	r.Handle(ToPath("configurations", "{name}", "resolved"), GetResolvedConfigurationHandlerFunc(base, log)).
+		Methods(http.MethodGet)
+
 	// PUT /api/configurations/$NAME
 	r.Handle(ToPath("configurations", "{name}"), PutConfigurationHandlerFunc(base, log)).
 		Methods(http.MethodPut)
@@ -119,7 +164,11 @@ func RouterHandlerFunc(base util.AbsolutePath, lister accounts.AccountList, log
 		Methods(http.MethodDelete)
 
 	// GET /api/configurations/$NAME/files
-	r.Handle(ToPath("configurations", "{name}", "files"), GetConfigFilesHandlerFunc(base, filesService, log)).
+	r.Handle(ToPath("configurations", "{name}", "files"), limiter.Limit(GetConfigFilesHandlerFunc(base, filesService, log))).
+		Methods(http.MethodGet)
+
+	// GET /api/configurations/$NAME/manifest
+	r.Handle(ToPath("configurations", "{name}", "manifest"), limiter.Limit(GetConfigManifestHandlerFunc(base, log))).
 		Methods(http.MethodGet)
 
 	// POST /api/configurations/$NAME/files
@@ -159,23 +208,40 @@ func RouterHandlerFunc(base util.AbsolutePath, lister accounts.AccountList, log
 		Methods(http.MethodGet)
 
 	// POST /api/deployments/$NAME intiates a deployment
-	r.Handle(ToPath("deployments", "{name}"), PostDeploymentHandlerFunc(base, log, lister, emitter)).
+	r.Handle(ToPath("deployments", "{name}"), PostDeploymentHandlerFunc(base, log, lister, deployLimiter, runningDeployments, emitter)).
+		Methods(http.MethodPost)
+
+	// POST /api/deployments/$NAME/cancel/$LOCALID cancels a deployment in progress
+	r.Handle(ToPath("deployments", "{name}", "cancel", "{localid}"), PostDeploymentCancelHandlerFunc(runningDeployments, log)).
 		Methods(http.MethodPost)
 
 	// DELETE /api/deployments/$NAME
 	r.Handle(ToPath("deployments", "{name}"), DeleteDeploymentHandlerFunc(base, log)).
 		Methods(http.MethodDelete)
 
+	// DELETE /api/deployments/$NAME/content deletes the content
+	// from the server, then the deployment record
+	r.Handle(ToPath("deployments", "{name}", "content"), DeleteDeploymentContentHandlerFunc(base, log, lister)).
+		Methods(http.MethodDelete)
+
 	// GET /api/deployments/$NAME/environment
 	r.Handle(ToPath("deployments", "{name}", "environment"), GetDeploymentEnvironmentHandlerFunc(base, log, lister)).
 		Methods(http.MethodGet)
 
+	// POST /api/deployments/$NAME/environment/import
+	r.Handle(ToPath("deployments", "{name}", "environment", "import"), PostDeploymentEnvironmentImportHandlerFunc(base, log, lister)).
+		Methods(http.MethodPost)
+
+	// GET /api/deployments/$NAME/logs
+	r.Handle(ToPath("deployments", "{name}", "logs"), GetDeploymentLogsHandlerFunc(base, log, lister)).
+		Methods(http.MethodGet)
+
 	// POST /api/packages/python/scan
-	r.Handle(ToPath("packages", "python", "scan"), NewPostPackagesPythonScanHandler(base, log)).
+	r.Handle(ToPath("packages", "python", "scan"), limiter.Limit(NewPostPackagesPythonScanHandler(base, log).ServeHTTP)).
 		Methods(http.MethodPost)
 
 	// POST /api/packages/r/scan
-	r.Handle(ToPath("packages", "r", "scan"), NewPostPackagesRScanHandler(base, log)).
+	r.Handle(ToPath("packages", "r", "scan"), limiter.Limit(NewPostPackagesRScanHandler(base, log).ServeHTTP)).
 		Methods(http.MethodPost)
 
 	c := cors.AllowAll().Handler(r)
@@ -0,0 +1,34 @@
+package api
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/posit-dev/publisher/internal/credentials"
+	"github.com/posit-dev/publisher/internal/logging"
+)
+
+// DeleteAccountHandlerFunc returns a handler that removes
+// an account from the credentials store.
+func DeleteAccountHandlerFunc(log logging.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		guid := mux.Vars(req)["guid"]
+
+		cs, ok := newCredentialsService(w, req, log)
+		if !ok {
+			return
+		}
+		err := cs.Delete(guid)
+		if err != nil {
+			if e, ok := err.(*credentials.NotFoundError); ok {
+				NotFound(w, log, e)
+			} else {
+				InternalError(w, req, log, err)
+			}
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
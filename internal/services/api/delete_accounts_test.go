@@ -0,0 +1,62 @@
+package api
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/posit-dev/publisher/internal/credentials"
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/stretchr/testify/suite"
+	"github.com/zalando/go-keyring"
+)
+
+type DeleteAccountSuite struct {
+	utiltest.Suite
+	log logging.Logger
+}
+
+func TestDeleteAccountSuite(t *testing.T) {
+	suite.Run(t, new(DeleteAccountSuite))
+}
+
+func (s *DeleteAccountSuite) SetupSuite() {
+	s.log = logging.New()
+}
+
+func (s *DeleteAccountSuite) SetupTest() {
+	keyring.MockInit()
+}
+
+func (s *DeleteAccountSuite) delete(guid string) *httptest.ResponseRecorder {
+	req, err := http.NewRequest("DELETE", "/api/accounts/"+guid, nil)
+	s.NoError(err)
+	req = mux.SetURLVars(req, map[string]string{"guid": guid})
+
+	rec := httptest.NewRecorder()
+	h := DeleteAccountHandlerFunc(s.log)
+	h(rec, req)
+	return rec
+}
+
+func (s *DeleteAccountSuite) TestDeleteAccount() {
+	cs, err := credentials.NewCredentialsService(s.log)
+	s.NoError(err)
+	cred, err := cs.Set(credentials.CreateCredentialDetails{Name: "example", URL: "https://connect.example.com", ApiKey: "12345"})
+	s.NoError(err)
+
+	rec := s.delete(cred.GUID)
+	s.Equal(http.StatusNoContent, rec.Result().StatusCode)
+
+	_, err = cs.Get(cred.GUID)
+	s.IsType(&credentials.NotFoundError{}, err)
+}
+
+func (s *DeleteAccountSuite) TestDeleteAccountNotFound() {
+	rec := s.delete("nonexistent")
+	s.Equal(http.StatusNotFound, rec.Result().StatusCode)
+}
@@ -0,0 +1,82 @@
+package api
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/posit-dev/publisher/internal/accounts"
+	"github.com/posit-dev/publisher/internal/clients/http_client"
+	"github.com/posit-dev/publisher/internal/deployment"
+	"github.com/posit-dev/publisher/internal/events"
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/util"
+)
+
+// DeleteDeploymentContentHandlerFunc deletes the deployed content
+// from the server, then removes the deployment record. If the
+// content can't be deleted, the record is kept.
+func DeleteDeploymentContentHandlerFunc(base util.AbsolutePath, log logging.Logger, accountList accounts.AccountList) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		name := mux.Vars(req)["name"]
+		projectDir, _, err := ProjectDirFromRequest(base, w, req, log)
+		if err != nil {
+			// Response already returned by ProjectDirFromRequest
+			return
+		}
+
+		path := deployment.GetDeploymentPath(projectDir, name)
+		d, err := deployment.FromFile(path)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				http.NotFound(w, req)
+				return
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(fmt.Sprintf("deployment %s is invalid: %s", name, err)))
+			return
+		}
+		if !d.IsDeployed() {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(fmt.Sprintf("deployment %s is not deployed", name)))
+			return
+		}
+		account, err := accountList.GetAccountByServerURL(d.ServerURL)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(fmt.Sprintf("no credential found to use with deployment %s", name)))
+			return
+		}
+		client, err := clientFactory(account, 30*time.Second, events.NewNullEmitter(), log)
+		if err != nil {
+			InternalError(w, req, log, err)
+			return
+		}
+
+		err = client.DeleteContent(d.ID, log)
+		if err != nil {
+			httpErr, ok := err.(*http_client.HTTPError)
+			if ok {
+				// Pass through HTTP Error from Connect
+				w.WriteHeader(httpErr.Status)
+				w.Write([]byte(httpErr.Error()))
+				return
+			}
+			InternalError(w, req, log, err)
+			return
+		}
+		log.Info("Deleted content from the server", "deployment", name, "content_id", d.ID, "server", d.ServerURL)
+
+		err = path.Remove()
+		if err != nil {
+			InternalError(w, req, log, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
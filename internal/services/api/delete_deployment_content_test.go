@@ -0,0 +1,130 @@
+package api
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/posit-dev/publisher/internal/accounts"
+	"github.com/posit-dev/publisher/internal/clients/connect"
+	"github.com/posit-dev/publisher/internal/clients/http_client"
+	"github.com/posit-dev/publisher/internal/deployment"
+	"github.com/posit-dev/publisher/internal/events"
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/types"
+	"github.com/posit-dev/publisher/internal/util"
+	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/suite"
+)
+
+type DeleteDeploymentContentSuite struct {
+	utiltest.Suite
+	log    logging.Logger
+	cwd    util.AbsolutePath
+	path   util.AbsolutePath
+	lister *accounts.MockAccountList
+}
+
+func TestDeleteDeploymentContentSuite(t *testing.T) {
+	suite.Run(t, new(DeleteDeploymentContentSuite))
+}
+
+func (s *DeleteDeploymentContentSuite) SetupSuite() {
+	s.log = logging.New()
+}
+
+func (s *DeleteDeploymentContentSuite) SetupTest() {
+	fs := afero.NewMemMapFs()
+	cwd, err := util.Getwd(fs)
+	s.Nil(err)
+	s.cwd = cwd
+	s.cwd.MkdirAll(0700)
+
+	clientFactory = connect.NewConnectClient
+
+	s.path = deployment.GetDeploymentPath(s.cwd, "dep")
+	d := deployment.New()
+	d.ID = "123"
+	d.ServerURL = "https://connect.example.com"
+	err = d.WriteFile(s.path)
+	s.NoError(err)
+
+	s.lister = &accounts.MockAccountList{}
+	acct := &accounts.Account{
+		Name:       "myAccount",
+		URL:        "https://connect.example.com",
+		ServerType: accounts.ServerTypeConnect,
+	}
+	s.lister.On("GetAccountByServerURL", "https://connect.example.com").Return(acct, nil)
+}
+
+func (s *DeleteDeploymentContentSuite) useClient(client connect.APIClient) {
+	clientFactory = func(account *accounts.Account, timeout time.Duration, emitter events.Emitter, log logging.Logger) (connect.APIClient, error) {
+		return client, nil
+	}
+}
+
+func (s *DeleteDeploymentContentSuite) delete() *httptest.ResponseRecorder {
+	h := DeleteDeploymentContentHandlerFunc(s.cwd, s.log, s.lister)
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest("DELETE", "/api/deployments/dep/content", nil)
+	s.NoError(err)
+	req = mux.SetURLVars(req, map[string]string{"name": "dep"})
+	h(rec, req)
+	return rec
+}
+
+func (s *DeleteDeploymentContentSuite) TestDeleteDeploymentContent() {
+	client := connect.NewMockClient()
+	client.On("DeleteContent", types.ContentID("123"), s.log).Return(nil)
+	s.useClient(client)
+
+	rec := s.delete()
+	s.Equal(http.StatusNoContent, rec.Result().StatusCode)
+	client.AssertExpectations(s.T())
+
+	exists, err := s.path.Exists()
+	s.NoError(err)
+	s.False(exists)
+}
+
+func (s *DeleteDeploymentContentSuite) TestDeleteDeploymentContentServerErr() {
+	client := connect.NewMockClient()
+	httpErr := http_client.NewHTTPError("https://connect.example.com", "DELETE", http.StatusForbidden)
+	client.On("DeleteContent", types.ContentID("123"), s.log).Return(httpErr)
+	s.useClient(client)
+
+	rec := s.delete()
+	s.Equal(http.StatusForbidden, rec.Result().StatusCode)
+
+	// The record is kept.
+	exists, err := s.path.Exists()
+	s.NoError(err)
+	s.True(exists)
+}
+
+func (s *DeleteDeploymentContentSuite) TestDeleteDeploymentContentNotDeployed() {
+	d := deployment.New()
+	err := d.WriteFile(s.path)
+	s.NoError(err)
+
+	rec := s.delete()
+	s.Equal(http.StatusBadRequest, rec.Result().StatusCode)
+
+	exists, err := s.path.Exists()
+	s.NoError(err)
+	s.True(exists)
+}
+
+func (s *DeleteDeploymentContentSuite) TestDeleteDeploymentContentNotFound() {
+	err := s.path.Remove()
+	s.NoError(err)
+
+	rec := s.delete()
+	s.Equal(http.StatusNotFound, rec.Result().StatusCode)
+}
@@ -39,6 +39,8 @@ type preDeploymentDTO struct {
 	DashboardURL string              `toml:"dashboard_url,omitempty" json:"dashboardUrl"`
 	DirectURL    string              `toml:"direct_url,omitempty" json:"directUrl"`
 	LogsURL      string              `toml:"logs_url,omitempty" json:"logsUrl"`
+	Note         string              `json:"note,omitempty"`
+	Labels       map[string]string   `json:"labels,omitempty"`
 }
 
 type fullDeploymentDTO struct {
@@ -112,6 +114,8 @@ func deploymentAsDTO(d *deployment.Deployment, err error, projectDir util.Absolu
 			DashboardURL: d.DashboardURL,
 			DirectURL:    d.DirectURL,
 			LogsURL:      d.LogsURL,
+			Note:         d.Note,
+			Labels:       d.Labels,
 		}
 	}
 }
@@ -17,14 +17,16 @@ type FilesService interface {
 	GetFile(path util.AbsolutePath, matchList matcher.MatchList) (*File, error)
 }
 
-func CreateFilesService(base util.AbsolutePath, log logging.Logger) FilesService {
+func CreateFilesService(base util.AbsolutePath, symlinkPolicy util.SymlinkPolicy, log logging.Logger) FilesService {
 	return filesService{
-		log: log,
+		symlinkPolicy: symlinkPolicy,
+		log:           log,
 	}
 }
 
 type filesService struct {
-	log logging.Logger
+	symlinkPolicy util.SymlinkPolicy
+	log           logging.Logger
 }
 
 func (s filesService) GetFile(p util.AbsolutePath, matchList matcher.MatchList) (*File, error) {
@@ -42,7 +44,7 @@ func (s filesService) GetFile(p util.AbsolutePath, matchList matcher.MatchList)
 		return nil, err
 	}
 
-	walker := util.NewSymlinkWalker(util.FSWalker{}, s.log)
+	walker := util.NewSymlinkWalker(util.FSWalker{}, s.symlinkPolicy, s.log)
 	err = walker.Walk(p, func(path util.AbsolutePath, info fs.FileInfo, err error) error {
 		if err != nil {
 			if errors.Is(err, os.ErrNotExist) {
@@ -4,6 +4,7 @@ package files
 
 import (
 	"os"
+	"runtime"
 	"testing"
 
 	"github.com/posit-dev/publisher/internal/bundles/matcher"
@@ -43,13 +44,13 @@ func (s *ServicesSuite) TestCreateFilesService() {
 	base, err := util.Getwd(afs)
 	s.NoError(err)
 
-	service := CreateFilesService(base, s.log)
+	service := CreateFilesService(base, util.SymlinkFollow, s.log)
 	s.NotNil(service)
 }
 
 func (s *ServicesSuite) TestGetFile() {
 	base := s.cwd
-	service := CreateFilesService(base, s.log)
+	service := CreateFilesService(base, util.SymlinkFollow, s.log)
 	s.NotNil(service)
 	matchList, err := matcher.NewMatchList(base, nil)
 	s.NoError(err)
@@ -75,7 +76,7 @@ func (s *ServicesSuite) TestGetFilePermissionErr() {
 	afs.On("Stat", base.Join("Scripts", "python.exe").String()).Return(fileInfo, os.ErrNotExist)
 	afs.On("Stat", base.Join("Scripts", "python3.exe").String()).Return(fileInfo, os.ErrNotExist)
 
-	service := CreateFilesService(base, s.log)
+	service := CreateFilesService(base, util.SymlinkFollow, s.log)
 	s.NotNil(service)
 	matchList, err := matcher.NewMatchList(base, nil)
 	s.NoError(err)
@@ -89,7 +90,7 @@ func (s *ServicesSuite) TestGetFileUsingSampleContent() {
 	afs := afero.NewOsFs()
 	base := s.cwd.Join("..", "..", "..", "..", "test", "sample-content", "fastapi-simple").WithFs(afs)
 
-	service := CreateFilesService(base, s.log)
+	service := CreateFilesService(base, util.SymlinkFollow, s.log)
 	s.NotNil(service)
 
 	patterns := []string{
@@ -119,7 +120,7 @@ func (s *ServicesSuite) TestGetFileUsingSampleContentWithTrailingSlash() {
 	afs := afero.NewOsFs()
 	base := s.cwd.Join("..", "..", "..", "..", "test", "sample-content", "fastapi-simple").WithFs(afs)
 
-	service := CreateFilesService(base, s.log)
+	service := CreateFilesService(base, util.SymlinkFollow, s.log)
 	s.NotNil(service)
 	matchList, err := matcher.NewMatchList(base, nil)
 	s.NoError(err)
@@ -134,7 +135,7 @@ func (s *ServicesSuite) TestGetFileUsingSampleContentFromParentDir() {
 	base := s.cwd.Join("..", "..", "..", "..").WithFs(afs)
 	toList := base.Join("test", "sample-content", "fastapi-simple")
 
-	service := CreateFilesService(base, s.log)
+	service := CreateFilesService(base, util.SymlinkFollow, s.log)
 	s.NotNil(service)
 	patterns := []string{
 		"*.py",
@@ -163,7 +164,7 @@ func (s *ServicesSuite) TestGetFileUsingSampleContentAllIncluded() {
 	afs := afero.NewOsFs()
 	base := s.cwd.Join("..", "..", "..", "..", "test", "sample-content", "fastapi-simple").WithFs(afs)
 
-	service := CreateFilesService(base, s.log)
+	service := CreateFilesService(base, util.SymlinkFollow, s.log)
 	s.NotNil(service)
 
 	patterns := []string{
@@ -192,7 +193,7 @@ func (s *ServicesSuite) TestGetFileUsingSampleContentAllExcluded() {
 	afs := afero.NewOsFs()
 	base := s.cwd.Join("..", "..", "..", "..", "test", "sample-content", "fastapi-simple").WithFs(afs)
 
-	service := CreateFilesService(base, s.log)
+	service := CreateFilesService(base, util.SymlinkFollow, s.log)
 	s.NotNil(service)
 
 	patterns := []string{
@@ -219,7 +220,7 @@ func (s *ServicesSuite) TestGetFileUsingSampleContentAllExcluded() {
 
 func (s *ServicesSuite) TestGetFileSizeAndCount() {
 	base := s.cwd
-	service := CreateFilesService(base, s.log)
+	service := CreateFilesService(base, util.SymlinkFollow, s.log)
 	s.NotNil(service)
 	matchList, err := matcher.NewMatchList(base, nil)
 	s.NoError(err)
@@ -244,3 +245,65 @@ func (s *ServicesSuite) TestGetFileSizeAndCount() {
 	s.Equal(int64(2), file.FileCount)
 	s.Equal(int64(7), file.Size)
 }
+
+func (s *ServicesSuite) symlinkTestDir() util.AbsolutePath {
+	afs := afero.NewOsFs()
+	return s.cwd.Join("..", "..", "..", "util", "testdata", "symlink_test", "bundle_dir").WithFs(afs)
+}
+
+func (s *ServicesSuite) TestGetFileSymlinksFollow() {
+	if runtime.GOOS == "windows" {
+		s.T().Skip()
+	}
+	base := s.symlinkTestDir()
+	service := CreateFilesService(base, util.SymlinkFollow, s.log)
+	matchList, err := matcher.NewMatchList(base, nil)
+	s.NoError(err)
+
+	file, err := service.GetFile(base, matchList)
+	s.NoError(err)
+	s.NotNil(file)
+
+	names := []string{}
+	for _, child := range file.Files {
+		names = append(names, child.Base)
+	}
+	s.Contains(names, "linked_dir")
+	s.Contains(names, "linked_file")
+	s.Contains(names, "somefile")
+}
+
+func (s *ServicesSuite) TestGetFileSymlinksSkip() {
+	if runtime.GOOS == "windows" {
+		s.T().Skip()
+	}
+	base := s.symlinkTestDir()
+	service := CreateFilesService(base, util.SymlinkSkip, s.log)
+	matchList, err := matcher.NewMatchList(base, nil)
+	s.NoError(err)
+
+	file, err := service.GetFile(base, matchList)
+	s.NoError(err)
+	s.NotNil(file)
+
+	names := []string{}
+	for _, child := range file.Files {
+		names = append(names, child.Base)
+	}
+	s.NotContains(names, "linked_dir")
+	s.NotContains(names, "linked_file")
+	s.Contains(names, "somefile")
+}
+
+func (s *ServicesSuite) TestGetFileSymlinksError() {
+	if runtime.GOOS == "windows" {
+		s.T().Skip()
+	}
+	base := s.symlinkTestDir()
+	service := CreateFilesService(base, util.SymlinkError, s.log)
+	matchList, err := matcher.NewMatchList(base, nil)
+	s.NoError(err)
+
+	_, err = service.GetFile(base, matchList)
+	s.ErrorIs(err, util.ErrSymlinkNotAllowed)
+}
@@ -0,0 +1,254 @@
+package files
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/posit-dev/publisher/internal/bundles/matcher"
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/util"
+)
+
+type ChangeKind string
+
+const (
+	ChangeAdded   ChangeKind = "add"
+	ChangeRemoved ChangeKind = "remove"
+	ChangeUpdated ChangeKind = "change"
+)
+
+// Change describes a file or directory that was added,
+// removed, or modified in the project directory.
+type Change struct {
+	Kind ChangeKind `json:"kind"`
+	Rel  string     `json:"rel"` // the relative path to the project root, as in File
+}
+
+// DefaultDebounce is how long the watcher waits for
+// changes to settle before reporting them.
+const DefaultDebounce = 250 * time.Millisecond
+
+// Watcher reports changes to the files in a project directory.
+// Files and directories that the files endpoint ignores,
+// like Python environments or .git, are not watched.
+type Watcher struct {
+	base      util.AbsolutePath
+	matchList matcher.MatchList
+	watcher   *fsnotify.Watcher
+	debounce  time.Duration
+	log       logging.Logger
+
+	// Changes receives batches of changes, after they have settled
+	// for the debounce interval. It is closed when the watcher stops.
+	Changes chan []Change
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+func NewWatcher(base util.AbsolutePath, debounce time.Duration, log logging.Logger) (*Watcher, error) {
+	matchList, err := matcher.NewMatchList(base, matcher.StandardExclusions)
+	if err != nil {
+		return nil, err
+	}
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	w := &Watcher{
+		base:      base,
+		matchList: matchList,
+		watcher:   fsWatcher,
+		debounce:  debounce,
+		log:       log,
+		Changes:   make(chan []Change),
+		done:      make(chan struct{}),
+	}
+	err = w.addTree(base)
+	if err != nil {
+		fsWatcher.Close()
+		return nil, err
+	}
+	go w.run()
+	return w, nil
+}
+
+// Close stops watching. Pending changes are discarded.
+func (w *Watcher) Close() error {
+	var err error
+	w.closeOnce.Do(func() {
+		close(w.done)
+		err = w.watcher.Close()
+	})
+	return err
+}
+
+func (w *Watcher) ignored(path util.AbsolutePath, isDir bool) bool {
+	if isDir && (util.IsPythonEnvironmentDir(path) || util.IsRenvLibraryDir(path)) {
+		return true
+	}
+	m := w.matchList.Match(path)
+	return m != nil && m.Exclude
+}
+
+// addTree watches dir and all of its subdirectories.
+// fsnotify doesn't watch recursively.
+func (w *Watcher) addTree(dir util.AbsolutePath) error {
+	return dir.Walk(func(path util.AbsolutePath, info fs.FileInfo, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) || errors.Is(err, os.ErrPermission) {
+				return nil
+			}
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if w.ignored(path, true) {
+			return filepath.SkipDir
+		}
+		err = w.watcher.Add(path.String())
+		if err != nil {
+			w.log.Warn("cannot watch directory; skipping", "path", path, "error", err)
+			return filepath.SkipDir
+		}
+		return nil
+	})
+}
+
+// change converts an fsnotify event to a Change,
+// returning nil if the event should not be reported.
+func (w *Watcher) change(event fsnotify.Event) *Change {
+	path := util.NewAbsolutePath(event.Name, w.base.Fs())
+	var kind ChangeKind
+	isDir := false
+
+	switch {
+	case event.Has(fsnotify.Create):
+		kind = ChangeAdded
+		isDir, _ = path.IsDir()
+	case event.Has(fsnotify.Remove), event.Has(fsnotify.Rename):
+		kind = ChangeRemoved
+	case event.Has(fsnotify.Write):
+		kind = ChangeUpdated
+	default:
+		// Permission changes don't affect the file tree.
+		return nil
+	}
+	if w.ignored(path, isDir) {
+		return nil
+	}
+	if kind == ChangeAdded && isDir {
+		// Files may be created in the new directory before it is
+		// watched, so clients should re-read its contents.
+		err := w.addTree(path)
+		if err != nil {
+			w.log.Warn("cannot watch new directory", "path", path, "error", err)
+		}
+	}
+	rel, err := path.Rel(w.base)
+	if err != nil {
+		return nil
+	}
+	return &Change{
+		Kind: kind,
+		Rel:  rel.String(),
+	}
+}
+
+// merge combines a new change with an earlier one for the same path
+// that hasn't been reported yet. It returns false if the changes
+// cancel each other out.
+func merge(earlier ChangeKind, later ChangeKind) (ChangeKind, bool) {
+	switch {
+	case earlier == ChangeAdded && later == ChangeRemoved:
+		// Created and removed before anyone saw it.
+		return "", false
+	case earlier == ChangeAdded:
+		// Still new, regardless of later writes.
+		return ChangeAdded, true
+	case earlier == ChangeRemoved && later == ChangeAdded:
+		// Replaced, as editors do when saving atomically.
+		return ChangeUpdated, true
+	default:
+		return later, true
+	}
+}
+
+func (w *Watcher) run() {
+	defer close(w.Changes)
+
+	pending := map[string]ChangeKind{}
+	var order []string
+	var timer *time.Timer
+	var timeout <-chan time.Time
+
+	for {
+		select {
+		case <-w.done:
+			return
+
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			c := w.change(event)
+			if c == nil {
+				continue
+			}
+			if earlier, ok := pending[c.Rel]; ok {
+				kind, keep := merge(earlier, c.Kind)
+				if keep {
+					pending[c.Rel] = kind
+				} else {
+					delete(pending, c.Rel)
+				}
+			} else {
+				pending[c.Rel] = c.Kind
+				order = append(order, c.Rel)
+			}
+			// Wait for changes to settle.
+			if timer == nil {
+				timer = time.NewTimer(w.debounce)
+			} else {
+				timer.Reset(w.debounce)
+			}
+			timeout = timer.C
+
+		case <-timeout:
+			timeout = nil
+			changes := []Change{}
+			for _, rel := range order {
+				if kind, ok := pending[rel]; ok {
+					changes = append(changes, Change{Kind: kind, Rel: rel})
+					// A path may be listed more than once
+					// if its changes canceled out in between.
+					delete(pending, rel)
+				}
+			}
+			order = nil
+			if len(changes) == 0 {
+				continue
+			}
+			select {
+			case w.Changes <- changes:
+			case <-w.done:
+				return
+			}
+
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			w.log.Warn("error watching project files", "error", err)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package files
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"testing"
+	"time"
+
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/util"
+	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/stretchr/testify/suite"
+)
+
+type WatcherSuite struct {
+	utiltest.Suite
+	log logging.Logger
+	cwd util.AbsolutePath
+}
+
+func TestWatcherSuite(t *testing.T) {
+	suite.Run(t, new(WatcherSuite))
+}
+
+func (s *WatcherSuite) SetupTest() {
+	// fsnotify watches the real filesystem.
+	s.log = logging.New()
+	s.cwd = util.NewAbsolutePath(s.T().TempDir(), nil)
+	s.NoError(s.cwd.Join("app.py").WriteFile([]byte("import flask\n"), 0666))
+	s.NoError(s.cwd.Join("static").MkdirAll(0777))
+}
+
+const testDebounce = 50 * time.Millisecond
+
+func (s *WatcherSuite) nextChanges(w *Watcher) []Change {
+	select {
+	case changes := <-w.Changes:
+		return changes
+	case <-time.After(5 * time.Second):
+		s.Fail("timed out waiting for changes")
+		return nil
+	}
+}
+
+func (s *WatcherSuite) TestModifyFile() {
+	w, err := NewWatcher(s.cwd, testDebounce, s.log)
+	s.NoError(err)
+	defer w.Close()
+
+	s.NoError(s.cwd.Join("app.py").WriteFile([]byte("import flask\nimport pandas\n"), 0666))
+	s.Equal([]Change{{Kind: ChangeUpdated, Rel: "app.py"}}, s.nextChanges(w))
+}
+
+func (s *WatcherSuite) TestAddAndRemoveInSubdir() {
+	w, err := NewWatcher(s.cwd, testDebounce, s.log)
+	s.NoError(err)
+	defer w.Close()
+
+	path := s.cwd.Join("static", "index.html")
+	s.NoError(path.WriteFile([]byte("<html></html>"), 0666))
+	// Creating and writing the file is reported as a single addition.
+	rel := util.NewRelativePath("static/index.html", nil).String()
+	s.Equal([]Change{{Kind: ChangeAdded, Rel: rel}}, s.nextChanges(w))
+
+	s.NoError(path.Remove())
+	s.Equal([]Change{{Kind: ChangeRemoved, Rel: rel}}, s.nextChanges(w))
+}
+
+func (s *WatcherSuite) TestNewDirectoryIsWatched() {
+	w, err := NewWatcher(s.cwd, testDebounce, s.log)
+	s.NoError(err)
+	defer w.Close()
+
+	s.NoError(s.cwd.Join("data").MkdirAll(0777))
+	s.Equal([]Change{{Kind: ChangeAdded, Rel: "data"}}, s.nextChanges(w))
+
+	s.NoError(s.cwd.Join("data", "input.csv").WriteFile([]byte("a,b\n"), 0666))
+	rel := util.NewRelativePath("data/input.csv", nil).String()
+	s.Equal([]Change{{Kind: ChangeAdded, Rel: rel}}, s.nextChanges(w))
+}
+
+func (s *WatcherSuite) TestIgnoredFiles() {
+	s.NoError(s.cwd.Join("__pycache__").MkdirAll(0777))
+	w, err := NewWatcher(s.cwd, testDebounce, s.log)
+	s.NoError(err)
+	defer w.Close()
+
+	// Excluded by the standard exclusions.
+	s.NoError(s.cwd.Join("__pycache__", "app.cpython-311.pyc").WriteFile(nil, 0666))
+	s.NoError(s.cwd.Join(".DS_Store").WriteFile(nil, 0666))
+	// Not excluded; the only change reported.
+	s.NoError(s.cwd.Join("requirements.txt").WriteFile([]byte("flask\n"), 0666))
+
+	s.Equal([]Change{{Kind: ChangeAdded, Rel: "requirements.txt"}}, s.nextChanges(w))
+}
+
+func (s *WatcherSuite) TestDebounce() {
+	w, err := NewWatcher(s.cwd, testDebounce, s.log)
+	s.NoError(err)
+	defer w.Close()
+
+	// Rapid changes are reported together.
+	path := s.cwd.Join("app.py")
+	for i := 0; i < 5; i++ {
+		s.NoError(path.WriteFile([]byte("import flask\n"), 0666))
+	}
+	s.NoError(s.cwd.Join("tmp.txt").WriteFile(nil, 0666))
+	s.NoError(s.cwd.Join("tmp.txt").Remove())
+
+	s.Equal([]Change{{Kind: ChangeUpdated, Rel: "app.py"}}, s.nextChanges(w))
+}
+
+func (s *WatcherSuite) TestClose() {
+	w, err := NewWatcher(s.cwd, testDebounce, s.log)
+	s.NoError(err)
+	s.NoError(w.Close())
+
+	_, ok := <-w.Changes
+	s.False(ok)
+}
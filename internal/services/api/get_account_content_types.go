@@ -0,0 +1,45 @@
+package api
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/posit-dev/publisher/internal/accounts"
+	"github.com/posit-dev/publisher/internal/events"
+	"github.com/posit-dev/publisher/internal/logging"
+)
+
+// GetAccountContentTypesHandlerFunc returns the content types that
+// the account's server supports, so that unsupported types can be
+// disabled when choosing one.
+func GetAccountContentTypesHandlerFunc(lister accounts.AccountList, log logging.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		name := mux.Vars(req)["name"]
+		account, err := lister.GetAccountByName(name)
+		if err != nil {
+			if errors.Is(err, accounts.ErrAccountNotFound) {
+				http.NotFound(w, req)
+			} else {
+				InternalError(w, req, log, err)
+			}
+			return
+		}
+		client, err := clientFactory(account, 30*time.Second, events.NewNullEmitter(), log)
+		if err != nil {
+			InternalError(w, req, log, err)
+			return
+		}
+		contentTypes, err := client.GetSupportedContentTypes(log)
+		if err != nil {
+			InternalError(w, req, log, err)
+			return
+		}
+		w.Header().Set("content-type", "application/json")
+		json.NewEncoder(w).Encode(contentTypes)
+	}
+}
@@ -0,0 +1,99 @@
+package api
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/posit-dev/publisher/internal/accounts"
+	"github.com/posit-dev/publisher/internal/clients/connect"
+	"github.com/posit-dev/publisher/internal/config"
+	"github.com/posit-dev/publisher/internal/events"
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type GetAccountContentTypesSuite struct {
+	utiltest.Suite
+	log logging.Logger
+}
+
+func TestGetAccountContentTypesSuite(t *testing.T) {
+	suite.Run(t, new(GetAccountContentTypesSuite))
+}
+
+func (s *GetAccountContentTypesSuite) SetupSuite() {
+	s.log = logging.New()
+}
+
+func (s *GetAccountContentTypesSuite) TearDownTest() {
+	clientFactory = connect.NewConnectClient
+}
+
+func (s *GetAccountContentTypesSuite) getContentTypes(lister accounts.AccountList) *httptest.ResponseRecorder {
+	h := GetAccountContentTypesHandlerFunc(lister, s.log)
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/api/accounts/myAccount/content-types", nil)
+	s.NoError(err)
+	req = mux.SetURLVars(req, map[string]string{"name": "myAccount"})
+	h(rec, req)
+	return rec
+}
+
+func (s *GetAccountContentTypesSuite) TestGetContentTypes() {
+	lister := &accounts.MockAccountList{}
+	acct := &accounts.Account{
+		Name: "myAccount",
+		URL:  "https://connect.example.com",
+	}
+	lister.On("GetAccountByName", "myAccount").Return(acct, nil)
+
+	client := connect.NewMockClient()
+	supported := []config.ContentType{
+		config.ContentTypeHTML,
+		config.ContentTypePythonDash,
+	}
+	client.On("GetSupportedContentTypes", mock.Anything).Return(supported, nil)
+	clientFactory = func(account *accounts.Account, timeout time.Duration, emitter events.Emitter, log logging.Logger) (connect.APIClient, error) {
+		s.Equal(acct, account)
+		return client, nil
+	}
+
+	rec := s.getContentTypes(lister)
+	s.Equal(http.StatusOK, rec.Result().StatusCode)
+	s.Equal("application/json", rec.Header().Get("content-type"))
+
+	var res []config.ContentType
+	s.NoError(json.NewDecoder(rec.Body).Decode(&res))
+	s.Equal(supported, res)
+}
+
+func (s *GetAccountContentTypesSuite) TestGetContentTypesAccountNotFound() {
+	lister := &accounts.MockAccountList{}
+	lister.On("GetAccountByName", "myAccount").Return(nil, accounts.ErrAccountNotFound)
+
+	rec := s.getContentTypes(lister)
+	s.Equal(http.StatusNotFound, rec.Result().StatusCode)
+}
+
+func (s *GetAccountContentTypesSuite) TestGetContentTypesServerErr() {
+	lister := &accounts.MockAccountList{}
+	lister.On("GetAccountByName", "myAccount").Return(&accounts.Account{}, nil)
+
+	client := connect.NewMockClient()
+	client.On("GetSupportedContentTypes", mock.Anything).Return(nil, errors.New("test error"))
+	clientFactory = func(account *accounts.Account, timeout time.Duration, emitter events.Emitter, log logging.Logger) (connect.APIClient, error) {
+		return client, nil
+	}
+
+	rec := s.getContentTypes(lister)
+	s.Equal(http.StatusInternalServerError, rec.Result().StatusCode)
+}
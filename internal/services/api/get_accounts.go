@@ -21,6 +21,7 @@ type getAccountResponse struct {
 	Insecure    bool   `json:"insecure"`    // Skip https server verification
 	Certificate string `json:"caCert"`      // Root CA certificate, if server cert is signed by a private CA
 	AccountName string `json:"accountName"` // For shinyapps.io and Posit Cloud servers
+	GUID        string `json:"guid"`        // Credential GUID, if from the credentials store
 }
 
 type getAccountsResponse []*getAccountResponse
@@ -37,6 +38,7 @@ func toGetAccountResponse(acct *accounts.Account) *getAccountResponse {
 		Insecure:    acct.Insecure,
 		Certificate: acct.Certificate,
 		AccountName: acct.AccountName,
+		GUID:        acct.GUID,
 	}
 }
 
@@ -10,9 +10,11 @@ import (
 	"testing"
 
 	"github.com/posit-dev/publisher/internal/accounts"
+	"github.com/posit-dev/publisher/internal/credentials"
 	"github.com/posit-dev/publisher/internal/logging"
 	"github.com/posit-dev/publisher/internal/util/utiltest"
 	"github.com/stretchr/testify/suite"
+	"github.com/zalando/go-keyring"
 )
 
 type GetAccountsSuite struct {
@@ -97,3 +99,34 @@ func (s *GetAccountsSuite) TestGetAccountsNoAccounts() {
 	s.NotNil(accts)
 	s.Len(accts, 0)
 }
+
+func (s *GetAccountsSuite) TestGetAccountsMergesProviders() {
+	keyring.MockInit()
+	cs, err := credentials.NewCredentialsService(s.log)
+	s.NoError(err)
+	cred, err := cs.Set(credentials.CreateCredentialDetails{Name: "saved", URL: "https://connect.example.com", ApiKey: "12345"})
+	s.NoError(err)
+
+	s.T().Setenv("CONNECT_SERVER", "https://other.example.com")
+	s.T().Setenv("CONNECT_API_KEY", "67890")
+
+	lister, err := accounts.NewAccountList(utiltest.NewMockFs(), s.log)
+	s.NoError(err)
+	h := GetAccountsHandlerFunc(lister, s.log)
+
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/api/accounts", nil)
+	s.NoError(err)
+	h(rec, req)
+	s.Equal(http.StatusOK, rec.Result().StatusCode)
+
+	accts := getAccountsResponse{}
+	s.NoError(json.NewDecoder(rec.Body).Decode(&accts))
+	s.Len(accts, 2)
+	s.Equal("saved", accts[0].Name)
+	s.Equal(string(accounts.AccountSourceKeychain), accts[0].Source)
+	s.Equal(cred.GUID, accts[0].GUID)
+	s.Equal("env", accts[1].Name)
+	s.Equal(string(accounts.AccountSourceEnvironment), accts[1].Source)
+	s.Equal("", accts[1].GUID)
+}
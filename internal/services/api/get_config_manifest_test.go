@@ -0,0 +1,93 @@
+package api
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/posit-dev/publisher/internal/bundles"
+	"github.com/posit-dev/publisher/internal/clients/connect"
+	"github.com/posit-dev/publisher/internal/config"
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/util"
+	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/suite"
+)
+
+type GetConfigManifestSuite struct {
+	utiltest.Suite
+	cwd util.AbsolutePath
+	log logging.Logger
+	h   http.HandlerFunc
+}
+
+func TestGetConfigManifestSuite(t *testing.T) {
+	suite.Run(t, new(GetConfigManifestSuite))
+}
+
+func (s *GetConfigManifestSuite) SetupSuite() {
+	s.log = logging.New()
+}
+
+func (s *GetConfigManifestSuite) SetupTest() {
+	fs := afero.NewMemMapFs()
+	cwd, err := util.Getwd(fs)
+	s.Nil(err)
+	s.cwd = cwd
+	s.cwd.MkdirAll(0700)
+	s.h = GetConfigManifestHandlerFunc(s.cwd, s.log)
+}
+
+func (s *GetConfigManifestSuite) get(name string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/api/configurations/"+name+"/manifest", nil)
+	s.NoError(err)
+	req = mux.SetURLVars(req, map[string]string{"name": name})
+	s.h(rec, req)
+	return rec
+}
+
+func (s *GetConfigManifestSuite) TestGetConfigManifest() {
+	cfg := config.New()
+	cfg.Type = config.ContentTypeHTML
+	cfg.Entrypoint = "index.html"
+	cfg.Files = []string{"index.html", "style.css"}
+	err := cfg.WriteFile(config.GetConfigPath(s.cwd, "myConfig"))
+	s.NoError(err)
+	s.NoError(s.cwd.Join("index.html").WriteFile([]byte("<html></html>"), 0600))
+	s.NoError(s.cwd.Join("style.css").WriteFile([]byte("body {}"), 0600))
+	s.NoError(s.cwd.Join("notes.txt").WriteFile([]byte("not deployed"), 0600))
+
+	rec := s.get("myConfig")
+	s.Equal(http.StatusOK, rec.Result().StatusCode)
+	s.Equal("application/json", rec.Header().Get("content-type"))
+
+	manifest, err := bundles.ReadManifest(rec.Body)
+	s.NoError(err)
+	s.Equal([]string{"index.html", "style.css"}, manifest.GetFilenames())
+	s.Equal(connect.AppModeFromType(config.ContentTypeHTML), manifest.Metadata.AppMode)
+	s.Equal("index.html", manifest.Metadata.Entrypoint)
+	s.NotEmpty(manifest.Files["index.html"].Checksum)
+}
+
+func (s *GetConfigManifestSuite) TestGetConfigManifestNotFound() {
+	rec := s.get("nonexistent")
+	s.Equal(http.StatusNotFound, rec.Result().StatusCode)
+}
+
+func (s *GetConfigManifestSuite) TestGetConfigManifestMissingLockfile() {
+	cfg := config.New()
+	cfg.Type = config.ContentTypeRShiny
+	cfg.Entrypoint = "app.R"
+	cfg.R = &config.R{Version: "4.3.1"}
+	err := cfg.WriteFile(config.GetConfigPath(s.cwd, "myConfig"))
+	s.NoError(err)
+
+	rec := s.get("myConfig")
+	s.Equal(http.StatusUnprocessableEntity, rec.Result().StatusCode)
+	s.Contains(rec.Body.String(), "renv.lock")
+}
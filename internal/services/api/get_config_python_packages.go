@@ -59,13 +59,17 @@ func (h *getConfigPythonPackagesHandler) ServeHTTP(w http.ResponseWriter, req *h
 		w.WriteHeader(http.StatusConflict)
 		return
 	}
-	requirementsFilename := cfg.Python.PackageFile
-	if requirementsFilename == "" {
-		requirementsFilename = inspect.PythonRequirementsFilename
+	// Poetry and Pipenv requirements are exported from the
+	// lock file when publishing.
+	reqs, exported, err := inspect.ReadLockedRequirements(cfg.Python.PackageManager, projectDir)
+	if err == nil && !exported {
+		requirementsFilename := cfg.Python.PackageFile
+		if requirementsFilename == "" {
+			requirementsFilename = inspect.PythonRequirementsFilename
+		}
+		path := projectDir.Join(requirementsFilename)
+		reqs, err = h.inspector.ReadRequirementsFile(path)
 	}
-
-	path := projectDir.Join(requirementsFilename)
-	reqs, err := h.inspector.ReadRequirementsFile(path)
 	if err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
 			NotFound(w, h.log, err)
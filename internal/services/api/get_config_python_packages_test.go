@@ -11,6 +11,7 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/posit-dev/publisher/internal/config"
+	"github.com/posit-dev/publisher/internal/inspect"
 	"github.com/posit-dev/publisher/internal/logging"
 	"github.com/posit-dev/publisher/internal/util"
 	"github.com/posit-dev/publisher/internal/util/utiltest"
@@ -76,6 +77,34 @@ func (s *GetConfigRequirementsSuite) TestGetConfigRequirements() {
 	}, res.Requirements)
 }
 
+func (s *GetConfigRequirementsSuite) TestGetConfigRequirementsPipenv() {
+	lock := []byte(`{"default": {"flask": {"version": "==3.0.3"}}, "develop": {"pytest": {"version": "==8.2.2"}}}`)
+	s.cwd.Join(inspect.PipfileLockFilename).WriteFile(lock, 0666)
+
+	cfg := config.New()
+	cfg.Type = config.ContentTypePythonFlask
+	cfg.Python = &config.Python{
+		Version:        "3.11.3",
+		PackageManager: "pipenv",
+		PackageFile:    "requirements.txt",
+	}
+	err := cfg.WriteFile(config.GetConfigPath(s.cwd, "myConfig"))
+	s.NoError(err)
+
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/api/configurations/myConfig/requirements", nil)
+	s.NoError(err)
+	req = mux.SetURLVars(req, map[string]string{"name": "myConfig"})
+
+	h := NewGetConfigPythonPackagesHandler(s.cwd, s.log)
+	h.ServeHTTP(rec, req)
+
+	s.Equal(http.StatusOK, rec.Result().StatusCode)
+	res := pythonPackagesDTO{}
+	s.NoError(json.NewDecoder(rec.Body).Decode(&res))
+	s.Equal([]string{"flask==3.0.3"}, res.Requirements)
+}
+
 func (s *GetConfigRequirementsSuite) TestGetConfigRequirementsNotFound() {
 	rec := httptest.NewRecorder()
 	req, err := http.NewRequest("GET", "/api/configurations/myConfig/requirements", nil)
@@ -3,7 +3,6 @@ package api
 // Copyright (C) 2023 by Posit Software, PBC.
 
 import (
-	"encoding/json"
 	"errors"
 	"io/fs"
 	"net/http"
@@ -34,7 +33,6 @@ func GetConfigurationHandlerFunc(base util.AbsolutePath, log logging.Logger) htt
 			http.NotFound(w, req)
 			return
 		}
-		w.Header().Set("content-type", "application/json")
 		if err != nil {
 			response := &configDTO{
 				configLocation: configLocation{
@@ -46,7 +44,7 @@ func GetConfigurationHandlerFunc(base util.AbsolutePath, log logging.Logger) htt
 				Configuration: nil,
 				Error:         types.AsAgentError(err),
 			}
-			json.NewEncoder(w).Encode(response)
+			JsonResultWithETag(w, req, log, response)
 		} else {
 			response := &configDTO{
 				configLocation: configLocation{
@@ -56,9 +54,10 @@ func GetConfigurationHandlerFunc(base util.AbsolutePath, log logging.Logger) htt
 				},
 				ProjectDir:    relProjectDir.String(),
 				Configuration: cfg,
+				Warnings:      cfg.Lint(projectDir),
 				Error:         nil,
 			}
-			json.NewEncoder(w).Encode(response)
+			JsonResultWithETag(w, req, log, response)
 		}
 	}
 }
@@ -0,0 +1,60 @@
+package api
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/posit-dev/publisher/internal/config"
+	"github.com/posit-dev/publisher/internal/initialize"
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/types"
+	"github.com/posit-dev/publisher/internal/util"
+)
+
+// GetResolvedConfigurationHandlerFunc returns the configuration as it will be
+// used for deployment, with defaults applied and unspecified Python and R
+// versions inspected. The optional `python` and `r` query parameters
+// select the interpreters to inspect.
+func GetResolvedConfigurationHandlerFunc(base util.AbsolutePath, log logging.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		name := mux.Vars(req)["name"]
+		projectDir, relProjectDir, err := ProjectDirFromRequest(base, w, req, log)
+		if err != nil {
+			// Response already returned by ProjectDirFromRequest
+			return
+		}
+		path := config.GetConfigPath(projectDir, name)
+		relPath, err := path.Rel(projectDir)
+		if err != nil {
+			InternalError(w, req, log, err)
+			return
+		}
+		python := util.NewPath(req.URL.Query().Get("python"), nil)
+		rExecutable := util.NewPath(req.URL.Query().Get("r"), nil)
+		cfg, err := initialize.ResolveConfig(projectDir, name, python, rExecutable, log)
+		if err != nil && errors.Is(err, fs.ErrNotExist) {
+			http.NotFound(w, req)
+			return
+		}
+		response := &configDTO{
+			configLocation: configLocation{
+				Name:    name,
+				Path:    path.String(),
+				RelPath: relPath.String(),
+			},
+			ProjectDir: relProjectDir.String(),
+		}
+		if err != nil {
+			response.Error = types.AsAgentError(err)
+		} else {
+			response.Configuration = cfg
+		}
+		w.Header().Set("content-type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}
+}
@@ -0,0 +1,134 @@
+package api
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/posit-dev/publisher/internal/config"
+	"github.com/posit-dev/publisher/internal/initialize"
+	"github.com/posit-dev/publisher/internal/inspect"
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/util"
+	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/suite"
+)
+
+type GetResolvedConfigurationSuite struct {
+	utiltest.Suite
+	log logging.Logger
+	cwd util.AbsolutePath
+}
+
+func TestGetResolvedConfigurationSuite(t *testing.T) {
+	suite.Run(t, new(GetResolvedConfigurationSuite))
+}
+
+func (s *GetResolvedConfigurationSuite) SetupSuite() {
+	s.log = logging.New()
+}
+
+func (s *GetResolvedConfigurationSuite) SetupTest() {
+	fs := afero.NewMemMapFs()
+	cwd, err := util.Getwd(fs)
+	s.Nil(err)
+	s.cwd = cwd
+	s.cwd.MkdirAll(0700)
+}
+
+func (s *GetResolvedConfigurationSuite) TearDownTest() {
+	initialize.PythonInspectorFactory = inspect.NewPythonInspector
+}
+
+func (s *GetResolvedConfigurationSuite) TestGetResolvedConfiguration() {
+	cfg := config.New()
+	cfg.Type = config.ContentTypePythonDash
+	cfg.Entrypoint = "app.py"
+	cfg.Python = &config.Python{}
+	err := cfg.WriteFile(config.GetConfigPath(s.cwd, "myConfig"))
+	s.NoError(err)
+
+	var inspectedPython util.Path
+	initialize.PythonInspectorFactory = func(base util.AbsolutePath, python util.Path, log logging.Logger) inspect.PythonInspector {
+		inspectedPython = python
+		i := inspect.NewMockPythonInspector()
+		i.On("InspectPython").Return(&config.Python{
+			Version:        "3.11.3",
+			PackageFile:    "requirements.txt",
+			PackageManager: "pip",
+		}, nil)
+		return i
+	}
+
+	h := GetResolvedConfigurationHandlerFunc(s.cwd, s.log)
+
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/api/configurations/myConfig/resolved?python=/opt/python/bin/python3", nil)
+	s.NoError(err)
+	req = mux.SetURLVars(req, map[string]string{"name": "myConfig"})
+
+	h(rec, req)
+
+	s.Equal(http.StatusOK, rec.Result().StatusCode)
+	s.Equal("application/json", rec.Header().Get("content-type"))
+
+	res := configDTO{}
+	dec := json.NewDecoder(rec.Body)
+	dec.DisallowUnknownFields()
+	s.NoError(dec.Decode(&res))
+
+	s.Equal("myConfig", res.Name)
+	s.Nil(res.Error)
+	s.Equal("/opt/python/bin/python3", inspectedPython.String())
+	s.Equal(&config.Python{
+		Version:        "3.11.3",
+		PackageFile:    "requirements.txt",
+		PackageManager: "pip",
+	}, res.Configuration.Python)
+
+	// The configuration file is unchanged
+	saved, err := config.FromFile(config.GetConfigPath(s.cwd, "myConfig"))
+	s.NoError(err)
+	s.Equal("", saved.Python.Version)
+}
+
+func (s *GetResolvedConfigurationSuite) TestGetResolvedConfigurationNotFound() {
+	h := GetResolvedConfigurationHandlerFunc(s.cwd, s.log)
+
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/api/configurations/myConfig/resolved", nil)
+	s.NoError(err)
+	req = mux.SetURLVars(req, map[string]string{"name": "myConfig"})
+
+	h(rec, req)
+
+	s.Equal(http.StatusNotFound, rec.Result().StatusCode)
+}
+
+func (s *GetResolvedConfigurationSuite) TestGetResolvedConfigurationError() {
+	path := config.GetConfigPath(s.cwd, "myConfig")
+	err := path.WriteFile([]byte(`foo = 1`), 0666)
+	s.NoError(err)
+
+	h := GetResolvedConfigurationHandlerFunc(s.cwd, s.log)
+
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/api/configurations/myConfig/resolved", nil)
+	s.NoError(err)
+	req = mux.SetURLVars(req, map[string]string{"name": "myConfig"})
+
+	h(rec, req)
+
+	s.Equal(http.StatusOK, rec.Result().StatusCode)
+	res := configDTO{}
+	dec := json.NewDecoder(rec.Body)
+	dec.DisallowUnknownFields()
+	s.NoError(dec.Decode(&res))
+	s.NotNil(res.Error)
+	s.Nil(res.Configuration)
+}
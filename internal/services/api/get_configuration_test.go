@@ -85,6 +85,41 @@ func (s *GetConfigurationuite) TestGetConfiguration() {
 	s.Equal(".", res.ProjectDir)
 	s.Nil(res.Error)
 	s.Equal(cfg, res.Configuration)
+	s.Nil(res.Warnings)
+}
+
+func (s *GetConfigurationuite) TestGetConfigurationWarnings() {
+	cfg := config.New()
+	cfg.Type = config.ContentTypePythonDash
+	cfg.Entrypoint = "app.py"
+	cfg.Files = []string{"/requirements.txt"}
+	cfg.Python = &config.Python{
+		Version:        "3.4.5",
+		PackageManager: "pip",
+	}
+	err := cfg.WriteFile(config.GetConfigPath(s.cwd, "myConfig"))
+	s.NoError(err)
+	err = s.cwd.Join("app.py").WriteFile(nil, 0666)
+	s.NoError(err)
+
+	h := GetConfigurationHandlerFunc(s.cwd, s.log)
+
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/api/configurations/myConfig", nil)
+	s.NoError(err)
+	req = mux.SetURLVars(req, map[string]string{"name": "myConfig"})
+
+	h(rec, req)
+
+	s.Equal(http.StatusOK, rec.Result().StatusCode)
+	res := configDTO{}
+	dec := json.NewDecoder(rec.Body)
+	dec.DisallowUnknownFields()
+	s.NoError(dec.Decode(&res))
+
+	s.Nil(res.Error)
+	s.Len(res.Warnings, 1)
+	s.Equal(config.LintEntrypointNotIncluded, res.Warnings[0].Code)
 }
 
 func (s *GetConfigurationuite) TestGetConfigurationError() {
@@ -185,3 +220,47 @@ func (s *GetConfigurationuite) TestGetConfigurationBadDir() {
 
 	s.Equal(http.StatusBadRequest, rec.Result().StatusCode)
 }
+
+func (s *GetConfigurationuite) TestGetConfigurationETag() {
+	s.makeConfiguration("myConfig")
+	h := GetConfigurationHandlerFunc(s.cwd, s.log)
+
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", "/api/configurations/myConfig", nil)
+		s.NoError(err)
+		req = mux.SetURLVars(req, map[string]string{"name": "myConfig"})
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		h(rec, req)
+		return rec
+	}
+
+	rec := get("")
+	s.Equal(http.StatusOK, rec.Result().StatusCode)
+	etag := rec.Header().Get("ETag")
+	s.NotEqual("", etag)
+
+	// Unchanged
+	rec = get(etag)
+	s.Equal(http.StatusNotModified, rec.Result().StatusCode)
+	s.Equal(etag, rec.Header().Get("ETag"))
+	s.Equal(0, rec.Body.Len())
+
+	// Changed
+	path := config.GetConfigPath(s.cwd, "myConfig")
+	cfg, err := config.FromFile(path)
+	s.NoError(err)
+	cfg.Title = "A New Title"
+	s.NoError(cfg.WriteFile(path))
+
+	rec = get(etag)
+	s.Equal(http.StatusOK, rec.Result().StatusCode)
+	newETag := rec.Header().Get("ETag")
+	s.NotEqual(etag, newETag)
+
+	res := configDTO{}
+	s.NoError(json.NewDecoder(rec.Body).Decode(&res))
+	s.Equal("A New Title", res.Configuration.Title)
+}
@@ -3,7 +3,6 @@ package api
 // Copyright (C) 2023 by Posit Software, PBC.
 
 import (
-	"encoding/json"
 	"errors"
 	"io/fs"
 	"net/http"
@@ -26,9 +25,10 @@ type configLocation struct {
 
 type configDTO struct {
 	configLocation
-	ProjectDir    string            `json:"projectDir"` // Relative path to the project directory from the global base
-	Configuration *config.Config    `json:"configuration,omitempty"`
-	Error         *types.AgentError `json:"error,omitempty"`
+	ProjectDir    string               `json:"projectDir"` // Relative path to the project directory from the global base
+	Configuration *config.Config       `json:"configuration,omitempty"`
+	Warnings      []config.LintWarning `json:"warnings,omitempty"`
+	Error         *types.AgentError    `json:"error,omitempty"`
 }
 
 func readConfigFiles(projectDir util.AbsolutePath, relProjectDir util.RelativePath, entrypoint string) ([]configDTO, error) {
@@ -72,6 +72,7 @@ func readConfigFiles(projectDir util.AbsolutePath, relProjectDir util.RelativePa
 				},
 				ProjectDir:    relProjectDir.String(),
 				Configuration: cfg,
+				Warnings:      cfg.Lint(projectDir),
 				Error:         nil,
 			})
 		}
@@ -135,7 +136,6 @@ func GetConfigurationsHandlerFunc(base util.AbsolutePath, log logging.Logger) ht
 			InternalError(w, req, log, err)
 			return
 		}
-		w.Header().Set("content-type", "application/json")
-		json.NewEncoder(w).Encode(response)
+		JsonResultWithETag(w, req, log, response)
 	}
 }
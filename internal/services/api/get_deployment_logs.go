@@ -0,0 +1,188 @@
+package api
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/posit-dev/publisher/internal/accounts"
+	"github.com/posit-dev/publisher/internal/clients/connect"
+	"github.com/posit-dev/publisher/internal/clients/http_client"
+	"github.com/posit-dev/publisher/internal/deployment"
+	"github.com/posit-dev/publisher/internal/events"
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/util"
+)
+
+// How often to check for new log entries when following the logs.
+var contentLogsPollInterval = 2 * time.Second
+
+type deploymentLogsDTO struct {
+	Entries []connect.LogEntry `json:"entries"`
+	// Pass these as `since` and `skip` to get the entries after these.
+	Next time.Time `json:"next"`
+	Skip int       `json:"skip"`
+}
+
+// logCursor is the position in the content's logs. Several entries
+// can have the same timestamp, so it counts the entries at `since`
+// that were already returned; later ones at that time are not lost.
+type logCursor struct {
+	since time.Time
+	skip  int
+}
+
+// next returns the entries that haven't been returned yet, given the
+// entries at or after c.since, and moves the cursor past them.
+func (c *logCursor) next(entries []connect.LogEntry) []connect.LogEntry {
+	skipped := 0
+	for skipped < len(entries) && skipped < c.skip && entries[skipped].Timestamp.Equal(c.since) {
+		skipped++
+	}
+	newEntries := entries[skipped:]
+	if len(newEntries) == 0 {
+		return newEntries
+	}
+	last := newEntries[len(newEntries)-1].Timestamp
+	if !last.Equal(c.since) {
+		c.since = last
+		c.skip = 0
+	}
+	for _, entry := range newEntries {
+		if entry.Timestamp.Equal(last) {
+			c.skip++
+		}
+	}
+	return newEntries
+}
+
+// GetDeploymentLogsHandlerFunc returns the server-side logs of the
+// deployed content. Query parameters:
+//   - since: only return entries at or after this RFC 3339 timestamp.
+//   - skip: how many of the entries at `since` to leave out,
+//     because they were already returned.
+//   - follow: if true, stream the entries as newline-delimited JSON,
+//     checking for new ones until the request is cancelled.
+func GetDeploymentLogsHandlerFunc(base util.AbsolutePath, log logging.Logger, accountList accounts.AccountList) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		name := mux.Vars(req)["name"]
+		projectDir, _, err := ProjectDirFromRequest(base, w, req, log)
+		if err != nil {
+			// Response already returned by ProjectDirFromRequest
+			return
+		}
+		query := req.URL.Query()
+		var since time.Time
+		if s := query.Get("since"); s != "" {
+			since, err = time.Parse(time.RFC3339Nano, s)
+			if err != nil {
+				BadRequest(w, req, log, fmt.Errorf("invalid since parameter: %w", err))
+				return
+			}
+		}
+		skip := 0
+		if s := query.Get("skip"); s != "" {
+			skip, err = strconv.Atoi(s)
+			if err != nil || skip < 0 {
+				BadRequest(w, req, log, fmt.Errorf("invalid skip parameter: %s", s))
+				return
+			}
+		}
+		follow := false
+		if f := query.Get("follow"); f != "" {
+			follow, err = strconv.ParseBool(f)
+			if err != nil {
+				BadRequest(w, req, log, fmt.Errorf("invalid follow parameter: %w", err))
+				return
+			}
+		}
+
+		path := deployment.GetDeploymentPath(projectDir, name)
+		d, err := deployment.FromFile(path)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				http.NotFound(w, req)
+				return
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(fmt.Sprintf("deployment %s is invalid: %s", name, err)))
+			return
+		}
+		if !d.IsDeployed() {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(fmt.Sprintf("deployment %s is not deployed", name)))
+			return
+		}
+		account, err := accountList.GetAccountByServerURL(d.ServerURL)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(fmt.Sprintf("no credential found to use with deployment %s", name)))
+			return
+		}
+		client, err := clientFactory(account, 30*time.Second, events.NewNullEmitter(), log)
+		if err != nil {
+			InternalError(w, req, log, err)
+			return
+		}
+
+		cursor := logCursor{since: since, skip: skip}
+		entries, err := client.GetContentLogs(d.ID, cursor.since, log)
+		if err != nil {
+			httpErr, ok := err.(*http_client.HTTPError)
+			if ok {
+				// Pass through HTTP Error from Connect
+				w.WriteHeader(httpErr.Status)
+				w.Write([]byte(httpErr.Error()))
+				return
+			}
+			InternalError(w, req, log, err)
+			return
+		}
+		entries = cursor.next(entries)
+		if !follow {
+			JsonResult(w, http.StatusOK, deploymentLogsDTO{
+				Entries: entries,
+				Next:    cursor.since,
+				Skip:    cursor.skip,
+			})
+			return
+		}
+
+		w.Header().Set("content-type", "application/x-ndjson")
+		w.WriteHeader(http.StatusOK)
+		enc := json.NewEncoder(w)
+		flusher, _ := w.(http.Flusher)
+		for {
+			for _, entry := range entries {
+				err = enc.Encode(entry)
+				if err != nil {
+					// The client has gone away.
+					return
+				}
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+
+			select {
+			case <-req.Context().Done():
+				return
+			case <-time.After(contentLogsPollInterval):
+			}
+			entries, err = client.GetContentLogs(d.ID, cursor.since, log)
+			if err != nil {
+				// The response has started, so all we can do is stop.
+				log.Error("Error getting content logs", "deployment", name, "error", err.Error())
+				return
+			}
+			entries = cursor.next(entries)
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/posit-dev/publisher/internal/accounts"
+	"github.com/posit-dev/publisher/internal/clients/connect"
+	"github.com/posit-dev/publisher/internal/clients/http_client"
+	"github.com/posit-dev/publisher/internal/deployment"
+	"github.com/posit-dev/publisher/internal/events"
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/types"
+	"github.com/posit-dev/publisher/internal/util"
+	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+type GetDeploymentLogsSuite struct {
+	utiltest.Suite
+	log    logging.Logger
+	cwd    util.AbsolutePath
+	lister *accounts.MockAccountList
+}
+
+func TestGetDeploymentLogsSuite(t *testing.T) {
+	suite.Run(t, new(GetDeploymentLogsSuite))
+}
+
+func (s *GetDeploymentLogsSuite) SetupSuite() {
+	s.log = logging.New()
+}
+
+func (s *GetDeploymentLogsSuite) SetupTest() {
+	fs := afero.NewMemMapFs()
+	cwd, err := util.Getwd(fs)
+	s.Nil(err)
+	s.cwd = cwd
+	s.cwd.MkdirAll(0700)
+
+	clientFactory = connect.NewConnectClient
+
+	path := deployment.GetDeploymentPath(s.cwd, "dep")
+	d := deployment.New()
+	d.ID = "123"
+	d.ServerURL = "https://connect.example.com"
+	d.WriteFile(path)
+
+	s.lister = &accounts.MockAccountList{}
+	acct := &accounts.Account{
+		Name:       "myAccount",
+		URL:        "https://connect.example.com",
+		ServerType: accounts.ServerTypeConnect,
+	}
+	s.lister.On("GetAccountByServerURL", "https://connect.example.com").Return(acct, nil)
+}
+
+func (s *GetDeploymentLogsSuite) useClient(client connect.APIClient) {
+	clientFactory = func(account *accounts.Account, timeout time.Duration, emitter events.Emitter, log logging.Logger) (connect.APIClient, error) {
+		return client, nil
+	}
+}
+
+func (s *GetDeploymentLogsSuite) TestGetDeploymentLogs() {
+	since := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	entries := []connect.LogEntry{
+		{Source: "stdout", Timestamp: since.Add(time.Minute), Data: "one"},
+		{Source: "stderr", Timestamp: since.Add(2 * time.Minute), Data: "two"},
+	}
+	client := connect.NewMockClient()
+	client.On("GetContentLogs", types.ContentID("123"), since, s.log).Return(entries, nil)
+	s.useClient(client)
+
+	h := GetDeploymentLogsHandlerFunc(s.cwd, s.log, s.lister)
+
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/api/deployments/dep/logs?since=2024-06-01T12:00:00Z", nil)
+	s.NoError(err)
+	req = mux.SetURLVars(req, map[string]string{"name": "dep"})
+	h(rec, req)
+
+	s.Equal(http.StatusOK, rec.Result().StatusCode)
+	res := deploymentLogsDTO{}
+	dec := json.NewDecoder(rec.Body)
+	dec.DisallowUnknownFields()
+	s.NoError(dec.Decode(&res))
+	s.Equal([]string{"one", "two"}, []string{res.Entries[0].Data, res.Entries[1].Data})
+	s.True(since.Add(2 * time.Minute).Equal(res.Next))
+}
+
+func (s *GetDeploymentLogsSuite) TestGetDeploymentLogsNoEntries() {
+	client := connect.NewMockClient()
+	client.On("GetContentLogs", types.ContentID("123"), time.Time{}, s.log).Return([]connect.LogEntry{}, nil)
+	s.useClient(client)
+
+	h := GetDeploymentLogsHandlerFunc(s.cwd, s.log, s.lister)
+
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/api/deployments/dep/logs", nil)
+	s.NoError(err)
+	req = mux.SetURLVars(req, map[string]string{"name": "dep"})
+	h(rec, req)
+
+	s.Equal(http.StatusOK, rec.Result().StatusCode)
+	res := deploymentLogsDTO{}
+	s.NoError(json.NewDecoder(rec.Body).Decode(&res))
+	s.Len(res.Entries, 0)
+	s.True(res.Next.IsZero())
+}
+
+func (s *GetDeploymentLogsSuite) TestGetDeploymentLogsFollow() {
+	oldInterval := contentLogsPollInterval
+	contentLogsPollInterval = time.Millisecond
+	defer func() { contentLogsPollInterval = oldInterval }()
+
+	first := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	second := first.Add(time.Minute)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := connect.NewMockClient()
+	client.On("GetContentLogs", types.ContentID("123"), time.Time{}, s.log).Return(
+		[]connect.LogEntry{{Source: "stdout", Timestamp: first, Data: "one"}}, nil)
+	// The next poll starts from the last entry.
+	client.On("GetContentLogs", types.ContentID("123"), first, s.log).Return(
+		[]connect.LogEntry{{Source: "stdout", Timestamp: second, Data: "two"}}, nil)
+	client.On("GetContentLogs", types.ContentID("123"), second, s.log).Return(
+		[]connect.LogEntry{}, nil).Run(func(mock.Arguments) {
+		cancel()
+	})
+	s.useClient(client)
+
+	h := GetDeploymentLogsHandlerFunc(s.cwd, s.log, s.lister)
+
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequestWithContext(ctx, "GET", "/api/deployments/dep/logs?follow=true", nil)
+	s.NoError(err)
+	req = mux.SetURLVars(req, map[string]string{"name": "dep"})
+	h(rec, req)
+
+	s.Equal(http.StatusOK, rec.Result().StatusCode)
+	s.Equal("application/x-ndjson", rec.Header().Get("content-type"))
+	lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+	s.Len(lines, 2)
+	var entry connect.LogEntry
+	s.NoError(json.Unmarshal([]byte(lines[1]), &entry))
+	s.Equal("two", entry.Data)
+}
+
+func (s *GetDeploymentLogsSuite) TestGetDeploymentLogsSkip() {
+	since := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	entries := []connect.LogEntry{
+		{Source: "stdout", Timestamp: since, Data: "seen"},
+		{Source: "stdout", Timestamp: since, Data: "one"},
+		{Source: "stderr", Timestamp: since.Add(time.Minute), Data: "two"},
+		{Source: "stderr", Timestamp: since.Add(time.Minute), Data: "three"},
+	}
+	client := connect.NewMockClient()
+	client.On("GetContentLogs", types.ContentID("123"), since, s.log).Return(entries, nil)
+	s.useClient(client)
+
+	h := GetDeploymentLogsHandlerFunc(s.cwd, s.log, s.lister)
+
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/api/deployments/dep/logs?since=2024-06-01T12:00:00Z&skip=1", nil)
+	s.NoError(err)
+	req = mux.SetURLVars(req, map[string]string{"name": "dep"})
+	h(rec, req)
+
+	s.Equal(http.StatusOK, rec.Result().StatusCode)
+	res := deploymentLogsDTO{}
+	s.NoError(json.NewDecoder(rec.Body).Decode(&res))
+	data := []string{}
+	for _, entry := range res.Entries {
+		data = append(data, entry.Data)
+	}
+	s.Equal([]string{"one", "two", "three"}, data)
+	s.True(since.Add(time.Minute).Equal(res.Next))
+	s.Equal(2, res.Skip)
+}
+
+func (s *GetDeploymentLogsSuite) TestGetDeploymentLogsFollowSameTimestamp() {
+	oldInterval := contentLogsPollInterval
+	contentLogsPollInterval = time.Millisecond
+	defer func() { contentLogsPollInterval = oldInterval }()
+
+	ts := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	one := connect.LogEntry{Source: "stdout", Timestamp: ts, Data: "one"}
+	two := connect.LogEntry{Source: "stdout", Timestamp: ts, Data: "two"}
+	client := connect.NewMockClient()
+	client.On("GetContentLogs", types.ContentID("123"), time.Time{}, s.log).Return(
+		[]connect.LogEntry{one}, nil)
+	// Another entry was written with the same timestamp.
+	client.On("GetContentLogs", types.ContentID("123"), ts, s.log).Return(
+		[]connect.LogEntry{one, two}, nil).Once()
+	client.On("GetContentLogs", types.ContentID("123"), ts, s.log).Return(
+		[]connect.LogEntry{one, two}, nil).Run(func(mock.Arguments) {
+		cancel()
+	})
+	s.useClient(client)
+
+	h := GetDeploymentLogsHandlerFunc(s.cwd, s.log, s.lister)
+
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequestWithContext(ctx, "GET", "/api/deployments/dep/logs?follow=true", nil)
+	s.NoError(err)
+	req = mux.SetURLVars(req, map[string]string{"name": "dep"})
+	h(rec, req)
+
+	s.Equal(http.StatusOK, rec.Result().StatusCode)
+	lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+	data := []string{}
+	for _, line := range lines {
+		var entry connect.LogEntry
+		s.NoError(json.Unmarshal([]byte(line), &entry))
+		data = append(data, entry.Data)
+	}
+	s.Equal([]string{"one", "two"}, data)
+}
+
+func (s *GetDeploymentLogsSuite) TestGetDeploymentLogsBadSince() {
+	h := GetDeploymentLogsHandlerFunc(s.cwd, s.log, s.lister)
+
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/api/deployments/dep/logs?since=yesterday", nil)
+	s.NoError(err)
+	req = mux.SetURLVars(req, map[string]string{"name": "dep"})
+	h(rec, req)
+
+	s.Equal(http.StatusBadRequest, rec.Result().StatusCode)
+}
+
+func (s *GetDeploymentLogsSuite) TestGetDeploymentLogsNotDeployed() {
+	path := deployment.GetDeploymentPath(s.cwd, "predeployment")
+	d := deployment.New()
+	d.WriteFile(path)
+
+	h := GetDeploymentLogsHandlerFunc(s.cwd, s.log, s.lister)
+
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/api/deployments/predeployment/logs", nil)
+	s.NoError(err)
+	req = mux.SetURLVars(req, map[string]string{"name": "predeployment"})
+	h(rec, req)
+
+	s.Equal(http.StatusBadRequest, rec.Result().StatusCode)
+	body, _ := io.ReadAll(rec.Body)
+	s.Contains(string(body), "deployment predeployment is not deployed")
+}
+
+func (s *GetDeploymentLogsSuite) TestGetDeploymentLogsPassesStatusFromServer() {
+	client := connect.NewMockClient()
+	httpErr := http_client.NewHTTPError("https://connect.example.com", "GET", http.StatusNotFound)
+	client.On("GetContentLogs", types.ContentID("123"), time.Time{}, s.log).Return(nil, httpErr)
+	s.useClient(client)
+
+	h := GetDeploymentLogsHandlerFunc(s.cwd, s.log, s.lister)
+
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/api/deployments/dep/logs", nil)
+	s.NoError(err)
+	req = mux.SetURLVars(req, map[string]string{"name": "dep"})
+	h(rec, req)
+
+	s.Equal(http.StatusNotFound, rec.Result().StatusCode)
+}
@@ -3,7 +3,6 @@ package api
 // Copyright (C) 2023 by Posit Software, PBC.
 
 import (
-	"encoding/json"
 	"net/http"
 
 	"github.com/posit-dev/publisher/internal/bundles/matcher"
@@ -13,15 +12,24 @@ import (
 	"github.com/posit-dev/publisher/internal/util"
 )
 
-func GetFileHandlerFunc(base util.AbsolutePath, filesService files.FilesService, pathsService paths.PathsService, log logging.Logger) http.HandlerFunc {
+var pathsServiceFactory = paths.CreatePathsService
+
+func GetFileHandlerFunc(base util.AbsolutePath, filesService files.FilesService, log logging.Logger) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		projectDir, _, err := ProjectDirFromRequest(base, w, r, log)
+		if err != nil {
+			// Response already returned by ProjectDirFromRequest
+			return
+		}
 		var p util.AbsolutePath
 		if q := r.URL.Query(); q.Has("pathname") {
-			p = base.Join(q.Get("pathname"))
+			p = projectDir.Join(q.Get("pathname"))
 		} else {
-			p = base
+			p = projectDir
 		}
 
+		// Only files in the requested project are allowed.
+		pathsService := pathsServiceFactory(projectDir, log)
 		ok, err := pathsService.IsSafe(p)
 		if err != nil {
 			InternalError(w, r, log, err)
@@ -35,7 +43,7 @@ func GetFileHandlerFunc(base util.AbsolutePath, filesService files.FilesService,
 			w.Write([]byte(http.StatusText(http.StatusForbidden)))
 			return
 		}
-		matchList, err := matcher.NewMatchList(base, matcher.StandardExclusions)
+		matchList, err := matcher.NewMatchList(projectDir, matcher.StandardExclusions)
 		if err != nil {
 			InternalError(w, r, log, err)
 			return
@@ -47,7 +55,6 @@ func GetFileHandlerFunc(base util.AbsolutePath, filesService files.FilesService,
 			return
 		}
 
-		w.Header().Set("content-type", "application/json")
-		json.NewEncoder(w).Encode(file)
+		JsonResultWithETag(w, r, log, file)
 	}
 }
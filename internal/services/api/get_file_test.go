@@ -11,6 +11,7 @@ import (
 
 	"github.com/posit-dev/publisher/internal/logging"
 	"github.com/posit-dev/publisher/internal/services/api/files"
+	"github.com/posit-dev/publisher/internal/services/api/paths"
 	"github.com/posit-dev/publisher/internal/util"
 	"github.com/posit-dev/publisher/internal/util/utiltest"
 	"github.com/spf13/afero"
@@ -31,6 +32,18 @@ func (s *GetFileHandlerFuncSuite) SetupSuite() {
 	s.log = logging.New()
 }
 
+func (s *GetFileHandlerFuncSuite) TearDownTest() {
+	pathsServiceFactory = paths.CreatePathsService
+}
+
+// newHandler returns a handler that checks paths using pathsService.
+func (s *GetFileHandlerFuncSuite) newHandler(base util.AbsolutePath, filesService files.FilesService, pathsService paths.PathsService) http.HandlerFunc {
+	pathsServiceFactory = func(util.AbsolutePath, logging.Logger) paths.PathsService {
+		return pathsService
+	}
+	return GetFileHandlerFunc(base, filesService, s.log)
+}
+
 func (s *GetFileHandlerFuncSuite) TestGetFileHandlerFunc() {
 	files := new(MockFilesService)
 	files.On("GetFile", mock.Anything, mock.Anything).Return(nil, nil)
@@ -41,8 +54,9 @@ func (s *GetFileHandlerFuncSuite) TestGetFileHandlerFunc() {
 	afs := afero.NewMemMapFs()
 	base, err := util.Getwd(afs)
 	s.NoError(err)
+	s.NoError(base.MkdirAll(0777))
 
-	h := GetFileHandlerFunc(base, files, paths, s.log)
+	h := s.newHandler(base, files, paths)
 	s.NotNil(h)
 }
 
@@ -51,6 +65,7 @@ func (s *GetFileHandlerFuncSuite) TestHandlerFunc() {
 	afs := afero.NewMemMapFs()
 	base, err := util.Getwd(afs)
 	s.NoError(err)
+	s.NoError(base.MkdirAll(0777))
 
 	src := &files.File{Rel: "."}
 
@@ -60,7 +75,7 @@ func (s *GetFileHandlerFuncSuite) TestHandlerFunc() {
 	pathsService := new(MockPathsService)
 	pathsService.On("IsSafe", mock.Anything).Return(true, nil)
 
-	h := GetFileHandlerFunc(base, filesService, pathsService, s.log)
+	h := s.newHandler(base, filesService, pathsService)
 
 	rec := httptest.NewRecorder()
 
@@ -85,6 +100,7 @@ func (s *GetFileHandlerFuncSuite) TestHandlerFuncUsingPathname() {
 	afs := afero.NewMemMapFs()
 	base, err := util.Getwd(afs)
 	s.NoError(err)
+	s.NoError(base.MkdirAll(0777))
 
 	pathname := "pathname"
 	src := &files.File{Rel: pathname}
@@ -95,7 +111,7 @@ func (s *GetFileHandlerFuncSuite) TestHandlerFuncUsingPathname() {
 	pathsService := new(MockPathsService)
 	pathsService.On("IsSafe", mock.Anything).Return(true, nil)
 
-	h := GetFileHandlerFunc(base, filesService, pathsService, s.log)
+	h := s.newHandler(base, filesService, pathsService)
 
 	rec := httptest.NewRecorder()
 
@@ -120,13 +136,14 @@ func (s *GetFileHandlerFuncSuite) TestHandlerFuncIsSafeReturnsError() {
 	afs := afero.NewMemMapFs()
 	base, err := util.Getwd(afs)
 	s.NoError(err)
+	s.NoError(base.MkdirAll(0777))
 
 	filesService := new(MockFilesService)
 
 	pathsService := new(MockPathsService)
 	pathsService.On("IsSafe", mock.Anything).Return(false, errors.New(""))
 
-	h := GetFileHandlerFunc(base, filesService, pathsService, s.log)
+	h := s.newHandler(base, filesService, pathsService)
 
 	rec := httptest.NewRecorder()
 
@@ -142,13 +159,14 @@ func (s *GetFileHandlerFuncSuite) TestHandlerFuncIsSafeReturnsFalse() {
 	afs := afero.NewMemMapFs()
 	base, err := util.Getwd(afs)
 	s.NoError(err)
+	s.NoError(base.MkdirAll(0777))
 
 	filesService := new(MockFilesService)
 
 	pathsService := new(MockPathsService)
 	pathsService.On("IsSafe", mock.Anything).Return(false, nil)
 
-	h := GetFileHandlerFunc(base, filesService, pathsService, s.log)
+	h := s.newHandler(base, filesService, pathsService)
 
 	rec := httptest.NewRecorder()
 
@@ -164,6 +182,7 @@ func (s *GetFileHandlerFuncSuite) TestHandlerFuncGetFileReturnsError() {
 	afs := afero.NewMemMapFs()
 	base, err := util.Getwd(afs)
 	s.NoError(err)
+	s.NoError(base.MkdirAll(0777))
 
 	src := &files.File{Rel: base.String()}
 
@@ -173,7 +192,7 @@ func (s *GetFileHandlerFuncSuite) TestHandlerFuncGetFileReturnsError() {
 	pathsService := new(MockPathsService)
 	pathsService.On("IsSafe", mock.Anything).Return(true, nil)
 
-	h := GetFileHandlerFunc(base, filesService, pathsService, s.log)
+	h := s.newHandler(base, filesService, pathsService)
 
 	rec := httptest.NewRecorder()
 
@@ -184,3 +203,124 @@ func (s *GetFileHandlerFuncSuite) TestHandlerFuncGetFileReturnsError() {
 
 	s.Equal(http.StatusInternalServerError, rec.Result().StatusCode)
 }
+
+func (s *GetFileHandlerFuncSuite) TestHandlerFuncSubdir() {
+	afs := afero.NewMemMapFs()
+	base, err := util.Getwd(afs)
+	s.NoError(err)
+	projectDir := base.Join("subproject")
+	s.NoError(projectDir.MkdirAll(0777))
+
+	src := &files.File{Rel: "app.py"}
+
+	filesService := new(MockFilesService)
+	filesService.On("GetFile", projectDir.Join("app.py"), mock.Anything).Return(src, nil)
+
+	pathsService := new(MockPathsService)
+	pathsService.On("IsSafe", projectDir.Join("app.py")).Return(true, nil)
+
+	h := s.newHandler(base, filesService, pathsService)
+
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "?pathname=app.py", nil)
+	s.NoError(err)
+	req.Header.Set(ProjectDirHeader, "subproject")
+
+	h(rec, req)
+
+	s.Equal(http.StatusOK, rec.Result().StatusCode)
+	filesService.AssertExpectations(s.T())
+}
+
+func (s *GetFileHandlerFuncSuite) TestHandlerFuncSubdirPathOutsideProject() {
+	afs := afero.NewMemMapFs()
+	base, err := util.Getwd(afs)
+	s.NoError(err)
+	projectDir := base.Join("subproject")
+	s.NoError(projectDir.MkdirAll(0777))
+	s.NoError(base.Join("other.txt").WriteFile([]byte("other"), 0666))
+
+	// The real paths service, for the project directory.
+	filesService := new(MockFilesService)
+	h := GetFileHandlerFunc(base, filesService, s.log)
+
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "?pathname=../other.txt", nil)
+	s.NoError(err)
+	req.Header.Set(ProjectDirHeader, "subproject")
+
+	h(rec, req)
+
+	s.Equal(http.StatusForbidden, rec.Result().StatusCode)
+	filesService.AssertNotCalled(s.T(), "GetFile", mock.Anything, mock.Anything)
+}
+
+func (s *GetFileHandlerFuncSuite) TestHandlerFuncSubdirOutsideBase() {
+	afs := afero.NewMemMapFs()
+	base, err := util.Getwd(afs)
+	s.NoError(err)
+	s.NoError(base.MkdirAll(0777))
+
+	filesService := new(MockFilesService)
+	pathsService := new(MockPathsService)
+	h := s.newHandler(base, filesService, pathsService)
+
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "?dir=..", nil)
+	s.NoError(err)
+
+	h(rec, req)
+
+	s.Equal(http.StatusBadRequest, rec.Result().StatusCode)
+	filesService.AssertNotCalled(s.T(), "GetFile", mock.Anything, mock.Anything)
+}
+
+func (s *GetFileHandlerFuncSuite) TestHandlerFuncETag() {
+	afs := afero.NewMemMapFs()
+	base, err := util.Getwd(afs)
+	s.NoError(err)
+	s.NoError(base.MkdirAll(0777))
+
+	src := &files.File{Rel: ".", Size: 100}
+
+	filesService := new(MockFilesService)
+	filesService.On("GetFile", mock.Anything, mock.Anything).Return(src, nil)
+
+	pathsService := new(MockPathsService)
+	pathsService.On("IsSafe", mock.Anything).Return(true, nil)
+
+	h := s.newHandler(base, filesService, pathsService)
+
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", "", nil)
+		s.NoError(err)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		h(rec, req)
+		return rec
+	}
+
+	rec := get("")
+	s.Equal(http.StatusOK, rec.Result().StatusCode)
+	etag := rec.Header().Get("ETag")
+	s.NotEqual("", etag)
+
+	// Unchanged, including weak and multiple validators
+	for _, ifNoneMatch := range []string{etag, "W/" + etag, `"other", ` + etag, "*"} {
+		rec = get(ifNoneMatch)
+		s.Equal(http.StatusNotModified, rec.Result().StatusCode)
+		s.Equal(0, rec.Body.Len())
+	}
+
+	// Changed
+	src.Size = 200
+	rec = get(etag)
+	s.Equal(http.StatusOK, rec.Result().StatusCode)
+	s.NotEqual(etag, rec.Header().Get("ETag"))
+
+	res := &files.File{}
+	s.NoError(json.NewDecoder(rec.Body).Decode(res))
+	s.Equal(int64(200), res.Size)
+}
@@ -0,0 +1,79 @@
+package api
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/services/api/files"
+	"github.com/posit-dev/publisher/internal/util"
+)
+
+var upgrader = websocket.Upgrader{
+	// Like the rest of the API, allow any origin.
+	// The UI is served from a webview with its own origin.
+	CheckOrigin: func(*http.Request) bool { return true },
+}
+
+type filesWatchMessage struct {
+	Changes []files.Change `json:"changes"`
+}
+
+// GetFilesWatchHandlerFunc upgrades the request to a websocket,
+// then sends a message each time files in the project directory change.
+func GetFilesWatchHandlerFunc(base util.AbsolutePath, debounce time.Duration, log logging.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		projectDir, _, err := ProjectDirFromRequest(base, w, req, log)
+		if err != nil {
+			// Response already returned by ProjectDirFromRequest
+			return
+		}
+		watcher, err := files.NewWatcher(projectDir, debounce, log)
+		if err != nil {
+			InternalError(w, req, log, err)
+			return
+		}
+		defer watcher.Close()
+
+		conn, err := upgrader.Upgrade(w, req, nil)
+		if err != nil {
+			// Upgrade already returned an error response
+			log.Error("cannot open websocket", "error", err.Error())
+			return
+		}
+		defer conn.Close()
+
+		// Messages from the client are not used, but reading is
+		// how we find out that the client has gone away.
+		closed := make(chan struct{})
+		go func() {
+			defer close(closed)
+			for {
+				_, _, err := conn.ReadMessage()
+				if err != nil {
+					return
+				}
+			}
+		}()
+
+		for {
+			select {
+			case <-closed:
+				return
+			case changes, ok := <-watcher.Changes:
+				if !ok {
+					return
+				}
+				err = conn.WriteJSON(filesWatchMessage{Changes: changes})
+				if err != nil {
+					log.Debug("cannot send file changes", "error", err.Error())
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package api
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/services/api/files"
+	"github.com/posit-dev/publisher/internal/util"
+	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/stretchr/testify/suite"
+)
+
+type GetFilesWatchSuite struct {
+	utiltest.Suite
+	log logging.Logger
+	cwd util.AbsolutePath
+}
+
+func TestGetFilesWatchSuite(t *testing.T) {
+	suite.Run(t, new(GetFilesWatchSuite))
+}
+
+func (s *GetFilesWatchSuite) SetupTest() {
+	// fsnotify watches the real filesystem.
+	s.log = logging.New()
+	s.cwd = util.NewAbsolutePath(s.T().TempDir(), nil)
+	s.NoError(s.cwd.Join("subdir").MkdirAll(0777))
+	s.NoError(s.cwd.Join("subdir", "app.py").WriteFile([]byte("import flask\n"), 0666))
+}
+
+func (s *GetFilesWatchSuite) TestWatch() {
+	h := GetFilesWatchHandlerFunc(s.cwd, 10*time.Millisecond, s.log)
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(srv.URL, "http") + "/api/files/watch?dir=subdir"
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	s.NoError(err)
+	defer conn.Close()
+
+	s.NoError(s.cwd.Join("subdir", "app.py").WriteFile([]byte("import dash\n"), 0666))
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var msg filesWatchMessage
+	s.NoError(conn.ReadJSON(&msg))
+	s.Equal([]files.Change{{Kind: files.ChangeUpdated, Rel: "app.py"}}, msg.Changes)
+}
+
+func (s *GetFilesWatchSuite) TestWatchDirOutsideBase() {
+	h := GetFilesWatchHandlerFunc(s.cwd, 10*time.Millisecond, s.log)
+
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/api/files/watch?dir=..", nil)
+	s.NoError(err)
+	h(rec, req)
+
+	s.Equal(http.StatusBadRequest, rec.Result().StatusCode)
+}
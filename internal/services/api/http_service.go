@@ -40,11 +40,18 @@ func newHTTPService(
 	openBrowser bool,
 	openBrowserAt string,
 	accessLog bool,
+	compress bool,
+	maxRequestBodySize int64,
 	log logging.Logger) *Service {
 
+	if compress {
+		handler = middleware.Gzip(middleware.DefaultGzipMinSize, handler)
+	}
 	if accessLog {
 		handler = middleware.LogRequest("Access Log", log, handler)
 	}
+	// Limit the body before anything reads it, including the access log.
+	handler = middleware.LimitRequestBody(maxRequestBodySize, handler)
 	handler = middleware.PanicRecovery(log, handler)
 
 	return &Service{
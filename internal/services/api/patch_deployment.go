@@ -16,8 +16,10 @@ import (
 // Copyright (C) 2023 by Posit Software, PBC.
 
 type PatchDeploymentRequestBody struct {
-	ConfigName string          `json:"configurationName"`
-	ID         types.ContentID `json:"id"`
+	ConfigName string            `json:"configurationName"`
+	ID         types.ContentID   `json:"id"`
+	Note       *string           `json:"note"`
+	Labels     map[string]string `json:"labels"`
 }
 
 func PatchDeploymentHandlerFunc(
@@ -85,6 +87,17 @@ func PatchDeploymentHandlerFunc(
 			d.LogsURL = util.GetLogsURL(d.ServerURL, b.ID)
 			d.DirectURL = util.GetDirectURL(d.ServerURL, b.ID)
 		}
+		// The note and labels are replaced when present,
+		// so they can be cleared with an empty value.
+		if b.Note != nil {
+			d.Note = *b.Note
+		}
+		if b.Labels != nil {
+			d.Labels = b.Labels
+			if len(d.Labels) == 0 {
+				d.Labels = nil
+			}
+		}
 
 		err = d.WriteFile(path)
 		if err != nil {
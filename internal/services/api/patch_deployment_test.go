@@ -227,3 +227,59 @@ func (s *PatchDeploymentHandlerFuncSuite) TestPatchDeploymentBadDir() {
 
 	s.Equal(http.StatusBadRequest, rec.Result().StatusCode)
 }
+
+func (s *PatchDeploymentHandlerFuncSuite) TestPatchDeploymentHandlerFuncWithNoteAndLabels() {
+	log := logging.New()
+
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest("PATCH", "/api/deployments/myTargetName", nil)
+	s.NoError(err)
+	req = mux.SetURLVars(req, map[string]string{"name": "myTargetName"})
+
+	path := deployment.GetDeploymentPath(s.cwd, "myTargetName")
+	d := deployment.New()
+	d.Labels = map[string]string{"old": "label"}
+	err = d.WriteFile(path)
+	s.NoError(err)
+
+	req.Body = io.NopCloser(strings.NewReader(`{"note": "prod release", "labels": {"environment": "production"}}`))
+
+	handler := PatchDeploymentHandlerFunc(s.cwd, log)
+	handler(rec, req)
+
+	s.Equal(http.StatusOK, rec.Result().StatusCode)
+	s.Contains(rec.Body.String(), `"note":"prod release"`)
+	s.Contains(rec.Body.String(), `"labels":{"environment":"production"}`)
+
+	updated, err := deployment.FromFile(path)
+	s.NoError(err)
+	s.Equal("prod release", updated.Note)
+	s.Equal(map[string]string{"environment": "production"}, updated.Labels)
+}
+
+func (s *PatchDeploymentHandlerFuncSuite) TestPatchDeploymentHandlerFuncClearNoteAndLabels() {
+	log := logging.New()
+
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest("PATCH", "/api/deployments/myTargetName", nil)
+	s.NoError(err)
+	req = mux.SetURLVars(req, map[string]string{"name": "myTargetName"})
+
+	path := deployment.GetDeploymentPath(s.cwd, "myTargetName")
+	d := deployment.New()
+	d.Annotate("prod release", map[string]string{"environment": "production"})
+	err = d.WriteFile(path)
+	s.NoError(err)
+
+	req.Body = io.NopCloser(strings.NewReader(`{"note": "", "labels": {}}`))
+
+	handler := PatchDeploymentHandlerFunc(s.cwd, log)
+	handler(rec, req)
+
+	s.Equal(http.StatusOK, rec.Result().StatusCode)
+
+	updated, err := deployment.FromFile(path)
+	s.NoError(err)
+	s.Equal("", updated.Note)
+	s.Nil(updated.Labels)
+}
@@ -0,0 +1,112 @@
+package api
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/posit-dev/publisher/internal/accounts"
+	"github.com/posit-dev/publisher/internal/credentials"
+	"github.com/posit-dev/publisher/internal/events"
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/types"
+)
+
+type postAccountsRequest struct {
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	ApiKey string `json:"apiKey"`
+	Force  bool   `json:"force"` // Save without verifying the credentials
+}
+
+// verifyAccountCredentials checks that the API key
+// can authenticate to the server.
+func verifyAccountCredentials(b *postAccountsRequest, log logging.Logger) error {
+	account := &accounts.Account{
+		ServerType: accounts.ServerTypeConnect,
+		AuthType:   accounts.AuthTypeAPIKey,
+		Name:       b.Name,
+		URL:        b.URL,
+		ApiKey:     b.ApiKey,
+	}
+	client, err := clientFactory(account, 30*time.Second, events.NewNullEmitter(), log)
+	if err != nil {
+		return err
+	}
+	_, err = client.TestAuthentication(log)
+	return err
+}
+
+// newCredentialsService returns the credentials store. If it's
+// unavailable, an error response has been written and ok is false.
+func newCredentialsService(w http.ResponseWriter, req *http.Request, log logging.Logger) (credentials.CredentialsService, bool) {
+	cs, err := credentials.NewCredentialsService(log)
+	if err != nil {
+		if aerr, ok := err.(*types.AgentError); ok {
+			if aerr.Code == types.ErrorCredentialServiceUnavailable {
+				apiErr := types.APIErrorCredentialsUnavailableFromAgentError(*aerr)
+				apiErr.JSONResponse(w)
+				return nil, false
+			}
+		}
+		InternalError(w, req, log, err)
+		return nil, false
+	}
+	return cs, true
+}
+
+// PostAccountsHandlerFunc returns a handler that saves a new
+// account in the credentials store. The credentials are verified
+// against the server first, unless the request sets force.
+func PostAccountsHandlerFunc(log logging.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		dec := json.NewDecoder(req.Body)
+		dec.DisallowUnknownFields()
+		var b postAccountsRequest
+		err := dec.Decode(&b)
+		if err != nil {
+			BadRequest(w, req, log, err)
+			return
+		}
+		if b.Name == "" || b.URL == "" || b.ApiKey == "" {
+			BadRequest(w, req, log, credentials.NewIncompleteCredentialError())
+			return
+		}
+		if !b.Force {
+			err = verifyAccountCredentials(&b, log)
+			if err != nil {
+				log.Info("Not saving account with unverified credentials", "name", b.Name, "url", b.URL, "error", err.Error())
+				JsonResult(w, http.StatusUnprocessableEntity, types.AsAgentError(err))
+				return
+			}
+		}
+		cs, ok := newCredentialsService(w, req, log)
+		if !ok {
+			return
+		}
+		cred, err := cs.Set(credentials.CreateCredentialDetails{
+			Name:   b.Name,
+			URL:    b.URL,
+			ApiKey: b.ApiKey,
+		})
+		if err != nil {
+			switch e := err.(type) {
+			case *credentials.NameCollisionError:
+				apiErr := types.APIErrorCredentialCollisionFromDetails(types.ErrorCredentialNameCollision, e.Name, e.URL)
+				apiErr.JSONResponse(w)
+			case *credentials.URLCollisionError:
+				apiErr := types.APIErrorCredentialCollisionFromDetails(types.ErrorCredentialURLCollision, e.Name, e.URL)
+				apiErr.JSONResponse(w)
+			case *credentials.IncompleteCredentialError:
+				BadRequest(w, req, log, e)
+			default:
+				InternalError(w, req, log, err)
+			}
+			return
+		}
+		account := accounts.AccountFromCredential(*cred)
+		JsonResult(w, http.StatusCreated, toGetAccountResponse(&account))
+	}
+}
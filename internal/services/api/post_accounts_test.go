@@ -0,0 +1,205 @@
+package api
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/posit-dev/publisher/internal/accounts"
+	"github.com/posit-dev/publisher/internal/clients/connect"
+	"github.com/posit-dev/publisher/internal/credentials"
+	"github.com/posit-dev/publisher/internal/events"
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/types"
+	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/zalando/go-keyring"
+)
+
+type PostAccountsSuite struct {
+	utiltest.Suite
+	log    logging.Logger
+	client *connect.MockClient
+}
+
+func TestPostAccountsSuite(t *testing.T) {
+	suite.Run(t, new(PostAccountsSuite))
+}
+
+func (s *PostAccountsSuite) SetupSuite() {
+	s.log = logging.New()
+}
+
+func (s *PostAccountsSuite) SetupTest() {
+	keyring.MockInit()
+	s.client = connect.NewMockClient()
+	clientFactory = func(account *accounts.Account, timeout time.Duration, emitter events.Emitter, log logging.Logger) (connect.APIClient, error) {
+		return s.client, nil
+	}
+}
+
+func (s *PostAccountsSuite) TearDownTest() {
+	clientFactory = connect.NewConnectClient
+}
+
+func (s *PostAccountsSuite) authenticates() {
+	s.client.On("TestAuthentication", mock.Anything).Return(&connect.User{Username: "bob"}, nil)
+}
+
+// listCredentials returns the names of the saved credentials.
+func (s *PostAccountsSuite) listCredentials() []string {
+	cs, err := credentials.NewCredentialsService(s.log)
+	s.NoError(err)
+	creds, err := cs.List()
+	s.NoError(err)
+	names := []string{}
+	for _, cred := range creds {
+		names = append(names, cred.Name)
+	}
+	return names
+}
+
+func (s *PostAccountsSuite) post(body postAccountsRequest) *httptest.ResponseRecorder {
+	data, err := json.Marshal(body)
+	s.NoError(err)
+	req, err := http.NewRequest("POST", "/api/accounts", bytes.NewBuffer(data))
+	s.NoError(err)
+
+	rec := httptest.NewRecorder()
+	h := PostAccountsHandlerFunc(s.log)
+	h(rec, req)
+	return rec
+}
+
+func (s *PostAccountsSuite) TestPostAccounts() {
+	s.authenticates()
+	rec := s.post(postAccountsRequest{
+		Name:   "example",
+		URL:    "https://connect.example.com",
+		ApiKey: "12345",
+	})
+	s.Equal(http.StatusCreated, rec.Result().StatusCode)
+
+	var res getAccountResponse
+	dec := json.NewDecoder(rec.Body)
+	dec.DisallowUnknownFields()
+	s.NoError(dec.Decode(&res))
+	s.Equal("example", res.Name)
+	s.Equal("https://connect.example.com", res.URL)
+	s.Equal("api-key", res.AuthType)
+	s.NotEqual("", res.GUID)
+
+	// The account is in the credentials store.
+	cs, err := credentials.NewCredentialsService(s.log)
+	s.NoError(err)
+	cred, err := cs.Get(res.GUID)
+	s.NoError(err)
+	s.Equal("12345", cred.ApiKey)
+	s.client.AssertCalled(s.T(), "TestAuthentication", mock.Anything)
+}
+
+func (s *PostAccountsSuite) TestPostAccountsRejected() {
+	authErr := types.NewAgentError(events.AuthenticationFailedCode, errors.New("the API key is not valid"), nil)
+	s.client.On("TestAuthentication", mock.Anything).Return(nil, authErr)
+
+	rec := s.post(postAccountsRequest{
+		Name:   "example",
+		URL:    "https://connect.example.com",
+		ApiKey: "bad",
+	})
+	s.Equal(http.StatusUnprocessableEntity, rec.Result().StatusCode)
+
+	var res types.AgentError
+	s.NoError(json.NewDecoder(rec.Body).Decode(&res))
+	s.Equal(events.AuthenticationFailedCode, res.Code)
+	s.Equal("The API key is not valid.", res.Message)
+
+	// Nothing was saved.
+	s.Len(s.listCredentials(), 0)
+}
+
+func (s *PostAccountsSuite) TestPostAccountsForced() {
+	rec := s.post(postAccountsRequest{
+		Name:   "example",
+		URL:    "https://connect.example.com",
+		ApiKey: "unverified",
+		Force:  true,
+	})
+	s.Equal(http.StatusCreated, rec.Result().StatusCode)
+	s.Equal([]string{"example"}, s.listCredentials())
+	s.client.AssertNotCalled(s.T(), "TestAuthentication", mock.Anything)
+}
+
+func (s *PostAccountsSuite) decodeCollision(rec *httptest.ResponseRecorder) types.APIErrorCredentialCollision {
+	s.Equal(http.StatusConflict, rec.Result().StatusCode)
+	s.Equal("application/json", rec.Header().Get("content-type"))
+
+	var res types.APIErrorCredentialCollision
+	dec := json.NewDecoder(rec.Body)
+	dec.DisallowUnknownFields()
+	s.NoError(dec.Decode(&res))
+	return res
+}
+
+func (s *PostAccountsSuite) TestPostAccountsURLCollision() {
+	s.authenticates()
+	cs, err := credentials.NewCredentialsService(s.log)
+	s.NoError(err)
+	_, err = cs.Set(credentials.CreateCredentialDetails{Name: "example", URL: "https://connect.example.com", ApiKey: "12345"})
+	s.NoError(err)
+
+	rec := s.post(postAccountsRequest{
+		Name:   "other",
+		URL:    "https://connect.example.com",
+		ApiKey: "67890",
+	})
+	res := s.decodeCollision(rec)
+	// The details describe the existing account.
+	s.Equal(types.ErrorCredentialURLCollision, res.Code)
+	s.Equal("example", res.Details.Name)
+	s.Equal("https://connect.example.com", res.Details.URL)
+}
+
+func (s *PostAccountsSuite) TestPostAccountsNameCollision() {
+	s.authenticates()
+	cs, err := credentials.NewCredentialsService(s.log)
+	s.NoError(err)
+	_, err = cs.Set(credentials.CreateCredentialDetails{Name: "example", URL: "https://connect.example.com", ApiKey: "12345"})
+	s.NoError(err)
+
+	rec := s.post(postAccountsRequest{
+		Name:   "example",
+		URL:    "https://other.example.com",
+		ApiKey: "67890",
+	})
+	res := s.decodeCollision(rec)
+	s.Equal(types.ErrorCredentialNameCollision, res.Code)
+	s.Equal("example", res.Details.Name)
+	s.Equal("https://connect.example.com", res.Details.URL)
+}
+
+func (s *PostAccountsSuite) TestPostAccountsIncomplete() {
+	rec := s.post(postAccountsRequest{
+		Name: "example",
+		URL:  "https://connect.example.com",
+	})
+	s.Equal(http.StatusBadRequest, rec.Result().StatusCode)
+}
+
+func (s *PostAccountsSuite) TestPostAccountsBadJSON() {
+	req, err := http.NewRequest("POST", "/api/accounts", strings.NewReader(`{"bogus": 1}`))
+	s.NoError(err)
+
+	rec := httptest.NewRecorder()
+	h := PostAccountsHandlerFunc(s.log)
+	h(rec, req)
+	s.Equal(http.StatusBadRequest, rec.Result().StatusCode)
+}
@@ -0,0 +1,96 @@
+package api
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/posit-dev/publisher/internal/config"
+	"github.com/posit-dev/publisher/internal/initialize"
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/types"
+	"github.com/posit-dev/publisher/internal/util"
+)
+
+type postConfigurationEnsureRequestBody struct {
+	Name   string `json:"configurationName"`
+	Python string `json:"python"`
+}
+
+// PostConfigurationEnsureHandlerFunc returns the named configuration,
+// or the only configuration if no name is given. If it doesn't exist,
+// it is created from the detected content, as the CLI does when
+// deploying without a configuration.
+func PostConfigurationEnsureHandlerFunc(base util.AbsolutePath, log logging.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		projectDir, relProjectDir, err := ProjectDirFromRequest(base, w, req, log)
+		if err != nil {
+			// Response already returned by ProjectDirFromRequest
+			return
+		}
+		dec := json.NewDecoder(req.Body)
+		dec.DisallowUnknownFields()
+		var b postConfigurationEnsureRequestBody
+		err = dec.Decode(&b)
+		if err != nil && !errors.Is(err, io.EOF) {
+			// An empty body uses the defaults.
+			BadRequest(w, req, log, err)
+			return
+		}
+		if b.Name != "" {
+			err = util.ValidateFilename(b.Name)
+			if err != nil {
+				BadRequest(w, req, log, err)
+				return
+			}
+		}
+		python := util.NewPath(b.Python, nil)
+		name, created, err := initialize.EnsureConfig(projectDir, b.Name, python, util.Path{}, log)
+		if err != nil {
+			if errors.Is(err, config.ErrMultipleConfigs) {
+				BadRequest(w, req, log, err)
+				return
+			}
+			if aerr, ok := types.IsAgentErrorOf(err, types.ErrorNoDeployableContent); ok {
+				apiErr := types.APIErrorNoDeployableContentFromAgentError(*aerr)
+				log.Error("No deployable content was detected", "path", projectDir.String())
+				apiErr.JSONResponse(w)
+				return
+			}
+			InspectionError(w, req, log, err)
+			return
+		}
+
+		path := config.GetConfigPath(projectDir, name)
+		relPath, err := path.Rel(projectDir)
+		if err != nil {
+			InternalError(w, req, log, err)
+			return
+		}
+		response := &configDTO{
+			configLocation: configLocation{
+				Name:    name,
+				Path:    path.String(),
+				RelPath: relPath.String(),
+			},
+			ProjectDir: relProjectDir.String(),
+		}
+		cfg, err := config.FromFile(path)
+		if err != nil {
+			// An existing configuration may be invalid;
+			// return it with the error so it can be fixed.
+			response.Error = types.AsAgentError(err)
+		} else {
+			response.Configuration = cfg
+			response.Warnings = cfg.Lint(projectDir)
+		}
+		status := http.StatusOK
+		if created {
+			status = http.StatusCreated
+		}
+		JsonResult(w, status, response)
+	}
+}
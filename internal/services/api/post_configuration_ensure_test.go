@@ -0,0 +1,159 @@
+package api
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/posit-dev/publisher/internal/config"
+	"github.com/posit-dev/publisher/internal/initialize"
+	"github.com/posit-dev/publisher/internal/inspect"
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/types"
+	"github.com/posit-dev/publisher/internal/util"
+	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/suite"
+)
+
+type PostConfigurationEnsureSuite struct {
+	utiltest.Suite
+	log logging.Logger
+	cwd util.AbsolutePath
+}
+
+func TestPostConfigurationEnsureSuite(t *testing.T) {
+	suite.Run(t, new(PostConfigurationEnsureSuite))
+}
+
+func (s *PostConfigurationEnsureSuite) SetupSuite() {
+	s.log = logging.New()
+}
+
+func (s *PostConfigurationEnsureSuite) SetupTest() {
+	fs := afero.NewMemMapFs()
+	cwd, err := util.Getwd(fs)
+	s.Nil(err)
+	s.cwd = cwd
+	s.cwd.MkdirAll(0700)
+
+	initialize.PythonInspectorFactory = func(util.AbsolutePath, util.Path, logging.Logger) inspect.PythonInspector {
+		i := inspect.NewMockPythonInspector()
+		i.On("InspectPython").Return(&config.Python{
+			Version:        "3.11.3",
+			PackageFile:    "requirements.txt",
+			PackageManager: "pip",
+		}, nil)
+		return i
+	}
+}
+
+func (s *PostConfigurationEnsureSuite) TearDownTest() {
+	initialize.PythonInspectorFactory = inspect.NewPythonInspector
+}
+
+func (s *PostConfigurationEnsureSuite) ensure(body string) *httptest.ResponseRecorder {
+	h := PostConfigurationEnsureHandlerFunc(s.cwd, s.log)
+
+	rec := httptest.NewRecorder()
+	var reqBody io.Reader = http.NoBody
+	if body != "" {
+		reqBody = strings.NewReader(body)
+	}
+	req, err := http.NewRequest("POST", "/api/configurations/ensure", reqBody)
+	s.NoError(err)
+	h(rec, req)
+	return rec
+}
+
+func (s *PostConfigurationEnsureSuite) decode(rec *httptest.ResponseRecorder) configDTO {
+	var res configDTO
+	dec := json.NewDecoder(rec.Body)
+	dec.DisallowUnknownFields()
+	s.NoError(dec.Decode(&res))
+	return res
+}
+
+func (s *PostConfigurationEnsureSuite) TestEnsureExisting() {
+	cfg := config.New()
+	cfg.Type = config.ContentTypeHTML
+	cfg.Entrypoint = "index.html"
+	err := cfg.WriteFile(config.GetConfigPath(s.cwd, "myConfig"))
+	s.NoError(err)
+
+	rec := s.ensure(`{"configurationName": "myConfig"}`)
+	s.Equal(http.StatusOK, rec.Result().StatusCode)
+
+	res := s.decode(rec)
+	s.Equal("myConfig", res.Name)
+	s.Nil(res.Error)
+	s.NotNil(res.Configuration)
+	s.Equal(config.ContentTypeHTML, res.Configuration.Type)
+	s.Equal("index.html", res.Configuration.Entrypoint)
+
+	// The only configuration is used if no name is given.
+	rec = s.ensure("")
+	s.Equal(http.StatusOK, rec.Result().StatusCode)
+	res = s.decode(rec)
+	s.Equal("myConfig", res.Name)
+}
+
+func (s *PostConfigurationEnsureSuite) TestEnsureCreates() {
+	appCode := "from flask import Flask\napp = Flask(__name__)\n"
+	err := s.cwd.Join("app.py").WriteFile([]byte(appCode), 0666)
+	s.NoError(err)
+
+	rec := s.ensure("")
+	s.Equal(http.StatusCreated, rec.Result().StatusCode)
+
+	res := s.decode(rec)
+	s.Equal(config.DefaultConfigName, res.Name)
+	s.Nil(res.Error)
+	s.NotNil(res.Configuration)
+	s.Equal(config.ContentTypePythonFlask, res.Configuration.Type)
+	s.Equal("app.py", res.Configuration.Entrypoint)
+
+	exists, err := config.GetConfigPath(s.cwd, config.DefaultConfigName).Exists()
+	s.NoError(err)
+	s.True(exists)
+
+	// Now that it exists, it is returned as-is.
+	rec = s.ensure("")
+	s.Equal(http.StatusOK, rec.Result().StatusCode)
+}
+
+func (s *PostConfigurationEnsureSuite) TestEnsureNoDeployableContent() {
+	// The project directory is empty.
+	rec := s.ensure(`{"configurationName": "myConfig"}`)
+	s.Equal(http.StatusUnprocessableEntity, rec.Result().StatusCode)
+
+	var res types.APIErrorNoDeployableContent
+	s.NoError(json.NewDecoder(rec.Body).Decode(&res))
+	s.Equal(types.ErrorNoDeployableContent, res.Code)
+
+	exists, err := config.GetConfigPath(s.cwd, "myConfig").Exists()
+	s.NoError(err)
+	s.False(exists)
+}
+
+func (s *PostConfigurationEnsureSuite) TestEnsureMultipleConfigs() {
+	for _, name := range []string{"a", "b"} {
+		cfg := config.New()
+		cfg.Type = config.ContentTypeHTML
+		cfg.Entrypoint = "index.html"
+		err := cfg.WriteFile(config.GetConfigPath(s.cwd, name))
+		s.NoError(err)
+	}
+	rec := s.ensure("")
+	s.Equal(http.StatusBadRequest, rec.Result().StatusCode)
+}
+
+func (s *PostConfigurationEnsureSuite) TestEnsureBadName() {
+	rec := s.ensure(`{"configurationName": "../escape"}`)
+	s.Equal(http.StatusBadRequest, rec.Result().StatusCode)
+}
@@ -15,6 +15,8 @@ type PostCredentialsRequest struct {
 	Name   string `json:"name"`
 	URL    string `json:"url"`
 	ApiKey string `json:"apiKey"`
+	Token  string `json:"token"`
+	Secret string `json:"secret"`
 }
 
 type PostCredentialsResponse = credentials.Credential
@@ -44,7 +46,13 @@ func PostCredentialFuncHandler(log logging.Logger) http.HandlerFunc {
 			return
 		}
 
-		cred, err := cs.Set(body.Name, body.URL, body.ApiKey)
+		cred, err := cs.Set(credentials.CreateCredentialDetails{
+			Name:   body.Name,
+			URL:    body.URL,
+			ApiKey: body.ApiKey,
+			Token:  body.Token,
+			Secret: body.Secret,
+		})
 		if err != nil {
 			if _, ok := err.(*credentials.URLCollisionError); ok {
 				http.Error(w, http.StatusText(http.StatusConflict), http.StatusConflict)
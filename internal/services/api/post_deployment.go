@@ -5,6 +5,7 @@ package api
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/gorilla/mux"
@@ -13,6 +14,7 @@ import (
 	"github.com/posit-dev/publisher/internal/events"
 	"github.com/posit-dev/publisher/internal/logging"
 	"github.com/posit-dev/publisher/internal/publish"
+	"github.com/posit-dev/publisher/internal/services/middleware"
 	"github.com/posit-dev/publisher/internal/state"
 	"github.com/posit-dev/publisher/internal/types"
 	"github.com/posit-dev/publisher/internal/util"
@@ -23,6 +25,10 @@ type PostDeploymentRequestBody struct {
 	ConfigName  string            `json:"config"`
 	Secrets     map[string]string `json:"secrets,omitempty"`
 	Insecure    bool              `json:"insecure"`
+	Bundle      string            `json:"bundle,omitempty"`   // Existing bundle to deploy, relative to the project directory
+	Manifest    string            `json:"manifest,omitempty"` // Existing manifest listing the files to deploy, relative to the project directory
+	Strict      bool              `json:"strict,omitempty"`   // Fail if there are any warnings
+	PruneEnv    bool              `json:"pruneEnv,omitempty"` // Remove environment variables that are not in the configuration
 }
 
 type PostDeploymentsReponse struct {
@@ -36,6 +42,8 @@ func PostDeploymentHandlerFunc(
 	base util.AbsolutePath,
 	log logging.Logger,
 	accountList accounts.AccountList,
+	deployLimiter *middleware.ConcurrencyLimiter,
+	running *RunningDeployments,
 	emitter events.Emitter) http.HandlerFunc {
 
 	return func(w http.ResponseWriter, req *http.Request) {
@@ -53,6 +61,36 @@ func PostDeploymentHandlerFunc(
 			BadRequest(w, req, log, err)
 			return
 		}
+		if b.Bundle != "" && b.Manifest != "" {
+			BadRequest(w, req, log, errors.New("bundle and manifest cannot both be specified"))
+			return
+		}
+		var sourceRelPath util.Path
+		var sourceName string
+		if b.Bundle != "" {
+			sourceRelPath = util.NewPath(b.Bundle, nil)
+			sourceName = "bundle"
+		} else if b.Manifest != "" {
+			sourceRelPath = util.NewPath(b.Manifest, nil)
+			sourceName = "manifest"
+		}
+		var sourcePath util.AbsolutePath
+		if sourceName != "" {
+			if !sourceRelPath.IsLocal() {
+				BadRequest(w, req, log, fmt.Errorf("%s '%s' must be inside the project directory", sourceName, sourceRelPath))
+				return
+			}
+			sourcePath = projectDir.Join(sourceRelPath.String())
+			exists, err := sourcePath.Exists()
+			if err != nil {
+				InternalError(w, req, log, err)
+				return
+			}
+			if !exists {
+				NotFound(w, log, fmt.Errorf("%s '%s' not found", sourceName, sourcePath))
+				return
+			}
+		}
 		localID, err := state.NewLocalID()
 		if err != nil {
 			InternalError(w, req, log, err)
@@ -105,6 +143,12 @@ func PostDeploymentHandlerFunc(
 		}
 		log.Debug("New account derived state created", "account", b.AccountName, "config", b.ConfigName)
 
+		// The slot is held until publishing finishes, not just for this request.
+		if !deployLimiter.Acquire(req.Context()) {
+			deployLimiter.TooManyRequests(w)
+			return
+		}
+
 		response := PostDeploymentsReponse{
 			LocalID: localID,
 		}
@@ -114,15 +158,32 @@ func PostDeploymentHandlerFunc(
 
 		log := log.WithArgs("local_id", localID)
 		newState.LocalID = localID
-		publisher, err := publisherFactory(newState, emitter, log)
+		newState.SymlinkPolicy = apiSymlinkPolicy
+		newState.Strict = b.Strict
+		newState.PruneEnvVars = b.PruneEnv
+		// The publish outlives this request, so it can't use the
+		// request's context. It is cancelled by the cancel endpoint.
+		ctx := running.start(name, localID)
+		publisher, err := publisherFactory(ctx, newState, emitter, log)
 		log.Debug("New publisher derived from state", "account", b.AccountName, "config", b.ConfigName)
 		if err != nil {
+			running.finish(localID)
+			deployLimiter.Release()
 			InternalError(w, req, log, err)
 			return
 		}
 
 		go func() {
-			err = publisher.PublishDirectory()
+			defer deployLimiter.Release()
+			defer running.finish(localID)
+			switch {
+			case b.Bundle != "":
+				err = publisher.PublishBundle(sourcePath)
+			case b.Manifest != "":
+				err = publisher.PublishManifest(sourcePath)
+			default:
+				err = publisher.PublishDirectory()
+			}
 			if err != nil {
 				log.Error("Deployment failed", "error", err.Error())
 				return
@@ -0,0 +1,88 @@
+package api
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/state"
+)
+
+// RunningDeployments tracks the deployments in progress,
+// so they can be cancelled.
+type RunningDeployments struct {
+	mu      sync.Mutex
+	running map[state.LocalDeploymentID]runningDeployment
+}
+
+type runningDeployment struct {
+	name   string
+	cancel context.CancelFunc
+}
+
+func NewRunningDeployments() *RunningDeployments {
+	return &RunningDeployments{
+		running: make(map[state.LocalDeploymentID]runningDeployment),
+	}
+}
+
+// start returns the context for a new deployment, which is
+// cancelled by cancel or finish. A nil RunningDeployments
+// returns a context that isn't cancelled.
+func (d *RunningDeployments) start(name string, localID state.LocalDeploymentID) context.Context {
+	if d == nil {
+		return context.Background()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.running[localID] = runningDeployment{
+		name:   name,
+		cancel: cancel,
+	}
+	return ctx
+}
+
+// finish forgets a deployment that is no longer running.
+func (d *RunningDeployments) finish(localID state.LocalDeploymentID) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if r, ok := d.running[localID]; ok {
+		r.cancel()
+		delete(d.running, localID)
+	}
+}
+
+// cancel stops the named deployment. It returns false
+// if the deployment isn't running.
+func (d *RunningDeployments) cancel(name string, localID state.LocalDeploymentID) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	r, ok := d.running[localID]
+	if !ok || r.name != name {
+		return false
+	}
+	r.cancel()
+	return true
+}
+
+func PostDeploymentCancelHandlerFunc(running *RunningDeployments, log logging.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		name := mux.Vars(req)["name"]
+		localID := state.LocalDeploymentID(mux.Vars(req)["localid"])
+		if !running.cancel(name, localID) {
+			NotFound(w, log, fmt.Errorf("deployment '%s' with local ID '%s' is not in progress", name, localID))
+			return
+		}
+		log.Info("Deployment cancelled", "deployment", name, "local_id", localID)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
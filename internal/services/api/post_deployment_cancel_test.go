@@ -0,0 +1,114 @@
+package api
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/posit-dev/publisher/internal/accounts"
+	"github.com/posit-dev/publisher/internal/deployment"
+	"github.com/posit-dev/publisher/internal/events"
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/publish"
+	"github.com/posit-dev/publisher/internal/state"
+	"github.com/posit-dev/publisher/internal/util"
+	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type PostDeploymentCancelSuite struct {
+	utiltest.Suite
+	cwd util.AbsolutePath
+	log logging.Logger
+}
+
+func TestPostDeploymentCancelSuite(t *testing.T) {
+	suite.Run(t, new(PostDeploymentCancelSuite))
+}
+
+func (s *PostDeploymentCancelSuite) SetupTest() {
+	s.log = logging.New()
+	cwd, err := util.Getwd(afero.NewMemMapFs())
+	s.NoError(err)
+	s.cwd = cwd
+	s.NoError(s.cwd.MkdirAll(0700))
+
+	stateFactory = func(
+		path util.AbsolutePath,
+		accountName, configName, targetName, saveName string,
+		accountList accounts.AccountList,
+		secrets map[string]string,
+		insecure bool) (*state.State, error) {
+
+		st := state.Empty()
+		st.Account = &accounts.Account{}
+		st.Target = deployment.New()
+		return st, nil
+	}
+}
+
+func (s *PostDeploymentCancelSuite) TearDownTest() {
+	stateFactory = state.New
+	publisherFactory = publish.NewFromState
+}
+
+func (s *PostDeploymentCancelSuite) cancel(running *RunningDeployments, name string, localID state.LocalDeploymentID) int {
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest("POST", "/api/deployments/"+name+"/cancel/"+string(localID), nil)
+	s.NoError(err)
+	req = mux.SetURLVars(req, map[string]string{"name": name, "localid": string(localID)})
+	PostDeploymentCancelHandlerFunc(running, s.log)(rec, req)
+	return rec.Result().StatusCode
+}
+
+func (s *PostDeploymentCancelSuite) TestCancel() {
+	running := NewRunningDeployments()
+
+	var publishCtx context.Context
+	cancelled := make(chan struct{})
+	publisher := &mockPublisher{}
+	publisher.On("PublishDirectory").Return(context.Canceled).Run(func(mock.Arguments) {
+		<-publishCtx.Done()
+		close(cancelled)
+	})
+	publisherFactory = func(ctx context.Context, _ *state.State, _ events.Emitter, _ logging.Logger) (publish.Publisher, error) {
+		publishCtx = ctx
+		return publisher, nil
+	}
+
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest("POST", "/api/deployments/myTargetName", strings.NewReader(
+		`{"account": "local", "config": "default"}`))
+	s.NoError(err)
+	req = mux.SetURLVars(req, map[string]string{"name": "myTargetName"})
+	PostDeploymentHandlerFunc(s.cwd, s.log, nil, nil, running, events.NewNullEmitter())(rec, req)
+	s.Equal(http.StatusAccepted, rec.Result().StatusCode)
+
+	var res PostDeploymentsReponse
+	s.NoError(json.NewDecoder(rec.Body).Decode(&res))
+
+	// Only the deployment's own name and local ID cancel it.
+	s.Equal(http.StatusNotFound, s.cancel(running, "otherTarget", res.LocalID))
+	s.Equal(http.StatusNotFound, s.cancel(running, "myTargetName", "otherLocalID"))
+
+	s.Equal(http.StatusNoContent, s.cancel(running, "myTargetName", res.LocalID))
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		s.Fail("publishing was not cancelled")
+	}
+
+	// Once publishing stops, the deployment isn't running.
+	s.Eventually(func() bool {
+		return s.cancel(running, "myTargetName", res.LocalID) == http.StatusNotFound
+	}, time.Second, 10*time.Millisecond)
+}
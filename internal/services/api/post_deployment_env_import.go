@@ -0,0 +1,129 @@
+package api
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/posit-dev/publisher/internal/accounts"
+	"github.com/posit-dev/publisher/internal/clients/http_client"
+	"github.com/posit-dev/publisher/internal/config"
+	"github.com/posit-dev/publisher/internal/deployment"
+	"github.com/posit-dev/publisher/internal/events"
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/util"
+)
+
+// importEnvNames adds the names of environment variables set on
+// the server to the configuration's secrets. The server does not
+// reveal their values, and secrets are only sent when a value is
+// supplied, so the values on the server are left unchanged.
+// Names already in the configuration's environment are skipped.
+func importEnvNames(cfg *config.Config, names []string) {
+	for _, name := range names {
+		if _, ok := cfg.Environment[name]; ok {
+			continue
+		}
+		// AddSecret only fails for names in the environment.
+		_ = cfg.AddSecret(name)
+	}
+}
+
+// PostDeploymentEnvironmentImportHandlerFunc adds the names of the
+// environment variables of the deployed content to the deployment's
+// configuration, which is useful when adopting content that was
+// configured on the server.
+func PostDeploymentEnvironmentImportHandlerFunc(base util.AbsolutePath, log logging.Logger, accountList accounts.AccountList) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		name := mux.Vars(req)["name"]
+		projectDir, relProjectDir, err := ProjectDirFromRequest(base, w, req, log)
+		if err != nil {
+			// Response already returned by ProjectDirFromRequest
+			return
+		}
+
+		path := deployment.GetDeploymentPath(projectDir, name)
+		d, err := deployment.FromFile(path)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				http.NotFound(w, req)
+				return
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(fmt.Sprintf("deployment %s is invalid: %s", name, err)))
+			return
+		}
+		if !d.IsDeployed() {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(fmt.Sprintf("deployment %s is not deployed", name)))
+			return
+		}
+		if d.ConfigName == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(fmt.Sprintf("deployment %s does not have a configuration", name)))
+			return
+		}
+		configPath := config.GetConfigPath(projectDir, d.ConfigName)
+		cfg, err := configFromFile(configPath)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				http.NotFound(w, req)
+				return
+			}
+			BadRequest(w, req, log, err)
+			return
+		}
+
+		account, err := accountList.GetAccountByServerURL(d.ServerURL)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(fmt.Sprintf("no credential found to use with deployment %s", name)))
+			return
+		}
+		client, err := clientFactory(account, 30*time.Second, events.NewNullEmitter(), log)
+		if err != nil {
+			InternalError(w, req, log, err)
+			return
+		}
+		env, err := client.GetEnvVars(d.ID, log)
+		if err != nil {
+			httpErr, ok := err.(*http_client.HTTPError)
+			if ok {
+				// Pass through HTTP Error from Connect
+				w.WriteHeader(httpErr.Status)
+				w.Write([]byte(httpErr.Error()))
+				return
+			}
+			InternalError(w, req, log, err)
+			return
+		}
+
+		importEnvNames(cfg, *env)
+		err = cfg.WriteFile(configPath)
+		if err != nil {
+			InternalError(w, req, log, err)
+			return
+		}
+
+		relPath, err := configPath.Rel(base)
+		if err != nil {
+			InternalError(w, req, log, err)
+			return
+		}
+		response := &configDTO{
+			configLocation: configLocation{
+				Name:    d.ConfigName,
+				Path:    configPath.String(),
+				RelPath: relPath.String(),
+			},
+			ProjectDir:    relProjectDir.String(),
+			Configuration: cfg,
+		}
+		JsonResult(w, http.StatusOK, response)
+	}
+}
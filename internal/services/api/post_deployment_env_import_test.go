@@ -0,0 +1,151 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/posit-dev/publisher/internal/accounts"
+	"github.com/posit-dev/publisher/internal/clients/connect"
+	"github.com/posit-dev/publisher/internal/clients/http_client"
+	"github.com/posit-dev/publisher/internal/config"
+	"github.com/posit-dev/publisher/internal/deployment"
+	"github.com/posit-dev/publisher/internal/events"
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/types"
+	"github.com/posit-dev/publisher/internal/util"
+	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/suite"
+)
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+type PostDeploymentEnvImportSuite struct {
+	utiltest.Suite
+	log    logging.Logger
+	cwd    util.AbsolutePath
+	lister *accounts.MockAccountList
+}
+
+func TestPostDeploymentEnvImportSuite(t *testing.T) {
+	suite.Run(t, new(PostDeploymentEnvImportSuite))
+}
+
+func (s *PostDeploymentEnvImportSuite) SetupSuite() {
+	s.log = logging.New()
+}
+
+func (s *PostDeploymentEnvImportSuite) SetupTest() {
+	fs := afero.NewMemMapFs()
+	cwd, err := util.Getwd(fs)
+	s.Nil(err)
+	s.cwd = cwd
+	s.cwd.MkdirAll(0700)
+
+	clientFactory = connect.NewConnectClient
+
+	d := deployment.New()
+	d.ID = "123"
+	d.ServerURL = "https://connect.example.com"
+	d.ConfigName = "myConfig"
+	err = d.WriteFile(deployment.GetDeploymentPath(s.cwd, "dep"))
+	s.NoError(err)
+
+	s.lister = &accounts.MockAccountList{}
+	acct := &accounts.Account{
+		Name:       "myAccount",
+		URL:        "https://connect.example.com",
+		ServerType: accounts.ServerTypeConnect,
+	}
+	s.lister.On("GetAccountByServerURL", "https://connect.example.com").Return(acct, nil)
+}
+
+func (s *PostDeploymentEnvImportSuite) useEnv(env types.Environment, err error) {
+	client := connect.NewMockClient()
+	if err != nil {
+		client.On("GetEnvVars", types.ContentID("123"), s.log).Return(nil, err)
+	} else {
+		client.On("GetEnvVars", types.ContentID("123"), s.log).Return(&env, nil)
+	}
+	clientFactory = func(account *accounts.Account, timeout time.Duration, emitter events.Emitter, log logging.Logger) (connect.APIClient, error) {
+		return client, nil
+	}
+}
+
+func (s *PostDeploymentEnvImportSuite) post() *httptest.ResponseRecorder {
+	h := PostDeploymentEnvironmentImportHandlerFunc(s.cwd, s.log, s.lister)
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest("POST", "/api/deployments/dep/environment/import", nil)
+	s.NoError(err)
+	req = mux.SetURLVars(req, map[string]string{"name": "dep"})
+	h(rec, req)
+	return rec
+}
+
+func (s *PostDeploymentEnvImportSuite) TestImportEnvNames() {
+	cfg := config.New()
+	cfg.Environment = config.Environment{"FOO": "1"}
+	cfg.Secrets = []string{"API_KEY"}
+
+	importEnvNames(cfg, []string{"FOO", "API_KEY", "DB_PASSWORD"})
+	s.Equal(config.Environment{"FOO": "1"}, cfg.Environment)
+	s.Equal([]string{"API_KEY", "DB_PASSWORD"}, cfg.Secrets)
+}
+
+func (s *PostDeploymentEnvImportSuite) TestPostDeploymentEnvImport() {
+	cfg := config.New()
+	cfg.Type = config.ContentTypeHTML
+	cfg.Environment = config.Environment{"FOO": "1"}
+	configPath := config.GetConfigPath(s.cwd, "myConfig")
+	err := cfg.WriteFile(configPath)
+	s.NoError(err)
+
+	s.useEnv(types.Environment{"FOO", "BAR", "BAZ"}, nil)
+	rec := s.post()
+
+	s.Equal(http.StatusOK, rec.Result().StatusCode)
+	updated, err := config.FromFile(configPath)
+	s.NoError(err)
+	s.Equal([]string{"BAR", "BAZ"}, updated.Secrets)
+	s.Equal(config.Environment{"FOO": "1"}, updated.Environment)
+}
+
+func (s *PostDeploymentEnvImportSuite) TestPostDeploymentEnvImportNoConfig() {
+	s.useEnv(types.Environment{"FOO"}, nil)
+	rec := s.post()
+	s.Equal(http.StatusNotFound, rec.Result().StatusCode)
+}
+
+func (s *PostDeploymentEnvImportSuite) TestPostDeploymentEnvImportNotDeployed() {
+	d := deployment.New()
+	d.ConfigName = "myConfig"
+	err := d.WriteFile(deployment.GetDeploymentPath(s.cwd, "dep"))
+	s.NoError(err)
+
+	rec := s.post()
+	s.Equal(http.StatusBadRequest, rec.Result().StatusCode)
+	body, _ := io.ReadAll(rec.Body)
+	s.Contains(string(body), "deployment dep is not deployed")
+}
+
+func (s *PostDeploymentEnvImportSuite) TestPostDeploymentEnvImportPassesStatusFromServer() {
+	cfg := config.New()
+	cfg.Type = config.ContentTypeHTML
+	configPath := config.GetConfigPath(s.cwd, "myConfig")
+	err := cfg.WriteFile(configPath)
+	s.NoError(err)
+
+	httpErr := http_client.NewHTTPError("https://connect.example.com", "GET", http.StatusNotFound)
+	s.useEnv(nil, httpErr)
+	rec := s.post()
+	s.Equal(http.StatusNotFound, rec.Result().StatusCode)
+
+	// The configuration is unchanged.
+	updated, err := config.FromFile(configPath)
+	s.NoError(err)
+	s.Len(updated.Secrets, 0)
+}
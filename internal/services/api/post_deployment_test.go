@@ -3,6 +3,7 @@ package api
 // Copyright (C) 2023 by Posit Software, PBC.
 
 import (
+	"context"
 	"errors"
 	"io"
 	"net/http"
@@ -10,6 +11,7 @@ import (
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/posit-dev/publisher/internal/accounts"
@@ -18,6 +20,7 @@ import (
 	"github.com/posit-dev/publisher/internal/events"
 	"github.com/posit-dev/publisher/internal/logging"
 	"github.com/posit-dev/publisher/internal/publish"
+	"github.com/posit-dev/publisher/internal/services/middleware"
 	"github.com/posit-dev/publisher/internal/state"
 	"github.com/posit-dev/publisher/internal/util"
 	"github.com/posit-dev/publisher/internal/util/utiltest"
@@ -55,6 +58,16 @@ func (m *mockPublisher) PublishDirectory() error {
 	return args.Error(0)
 }
 
+func (m *mockPublisher) PublishBundle(path util.AbsolutePath) error {
+	args := m.Called(path)
+	return args.Error(0)
+}
+
+func (m *mockPublisher) PublishManifest(path util.AbsolutePath) error {
+	args := m.Called(path)
+	return args.Error(0)
+}
+
 func (s *PostDeploymentHandlerFuncSuite) TestPostDeploymentHandlerFunc() {
 	log := logging.New()
 
@@ -73,7 +86,8 @@ func (s *PostDeploymentHandlerFuncSuite) TestPostDeploymentHandlerFunc() {
 
 	publisher := &mockPublisher{}
 	publisher.On("PublishDirectory", mock.Anything).Return(nil)
-	publisherFactory = func(*state.State, events.Emitter, logging.Logger) (publish.Publisher, error) {
+	publisherFactory = func(_ context.Context, st *state.State, _ events.Emitter, _ logging.Logger) (publish.Publisher, error) {
+		s.Equal(util.SymlinkContain, st.SymlinkPolicy)
 		return publisher, nil
 	}
 	stateFactory = func(
@@ -95,12 +109,60 @@ func (s *PostDeploymentHandlerFuncSuite) TestPostDeploymentHandlerFunc() {
 		st.Target = deployment.New()
 		return st, nil
 	}
-	handler := PostDeploymentHandlerFunc(s.cwd, log, lister, events.NewNullEmitter())
+	handler := PostDeploymentHandlerFunc(s.cwd, log, lister, nil, nil, events.NewNullEmitter())
 	handler(rec, req)
 
 	s.Equal(http.StatusAccepted, rec.Result().StatusCode)
 }
 
+func (s *PostDeploymentHandlerFuncSuite) TestPostDeploymentHandlerFuncHoldsSlot() {
+	log := logging.New()
+	lister := &accounts.MockAccountList{}
+
+	done := make(chan struct{})
+	publisher := &mockPublisher{}
+	publisher.On("PublishDirectory").Return(nil).Run(func(mock.Arguments) {
+		<-done
+	})
+	publisherFactory = func(context.Context, *state.State, events.Emitter, logging.Logger) (publish.Publisher, error) {
+		return publisher, nil
+	}
+	stateFactory = func(
+		path util.AbsolutePath,
+		accountName, configName, targetName, saveName string,
+		accountList accounts.AccountList,
+		secrets map[string]string,
+		insecure bool) (*state.State, error) {
+
+		st := state.Empty()
+		st.Account = &accounts.Account{}
+		st.Target = deployment.New()
+		return st, nil
+	}
+	deployLimiter := middleware.NewConcurrencyLimiter(1, 10*time.Millisecond)
+	handler := PostDeploymentHandlerFunc(s.cwd, log, lister, deployLimiter, nil, events.NewNullEmitter())
+
+	post := func() int {
+		rec := httptest.NewRecorder()
+		req, err := http.NewRequest("POST", "/api/deployments/myTargetName", strings.NewReader(
+			`{"account": "local", "config": "default"}`))
+		s.NoError(err)
+		req = mux.SetURLVars(req, map[string]string{"name": "myTargetName"})
+		handler(rec, req)
+		return rec.Result().StatusCode
+	}
+
+	// The first deployment holds the only deployment slot while publishing.
+	s.Equal(http.StatusAccepted, post())
+	s.Equal(http.StatusTooManyRequests, post())
+
+	// Once it finishes, the slot is free again.
+	close(done)
+	s.Eventually(func() bool {
+		return post() == http.StatusAccepted
+	}, time.Second, 10*time.Millisecond)
+}
+
 func (s *PostDeploymentHandlerFuncSuite) TestPostDeploymentHandlerFuncBadJSON() {
 	log := logging.New()
 
@@ -110,7 +172,54 @@ func (s *PostDeploymentHandlerFuncSuite) TestPostDeploymentHandlerFuncBadJSON()
 
 	req.Body = io.NopCloser(strings.NewReader(`{"random": "123"}`))
 
-	handler := PostDeploymentHandlerFunc(s.cwd, log, nil, events.NewNullEmitter())
+	handler := PostDeploymentHandlerFunc(s.cwd, log, nil, nil, nil, events.NewNullEmitter())
+	handler(rec, req)
+	s.Equal(http.StatusBadRequest, rec.Result().StatusCode)
+}
+
+func (s *PostDeploymentHandlerFuncSuite) TestPostDeploymentHandlerFuncBundleNotFound() {
+	log := logging.New()
+
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest("POST", "/api/deployments/myTargetName", nil)
+	s.NoError(err)
+
+	req.Body = io.NopCloser(strings.NewReader(`{"bundle": "bundle.tar.gz"}`))
+
+	handler := PostDeploymentHandlerFunc(s.cwd, log, nil, nil, nil, events.NewNullEmitter())
+	handler(rec, req)
+	s.Equal(http.StatusNotFound, rec.Result().StatusCode)
+}
+
+func (s *PostDeploymentHandlerFuncSuite) TestPostDeploymentHandlerFuncBundleOutsideProject() {
+	log := logging.New()
+
+	for _, body := range []string{
+		`{"bundle": "../bundle.tar.gz"}`,
+		`{"bundle": "/tmp/bundle.tar.gz"}`,
+		`{"manifest": "../other/manifest.json"}`,
+	} {
+		rec := httptest.NewRecorder()
+		req, err := http.NewRequest("POST", "/api/deployments/myTargetName", nil)
+		s.NoError(err)
+		req.Body = io.NopCloser(strings.NewReader(body))
+
+		handler := PostDeploymentHandlerFunc(s.cwd, log, nil, nil, nil, events.NewNullEmitter())
+		handler(rec, req)
+		s.Equal(http.StatusBadRequest, rec.Result().StatusCode, body)
+	}
+}
+
+func (s *PostDeploymentHandlerFuncSuite) TestPostDeploymentHandlerFuncBundleAndManifest() {
+	log := logging.New()
+
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest("POST", "/api/deployments/myTargetName", nil)
+	s.NoError(err)
+
+	req.Body = io.NopCloser(strings.NewReader(`{"bundle": "bundle.tar.gz", "manifest": "manifest.json"}`))
+
+	handler := PostDeploymentHandlerFunc(s.cwd, log, nil, nil, nil, events.NewNullEmitter())
 	handler(rec, req)
 	s.Equal(http.StatusBadRequest, rec.Result().StatusCode)
 }
@@ -131,7 +240,7 @@ func (s *PostDeploymentHandlerFuncSuite) TestPostDeploymentHandlerFuncStateErr()
 		return nil, errors.New("test error from state factory")
 	}
 
-	handler := PostDeploymentHandlerFunc(s.cwd, log, nil, events.NewNullEmitter())
+	handler := PostDeploymentHandlerFunc(s.cwd, log, nil, nil, nil, events.NewNullEmitter())
 	handler(rec, req)
 	s.Equal(http.StatusBadRequest, rec.Result().StatusCode)
 	body, _ := io.ReadAll(rec.Body)
@@ -174,7 +283,7 @@ func (s *PostDeploymentHandlerFuncSuite) TestPostDeploymentHandlerFuncWrongServe
 			"insecure": false
 		}`))
 
-	handler := PostDeploymentHandlerFunc(s.cwd, log, lister, events.NewNullEmitter())
+	handler := PostDeploymentHandlerFunc(s.cwd, log, lister, nil, nil, events.NewNullEmitter())
 	handler(rec, req)
 
 	s.Equal(http.StatusConflict, rec.Result().StatusCode)
@@ -205,11 +314,11 @@ func (s *PostDeploymentHandlerFuncSuite) TestPostDeploymentHandlerFuncPublishErr
 	testErr := errors.New("test error from PublishDirectory")
 	publisher := &mockPublisher{}
 	publisher.On("PublishDirectory", mock.Anything).Return(testErr)
-	publisherFactory = func(*state.State, events.Emitter, logging.Logger) (publish.Publisher, error) {
+	publisherFactory = func(context.Context, *state.State, events.Emitter, logging.Logger) (publish.Publisher, error) {
 		return publisher, nil
 	}
 
-	handler := PostDeploymentHandlerFunc(s.cwd, log, lister, events.NewNullEmitter())
+	handler := PostDeploymentHandlerFunc(s.cwd, log, lister, nil, nil, events.NewNullEmitter())
 	handler(rec, req)
 
 	// Handler returns 202 Accepted even if publishing errs,
@@ -241,7 +350,7 @@ func (s *PostDeploymentHandlerFuncSuite) TestPostDeploymentSubdir() {
 
 	publisher := &mockPublisher{}
 	publisher.On("PublishDirectory", mock.Anything).Return(nil)
-	publisherFactory = func(*state.State, events.Emitter, logging.Logger) (publish.Publisher, error) {
+	publisherFactory = func(context.Context, *state.State, events.Emitter, logging.Logger) (publish.Publisher, error) {
 		return publisher, nil
 	}
 	stateFactory = func(
@@ -262,7 +371,7 @@ func (s *PostDeploymentHandlerFuncSuite) TestPostDeploymentSubdir() {
 		st.Target = deployment.New()
 		return st, nil
 	}
-	handler := PostDeploymentHandlerFunc(base, log, lister, events.NewNullEmitter())
+	handler := PostDeploymentHandlerFunc(base, log, lister, nil, nil, events.NewNullEmitter())
 	handler(rec, req)
 
 	s.Equal(http.StatusAccepted, rec.Result().StatusCode)
@@ -290,7 +399,7 @@ func (s *PostDeploymentHandlerFuncSuite) TestPostDeploymentHandlerFuncWithSecret
 
 	publisher := &mockPublisher{}
 	publisher.On("PublishDirectory", mock.Anything).Return(nil)
-	publisherFactory = func(*state.State, events.Emitter, logging.Logger) (publish.Publisher, error) {
+	publisherFactory = func(context.Context, *state.State, events.Emitter, logging.Logger) (publish.Publisher, error) {
 		return publisher, nil
 	}
 
@@ -317,7 +426,7 @@ func (s *PostDeploymentHandlerFuncSuite) TestPostDeploymentHandlerFuncWithSecret
 		return st, nil
 	}
 
-	handler := PostDeploymentHandlerFunc(s.cwd, log, lister, events.NewNullEmitter())
+	handler := PostDeploymentHandlerFunc(s.cwd, log, lister, nil, nil, events.NewNullEmitter())
 	handler(rec, req)
 
 	s.Equal(http.StatusAccepted, rec.Result().StatusCode)
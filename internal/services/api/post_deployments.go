@@ -17,10 +17,12 @@ import (
 // Copyright (C) 2023 by Posit Software, PBC.
 
 type PostDeploymentsRequestBody struct {
-	AccountName string          `json:"account"`
-	ConfigName  string          `json:"config"`
-	SaveName    string          `json:"saveName"`
-	ID          types.ContentID `json:"id"`
+	AccountName string            `json:"account"`
+	ConfigName  string            `json:"config"`
+	SaveName    string            `json:"saveName"`
+	ID          types.ContentID   `json:"id"`
+	Note        string            `json:"note"`
+	Labels      map[string]string `json:"labels"`
 }
 
 func PostDeploymentsHandlerFunc(
@@ -92,6 +94,7 @@ func PostDeploymentsHandlerFunc(
 		d.ServerURL = acct.URL
 		d.ServerType = acct.ServerType
 		d.ConfigName = b.ConfigName
+		d.Annotate(b.Note, b.Labels)
 
 		if b.ID != "" {
 			d.ID = b.ID
@@ -5,16 +5,18 @@ package api
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io/fs"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 
 	"github.com/posit-dev/publisher/internal/bundles/matcher"
 	"github.com/posit-dev/publisher/internal/config"
 	"github.com/posit-dev/publisher/internal/initialize"
+	"github.com/posit-dev/publisher/internal/inspect/detectors"
 	"github.com/posit-dev/publisher/internal/logging"
-	"github.com/posit-dev/publisher/internal/types"
 	"github.com/posit-dev/publisher/internal/util"
 )
 
@@ -34,6 +36,9 @@ func getEntrypointPath(projectDir util.AbsolutePath, w http.ResponseWriter, req
 	if entrypoint == "" {
 		return util.RelativePath{}, nil
 	}
+	if detectors.IsEntrypointGlob(entrypoint) {
+		return getEntrypointFromGlob(projectDir, entrypoint, w, req, log)
+	}
 	entrypointPath, err := projectDir.SafeJoin(entrypoint)
 	if err != nil {
 		BadRequest(w, req, log, err)
@@ -58,7 +63,45 @@ func getEntrypointPath(projectDir util.AbsolutePath, w http.ResponseWriter, req
 	return relEntrypoint, nil
 }
 
-func PostInspectHandlerFunc(base util.AbsolutePath, log logging.Logger) http.HandlerFunc {
+func getEntrypointFromGlob(projectDir util.AbsolutePath, pattern string, w http.ResponseWriter, req *http.Request, log logging.Logger) (util.RelativePath, error) {
+	if !util.NewPath(pattern, nil).IsLocal() {
+		err := fmt.Errorf("entrypoint pattern must be a relative path within the project: '%s'", pattern)
+		BadRequest(w, req, log, err)
+		return util.RelativePath{}, err
+	}
+	relEntrypoint, err := detectors.ResolveEntrypoint(projectDir, util.NewRelativePath(pattern, projectDir.Fs()))
+	if err != nil {
+		if errors.Is(err, detectors.ErrNoEntrypointMatch) {
+			NotFound(w, log, err)
+		} else {
+			BadRequest(w, req, log, err)
+		}
+		return util.RelativePath{}, err
+	}
+	return relEntrypoint, nil
+}
+
+// getDetectionOptions returns the detection options from the
+// request's query parameters. searchDepth enables searching
+// subdirectories of each project directory for entrypoints,
+// and scanImports=true enables looking for framework imports
+// in the project modules that entrypoints import.
+func getDetectionOptions(w http.ResponseWriter, req *http.Request, log logging.Logger) (initialize.DetectionOptions, error) {
+	opts := initialize.DetectionOptions{}
+	if depth := req.URL.Query().Get("searchDepth"); depth != "" {
+		searchDepth, err := strconv.Atoi(depth)
+		if err != nil || searchDepth < 0 || searchDepth > detectors.MaxSearchDepth {
+			err = fmt.Errorf("searchDepth must be a number from 0 to %d: '%s'", detectors.MaxSearchDepth, depth)
+			BadRequest(w, req, log, err)
+			return opts, err
+		}
+		opts.SearchDepth = searchDepth
+	}
+	opts.ScanImports = req.URL.Query().Get("scanImports") == "true"
+	return opts, nil
+}
+
+func PostInspectHandlerFunc(base util.AbsolutePath, cache *detectors.DetectionCache, log logging.Logger) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
 		projectDir, relProjectDir, err := ProjectDirFromRequest(base, w, req, log)
 		if err != nil {
@@ -73,6 +116,12 @@ func PostInspectHandlerFunc(base util.AbsolutePath, log logging.Logger) http.Han
 			BadRequest(w, req, log, err)
 			return
 		}
+		opts, err := getDetectionOptions(w, req, log)
+		if err != nil {
+			// Response already returned by getDetectionOptions
+			return
+		}
+		opts.Cache = cache
 		pythonPath := util.NewPath(b.Python, nil)
 		response := []postInspectResponseBody{}
 
@@ -108,7 +157,11 @@ func PostInspectHandlerFunc(base util.AbsolutePath, log logging.Logger) http.Han
 				}
 				entrypoint := req.URL.Query().Get("entrypoint")
 				entrypointPath := util.NewRelativePath(entrypoint, base.Fs())
-				configs, err := initialize.GetPossibleConfigs(path, pythonPath, util.Path{}, entrypointPath, log)
+				configs, err := initialize.GetPossibleConfigs(path, pythonPath, util.Path{}, entrypointPath, opts, log)
+				if errors.Is(err, detectors.ErrNoEntrypointMatch) {
+					// An entrypoint pattern only needs to match in some directories.
+					return nil
+				}
 				if err != nil {
 					return err
 				}
@@ -131,13 +184,7 @@ func PostInspectHandlerFunc(base util.AbsolutePath, log logging.Logger) http.Han
 				return nil
 			})
 			if err != nil {
-				if aerr, ok := types.IsAgentErrorOf(err, types.ErrorPythonExecNotFound); ok {
-					apiErr := types.APIErrorPythonExecNotFoundFromAgentError(*aerr)
-					log.Error("Python executable not found", "error", err.Error())
-					apiErr.JSONResponse(w)
-					return
-				}
-				InternalError(w, req, log, err)
+				InspectionError(w, req, log, err)
 				return
 			}
 		} else {
@@ -146,15 +193,9 @@ func PostInspectHandlerFunc(base util.AbsolutePath, log logging.Logger) http.Han
 				// Response already returned by getEntrypointPath
 				return
 			}
-			configs, err := initialize.GetPossibleConfigs(projectDir, pythonPath, util.Path{}, entrypointPath, log)
+			configs, err := initialize.GetPossibleConfigs(projectDir, pythonPath, util.Path{}, entrypointPath, opts, log)
 			if err != nil {
-				if aerr, ok := types.IsAgentErrorOf(err, types.ErrorPythonExecNotFound); ok {
-					apiErr := types.APIErrorPythonExecNotFoundFromAgentError(*aerr)
-					log.Error("Python executable not found", "error", err.Error())
-					apiErr.JSONResponse(w)
-					return
-				}
-				InternalError(w, req, log, err)
+				InspectionError(w, req, log, err)
 				return
 			}
 
@@ -0,0 +1,173 @@
+package api
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/posit-dev/publisher/internal/config"
+	"github.com/posit-dev/publisher/internal/initialize"
+	"github.com/posit-dev/publisher/internal/inspect"
+	"github.com/posit-dev/publisher/internal/inspect/detectors"
+	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/services/middleware"
+	"github.com/posit-dev/publisher/internal/util"
+	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/suite"
+)
+
+type PostInspectSuite struct {
+	utiltest.Suite
+	log logging.Logger
+	cwd util.AbsolutePath
+}
+
+func TestPostInspectSuite(t *testing.T) {
+	suite.Run(t, new(PostInspectSuite))
+}
+
+func (s *PostInspectSuite) SetupSuite() {
+	s.log = logging.New()
+}
+
+func (s *PostInspectSuite) SetupTest() {
+	fs := afero.NewMemMapFs()
+	cwd, err := util.Getwd(fs)
+	s.Nil(err)
+	s.cwd = cwd
+	s.cwd.MkdirAll(0700)
+
+	initialize.PythonInspectorFactory = func(util.AbsolutePath, util.Path, logging.Logger) inspect.PythonInspector {
+		i := inspect.NewMockPythonInspector()
+		i.On("InspectPython").Return(&config.Python{
+			Version:        "3.11.3",
+			PackageFile:    "requirements.txt",
+			PackageManager: "pip",
+		}, nil)
+		return i
+	}
+}
+
+func (s *PostInspectSuite) TearDownTest() {
+	initialize.PythonInspectorFactory = inspect.NewPythonInspector
+}
+
+func (s *PostInspectSuite) inspect(url string) ([]postInspectResponseBody, int) {
+	h := PostInspectHandlerFunc(s.cwd, detectors.NewDetectionCache(), s.log)
+
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest("POST", url, strings.NewReader(`{"python": ""}`))
+	s.NoError(err)
+	h(rec, req)
+
+	if rec.Result().StatusCode != http.StatusOK {
+		return nil, rec.Result().StatusCode
+	}
+	var res []postInspectResponseBody
+	dec := json.NewDecoder(rec.Body)
+	dec.DisallowUnknownFields()
+	s.NoError(dec.Decode(&res))
+	return res, http.StatusOK
+}
+
+func (s *PostInspectSuite) TestInspectFlaskApp() {
+	appCode := "from flask import Flask\napp = Flask(__name__)\n"
+	err := s.cwd.Join("app.py").WriteFile([]byte(appCode), 0666)
+	s.NoError(err)
+	err = s.cwd.Join("requirements.txt").WriteFile([]byte("flask\n"), 0666)
+	s.NoError(err)
+
+	res, status := s.inspect("/api/inspect")
+	s.Equal(http.StatusOK, status)
+	s.Len(res, 1)
+	s.Equal(".", res[0].ProjectDir)
+	cfg := res[0].Configuration
+	s.Equal(config.ContentTypePythonFlask, cfg.Type)
+	s.Equal("app.py", cfg.Entrypoint)
+	s.Equal("3.11.3", cfg.Python.Version)
+	s.Equal([]string{"/app.py", "/requirements.txt"}, cfg.Files)
+}
+
+func (s *PostInspectSuite) TestInspectEntrypoint() {
+	err := s.cwd.Join("index.html").WriteFile([]byte("<html></html>"), 0666)
+	s.NoError(err)
+	err = s.cwd.Join("other.html").WriteFile([]byte("<html></html>"), 0666)
+	s.NoError(err)
+
+	res, status := s.inspect("/api/inspect?entrypoint=other.html")
+	s.Equal(http.StatusOK, status)
+	s.Len(res, 1)
+	s.Equal(config.ContentTypeHTML, res[0].Configuration.Type)
+	s.Equal("other.html", res[0].Configuration.Entrypoint)
+}
+
+func (s *PostInspectSuite) TestInspectEntrypointNotFound() {
+	_, status := s.inspect("/api/inspect?entrypoint=app.py")
+	s.Equal(http.StatusNotFound, status)
+}
+
+func (s *PostInspectSuite) TestInspectSearchDepth() {
+	srcDir := s.cwd.Join("src")
+	err := srcDir.MkdirAll(0777)
+	s.NoError(err)
+	appCode := "from flask import Flask\napp = Flask(__name__)\n"
+	err = srcDir.Join("app.py").WriteFile([]byte(appCode), 0666)
+	s.NoError(err)
+
+	res, status := s.inspect("/api/inspect")
+	s.Equal(http.StatusOK, status)
+	s.Len(res, 1)
+	s.Equal(config.ContentTypeUnknown, res[0].Configuration.Type)
+
+	res, status = s.inspect("/api/inspect?searchDepth=1")
+	s.Equal(http.StatusOK, status)
+	s.Len(res, 1)
+	s.Equal(config.ContentTypePythonFlask, res[0].Configuration.Type)
+	s.Equal("src/app.py", res[0].Configuration.Entrypoint)
+}
+
+func (s *PostInspectSuite) TestInspectSearchDepthInvalid() {
+	_, status := s.inspect("/api/inspect?searchDepth=deep")
+	s.Equal(http.StatusBadRequest, status)
+	_, status = s.inspect("/api/inspect?searchDepth=10")
+	s.Equal(http.StatusBadRequest, status)
+}
+
+func (s *PostInspectSuite) TestInspectScanImports() {
+	err := s.cwd.Join("myapp").MkdirAll(0777)
+	s.NoError(err)
+	err = s.cwd.Join("app.py").WriteFile([]byte("from myapp.server import app\n"), 0666)
+	s.NoError(err)
+	appCode := "from flask import Flask\napp = Flask(__name__)\n"
+	err = s.cwd.Join("myapp", "server.py").WriteFile([]byte(appCode), 0666)
+	s.NoError(err)
+
+	res, status := s.inspect("/api/inspect")
+	s.Equal(http.StatusOK, status)
+	s.Len(res, 1)
+	s.Equal(config.ContentTypeUnknown, res[0].Configuration.Type)
+
+	res, status = s.inspect("/api/inspect?scanImports=true")
+	s.Equal(http.StatusOK, status)
+	s.Len(res, 1)
+	s.Equal(config.ContentTypePythonFlask, res[0].Configuration.Type)
+	s.Equal("app.py", res[0].Configuration.Entrypoint)
+}
+
+func (s *PostInspectSuite) TestInspectBodyTooLarge() {
+	h := middleware.LimitRequestBody(100, PostInspectHandlerFunc(s.cwd, detectors.NewDetectionCache(), s.log))
+
+	rec := httptest.NewRecorder()
+	body := `{"python": "` + strings.Repeat("x", 200) + `"}`
+	req, err := http.NewRequest("POST", "/api/inspect", strings.NewReader(body))
+	s.NoError(err)
+	req.ContentLength = -1
+	h(rec, req)
+
+	s.Equal(http.StatusRequestEntityTooLarge, rec.Result().StatusCode)
+}
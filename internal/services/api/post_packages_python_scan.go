@@ -7,6 +7,7 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"time"
 
 	"github.com/posit-dev/publisher/internal/inspect"
 	"github.com/posit-dev/publisher/internal/logging"
@@ -17,12 +18,19 @@ import (
 type PostPackagesPythonScanRequest struct {
 	Python   string `json:"python"`
 	SaveName string `json:"saveName"`
+	// Timeout is the maximum scan time in seconds; 0 means no limit.
+	Timeout int `json:"timeout"`
+	// ReportUnused lists the packages in the existing requirements
+	// file that aren't imported by the project.
+	ReportUnused bool `json:"reportUnused"`
 }
 
 type PostPackagesPythonScanResponse struct {
 	Python       string   `json:"python"`
 	Requirements []string `json:"requirements"`
 	Incomplete   []string `json:"incomplete"`
+	Unused       []string `json:"unused"`
+	Partial      bool     `json:"partial"`
 }
 
 var inspectorFactory = inspect.NewPythonInspector
@@ -63,7 +71,15 @@ func (h *PostPackagesPythonScanHandler) ServeHTTP(w http.ResponseWriter, req *ht
 		BadRequest(w, req, h.log, err)
 		return
 	}
-	reqs, incomplete, effectivePython, err := inspector.ScanRequirements(projectDir)
+	var deadline time.Time
+	if b.Timeout > 0 {
+		deadline = time.Now().Add(time.Duration(b.Timeout) * time.Second)
+	}
+	packageFile := ""
+	if b.ReportUnused {
+		packageFile = b.SaveName
+	}
+	scan, err := inspector.ScanRequirements(projectDir, packageFile, deadline)
 	if err != nil {
 		if aerr, ok := types.IsAgentErrorOf(err, types.ErrorPythonExecNotFound); ok {
 			apiErr := types.APIErrorPythonExecNotFoundFromAgentError(*aerr)
@@ -75,16 +91,21 @@ func (h *PostPackagesPythonScanHandler) ServeHTTP(w http.ResponseWriter, req *ht
 		InternalError(w, req, h.log, err)
 		return
 	}
-	dest := projectDir.Join(b.SaveName)
-	err = inspector.WriteRequirementsFile(dest, reqs)
-	if err != nil {
-		InternalError(w, req, h.log, err)
-		return
+	if !scan.Partial {
+		// Don't overwrite the requirements file with partial results.
+		dest := projectDir.Join(b.SaveName)
+		err = inspector.WriteRequirementsFile(dest, scan.Requirements)
+		if err != nil {
+			InternalError(w, req, h.log, err)
+			return
+		}
 	}
 	response := PostPackagesPythonScanResponse{
-		Python:       effectivePython,
-		Requirements: reqs,
-		Incomplete:   incomplete,
+		Python:       scan.Python,
+		Requirements: scan.Requirements,
+		Incomplete:   scan.Incomplete,
+		Unused:       scan.Unused,
+		Partial:      scan.Partial,
 	}
 	w.Header().Set("content-type", "application/json")
 	json.NewEncoder(w).Encode(response)
@@ -11,6 +11,7 @@ import (
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/posit-dev/publisher/internal/inspect"
 	"github.com/posit-dev/publisher/internal/logging"
@@ -44,7 +45,7 @@ func (s *PostPackagesPythonScanSuite) TestNewPostRequirementsHandler() {
 
 func (s *PostPackagesPythonScanSuite) TestServeHTTP() {
 	rec := httptest.NewRecorder()
-	body := strings.NewReader(`{"saveName":""}`)
+	body := strings.NewReader(`{"saveName":"", "reportUnused": true}`)
 	req, err := http.NewRequest("POST", "/api/packages/python/scan", body)
 	s.NoError(err)
 
@@ -65,7 +66,15 @@ func (s *PostPackagesPythonScanSuite) TestServeHTTP() {
 	incomplete := []string{
 		"pandas",
 	}
-	i.On("ScanRequirements", mock.Anything).Return(pkgs, incomplete, "/usr/bin/python", nil)
+	unused := []string{
+		"requests",
+	}
+	i.On("ScanRequirements", mock.Anything, "requirements.txt", mock.Anything).Return(&inspect.RequirementsScan{
+		Requirements: pkgs,
+		Incomplete:   incomplete,
+		Unused:       unused,
+		Python:       "/usr/bin/python",
+	}, nil)
 	i.On("WriteRequirementsFile", destPath, mock.Anything).Return(nil)
 	inspectorFactory = func(util.AbsolutePath, util.Path, logging.Logger) inspect.PythonInspector { return i }
 
@@ -79,9 +88,51 @@ func (s *PostPackagesPythonScanSuite) TestServeHTTP() {
 
 	s.Equal(pkgs, res.Requirements)
 	s.Equal(incomplete, res.Incomplete)
+	s.Equal(unused, res.Unused)
 	s.Equal("/usr/bin/python", res.Python)
 }
 
+func (s *PostPackagesPythonScanSuite) TestServeHTTPPartial() {
+	rec := httptest.NewRecorder()
+	body := strings.NewReader(`{"timeout":10}`)
+	req, err := http.NewRequest("POST", "/api/packages/python/scan", body)
+	s.NoError(err)
+
+	base := util.NewAbsolutePath("/project", afero.NewMemMapFs())
+	err = base.MkdirAll(0777)
+	s.NoError(err)
+
+	log := logging.New()
+	h := NewPostPackagesPythonScanHandler(base, log)
+
+	i := inspect.NewMockPythonInspector()
+	isDeadline := mock.MatchedBy(func(deadline time.Time) bool {
+		return !deadline.IsZero() && time.Until(deadline) <= 10*time.Second
+	})
+	i.On("ScanRequirements", mock.Anything, mock.Anything, isDeadline).Return(&inspect.RequirementsScan{
+		Requirements: []string{"numpy"},
+		Incomplete:   []string{"numpy"},
+		Unused:       []string{},
+		Python:       "/usr/bin/python",
+		Partial:      true,
+	}, nil)
+	inspectorFactory = func(util.AbsolutePath, util.Path, logging.Logger) inspect.PythonInspector { return i }
+
+	h.ServeHTTP(rec, req)
+
+	s.Equal(http.StatusOK, rec.Result().StatusCode)
+
+	var res PostPackagesPythonScanResponse
+	dec := json.NewDecoder(rec.Body)
+	s.NoError(dec.Decode(&res))
+
+	s.True(res.Partial)
+	s.Equal([]string{"numpy"}, res.Requirements)
+	s.Equal([]string{"numpy"}, res.Incomplete)
+	// Partial results are not written to the requirements file.
+	i.AssertNotCalled(s.T(), "WriteRequirementsFile", mock.Anything, mock.Anything)
+}
+
 func (s *PostPackagesPythonScanSuite) TestServeHTTPEmptyBody() {
 	rec := httptest.NewRecorder()
 	body := strings.NewReader("")
@@ -97,7 +148,8 @@ func (s *PostPackagesPythonScanSuite) TestServeHTTPEmptyBody() {
 	h := NewPostPackagesPythonScanHandler(base, log)
 
 	i := inspect.NewMockPythonInspector()
-	i.On("ScanRequirements", mock.Anything).Return(nil, nil, "", nil)
+	// Unused packages aren't reported unless requested.
+	i.On("ScanRequirements", mock.Anything, "", mock.Anything).Return(&inspect.RequirementsScan{}, nil)
 	i.On("WriteRequirementsFile", destPath, mock.Anything).Return(nil)
 	inspectorFactory = func(util.AbsolutePath, util.Path, logging.Logger) inspect.PythonInspector { return i }
 
@@ -121,7 +173,7 @@ func (s *PostPackagesPythonScanSuite) TestServeHTTPWithSaveName() {
 	h := NewPostPackagesPythonScanHandler(base, log)
 
 	i := inspect.NewMockPythonInspector()
-	i.On("ScanRequirements", mock.Anything).Return(nil, nil, "", nil)
+	i.On("ScanRequirements", mock.Anything, mock.Anything, mock.Anything).Return(&inspect.RequirementsScan{}, nil)
 	i.On("WriteRequirementsFile", destPath, mock.Anything).Return(nil)
 	inspectorFactory = func(util.AbsolutePath, util.Path, logging.Logger) inspect.PythonInspector { return i }
 
@@ -144,7 +196,7 @@ func (s *PostPackagesPythonScanSuite) TestServeHTTPErr() {
 
 	testError := errors.New("test error from ScanRequirements")
 	i := inspect.NewMockPythonInspector()
-	i.On("ScanRequirements", mock.Anything).Return(nil, nil, "", testError)
+	i.On("ScanRequirements", mock.Anything, mock.Anything, mock.Anything).Return(nil, testError)
 	inspectorFactory = func(util.AbsolutePath, util.Path, logging.Logger) inspect.PythonInspector { return i }
 
 	h.ServeHTTP(rec, req)
@@ -173,7 +225,7 @@ func (s *PostPackagesPythonScanSuite) TestServeHTTPSubdir() {
 	h := NewPostPackagesPythonScanHandler(base, log)
 
 	i := inspect.NewMockPythonInspector()
-	i.On("ScanRequirements", mock.Anything).Return(nil, nil, "", nil)
+	i.On("ScanRequirements", mock.Anything, mock.Anything, mock.Anything).Return(&inspect.RequirementsScan{}, nil)
 	i.On("WriteRequirementsFile", destPath, mock.Anything).Return(nil)
 	inspectorFactory = func(base util.AbsolutePath, python util.Path, log logging.Logger) inspect.PythonInspector {
 		s.Equal(projectDir, base)
@@ -199,7 +251,7 @@ func (s *PostPackagesPythonScanSuite) TestServeHTTPNoPythonErr() {
 
 	testError := types.NewAgentError(types.ErrorPythonExecNotFound, errors.New("no python"), nil)
 	i := inspect.NewMockPythonInspector()
-	i.On("ScanRequirements", mock.Anything).Return(nil, nil, "", testError)
+	i.On("ScanRequirements", mock.Anything, mock.Anything, mock.Anything).Return(nil, testError)
 	inspectorFactory = func(util.AbsolutePath, util.Path, logging.Logger) inspect.PythonInspector { return i }
 
 	h.ServeHTTP(rec, req)
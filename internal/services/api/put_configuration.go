@@ -14,6 +14,7 @@ import (
 	"github.com/posit-dev/publisher/internal/config"
 	"github.com/posit-dev/publisher/internal/logging"
 	"github.com/posit-dev/publisher/internal/schema"
+	"github.com/posit-dev/publisher/internal/types"
 	"github.com/posit-dev/publisher/internal/util"
 )
 
@@ -47,6 +48,7 @@ func camelToSnakeMap(m map[string]any) {
 func PutConfigurationHandlerFunc(base util.AbsolutePath, log logging.Logger) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
 		name := mux.Vars(req)["name"]
+		draft := req.URL.Query().Get("draft") == "true"
 		projectDir, relProjectDir, err := ProjectDirFromRequest(base, w, req, log)
 		if err != nil {
 			// Response already returned by ProjectDirFromRequest
@@ -87,9 +89,21 @@ func PutConfigurationHandlerFunc(base util.AbsolutePath, log logging.Logger) htt
 			InternalError(w, req, log, err)
 			return
 		}
-		err = validator.ValidateContent(rawConfig)
+		if draft {
+			// Drafts may be incomplete, but what's there must be valid.
+			err = validator.ValidatePartialContent(rawConfig)
+		} else {
+			err = validator.ValidateContent(rawConfig)
+		}
 		if err != nil {
-			BadRequest(w, req, log, err)
+			fieldErrors := schema.FieldErrors(err)
+			if fieldErrors == nil {
+				BadRequest(w, req, log, err)
+				return
+			}
+			apiErr := types.APIErrorInvalidConfigFromFieldErrors(fieldErrors)
+			log.Error("Invalid configuration", "apiErr", apiErr.Error())
+			apiErr.JSONResponse(w)
 			return
 		}
 
@@ -102,6 +116,23 @@ func PutConfigurationHandlerFunc(base util.AbsolutePath, log logging.Logger) htt
 			BadRequest(w, req, log, err)
 			return
 		}
+		// Saving without draft mode marks the configuration as complete.
+		cfg.Draft = draft
+		if draft {
+			// Fill in what's needed for the draft to be read back.
+			if cfg.Schema == "" {
+				cfg.Schema = schema.ConfigSchemaURL
+			}
+			if cfg.Type == "" {
+				cfg.Type = config.ContentTypeUnknown
+			}
+		}
+
+		err = cfg.NormalizeFiles(projectDir)
+		if err != nil {
+			BadRequest(w, req, log, err)
+			return
+		}
 
 		configPath := config.GetConfigPath(projectDir, name)
 
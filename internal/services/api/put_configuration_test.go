@@ -15,6 +15,8 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/posit-dev/publisher/internal/config"
 	"github.com/posit-dev/publisher/internal/logging"
+	"github.com/posit-dev/publisher/internal/services/middleware"
+	"github.com/posit-dev/publisher/internal/types"
 	"github.com/posit-dev/publisher/internal/util"
 	"github.com/posit-dev/publisher/internal/util/utiltest"
 	"github.com/spf13/afero"
@@ -115,6 +117,135 @@ func (s *PutConfigurationSuite) TestPutConfigurationBadConfig() {
 	s.False(exists)
 }
 
+func (s *PutConfigurationSuite) TestPutConfigurationDraft() {
+	log := logging.New()
+
+	configName := "myConfig"
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest("PUT", "/api/configurations/"+configName+"?draft=true", nil)
+	s.NoError(err)
+	req = mux.SetURLVars(req, map[string]string{"name": configName})
+
+	// No $schema, type, or entrypoint
+	req.Body = io.NopCloser(strings.NewReader(`{"title": "Work in progress"}`))
+
+	handler := PutConfigurationHandlerFunc(s.cwd, log)
+	handler(rec, req)
+	s.Equal(http.StatusOK, rec.Result().StatusCode)
+
+	var responseBody configDTO
+	err = json.NewDecoder(rec.Result().Body).Decode(&responseBody)
+	s.NoError(err)
+	s.True(responseBody.Configuration.Draft)
+
+	// The draft can be read back.
+	configPath := config.GetConfigPath(s.cwd, configName)
+	cfg, err := config.FromFile(configPath)
+	s.NoError(err)
+	s.True(cfg.Draft)
+	s.Equal("Work in progress", cfg.Title)
+	s.Equal(config.ContentTypeUnknown, cfg.Type)
+}
+
+func (s *PutConfigurationSuite) TestPutConfigurationDraftBadConfig() {
+	log := logging.New()
+
+	configName := "myConfig"
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest("PUT", "/api/configurations/"+configName+"?draft=true", nil)
+	s.NoError(err)
+	req = mux.SetURLVars(req, map[string]string{"name": configName})
+
+	// Drafts may be incomplete, but not invalid.
+	req.Body = io.NopCloser(strings.NewReader(`{"type": "this-is-not-valid"}`))
+
+	handler := PutConfigurationHandlerFunc(s.cwd, log)
+	handler(rec, req)
+	s.Equal(http.StatusBadRequest, rec.Result().StatusCode)
+
+	configPath := config.GetConfigPath(s.cwd, configName)
+	exists, err := configPath.Exists()
+	s.NoError(err)
+	s.False(exists)
+}
+
+func (s *PutConfigurationSuite) TestPutConfigurationIncompleteNotDraft() {
+	log := logging.New()
+
+	configName := "myConfig"
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest("PUT", "/api/configurations/"+configName, nil)
+	s.NoError(err)
+	req = mux.SetURLVars(req, map[string]string{"name": configName})
+
+	req.Body = io.NopCloser(strings.NewReader(`{"title": "Work in progress"}`))
+
+	handler := PutConfigurationHandlerFunc(s.cwd, log)
+	handler(rec, req)
+	s.Equal(http.StatusBadRequest, rec.Result().StatusCode)
+}
+
+func (s *PutConfigurationSuite) TestPutConfigurationTooLarge() {
+	log := logging.New()
+
+	configName := "myConfig"
+	rec := httptest.NewRecorder()
+	body := `{"type": "html", "entrypoint": "index.html", "description": "` + strings.Repeat("x", 2000) + `"}`
+	req, err := http.NewRequest("PUT", "/api/configurations/"+configName, strings.NewReader(body))
+	s.NoError(err)
+	req = mux.SetURLVars(req, map[string]string{"name": configName})
+	// Don't declare the length, so the limit is enforced while reading.
+	req.ContentLength = -1
+
+	handler := middleware.LimitRequestBody(1000, PutConfigurationHandlerFunc(s.cwd, log))
+	handler(rec, req)
+	s.Equal(http.StatusRequestEntityTooLarge, rec.Result().StatusCode)
+
+	configPath := config.GetConfigPath(s.cwd, configName)
+	exists, err := configPath.Exists()
+	s.NoError(err)
+	s.False(exists)
+}
+
+func (s *PutConfigurationSuite) TestPutConfigurationFieldErrors() {
+	log := logging.New()
+
+	configName := "myConfig"
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest("PUT", "/api/configurations/"+configName, nil)
+	s.NoError(err)
+	req = mux.SetURLVars(req, map[string]string{"name": configName})
+
+	req.Body = io.NopCloser(strings.NewReader(`{
+		"$schema": "https://cdn.posit.co/publisher/schemas/posit-publishing-schema-v3.json",
+		"type": "this-is-not-valid",
+		"validate": "yes",
+		"python": {
+			"packageManager": "pip"
+		}
+	}`))
+
+	handler := PutConfigurationHandlerFunc(s.cwd, log)
+	handler(rec, req)
+	s.Equal(http.StatusBadRequest, rec.Result().StatusCode)
+	s.Equal("application/json", rec.Header().Get("content-type"))
+
+	var res types.APIErrorInvalidConfigDetails
+	err = json.NewDecoder(rec.Result().Body).Decode(&res)
+	s.NoError(err)
+	s.Equal(types.ErrorInvalidConfig, res.Code)
+
+	fields := map[string]string{}
+	for _, fieldErr := range res.Details.Errors {
+		fields[fieldErr.Field] = fieldErr.Message
+	}
+	s.Len(fields, 4)
+	s.Contains(fields["type"], "value must be one of")
+	s.Contains(fields["validate"], "expected boolean")
+	s.Equal("missing required property", fields["entrypoint"])
+	s.Equal("missing required property", fields["python.version"])
+}
+
 func (s *PutConfigurationSuite) TestPutConfigurationBadName() {
 	log := logging.New()
 
@@ -204,3 +335,66 @@ func (s *PutConfigurationSuite) TestPutConfigurationSubdir() {
 	s.Equal(&expected, responseBody.Configuration.Connect.Kubernetes.DefaultREnvironmentManagement)
 	s.Len(responseBody.Configuration.Comments, 2)
 }
+
+func (s *PutConfigurationSuite) TestPutConfigurationNormalizesFiles() {
+	log := logging.New()
+
+	configName := "myConfig"
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest("PUT", "/api/configurations/"+configName, nil)
+	s.NoError(err)
+	req = mux.SetURLVars(req, map[string]string{"name": configName})
+
+	appPath, err := json.Marshal(s.cwd.Join("app.py").String())
+	s.NoError(err)
+	req.Body = io.NopCloser(strings.NewReader(`{
+		"$schema": "https://cdn.posit.co/publisher/schemas/posit-publishing-schema-v3.json",
+		"type": "html",
+		"entrypoint": "index.html",
+		"files": ["/index.html", "./index.html", "/lib//util.js", ` + string(appPath) + `]
+	}`))
+
+	handler := PutConfigurationHandlerFunc(s.cwd, log)
+	handler(rec, req)
+	s.Equal(http.StatusOK, rec.Result().StatusCode)
+
+	var responseBody configDTO
+	err = json.NewDecoder(rec.Result().Body).Decode(&responseBody)
+	s.NoError(err)
+	expected := []string{"/index.html", "/lib/util.js", "/app.py"}
+	s.Equal(expected, responseBody.Configuration.Files)
+
+	cfg, err := config.FromFile(config.GetConfigPath(s.cwd, configName))
+	s.NoError(err)
+	s.Equal(expected, cfg.Files)
+}
+
+func (s *PutConfigurationSuite) TestPutConfigurationFileOutsideProject() {
+	log := logging.New()
+
+	configName := "myConfig"
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest("PUT", "/api/configurations/"+configName, nil)
+	s.NoError(err)
+	req = mux.SetURLVars(req, map[string]string{"name": configName})
+
+	req.Body = io.NopCloser(strings.NewReader(`{
+		"$schema": "https://cdn.posit.co/publisher/schemas/posit-publishing-schema-v3.json",
+		"type": "html",
+		"entrypoint": "index.html",
+		"files": ["/index.html", "../secrets.txt"]
+	}`))
+
+	handler := PutConfigurationHandlerFunc(s.cwd, log)
+	handler(rec, req)
+	s.Equal(http.StatusBadRequest, rec.Result().StatusCode)
+
+	body, err := io.ReadAll(rec.Result().Body)
+	s.NoError(err)
+	s.Contains(string(body), config.ErrFileOutsideProject.Error())
+
+	// The configuration should not have been written.
+	exists, err := config.GetConfigPath(s.cwd, configName).Exists()
+	s.NoError(err)
+	s.False(exists)
+}
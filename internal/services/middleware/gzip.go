@@ -0,0 +1,171 @@
+package middleware
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// DefaultGzipMinSize is the smallest response that is worth compressing.
+const DefaultGzipMinSize = 1400
+
+type gzipMode int
+
+const (
+	gzipUndecided   gzipMode = iota
+	gzipBuffering            // JSON response, waiting to see if it's big enough
+	gzipPlain                // writing through, uncompressed
+	gzipCompressing          // writing through the gzip writer
+)
+
+// gzipResponseWriter compresses JSON responses of at least minSize bytes.
+// Responses are buffered until they reach minSize, so small responses
+// are sent as-is. Other content types (like the event stream)
+// and websocket connections are passed through unchanged.
+type gzipResponseWriter struct {
+	writer  http.ResponseWriter
+	minSize int
+	mode    gzipMode
+	status  int
+	buf     bytes.Buffer
+	gz      *gzip.Writer
+}
+
+func (w *gzipResponseWriter) Header() http.Header {
+	return w.writer.Header()
+}
+
+func (w *gzipResponseWriter) WriteHeader(status int) {
+	if w.mode != gzipUndecided {
+		return
+	}
+	w.status = status
+	header := w.writer.Header()
+	isJSON := strings.HasPrefix(strings.ToLower(header.Get("Content-Type")), "application/json")
+	if status != http.StatusOK || !isJSON || header.Get("Content-Encoding") != "" {
+		w.mode = gzipPlain
+		w.writer.WriteHeader(status)
+		return
+	}
+	header.Add("Vary", "Accept-Encoding")
+	w.mode = gzipBuffering
+}
+
+func (w *gzipResponseWriter) Write(p []byte) (int, error) {
+	if w.mode == gzipUndecided {
+		w.WriteHeader(http.StatusOK)
+	}
+	switch w.mode {
+	case gzipBuffering:
+		w.buf.Write(p)
+		if w.buf.Len() >= w.minSize {
+			err := w.startCompressing()
+			if err != nil {
+				return 0, err
+			}
+		}
+		return len(p), nil
+	case gzipCompressing:
+		return w.gz.Write(p)
+	default:
+		return w.writer.Write(p)
+	}
+}
+
+func (w *gzipResponseWriter) startCompressing() error {
+	header := w.writer.Header()
+	header.Set("Content-Encoding", "gzip")
+	header.Del("Content-Length")
+	w.writer.WriteHeader(w.status)
+	w.gz = gzip.NewWriter(w.writer)
+	w.mode = gzipCompressing
+	_, err := w.gz.Write(w.buf.Bytes())
+	w.buf.Reset()
+	return err
+}
+
+// writeBuffered sends a buffered response that turned out to be
+// too small to compress.
+func (w *gzipResponseWriter) writeBuffered() error {
+	w.mode = gzipPlain
+	w.writer.WriteHeader(w.status)
+	_, err := w.writer.Write(w.buf.Bytes())
+	w.buf.Reset()
+	return err
+}
+
+func (w *gzipResponseWriter) finish() error {
+	switch w.mode {
+	case gzipBuffering:
+		return w.writeBuffered()
+	case gzipCompressing:
+		return w.gz.Close()
+	}
+	return nil
+}
+
+func (w *gzipResponseWriter) Flush() {
+	// sse.Server.ServeHTTP requires that the writer also implement http.Flusher
+	switch w.mode {
+	case gzipBuffering:
+		w.writeBuffered()
+	case gzipCompressing:
+		w.gz.Flush()
+	}
+	if flusher, ok := w.writer.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *gzipResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	// websocket upgrades require that the writer also implement http.Hijacker
+	hijacker, ok := w.writer.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking")
+	}
+	return hijacker.Hijack()
+}
+
+// acceptsGzip returns true if the Accept-Encoding header
+// allows gzip, and doesn't give it a quality of zero.
+func acceptsGzip(req *http.Request) bool {
+	for _, value := range req.Header.Values("Accept-Encoding") {
+		for _, encoding := range strings.Split(value, ",") {
+			name, params, _ := strings.Cut(encoding, ";")
+			if strings.TrimSpace(strings.ToLower(name)) != "gzip" {
+				continue
+			}
+			key, value, _ := strings.Cut(strings.TrimSpace(params), "=")
+			if key != "q" {
+				return true
+			}
+			q, err := strconv.ParseFloat(value, 64)
+			return err == nil && q > 0
+		}
+	}
+	return false
+}
+
+// Gzip compresses JSON responses of at least minSize bytes,
+// for clients that accept gzip encoding.
+func Gzip(minSize int, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if !acceptsGzip(req) {
+			next(w, req)
+			return
+		}
+		writer := &gzipResponseWriter{
+			writer:  w,
+			minSize: minSize,
+		}
+		next(writer, req)
+		writer.finish()
+	}
+}
@@ -0,0 +1,113 @@
+package middleware
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/stretchr/testify/suite"
+)
+
+type GzipSuite struct {
+	utiltest.Suite
+}
+
+func TestGzipSuite(t *testing.T) {
+	suite.Run(t, new(GzipSuite))
+}
+
+var largeJSON = `{"files": [` + strings.Repeat(`"app.py", `, 500) + `"app.py"]}`
+
+func jsonHandler(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(body))
+	}
+}
+
+func (s *GzipSuite) serve(h http.HandlerFunc, acceptEncoding string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest(http.MethodGet, "/api/files", nil)
+	s.NoError(err)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	Gzip(DefaultGzipMinSize, h).ServeHTTP(rec, req)
+	return rec
+}
+
+func (s *GzipSuite) TestLargeJSONCompressed() {
+	rec := s.serve(jsonHandler(largeJSON), "gzip, deflate, br")
+	s.Equal(http.StatusOK, rec.Code)
+	s.Equal("gzip", rec.Header().Get("Content-Encoding"))
+	s.Equal("Accept-Encoding", rec.Header().Get("Vary"))
+	s.Less(rec.Body.Len(), len(largeJSON))
+
+	r, err := gzip.NewReader(rec.Body)
+	s.NoError(err)
+	body, err := io.ReadAll(r)
+	s.NoError(err)
+	s.Equal(largeJSON, string(body))
+}
+
+func (s *GzipSuite) TestNotAccepted() {
+	for _, acceptEncoding := range []string{"", "deflate", "gzip;q=0"} {
+		rec := s.serve(jsonHandler(largeJSON), acceptEncoding)
+		s.Equal(http.StatusOK, rec.Code)
+		s.Equal("", rec.Header().Get("Content-Encoding"))
+		s.Equal(largeJSON, rec.Body.String())
+	}
+}
+
+func (s *GzipSuite) TestSmallJSONNotCompressed() {
+	rec := s.serve(jsonHandler(`{"ok": true}`), "gzip")
+	s.Equal(http.StatusOK, rec.Code)
+	s.Equal("", rec.Header().Get("Content-Encoding"))
+	s.Equal(`{"ok": true}`, rec.Body.String())
+}
+
+func (s *GzipSuite) TestOtherContentNotCompressed() {
+	h := func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("content-type", "text/event-stream")
+		w.Write([]byte(largeJSON))
+	}
+	rec := s.serve(h, "gzip")
+	s.Equal("", rec.Header().Get("Content-Encoding"))
+	s.Equal(largeJSON, rec.Body.String())
+}
+
+func (s *GzipSuite) TestErrorsNotCompressed() {
+	h := func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(largeJSON))
+	}
+	rec := s.serve(h, "gzip")
+	s.Equal(http.StatusBadRequest, rec.Code)
+	s.Equal("", rec.Header().Get("Content-Encoding"))
+	s.Equal(largeJSON, rec.Body.String())
+}
+
+func (s *GzipSuite) TestImplicitStatus() {
+	h := func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		w.Write([]byte(largeJSON[:100]))
+		w.Write([]byte(largeJSON[100:]))
+	}
+	rec := s.serve(h, "gzip")
+	s.Equal(http.StatusOK, rec.Code)
+	s.Equal("gzip", rec.Header().Get("Content-Encoding"))
+
+	r, err := gzip.NewReader(rec.Body)
+	s.NoError(err)
+	body, err := io.ReadAll(r)
+	s.NoError(err)
+	s.Equal(largeJSON, string(body))
+}
@@ -0,0 +1,96 @@
+package middleware
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// DefaultMaxConcurrentOperations is how many expensive API
+// operations (file walks and dependency scans), and separately
+// how many deployments, can run at once.
+const DefaultMaxConcurrentOperations = 4
+
+// DefaultConcurrencyWait is how long a request waits for
+// a free slot before it is rejected.
+const DefaultConcurrencyWait = 30 * time.Second
+
+// ConcurrencyLimiter bounds the number of expensive operations
+// running at once. A nil limiter doesn't limit anything.
+type ConcurrencyLimiter struct {
+	slots chan struct{}
+	wait  time.Duration
+}
+
+// NewConcurrencyLimiter returns a limiter that allows max operations
+// at once. Others wait up to `wait` for a slot. If max is zero or
+// less, operations aren't limited and nil is returned.
+func NewConcurrencyLimiter(max int, wait time.Duration) *ConcurrencyLimiter {
+	if max <= 0 {
+		return nil
+	}
+	return &ConcurrencyLimiter{
+		slots: make(chan struct{}, max),
+		wait:  wait,
+	}
+}
+
+// Acquire waits for a free slot. It returns false if none became
+// available in time, or if ctx was cancelled. If it returns true,
+// the caller must call Release when the operation is done.
+func (l *ConcurrencyLimiter) Acquire(ctx context.Context) bool {
+	if l == nil {
+		return true
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return true
+	default:
+	}
+	timer := time.NewTimer(l.wait)
+	defer timer.Stop()
+
+	select {
+	case l.slots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// Release frees a slot taken by Acquire.
+func (l *ConcurrencyLimiter) Release() {
+	if l == nil {
+		return
+	}
+	<-l.slots
+}
+
+// TooManyRequests responds with a 429 for a request
+// that couldn't get a slot.
+func (l *ConcurrencyLimiter) TooManyRequests(w http.ResponseWriter) {
+	w.Header().Set("Retry-After", fmt.Sprintf("%d", int(l.wait.Seconds())+1))
+	msg := fmt.Sprintf("too many operations in progress; the limit is %d", cap(l.slots))
+	http.Error(w, msg, http.StatusTooManyRequests)
+}
+
+// Limit runs next while holding a slot, rejecting the request
+// with a 429 if no slot is available in time.
+func (l *ConcurrencyLimiter) Limit(next http.HandlerFunc) http.HandlerFunc {
+	if l == nil {
+		return next
+	}
+	return func(w http.ResponseWriter, req *http.Request) {
+		if !l.Acquire(req.Context()) {
+			l.TooManyRequests(w)
+			return
+		}
+		defer l.Release()
+		next(w, req)
+	}
+}
@@ -0,0 +1,145 @@
+package middleware
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/stretchr/testify/suite"
+)
+
+type LimitConcurrencySuite struct {
+	utiltest.Suite
+}
+
+func TestLimitConcurrencySuite(t *testing.T) {
+	suite.Run(t, new(LimitConcurrencySuite))
+}
+
+// blockingHandler counts the requests in progress, and
+// blocks each one until release is closed.
+type blockingHandler struct {
+	running  atomic.Int32
+	maxSeen  atomic.Int32
+	started  chan struct{}
+	release  chan struct{}
+	finished atomic.Int32
+}
+
+func newBlockingHandler() *blockingHandler {
+	return &blockingHandler{
+		started: make(chan struct{}, 100),
+		release: make(chan struct{}),
+	}
+}
+
+func (h *blockingHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	n := h.running.Add(1)
+	for {
+		seen := h.maxSeen.Load()
+		if n <= seen || h.maxSeen.CompareAndSwap(seen, n) {
+			break
+		}
+	}
+	h.started <- struct{}{}
+	<-h.release
+	h.running.Add(-1)
+	h.finished.Add(1)
+}
+
+// serve sends count requests at once, and returns their status codes.
+func serve(handler http.HandlerFunc, count int) <-chan int {
+	codes := make(chan int, count)
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/api/files", nil)
+			handler(rec, req)
+			codes <- rec.Code
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(codes)
+	}()
+	return codes
+}
+
+func (s *LimitConcurrencySuite) TestRejectsBeyondLimit() {
+	h := newBlockingHandler()
+	limiter := NewConcurrencyLimiter(2, 50*time.Millisecond)
+	codes := serve(limiter.Limit(h.ServeHTTP), 5)
+
+	// Two requests get in; the other three time out waiting.
+	<-h.started
+	<-h.started
+	rejected := 0
+	for i := 0; i < 3; i++ {
+		code := <-codes
+		s.Equal(http.StatusTooManyRequests, code)
+		rejected++
+	}
+	close(h.release)
+	for code := range codes {
+		s.Equal(http.StatusOK, code)
+	}
+	s.Equal(3, rejected)
+	s.Equal(int32(2), h.maxSeen.Load())
+	s.Equal(int32(2), h.finished.Load())
+}
+
+func (s *LimitConcurrencySuite) TestQueuesWithinWait() {
+	h := newBlockingHandler()
+	limiter := NewConcurrencyLimiter(2, 10*time.Second)
+	codes := serve(limiter.Limit(h.ServeHTTP), 6)
+
+	<-h.started
+	<-h.started
+	close(h.release)
+
+	// Everything runs eventually, but never more than two at once.
+	for code := range codes {
+		s.Equal(http.StatusOK, code)
+	}
+	s.Equal(int32(6), h.finished.Load())
+	s.LessOrEqual(h.maxSeen.Load(), int32(2))
+}
+
+func (s *LimitConcurrencySuite) TestRetryAfter() {
+	limiter := NewConcurrencyLimiter(1, 0)
+	s.True(limiter.Acquire(httptest.NewRequest(http.MethodGet, "/", nil).Context()))
+	defer limiter.Release()
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/files", nil)
+	limiter.Limit(func(w http.ResponseWriter, req *http.Request) {})(rec, req)
+
+	s.Equal(http.StatusTooManyRequests, rec.Code)
+	s.Equal("1", rec.Header().Get("Retry-After"))
+	s.Contains(rec.Body.String(), "the limit is 1")
+}
+
+func (s *LimitConcurrencySuite) TestNoLimit() {
+	limiter := NewConcurrencyLimiter(0, time.Second)
+	s.Nil(limiter)
+
+	h := newBlockingHandler()
+	codes := serve(limiter.Limit(h.ServeHTTP), 5)
+	for i := 0; i < 5; i++ {
+		<-h.started
+	}
+	close(h.release)
+	for code := range codes {
+		s.Equal(http.StatusOK, code)
+	}
+	s.Equal(int32(5), h.maxSeen.Load())
+}
@@ -0,0 +1,30 @@
+package middleware
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// DefaultMaxRequestBodySize is the largest request body the API accepts.
+// Requests are JSON documents, so this is generous.
+const DefaultMaxRequestBodySize int64 = 10 << 20
+
+// LimitRequestBody rejects requests with bodies larger than limit bytes.
+// Requests that declare a larger Content-Length get a 413 right away.
+// Otherwise, reading past the limit returns an *http.MaxBytesError
+// to the handler, which should respond with a 413.
+func LimitRequestBody(limit int64, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if req.ContentLength > limit {
+			msg := fmt.Sprintf("request body is too large; the limit is %d bytes", limit)
+			http.Error(w, msg, http.StatusRequestEntityTooLarge)
+			return
+		}
+		if req.Body != nil {
+			req.Body = http.MaxBytesReader(w, req.Body, limit)
+		}
+		next(w, req)
+	}
+}
@@ -0,0 +1,66 @@
+package middleware
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/stretchr/testify/suite"
+)
+
+type LimitRequestBodySuite struct {
+	utiltest.Suite
+}
+
+func TestLimitRequestBodySuite(t *testing.T) {
+	suite.Run(t, new(LimitRequestBodySuite))
+}
+
+func (s *LimitRequestBodySuite) TestWithinLimit() {
+	var body []byte
+	next := func(w http.ResponseWriter, req *http.Request) {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		s.NoError(err)
+	}
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/inspect", strings.NewReader("0123456789"))
+	LimitRequestBody(10, next).ServeHTTP(rec, req)
+
+	s.Equal(http.StatusOK, rec.Code)
+	s.Equal("0123456789", string(body))
+}
+
+func (s *LimitRequestBodySuite) TestDeclaredLengthTooLarge() {
+	called := false
+	next := func(w http.ResponseWriter, req *http.Request) {
+		called = true
+	}
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/inspect", strings.NewReader("0123456789!"))
+	LimitRequestBody(10, next).ServeHTTP(rec, req)
+
+	s.Equal(http.StatusRequestEntityTooLarge, rec.Code)
+	s.False(called)
+}
+
+func (s *LimitRequestBodySuite) TestUndeclaredLengthTooLarge() {
+	var readErr error
+	next := func(w http.ResponseWriter, req *http.Request) {
+		_, readErr = io.ReadAll(req.Body)
+	}
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/inspect", strings.NewReader("0123456789!"))
+	// As with a chunked request
+	req.ContentLength = -1
+	LimitRequestBody(10, next).ServeHTTP(rec, req)
+
+	var maxBytesErr *http.MaxBytesError
+	s.True(errors.As(readErr, &maxBytesErr))
+}
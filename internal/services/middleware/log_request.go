@@ -3,10 +3,13 @@ package middleware
 // Copyright (C) 2023 by Posit Software, PBC.
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"strings"
 	"time"
@@ -54,6 +57,15 @@ func (w *statusCapturingResponseWriter) Flush() {
 	w.writer.(http.Flusher).Flush()
 }
 
+func (w *statusCapturingResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	// websocket upgrades require that the writer also implement http.Hijacker
+	hijacker, ok := w.writer.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking")
+	}
+	return hijacker.Hijack()
+}
+
 func getBodyParams(req *http.Request) map[string]string {
 	contentType := req.Header.Get("Content-Type")
 	if contentType != "application/json" {
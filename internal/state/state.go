@@ -24,6 +24,18 @@ type State struct {
 	Target      *deployment.Deployment
 	LocalID     LocalDeploymentID
 	Secrets     map[string]string
+
+	// SymlinkPolicy controls how symlinks are handled when bundling.
+	// If empty, util.DefaultSymlinkPolicy is used.
+	SymlinkPolicy util.SymlinkPolicy
+
+	// Strict makes deployment fail if there are
+	// any warnings, instead of only reporting them.
+	Strict bool
+
+	// PruneEnvVars removes environment variables from the content
+	// on the server if they are no longer in the configuration.
+	PruneEnvVars bool
 }
 
 func loadConfig(path util.AbsolutePath, configName string) (*config.Config, error) {
@@ -95,6 +107,7 @@ func Empty() *State {
 }
 
 var ErrServerURLMismatch = errors.New("the account provided is for a different server; it must match the server for this deployment")
+var ErrDraftConfig = errors.New("the configuration is a draft; complete it before deploying")
 
 func New(path util.AbsolutePath, accountName, configName, targetName string, saveName string, accountList accounts.AccountList, secrets map[string]string, insecure bool) (*State, error) {
 	var target *deployment.Deployment
@@ -139,7 +152,10 @@ func New(path util.AbsolutePath, accountName, configName, targetName string, sav
 	}
 
 	if configName == "" {
-		configName = config.DefaultConfigName
+		configName, err = config.DiscoverConfigName(path)
+		if err != nil {
+			return nil, err
+		}
 	}
 	cfg, err = loadConfig(path, configName)
 	if err != nil {
@@ -148,6 +164,9 @@ func New(path util.AbsolutePath, accountName, configName, targetName string, sav
 		}
 		return nil, err
 	}
+	if cfg.Draft {
+		return nil, fmt.Errorf("can't deploy '%s': %w", configName, ErrDraftConfig)
+	}
 
 	// Check that the secrets passed are in the config
 	for secret := range secrets {
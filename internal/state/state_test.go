@@ -335,6 +335,32 @@ func (s *StateSuite) TestNewNonDefaultConfig() {
 	s.NotNil(state.Target)
 }
 
+func (s *StateSuite) TestNewDiscoversConfig() {
+	accts := &accounts.MockAccountList{}
+	acct := accounts.Account{}
+	accts.On("GetAllAccounts").Return([]accounts.Account{acct}, nil)
+
+	cfg := s.makeConfiguration("staging")
+
+	state, err := New(s.cwd, "", "", "", "", accts, nil, false)
+	s.NoError(err)
+	s.Equal("staging", state.ConfigName)
+	s.Equal(cfg, state.Config)
+}
+
+func (s *StateSuite) TestNewMultipleConfigsErr() {
+	accts := &accounts.MockAccountList{}
+	acct := accounts.Account{}
+	accts.On("GetAllAccounts").Return([]accounts.Account{acct}, nil)
+
+	s.makeConfiguration("default")
+	s.makeConfiguration("staging")
+
+	state, err := New(s.cwd, "", "", "", "", accts, nil, false)
+	s.ErrorIs(err, config.ErrMultipleConfigs)
+	s.Nil(state)
+}
+
 func (s *StateSuite) TestNewConfigErr() {
 	accts := &accounts.MockAccountList{}
 	acct := accounts.Account{}
@@ -346,6 +372,23 @@ func (s *StateSuite) TestNewConfigErr() {
 	s.Nil(state)
 }
 
+func (s *StateSuite) TestNewDraftConfig() {
+	accts := &accounts.MockAccountList{}
+	acct := accounts.Account{}
+	accts.On("GetAllAccounts").Return([]accounts.Account{acct}, nil)
+
+	path := config.GetConfigPath(s.cwd, "default")
+	cfg := config.New()
+	cfg.Draft = true
+	cfg.Title = "Work in progress"
+	err := cfg.WriteFile(path)
+	s.NoError(err)
+
+	state, err := New(s.cwd, "", "", "", "", accts, nil, false)
+	s.ErrorIs(err, ErrDraftConfig)
+	s.Nil(state)
+}
+
 func (s *StateSuite) TestNewWithTarget() {
 	accts := &accounts.MockAccountList{}
 	acct1 := accounts.Account{
@@ -162,6 +162,36 @@ func APIErrorCredentialsUnavailableFromAgentError(aerr AgentError) APIErrorCrede
 	}
 }
 
+// ErrorCredentialNameCollision, ErrorCredentialURLCollision
+type CredentialCollisionDetails struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type APIErrorCredentialCollision struct {
+	Code    ErrorCode                  `json:"code"`
+	Details CredentialCollisionDetails `json:"details"`
+}
+
+func (apierr *APIErrorCredentialCollision) Error() string {
+	return fmt.Sprintf("Error: %s, Name: %s, URL: %s",
+		apierr.Code, apierr.Details.Name, apierr.Details.URL)
+}
+
+func (apierr *APIErrorCredentialCollision) JSONResponse(w http.ResponseWriter) {
+	jsonResult(w, http.StatusConflict, apierr)
+}
+
+func APIErrorCredentialCollisionFromDetails(code ErrorCode, name string, url string) APIErrorCredentialCollision {
+	return APIErrorCredentialCollision{
+		Code: code,
+		Details: CredentialCollisionDetails{
+			Name: name,
+			URL:  url,
+		},
+	}
+}
+
 type APIErrorPythonExecNotFound struct {
 	Code ErrorCode `json:"code"`
 }
@@ -175,3 +205,63 @@ func APIErrorPythonExecNotFoundFromAgentError(aerr AgentError) APIErrorPythonExe
 func (apierr *APIErrorPythonExecNotFound) JSONResponse(w http.ResponseWriter) {
 	jsonResult(w, http.StatusUnprocessableEntity, apierr)
 }
+
+type APIErrorNoDeployableContent struct {
+	Code ErrorCode `json:"code"`
+}
+
+func APIErrorNoDeployableContentFromAgentError(aerr AgentError) APIErrorNoDeployableContent {
+	return APIErrorNoDeployableContent{
+		Code: ErrorNoDeployableContent,
+	}
+}
+
+func (apierr *APIErrorNoDeployableContent) JSONResponse(w http.ResponseWriter) {
+	jsonResult(w, http.StatusUnprocessableEntity, apierr)
+}
+
+type APIErrorQuartoNotFound struct {
+	Code ErrorCode `json:"code"`
+}
+
+func APIErrorQuartoNotFoundFromAgentError(aerr AgentError) APIErrorQuartoNotFound {
+	return APIErrorQuartoNotFound{
+		Code: ErrorQuartoNotFound,
+	}
+}
+
+func (apierr *APIErrorQuartoNotFound) JSONResponse(w http.ResponseWriter) {
+	jsonResult(w, http.StatusUnprocessableEntity, apierr)
+}
+
+// ErrorInvalidConfig
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+type InvalidConfigDetails struct {
+	Errors []FieldError `json:"errors"`
+}
+
+type APIErrorInvalidConfigDetails struct {
+	Code    ErrorCode            `json:"code"`
+	Details InvalidConfigDetails `json:"details"`
+}
+
+func (apierr *APIErrorInvalidConfigDetails) Error() string {
+	return fmt.Sprintf("Error: ErrorInvalidConfig, Errors: %v", apierr.Details.Errors)
+}
+
+func (apierr *APIErrorInvalidConfigDetails) JSONResponse(w http.ResponseWriter) {
+	jsonResult(w, http.StatusBadRequest, apierr)
+}
+
+func APIErrorInvalidConfigFromFieldErrors(fieldErrors []FieldError) APIErrorInvalidConfigDetails {
+	return APIErrorInvalidConfigDetails{
+		Code: ErrorInvalidConfig,
+		Details: InvalidConfigDetails{
+			Errors: fieldErrors,
+		},
+	}
+}
@@ -20,6 +20,8 @@ const (
 	ErrorInvalidConfigFiles           ErrorCode = "invalidConfigFiles"
 	ErrorCredentialServiceUnavailable ErrorCode = "credentialsServiceUnavailable"
 	ErrorCertificateVerification      ErrorCode = "errorCertificateVerification"
+	ErrorCredentialNameCollision      ErrorCode = "credentialNameCollision"
+	ErrorCredentialURLCollision       ErrorCode = "credentialURLCollision"
 	ErrorRenvPackageVersionMismatch   ErrorCode = "renvPackageVersionMismatch"
 	ErrorRenvPackageSourceMissing     ErrorCode = "renvPackageSourceMissing"
 	ErrorRenvLockPackagesReading      ErrorCode = "renvlockPackagesReadingError"
@@ -29,6 +31,12 @@ const (
 	ErrorTomlValidationError          ErrorCode = "tomlValidationError"
 	ErrorTomlUnknownError             ErrorCode = "tomlUnknownError"
 	ErrorPythonExecNotFound           ErrorCode = "pythonExecNotFound"
+	ErrorInvalidConfig                ErrorCode = "invalidConfig"
+	ErrorStrictModeWarnings           ErrorCode = "strictModeWarnings"
+	ErrorInvalidEnvVarName            ErrorCode = "invalidEnvVarName"
+	ErrorNoDeployableContent          ErrorCode = "noDeployableContent"
+	ErrorQuartoNotFound               ErrorCode = "quartoNotFound"
+	ErrorEntrypointCaseMismatch       ErrorCode = "entrypointCaseMismatch"
 )
 
 type EventableError interface {
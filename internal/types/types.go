@@ -16,6 +16,14 @@ type TaskID string
 type UserID string
 type PrincipalID string
 
+// TaskPosition records how much of a server task's output has been
+// received, so that waiting for the task can resume without
+// repeating output.
+type TaskPosition struct {
+	TaskID TaskID `toml:"task_id" json:"taskId"`
+	Offset int32  `toml:"offset" json:"offset"`
+}
+
 type NullString = Optional[string]
 type NullInt32 = Optional[int32]
 type NullInt64 = Optional[int64]
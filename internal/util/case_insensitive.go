@@ -0,0 +1,172 @@
+package util
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// CaseInsensitivePathsEnvVar names an environment variable that
+// overrides whether file paths are compared without regard to case.
+// It is a boolean such as "true" or "false".
+const CaseInsensitivePathsEnvVar = "POSIT_PUBLISHER_CASE_INSENSITIVE_PATHS"
+
+// CaseInsensitivePaths reports whether file paths should be compared
+// without regard to case, so that matching is consistent with a
+// case-insensitive filesystem. Unless configured with
+// CaseInsensitivePathsEnvVar, this is true on macOS and Windows,
+// whose default filesystems are case-insensitive.
+// Tests can replace it to simulate either kind of filesystem.
+var CaseInsensitivePaths = func() bool {
+	value := os.Getenv(CaseInsensitivePathsEnvVar)
+	if value != "" {
+		insensitive, err := strconv.ParseBool(value)
+		if err == nil {
+			return insensitive
+		}
+	}
+	return runtime.GOOS == "darwin" || runtime.GOOS == "windows"
+}
+
+// glob returns the paths matching the pattern, like afero.Glob.
+// If paths are case-insensitive, so is the matching.
+func glob(fs afero.Fs, pattern string) ([]string, error) {
+	if !CaseInsensitivePaths() {
+		return afero.Glob(fs, pattern)
+	}
+	return globFold(fs, pattern)
+}
+
+// globFold is afero.Glob, but it ignores case when
+// matching the wildcards in the pattern.
+func globFold(fs afero.Fs, pattern string) ([]string, error) {
+	// Check the pattern is well formed.
+	_, err := filepath.Match(pattern, "")
+	if err != nil {
+		return nil, err
+	}
+	if !hasGlobMeta(pattern) {
+		_, err = fs.Stat(pattern)
+		if err != nil {
+			return nil, nil
+		}
+		return []string{pattern}, nil
+	}
+	dir, file := filepath.Split(pattern)
+	dir = cleanGlobPath(dir)
+	if !hasGlobMeta(dir) {
+		return globDirFold(fs, dir, file, nil), nil
+	}
+	dirMatches, err := globFold(fs, dir)
+	if err != nil {
+		return nil, err
+	}
+	var matches []string
+	for _, d := range dirMatches {
+		matches = globDirFold(fs, d, file, matches)
+	}
+	return matches, nil
+}
+
+// globDirFold appends the entries in dir that match
+// the pattern, ignoring case, to matches.
+func globDirFold(fs afero.Fs, dir string, pattern string, matches []string) []string {
+	entries, err := afero.ReadDir(fs, dir)
+	if err != nil {
+		// Like filepath.Glob, ignore I/O errors.
+		return matches
+	}
+	lowerPattern := strings.ToLower(pattern)
+	for _, entry := range entries {
+		matched, _ := filepath.Match(lowerPattern, strings.ToLower(entry.Name()))
+		if matched {
+			matches = append(matches, filepath.Join(dir, entry.Name()))
+		}
+	}
+	return matches
+}
+
+func hasGlobMeta(path string) bool {
+	magicChars := `*?[`
+	if runtime.GOOS != "windows" {
+		magicChars = `*?[\`
+	}
+	return strings.ContainsAny(path, magicChars)
+}
+
+// cleanGlobPath prepares the directory part of a
+// pattern for matching, as filepath.Glob does.
+func cleanGlobPath(path string) string {
+	switch {
+	case path == "":
+		return "."
+	case len(path) == len(filepath.VolumeName(path))+1:
+		// The root directory, such as / or C:\
+		return path
+	default:
+		// Remove the trailing separator
+		return path[0 : len(path)-1]
+	}
+}
+
+// FindCaseMismatch checks whether rel names a file in base only when
+// case is ignored, such as App.py when the file is app.py. If so, it
+// returns the path with the case of each name as it is on disk, and true.
+// It returns false if rel matches exactly, or doesn't match at all.
+// Directory listings are compared, since on a case-insensitive
+// filesystem the mismatched path can still be opened, but it won't
+// work once deployed to a case-sensitive server.
+func FindCaseMismatch(base AbsolutePath, rel RelativePath) (RelativePath, bool, error) {
+	names := strings.Split(filepath.Clean(rel.String()), string(filepath.Separator))
+	dir := base
+	actual := make([]string, 0, len(names))
+	mismatch := false
+	for i, name := range names {
+		if name == "." || name == ".." {
+			return RelativePath{}, false, nil
+		}
+		if i > 0 {
+			isDir, err := dir.IsDir()
+			if err != nil || !isDir {
+				return RelativePath{}, false, nil
+			}
+		}
+		entries, err := dir.ReadDirNames()
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return RelativePath{}, false, nil
+			}
+			return RelativePath{}, false, err
+		}
+		found := ""
+		for _, entry := range entries {
+			if entry == name {
+				found = entry
+				break
+			}
+			if found == "" && strings.EqualFold(entry, name) {
+				found = entry
+			}
+		}
+		if found == "" {
+			return RelativePath{}, false, nil
+		}
+		if found != name {
+			mismatch = true
+		}
+		actual = append(actual, found)
+		dir = dir.Join(found)
+	}
+	if !mismatch {
+		return RelativePath{}, false, nil
+	}
+	return NewRelativePath(filepath.Join(actual...), rel.Fs()), true, nil
+}
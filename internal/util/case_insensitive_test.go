@@ -0,0 +1,107 @@
+package util
+
+// Copyright (C) 2024 by Posit Software, PBC.
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/posit-dev/publisher/internal/util/utiltest"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/suite"
+)
+
+type CaseInsensitiveSuite struct {
+	utiltest.Suite
+	base AbsolutePath
+}
+
+func TestCaseInsensitiveSuite(t *testing.T) {
+	suite.Run(t, new(CaseInsensitiveSuite))
+}
+
+var defaultCaseInsensitivePaths = CaseInsensitivePaths
+
+func (s *CaseInsensitiveSuite) SetupTest() {
+	s.base = NewAbsolutePath(filepath.FromSlash("/project"), afero.NewMemMapFs())
+	for _, name := range []string{"Index.HTML", "app.py", "Sub/APP.PY", "other/app.R"} {
+		path := s.base.Join(filepath.FromSlash(name))
+		s.NoError(path.Dir().MkdirAll(0700))
+		s.NoError(path.WriteFile([]byte(name), 0600))
+	}
+}
+
+func (s *CaseInsensitiveSuite) TearDownTest() {
+	CaseInsensitivePaths = defaultCaseInsensitivePaths
+}
+
+func (s *CaseInsensitiveSuite) glob(pattern string) []string {
+	paths, err := s.base.Glob(filepath.FromSlash(pattern))
+	s.NoError(err)
+	names := []string{}
+	for _, path := range paths {
+		rel, err := path.Rel(s.base)
+		s.NoError(err)
+		names = append(names, rel.ToSlash())
+	}
+	return names
+}
+
+func (s *CaseInsensitiveSuite) TestGlobCaseSensitive() {
+	CaseInsensitivePaths = func() bool { return false }
+	s.Equal([]string{}, s.glob("*.html"))
+	s.Equal([]string{"app.py"}, s.glob("*.py"))
+	s.Equal([]string{"Sub/APP.PY"}, s.glob("*/APP.PY"))
+}
+
+func (s *CaseInsensitiveSuite) TestGlobCaseInsensitive() {
+	CaseInsensitivePaths = func() bool { return true }
+	s.Equal([]string{"Index.HTML"}, s.glob("*.html"))
+	s.Equal([]string{"app.py"}, s.glob("*.py"))
+	s.Equal([]string{"Sub/APP.PY", "other/app.R"}, s.glob("*/app.*"))
+	s.Equal([]string{"app.py"}, s.glob("app.py"))
+	s.Equal([]string{}, s.glob("*.txt"))
+}
+
+func (s *CaseInsensitiveSuite) TestGlobBadPattern() {
+	CaseInsensitivePaths = func() bool { return true }
+	_, err := s.base.Glob("[")
+	s.ErrorIs(err, filepath.ErrBadPattern)
+}
+
+func (s *CaseInsensitiveSuite) TestCaseInsensitivePathsEnvVar() {
+	s.T().Setenv(CaseInsensitivePathsEnvVar, "true")
+	s.True(CaseInsensitivePaths())
+	s.T().Setenv(CaseInsensitivePathsEnvVar, "false")
+	s.False(CaseInsensitivePaths())
+	s.T().Setenv(CaseInsensitivePathsEnvVar, "")
+	s.Equal(runtime.GOOS == "darwin" || runtime.GOOS == "windows", CaseInsensitivePaths())
+}
+
+func (s *CaseInsensitiveSuite) findCaseMismatch(name string) (string, bool) {
+	actual, mismatch, err := FindCaseMismatch(s.base, NewRelativePath(filepath.FromSlash(name), s.base.Fs()))
+	s.NoError(err)
+	return actual.ToSlash(), mismatch
+}
+
+func (s *CaseInsensitiveSuite) TestFindCaseMismatch() {
+	actual, mismatch := s.findCaseMismatch("App.py")
+	s.True(mismatch)
+	s.Equal("app.py", actual)
+
+	actual, mismatch = s.findCaseMismatch("sub/app.py")
+	s.True(mismatch)
+	s.Equal("Sub/APP.PY", actual)
+
+	actual, mismatch = s.findCaseMismatch("index.html")
+	s.True(mismatch)
+	s.Equal("Index.HTML", actual)
+}
+
+func (s *CaseInsensitiveSuite) TestFindCaseMismatchNone() {
+	for _, name := range []string{"app.py", "Sub/APP.PY", "missing.py", "app.py/x", "../project/app.py"} {
+		_, mismatch := s.findCaseMismatch(name)
+		s.False(mismatch, name)
+	}
+}
@@ -211,7 +211,7 @@ func UserHomeDir(fs afero.Fs) (AbsolutePath, error) {
 }
 
 func (p Path) Glob(pattern string) ([]Path, error) {
-	matches, err := afero.Glob(p.fs, p.Join(pattern).String())
+	matches, err := glob(p.fs, p.Join(pattern).String())
 	if err != nil {
 		return nil, err
 	}
@@ -223,7 +223,7 @@ func (p Path) Glob(pattern string) ([]Path, error) {
 }
 
 func (p AbsolutePath) Glob(pattern string) ([]AbsolutePath, error) {
-	matches, err := afero.Glob(p.fs, p.Join(pattern).String())
+	matches, err := glob(p.fs, p.Join(pattern).String())
 	if err != nil {
 		return nil, err
 	}
@@ -235,7 +235,7 @@ func (p AbsolutePath) Glob(pattern string) ([]AbsolutePath, error) {
 }
 
 func (p RelativePath) Glob(pattern string) ([]RelativePath, error) {
-	matches, err := afero.Glob(p.fs, p.Join(pattern).String())
+	matches, err := glob(p.fs, p.Join(pattern).String())
 	if err != nil {
 		return nil, err
 	}
@@ -3,40 +3,129 @@ package util
 // Copyright (C) 2023 by Posit Software, PBC.
 
 import (
+	"errors"
+	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/posit-dev/publisher/internal/logging"
 )
 
+// SymlinkPolicy determines how a SymlinkWalker handles symlinks.
+type SymlinkPolicy string
+
+const (
+	SymlinkFollow  SymlinkPolicy = "follow"  // Resolve symlinks and walk their targets
+	SymlinkSkip    SymlinkPolicy = "skip"    // Ignore symlinks entirely
+	SymlinkError   SymlinkPolicy = "error"   // Stop walking when a symlink is found
+	SymlinkContain SymlinkPolicy = "contain" // Follow symlinks, but stop walking if a target is outside the root
+)
+
+// DefaultSymlinkPolicy is used when no policy is specified.
+const DefaultSymlinkPolicy = SymlinkFollow
+
+var ErrSymlinkNotAllowed = errors.New("symlinks are not allowed")
+var ErrSymlinkOutsideRoot = errors.New("symlink target is outside the project directory")
+
+// ValidSymlinkPolicy returns an error if the provided policy is not
+// one of the known values. An empty policy is valid and selects
+// DefaultSymlinkPolicy.
+func ValidSymlinkPolicy(policy SymlinkPolicy) error {
+	switch policy {
+	case "", SymlinkFollow, SymlinkSkip, SymlinkError, SymlinkContain:
+		return nil
+	default:
+		return fmt.Errorf("unknown symlink policy '%s'", policy)
+	}
+}
+
 type symlinkWalker struct {
 	walker Walker
+	policy SymlinkPolicy
 	log    logging.Logger
 }
 
 // NewSymlinkWalker creates a SymlinkWalker, an instance of the
-// Walker interface that resolves symlinks before passing info
-// to the callback function.
-func NewSymlinkWalker(walker Walker, log logging.Logger) *symlinkWalker {
+// Walker interface that handles symlinks according to the
+// provided policy before passing info to the callback function.
+func NewSymlinkWalker(walker Walker, policy SymlinkPolicy, log logging.Logger) *symlinkWalker {
+	if policy == "" {
+		policy = DefaultSymlinkPolicy
+	}
 	return &symlinkWalker{
 		walker: walker,
+		policy: policy,
 		log:    log,
 	}
 }
 
 // Walk implements the Walker interface. It walks the underlying
-// file structure of the provided walker, following symlinks.
+// file structure of the provided walker, handling symlinks
+// according to the walker's policy.
 func (w *symlinkWalker) Walk(path AbsolutePath, fn AbsoluteWalkFunc) error {
-	return w.walker.Walk(path, w.visit(fn))
+	return w.walker.Walk(path, w.visit(path, fn, nil))
+}
+
+// checkContained returns an error if linkTarget (a fully resolved path)
+// is not within the root directory.
+func checkContained(root AbsolutePath, path AbsolutePath, linkTarget string) error {
+	resolvedRoot, err := filepath.EvalSymlinks(root.String())
+	if err != nil {
+		return err
+	}
+	rel, err := filepath.Rel(resolvedRoot, linkTarget)
+	if err != nil {
+		return err
+	}
+	if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("%w: %s links to %s", ErrSymlinkOutsideRoot, path, linkTarget)
+	}
+	return nil
+}
+
+// isAncestorOrSelf returns true if dir is the same as path or one of
+// its parent directories. Both must be fully resolved paths.
+func isAncestorOrSelf(dir string, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel == "." || filepath.IsLocal(rel)
 }
 
-func (w *symlinkWalker) visit(fn AbsoluteWalkFunc) AbsoluteWalkFunc {
+// isCycle returns true if following a symlink to the directory
+// linkTarget would walk a directory that is already being walked,
+// such as an ancestor of the link. linkDirs are the resolved
+// directories containing the symlink and the symlinks that
+// were followed to reach it.
+func isCycle(linkTarget string, linkDirs []string) bool {
+	for _, dir := range linkDirs {
+		if isAncestorOrSelf(linkTarget, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// visit returns a walk function that follows symlinks. linkDirs are
+// the resolved directories containing the symlinks that were followed
+// to reach the paths it is called with; they are used to detect cycles.
+func (w *symlinkWalker) visit(root AbsolutePath, fn AbsoluteWalkFunc, linkDirs []string) AbsoluteWalkFunc {
 	return func(path AbsolutePath, info fs.FileInfo, err error) error {
 		if err != nil {
 			return fn(path, nil, err)
 		}
 		if info.Mode().Type()&os.ModeSymlink != 0 {
+			switch w.policy {
+			case SymlinkSkip:
+				w.log.Info("Skipping symlink", "path", path)
+				return nil
+			case SymlinkError:
+				return fmt.Errorf("%w: %s", ErrSymlinkNotAllowed, path)
+			}
 			w.log.Info("Following symlink", "path", path)
 			linkTarget, err := filepath.EvalSymlinks(path.String())
 			if err != nil {
@@ -48,14 +137,34 @@ func (w *symlinkWalker) visit(fn AbsoluteWalkFunc) AbsoluteWalkFunc {
 				return nil
 			}
 
+			if w.policy == SymlinkContain {
+				err = checkContained(root, path, linkTarget)
+				if err != nil {
+					return err
+				}
+			}
+
 			targetPath := NewPath(linkTarget, path.Fs())
 			targetInfo, err := targetPath.Stat()
 			if err != nil {
 				w.log.Warn("Error getting info for symlink", "filepath", targetPath, "error", err.Error())
 				return nil
 			}
+			targetLinkDirs := linkDirs
+			if targetInfo.IsDir() {
+				linkDir, err := filepath.EvalSymlinks(path.Dir().String())
+				if err != nil {
+					return err
+				}
+				// Copy so that sibling links don't share the slice.
+				targetLinkDirs = append(slices.Clip(linkDirs), linkDir)
+				if isCycle(linkTarget, targetLinkDirs) {
+					w.log.Warn("Skipping symlink to a directory that contains it", "path", path, "target", linkTarget)
+					return nil
+				}
+			}
 			// Visit symlink target info but use the path to the link.
-			err = w.visit(fn)(path, targetInfo, nil)
+			err = w.visit(root, fn, targetLinkDirs)(path, targetInfo, nil)
 			if err != nil {
 				return err
 			}
@@ -70,7 +179,7 @@ func (w *symlinkWalker) visit(fn AbsoluteWalkFunc) AbsoluteWalkFunc {
 				// so that it appears as a descendant of the root dir.
 				for _, entry := range dirEntries {
 					subPath := path.Join(entry.Name())
-					err = w.Walk(subPath, w.visit(fn))
+					err = w.walker.Walk(subPath, w.visit(root, fn, targetLinkDirs))
 					if err != nil {
 						return err
 					}
@@ -5,6 +5,7 @@ package util
 import (
 	"errors"
 	"io/fs"
+	"os"
 	"path/filepath"
 	"runtime"
 	"sort"
@@ -56,7 +57,7 @@ func (s *SymlinkWalkerSuite) TestWalkNoSymlinks() {
 
 	underlyingWalker := &FSWalker{}
 	log := logging.New()
-	walker := NewSymlinkWalker(underlyingWalker, log)
+	walker := NewSymlinkWalker(underlyingWalker, SymlinkFollow, log)
 	sourcePath := s.cwd.Join("subdir")
 	fileList := []string{}
 
@@ -80,7 +81,7 @@ func (s *SymlinkWalkerSuite) TestWalkError() {
 
 	underlyingWalker := &FSWalker{}
 	log := logging.New()
-	walker := NewSymlinkWalker(underlyingWalker, log)
+	walker := NewSymlinkWalker(underlyingWalker, SymlinkFollow, log)
 	sourcePath, err := Getwd(badFS)
 	s.NoError(err)
 
@@ -98,7 +99,7 @@ func (s *SymlinkWalkerSuite) TestNewBundleFromDirectorySymlinks() {
 	log := logging.New()
 
 	underlyingWalker := &FSWalker{}
-	walker := NewSymlinkWalker(underlyingWalker, log)
+	walker := NewSymlinkWalker(underlyingWalker, SymlinkFollow, log)
 	fileList := []string{}
 
 	err := walker.Walk(sourcePath, func(path AbsolutePath, info fs.FileInfo, err error) error {
@@ -134,10 +135,185 @@ func (s *SymlinkWalkerSuite) TestNewBundleFromDirectoryMissingSymlinkTarget() {
 	log.On("Warn", "Error following symlink, ignoring file", "filepath", symlinkPathMatcher, "error", mock.Anything).Return()
 
 	underlyingWalker := &FSWalker{}
-	walker := NewSymlinkWalker(underlyingWalker, log)
+	walker := NewSymlinkWalker(underlyingWalker, SymlinkFollow, log)
 	err := walker.Walk(dirPath, func(path AbsolutePath, info fs.FileInfo, err error) error {
 		return nil
 	})
 	s.NoError(err)
 	log.AssertExpectations(s.T())
 }
+
+func (s *SymlinkWalkerSuite) TestWalkSymlinksSkip() {
+	realFS := afero.NewOsFs()
+	sourcePath := NewAbsolutePath(s.cwd.String(), realFS).Join("testdata", "symlink_test", "bundle_dir")
+	log := logging.New()
+
+	underlyingWalker := &FSWalker{}
+	walker := NewSymlinkWalker(underlyingWalker, SymlinkSkip, log)
+	fileList := []string{}
+
+	err := walker.Walk(sourcePath, func(path AbsolutePath, info fs.FileInfo, err error) error {
+		s.Nil(err)
+		fileList = append(fileList, path.Base())
+		return nil
+	})
+	s.Nil(err)
+	sort.Strings(fileList)
+	s.Equal([]string{
+		"bundle_dir",
+		"somefile",
+	}, fileList)
+}
+
+func (s *SymlinkWalkerSuite) TestWalkSymlinksError() {
+	realFS := afero.NewOsFs()
+	sourcePath := NewAbsolutePath(s.cwd.String(), realFS).Join("testdata", "symlink_test", "bundle_dir")
+	log := logging.New()
+
+	underlyingWalker := &FSWalker{}
+	walker := NewSymlinkWalker(underlyingWalker, SymlinkError, log)
+
+	err := walker.Walk(sourcePath, func(path AbsolutePath, info fs.FileInfo, err error) error {
+		return err
+	})
+	s.ErrorIs(err, ErrSymlinkNotAllowed)
+	s.ErrorContains(err, "linked_dir")
+}
+
+func (s *SymlinkWalkerSuite) TestValidSymlinkPolicy() {
+	s.NoError(ValidSymlinkPolicy(""))
+	s.NoError(ValidSymlinkPolicy(SymlinkFollow))
+	s.NoError(ValidSymlinkPolicy(SymlinkSkip))
+	s.NoError(ValidSymlinkPolicy(SymlinkError))
+	s.NoError(ValidSymlinkPolicy(SymlinkContain))
+	s.ErrorContains(ValidSymlinkPolicy("bogus"), "unknown symlink policy")
+}
+
+func (s *SymlinkWalkerSuite) TestWalkSymlinksContainEscaping() {
+	if runtime.GOOS == "windows" {
+		s.T().Skip()
+	}
+	realFS := afero.NewOsFs()
+	sourcePath := NewAbsolutePath(s.cwd.String(), realFS).Join("testdata", "symlink_test", "bundle_dir")
+	log := logging.New()
+
+	underlyingWalker := &FSWalker{}
+	walker := NewSymlinkWalker(underlyingWalker, SymlinkContain, log)
+
+	err := walker.Walk(sourcePath, func(path AbsolutePath, info fs.FileInfo, err error) error {
+		return err
+	})
+	s.ErrorIs(err, ErrSymlinkOutsideRoot)
+	s.ErrorContains(err, "linked_dir")
+}
+
+func (s *SymlinkWalkerSuite) TestWalkSymlinksContainInside() {
+	if runtime.GOOS == "windows" {
+		s.T().Skip()
+	}
+	realFS := afero.NewOsFs()
+	dir := NewAbsolutePath(s.T().TempDir(), realFS)
+	err := dir.Join("subdir").MkdirAll(0700)
+	s.NoError(err)
+	err = dir.Join("subdir", "testfile").WriteFile([]byte("hello"), 0600)
+	s.NoError(err)
+	err = os.Symlink("subdir", dir.Join("linked_dir").String())
+	s.NoError(err)
+	log := logging.New()
+
+	underlyingWalker := &FSWalker{}
+	walker := NewSymlinkWalker(underlyingWalker, SymlinkContain, log)
+	fileList := []string{}
+
+	err = walker.Walk(dir, func(path AbsolutePath, info fs.FileInfo, err error) error {
+		s.Nil(err)
+		rel, err := path.Rel(dir)
+		s.NoError(err)
+		fileList = append(fileList, rel.ToSlash())
+		return nil
+	})
+	s.NoError(err)
+	sort.Strings(fileList)
+	s.Equal([]string{
+		".",
+		"linked_dir",
+		"linked_dir/testfile",
+		"subdir",
+		"subdir/testfile",
+	}, fileList)
+}
+
+// walkRelPaths walks dir with a SymlinkFollow walker and returns
+// the paths it visits, relative to dir.
+func (s *SymlinkWalkerSuite) walkRelPaths(dir AbsolutePath, log logging.Logger) []string {
+	walker := NewSymlinkWalker(&FSWalker{}, SymlinkFollow, log)
+	fileList := []string{}
+	err := walker.Walk(dir, func(path AbsolutePath, info fs.FileInfo, err error) error {
+		s.Nil(err)
+		rel, err := path.Rel(dir)
+		s.NoError(err)
+		fileList = append(fileList, rel.ToSlash())
+		return nil
+	})
+	s.NoError(err)
+	sort.Strings(fileList)
+	return fileList
+}
+
+func (s *SymlinkWalkerSuite) TestWalkSymlinkCycleToParent() {
+	if runtime.GOOS == "windows" {
+		s.T().Skip()
+	}
+	realFS := afero.NewOsFs()
+	dir := NewAbsolutePath(s.T().TempDir(), realFS).Join("project")
+	err := dir.MkdirAll(0700)
+	s.NoError(err)
+	err = dir.Join("testfile").WriteFile([]byte("hello"), 0600)
+	s.NoError(err)
+	err = os.Symlink("..", dir.Join("a").String())
+	s.NoError(err)
+	err = os.Symlink(".", dir.Join("self").String())
+	s.NoError(err)
+
+	log := loggingtest.NewMockLogger()
+	log.On("Info", "Following symlink", "path", mock.Anything).Return()
+	log.On("Warn", "Skipping symlink to a directory that contains it", "path", dir.Join("a"), "target", mock.Anything).Return().Once()
+	log.On("Warn", "Skipping symlink to a directory that contains it", "path", dir.Join("self"), "target", mock.Anything).Return().Once()
+
+	s.Equal([]string{
+		".",
+		"testfile",
+	}, s.walkRelPaths(dir, log))
+	log.AssertExpectations(s.T())
+}
+
+func (s *SymlinkWalkerSuite) TestWalkSymlinkCycleBetweenDirs() {
+	if runtime.GOOS == "windows" {
+		s.T().Skip()
+	}
+	realFS := afero.NewOsFs()
+	dir := NewAbsolutePath(s.T().TempDir(), realFS)
+	for _, name := range []string{"a", "b"} {
+		err := dir.Join(name).MkdirAll(0700)
+		s.NoError(err)
+		err = dir.Join(name, "file").WriteFile([]byte(name), 0600)
+		s.NoError(err)
+	}
+	err := os.Symlink(filepath.Join("..", "b"), dir.Join("a", "to_b").String())
+	s.NoError(err)
+	err = os.Symlink(filepath.Join("..", "a"), dir.Join("b", "to_a").String())
+	s.NoError(err)
+
+	// Each link is followed once from the other directory.
+	s.Equal([]string{
+		".",
+		"a",
+		"a/file",
+		"a/to_b",
+		"a/to_b/file",
+		"b",
+		"b/file",
+		"b/to_a",
+		"b/to_a/file",
+	}, s.walkRelPaths(dir, logging.New()))
+}
@@ -17,7 +17,13 @@ func NormalizeServerURL(serverURL string) (string, error) {
 		purell.FlagRemoveTrailingSlash |
 		purell.FlagRemoveDotSegments |
 		purell.FlagRemoveDuplicateSlashes)
-	return purell.NormalizeURLString(serverURL, flags)
+	normalized, err := purell.NormalizeURLString(serverURL, flags)
+	if err != nil {
+		return "", err
+	}
+	// Server URLs copied from API clients, such as rsconnect-python,
+	// may include the API path, which isn't part of the server URL.
+	return strings.TrimSuffix(normalized, "/__api__"), nil
 }
 
 func GetDashboardURL(accountURL string, contentID types.ContentID) string {
@@ -30,6 +30,9 @@ func (s *UrlsSuite) TestNormalizeServerURL() {
 	s.normalizedUrlEquals("https://connect.example.com", "https://CONNECT.example.com")
 	s.normalizedUrlEquals("https://connect.example.com/rsc", "https://connect.example.com:443/rsc")
 	s.normalizedUrlEquals("https://connect.example.com/rsc", "https://connect.example.com///rsc/")
+
+	s.normalizedUrlEquals("https://connect.example.com", "https://connect.example.com/__api__")
+	s.normalizedUrlEquals("https://connect.example.com/rsc", "https://connect.example.com/rsc/__api__/")
 }
 
 func (u *UrlsSuite) TestGetListOfPossibleURLs() {